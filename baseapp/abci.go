@@ -2,10 +2,12 @@ package baseapp
 //BC MOD
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -19,6 +21,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	snapshottypes "github.com/cosmos/cosmos-sdk/snapshots/types"
 	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
 	"github.com/cosmos/cosmos-sdk/telemetry"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -192,9 +195,12 @@ func (app *BaseApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBeg
 			WithHeaderHash(req.Hash)
 	}
 
+	app.loadParamChangeSubscribers(app.deliverState.ctx)
+
 	if app.beginBlocker != nil {
 		res = app.beginBlocker(app.deliverState.ctx, req)
 		res.Events = sdk.MarkEventsToIndex(res.Events, app.indexEvents)
+		app.notifyParamChanges(app.deliverState.ctx, res.Events)
 	}
 	// set the signed validators for addition to context in deliverTx
 	app.voteInfos = req.LastCommitInfo.GetVotes()
@@ -218,6 +224,7 @@ func (app *BaseApp) EndBlock(req abci.RequestEndBlock) (res abci.ResponseEndBloc
 	if app.endBlocker != nil {
 		res = app.endBlocker(app.deliverState.ctx, req)
 		res.Events = sdk.MarkEventsToIndex(res.Events, app.indexEvents)
+		app.notifyParamChanges(app.deliverState.ctx, res.Events)
 	}
 
 	if cp := app.GetConsensusParams(app.deliverState.ctx); cp != nil {
@@ -343,6 +350,10 @@ func (app *BaseApp) ProcessProposal(req abci.RequestProcessProposal) (resp abci.
 		}
 	}()
 
+	if app.txSigExtractor != nil {
+		app.preVerifySignatures(app.processProposalState.ctx, req.Txs)
+	}
+
 	resp = app.processProposal(app.processProposalState.ctx, req)
 	return resp
 }
@@ -412,6 +423,8 @@ func (app *BaseApp) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliv
 		return sdkerrors.ResponseDeliverTxWithEvents(err, gInfo.GasWanted, gInfo.GasUsed, sdk.MarkEventsToIndex(anteEvents, app.indexEvents), app.trace)
 	}
 
+	app.notifyParamChanges(app.deliverState.ctx, result.Events)
+
 	return abci.ResponseDeliverTx{
 		GasWanted: int64(gInfo.GasWanted), // TODO: Should type accept unsigned ints?
 		GasUsed:   int64(gInfo.GasUsed),   // TODO: Should type accept unsigned ints?
@@ -518,7 +531,7 @@ func (app *BaseApp) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
 	// ref: https://github.com/cosmos/cosmos-sdk/pull/8039
 	defer func() {
 		if r := recover(); r != nil {
-			res = sdkerrors.QueryResult(sdkerrors.Wrapf(sdkerrors.ErrPanic, "%v", r), app.trace)
+			res = sdkerrors.QueryResult(app.queryPanicError(r), app.trace)
 		}
 	}()
 
@@ -701,6 +714,24 @@ func (app *BaseApp) handleQueryGRPC(handler GRPCQueryHandler, req abci.RequestQu
 	return res
 }
 
+// queryOutOfGasError returns an ErrOutOfGas error if the recovered panic of a
+// query is an out of gas panic, and nil otherwise.
+func (app *BaseApp) queryOutOfGasError(r interface{}) error {
+	oog, ok := r.(storetypes.ErrorOutOfGas)
+	if !ok {
+		return nil
+	}
+	return sdkerrors.Wrapf(sdkerrors.ErrOutOfGas, "query out of gas in location: %v; query gas limit: %d", oog.Descriptor, app.queryGasLimit)
+}
+
+// queryPanicError converts a panic recovered from a query into an error.
+func (app *BaseApp) queryPanicError(r interface{}) error {
+	if err := app.queryOutOfGasError(r); err != nil {
+		return err
+	}
+	return sdkerrors.Wrapf(sdkerrors.ErrPanic, "%v", r)
+}
+
 func gRPCErrorToSDKError(err error) error {
 	status, ok := grpcstatus.FromError(err)
 	if !ok {
@@ -784,6 +815,7 @@ func (app *BaseApp) CreateQueryContext(height int64, prove bool) (sdk.Context, e
 	ctx := sdk.NewContext(cacheMS, app.checkState.ctx.BlockHeader(), true, app.logger).
 		WithMinGasPrices(app.minGasPrices).
 		WithBlockHeight(height)
+	ctx = QueryGasLimit(app.queryGasLimit)(ctx)
 
 	if height != lastBlockHeight {
 		rms, ok := app.cms.(*rootmulti.Store)
@@ -905,6 +937,54 @@ func handleQueryApp(app *BaseApp, path []string, req abci.RequestQuery) abci.Res
 				Value:     []byte(app.version),
 			}
 
+		case "snapshots":
+			// exposes the snapshots offered to state-syncing peers, so that
+			// operators can pick state-sync sources over RPC
+			snapshots := app.ListSnapshots(abci.RequestListSnapshots{})
+			bz, err := snapshots.Marshal()
+			if err != nil {
+				return sdkerrors.QueryResult(sdkerrors.Wrap(err, "failed to encode snapshots"), app.trace)
+			}
+
+			return abci.ResponseQuery{
+				Codespace: sdkerrors.RootCodespace,
+				Height:    req.Height,
+				Value:     bz,
+			}
+
+		case "capabilities":
+			// advertises the signature algorithms and snapshot settings of the
+			// node, so that clients can check their compatibility over RPC
+			bz, err := json.Marshal(app.Capabilities())
+			if err != nil {
+				return sdkerrors.QueryResult(sdkerrors.Wrap(err, "failed to encode capabilities"), app.trace)
+			}
+
+			return abci.ResponseQuery{
+				Codespace: sdkerrors.RootCodespace,
+				Height:    req.Height,
+				Value:     bz,
+			}
+
+		case "snapshot_chunk":
+			// "/app/snapshot_chunk/<height>/<format>/<chunk>" serves the chunks of
+			// the offered snapshots, to bootstrap nodes over RPC
+			chunkReq, err := parseSnapshotChunkQuery(path[2:])
+			if err != nil {
+				return sdkerrors.QueryResult(err, app.trace)
+			}
+
+			chunk := app.LoadSnapshotChunk(chunkReq).Chunk
+			if chunk == nil {
+				return sdkerrors.QueryResult(sdkerrors.ErrNotFound.Wrapf("snapshot chunk %d of height %d format %d", chunkReq.Chunk, chunkReq.Height, chunkReq.Format), app.trace)
+			}
+
+			return abci.ResponseQuery{
+				Codespace: sdkerrors.RootCodespace,
+				Height:    req.Height,
+				Value:     chunk,
+			}
+
 		default:
 			return sdkerrors.QueryResult(sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unknown query: %s", path), app.trace)
 		}
@@ -913,10 +993,55 @@ func handleQueryApp(app *BaseApp, path []string, req abci.RequestQuery) abci.Res
 	return sdkerrors.QueryResult(
 		sdkerrors.Wrap(
 			sdkerrors.ErrUnknownRequest,
-			"expected second parameter to be either 'simulate' or 'version', neither was present",
+			"expected second parameter to be one of 'simulate', 'version', 'snapshots' or 'capabilities', none was present",
 		), app.trace)
 }
 
+// AppCapabilities is the response of the "/app/capabilities" query.
+type AppCapabilities struct {
+	// SignatureAlgorithms are the type URLs of the public keys whose
+	// signatures the app verifies, e.g. "/cosmos.crypto.secp256k1.PubKey", as
+	// set with SetSignatureAlgorithms.
+	SignatureAlgorithms []string `json:"signature_algorithms"`
+	// SnapshotInterval and SnapshotKeepRecent are the state-sync snapshot
+	// settings of the node, zero if it takes no snapshots.
+	SnapshotInterval   uint64 `json:"snapshot_interval"`
+	SnapshotKeepRecent uint32 `json:"snapshot_keep_recent"`
+}
+
+// Capabilities returns the capabilities the app advertises over RPC.
+func (app *BaseApp) Capabilities() AppCapabilities {
+	caps := AppCapabilities{SignatureAlgorithms: append([]string{}, app.signatureAlgorithms...)}
+	if app.snapshotManager != nil {
+		caps.SnapshotInterval = app.snapshotManager.GetInterval()
+		caps.SnapshotKeepRecent = app.snapshotManager.GetKeepRecent()
+	}
+	return caps
+}
+
+// parseSnapshotChunkQuery parses the <height>/<format>/<chunk> arguments of a
+// snapshot chunk query.
+func parseSnapshotChunkQuery(args []string) (abci.RequestLoadSnapshotChunk, error) {
+	if len(args) != 3 {
+		return abci.RequestLoadSnapshotChunk{}, sdkerrors.ErrInvalidRequest.Wrap("expected snapshot_chunk/<height>/<format>/<chunk>")
+	}
+
+	height, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return abci.RequestLoadSnapshotChunk{}, sdkerrors.ErrInvalidRequest.Wrapf("invalid snapshot height %q", args[0])
+	}
+	format, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil {
+		return abci.RequestLoadSnapshotChunk{}, sdkerrors.ErrInvalidRequest.Wrapf("invalid snapshot format %q", args[1])
+	}
+	chunk, err := strconv.ParseUint(args[2], 10, 32)
+	if err != nil {
+		return abci.RequestLoadSnapshotChunk{}, sdkerrors.ErrInvalidRequest.Wrapf("invalid snapshot chunk %q", args[2])
+	}
+
+	return abci.RequestLoadSnapshotChunk{Height: height, Format: uint32(format), Chunk: uint32(chunk)}, nil
+}
+
 func handleQueryStore(app *BaseApp, path []string, req abci.RequestQuery) abci.ResponseQuery {
 	// "/store" prefix for store queries
 	queryable, ok := app.cms.(sdk.Queryable)
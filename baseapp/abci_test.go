@@ -2,6 +2,7 @@ package baseapp_test
 //BC MOD
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -269,6 +270,60 @@ func TestABCI_ListSnapshots(t *testing.T) {
 	}}, resp)
 }
 
+func TestABCI_QuerySnapshots(t *testing.T) {
+	ssCfg := SnapshotsConfig{
+		blocks:             5,
+		blockTxs:           4,
+		snapshotInterval:   2,
+		snapshotKeepRecent: 2,
+		pruningOpts:        pruningtypes.NewPruningOptions(pruningtypes.PruningNothing),
+	}
+	suite := NewBaseAppSuiteWithSnapshots(t, ssCfg)
+
+	res := suite.baseApp.Query(abci.RequestQuery{Path: "/app/snapshots"})
+	require.True(t, res.IsOK(), res.Log)
+
+	var list abci.ResponseListSnapshots
+	require.NoError(t, list.Unmarshal(res.Value))
+	require.Equal(t, suite.baseApp.ListSnapshots(abci.RequestListSnapshots{}), list)
+
+	res = suite.baseApp.Query(abci.RequestQuery{Path: fmt.Sprintf("/app/snapshot_chunk/4/%d/1", snapshottypes.CurrentFormat)})
+	require.True(t, res.IsOK(), res.Log)
+	chunk := suite.baseApp.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{Height: 4, Format: snapshottypes.CurrentFormat, Chunk: 1}).Chunk
+	require.Equal(t, chunk, res.Value)
+
+	for _, path := range []string{
+		fmt.Sprintf("/app/snapshot_chunk/4/%d/9", snapshottypes.CurrentFormat),
+		"/app/snapshot_chunk/4/1",
+		"/app/snapshot_chunk/x/1/0",
+	} {
+		res = suite.baseApp.Query(abci.RequestQuery{Path: path})
+		require.False(t, res.IsOK(), path)
+	}
+}
+
+func TestABCI_QueryCapabilities(t *testing.T) {
+	ssCfg := SnapshotsConfig{
+		blocks:             2,
+		blockTxs:           2,
+		snapshotInterval:   2,
+		snapshotKeepRecent: 3,
+		pruningOpts:        pruningtypes.NewPruningOptions(pruningtypes.PruningNothing),
+	}
+	algos := []string{"/cosmos.crypto.secp256k1.PubKey", "/cosmos.crypto.multisig.LegacyAminoPubKey"}
+	suite := NewBaseAppSuiteWithSnapshots(t, ssCfg, baseapp.SetSignatureAlgorithms(algos...))
+
+	res := suite.baseApp.Query(abci.RequestQuery{Path: "/app/capabilities"})
+	require.True(t, res.IsOK(), res.Log)
+
+	var caps baseapp.AppCapabilities
+	require.NoError(t, json.Unmarshal(res.Value, &caps))
+	require.Equal(t, suite.baseApp.Capabilities(), caps)
+	require.Equal(t, uint64(2), caps.SnapshotInterval)
+	require.Equal(t, uint32(3), caps.SnapshotKeepRecent)
+	require.Equal(t, []string{"/cosmos.crypto.multisig.LegacyAminoPubKey", "/cosmos.crypto.secp256k1.PubKey"}, caps.SignatureAlgorithms)
+}
+
 func TestABCI_SnapshotWithPruning(t *testing.T) {
 	testCases := map[string]struct {
 		ssCfg             SnapshotsConfig
@@ -684,6 +739,40 @@ func TestABCI_DeliverTx(t *testing.T) {
 	}
 }
 
+func TestABCI_DeliverTx_FallbackTxDecoder(t *testing.T) {
+	anteKey := []byte("ante-key")
+	anteOpt := func(bapp *baseapp.BaseApp) { bapp.SetAnteHandler(anteHandlerTxTest(t, capKey1, anteKey)) }
+	suite := NewBaseAppSuite(t, anteOpt)
+	// the txs are decoded from protobuf, or else from protobuf JSON
+	suite.baseApp.SetTxDecoder(baseapp.ChainTxDecoders(suite.txConfig.TxDecoder(), suite.txConfig.TxJSONDecoder()))
+
+	suite.baseApp.InitChain(abci.RequestInitChain{
+		ConsensusParams: &tmproto.ConsensusParams{},
+	})
+
+	deliverKey := []byte("deliver-key")
+	baseapptestutil.RegisterCounterServer(suite.baseApp.MsgServiceRouter(), CounterServerImpl{t, capKey1, deliverKey})
+
+	suite.baseApp.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: 1}})
+	for i, encoder := range []sdk.TxEncoder{suite.txConfig.TxEncoder(), suite.txConfig.TxJSONEncoder()} {
+		counter := int64(i)
+		txBytes, err := encoder(newTxCounter(t, suite.txConfig, counter, counter))
+		require.NoError(t, err)
+
+		res := suite.baseApp.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+		require.True(t, res.IsOK(), fmt.Sprintf("%v", res))
+		require.Equal(t, sdk.MarkEventsToIndex(counterEvent(sdk.EventTypeMessage, counter).ToABCIEvents(), map[string]struct{}{})[0], res.GetEvents()[2])
+	}
+
+	// the error of the first decoder is returned when none decodes the tx
+	res := suite.baseApp.DeliverTx(abci.RequestDeliverTx{Tx: []byte("invalid")})
+	require.Equal(t, sdkerrors.ErrTxDecode.ABCICode(), res.Code)
+	require.Contains(t, res.Log, "wire type")
+
+	suite.baseApp.EndBlock(abci.RequestEndBlock{})
+	suite.baseApp.Commit()
+}
+
 func TestABCI_DeliverTx_MultiMsg(t *testing.T) {
 	anteKey := []byte("ante-key")
 	anteOpt := func(bapp *baseapp.BaseApp) { bapp.SetAnteHandler(anteHandlerTxTest(t, capKey1, anteKey)) }
@@ -814,6 +903,58 @@ func TestABCI_Query_SimulateTx(t *testing.T) {
 	}
 }
 
+func TestABCI_Simulate_StoreAccess(t *testing.T) {
+	anteOpt := func(bapp *baseapp.BaseApp) {
+		bapp.SetAnteHandler(func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, error) {
+			store := ctx.KVStore(capKey1)
+			store.Set([]byte("a"), []byte("1"))
+			store.Set([]byte("b"), []byte("22"))
+
+			it := store.Iterator(nil, nil)
+			defer it.Close()
+			for ; it.Valid(); it.Next() {
+				_ = it.Value()
+			}
+			return ctx, nil
+		})
+	}
+	suite := NewBaseAppSuite(t, anteOpt, baseapp.SetStoreAccessRecording(true))
+
+	suite.baseApp.InitChain(abci.RequestInitChain{
+		ConsensusParams: &tmproto.ConsensusParams{},
+	})
+
+	baseapptestutil.RegisterCounterServer(suite.baseApp.MsgServiceRouter(), CounterServerImplGasMeterOnly{})
+
+	suite.baseApp.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: 1}})
+
+	txBytes, err := suite.txConfig.TxEncoder()(newTxCounter(t, suite.txConfig, 0, 0))
+	require.NoError(t, err)
+
+	// the simulation result ends with the store access of the tx, the entries
+	// iterated over being counted as reads
+	_, result, err := suite.baseApp.Simulate(txBytes)
+	require.NoError(t, err)
+	event := result.Events[len(result.Events)-1]
+	require.Equal(t, abci.Event{
+		Type: baseapp.EventTypeStoreAccess,
+		Attributes: []abci.EventAttribute{
+			{Key: baseapp.AttributeKeyReads, Value: "2"},
+			{Key: baseapp.AttributeKeyReadBytes, Value: "5"},
+			{Key: baseapp.AttributeKeyWrites, Value: "2"},
+			{Key: baseapp.AttributeKeyWriteBytes, Value: "5"},
+			{Key: baseapp.AttributeKeyIterators, Value: "1"},
+		},
+	}, event)
+
+	// delivered txs record their store access without returning it
+	res := suite.baseApp.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+	require.True(t, res.IsOK(), res.Log)
+	for _, event := range res.Events {
+		require.NotEqual(t, baseapp.EventTypeStoreAccess, event.Type)
+	}
+}
+
 func TestABCI_InvalidTransaction(t *testing.T) {
 	anteOpt := func(bapp *baseapp.BaseApp) {
 		bapp.SetAnteHandler(func(ctx sdk.Context, tx sdk.Tx, simulate bool) (newCtx sdk.Context, err error) {
@@ -15,9 +15,11 @@ import (
 	"golang.org/x/exp/maps"
 
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/aggsig"
 	"github.com/cosmos/cosmos-sdk/snapshots"
 	"github.com/cosmos/cosmos-sdk/store"
 	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/telemetry"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/mempool"
@@ -63,6 +65,7 @@ type BaseApp struct { //nolint: maligned
 	mempool         mempool.Mempool            // application side mempool
 	anteHandler     sdk.AnteHandler            // ante handler for fee and auth
 	postHandler     sdk.PostHandler            // post handler, optional, e.g. for tips
+	rateLimiter     RateLimiter                // rate limiter of new txs, optional, checked around the ante handler
 	initChainer     sdk.InitChainer            // initialize state with validators and state blob
 	beginBlocker    sdk.BeginBlocker           // logic to run before any txs
 	processProposal sdk.ProcessProposalHandler // the handler which runs on ABCI ProcessProposal
@@ -72,6 +75,20 @@ type BaseApp struct { //nolint: maligned
 	idPeerFilter    sdk.PeerFilter             // filter peers by node ID
 	fauxMerkleMode  bool                       // if true, IAVL MountStores uses MountStoresDB for simulation speed.
 
+	// vote extension handlers, see ExtendVote and VerifyVoteExtension
+	extendVote    sdk.ExtendVoteHandler
+	verifyVoteExt sdk.VerifyVoteExtensionHandler
+
+	// signatures of the proposed block verified ahead of its execution, see
+	// SetTxSignatureExtractor
+	txSigExtractor   TxSignatureExtractor
+	sigCache         *aggsig.Cache
+	sigVerifyWorkers int
+
+	// type URLs of the public keys whose signatures the ante handler verifies,
+	// advertised by Capabilities
+	signatureAlgorithms []string
+
 	// manages snapshots, i.e. dumps of app state at certain intervals
 	snapshotManager *snapshots.Manager
 
@@ -98,6 +115,14 @@ type BaseApp struct { //nolint: maligned
 	// transaction. This is mainly used for DoS and spam prevention.
 	minGasPrices sdk.DecCoins
 
+	// queryGasLimit is the gas limit of the contexts queries are executed
+	// with, independent of the block gas limit. Zero means no limit.
+	queryGasLimit uint64
+
+	// recordStoreAccess enables the recording of the store access of delivered
+	// and simulated txs, see SetStoreAccessRecording.
+	recordStoreAccess bool
+
 	// initialHeight is the initial height at which we start the baseapp
 	initialHeight int64
 
@@ -143,6 +168,16 @@ type BaseApp struct { //nolint: maligned
 	// and exposing the requests and responses to external consumers
 	abciListeners []ABCIListener
 
+	// paramChangeSubscribers are notified of the modules whose params changed,
+	// see SubscribeParamChanges. They are loaded on the first BeginBlock after
+	// the node starts.
+	paramChangeSubscribers      []ParamChangeSubscriber
+	paramChangeSubscribersReady bool
+
+	// simFixtureRecorder records the transactions run with SimCheck, Simulate
+	// and SimDeliver, in tests.
+	simFixtureRecorder *SimFixtureRecorder
+
 	chainID string
 }
 
@@ -184,6 +219,14 @@ func NewBaseApp(
 		app.SetProcessProposal(abciProposalHandler.ProcessProposalHandler())
 	}
 
+	if app.extendVote == nil {
+		app.SetExtendVoteHandler(NoOpExtendVote())
+	}
+
+	if app.verifyVoteExt == nil {
+		app.SetVerifyVoteExtensionHandler(NoOpVerifyVoteExtensionHandler())
+	}
+
 	if app.interBlockCache != nil {
 		app.cms.SetInterBlockCache(app.interBlockCache)
 	}
@@ -588,6 +631,10 @@ func (app *BaseApp) getContextForTx(mode runTxMode, txBytes []byte) sdk.Context
 		ctx = ctx.WithIsReCheckTx(true)
 	}
 
+	if app.sigCache != nil && (mode == runTxProcessProposal || mode == runTxModeDeliver) {
+		ctx = ctx.WithValue(aggsig.ContextKey, app.sigCache)
+	}
+
 	if mode == runTxModeSimulate {
 		ctx, _ = ctx.CacheContext()
 	}
@@ -611,6 +658,10 @@ func (app *BaseApp) cacheTxContext(ctx sdk.Context, txBytes []byte) (sdk.Context
 		).(sdk.CacheMultiStore)
 	}
 
+	if access := storeAccessFromContext(ctx); access != nil {
+		return ctx.WithMultiStore(newRecordingMultiStore(msCache, access)), msCache
+	}
+
 	return ctx.WithMultiStore(msCache), msCache
 }
 
@@ -622,14 +673,27 @@ func (app *BaseApp) cacheTxContext(ctx sdk.Context, txBytes []byte) (sdk.Context
 // returned if the tx does not run out of gas and if all the messages are valid
 // and execute successfully. An error is returned otherwise.
 func (app *BaseApp) runTx(mode runTxMode, txBytes []byte) (gInfo sdk.GasInfo, result *sdk.Result, anteEvents []abci.Event, priority int64, err error) {
+	return app.runTxWithContext(app.getContextForTx(mode, txBytes), mode, txBytes)
+}
+
+// runTxWithContext is runTx with the tx context provided by the caller, which
+// must have been obtained from getContextForTx for the same mode and txBytes.
+func (app *BaseApp) runTxWithContext(ctx sdk.Context, mode runTxMode, txBytes []byte) (gInfo sdk.GasInfo, result *sdk.Result, anteEvents []abci.Event, priority int64, err error) {
 	// NOTE: GasWanted should be returned by the AnteHandler. GasUsed is
 	// determined by the GasMeter. We need access to the context to get the gas
 	// meter, so we initialize upfront.
 	var gasWanted uint64
 
-	ctx := app.getContextForTx(mode, txBytes)
 	ms := ctx.MultiStore()
 
+	// Record the store access of the tx, exposed to the post handler and
+	// returned with the simulation result.
+	var access *StoreAccess
+	if app.recordStoreAccess && (mode == runTxModeDeliver || mode == runTxModeSimulate) {
+		access = &StoreAccess{}
+		ctx = ctx.WithValue(storeAccessContextKey{}, access)
+	}
+
 	// only run the tx if there is block gas remaining
 	if mode == runTxModeDeliver && ctx.BlockGasMeter().IsOutOfGas() {
 		return gInfo, nil, nil, 0, sdkerrors.Wrap(sdkerrors.ErrOutOfGas, "no block gas left to run tx")
@@ -678,6 +742,13 @@ func (app *BaseApp) runTx(mode runTxMode, txBytes []byte) (gInfo sdk.GasInfo, re
 		return sdk.GasInfo{}, nil, nil, 0, err
 	}
 
+	if mode == runTxModeCheck && app.rateLimiter != nil {
+		if err := app.rateLimiter.Allow(ctx, tx); err != nil {
+			telemetry.IncrCounter(1, "tx", "rate_limited")
+			return sdk.GasInfo{}, nil, nil, 0, err
+		}
+	}
+
 	if app.anteHandler != nil {
 		var (
 			anteCtx sdk.Context
@@ -714,6 +785,15 @@ func (app *BaseApp) runTx(mode runTxMode, txBytes []byte) (gInfo sdk.GasInfo, re
 			return gInfo, nil, nil, 0, err
 		}
 
+		// the allowances are only charged once the signatures and fees are
+		// verified, so that forged txs can't use them up
+		if mode == runTxModeCheck && app.rateLimiter != nil {
+			if err := app.rateLimiter.AllowVerified(ctx, tx); err != nil {
+				telemetry.IncrCounter(1, "tx", "rate_limited")
+				return gInfo, nil, nil, 0, err
+			}
+		}
+
 		priority = ctx.Priority()
 		msCache.Write()
 		anteEvents = events.ToABCIEvents()
@@ -759,6 +839,10 @@ func (app *BaseApp) runTx(mode runTxMode, txBytes []byte) (gInfo sdk.GasInfo, re
 			result.Events = append(result.Events, newCtx.EventManager().ABCIEvents()...)
 		}
 
+		if mode == runTxModeSimulate && access != nil {
+			result.Events = append(result.Events, access.abciEvent())
+		}
+
 		if mode == runTxModeDeliver {
 			// When block gas exceeds, it'll panic and won't commit the cached store.
 			consumeBlockGas()
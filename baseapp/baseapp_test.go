@@ -2,6 +2,7 @@ package baseapp_test
 //BC MOD
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"testing"
 	"time"
@@ -577,6 +578,25 @@ func TestABCI_CreateQueryContext(t *testing.T) {
 	}
 }
 
+func TestQueryGasLimit(t *testing.T) {
+	app := baseapp.NewBaseApp(t.Name(), defaultLogger(), dbm.NewMemDB(), nil, baseapp.SetQueryGasLimit(1000))
+
+	app.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: 1}})
+	app.Commit()
+
+	ctx, err := app.CreateQueryContext(1, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), ctx.GasMeter().Limit())
+	require.PanicsWithValue(t, storetypes.ErrorOutOfGas{Descriptor: "query"}, func() {
+		ctx.GasMeter().ConsumeGas(1001, "query")
+	})
+
+	ctx = app.NewUncachedContext(false, tmproto.Header{}, baseapp.QueryGasLimit(500))
+	require.Equal(t, uint64(500), ctx.GasMeter().Limit())
+	ctx = app.NewUncachedContext(false, tmproto.Header{}, baseapp.QueryGasLimit(0))
+	require.Equal(t, storetypes.Gas(math.MaxUint64), ctx.GasMeter().Limit())
+}
+
 func TestSetMinGasPrices(t *testing.T) {
 	minGasPrices := sdk.DecCoins{sdk.NewInt64DecCoin("stake", 5000)}
 	suite := NewBaseAppSuite(t, baseapp.SetMinGasPrices(minGasPrices.String()))
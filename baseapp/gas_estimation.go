@@ -0,0 +1,148 @@
+package baseapp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// GasAdjustmentPolicy configures how EstimateGas turns the gas used by several
+// simulations into a recommended gas limit.
+type GasAdjustmentPolicy struct {
+	// Runs is the number of simulations. Run i executes the tx in the context of
+	// the block i blocks after the current check state block.
+	Runs int
+	// BlockTimeStep is the block time offset between two runs.
+	BlockTimeStep time.Duration
+	// Confidence is the number of standard deviations of the gas used added as
+	// margin to the estimate.
+	Confidence float64
+	// MinMargin is the minimum margin, as a fraction of the estimate.
+	MinMargin float64
+	// MaxGas caps the recommended gas limit. Zero uses the maximum block gas,
+	// if any.
+	MaxGas uint64
+}
+
+// DefaultGasAdjustmentPolicy returns a policy of 3 runs a block apart, with a
+// margin of 2 standard deviations and at least 10% of the estimate.
+func DefaultGasAdjustmentPolicy() GasAdjustmentPolicy {
+	return GasAdjustmentPolicy{
+		Runs:          3,
+		BlockTimeStep: 5 * time.Second,
+		Confidence:    2,
+		MinMargin:     0.1,
+	}
+}
+
+// Validate returns an error if the policy is invalid.
+func (p GasAdjustmentPolicy) Validate() error {
+	if p.Runs < 1 {
+		return fmt.Errorf("gas estimation needs at least one run, got %d", p.Runs)
+	}
+	if p.BlockTimeStep < 0 {
+		return fmt.Errorf("block time step must not be negative, got %s", p.BlockTimeStep)
+	}
+	if p.Confidence < 0 || math.IsNaN(p.Confidence) {
+		return fmt.Errorf("confidence must not be negative, got %v", p.Confidence)
+	}
+	if p.MinMargin < 0 || math.IsNaN(p.MinMargin) {
+		return fmt.Errorf("minimum margin must not be negative, got %v", p.MinMargin)
+	}
+	return nil
+}
+
+// GasEstimate is the result of EstimateGas.
+type GasEstimate struct {
+	// Samples holds the gas used by each simulation run.
+	Samples []uint64
+	// GasUsed is the highest gas used over all runs.
+	GasUsed uint64
+	// Margin is the confidence margin added to GasUsed.
+	Margin uint64
+	// GasLimit is the recommended gas limit, GasUsed plus Margin capped by the
+	// policy MaxGas or the maximum block gas.
+	GasLimit uint64
+	// Result is the result of the first simulation run.
+	Result *sdk.Result
+}
+
+// EstimateGas simulates the tx several times, each time in the context of a
+// later block, and returns a gas estimate with a confidence margin. Unlike a
+// single Simulate run, whose gas used only holds for the current block, the
+// estimate accounts for gas consumption depending on the block height or time.
+// The tx must succeed in every run.
+func (app *BaseApp) EstimateGas(txBytes []byte, policy GasAdjustmentPolicy) (GasEstimate, error) {
+	if err := policy.Validate(); err != nil {
+		return GasEstimate{}, sdkerrors.ErrInvalidRequest.Wrap(err.Error())
+	}
+
+	estimate := GasEstimate{Samples: make([]uint64, 0, policy.Runs)}
+	maxGas := policy.MaxGas
+
+	for i := 0; i < policy.Runs; i++ {
+		ctx := app.getContextForTx(runTxModeSimulate, txBytes)
+		if i == 0 && maxGas == 0 {
+			maxGas = app.GetMaximumBlockGas(ctx)
+		}
+
+		ctx = ctx.
+			WithBlockHeight(ctx.BlockHeight() + int64(i)).
+			WithBlockTime(ctx.BlockTime().Add(time.Duration(i) * policy.BlockTimeStep))
+
+		gasInfo, result, _, _, err := app.runTxWithContext(ctx, runTxModeSimulate, txBytes)
+		if err != nil {
+			return GasEstimate{}, sdkerrors.Wrapf(err, "gas estimation run %d failed", i)
+		}
+
+		if i == 0 {
+			estimate.Result = result
+		}
+		estimate.Samples = append(estimate.Samples, gasInfo.GasUsed)
+	}
+
+	estimate.GasUsed, estimate.Margin = gasMargin(estimate.Samples, policy)
+	estimate.GasLimit = estimate.GasUsed + estimate.Margin
+	if estimate.GasLimit < estimate.GasUsed {
+		estimate.GasLimit = math.MaxUint64
+	}
+
+	if maxGas > 0 && estimate.GasLimit > maxGas {
+		if estimate.GasUsed > maxGas {
+			return GasEstimate{}, sdkerrors.ErrOutOfGas.Wrapf("estimated gas %d exceeds the maximum gas %d", estimate.GasUsed, maxGas)
+		}
+		estimate.GasLimit = maxGas
+	}
+
+	return estimate, nil
+}
+
+// gasMargin returns the highest sample and the margin to add to it: the
+// policy confidence times the samples standard deviation, and at least the
+// policy minimum margin.
+func gasMargin(samples []uint64, policy GasAdjustmentPolicy) (highest, margin uint64) {
+	var mean float64
+	for _, sample := range samples {
+		if sample > highest {
+			highest = sample
+		}
+		mean += float64(sample)
+	}
+	mean /= float64(len(samples))
+
+	var variance float64
+	for _, sample := range samples {
+		d := float64(sample) - mean
+		variance += d * d
+	}
+	variance /= float64(len(samples))
+
+	m := math.Max(policy.Confidence*math.Sqrt(variance), policy.MinMargin*float64(highest))
+	if m >= math.MaxUint64 {
+		return highest, math.MaxUint64
+	}
+	return highest, uint64(math.Ceil(m))
+}
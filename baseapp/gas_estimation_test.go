@@ -0,0 +1,85 @@
+package baseapp_test
+
+import (
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	baseapptestutil "github.com/cosmos/cosmos-sdk/baseapp/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestEstimateGas(t *testing.T) {
+	gasConsumed := uint64(5)
+	anteOpt := func(bapp *baseapp.BaseApp) {
+		bapp.SetAnteHandler(func(ctx sdk.Context, tx sdk.Tx, simulate bool) (newCtx sdk.Context, err error) {
+			// make the gas used depend on the block context
+			newCtx = ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
+			newCtx.GasMeter().ConsumeGas(uint64(ctx.BlockHeight())*100, "ante")
+			return
+		})
+	}
+	suite := NewBaseAppSuite(t, anteOpt)
+
+	suite.baseApp.InitChain(abci.RequestInitChain{
+		ConsensusParams: &tmproto.ConsensusParams{},
+	})
+
+	baseapptestutil.RegisterCounterServer(suite.baseApp.MsgServiceRouter(), CounterServerImplGasMeterOnly{gasConsumed})
+
+	header := tmproto.Header{Height: 1}
+	suite.baseApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+
+	tx := newTxCounter(t, suite.txConfig, 1, 1)
+	txBytes, err := suite.txConfig.TxEncoder()(tx)
+	require.NoError(t, err)
+
+	t.Run("single run", func(t *testing.T) {
+		policy := baseapp.DefaultGasAdjustmentPolicy()
+		policy.Runs = 1
+
+		estimate, err := suite.baseApp.EstimateGas(txBytes, policy)
+		require.NoError(t, err)
+		require.NotNil(t, estimate.Result)
+
+		gInfo, _, err := suite.baseApp.Simulate(txBytes)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{gInfo.GasUsed}, estimate.Samples)
+		require.Equal(t, gInfo.GasUsed, estimate.GasUsed)
+		require.Equal(t, estimate.GasUsed+estimate.Margin, estimate.GasLimit)
+	})
+
+	t.Run("varying block context", func(t *testing.T) {
+		estimate, err := suite.baseApp.EstimateGas(txBytes, baseapp.DefaultGasAdjustmentPolicy())
+		require.NoError(t, err)
+		require.Len(t, estimate.Samples, 3)
+		require.Equal(t, estimate.Samples[0]+100, estimate.Samples[1])
+		require.Equal(t, estimate.Samples[1]+100, estimate.Samples[2])
+		require.Equal(t, estimate.Samples[2], estimate.GasUsed)
+		require.Greater(t, estimate.Margin, uint64(0))
+		require.Equal(t, estimate.GasUsed+estimate.Margin, estimate.GasLimit)
+	})
+
+	t.Run("capped gas limit", func(t *testing.T) {
+		policy := baseapp.DefaultGasAdjustmentPolicy()
+		estimate, err := suite.baseApp.EstimateGas(txBytes, policy)
+		require.NoError(t, err)
+
+		policy.MaxGas = estimate.GasUsed
+		capped, err := suite.baseApp.EstimateGas(txBytes, policy)
+		require.NoError(t, err)
+		require.Equal(t, estimate.GasUsed, capped.GasLimit)
+
+		policy.MaxGas = estimate.GasUsed - 1
+		_, err = suite.baseApp.EstimateGas(txBytes, policy)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		_, err := suite.baseApp.EstimateGas(txBytes, baseapp.GasAdjustmentPolicy{})
+		require.Error(t, err)
+	})
+}
@@ -80,7 +80,12 @@ func (app *BaseApp) RegisterGRPCServer(server gogogrpc.Server) {
 				MethodName: method.MethodName,
 				Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
 					return methodHandler(srv, ctx, dec, grpcmiddleware.ChainUnaryServer(
-						grpcrecovery.UnaryServerInterceptor(),
+						grpcrecovery.UnaryServerInterceptor(grpcrecovery.WithRecoveryHandler(func(p interface{}) error {
+							if err := app.queryOutOfGasError(p); err != nil {
+								return err
+							}
+							return status.Errorf(codes.Internal, "%v", p)
+						})),
 						interceptor,
 					))
 				},
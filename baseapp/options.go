@@ -3,10 +3,12 @@ package baseapp
 import (
 	"fmt"
 	"io"
+	"sort"
 
 	dbm "github.com/cometbft/cometbft-db"
 
 	"github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/aggsig"
 	"github.com/cosmos/cosmos-sdk/snapshots"
 	snapshottypes "github.com/cosmos/cosmos-sdk/snapshots/types"
 	"github.com/cosmos/cosmos-sdk/store"
@@ -91,6 +93,42 @@ func SetMempool(mempool mempool.Mempool) func(*BaseApp) {
 	return func(app *BaseApp) { app.SetMempool(mempool) }
 }
 
+// SetQueryGasLimit returns a BaseApp option function that sets the gas limit
+// of gRPC and ABCI queries. Zero means no limit.
+func SetQueryGasLimit(queryGasLimit uint64) func(*BaseApp) {
+	return func(app *BaseApp) { app.queryGasLimit = queryGasLimit }
+}
+
+// SetRateLimiter returns a BaseApp option function that sets the rate limiter
+// of new transactions.
+func SetRateLimiter(rl RateLimiter) func(*BaseApp) {
+	return func(app *BaseApp) { app.SetRateLimiter(rl) }
+}
+
+// SetSignatureAlgorithms returns a BaseApp option function that sets the
+// signature algorithms the app advertises.
+func SetSignatureAlgorithms(algos ...string) func(*BaseApp) {
+	return func(app *BaseApp) { app.SetSignatureAlgorithms(algos...) }
+}
+
+// SetStoreAccessRecording returns a BaseApp option function that enables the
+// recording of the store access of delivered and simulated transactions.
+func SetStoreAccessRecording(enabled bool) func(*BaseApp) {
+	return func(app *BaseApp) { app.SetStoreAccessRecording(enabled) }
+}
+
+// SetTxSignatureExtractor returns a BaseApp option function that sets the
+// extractor of the signatures verified ahead of the execution of blocks.
+func SetTxSignatureExtractor(extractor TxSignatureExtractor, workers int) func(*BaseApp) {
+	return func(app *BaseApp) { app.SetTxSignatureExtractor(extractor, workers) }
+}
+
+// SetTxDecoders returns a BaseApp option function that sets the TxDecoder to
+// the chain of the decoders, see ChainTxDecoders.
+func SetTxDecoders(decoders ...sdk.TxDecoder) func(*BaseApp) {
+	return func(app *BaseApp) { app.SetTxDecoders(decoders...) }
+}
+
 // SetChainID sets the chain ID in BaseApp.
 func SetChainID(chainID string) func(*BaseApp) {
 	return func(app *BaseApp) { app.chainID = chainID }
@@ -257,6 +295,16 @@ func (app *BaseApp) SetTxDecoder(txDecoder sdk.TxDecoder) {
 	app.txDecoder = txDecoder
 }
 
+// SetTxDecoders sets the TxDecoder to the chain of the decoders, tried in
+// order on CheckTx, DeliverTx, the proposals and simulations, see
+// ChainTxDecoders.
+func (app *BaseApp) SetTxDecoders(decoders ...sdk.TxDecoder) {
+	if app.sealed {
+		panic("SetTxDecoders() on sealed BaseApp")
+	}
+	app.txDecoder = ChainTxDecoders(decoders...)
+}
+
 // SetTxEncoder sets the TxEncoder if it wasn't provided in the BaseApp constructor.
 func (app *BaseApp) SetTxEncoder(txEncoder sdk.TxEncoder) {
 	app.txEncoder = txEncoder
@@ -277,6 +325,56 @@ func (app *BaseApp) SetMempool(mempool mempool.Mempool) {
 	app.mempool = mempool
 }
 
+// SetRateLimiter sets the rate limiter consulted on CheckTx of new
+// transactions, before and after the ante handler.
+func (app *BaseApp) SetRateLimiter(rl RateLimiter) {
+	if app.sealed {
+		panic("SetRateLimiter() on sealed BaseApp")
+	}
+	app.rateLimiter = rl
+}
+
+// SetSignatureAlgorithms sets the type URLs of the public keys whose
+// signatures the ante handler verifies, e.g.
+// ante.DefaultSignatureAlgorithms, which the app advertises with its
+// capabilities. The public keys registered in the interface registry may
+// include unsupported ones, e.g. ed25519 consensus keys.
+func (app *BaseApp) SetSignatureAlgorithms(algos ...string) {
+	if app.sealed {
+		panic("SetSignatureAlgorithms() on sealed BaseApp")
+	}
+	app.signatureAlgorithms = append([]string(nil), algos...)
+	sort.Strings(app.signatureAlgorithms)
+}
+
+// SetStoreAccessRecording enables the recording of the KV store reads and
+// writes of delivered and simulated transactions. The post handler reads them
+// with StoreAccessFromContext, e.g. to implement fee rebates, and the results
+// of simulated transactions get a store_access event holding them, for
+// clients to estimate such fees.
+func (app *BaseApp) SetStoreAccessRecording(enabled bool) {
+	if app.sealed {
+		panic("SetStoreAccessRecording() on sealed BaseApp")
+	}
+	app.recordStoreAccess = enabled
+}
+
+// SetTxSignatureExtractor sets the extractor of the signatures of the
+// transactions of proposed blocks, which are then verified in parallel on
+// ProcessProposal, with up to workers goroutines, GOMAXPROCS if workers isn't
+// positive. The ante handler skips the verification of the valid signatures.
+func (app *BaseApp) SetTxSignatureExtractor(extractor TxSignatureExtractor, workers int) {
+	if app.sealed {
+		panic("SetTxSignatureExtractor() on sealed BaseApp")
+	}
+	app.txSigExtractor = extractor
+	app.sigVerifyWorkers = workers
+	app.sigCache = nil
+	if extractor != nil {
+		app.sigCache = aggsig.NewCache(DefaultSigCacheSize)
+	}
+}
+
 // SetProcessProposal sets the process proposal function for the BaseApp.
 func (app *BaseApp) SetProcessProposal(handler sdk.ProcessProposalHandler) {
 	if app.sealed {
@@ -293,3 +391,23 @@ func (app *BaseApp) SetPrepareProposal(handler sdk.PrepareProposalHandler) {
 
 	app.prepareProposal = handler
 }
+
+// SetExtendVoteHandler sets the handler extending the precommit votes of the
+// node. It is inert with CometBFT v0.37, whose ABCI 1.0 has no vote
+// extensions, see VoteExtensionsEnabled.
+func (app *BaseApp) SetExtendVoteHandler(handler sdk.ExtendVoteHandler) {
+	if app.sealed {
+		panic("SetExtendVoteHandler() on sealed BaseApp")
+	}
+	app.extendVote = handler
+}
+
+// SetVerifyVoteExtensionHandler sets the handler verifying the vote extensions
+// of validators. It is inert with CometBFT v0.37, whose ABCI 1.0 has no vote
+// extensions, see VoteExtensionsEnabled.
+func (app *BaseApp) SetVerifyVoteExtensionHandler(handler sdk.VerifyVoteExtensionHandler) {
+	if app.sealed {
+		panic("SetVerifyVoteExtensionHandler() on sealed BaseApp")
+	}
+	app.verifyVoteExt = handler
+}
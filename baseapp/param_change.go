@@ -0,0 +1,167 @@
+package baseapp
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ParamChangeSubscriber is notified when module params change, e.g. to rebuild
+// a configuration cached from them.
+//
+// Modules report their params changes by emitting an sdk.EventTypeParamChange
+// event with their name as sdk.AttributeKeyModule attribute. Subscribers are
+// notified as soon as the begin blocker, a delivered transaction or the end
+// blocker reporting the change returns, with a context on the block state, so
+// that the following transactions of the block run with the new params, as if
+// they were read from the store.
+type ParamChangeSubscriber interface {
+	// OnParamChange is called with the sorted names of the modules whose params
+	// changed, or with nil modules when the subscriber must load its
+	// configuration, i.e. on the first BeginBlock after the node starts.
+	OnParamChange(ctx sdk.Context, modules []string) error
+}
+
+// SubscribeParamChanges registers a subscriber notified of module params
+// changes.
+func (app *BaseApp) SubscribeParamChanges(sub ParamChangeSubscriber) {
+	if app.sealed {
+		panic("SubscribeParamChanges() on sealed BaseApp")
+	}
+
+	app.paramChangeSubscribers = append(app.paramChangeSubscribers, sub)
+}
+
+// SetReloadableAnteHandler sets the ante handler and subscribes it to params
+// changes.
+func (app *BaseApp) SetReloadableAnteHandler(ah *ReloadableAnteHandler) {
+	app.SetAnteHandler(ah.AnteHandle)
+	app.SubscribeParamChanges(ah)
+}
+
+// loadParamChangeSubscribers lets subscribers load their configuration from
+// the state of ctx, once after the node starts.
+func (app *BaseApp) loadParamChangeSubscribers(ctx sdk.Context) {
+	if app.paramChangeSubscribersReady {
+		return
+	}
+
+	for _, sub := range app.paramChangeSubscribers {
+		if err := sub.OnParamChange(paramChangeContext(ctx), nil); err != nil {
+			panic(fmt.Errorf("failed to load param change subscriber, height: %d, err: %w", ctx.BlockHeight(), err))
+		}
+	}
+	app.paramChangeSubscribersReady = true
+}
+
+// notifyParamChanges notifies subscribers of the modules reporting params
+// changes in events, with the state of ctx.
+func (app *BaseApp) notifyParamChanges(ctx sdk.Context, events []abci.Event) {
+	if len(app.paramChangeSubscribers) == 0 {
+		return
+	}
+
+	changed := make(map[string]bool)
+	for _, event := range events {
+		if event.Type != sdk.EventTypeParamChange {
+			continue
+		}
+
+		for _, attr := range event.Attributes {
+			if attr.Key == sdk.AttributeKeyModule {
+				changed[attr.Value] = true
+			}
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	modules := make([]string, 0, len(changed))
+	for module := range changed {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, sub := range app.paramChangeSubscribers {
+		if err := sub.OnParamChange(paramChangeContext(ctx), modules); err != nil {
+			panic(fmt.Errorf("failed to notify param change subscriber, height: %d, modules: %v, err: %w", ctx.BlockHeight(), modules, err))
+		}
+	}
+}
+
+// paramChangeContext returns the context subscribers are notified with, whose
+// gas and events aren't accounted to the block.
+func paramChangeContext(ctx sdk.Context) sdk.Context {
+	return ctx.WithGasMeter(sdk.NewInfiniteGasMeter()).WithEventManager(sdk.NewEventManager())
+}
+
+// AnteHandlerBuilder builds an ante handler from the state of ctx, e.g. with a
+// configuration read from module params.
+type AnteHandlerBuilder func(ctx sdk.Context) (sdk.AnteHandler, error)
+
+// ReloadableAnteHandler is an ante handler which is rebuilt when the params of
+// the modules it depends on change, e.g. signature verification costs, so that
+// its cached configuration is updated without restarting the node. Rebuilt handlers are swapped in atomically, and are used
+// from the transaction following the params change.
+type ReloadableAnteHandler struct {
+	build   AnteHandlerBuilder
+	modules map[string]bool
+	handler atomic.Pointer[sdk.AnteHandler]
+}
+
+var _ ParamChangeSubscriber = &ReloadableAnteHandler{}
+
+// NewReloadableAnteHandler returns an ante handler built by build, and rebuilt
+// when the params of the given modules change.
+func NewReloadableAnteHandler(build AnteHandlerBuilder, modules ...string) *ReloadableAnteHandler {
+	h := &ReloadableAnteHandler{build: build, modules: make(map[string]bool)}
+	for _, module := range modules {
+		h.modules[module] = true
+	}
+	return h
+}
+
+// AnteHandle implements sdk.AnteHandler.
+func (h *ReloadableAnteHandler) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, error) {
+	handler := h.handler.Load()
+	if handler == nil {
+		// the handler is built on the first BeginBlock after the node starts,
+		// before which it can only be called by CheckTx and simulations
+		if err := h.reload(ctx.WithGasMeter(sdk.NewInfiniteGasMeter())); err != nil {
+			return ctx, err
+		}
+		handler = h.handler.Load()
+	}
+
+	return (*handler)(ctx, tx, simulate)
+}
+
+// OnParamChange implements ParamChangeSubscriber, rebuilding the ante handler
+// when the params of one of its modules changed.
+func (h *ReloadableAnteHandler) OnParamChange(ctx sdk.Context, modules []string) error {
+	if modules == nil {
+		return h.reload(ctx)
+	}
+
+	for _, module := range modules {
+		if h.modules[module] {
+			return h.reload(ctx)
+		}
+	}
+	return nil
+}
+
+func (h *ReloadableAnteHandler) reload(ctx sdk.Context) error {
+	handler, err := h.build(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to build ante handler: %w", err)
+	}
+
+	h.handler.Store(&handler)
+	return nil
+}
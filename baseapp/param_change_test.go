@@ -0,0 +1,92 @@
+package baseapp_test
+
+import (
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	baseapptestutil "github.com/cosmos/cosmos-sdk/baseapp/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestParamChange_ReloadableAnteHandler(t *testing.T) {
+	paramKey := []byte("param-key")
+	gasPricesKey := []byte("gas-prices-key")
+
+	// built records the param value read by each build of the ante handler,
+	// and used the one the last tx ran with
+	var built []string
+	var used string
+	authAnte := baseapp.NewReloadableAnteHandler(func(ctx sdk.Context) (sdk.AnteHandler, error) {
+		param := string(ctx.KVStore(capKey1).Get(paramKey))
+		built = append(built, param)
+		return func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) {
+			used = param
+			return ctx, nil
+		}, nil
+	}, "auth")
+
+	var otherBuilds int
+	otherAnte := baseapp.NewReloadableAnteHandler(func(ctx sdk.Context) (sdk.AnteHandler, error) {
+		otherBuilds++
+		return func(ctx sdk.Context, _ sdk.Tx, _ bool) (sdk.Context, error) { return ctx, nil }, nil
+	}, "bank")
+
+	setParam := func(ctx sdk.Context, key []byte, value, module string) []abci.Event {
+		ctx.KVStore(capKey1).Set(key, []byte(value))
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(sdk.EventTypeParamChange, sdk.NewAttribute(sdk.AttributeKeyModule, module)),
+		)
+		return ctx.EventManager().ABCIEvents()
+	}
+
+	opts := func(bapp *baseapp.BaseApp) {
+		bapp.SetReloadableAnteHandler(authAnte)
+		bapp.SubscribeParamChanges(otherAnte)
+		bapp.SetBeginBlocker(func(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+			if req.Header.Height != 2 {
+				return abci.ResponseBeginBlock{}
+			}
+			return abci.ResponseBeginBlock{Events: setParam(ctx, paramKey, "updated", "auth")}
+		})
+		bapp.SetEndBlocker(func(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+			if req.Height != 3 {
+				return abci.ResponseEndBlock{}
+			}
+			return abci.ResponseEndBlock{Events: setParam(ctx, gasPricesKey, "3stake", "fees")}
+		})
+	}
+	suite := NewBaseAppSuite(t, opts)
+	suite.baseApp.InitChain(abci.RequestInitChain{ConsensusParams: &tmproto.ConsensusParams{}})
+	baseapptestutil.RegisterCounterServer(suite.baseApp.MsgServiceRouter(), CounterServerImplGasMeterOnly{})
+
+	txBytes, err := suite.txConfig.TxEncoder()(newTxCounter(t, suite.txConfig, 0, 0))
+	require.NoError(t, err)
+
+	// the ante handlers are built on the first block
+	suite.baseApp.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: 1}})
+	require.Equal(t, []string{""}, built)
+	require.Equal(t, 1, otherBuilds)
+	suite.baseApp.EndBlock(abci.RequestEndBlock{Height: 1})
+	suite.baseApp.Commit()
+
+	// the params changed by the begin blocker apply to the txs of the block
+	suite.baseApp.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: 2}})
+	require.Equal(t, []string{"", "updated"}, built)
+	require.Equal(t, 1, otherBuilds)
+	res := suite.baseApp.DeliverTx(abci.RequestDeliverTx{Tx: txBytes})
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, "updated", used)
+	suite.baseApp.EndBlock(abci.RequestEndBlock{Height: 2})
+	suite.baseApp.Commit()
+
+	// the params changes of other modules don't rebuild the ante handlers
+	suite.baseApp.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: 3}})
+	suite.baseApp.EndBlock(abci.RequestEndBlock{Height: 3})
+	require.Equal(t, []string{"", "updated"}, built)
+	require.Equal(t, 1, otherBuilds)
+	suite.baseApp.Commit()
+}
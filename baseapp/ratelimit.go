@@ -0,0 +1,249 @@
+package baseapp
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// RateLimiter limits the rate of new transactions accepted in the mempool. It
+// is consulted on CheckTx of new transactions, once after they are decoded and
+// before the ante handler runs, so that spam is shed before signatures are
+// verified, and once after the ante handler verified their signatures and
+// fees, so that allowances are only charged for the transactions signed and
+// paid for. Rechecked, simulated and delivered transactions aren't limited.
+type RateLimiter interface {
+	// Allow returns an error, wrapping sdkerrors.ErrRateLimited, if the
+	// transaction must be rejected before the ante handler runs. It must not
+	// charge any allowance, since the transaction isn't verified yet.
+	Allow(ctx sdk.Context, tx sdk.Tx) error
+	// AllowVerified returns an error, wrapping sdkerrors.ErrRateLimited, if
+	// the transaction, whose signatures and fees were verified by the ante
+	// handler, must be rejected, and charges its allowances otherwise.
+	AllowVerified(ctx sdk.Context, tx sdk.Tx) error
+}
+
+// RateLimit is the rate of a token bucket. A zero RateLimit disables the limit.
+type RateLimit struct {
+	// PerSecond is the number of transactions, or messages for message type
+	// limits, allowed per second on average.
+	PerSecond float64
+	// Burst is the number of transactions, or messages, allowed at once.
+	Burst uint64
+}
+
+func (rl RateLimit) enabled() bool {
+	return rl.PerSecond > 0 && rl.Burst > 0
+}
+
+// TokenBucketRateLimiterConfig is the configuration of a
+// TokenBucketRateLimiter.
+type TokenBucketRateLimiterConfig struct {
+	// PerSender limits the transactions signed by each signer.
+	PerSender RateLimit
+	// PerMsgType limits the messages of each type, across all transactions.
+	PerMsgType RateLimit
+}
+
+// Enabled reports whether any of the limits is enabled.
+func (cfg TokenBucketRateLimiterConfig) Enabled() bool {
+	return cfg.PerSender.enabled() || cfg.PerMsgType.enabled()
+}
+
+const (
+	rateLimitKindSender  = "sender"
+	rateLimitKindMsgType = "msg type"
+
+	// idle buckets are pruned once there are pruneBucketsThreshold of them,
+	// at most every pruneBucketsInterval
+	pruneBucketsThreshold = 10_000
+	pruneBucketsInterval  = time.Minute
+)
+
+type rateLimitKey struct {
+	kind string
+	key  string
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the last refill.
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.limit.PerSecond
+		if b.tokens > float64(b.limit.Burst) {
+			b.tokens = float64(b.limit.Burst)
+		}
+	}
+	b.last = now
+}
+
+// TokenBucketRateLimiter is an in-memory RateLimiter limiting transactions per
+// sender and per message type with token buckets. A transaction is only
+// allowed if all its buckets have enough tokens. They are only read before the
+// ante handler, and taken once the ante handler verified the signatures and
+// fees of the transaction, so that unsigned or fee-less transactions can't use
+// up the allowance of the senders or of the message types. Its state is local
+// to the node and lost on restart.
+type TokenBucketRateLimiter struct {
+	cfg TokenBucketRateLimiterConfig
+	now func() time.Time
+
+	mtx        sync.Mutex
+	buckets    map[rateLimitKey]*tokenBucket
+	lastPruned time.Time
+}
+
+var _ RateLimiter = (*TokenBucketRateLimiter)(nil)
+
+// NewTokenBucketRateLimiter returns a TokenBucketRateLimiter with the given
+// limits.
+func NewTokenBucketRateLimiter(cfg TokenBucketRateLimiterConfig) *TokenBucketRateLimiter {
+	return &TokenBucketRateLimiter{
+		cfg:     cfg,
+		now:     time.Now,
+		buckets: make(map[rateLimitKey]*tokenBucket),
+	}
+}
+
+// Allow implements RateLimiter. It rejects the transaction when its signers or
+// its message types have used up their allowance, without taking any token.
+func (rl *TokenBucketRateLimiter) Allow(_ sdk.Context, tx sdk.Tx) error {
+	return rl.take(rl.costs(tx), false)
+}
+
+// AllowVerified implements RateLimiter. It takes the tokens of the signers and
+// of the message types of the transaction.
+func (rl *TokenBucketRateLimiter) AllowVerified(_ sdk.Context, tx sdk.Tx) error {
+	return rl.take(rl.costs(tx), true)
+}
+
+// take checks that the buckets of the costs have enough tokens, and takes them
+// if charge is set.
+func (rl *TokenBucketRateLimiter) take(costs map[rateLimitKey]uint64, charge bool) error {
+	if len(costs) == 0 {
+		return nil
+	}
+
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	now := rl.now()
+	rl.prune(now)
+
+	// check all the buckets before taking any token, so that rejected
+	// transactions don't use up the allowance of their other keys
+	keys := make([]rateLimitKey, 0, len(costs))
+	for key := range costs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		return keys[i].key < keys[j].key
+	})
+
+	buckets := make([]*tokenBucket, len(keys))
+	for i, key := range keys {
+		b := rl.bucket(key, now)
+		if b.tokens < float64(costs[key]) {
+			return sdkerrors.Wrapf(sdkerrors.ErrRateLimited, "%s %s exceeds its limit of %g per second (burst %d)",
+				key.kind, key.key, b.limit.PerSecond, b.limit.Burst)
+		}
+		buckets[i] = b
+	}
+
+	if charge {
+		for i, key := range keys {
+			buckets[i].tokens -= float64(costs[key])
+		}
+	}
+	return nil
+}
+
+// costs returns the tokens the transaction takes from each of its buckets:
+// one from the bucket of each of its signers, and one per message from the
+// bucket of its type.
+func (rl *TokenBucketRateLimiter) costs(tx sdk.Tx) map[rateLimitKey]uint64 {
+	costs := rl.msgTypeCosts(tx)
+	for key, cost := range rl.senderCosts(tx) {
+		costs[key] = cost
+	}
+	return costs
+}
+
+// senderCosts returns the token the transaction takes from the bucket of
+// each of its signers.
+func (rl *TokenBucketRateLimiter) senderCosts(tx sdk.Tx) map[rateLimitKey]uint64 {
+	costs := make(map[rateLimitKey]uint64)
+	if !rl.cfg.PerSender.enabled() {
+		return costs
+	}
+
+	for _, msg := range tx.GetMsgs() {
+		for _, signer := range msg.GetSigners() {
+			costs[rateLimitKey{rateLimitKindSender, signer.String()}] = 1
+		}
+	}
+	return costs
+}
+
+// msgTypeCosts returns the tokens the transaction takes from the bucket of
+// each of its message types, one per message.
+func (rl *TokenBucketRateLimiter) msgTypeCosts(tx sdk.Tx) map[rateLimitKey]uint64 {
+	costs := make(map[rateLimitKey]uint64)
+	if !rl.cfg.PerMsgType.enabled() {
+		return costs
+	}
+
+	for _, msg := range tx.GetMsgs() {
+		costs[rateLimitKey{rateLimitKindMsgType, sdk.MsgTypeURL(msg)}]++
+	}
+	return costs
+}
+
+// bucket returns the refilled bucket of the key, creating a full one if it
+// doesn't exist.
+func (rl *TokenBucketRateLimiter) bucket(key rateLimitKey, now time.Time) *tokenBucket {
+	b, ok := rl.buckets[key]
+	if !ok {
+		limit := rl.limit(key.kind)
+		b = &tokenBucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		rl.buckets[key] = b
+	}
+	b.refill(now)
+	return b
+}
+
+func (rl *TokenBucketRateLimiter) limit(kind string) RateLimit {
+	switch kind {
+	case rateLimitKindSender:
+		return rl.cfg.PerSender
+	default:
+		return rl.cfg.PerMsgType
+	}
+}
+
+// prune removes the buckets which are full again, which are equivalent to
+// new buckets, so that the buckets of past senders don't accumulate.
+func (rl *TokenBucketRateLimiter) prune(now time.Time) {
+	if len(rl.buckets) < pruneBucketsThreshold || now.Sub(rl.lastPruned) < pruneBucketsInterval {
+		return
+	}
+	rl.lastPruned = now
+
+	for key, b := range rl.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(rl.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package baseapp
+
+import (
+	"testing"
+	"time"
+
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+type rateLimitTestTx struct {
+	msgs []sdk.Msg
+}
+
+func (tx rateLimitTestTx) GetMsgs() []sdk.Msg   { return tx.msgs }
+func (tx rateLimitTestTx) ValidateBasic() error { return nil }
+
+func TestTokenBucketRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	rl := NewTokenBucketRateLimiter(TokenBucketRateLimiterConfig{
+		PerSender:  RateLimit{PerSecond: 1, Burst: 2},
+		PerMsgType: RateLimit{PerSecond: 10, Burst: 5},
+	})
+	rl.now = func() time.Time { return now }
+
+	ctx := sdk.NewContext(nil, tmproto.Header{}, true, nil)
+	alice, bob := sdk.AccAddress("alice"), sdk.AccAddress("bob")
+	txOf := func(signers ...sdk.AccAddress) sdk.Tx {
+		msgs := make([]sdk.Msg, len(signers))
+		for i, signer := range signers {
+			msgs[i] = testdata.NewTestMsg(signer)
+		}
+		return rateLimitTestTx{msgs: msgs}
+	}
+
+	msgType := rateLimitKey{rateLimitKindMsgType, sdk.MsgTypeURL(&testdata.TestMsg{})}
+
+	// no allowance is charged before the signatures and fees are verified,
+	// so that unsigned txs don't use up the allowance of the senders nor of
+	// the msg types
+	for i := 0; i < 10; i++ {
+		require.NoError(t, rl.Allow(ctx, txOf(alice)))
+	}
+	require.Equal(t, float64(5), rl.buckets[msgType].tokens)
+	require.NoError(t, rl.AllowVerified(ctx, txOf(alice)))
+	require.NoError(t, rl.AllowVerified(ctx, txOf(alice)))
+	err := rl.AllowVerified(ctx, txOf(alice))
+	require.ErrorIs(t, err, sdkerrors.ErrRateLimited)
+	require.Contains(t, err.Error(), "sender "+alice.String())
+
+	// once alice's burst is used up, her txs are rejected before the ante
+	// handler, and bob is limited separately
+	require.ErrorIs(t, rl.Allow(ctx, txOf(alice)), sdkerrors.ErrRateLimited)
+	require.NoError(t, rl.Allow(ctx, txOf(bob)))
+	require.NoError(t, rl.AllowVerified(ctx, txOf(bob)))
+
+	// a rejected tx doesn't take the tokens of its other keys
+	require.ErrorIs(t, rl.AllowVerified(ctx, txOf(alice, bob)), sdkerrors.ErrRateLimited)
+	require.Equal(t, float64(2), rl.buckets[msgType].tokens)
+	require.NoError(t, rl.AllowVerified(ctx, txOf(bob)))
+
+	// the msg type has one token left
+	carol, dave, erin := sdk.AccAddress("carol"), sdk.AccAddress("dave"), sdk.AccAddress("erin")
+	require.NoError(t, rl.AllowVerified(ctx, txOf(carol)))
+	err = rl.Allow(ctx, txOf(dave))
+	require.ErrorIs(t, err, sdkerrors.ErrRateLimited)
+	require.Contains(t, err.Error(), "msg type /testpb.TestMsg")
+	require.ErrorIs(t, rl.AllowVerified(ctx, txOf(dave)), sdkerrors.ErrRateLimited)
+
+	// tokens are refilled at the rate
+	now = now.Add(time.Second)
+	require.NoError(t, rl.AllowVerified(ctx, txOf(alice)))
+	require.ErrorIs(t, rl.AllowVerified(ctx, txOf(alice)), sdkerrors.ErrRateLimited)
+
+	// each message takes a token of its type, so txs of more messages of a
+	// type than its burst are rejected
+	now = now.Add(10 * time.Second)
+	err = rl.Allow(ctx, txOf(alice, bob, carol, dave, erin, alice))
+	require.ErrorIs(t, err, sdkerrors.ErrRateLimited)
+	require.Contains(t, err.Error(), "msg type /testpb.TestMsg")
+	require.NoError(t, rl.Allow(ctx, txOf(alice, bob, carol, dave, erin)))
+}
+
+func TestTokenBucketRateLimiterDisabled(t *testing.T) {
+	cfg := TokenBucketRateLimiterConfig{PerSender: RateLimit{PerSecond: 1}}
+	require.False(t, cfg.Enabled())
+
+	rl := NewTokenBucketRateLimiter(cfg)
+	ctx := sdk.NewContext(nil, tmproto.Header{}, true, nil)
+	tx := rateLimitTestTx{msgs: []sdk.Msg{testdata.NewTestMsg(sdk.AccAddress("alice"))}}
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, rl.Allow(ctx, tx))
+		require.NoError(t, rl.AllowVerified(ctx, tx))
+	}
+	require.Empty(t, rl.buckets)
+}
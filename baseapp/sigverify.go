@@ -0,0 +1,41 @@
+package baseapp
+
+import (
+	"github.com/cosmos/cosmos-sdk/crypto/aggsig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// DefaultSigCacheSize is the number of verified signatures the BaseApp
+// remembers per block when a TxSignatureExtractor is set.
+const DefaultSigCacheSize = 100_000
+
+// TxSignatureExtractor returns the signatures of a transaction, with the
+// bytes they sign, for the BaseApp to verify them ahead of its execution. It
+// runs on the state of the last committed block: signatures whose sign bytes
+// it can't compute, e.g. of accounts created in the block, may be omitted,
+// and are verified by the ante handler as usual.
+type TxSignatureExtractor func(ctx sdk.Context, tx sdk.Tx) ([]aggsig.Entry, error)
+
+// preVerifySignatures verifies the signatures of the transactions of a
+// proposed block in one batch, spread over the CPUs, and remembers the valid
+// ones in the signature cache, which the ante handler consults through the
+// context of the transactions. Invalid signatures aren't remembered, so that
+// the ante handler rejects their transactions.
+func (app *BaseApp) preVerifySignatures(ctx sdk.Context, txs [][]byte) {
+	app.sigCache.Reset()
+
+	var entries []aggsig.Entry
+	for _, txBytes := range txs {
+		tx, err := app.txDecoder(txBytes)
+		if err != nil {
+			continue
+		}
+		txEntries, err := app.txSigExtractor(ctx, tx)
+		if err != nil {
+			app.logger.Debug("failed to extract the signatures of tx", "err", err)
+			continue
+		}
+		entries = append(entries, txEntries...)
+	}
+	aggsig.Verify(entries, app.sigVerifyWorkers, app.sigCache)
+}
@@ -0,0 +1,187 @@
+package baseapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Modes of the simulations recorded in fixtures.
+const (
+	SimModeCheck    = "check"
+	SimModeSimulate = "simulate"
+	SimModeDeliver  = "deliver"
+)
+
+var simModes = map[string]runTxMode{
+	SimModeCheck:    runTxModeCheck,
+	SimModeSimulate: runTxModeSimulate,
+	SimModeDeliver:  runTxModeDeliver,
+}
+
+// SimFixture is the input and output of a transaction run with SimCheck,
+// Simulate or SimDeliver. Fixtures are recorded to golden files with a
+// SimFixtureRecorder and replayed with ReplaySimFixtures, so that regression
+// tests detect behavior changes, e.g. after dependency bumps.
+type SimFixture struct {
+	Mode      string `json:"mode"`
+	TxBytes   []byte `json:"tx_bytes"`
+	GasWanted uint64 `json:"gas_wanted"`
+	GasUsed   uint64 `json:"gas_used"`
+	// Codespace, Code and Log describe the error of failed transactions.
+	Codespace string       `json:"codespace,omitempty"`
+	Code      uint32       `json:"code,omitempty"`
+	Log       string       `json:"log,omitempty"`
+	Data      []byte       `json:"data,omitempty"`
+	Events    []abci.Event `json:"events,omitempty"`
+}
+
+func newSimFixture(mode runTxMode, txBytes []byte, gasInfo sdk.GasInfo, result *sdk.Result, err error) SimFixture {
+	f := SimFixture{
+		TxBytes:   txBytes,
+		GasWanted: gasInfo.GasWanted,
+		GasUsed:   gasInfo.GasUsed,
+	}
+	for name, m := range simModes {
+		if m == mode {
+			f.Mode = name
+		}
+	}
+
+	if err != nil {
+		f.Codespace, f.Code, f.Log = sdkerrors.ABCIInfo(err, false)
+	}
+	if result != nil {
+		f.Data = result.Data
+		f.Events = result.Events
+	}
+	return f
+}
+
+// Diff returns the differences of the outputs of actual from the ones of f.
+func (f SimFixture) Diff(actual SimFixture) []string {
+	var diffs []string
+	diff := func(field string, expected, got interface{}) {
+		if !reflect.DeepEqual(expected, got) {
+			diffs = append(diffs, fmt.Sprintf("%s: expected %v, got %v", field, expected, got))
+		}
+	}
+
+	diff("gas wanted", f.GasWanted, actual.GasWanted)
+	diff("gas used", f.GasUsed, actual.GasUsed)
+	diff("codespace", f.Codespace, actual.Codespace)
+	diff("code", f.Code, actual.Code)
+	diff("log", f.Log, actual.Log)
+	if !bytes.Equal(f.Data, actual.Data) {
+		diffs = append(diffs, fmt.Sprintf("data: expected %X, got %X", f.Data, actual.Data))
+	}
+	// compared in their golden file encoding, which doesn't distinguish nil
+	// and empty attributes
+	if len(f.Events) != 0 || len(actual.Events) != 0 {
+		expectedEvents, _ := json.Marshal(f.Events)
+		actualEvents, _ := json.Marshal(actual.Events)
+		if !bytes.Equal(expectedEvents, actualEvents) {
+			diffs = append(diffs, fmt.Sprintf("events: expected %s, got %s", expectedEvents, actualEvents))
+		}
+	}
+	return diffs
+}
+
+// SimFixtureRecorder records the transactions run with SimCheck, Simulate and
+// SimDeliver by the BaseApps it is set on with SetSimFixtureRecorder.
+type SimFixtureRecorder struct {
+	mtx      sync.Mutex
+	fixtures []SimFixture
+}
+
+// NewSimFixtureRecorder returns an empty recorder.
+func NewSimFixtureRecorder() *SimFixtureRecorder {
+	return &SimFixtureRecorder{}
+}
+
+func (r *SimFixtureRecorder) record(f SimFixture) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.fixtures = append(r.fixtures, f)
+}
+
+// Fixtures returns the recorded fixtures, in order.
+func (r *SimFixtureRecorder) Fixtures() []SimFixture {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]SimFixture(nil), r.fixtures...)
+}
+
+// WriteFile writes the recorded fixtures to the golden file at path.
+func (r *SimFixtureRecorder) WriteFile(path string) error {
+	bz, err := json.MarshalIndent(r.Fixtures(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bz, '\n'), 0o644)
+}
+
+// LoadSimFixtures reads the fixtures of the golden file at path.
+func LoadSimFixtures(path string) ([]SimFixture, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []SimFixture
+	if err := json.Unmarshal(bz, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse simulation fixtures %s: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// SetSimFixtureRecorder sets the recorder of the transactions run with
+// SimCheck, Simulate and SimDeliver. A nil recorder stops recording.
+func (app *BaseApp) SetSimFixtureRecorder(r *SimFixtureRecorder) {
+	app.simFixtureRecorder = r
+}
+
+func (app *BaseApp) recordSimFixture(mode runTxMode, txBytes []byte, gasInfo sdk.GasInfo, result *sdk.Result, err error) {
+	if app.simFixtureRecorder != nil {
+		app.simFixtureRecorder.record(newSimFixture(mode, txBytes, gasInfo, result, err))
+	}
+}
+
+// ReplaySimFixtures runs the transactions of the fixtures, in order, and
+// returns an error listing the fixtures whose outputs changed. The app must be
+// in the state the fixtures were recorded from, e.g. within the same block of
+// the same chain for deliver fixtures.
+func (app *BaseApp) ReplaySimFixtures(fixtures []SimFixture) error {
+	var mismatches []string
+	for i, f := range fixtures {
+		mode, ok := simModes[f.Mode]
+		if !ok {
+			return fmt.Errorf("fixture %d: unknown simulation mode %q", i, f.Mode)
+		}
+
+		gasInfo, result, _, _, err := app.runTx(mode, f.TxBytes)
+		actual := newSimFixture(mode, f.TxBytes, gasInfo, result, err)
+		for _, d := range f.Diff(actual) {
+			mismatches = append(mismatches, fmt.Sprintf("fixture %d (%s): %s", i, f.Mode, d))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("simulation fixtures mismatch:\n%s", strings.Join(mismatches, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package baseapp_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	baseapptestutil "github.com/cosmos/cosmos-sdk/baseapp/testutil"
+)
+
+func TestSimFixtures(t *testing.T) {
+	anteKey := []byte("ante-key")
+	deliverKey := []byte("deliver-key")
+
+	// newSuite returns a suite at the start of the first block
+	newSuite := func() *BaseAppSuite {
+		suite := NewBaseAppSuite(t, func(bapp *baseapp.BaseApp) {
+			bapp.SetAnteHandler(anteHandlerTxTest(t, capKey1, anteKey))
+		})
+		baseapptestutil.RegisterCounterServer(suite.baseApp.MsgServiceRouter(), CounterServerImpl{t, capKey1, deliverKey})
+		suite.baseApp.InitChain(abci.RequestInitChain{ConsensusParams: &tmproto.ConsensusParams{}})
+		suite.baseApp.BeginBlock(abci.RequestBeginBlock{Header: tmproto.Header{Height: 1}})
+		return suite
+	}
+
+	suite := newSuite()
+	recorder := baseapp.NewSimFixtureRecorder()
+	suite.baseApp.SetSimFixtureRecorder(recorder)
+
+	txEncoder := suite.txConfig.TxEncoder()
+	_, _, err := suite.baseApp.SimCheck(txEncoder, newTxCounter(t, suite.txConfig, 0, 0))
+	require.NoError(t, err)
+	_, _, err = suite.baseApp.SimDeliver(txEncoder, newTxCounter(t, suite.txConfig, 0, 0))
+	require.NoError(t, err)
+	_, _, err = suite.baseApp.SimDeliver(txEncoder, setFailOnHandler(suite.txConfig, newTxCounter(t, suite.txConfig, 1, 1), true))
+	require.Error(t, err)
+
+	fixtures := recorder.Fixtures()
+	require.Len(t, fixtures, 3)
+	require.Equal(t, baseapp.SimModeCheck, fixtures[0].Mode)
+	require.Equal(t, baseapp.SimModeDeliver, fixtures[1].Mode)
+	require.NotEmpty(t, fixtures[1].Events)
+	require.NotZero(t, fixtures[2].Code)
+
+	file := filepath.Join(t.TempDir(), "testdata", "fixtures.json")
+	require.NoError(t, recorder.WriteFile(file))
+	loaded, err := baseapp.LoadSimFixtures(file)
+	require.NoError(t, err)
+
+	// the fixtures replay on an app in the same state
+	require.NoError(t, newSuite().baseApp.ReplaySimFixtures(loaded))
+
+	// and detect changed outputs
+	loaded[1].GasUsed++
+	loaded[2].Code = 0
+	err = newSuite().baseApp.ReplaySimFixtures(loaded)
+	require.ErrorContains(t, err, "fixture 1 (deliver): gas used")
+	require.ErrorContains(t, err, "fixture 2 (deliver): code")
+}
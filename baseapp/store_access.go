@@ -0,0 +1,172 @@
+package baseapp
+
+import (
+	"strconv"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// EventTypeStoreAccess is the type of the event holding the store access
+	// of a simulated transaction, see SetStoreAccessRecording.
+	EventTypeStoreAccess = "store_access"
+
+	AttributeKeyReads      = "reads"
+	AttributeKeyReadBytes  = "read_bytes"
+	AttributeKeyWrites     = "writes"
+	AttributeKeyWriteBytes = "write_bytes"
+	AttributeKeyIterators  = "iterators"
+)
+
+// StoreAccess counts the KV store accesses of a transaction, from the ante
+// handler on. Applications read it from the post handler with
+// StoreAccessFromContext, e.g. to refund part of the fee of transactions
+// touching little state.
+type StoreAccess struct {
+	// Reads is the number of Get and Has calls, and of the entries iterated
+	// over.
+	Reads uint64
+	// ReadBytes is the size of the keys and values read.
+	ReadBytes uint64
+	// Writes is the number of Set and Delete calls.
+	Writes uint64
+	// WriteBytes is the size of the keys and values written.
+	WriteBytes uint64
+	// Iterators is the number of iterators opened.
+	Iterators uint64
+}
+
+type storeAccessContextKey struct{}
+
+// StoreAccessFromContext returns the store access of the transaction so far,
+// and false if the store access isn't recorded: store access recording isn't
+// enabled, or the transaction is checked rather than delivered or simulated.
+func StoreAccessFromContext(ctx sdk.Context) (StoreAccess, bool) {
+	access := storeAccessFromContext(ctx)
+	if access == nil {
+		return StoreAccess{}, false
+	}
+	return *access, true
+}
+
+func storeAccessFromContext(ctx sdk.Context) *StoreAccess {
+	access, _ := ctx.Value(storeAccessContextKey{}).(*StoreAccess)
+	return access
+}
+
+// abciEvent returns the event appended to the result of simulated
+// transactions.
+func (a StoreAccess) abciEvent() abci.Event {
+	return abci.Event{
+		Type: EventTypeStoreAccess,
+		Attributes: []abci.EventAttribute{
+			{Key: AttributeKeyReads, Value: strconv.FormatUint(a.Reads, 10)},
+			{Key: AttributeKeyReadBytes, Value: strconv.FormatUint(a.ReadBytes, 10)},
+			{Key: AttributeKeyWrites, Value: strconv.FormatUint(a.Writes, 10)},
+			{Key: AttributeKeyWriteBytes, Value: strconv.FormatUint(a.WriteBytes, 10)},
+			{Key: AttributeKeyIterators, Value: strconv.FormatUint(a.Iterators, 10)},
+		},
+	}
+}
+
+// cacheMultiStore allows recordingMultiStore to embed a CacheMultiStore while
+// overriding its CacheMultiStore method.
+type cacheMultiStore = storetypes.CacheMultiStore
+
+// recordingMultiStore is a multi-store branch whose KV stores record their
+// accesses. The branches of the store record to the same StoreAccess, and
+// their writes are counted once, when they are set rather than when the
+// branch is written.
+type recordingMultiStore struct {
+	cacheMultiStore
+	access *StoreAccess
+}
+
+func newRecordingMultiStore(ms storetypes.CacheMultiStore, access *StoreAccess) recordingMultiStore {
+	return recordingMultiStore{cacheMultiStore: ms, access: access}
+}
+
+func (rs recordingMultiStore) CacheMultiStore() storetypes.CacheMultiStore {
+	return newRecordingMultiStore(rs.cacheMultiStore.CacheMultiStore(), rs.access)
+}
+
+func (rs recordingMultiStore) GetStore(key storetypes.StoreKey) storetypes.Store {
+	store := rs.cacheMultiStore.GetStore(key)
+	if kv, ok := store.(storetypes.KVStore); ok {
+		return recordingKVStore{KVStore: kv, access: rs.access}
+	}
+	return store
+}
+
+func (rs recordingMultiStore) GetKVStore(key storetypes.StoreKey) storetypes.KVStore {
+	return recordingKVStore{KVStore: rs.cacheMultiStore.GetKVStore(key), access: rs.access}
+}
+
+// recordingKVStore is a KV store recording its accesses.
+type recordingKVStore struct {
+	storetypes.KVStore
+	access *StoreAccess
+}
+
+func (s recordingKVStore) Get(key []byte) []byte {
+	value := s.KVStore.Get(key)
+	s.access.Reads++
+	s.access.ReadBytes += uint64(len(key) + len(value))
+	return value
+}
+
+func (s recordingKVStore) Has(key []byte) bool {
+	s.access.Reads++
+	s.access.ReadBytes += uint64(len(key))
+	return s.KVStore.Has(key)
+}
+
+func (s recordingKVStore) Set(key, value []byte) {
+	s.access.Writes++
+	s.access.WriteBytes += uint64(len(key) + len(value))
+	s.KVStore.Set(key, value)
+}
+
+func (s recordingKVStore) Delete(key []byte) {
+	s.access.Writes++
+	s.access.WriteBytes += uint64(len(key))
+	s.KVStore.Delete(key)
+}
+
+func (s recordingKVStore) Iterator(start, end []byte) storetypes.Iterator {
+	s.access.Iterators++
+	return newRecordingIterator(s.KVStore.Iterator(start, end), s.access)
+}
+
+func (s recordingKVStore) ReverseIterator(start, end []byte) storetypes.Iterator {
+	s.access.Iterators++
+	return newRecordingIterator(s.KVStore.ReverseIterator(start, end), s.access)
+}
+
+// recordingIterator is an iterator recording a read of each entry it
+// reaches, like the gas KV store charges for them.
+type recordingIterator struct {
+	storetypes.Iterator
+	access *StoreAccess
+}
+
+func newRecordingIterator(parent storetypes.Iterator, access *StoreAccess) storetypes.Iterator {
+	it := recordingIterator{Iterator: parent, access: access}
+	it.recordRead()
+	return it
+}
+
+func (it recordingIterator) Next() {
+	it.Iterator.Next()
+	it.recordRead()
+}
+
+func (it recordingIterator) recordRead() {
+	if it.Valid() {
+		it.access.Reads++
+		it.access.ReadBytes += uint64(len(it.Key()) + len(it.Value()))
+	}
+}
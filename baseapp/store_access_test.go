@@ -0,0 +1,70 @@
+package baseapp
+
+import (
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestStoreAccessRecording(t *testing.T) {
+	key := sdk.NewKVStoreKey("store_access")
+	cms := rootmulti.NewStore(dbm.NewMemDB(), log.NewNopLogger())
+	cms.MountStoreWithDB(key, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, cms.LoadLatestVersion())
+
+	app := &BaseApp{}
+	ctx := sdk.NewContext(cms, tmproto.Header{}, false, log.NewNopLogger())
+	_, ok := StoreAccessFromContext(ctx)
+	require.False(t, ok)
+
+	// without recording, the tx context store is a plain branch
+	txCtx, _ := app.cacheTxContext(ctx, []byte("tx"))
+	_, ok = txCtx.MultiStore().(recordingMultiStore)
+	require.False(t, ok)
+
+	access := &StoreAccess{}
+	ctx = ctx.WithValue(storeAccessContextKey{}, access)
+	txCtx, msCache := app.cacheTxContext(ctx, []byte("tx"))
+
+	store := txCtx.KVStore(key)
+	store.Set([]byte("key"), []byte("value"))
+	require.Equal(t, []byte("value"), store.Get([]byte("key")))
+	require.True(t, store.Has([]byte("key")))
+	store.Delete([]byte("other"))
+	it := store.Iterator(nil, nil)
+	for ; it.Valid(); it.Next() {
+	}
+	it.Close()
+
+	// the entries iterated over are counted as reads
+	require.Equal(t, uint64(3), access.Reads)
+
+	// the branches of the tx context record to the same StoreAccess
+	branchCtx, write := txCtx.CacheContext()
+	branchCtx.KVStore(key).Set([]byte("branch"), []byte("v"))
+	write()
+	msCache.Write()
+
+	got, ok := StoreAccessFromContext(txCtx)
+	require.True(t, ok)
+	require.Equal(t, StoreAccess{
+		Reads:      3,
+		ReadBytes:  uint64(len("keyvalue") + len("key") + len("keyvalue")),
+		Writes:     3,
+		WriteBytes: uint64(len("keyvalue") + len("other") + len("branchv")),
+		Iterators:  1,
+	}, got)
+	require.Equal(t, []byte("v"), ctx.MultiStore().GetKVStore(key).Get([]byte("branch")))
+
+	event := got.abciEvent()
+	require.Equal(t, EventTypeStoreAccess, event.Type)
+	require.Equal(t, AttributeKeyWrites, event.Attributes[2].Key)
+	require.Equal(t, "3", event.Attributes[2].Value)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	tmproto "github.com/baron-chain/cometbft-bc/proto/tendermint/types"
+	storetypes "github.com/baron-chain/cosmos-bc-47/store/types"
 	sdk "github.com/baron-chain/cosmos-bc-47/types"
 	sdkerrors "github.com/baron-chain/cosmos-bc-47/types/errors"
 )
@@ -20,6 +21,7 @@ func (app *BaseApp) SimCheck(txEncoder sdk.TxEncoder, tx sdk.Tx) (gasInfo sdk.Ga
 }
 
 // Simulate executes a transaction in simulation mode to estimate gas usage.
+// The tx runs once, see EstimateGas for an estimate with a confidence margin.
 func (app *BaseApp) Simulate(txBytes []byte) (gasInfo sdk.GasInfo, result *sdk.Result, err error) {
 	return app.runTxSimulation(runTxModeSimulate, txBytes)
 }
@@ -34,19 +36,38 @@ func (app *BaseApp) SimDeliver(txEncoder sdk.TxEncoder, tx sdk.Tx) (gasInfo sdk.
 	return app.runTxSimulation(runTxModeDeliver, txBytes)
 }
 
+// ContextOption configures a context created by NewContext or
+// NewUncachedContext.
+type ContextOption func(sdk.Context) sdk.Context
+
+// QueryGasLimit gives the context a gas meter of its own, limited to limit, as
+// queries get, so that they are not bound by the block gas. Zero sets an
+// infinite gas meter.
+func QueryGasLimit(limit uint64) ContextOption {
+	return func(ctx sdk.Context) sdk.Context {
+		if limit == 0 {
+			return ctx.WithGasMeter(storetypes.NewInfiniteGasMeter())
+		}
+		return ctx.WithGasMeter(storetypes.NewGasMeter(limit))
+	}
+}
+
 // NewContext creates a new context for transaction processing.
-func (app *BaseApp) NewContext(isCheckTx bool, header tmproto.Header) sdk.Context {
+func (app *BaseApp) NewContext(isCheckTx bool, header tmproto.Header, opts ...ContextOption) sdk.Context {
+	var ctx sdk.Context
 	if isCheckTx {
-		return sdk.NewContext(app.checkState.ms, header, true, app.logger).
+		ctx = sdk.NewContext(app.checkState.ms, header, true, app.logger).
 			WithMinGasPrices(app.minGasPrices)
+	} else {
+		ctx = sdk.NewContext(app.deliverState.ms, header, false, app.logger)
 	}
-	
-	return sdk.NewContext(app.deliverState.ms, header, false, app.logger)
+
+	return applyContextOptions(ctx, opts)
 }
 
 // NewUncachedContext creates a new context without caching.
-func (app *BaseApp) NewUncachedContext(isCheckTx bool, header tmproto.Header) sdk.Context {
-	return sdk.NewContext(app.cms, header, isCheckTx, app.logger)
+func (app *BaseApp) NewUncachedContext(isCheckTx bool, header tmproto.Header, opts ...ContextOption) sdk.Context {
+	return applyContextOptions(sdk.NewContext(app.cms, header, isCheckTx, app.logger), opts)
 }
 
 // GetContextForDeliverTx returns the context for transaction delivery.
@@ -56,6 +77,13 @@ func (app *BaseApp) GetContextForDeliverTx(txBytes []byte) sdk.Context {
 
 // Helper functions
 
+func applyContextOptions(ctx sdk.Context, opts []ContextOption) sdk.Context {
+	for _, opt := range opts {
+		ctx = opt(ctx)
+	}
+	return ctx
+}
+
 // encodeTx encodes a transaction using the provided encoder.
 func encodeTx(txEncoder sdk.TxEncoder, tx sdk.Tx) ([]byte, error) {
 	txBytes, err := txEncoder(tx)
@@ -68,5 +96,6 @@ func encodeTx(txEncoder sdk.TxEncoder, tx sdk.Tx) ([]byte, error) {
 // runTxSimulation executes a transaction in the specified mode and returns relevant info.
 func (app *BaseApp) runTxSimulation(mode runTxMode, txBytes []byte) (sdk.GasInfo, *sdk.Result, error) {
 	gasInfo, result, _, _, err := app.runTx(mode, txBytes)
+	app.recordSimFixture(mode, txBytes, gasInfo, result, err)
 	return gasInfo, result, err
 }
@@ -0,0 +1,37 @@
+package baseapp
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// ChainTxDecoders returns a TxDecoder trying the decoders in order, e.g. the
+// protobuf decoder then the amino JSON one, and returning the transaction of
+// the first which decodes the bytes. It lets an app accept transactions of
+// several encodings, e.g. while clients migrate from one to another. If none
+// decodes the bytes, the error of the first decoder is returned, the others
+// being fallbacks.
+func ChainTxDecoders(decoders ...sdk.TxDecoder) sdk.TxDecoder {
+	switch len(decoders) {
+	case 0:
+		return func([]byte) (sdk.Tx, error) {
+			return nil, sdkerrors.ErrTxDecode.Wrap("no tx decoder set")
+		}
+	case 1:
+		return decoders[0]
+	}
+
+	return func(txBytes []byte) (sdk.Tx, error) {
+		var firstErr error
+		for _, decoder := range decoders {
+			tx, err := decoder(txBytes)
+			if err == nil {
+				return tx, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		return nil, firstErr
+	}
+}
@@ -0,0 +1,40 @@
+package baseapp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func TestChainTxDecoders(t *testing.T) {
+	errProto := sdkerrors.ErrTxDecode.Wrap("not protobuf")
+	decoderOf := func(prefix byte, err error) sdk.TxDecoder {
+		return func(txBytes []byte) (sdk.Tx, error) {
+			if len(txBytes) == 0 || txBytes[0] != prefix {
+				return nil, err
+			}
+			return rateLimitTestTx{msgs: []sdk.Msg{testdata.NewTestMsg(sdk.AccAddress(txBytes))}}, nil
+		}
+	}
+	protoDecoder := decoderOf('p', errProto)
+	jsonDecoder := decoderOf('{', errors.New("not json"))
+
+	decoder := ChainTxDecoders(protoDecoder, jsonDecoder)
+	for _, txBytes := range [][]byte{[]byte("proto"), []byte("{}")} {
+		tx, err := decoder(txBytes)
+		require.NoError(t, err)
+		require.Equal(t, sdk.AccAddress(txBytes), tx.GetMsgs()[0].GetSigners()[0])
+	}
+
+	// the error of the first decoder is returned
+	_, err := decoder([]byte("amino"))
+	require.ErrorIs(t, err, errProto)
+
+	_, err = ChainTxDecoders()([]byte("proto"))
+	require.ErrorIs(t, err, sdkerrors.ErrTxDecode)
+}
@@ -0,0 +1,263 @@
+package baseapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/sign/dilithium"
+	"github.com/cloudflare/circl/sign/dilithium/mode3"
+	abci "github.com/cometbft/cometbft/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/crypto/aggsig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// voteAttestationDomain prefixes the signed attestations, so that their
+// signatures can't be replayed as the signatures of other messages.
+const voteAttestationDomain = "baron-chain vote extension attestation:"
+
+// attestationCacheSize is the number of attestation signatures an
+// AttestationHandler remembers once verified, so that the attestations
+// verified in VerifyVoteExtension aren't verified again by the proposer of
+// the next height.
+const attestationCacheSize = 10_000
+
+// VoteAttestation is the vote extension of the AttestationHandler: a payload,
+// e.g. a price feed, signed with the Dilithium3 attestation key of a validator
+// for a chain and height. The vote extension is itself signed by the consensus
+// key of the validator, the Dilithium3 signature keeps the attestation
+// verifiable once classical signatures are broken.
+type VoteAttestation struct {
+	ChainID string `json:"chain_id"`
+	Height  int64  `json:"height"`
+	Payload []byte `json:"payload"`
+	// Signature is the Dilithium3 signature of the attestation, without it.
+	Signature []byte `json:"signature"`
+}
+
+// signBytes returns the bytes signed by the attestation.
+func (a VoteAttestation) signBytes() ([]byte, error) {
+	a.Signature = nil
+	bz, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(voteAttestationDomain), bz...), nil
+}
+
+// ValidatorAttestation is the verified attestation of a validator, in the
+// votes of the last commit.
+type ValidatorAttestation struct {
+	Validator   sdk.ConsAddress
+	Power       int64
+	Attestation VoteAttestation
+}
+
+// AttestationProvider returns the payload the node attests at a height, e.g.
+// the prices it observed. A nil payload extends the vote with no attestation.
+type AttestationProvider func(ctx sdk.Context, height int64) ([]byte, error)
+
+// AttestationValidator checks the payload attested by a validator, e.g. that
+// the prices are well formed. It is optional.
+type AttestationValidator func(ctx sdk.Context, validator sdk.ConsAddress, payload []byte) error
+
+// AttestationKeys returns the packed Dilithium3 attestation public keys of the
+// validators, e.g. registered on chain by a module.
+type AttestationKeys interface {
+	AttestationPubKey(ctx sdk.Context, validator sdk.ConsAddress) ([]byte, error)
+}
+
+// AttestationHandler extends the precommit votes of the node with the
+// Dilithium3-signed attestations of an AttestationProvider, and verifies the
+// ones of the other validators. Its handlers are set with
+// SetExtendVoteHandler and SetVerifyVoteExtensionHandler, and the proposers
+// collect the verified attestations of the last commit with
+// VerifiedAttestations in their PrepareProposal handler. The signatures are
+// verified with aggsig, and remembered once valid.
+//
+// Vote extensions require ABCI 2.0, i.e. CometBFT v0.38: with CometBFT v0.37
+// the handlers are never called, and the proposers never get attestations.
+type AttestationHandler struct {
+	privKey  *mode3.PrivateKey
+	keys     AttestationKeys
+	provide  AttestationProvider
+	validate AttestationValidator
+	cache    *aggsig.Cache
+}
+
+// NewAttestationHandler returns an AttestationHandler signing the payloads of
+// provide with the packed Dilithium3 private key privKey, e.g. derived with
+// hd.DilithiumKeyFromSeed. Nodes which don't attest, e.g. because they aren't
+// validators, pass a nil privKey and only verify attestations.
+func NewAttestationHandler(privKey []byte, keys AttestationKeys, provide AttestationProvider, validate AttestationValidator) (*AttestationHandler, error) {
+	if keys == nil {
+		return nil, errors.New("attestation keys must be set")
+	}
+	h := &AttestationHandler{
+		keys:     keys,
+		provide:  provide,
+		validate: validate,
+		cache:    aggsig.NewCache(attestationCacheSize),
+	}
+	if privKey != nil {
+		if provide == nil {
+			return nil, errors.New("attestation provider must be set to attest")
+		}
+		h.privKey = new(mode3.PrivateKey)
+		if err := h.privKey.UnmarshalBinary(privKey); err != nil {
+			return nil, fmt.Errorf("invalid attestation private key: %w", err)
+		}
+	}
+	return h, nil
+}
+
+// ExtendVoteHandler returns the handler extending the votes of the node with
+// its attestations.
+func (h *AttestationHandler) ExtendVoteHandler() sdk.ExtendVoteHandler {
+	return func(ctx sdk.Context, req sdk.RequestExtendVote) (sdk.ResponseExtendVote, error) {
+		if h.privKey == nil {
+			return sdk.ResponseExtendVote{VoteExtension: []byte{}}, nil
+		}
+
+		payload, err := h.provide(ctx, req.Height)
+		if err != nil {
+			return sdk.ResponseExtendVote{}, fmt.Errorf("failed to get attestation payload: %w", err)
+		}
+		if payload == nil {
+			return sdk.ResponseExtendVote{VoteExtension: []byte{}}, nil
+		}
+
+		a := VoteAttestation{ChainID: ctx.ChainID(), Height: req.Height, Payload: payload}
+		signBytes, err := a.signBytes()
+		if err != nil {
+			return sdk.ResponseExtendVote{}, err
+		}
+		a.Signature = make([]byte, mode3.SignatureSize)
+		mode3.SignTo(h.privKey, signBytes, a.Signature)
+
+		bz, err := json.Marshal(a)
+		if err != nil {
+			return sdk.ResponseExtendVote{}, err
+		}
+		return sdk.ResponseExtendVote{VoteExtension: bz}, nil
+	}
+}
+
+// VerifyVoteExtensionHandler returns the handler verifying the attestations
+// of the validators. Votes without extension are accepted, as validators may
+// have nothing to attest.
+func (h *AttestationHandler) VerifyVoteExtensionHandler() sdk.VerifyVoteExtensionHandler {
+	return func(ctx sdk.Context, req sdk.RequestVerifyVoteExtension) (sdk.ResponseVerifyVoteExtension, error) {
+		if len(req.VoteExtension) == 0 {
+			return sdk.ResponseVerifyVoteExtension{Status: sdk.VerifyVoteExtensionAccept}, nil
+		}
+		_, errs := h.verify(ctx, req.Height, []attestationVote{{validator: req.ValidatorAddress, ext: req.VoteExtension}})
+		if errs[0] != nil {
+			return sdk.ResponseVerifyVoteExtension{}, errs[0]
+		}
+		return sdk.ResponseVerifyVoteExtension{Status: sdk.VerifyVoteExtensionAccept}, nil
+	}
+}
+
+// VerifiedAttestations returns the attestations of the votes of the last
+// commit which verify, in the order of the votes. Their signatures are
+// verified in one batch, spread over the CPUs. The votes of the last commit
+// were extended at the height before the one of ctx. Invalid attestations are
+// logged and skipped, since a proposer can't trust the extensions it received.
+func (h *AttestationHandler) VerifiedAttestations(ctx sdk.Context, commit abci.ExtendedCommitInfo) []ValidatorAttestation {
+	var (
+		votes []attestationVote
+		infos []abci.ExtendedVoteInfo
+	)
+	for _, vote := range commit.Votes {
+		if !vote.SignedLastBlock || len(vote.VoteExtension) == 0 {
+			continue
+		}
+		votes = append(votes, attestationVote{validator: vote.Validator.Address, ext: vote.VoteExtension})
+		infos = append(infos, vote)
+	}
+
+	verified, errs := h.verify(ctx, ctx.BlockHeight()-1, votes)
+	var attestations []ValidatorAttestation
+	for i, vote := range infos {
+		if errs[i] != nil {
+			ctx.Logger().Error("skipping invalid vote attestation", "validator", fmt.Sprintf("%X", vote.Validator.Address), "err", errs[i])
+			continue
+		}
+		attestations = append(attestations, ValidatorAttestation{
+			Validator:   vote.Validator.Address,
+			Power:       vote.Validator.Power,
+			Attestation: verified[i],
+		})
+	}
+	return attestations
+}
+
+// attestationVote is the vote extension of a validator.
+type attestationVote struct {
+	validator []byte
+	ext       []byte
+}
+
+// verify decodes the attestations of votes at height and verifies their
+// signatures in one batch. It returns the attestation of each vote, or why it
+// is invalid.
+func (h *AttestationHandler) verify(ctx sdk.Context, height int64, votes []attestationVote) ([]VoteAttestation, []error) {
+	attestations := make([]VoteAttestation, len(votes))
+	errs := make([]error, len(votes))
+
+	var (
+		entries []aggsig.Entry
+		idxs    []int
+	)
+	for i, vote := range votes {
+		var entry aggsig.Entry
+		attestations[i], entry, errs[i] = h.decode(ctx, vote.validator, height, vote.ext)
+		if errs[i] == nil {
+			entries = append(entries, entry)
+			idxs = append(idxs, i)
+		}
+	}
+
+	valid := aggsig.Verify(entries, 0, h.cache)
+	for j, i := range idxs {
+		validator := votes[i].validator
+		if !valid[j] {
+			errs[i] = fmt.Errorf("invalid vote attestation signature of validator %X", validator)
+			continue
+		}
+		if h.validate != nil {
+			if err := h.validate(ctx, validator, attestations[i].Payload); err != nil {
+				errs[i] = fmt.Errorf("invalid vote attestation payload of validator %X: %w", validator, err)
+			}
+		}
+	}
+	return attestations, errs
+}
+
+// decode decodes the attestation of validator at height, and returns the
+// signature to verify.
+func (h *AttestationHandler) decode(ctx sdk.Context, validator []byte, height int64, ext []byte) (VoteAttestation, aggsig.Entry, error) {
+	var a VoteAttestation
+	if err := json.Unmarshal(ext, &a); err != nil {
+		return VoteAttestation{}, aggsig.Entry{}, fmt.Errorf("invalid vote attestation: %w", err)
+	}
+	if a.ChainID != ctx.ChainID() || a.Height != height {
+		return VoteAttestation{}, aggsig.Entry{}, fmt.Errorf("vote attestation is for chain %s at height %d, expected chain %s at height %d", a.ChainID, a.Height, ctx.ChainID(), height)
+	}
+
+	bz, err := h.keys.AttestationPubKey(ctx, validator)
+	if err != nil {
+		return VoteAttestation{}, aggsig.Entry{}, fmt.Errorf("failed to get the attestation public key of validator %X: %w", validator, err)
+	}
+	pubKey, err := aggsig.NewDilithiumPubKey(dilithium.Mode3.Name(), bz)
+	if err != nil {
+		return VoteAttestation{}, aggsig.Entry{}, fmt.Errorf("invalid attestation public key of validator %X: %w", validator, err)
+	}
+	signBytes, err := a.signBytes()
+	if err != nil {
+		return VoteAttestation{}, aggsig.Entry{}, err
+	}
+	return a, aggsig.Entry{PubKey: pubKey, Msg: signBytes, Sig: a.Signature}, nil
+}
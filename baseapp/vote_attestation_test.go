@@ -0,0 +1,138 @@
+package baseapp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cloudflare/circl/sign/dilithium/mode3"
+	dbm "github.com/cometbft/cometbft-db"
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type testAttestationKeys map[string][]byte
+
+func (k testAttestationKeys) AttestationPubKey(_ sdk.Context, validator sdk.ConsAddress) ([]byte, error) {
+	pubKey, ok := k[string(validator)]
+	if !ok {
+		return nil, fmt.Errorf("validator %X has no attestation key", validator)
+	}
+	return pubKey, nil
+}
+
+func newTestAttestationKey(seed byte) (pub, priv []byte) {
+	var s [mode3.SeedSize]byte
+	s[0] = seed
+	pubKey, privKey := mode3.NewKeyFromSeed(&s)
+	return pubKey.Bytes(), privKey.Bytes()
+}
+
+func TestAttestationHandler(t *testing.T) {
+	val1, val2 := []byte("validator-1"), []byte("validator-2")
+	pub1, priv1 := newTestAttestationKey(1)
+	pub2, priv2 := newTestAttestationKey(2)
+	keys := testAttestationKeys{string(val1): pub1, string(val2): pub2}
+
+	provide := func(_ sdk.Context, height int64) ([]byte, error) {
+		return []byte(fmt.Sprintf("BTC/USD=%d", 60000+height)), nil
+	}
+	validate := func(_ sdk.Context, _ sdk.ConsAddress, payload []byte) error {
+		if !bytes.HasPrefix(payload, []byte("BTC/USD=")) {
+			return errors.New("unknown price feed")
+		}
+		return nil
+	}
+
+	h1, err := NewAttestationHandler(priv1, keys, provide, validate)
+	require.NoError(t, err)
+	h2, err := NewAttestationHandler(priv2, keys, func(sdk.Context, int64) ([]byte, error) { return []byte("ETH"), nil }, validate)
+	require.NoError(t, err)
+	observer, err := NewAttestationHandler(nil, keys, nil, validate)
+	require.NoError(t, err)
+
+	ctx := sdk.Context{}.WithChainID("baron-1").WithBlockHeight(10).WithLogger(log.NewNopLogger())
+	ext1, err := h1.ExtendVoteHandler()(ctx, sdk.RequestExtendVote{Height: 10})
+	require.NoError(t, err)
+	ext2, err := h2.ExtendVoteHandler()(ctx, sdk.RequestExtendVote{Height: 10})
+	require.NoError(t, err)
+	none, err := observer.ExtendVoteHandler()(ctx, sdk.RequestExtendVote{Height: 10})
+	require.NoError(t, err)
+	require.Empty(t, none.VoteExtension)
+
+	verify := observer.VerifyVoteExtensionHandler()
+	res, err := verify(ctx, sdk.RequestVerifyVoteExtension{ValidatorAddress: val1, Height: 10, VoteExtension: ext1.VoteExtension})
+	require.NoError(t, err)
+	require.Equal(t, sdk.VerifyVoteExtensionAccept, res.Status)
+	res, err = verify(ctx, sdk.RequestVerifyVoteExtension{ValidatorAddress: val2, Height: 10})
+	require.NoError(t, err)
+	require.Equal(t, sdk.VerifyVoteExtensionAccept, res.Status)
+
+	// the attestation of a validator isn't valid for another one, or another height
+	_, err = verify(ctx, sdk.RequestVerifyVoteExtension{ValidatorAddress: val2, Height: 10, VoteExtension: ext1.VoteExtension})
+	require.ErrorContains(t, err, "invalid vote attestation signature")
+	_, err = verify(ctx, sdk.RequestVerifyVoteExtension{ValidatorAddress: val1, Height: 11, VoteExtension: ext1.VoteExtension})
+	require.ErrorContains(t, err, "expected chain baron-1 at height 11")
+	_, err = verify(ctx, sdk.RequestVerifyVoteExtension{ValidatorAddress: val2, Height: 10, VoteExtension: ext2.VoteExtension})
+	require.ErrorContains(t, err, "unknown price feed")
+
+	// the proposer of the next height collects the valid attestations
+	commit := abci.ExtendedCommitInfo{Votes: []abci.ExtendedVoteInfo{
+		{Validator: abci.Validator{Address: val1, Power: 10}, SignedLastBlock: true, VoteExtension: ext1.VoteExtension},
+		{Validator: abci.Validator{Address: val2, Power: 5}, SignedLastBlock: true, VoteExtension: ext2.VoteExtension},
+		{Validator: abci.Validator{Address: []byte("validator-3"), Power: 1}},
+	}}
+	attestations := observer.VerifiedAttestations(ctx.WithBlockHeight(11), commit)
+	require.Len(t, attestations, 1)
+	require.Equal(t, sdk.ConsAddress(val1), attestations[0].Validator)
+	require.Equal(t, int64(10), attestations[0].Power)
+	require.Equal(t, []byte("BTC/USD=60010"), attestations[0].Attestation.Payload)
+
+	// the attestations are verified in one batch, which finds the forged ones,
+	// and the valid signatures are remembered
+	forged := abci.ExtendedVoteInfo{Validator: abci.Validator{Address: val2, Power: 5}, SignedLastBlock: true, VoteExtension: ext1.VoteExtension}
+	h3, err := NewAttestationHandler(nil, keys, nil, nil)
+	require.NoError(t, err)
+	commit.Votes = append(commit.Votes, forged)
+	attestations = h3.VerifiedAttestations(ctx.WithBlockHeight(11), commit)
+	require.Len(t, attestations, 2)
+	require.Equal(t, sdk.ConsAddress(val1), attestations[0].Validator)
+	require.Equal(t, sdk.ConsAddress(val2), attestations[1].Validator)
+	require.Equal(t, []byte("ETH"), attestations[1].Attestation.Payload)
+	require.Equal(t, 2, h3.cache.Len())
+
+	_, err = NewAttestationHandler([]byte("short"), keys, provide, nil)
+	require.ErrorContains(t, err, "invalid attestation private key")
+	_, err = NewAttestationHandler(priv1, keys, nil, nil)
+	require.ErrorContains(t, err, "provider must be set")
+}
+
+func TestVoteExtensionsEnabled(t *testing.T) {
+	defer func(v string) { abciVersion = v }(abciVersion)
+
+	var extended int
+	app := NewBaseApp("test", log.NewNopLogger(), dbm.NewMemDB(), nil)
+	app.SetExtendVoteHandler(func(_ sdk.Context, _ sdk.RequestExtendVote) (sdk.ResponseExtendVote, error) {
+		extended++
+		return sdk.ResponseExtendVote{VoteExtension: []byte("ext")}, nil
+	})
+
+	// ABCI 1.0 has no vote extensions, so that the handlers never run
+	abciVersion = "1.0.0"
+	require.False(t, VoteExtensionsEnabled())
+	_, err := app.ExtendVote(sdk.RequestExtendVote{Height: 1})
+	require.ErrorContains(t, err, "vote extensions aren't supported by ABCI 1.0.0")
+	_, err = app.VerifyVoteExtension(sdk.RequestVerifyVoteExtension{Height: 1})
+	require.Error(t, err)
+	require.NoError(t, app.SimulateVoteExtensions(1, nil, []byte("val")))
+	require.Zero(t, extended)
+
+	abciVersion = "2.0.0"
+	require.True(t, VoteExtensionsEnabled())
+	require.NoError(t, app.SimulateVoteExtensions(1, nil, []byte("val")))
+	require.Equal(t, 1, extended)
+}
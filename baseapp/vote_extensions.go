@@ -0,0 +1,164 @@
+package baseapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/cometbft/cometbft/version"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// abciVersion is the version of the ABCI protocol of the consensus engine the
+// app is built with.
+var abciVersion = version.ABCISemVer
+
+// VoteExtensionsEnabled returns whether the ABCI protocol of the consensus
+// engine the app is built with has vote extensions, i.e. whether it is ABCI
+// 2.0 or later. CometBFT v0.37 implements ABCI 1.0: its nodes never extend
+// votes nor verify vote extensions, so that ExtendVote and VerifyVoteExtension
+// fail, and simulations don't run the vote extension handlers.
+func VoteExtensionsEnabled() bool {
+	major, err := strconv.Atoi(strings.SplitN(abciVersion, ".", 2)[0])
+	return err == nil && major >= 2
+}
+
+// NoOpExtendVote returns an ExtendVoteHandler which extends no vote, the
+// default of the BaseApp.
+func NoOpExtendVote() sdk.ExtendVoteHandler {
+	return func(_ sdk.Context, _ sdk.RequestExtendVote) (sdk.ResponseExtendVote, error) {
+		return sdk.ResponseExtendVote{VoteExtension: []byte{}}, nil
+	}
+}
+
+// NoOpVerifyVoteExtensionHandler returns a VerifyVoteExtensionHandler which
+// accepts all vote extensions, the default of the BaseApp.
+func NoOpVerifyVoteExtensionHandler() sdk.VerifyVoteExtensionHandler {
+	return func(_ sdk.Context, _ sdk.RequestVerifyVoteExtension) (sdk.ResponseVerifyVoteExtension, error) {
+		return sdk.ResponseVerifyVoteExtension{Status: sdk.VerifyVoteExtensionAccept}, nil
+	}
+}
+
+// ExtendVote returns the extension of the precommit vote of the node for the
+// block of the request, made by the ExtendVoteHandler of the app. The handler
+// runs on a branch of the last committed state, which is discarded. It fails
+// unless VoteExtensionsEnabled.
+func (app *BaseApp) ExtendVote(req sdk.RequestExtendVote) (resp sdk.ResponseExtendVote, err error) {
+	if !VoteExtensionsEnabled() {
+		return sdk.ResponseExtendVote{}, fmt.Errorf("vote extensions aren't supported by ABCI %s", abciVersion)
+	}
+	if app.extendVote == nil {
+		return sdk.ResponseExtendVote{}, fmt.Errorf("application ExtendVote handler not set")
+	}
+	if req.Height < 1 {
+		return sdk.ResponseExtendVote{}, fmt.Errorf("ExtendVote called with invalid height %d", req.Height)
+	}
+
+	ctx := app.voteExtensionContext(req.Height, req.Hash)
+
+	defer func() {
+		if r := recover(); r != nil {
+			app.logger.Error(
+				"panic recovered in ExtendVote",
+				"height", req.Height,
+				"hash", fmt.Sprintf("%X", req.Hash),
+				"panic", r,
+			)
+			err = fmt.Errorf("recovered application panic in ExtendVote: %v", r)
+		}
+	}()
+
+	resp, err = app.extendVote(ctx, req)
+	if err != nil {
+		app.logger.Error("failed to extend vote", "height", req.Height, "hash", fmt.Sprintf("%X", req.Hash), "err", err)
+		return sdk.ResponseExtendVote{VoteExtension: []byte{}}, nil
+	}
+	return resp, nil
+}
+
+// VerifyVoteExtension verifies the vote extension of a validator with the
+// VerifyVoteExtensionHandler of the app, on a branch of the last committed
+// state. Extensions whose verification fails or panics are rejected. It fails
+// unless VoteExtensionsEnabled.
+func (app *BaseApp) VerifyVoteExtension(req sdk.RequestVerifyVoteExtension) (resp sdk.ResponseVerifyVoteExtension, err error) {
+	if !VoteExtensionsEnabled() {
+		return sdk.ResponseVerifyVoteExtension{}, fmt.Errorf("vote extensions aren't supported by ABCI %s", abciVersion)
+	}
+	if app.verifyVoteExt == nil {
+		return sdk.ResponseVerifyVoteExtension{}, fmt.Errorf("application VerifyVoteExtension handler not set")
+	}
+	if req.Height < 1 {
+		return sdk.ResponseVerifyVoteExtension{}, fmt.Errorf("VerifyVoteExtension called with invalid height %d", req.Height)
+	}
+
+	ctx := app.voteExtensionContext(req.Height, req.Hash)
+
+	defer func() {
+		if r := recover(); r != nil {
+			app.logger.Error(
+				"panic recovered in VerifyVoteExtension",
+				"height", req.Height,
+				"hash", fmt.Sprintf("%X", req.Hash),
+				"validator", fmt.Sprintf("%X", req.ValidatorAddress),
+				"panic", r,
+			)
+			resp, err = sdk.ResponseVerifyVoteExtension{Status: sdk.VerifyVoteExtensionReject}, nil
+		}
+	}()
+
+	resp, err = app.verifyVoteExt(ctx, req)
+	if err != nil {
+		app.logger.Error(
+			"failed to verify vote extension",
+			"height", req.Height,
+			"validator", fmt.Sprintf("%X", req.ValidatorAddress),
+			"err", err,
+		)
+		return sdk.ResponseVerifyVoteExtension{Status: sdk.VerifyVoteExtensionReject}, nil
+	}
+	return resp, nil
+}
+
+// SimulateVoteExtensions extends the vote of validator for the block at
+// height, as CometBFT would, and verifies the extension, so that simulations
+// can call it before committing each block to exercise the extension handlers
+// of the app as the consensus engine does. It does nothing unless
+// VoteExtensionsEnabled, like the consensus engine: the simulations of the SDK
+// don't call it while it is built with CometBFT v0.37.
+func (app *BaseApp) SimulateVoteExtensions(height int64, hash, validator []byte) error {
+	if !VoteExtensionsEnabled() || app.extendVote == nil || app.verifyVoteExt == nil {
+		return nil
+	}
+
+	ext, err := app.ExtendVote(sdk.RequestExtendVote{Hash: hash, Height: height})
+	if err != nil {
+		return err
+	}
+	res, err := app.VerifyVoteExtension(sdk.RequestVerifyVoteExtension{
+		Hash:             hash,
+		ValidatorAddress: validator,
+		Height:           height,
+		VoteExtension:    ext.VoteExtension,
+	})
+	if err != nil {
+		return err
+	}
+	if res.Status != sdk.VerifyVoteExtensionAccept {
+		return fmt.Errorf("vote extension of validator %X at height %d rejected", validator, height)
+	}
+	return nil
+}
+
+// voteExtensionContext returns a context on a branch of the last committed
+// state for the vote extension handlers.
+func (app *BaseApp) voteExtensionContext(height int64, hash []byte) sdk.Context {
+	header := tmproto.Header{ChainID: app.chainID, Height: height}
+	ctx := sdk.NewContext(app.cms.CacheMultiStore(), header, false, app.logger).
+		WithVoteInfos(app.voteInfos).
+		WithBlockHeight(height).
+		WithHeaderHash(hash)
+
+	return ctx.WithConsensusParams(app.GetConsensusParams(ctx))
+}
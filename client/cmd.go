@@ -127,13 +127,32 @@ func ReadPersistentCommandFlags(clientCtx Context, flagSet *pflag.FlagSet) (Cont
 	if clientCtx.Keyring == nil || flagSet.Changed(flags.FlagKeyringBackend) {
 		keyringBackend, _ := flagSet.GetString(flags.FlagKeyringBackend)
 
-		if keyringBackend != "" {
+		// the keyring opened by the persistent pre-run is reused when the
+		// command reads the flags again, rather than opening the backend twice,
+		// e.g. two sessions on a PKCS#11 token
+		reuse := clientCtx.Keyring != nil && clientCtx.flagKeyringDir != "" &&
+			clientCtx.flagKeyringDir == clientCtx.KeyringDir && clientCtx.Keyring.Backend() == keyringBackend
+		if keyringBackend != "" && !reuse {
 			kr, err := NewKeyringFromBackend(clientCtx, keyringBackend)
 			if err != nil {
 				return clientCtx, err
 			}
 
 			clientCtx = clientCtx.WithKeyring(kr)
+			clientCtx.flagKeyringDir = clientCtx.KeyringDir
+		}
+	}
+
+	// only the commands using the keyring, which have the keyring flags, are
+	// guarded, once for each keyring and chain ID
+	if flagSet.Lookup(flags.FlagKeyringBackend) != nil && clientCtx.Keyring != nil {
+		guard := keyringGuardKey{chainID: clientCtx.ChainID, backend: clientCtx.Keyring.Backend(), dir: clientCtx.KeyringDir}
+		if guard != clientCtx.guardedKeyring {
+			override, _ := flagSet.GetBool(flags.FlagIKnowWhatIAmDoing)
+			if err := CheckKeyringBackend(clientCtx, override); err != nil {
+				return clientCtx, err
+			}
+			clientCtx.guardedKeyring = guard
 		}
 	}
 
@@ -3,14 +3,21 @@ package client_test
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/testutil"
+	moduletestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
 )
 
 func TestValidateCmd(t *testing.T) {
@@ -119,3 +126,68 @@ func TestSetCmdClientContextHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestReadPersistentCommandFlagsReusesKeyring(t *testing.T) {
+	cfg := moduletestutil.MakeTestEncodingConfig()
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.AddKeyringFlags(flagSet)
+	flagSet.String(flags.FlagHome, "", "home dir")
+	require.NoError(t, flagSet.Parse([]string{
+		fmt.Sprintf("--%s=%s", flags.FlagKeyringBackend, keyring.BackendMemory),
+		fmt.Sprintf("--%s=%s", flags.FlagHome, t.TempDir()),
+	}))
+
+	clientCtx, err := client.ReadPersistentCommandFlags(client.Context{}.WithCodec(cfg.Codec), flagSet)
+	require.NoError(t, err)
+	_, _, err = clientCtx.Keyring.NewMnemonic("alice", keyring.English, hd.CreateHDPath(118, 0, 0).String(), keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+
+	// the command reading the flags again gets the keyring of the pre-run,
+	// rather than a new one
+	again, err := client.ReadPersistentCommandFlags(clientCtx, flagSet)
+	require.NoError(t, err)
+	_, err = again.Keyring.Key("alice")
+	require.NoError(t, err)
+}
+
+func TestReadPersistentCommandFlagsGuardsKeyring(t *testing.T) {
+	cfg := moduletestutil.MakeTestEncodingConfig()
+	home := t.TempDir()
+
+	// the keys commands get the keyring flags, and the override, from their
+	// parent
+	run := func(args ...string) error {
+		keysCmd := &cobra.Command{Use: "keys"}
+		flags.AddKeyringFlags(keysCmd.PersistentFlags())
+		keysCmd.PersistentFlags().String(flags.FlagHome, home, "home dir")
+		keysCmd.PersistentFlags().String(flags.FlagChainID, "", "chain ID")
+		keysCmd.AddCommand(&cobra.Command{
+			Use: "list",
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				clientCtx, err := client.ReadPersistentCommandFlags(client.Context{}.WithCodec(cfg.Codec).WithViper(""), cmd.Flags())
+				if err != nil {
+					return err
+				}
+				// the command reads the flags again
+				_, err = client.ReadPersistentCommandFlags(clientCtx, cmd.Flags())
+				return err
+			},
+		})
+		testutil.ApplyMockIODiscardOutErr(keysCmd)
+		keysCmd.SetArgs(append([]string{"list", "--keyring-backend", keyring.BackendTest, "--chain-id", "baron-1"}, args...))
+		return keysCmd.Execute()
+	}
+
+	require.ErrorIs(t, run(), client.ErrInsecureKeyringBackend)
+	require.NoError(t, run("--"+flags.FlagIKnowWhatIAmDoing))
+
+	// the override is recorded once, along with the refusal
+	bz, err := os.ReadFile(filepath.Join(home, client.KeyringAuditLogFileName))
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(bz)), "\n"), 2)
+
+	// the tx commands get the override along with the keyring flags
+	txCmd := &cobra.Command{Use: "send"}
+	flags.AddTxFlagsToCmd(txCmd)
+	require.NotNil(t, txCmd.Flags().Lookup(flags.FlagIKnowWhatIAmDoing))
+}
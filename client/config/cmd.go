@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -56,7 +57,29 @@ func runConfigCmd(cmd *cobra.Command, args []string) error {
 			cmd.Println(conf.Node)
 		case flags.FlagBroadcastMode:
 			cmd.Println(conf.BroadcastMode)
+		case KeyCoinType:
+			cmd.Println(conf.KeyCoinType)
+		case KeyAccountRange:
+			cmd.Println(conf.KeyAccountRange)
+		case KeyAlgo:
+			cmd.Println(conf.KeyAlgo)
+		case client.KeyMainnetChainIDPattern:
+			cmd.Println(conf.MainnetChainIDPattern)
 		default:
+			if name, field, ok := parseProfileKey(key); ok {
+				profile, err := conf.Profile(name)
+				if err != nil {
+					return err
+				}
+				switch field {
+				case KeyProfileChainID:
+					cmd.Println(profile.ChainID)
+					return nil
+				case KeyProfileNode:
+					cmd.Println(profile.Node)
+					return nil
+				}
+			}
 			err := errUnknownConfigKey(key)
 			return fmt.Errorf("couldn't get the value for the key: %v, error:  %v", key, err)
 		}
@@ -76,8 +99,28 @@ func runConfigCmd(cmd *cobra.Command, args []string) error {
 			conf.SetNode(value)
 		case flags.FlagBroadcastMode:
 			conf.SetBroadcastMode(value)
+		case KeyCoinType:
+			if err := conf.SetKeyCoinType(value); err != nil {
+				return err
+			}
+		case KeyAccountRange:
+			if err := conf.SetKeyAccountRange(value); err != nil {
+				return err
+			}
+		case KeyAlgo:
+			conf.SetKeyAlgo(value)
+		case client.KeyMainnetChainIDPattern:
+			if err := conf.SetMainnetChainIDPattern(value); err != nil {
+				return err
+			}
 		default:
-			return errUnknownConfigKey(key)
+			name, field, ok := parseProfileKey(key)
+			if !ok {
+				return errUnknownConfigKey(key)
+			}
+			if err := conf.SetProfileField(name, field, value); err != nil {
+				return err
+			}
 		}
 
 		confFile := filepath.Join(configPath, "client.toml")
@@ -95,3 +138,16 @@ func runConfigCmd(cmd *cobra.Command, args []string) error {
 func errUnknownConfigKey(key string) error {
 	return fmt.Errorf("unknown configuration key: %q", key)
 }
+
+// parseProfileKey parses a chain profile key, profiles.<name>.<field>.
+func parseProfileKey(key string) (name, field string, ok bool) {
+	if !strings.HasPrefix(key, KeyProfiles+".") {
+		return "", "", false
+	}
+	rest := strings.TrimPrefix(key, KeyProfiles+".")
+	i := strings.LastIndex(rest, ".")
+	if i <= 0 {
+		return "", "", false
+	}
+	return rest[:i], rest[i+1:], true
+}
@@ -4,8 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
 
 	"github.com/cosmos/cosmos-sdk/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 // Default constants
@@ -15,19 +22,66 @@ const (
 	output         = "text"
 	node           = "tcp://localhost:26657"
 	broadcastMode  = "sync"
+	keyAlgo        = "secp256k1"
+
+	// hardenedIndexLimit is the highest BIP32 account index
+	hardenedIndexLimit = 1<<31 - 1
+)
+
+// Client config keys of the key derivation defaults used by `keys add`.
+const (
+	KeyCoinType     = "key-coin-type"
+	KeyAccountRange = "key-account-range"
+	KeyAlgo         = "key-algo"
+)
+
+// Client config keys of the chain profiles, set as
+// profiles.<name>.chain-id and profiles.<name>.node.
+const (
+	KeyProfiles       = "profiles"
+	KeyProfileChainID = "chain-id"
+	KeyProfileNode    = "node"
 )
 
+var profileNameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
 type ClientConfig struct {
 	ChainID        string `mapstructure:"chain-id" json:"chain-id"`
 	KeyringBackend string `mapstructure:"keyring-backend" json:"keyring-backend"`
 	Output         string `mapstructure:"output" json:"output"`
 	Node           string `mapstructure:"node" json:"node"`
 	BroadcastMode  string `mapstructure:"broadcast-mode" json:"broadcast-mode"`
+
+	KeyCoinType     uint32 `mapstructure:"key-coin-type" json:"key-coin-type"`
+	KeyAccountRange string `mapstructure:"key-account-range" json:"key-account-range"`
+	KeyAlgo         string `mapstructure:"key-algo" json:"key-algo"`
+
+	MainnetChainIDPattern string `mapstructure:"mainnet-chain-id-pattern" json:"mainnet-chain-id-pattern"`
+
+	Profiles map[string]ChainProfile `mapstructure:"profiles" json:"profiles,omitempty"`
+}
+
+// ChainProfile is a named endpoint of the client config, e.g. mainnet, testnet
+// or localnet, selected with --chain-profile. Commands using a profile check
+// its chain ID against the network reported by its node.
+type ChainProfile struct {
+	ChainID string `mapstructure:"chain-id" json:"chain-id"`
+	Node    string `mapstructure:"node" json:"node"`
 }
 
 // defaultClientConfig returns the reference to ClientConfig with default values.
 func defaultClientConfig() *ClientConfig {
-	return &ClientConfig{chainID, keyringBackend, output, node, broadcastMode}
+	return &ClientConfig{
+		ChainID:        chainID,
+		KeyringBackend: keyringBackend,
+		Output:         output,
+		Node:           node,
+		BroadcastMode:  broadcastMode,
+		KeyCoinType:    sdk.GetConfig().GetCoinType(),
+		KeyAlgo:        keyAlgo,
+
+		MainnetChainIDPattern: client.DefaultMainnetChainIDPattern,
+	}
 }
 
 func (c *ClientConfig) SetChainID(chainID string) {
@@ -50,6 +104,116 @@ func (c *ClientConfig) SetBroadcastMode(broadcastMode string) {
 	c.BroadcastMode = broadcastMode
 }
 
+func (c *ClientConfig) SetKeyCoinType(coinType string) error {
+	v, err := strconv.ParseUint(coinType, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid coin type %q: %w", coinType, err)
+	}
+	c.KeyCoinType = uint32(v)
+	return nil
+}
+
+func (c *ClientConfig) SetKeyAccountRange(accountRange string) error {
+	if _, _, err := ParseAccountRange(accountRange); err != nil {
+		return err
+	}
+	c.KeyAccountRange = accountRange
+	return nil
+}
+
+func (c *ClientConfig) SetKeyAlgo(algo string) {
+	c.KeyAlgo = algo
+}
+
+// SetMainnetChainIDPattern sets the regular expression of the mainnet chain
+// IDs, on which the insecure keyring backends are refused, or none to allow
+// them on any chain.
+func (c *ClientConfig) SetMainnetChainIDPattern(pattern string) error {
+	if pattern != client.MainnetChainIDPatternNone {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid mainnet chain ID pattern %q: %w", pattern, err)
+		}
+	}
+	c.MainnetChainIDPattern = pattern
+	return nil
+}
+
+// Profile returns the chain profile with the given name.
+func (c *ClientConfig) Profile(name string) (ChainProfile, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return ChainProfile{}, fmt.Errorf("unknown chain profile %q, available profiles: [%s]", name, strings.Join(names, ", "))
+	}
+	return profile, nil
+}
+
+// SetProfileField sets the chain-id or node of the chain profile with the
+// given name, creating the profile if needed.
+func (c *ClientConfig) SetProfileField(name, key, value string) error {
+	if !profileNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid chain profile name %q, must only contain lowercase letters, digits, - and _", name)
+	}
+
+	profile := c.Profiles[name]
+	switch key {
+	case KeyProfileChainID:
+		profile.ChainID = value
+	case KeyProfileNode:
+		profile.Node = value
+	default:
+		return errUnknownConfigKey(fmt.Sprintf("%s.%s.%s", KeyProfiles, name, key))
+	}
+
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]ChainProfile)
+	}
+	c.Profiles[name] = profile
+	return nil
+}
+
+// ReadChainProfile reads the chain profile with the given name from the
+// client.toml file of the home directory.
+func ReadChainProfile(homeDir, name string) (ChainProfile, error) {
+	conf, err := getClientConfig(filepath.Join(homeDir, "config"), viper.New())
+	if err != nil {
+		return ChainProfile{}, fmt.Errorf("couldn't get client config: %v", err)
+	}
+	return conf.Profile(name)
+}
+
+// ParseAccountRange parses an inclusive "<min>-<max>" range of HD account
+// indexes. A single index is a range of one account, and an empty range
+// allows any account.
+func ParseAccountRange(accountRange string) (first, last uint32, err error) {
+	if accountRange == "" {
+		return 0, hardenedIndexLimit, nil
+	}
+
+	minStr, maxStr, found := strings.Cut(accountRange, "-")
+	if !found {
+		maxStr = minStr
+	}
+
+	minV, err := strconv.ParseUint(strings.TrimSpace(minStr), 10, 31)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid account range %q: %w", accountRange, err)
+	}
+	maxV, err := strconv.ParseUint(strings.TrimSpace(maxStr), 10, 31)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid account range %q: %w", accountRange, err)
+	}
+	if minV > maxV {
+		return 0, 0, fmt.Errorf("invalid account range %q: %d is greater than %d", accountRange, minV, maxV)
+	}
+
+	return uint32(minV), uint32(maxV), nil
+}
+
 // ReadFromClientConfig reads values from client.toml file and updates them in client Context
 func ReadFromClientConfig(ctx client.Context) (client.Context, error) {
 	configPath := filepath.Join(ctx.HomeDir, "config")
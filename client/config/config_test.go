@@ -110,3 +110,85 @@ func TestConfigCmdEnvFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigCmdKeyDefaults(t *testing.T) {
+	clientCtx, cleanup := initClientContext(t, "")
+	defer cleanup()
+
+	for key, value := range map[string]string{
+		config.KeyCoinType:     "60",
+		config.KeyAccountRange: "100-199",
+		config.KeyAlgo:         "dilithium",
+	} {
+		_, err := clitestutil.ExecTestCLICmd(clientCtx, config.Cmd(), []string{key, value})
+		require.NoError(t, err)
+
+		out, err := clitestutil.ExecTestCLICmd(clientCtx, config.Cmd(), []string{key})
+		require.NoError(t, err)
+		require.Equal(t, value+"\n", out.String())
+	}
+
+	_, err := clitestutil.ExecTestCLICmd(clientCtx, config.Cmd(), []string{config.KeyCoinType, "-1"})
+	require.Error(t, err)
+
+	_, err = clitestutil.ExecTestCLICmd(clientCtx, config.Cmd(), []string{config.KeyAccountRange, "199-100"})
+	require.Error(t, err)
+}
+
+func TestParseAccountRange(t *testing.T) {
+	tt := []struct {
+		accountRange string
+		first, last  uint32
+		expErr       bool
+	}{
+		{"", 0, 1<<31 - 1, false},
+		{"5", 5, 5, false},
+		{"100-199", 100, 199, false},
+		{" 1 - 2 ", 1, 2, false},
+		{"199-100", 0, 0, true},
+		{"a-b", 0, 0, true},
+		{"0-2147483648", 0, 0, true},
+	}
+
+	for _, tc := range tt {
+		first, last, err := config.ParseAccountRange(tc.accountRange)
+		if tc.expErr {
+			require.Error(t, err, tc.accountRange)
+			continue
+		}
+		require.NoError(t, err, tc.accountRange)
+		require.Equal(t, tc.first, first)
+		require.Equal(t, tc.last, last)
+	}
+}
+
+func TestConfigCmdProfiles(t *testing.T) {
+	clientCtx, cleanup := initClientContext(t, "")
+	defer cleanup()
+
+	for _, args := range [][]string{
+		{"profiles.testnet.chain-id", "baron-testnet-1"},
+		{"profiles.testnet.node", testNode1},
+		// profiles are kept when other keys are set
+		{flags.FlagNode, testNode2},
+	} {
+		_, err := clitestutil.ExecTestCLICmd(clientCtx, config.Cmd(), args)
+		require.NoError(t, err)
+	}
+
+	out, err := clitestutil.ExecTestCLICmd(clientCtx, config.Cmd(), []string{"profiles.testnet.node"})
+	require.NoError(t, err)
+	require.Equal(t, testNode1+"\n", out.String())
+
+	profile, err := config.ReadChainProfile(clientCtx.HomeDir, "testnet")
+	require.NoError(t, err)
+	require.Equal(t, config.ChainProfile{ChainID: "baron-testnet-1", Node: testNode1}, profile)
+
+	_, err = config.ReadChainProfile(clientCtx.HomeDir, "mainnet")
+	require.ErrorContains(t, err, "available profiles: [testnet]")
+
+	_, err = clitestutil.ExecTestCLICmd(clientCtx, config.Cmd(), []string{"profiles.testnet.grpc", "x"})
+	require.Error(t, err)
+	_, err = clitestutil.ExecTestCLICmd(clientCtx, config.Cmd(), []string{"profiles.Mainnet.node", testNode1})
+	require.Error(t, err)
+}
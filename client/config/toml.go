@@ -17,7 +17,7 @@ const defaultConfigTemplate = `# This is a TOML config file.
 
 # The network chain ID
 chain-id = "{{ .ChainID }}"
-# The keyring's backend, where the keys are stored (os|file|kwallet|pass|test|memory)
+# The keyring's backend, where the keys are stored (os|file|kwallet|pass|test|memory|command)
 keyring-backend = "{{ .KeyringBackend }}"
 # CLI output format (text|json)
 output = "{{ .Output }}"
@@ -25,6 +25,42 @@ output = "{{ .Output }}"
 node = "{{ .Node }}"
 # Transaction broadcasting mode (sync|async)
 broadcast-mode = "{{ .BroadcastMode }}"
+
+###############################################################################
+###                          Key Derivation Defaults                        ###
+###############################################################################
+
+# Defaults used by 'keys add' when no --hd-path is given. Flags take precedence.
+# BIP44 coin type of derived keys
+key-coin-type = {{ .KeyCoinType }}
+# Allowed BIP44 account indexes, as <min>-<max>; new keys use <min> unless --account is set
+key-account-range = "{{ .KeyAccountRange }}"
+# Key signing algorithm
+key-algo = "{{ .KeyAlgo }}"
+
+###############################################################################
+###                              Keyring Guard                              ###
+###############################################################################
+
+# Regular expression of the mainnet chain IDs, on which the insecure test keyring
+# backend is refused unless --i-know-what-i-am-doing is passed, or "none"
+mainnet-chain-id-pattern = "{{ .MainnetChainIDPattern }}"
+
+###############################################################################
+###                              Chain Profiles                             ###
+###############################################################################
+
+# Named endpoints, selected with --chain-profile by the rpc query commands. The
+# chain-id of a profile is checked against the network reported by its node.
+# [profiles.testnet]
+# chain-id = "baron-testnet-1"
+# node = "tcp://localhost:26657"
+{{- range $name, $profile := .Profiles }}
+
+[profiles.{{ $name }}]
+chain-id = "{{ $profile.ChainID }}"
+node = "{{ $profile.Node }}"
+{{- end }}
 `
 
 // writeConfigToFile parses defaultConfigTemplate, renders config using the template and writes it to
@@ -59,6 +59,14 @@ type Context struct {
 	// IsAux is true when the signer is an auxiliary signer (e.g. the tipper).
 	IsAux bool
 
+	// flagKeyringDir is the directory of the keyring opened from the
+	// --keyring-backend flag, which is reused, or its wrapper, when the flags
+	// are read again.
+	flagKeyringDir string
+	// guardedKeyring is the keyring checked by the mainnet keyring guard, so
+	// that reading the flags again doesn't audit it twice.
+	guardedKeyring keyringGuardKey
+
 	// TODO: Deprecated (remove).
 	LegacyAmino *codec.LegacyAmino
 }
@@ -18,12 +18,7 @@ const MinPassLength = 8
 // GetPassword will prompt for a password one-time (to sign a tx)
 // It enforces the password length
 func GetPassword(prompt string, buf *bufio.Reader) (pass string, err error) {
-	if inputIsTty() {
-		pass, err = speakeasy.FAsk(os.Stderr, prompt)
-	} else {
-		pass, err = readLineFromBuf(buf)
-	}
-
+	pass, err = GetSecret(prompt, buf)
 	if err != nil {
 		return "", err
 	}
@@ -37,6 +32,16 @@ func GetPassword(prompt string, buf *bufio.Reader) (pass string, err error) {
 	return pass, nil
 }
 
+// GetSecret will prompt for a secret one-time, without echoing it on
+// interactive prompts. Unlike GetPassword, it doesn't enforce a length, e.g.
+// for hardware token PINs.
+func GetSecret(prompt string, buf *bufio.Reader) (secret string, err error) {
+	if inputIsTty() {
+		return speakeasy.FAsk(os.Stderr, prompt)
+	}
+	return readLineFromBuf(buf)
+}
+
 // GetConfirmation will request user give the confirmation from stdin.
 // "y", "Y", "yes", "YES", and "Yes" all count as confirmations.
 // If the input is not recognized, it returns false and a nil error.
@@ -1,6 +1,13 @@
 package client
 //BC MOD //BC MOD 
 import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -29,3 +36,51 @@ func TestFailingInterfaceRegistry(t *testing.T) {
 		reg.EnsureRegistered(nil)
 	})
 }
+
+// healthHandler answers the JSON RPC health requests, recording their URLs.
+func healthHandler(t *testing.T, urls *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*urls = append(*urls, r.URL.String())
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":{}}`))
+	})
+}
+
+func TestNewClientFromNodeProxy(t *testing.T) {
+	var urls []string
+	proxy := httptest.NewServer(healthHandler(t, &urls))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	nodeProxy = http.ProxyURL(proxyURL)
+	defer func() { nodeProxy = http.ProxyFromEnvironment }()
+
+	// the node behind the bastion isn't reachable but through the proxy
+	rpcClient, err := NewClientFromNode("tcp://node.internal:26657")
+	require.NoError(t, err)
+	_, err = rpcClient.Health(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"http://node.internal:26657/"}, urls)
+}
+
+func TestNewClientFromNodeUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "node.sock")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	var urls []string
+	node := httptest.NewUnstartedServer(healthHandler(t, &urls))
+	node.Listener = l
+	node.Start()
+	defer node.Close()
+
+	rpcClient, err := NewClientFromNode("unix://" + socket)
+	require.NoError(t, err)
+	_, err = rpcClient.Health(context.Background())
+	require.NoError(t, err)
+	require.Len(t, urls, 1)
+}
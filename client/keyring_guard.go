@@ -0,0 +1,139 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+)
+
+const (
+	// KeyMainnetChainIDPattern is the client config key of the regular
+	// expression matching the mainnet chain IDs, on which the insecure
+	// keyring backends are refused.
+	KeyMainnetChainIDPattern = "mainnet-chain-id-pattern"
+	// DefaultMainnetChainIDPattern matches the mainnet chain IDs of Baron
+	// Chain, e.g. baron-1 or baron-mainnet-1, and not the testnet ones.
+	DefaultMainnetChainIDPattern = `^baron-(mainnet-)?[0-9]+$`
+	// MainnetChainIDPatternNone, set as the mainnet chain ID pattern, disables
+	// the guard.
+	MainnetChainIDPatternNone = "none"
+
+	// KeyringAuditLogFileName is the name of the file, within the keyring
+	// directory, the audited keyring operations are recorded to.
+	KeyringAuditLogFileName = "keyring-audit.log"
+
+	auditOperationInsecureBackend = "insecure-backend"
+)
+
+// ErrInsecureKeyringBackend is returned when an insecure keyring backend is
+// used on a mainnet chain without --i-know-what-i-am-doing.
+var ErrInsecureKeyringBackend = errors.New("insecure keyring backend")
+
+// insecureKeyringBackends store the keys unencrypted on disk.
+var insecureKeyringBackends = map[string]bool{
+	keyring.BackendTest: true,
+}
+
+// keyringGuardAuditEntry is the line of the keyring audit log recording the
+// use of an insecure backend on a mainnet chain, in the format of the entries
+// of the keys commands.
+type keyringGuardAuditEntry struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Keys      []string  `json:"keys"`
+	ChainID   string    `json:"chain_id"`
+	Backend   string    `json:"backend"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// keyringGuardKey identifies the keyring of a context checked by
+// CheckKeyringBackend.
+type keyringGuardKey struct {
+	chainID string
+	backend string
+	dir     string
+}
+
+// MainnetChainIDPattern returns the regular expression of the mainnet chain
+// IDs of the client config, or nil when the guard is disabled.
+func MainnetChainIDPattern(ctx Context) (*regexp.Regexp, error) {
+	pattern := DefaultMainnetChainIDPattern
+	if ctx.Viper != nil && ctx.Viper.GetString(KeyMainnetChainIDPattern) != "" {
+		pattern = ctx.Viper.GetString(KeyMainnetChainIDPattern)
+	}
+	if pattern == MainnetChainIDPatternNone {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q in client config: %w", KeyMainnetChainIDPattern, pattern, err)
+	}
+	return re, nil
+}
+
+// CheckKeyringBackend refuses the insecure keyring backends, which store the
+// keys unencrypted, when the chain ID of the context matches the mainnet chain
+// ID pattern, unless override is set. Both refusals and overrides are recorded
+// in the keyring audit log.
+func CheckKeyringBackend(ctx Context, override bool) error {
+	if ctx.Keyring == nil || !insecureKeyringBackends[ctx.Keyring.Backend()] || ctx.ChainID == "" {
+		return nil
+	}
+
+	re, err := MainnetChainIDPattern(ctx)
+	if err != nil || re == nil || !re.MatchString(ctx.ChainID) {
+		return err
+	}
+
+	entry := keyringGuardAuditEntry{
+		Time:      time.Now().UTC(),
+		Operation: auditOperationInsecureBackend,
+		Keys:      []string{},
+		ChainID:   ctx.ChainID,
+		Backend:   ctx.Keyring.Backend(),
+		Outcome:   "success",
+	}
+	if !override {
+		err = fmt.Errorf("%w: the %s backend stores the keys unencrypted, and %s is a mainnet chain ID matching %s; "+
+			"use a secure backend, or pass --i-know-what-i-am-doing", ErrInsecureKeyringBackend, entry.Backend, ctx.ChainID, re)
+		entry.Outcome = "rejected"
+		entry.Error = err.Error()
+	}
+
+	if auditErr := appendKeyringAuditLog(ctx.KeyringDir, entry); auditErr != nil && override {
+		// the use of an insecure backend on mainnet must leave a trace
+		return fmt.Errorf("refusing the %s backend on %s: %w", entry.Backend, ctx.ChainID, auditErr)
+	}
+	return err
+}
+
+func appendKeyringAuditLog(keyringDir string, entry keyringGuardAuditEntry) error {
+	bz, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(keyringDir, 0o700); err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(keyringDir, KeyringAuditLogFileName), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(append(bz, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package client_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/types/module/testutil"
+)
+
+func TestCheckKeyringBackend(t *testing.T) {
+	cfg := testutil.MakeTestEncodingConfig()
+	dir := t.TempDir()
+	kr, err := keyring.New(t.Name(), keyring.BackendTest, dir, nil, cfg.Codec)
+	require.NoError(t, err)
+
+	ctx := client.Context{}.WithViper("").WithKeyring(kr).WithKeyringDir(dir)
+	auditLog := filepath.Join(dir, client.KeyringAuditLogFileName)
+
+	// testnets are allowed, and not audited
+	require.NoError(t, client.CheckKeyringBackend(ctx.WithChainID("baron-testnet-1"), false))
+	require.NoFileExists(t, auditLog)
+
+	// mainnets are refused without the override
+	err = client.CheckKeyringBackend(ctx.WithChainID("baron-1"), false)
+	require.ErrorIs(t, err, client.ErrInsecureKeyringBackend)
+	require.ErrorContains(t, err, "--i-know-what-i-am-doing")
+
+	require.NoError(t, client.CheckKeyringBackend(ctx.WithChainID("baron-mainnet-2"), true))
+
+	bz, err := os.ReadFile(auditLog)
+	require.NoError(t, err)
+	var entries []map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	for dec.More() {
+		var entry map[string]interface{}
+		require.NoError(t, dec.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 2)
+	require.Equal(t, "baron-1", entries[0]["chain_id"])
+	require.Equal(t, "rejected", entries[0]["outcome"])
+	require.Equal(t, "baron-mainnet-2", entries[1]["chain_id"])
+	require.Equal(t, "success", entries[1]["outcome"])
+	require.Equal(t, keyring.BackendTest, entries[1]["backend"])
+
+	// the pattern is configurable, and the guard can be disabled
+	ctx.Viper.Set(client.KeyMainnetChainIDPattern, `^prod-`)
+	require.NoError(t, client.CheckKeyringBackend(ctx.WithChainID("baron-1"), false))
+	require.ErrorIs(t, client.CheckKeyringBackend(ctx.WithChainID("prod-chain"), false), client.ErrInsecureKeyringBackend)
+
+	ctx.Viper.Set(client.KeyMainnetChainIDPattern, client.MainnetChainIDPatternNone)
+	require.NoError(t, client.CheckKeyringBackend(ctx.WithChainID("baron-1"), false))
+
+	ctx.Viper.Set(client.KeyMainnetChainIDPattern, `(`)
+	require.ErrorContains(t, client.CheckKeyringBackend(ctx.WithChainID("baron-1"), false), "invalid")
+}
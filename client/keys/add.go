@@ -7,12 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 
 	"github.com/cosmos/go-bip39"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/config"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/client/input"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
@@ -23,15 +25,17 @@ import (
 )
 
 const (
-	flagInteractive = "interactive"
-	flagRecover     = "recover"
-	flagNoBackup    = "no-backup"
-	flagCoinType    = "coin-type"
-	flagAccount     = "account"
-	flagIndex       = "index"
-	flagMultisig    = "multisig"
-	flagNoSort      = "nosort"
-	flagHDPath      = "hd-path"
+	flagInteractive  = "interactive"
+	flagRecover      = "recover"
+	flagNoBackup     = "no-backup"
+	flagCoinType     = "coin-type"
+	flagAccount      = "account"
+	flagIndex        = "index"
+	flagMultisig     = "multisig"
+	flagNoSort       = "nosort"
+	flagHDPath       = "hd-path"
+	flagAccountRange = "account-range"
+	flagSecLevel     = "security-level"
 
 	// DefaultKeyPass contains the default key password for genesis transactions
 	DefaultKeyPass = "12345678"
@@ -54,6 +58,34 @@ local keystore.
 Use the --pubkey flag to add arbitrary public keys to the keystore for constructing
 multisig transactions.
 
+When no --hd-path is given, the key-coin-type, key-account-range and key-algo entries of
+the client config (see the config command) set the default coin type, account and algorithm,
+so that all the keys of an environment follow the same derivation conventions:
+
+    config key-account-range 100-199
+    keys add mykey --account 101
+
+Quantum-safe dilithium signing keys and kyber encryption keys are derived from the mnemonic
+and HD path with a domain-separated KDF, so they can be recovered exactly like secp256k1 keys:
+
+    keys add mykey --recover --algo dilithium
+
+kyber is a key encapsulation mechanism: kyber keys can't sign transactions, they are only the
+recipients of encrypted messages.
+
+With --security-level low, medium or high, or a NIST category from 1 to 5, the command fails
+unless the parameter set of the quantum-safe algorithm meets it. dilithium keys use Dilithium3
+and kyber keys Kyber768, which meet the medium level (NIST category 3):
+
+    keys add mykey --algo dilithium --security-level medium
+
+With --if-not-exists, provisioning scripts can be run again: if the name holds the key to
+add, the command succeeds without changes and prints the existing key in JSON, and it fails
+if the name holds another key. Recovered keys, public keys, ledger keys and multisig keys
+are compared by public key, while a generated key is never added over an existing one:
+
+    keys add validator --recover --if-not-exists
+
 You can create and store a multisig key by passing the list of key names stored in a keyring
 and the minimum number of signatures required through --multisig-threshold. The keys are
 sorted by address, unless the flag --nosort is set.
@@ -77,8 +109,12 @@ Example:
 	f.String(flagHDPath, "", "Manual HD Path derivation (overrides BIP44 config)")
 	f.Uint32(flagCoinType, sdk.GetConfig().GetCoinType(), "coin type number for HD derivation")
 	f.Uint32(flagAccount, 0, "Account number for HD derivation (less than equal 2147483647)")
+	f.String(flagAccountRange, "", "Allowed account numbers for HD derivation as <min>-<max>, <min> is used if --account is not set")
 	f.Uint32(flagIndex, 0, "Address index number for HD derivation (less than equal 2147483647)")
-	f.String(flags.FlagKeyType, string(hd.Secp256k1Type), "Key signing algorithm to generate keys for")
+	f.String(flags.FlagKeyType, string(hd.Secp256k1Type), "Key algorithm to generate keys for (secp256k1|dilithium, or kyber for encryption-only keys)")
+	f.Bool(flagForceEntropy, false, "Generate the mnemonic even if the entropy health check fails")
+	f.String(flagSecLevel, "", "Required security level of quantum-safe keys, selecting the quantum-safe algorithm unless --key-type is given (low|medium, or a NIST category from 1 to 3; high is not supported yet)")
+	addIfNotExistsFlag(cmd)
 
 	// support old flags name for backwards compatibility
 	f.SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
@@ -89,7 +125,7 @@ Example:
 		return pflag.NormalizedName(name)
 	})
 
-	return cmd
+	return enforceKeyNamespaces(cmd)
 }
 
 func runAddCmdPrepare(cmd *cobra.Command, args []string) error {
@@ -122,21 +158,44 @@ func runAddCmd(ctx client.Context, cmd *cobra.Command, args []string, inBuf *buf
 	kb := ctx.Keyring
 	outputFormat := ctx.OutputFormat
 
-	keyringAlgos, _ := kb.SupportedAlgorithms()
 	algoStr, _ := cmd.Flags().GetString(flags.FlagKeyType)
-	algo, err := keyring.NewSigningAlgoFromString(algoStr, keyringAlgos)
+	algoSet := cmd.Flags().Changed(flags.FlagKeyType)
+	if v, ok := clientConfigDefault(ctx, cmd, flags.FlagKeyType, config.KeyAlgo); ok && v != "" {
+		algoStr, algoSet = v, true
+	}
+	level, _ := cmd.Flags().GetString(flagSecLevel)
+
+	var algo keyring.SignatureAlgo
+	if level != "" && !algoSet {
+		// the security level selects the algorithm
+		algo, err = recommendSigningAlgo(kb, level)
+	} else {
+		algo, err = resolveSigningAlgo(kb, algoStr)
+		if err == nil && level != "" {
+			err = checkSecurityLevel(algo, level)
+		}
+	}
 	if err != nil {
 		return err
 	}
 
+	// existing is the key of the name with --if-not-exists, which the key to
+	// add, derived in memory, must match.
+	var existing *keyring.Record
+
 	if dryRun, _ := cmd.Flags().GetBool(flags.FlagDryRun); dryRun {
 		// use in memory keybase
-		kb = keyring.NewInMemory(ctx.Codec)
+		kb = keyring.NewInMemory(ctx.Codec, PQCKeyringOption())
 	} else {
-		_, err = kb.Key(name)
-		if err == nil {
+		existing, err = existingKeyIfNotExists(cmd, kb, name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			kb = keyring.NewInMemory(ctx.Codec, PQCKeyringOption())
+		} else if _, err = kb.Key(name); err == nil {
 			// account exists, ask for user confirmation
-			response, err2 := input.GetConfirmation(fmt.Sprintf("override the existing name %s", name), inBuf, cmd.ErrOrStderr())
+			response, err2 := input.GetConfirmation(localize(cmd, msgOverrideExisting, name), inBuf, cmd.ErrOrStderr())
 			if err2 != nil {
 				return err2
 			}
@@ -160,7 +219,7 @@ func runAddCmd(ctx client.Context, cmd *cobra.Command, args []string, inBuf *buf
 			}
 
 			for i, keyname := range multisigKeys {
-				k, err := kb.Key(keyname)
+				k, err := ctx.Keyring.Key(keyname)
 				if err != nil {
 					return err
 				}
@@ -184,6 +243,9 @@ func runAddCmd(ctx client.Context, cmd *cobra.Command, args []string, inBuf *buf
 				return err
 			}
 
+			if existing != nil {
+				return printExistingKey(cmd, existing, k)
+			}
 			return printCreate(cmd, k, false, "", outputFormat)
 		}
 	}
@@ -200,6 +262,9 @@ func runAddCmd(ctx client.Context, cmd *cobra.Command, args []string, inBuf *buf
 			return err
 		}
 
+		if existing != nil {
+			return printExistingKey(cmd, existing, k)
+		}
 		return printCreate(cmd, k, false, "", outputFormat)
 	}
 
@@ -210,11 +275,19 @@ func runAddCmd(ctx client.Context, cmd *cobra.Command, args []string, inBuf *buf
 	useLedger, _ := cmd.Flags().GetBool(flags.FlagUseLedger)
 
 	if len(hdPath) == 0 {
+		coinType, account, err = applyDerivationDefaults(ctx, cmd, coinType, account)
+		if err != nil {
+			return err
+		}
 		hdPath = hd.CreateHDPath(coinType, account, index).String()
 	} else if useLedger {
 		return errors.New("cannot set custom bip32 path with ledger")
 	}
 
+	if useLedger && pqcAlgos().Contains(algo) {
+		return fmt.Errorf("%s keys cannot be stored on a ledger device", algo.Name())
+	}
+
 	// If we're using ledger, only thing we need is the path and the bech32 prefix.
 	if useLedger {
 		bech32PrefixAccAddr := sdk.GetConfig().GetBech32AccountAddrPrefix()
@@ -223,6 +296,9 @@ func runAddCmd(ctx client.Context, cmd *cobra.Command, args []string, inBuf *buf
 			return err
 		}
 
+		if existing != nil {
+			return printExistingKey(cmd, existing, k)
+		}
 		return printCreate(cmd, k, false, "", outputFormat)
 	}
 
@@ -251,6 +327,15 @@ func runAddCmd(ctx client.Context, cmd *cobra.Command, args []string, inBuf *buf
 	}
 
 	if len(mnemonic) == 0 {
+		// a new key is generated, the existing key is kept whatever it is
+		if existing != nil {
+			return printExistingKey(cmd, existing, nil)
+		}
+
+		if err := ensureHealthyEntropy(cmd); err != nil {
+			return err
+		}
+
 		// read entropy seed straight from tmcrypto.Rand and convert to mnemonic
 		entropySeed, err := bip39.NewEntropy(mnemonicEntropySize)
 		if err != nil {
@@ -289,6 +374,9 @@ func runAddCmd(ctx client.Context, cmd *cobra.Command, args []string, inBuf *buf
 	if err != nil {
 		return err
 	}
+	if existing != nil {
+		return printExistingKey(cmd, existing, k)
+	}
 
 	// Recover key from seed passphrase
 	if recover {
@@ -300,6 +388,47 @@ func runAddCmd(ctx client.Context, cmd *cobra.Command, args []string, inBuf *buf
 	return printCreate(cmd, k, showMnemonic, mnemonic, outputFormat)
 }
 
+// clientConfigDefault returns the client config value of key, if it is set and
+// the flag overriding it is not.
+func clientConfigDefault(ctx client.Context, cmd *cobra.Command, flagName, key string) (string, bool) {
+	if cmd.Flags().Changed(flagName) || ctx.Viper == nil || !ctx.Viper.IsSet(key) {
+		return "", false
+	}
+	return ctx.Viper.GetString(key), true
+}
+
+// applyDerivationDefaults applies the coin type and account range of the client
+// config to the derivation path of a new key. The account defaults to the start
+// of the range and must fall within it.
+func applyDerivationDefaults(ctx client.Context, cmd *cobra.Command, coinType, account uint32) (uint32, uint32, error) {
+	if v, ok := clientConfigDefault(ctx, cmd, flagCoinType, config.KeyCoinType); ok {
+		configured, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid %s in client config: %w", config.KeyCoinType, err)
+		}
+		coinType = uint32(configured)
+	}
+
+	accountRange, _ := cmd.Flags().GetString(flagAccountRange)
+	if v, ok := clientConfigDefault(ctx, cmd, flagAccountRange, config.KeyAccountRange); ok {
+		accountRange = v
+	}
+
+	first, last, err := config.ParseAccountRange(accountRange)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !cmd.Flags().Changed(flagAccount) {
+		account = first
+	}
+	if account < first || account > last {
+		return 0, 0, fmt.Errorf("account %d is outside of the allowed account range %d-%d", account, first, last)
+	}
+
+	return coinType, account, nil
+}
+
 func printCreate(cmd *cobra.Command, k *keyring.Record, showMnemonic bool, mnemonic, outputFormat string) error {
 	switch outputFormat {
 	case OutputFormatText:
@@ -310,7 +439,7 @@ func printCreate(cmd *cobra.Command, k *keyring.Record, showMnemonic bool, mnemo
 
 		// print mnemonic unless requested not to.
 		if showMnemonic {
-			if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "\n**Important** write this mnemonic phrase in a safe place.\nIt is the only way to recover your account if you ever forget your password.\n\n%s\n", mnemonic); err != nil {
+			if _, err := fmt.Fprintf(cmd.ErrOrStderr(), "\n%s\n\n%s\n", localize(cmd, msgMnemonicImportant), mnemonic); err != nil {
 				return fmt.Errorf("failed to print mnemonic: %v", err)
 			}
 		}
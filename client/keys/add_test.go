@@ -276,3 +276,93 @@ func TestAddRecoverFileBackend(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "keyname1", k.Name)
 }
+
+func TestAddRecoverDilithium(t *testing.T) {
+	cmd := AddKeyCommand()
+	cmd.Flags().AddFlagSet(Commands("home").PersistentFlags())
+	cdc := clienttestutil.MakeTestCodec(t)
+
+	mockIn := testutil.ApplyMockIODiscardOutErr(cmd)
+	kbHome := t.TempDir()
+
+	// the keyring is opened from the flags with the options of the context
+	clientCtx := client.Context{}.WithKeyringDir(kbHome).WithInput(mockIn).WithCodec(cdc).
+		WithKeyringOptions(PQCKeyringOption())
+	ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+
+	cmd.SetArgs([]string{
+		"pq",
+		fmt.Sprintf("--%s=%s", flags.FlagHome, kbHome),
+		fmt.Sprintf("--%s=%s", cli.OutputFlag, OutputFormatText),
+		fmt.Sprintf("--%s=%s", flags.FlagKeyType, hd.DilithiumType),
+		fmt.Sprintf("--%s=%s", flags.FlagKeyringBackend, keyring.BackendTest),
+		fmt.Sprintf("--%s", flagRecover),
+	})
+	mockIn.Reset(testdata.TestMnemonic + "\n")
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	kb, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, kbHome, mockIn, cdc, PQCKeyringOption())
+	require.NoError(t, err)
+	k, err := kb.Key("pq")
+	require.NoError(t, err)
+	pub, err := k.GetPubKey()
+	require.NoError(t, err)
+
+	// the key is the one derived from the mnemonic
+	algo, err := keyring.NewSigningAlgoFromString(string(hd.DilithiumType), PQCSigningAlgos)
+	require.NoError(t, err)
+	expected, err := keyring.NewInMemory(cdc, PQCKeyringOption()).NewAccount("pq", testdata.TestMnemonic, keyring.DefaultBIP39Passphrase, sdk.FullFundraiserPath, algo)
+	require.NoError(t, err)
+	expectedPub, err := expected.GetPubKey()
+	require.NoError(t, err)
+	require.True(t, expectedPub.Equals(pub))
+}
+
+func TestCheckSecurityLevel(t *testing.T) {
+	dilithiumAlgo, err := keyring.NewSigningAlgoFromString(string(hd.DilithiumType), PQCSigningAlgos)
+	require.NoError(t, err)
+
+	require.NoError(t, checkSecurityLevel(dilithiumAlgo, "low"))
+	require.NoError(t, checkSecurityLevel(dilithiumAlgo, "medium"))
+	require.ErrorContains(t, checkSecurityLevel(dilithiumAlgo, "high"), "it requires dilithium5")
+	require.ErrorContains(t, checkSecurityLevel(dilithiumAlgo, "extreme"), "invalid security level")
+	require.ErrorContains(t, checkSecurityLevel(hd.Secp256k1, "low"), "not quantum-safe")
+}
+
+func TestRecommendSigningAlgo(t *testing.T) {
+	kb := keyring.NewInMemory(clienttestutil.MakeTestCodec(t), PQCKeyringOption())
+
+	for _, level := range []string{"low", "medium", "3"} {
+		algo, err := recommendSigningAlgo(kb, level)
+		require.NoError(t, err)
+		require.Equal(t, hd.DilithiumType, algo.Name())
+	}
+
+	_, err := recommendSigningAlgo(kb, "high")
+	require.ErrorContains(t, err, "requires dilithium5 keys, which are not supported")
+	_, err = recommendSigningAlgo(kb, "extreme")
+	require.ErrorContains(t, err, "invalid security level")
+
+	// the keyring must support the recommended algorithm
+	_, err = recommendSigningAlgo(keyring.NewInMemory(clienttestutil.MakeTestCodec(t)), "low")
+	require.ErrorContains(t, err, "not enabled in this keyring")
+}
+
+func TestPQCEncryptionAlgos(t *testing.T) {
+	// kyber is a key encapsulation mechanism, not a signature scheme
+	_, err := keyring.NewSigningAlgoFromString(string(hd.KyberType), PQCSigningAlgos)
+	require.Error(t, err)
+
+	// but the keyring derives kyber keys, to encrypt messages to
+	kb := keyring.NewInMemory(clienttestutil.MakeTestCodec(t), PQCKeyringOption())
+	algo, err := resolveSigningAlgo(kb, string(hd.KyberType))
+	require.NoError(t, err)
+	k, err := kb.NewAccount("recipient", testdata.TestMnemonic, keyring.DefaultBIP39Passphrase, sdk.FullFundraiserPath, algo)
+	require.NoError(t, err)
+	pub, err := k.GetPubKey()
+	require.NoError(t, err)
+	require.Equal(t, string(hd.KyberType), pub.Type())
+
+	_, err = resolveSigningAlgo(keyring.NewInMemory(clienttestutil.MakeTestCodec(t)), string(hd.KyberType))
+	require.ErrorContains(t, err, "not enabled in this keyring")
+}
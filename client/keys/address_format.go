@@ -0,0 +1,136 @@
+package keys
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/sha3"
+
+	cryptokeyring "github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+	"github.com/baron-chain/cosmos-sdk/types/bech32"
+)
+
+const (
+	flagAddressFormat = "address-format"
+
+	// AddressFormatBech32 is the default bech32 (BIP-173) address format.
+	AddressFormatBech32 = "bech32"
+	// AddressFormatBech32m is the bech32m (BIP-350) address format.
+	AddressFormatBech32m = "bech32m"
+	// AddressFormatHex is the 0x prefixed hex address format, with the
+	// EIP-55 mixed-case checksum.
+	AddressFormatHex = "hex"
+)
+
+func addAddressFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String(flagAddressFormat, AddressFormatBech32,
+		fmt.Sprintf("Address encoding (%s|%s|%s)", AddressFormatBech32, AddressFormatBech32m, AddressFormatHex))
+}
+
+func getAddressFormat(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString(flagAddressFormat)
+	switch format {
+	case AddressFormatBech32, AddressFormatBech32m, AddressFormatHex:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid --%s %q, expected %s, %s or %s",
+			flagAddressFormat, format, AddressFormatBech32, AddressFormatBech32m, AddressFormatHex)
+	}
+}
+
+// FormatAddress encodes the address bytes in the given format, with the
+// human readable part hrp for bech32 and bech32m.
+func FormatAddress(addr []byte, hrp, format string) (string, error) {
+	switch format {
+	case AddressFormatBech32:
+		return bech32.ConvertAndEncode(hrp, addr)
+	case AddressFormatBech32m:
+		return bech32.ConvertAndEncodeM(hrp, addr)
+	case AddressFormatHex:
+		return ChecksumHex(addr), nil
+	default:
+		return "", fmt.Errorf("unknown address format %q", format)
+	}
+}
+
+// ChecksumHex returns the 0x prefixed hex encoding of the address with the
+// EIP-55 checksum: the letters whose nibble of the keccak-256 hash of the
+// lowercase hex are 8 or more are uppercased. Addresses of other lengths than
+// Ethereum's 20 bytes are checksummed the same way.
+func ChecksumHex(addr []byte) string {
+	lower := hex.EncodeToString(addr)
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	hash := h.Sum(nil)
+
+	out := []byte(lower)
+	for i, c := range out {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0xf
+		}
+		if c >= 'a' && c <= 'f' && nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// decodeChecksumHex decodes a hex address, optionally 0x prefixed. Mixed-case
+// addresses must have a valid EIP-55 checksum.
+func decodeChecksumHex(s string) ([]byte, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	addr, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, err
+	}
+
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && ChecksumHex(addr)[2:] != digits {
+		return nil, fmt.Errorf("invalid EIP-55 checksum of %s", s)
+	}
+	return addr, nil
+}
+
+// reformatAddress re-encodes a bech32 address in the given format, keeping
+// its human readable part.
+func reformatAddress(bechAddr, format string) (string, error) {
+	if format == AddressFormatBech32 {
+		return bechAddr, nil
+	}
+
+	hrp, bz, err := bech32.DecodeAndConvert(bechAddr)
+	if err != nil {
+		return "", err
+	}
+	return FormatAddress(bz, hrp, format)
+}
+
+// withAddressFormat returns the key output of fn with its address in the
+// given format.
+func withAddressFormat(fn bechKeyOutFn, format string) bechKeyOutFn {
+	return func(k *cryptokeyring.Record) (cryptokeyring.KeyOutput, error) {
+		ko, err := fn(k)
+		if err != nil {
+			return cryptokeyring.KeyOutput{}, err
+		}
+
+		if ko.Address, err = reformatAddress(ko.Address, format); err != nil {
+			return cryptokeyring.KeyOutput{}, fmt.Errorf("failed to format address of %s: %w", k.Name, err)
+		}
+		return ko, nil
+	}
+}
+
+// recordAccAddress returns the account address of the record in the given
+// format.
+func recordAccAddress(k *cryptokeyring.Record, format string) (string, error) {
+	addr, err := k.GetAddress()
+	if err != nil {
+		return "", err
+	}
+	return FormatAddress(addr, sdk.GetConfig().GetBech32AccountAddrPrefix(), format)
+}
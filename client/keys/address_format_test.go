@@ -0,0 +1,111 @@
+package keys
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+	"github.com/baron-chain/cosmos-sdk/types/bech32"
+)
+
+func TestChecksumHex(t *testing.T) {
+	// EIP-55 test vectors
+	for _, addr := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		bz, err := hex.DecodeString(strings.ToLower(addr[2:]))
+		require.NoError(t, err)
+		require.Equal(t, addr, ChecksumHex(bz))
+
+		decoded, err := decodeChecksumHex(addr)
+		require.NoError(t, err)
+		require.Equal(t, bz, decoded)
+	}
+
+	// single case addresses have no checksum, mixed case ones must match it
+	_, err := decodeChecksumHex("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	require.NoError(t, err)
+	_, err = decodeChecksumHex("5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED")
+	require.NoError(t, err)
+	_, err = decodeChecksumHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	require.ErrorContains(t, err, "EIP-55")
+}
+
+func TestFormatAddress(t *testing.T) {
+	addr := sdk.AccAddress("address_format_test_")
+
+	bech, err := FormatAddress(addr, "baron", AddressFormatBech32)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(bech, "baron1"))
+
+	bechm, err := FormatAddress(addr, "baron", AddressFormatBech32m)
+	require.NoError(t, err)
+	require.NotEqual(t, bech, bechm)
+	hrp, bz, err := bech32.DecodeAndConvertM(bechm)
+	require.NoError(t, err)
+	require.Equal(t, "baron", hrp)
+	require.Equal(t, []byte(addr), bz)
+
+	hexAddr, err := FormatAddress(addr, "baron", AddressFormatHex)
+	require.NoError(t, err)
+	require.Equal(t, ChecksumHex(addr), hexAddr)
+
+	_, err = FormatAddress(addr, "baron", "base58")
+	require.Error(t, err)
+
+	// reformatting keeps the human readable part of the address
+	reformatted, err := reformatAddress(bech, AddressFormatBech32m)
+	require.NoError(t, err)
+	require.Equal(t, bechm, reformatted)
+	reformatted, err = reformatAddress(bech, AddressFormatBech32)
+	require.NoError(t, err)
+	require.Equal(t, bech, reformatted)
+}
+
+func TestParseAddressFormats(t *testing.T) {
+	config := sdk.NewConfig()
+	config.SetBech32PrefixForAccount("baron", "baronpub")
+	config.SetBech32PrefixForValidator("baronvaloper", "baronvaloperpub")
+	config.SetBech32PrefixForConsensusNode("baronvalcons", "baronvalconspub")
+
+	addr := sdk.AccAddress("address_format_test_")
+	bech, err := FormatAddress(addr, "baron", AddressFormatBech32)
+	require.NoError(t, err)
+	bechm, err := FormatAddress(addr, "baron", AddressFormatBech32m)
+	require.NoError(t, err)
+	checksummed := ChecksumHex(addr)
+
+	// bech32m addresses are decoded like bech32 ones
+	out, err := parseAddress(bechm, config, AddressFormatBech32)
+	require.NoError(t, err)
+	require.Equal(t, "baron", out.HumanReadable)
+	require.Equal(t, strings.ToUpper(hex.EncodeToString(addr)), out.HexBytes)
+
+	out, err = parseAddress(bech, config, AddressFormatBech32m)
+	require.NoError(t, err)
+	require.Equal(t, []string{bechm}, out.Bech32mFormats)
+
+	out, err = parseAddress(bech, config, AddressFormatHex)
+	require.NoError(t, err)
+	require.Equal(t, checksummed, out.ChecksumHex)
+
+	// checksummed hex addresses are converted to bech32 or bech32m
+	out, err = parseAddress(checksummed, config, AddressFormatBech32)
+	require.NoError(t, err)
+	require.Len(t, out.Bech32Formats, 6)
+	require.Equal(t, bech, out.Bech32Formats[0])
+	require.Empty(t, out.Bech32mFormats)
+
+	out, err = parseAddress(checksummed, config, AddressFormatBech32m)
+	require.NoError(t, err)
+	require.Len(t, out.Bech32mFormats, 6)
+	require.Equal(t, bechm, out.Bech32mFormats[0])
+	require.Empty(t, out.Bech32Formats)
+	require.Contains(t, out.String(), "Bech32m Formats:")
+}
@@ -0,0 +1,553 @@
+package keys
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-sdk/client"
+	"github.com/baron-chain/cosmos-sdk/codec/legacy"
+	"github.com/baron-chain/cosmos-sdk/crypto"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/baron-chain/cosmos-sdk/crypto/types"
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+)
+
+const (
+	flagApprovalFile = "approval-file"
+	flagApprover     = "approver"
+	flagApprovalTTL  = "ttl"
+
+	// DualControlConfigFileName is the name of the file, within the keyring
+	// directory, enabling dual control of destructive key operations.
+	DualControlConfigFileName = "keyring-dual-control.json"
+	// KeyAuditLogFileName is the name of the file, within the keyring
+	// directory, approved operations are recorded to, unless the dual control
+	// configuration sets another one.
+	KeyAuditLogFileName = "keyring-audit.log"
+
+	// ApprovalOpDelete is the operation of the keys delete and shred commands.
+	ApprovalOpDelete = "delete"
+	// ApprovalOpExport is the operation of the keys export, split, share and
+	// autobackup commands.
+	ApprovalOpExport = "export"
+	// ApprovalOpMigrate is the operation of the keys migrate command.
+	ApprovalOpMigrate = "migrate"
+
+	// AllKeys is the key name of approvals of operations on all the keys, such
+	// as migrate.
+	AllKeys = "*"
+
+	// keyApprovalDomain prefixes the signed approval, so that an approval
+	// signature can't be replayed as the signature of another message.
+	keyApprovalDomain = "baron-chain key approval:"
+
+	blockTypeKeyApproval  = "BARON CHAIN KEY APPROVAL"
+	keyApprovalVersion    = "1"
+	headerApprovalVersion = "version"
+	headerApprovalAddress = "address"
+	headerApprovalPubKey  = "pub_key"
+	keyApprovalNonceLen   = 16
+	defaultApprovalTTL    = time.Hour
+	maxApprovalTTL        = 24 * time.Hour
+
+	auditOutcomeSuccess  = "success"
+	auditOutcomeFailure  = "failure"
+	auditOutcomeRejected = "rejected"
+)
+
+// DualControlConfig enables dual control of destructive key operations: each
+// of them then requires an approval signed by one of the approver keys, given
+// with --approval-file, and is recorded in the audit log.
+type DualControlConfig struct {
+	// Approvers are the addresses of the keys allowed to approve operations.
+	Approvers []string `json:"approvers"`
+	// Operations are the operations requiring an approval, among delete,
+	// export and migrate. All of them when empty.
+	Operations []string `json:"operations,omitempty"`
+	// AuditLog is the path of the audit log, by default KeyAuditLogFileName in
+	// the keyring directory.
+	AuditLog string `json:"audit_log,omitempty"`
+}
+
+// LoadDualControlConfig reads the DualControlConfigFileName file of the
+// keyring directory. It returns nil when the file doesn't exist, i.e. when
+// dual control is not enabled.
+func LoadDualControlConfig(keyringDir string) (*DualControlConfig, error) {
+	file := filepath.Join(keyringDir, DualControlConfigFileName)
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read dual control config: %w", err)
+	}
+
+	var cfg DualControlConfig
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse dual control config %s: %w", file, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid dual control config %s: %w", file, err)
+	}
+
+	if cfg.AuditLog == "" {
+		cfg.AuditLog = filepath.Join(keyringDir, KeyAuditLogFileName)
+	}
+	return &cfg, nil
+}
+
+// Validate checks there is at least one approver, with a valid address, and
+// that the operations are known.
+func (c DualControlConfig) Validate() error {
+	if len(c.Approvers) == 0 {
+		return errors.New("no approvers")
+	}
+	for _, addr := range c.Approvers {
+		if _, err := sdk.AccAddressFromBech32(addr); err != nil {
+			return fmt.Errorf("invalid approver address %q: %w", addr, err)
+		}
+	}
+	for _, op := range c.Operations {
+		if !isApprovalOp(op) {
+			return fmt.Errorf("unknown operation %q", op)
+		}
+	}
+	return nil
+}
+
+// Requires reports whether the operation requires an approval.
+func (c DualControlConfig) Requires(op string) bool {
+	return len(c.Operations) == 0 || containsOp(c.Operations, op)
+}
+
+func isApprovalOp(op string) bool {
+	return op == ApprovalOpDelete || op == ApprovalOpExport || op == ApprovalOpMigrate
+}
+
+// KeyApproval is the approval of an operation on keys by an approver key. It
+// is exchanged as an ASCII-armored block.
+type KeyApproval struct {
+	Operation string    `json:"operation"`
+	Keys      []string  `json:"keys"`
+	Expires   time.Time `json:"expires"`
+	// Nonce makes each approval unique, so that it can only be used once.
+	Nonce []byte `json:"nonce"`
+
+	Address   string             `json:"-"`
+	PubKey    cryptotypes.PubKey `json:"-"`
+	Signature []byte             `json:"-"`
+}
+
+// KeyApprovalSignBytes returns the bytes an approval signature is over: its
+// JSON encoding, with sorted keys and without whitespace, prefixed with the key
+// approval domain.
+func KeyApprovalSignBytes(approval KeyApproval) ([]byte, error) {
+	bz, err := json.Marshal(approval)
+	if err != nil {
+		return nil, err
+	}
+	bz, err = sdk.SortJSON(bz)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(keyApprovalDomain), bz...), nil
+}
+
+// ApproveKeyOperation signs the approval of the operation on the named keys
+// with the key uid of kr, valid for ttl, and returns the armored approval.
+func ApproveKeyOperation(kr keyring.Keyring, uid, op string, keys []string, ttl time.Duration) (string, error) {
+	if !isApprovalOp(op) {
+		return "", fmt.Errorf("unknown operation %q, expected %s, %s or %s", op, ApprovalOpDelete, ApprovalOpExport, ApprovalOpMigrate)
+	}
+	if len(keys) == 0 {
+		return "", errors.New("no keys to approve the operation on")
+	}
+	if ttl <= 0 || ttl > maxApprovalTTL {
+		return "", fmt.Errorf("invalid approval ttl %s, must be positive and at most %s", ttl, maxApprovalTTL)
+	}
+
+	k, err := kr.Key(uid)
+	if err != nil {
+		return "", err
+	}
+	pub, err := k.GetPubKey()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, keyApprovalNonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	approval := KeyApproval{
+		Operation: op,
+		Keys:      normalizeApprovalKeys(keys),
+		Expires:   time.Now().UTC().Add(ttl).Truncate(time.Second),
+		Nonce:     nonce,
+		Address:   sdk.AccAddress(pub.Address()).String(),
+		PubKey:    pub,
+	}
+
+	bz, err := KeyApprovalSignBytes(approval)
+	if err != nil {
+		return "", err
+	}
+	if approval.Signature, _, err = kr.Sign(uid, bz); err != nil {
+		return "", fmt.Errorf("failed to sign approval: %w", err)
+	}
+	return ArmorKeyApproval(approval)
+}
+
+// ArmorKeyApproval encodes approval as an ASCII-armored block.
+func ArmorKeyApproval(approval KeyApproval) (string, error) {
+	bz, err := json.Marshal(approval)
+	if err != nil {
+		return "", err
+	}
+	pubBytes, err := legacy.Cdc.Marshal(approval.PubKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	headers := map[string]string{
+		headerApprovalVersion: keyApprovalVersion,
+		headerApprovalAddress: approval.Address,
+		headerApprovalPubKey:  base64.StdEncoding.EncodeToString(pubBytes),
+	}
+	// the signature follows the approval, which is a single JSON line
+	body := append(append(bz, '\n'), approval.Signature...)
+	return crypto.EncodeArmor(blockTypeKeyApproval, headers, body), nil
+}
+
+// UnarmorKeyApproval decodes an ASCII-armored approval, without verifying it.
+func UnarmorKeyApproval(armored string) (KeyApproval, error) {
+	blockType, headers, body, err := crypto.DecodeArmor(armored)
+	if err != nil {
+		return KeyApproval{}, fmt.Errorf("invalid approval: %w", err)
+	}
+	if blockType != blockTypeKeyApproval {
+		return KeyApproval{}, fmt.Errorf("unrecognized armor type %q, expected: %q", blockType, blockTypeKeyApproval)
+	}
+	if headers[headerApprovalVersion] != keyApprovalVersion {
+		return KeyApproval{}, fmt.Errorf("unrecognized approval version: %v", headers[headerApprovalVersion])
+	}
+
+	i := bytes.IndexByte(body, '\n')
+	if i < 0 {
+		return KeyApproval{}, errors.New("invalid approval: missing signature")
+	}
+	var approval KeyApproval
+	if err := json.Unmarshal(body[:i], &approval); err != nil {
+		return KeyApproval{}, fmt.Errorf("invalid approval: %w", err)
+	}
+
+	pubBytes, err := base64.StdEncoding.DecodeString(headers[headerApprovalPubKey])
+	if err != nil {
+		return KeyApproval{}, fmt.Errorf("invalid approval public key: %w", err)
+	}
+	if approval.PubKey, err = legacy.PubKeyFromBytes(pubBytes); err != nil {
+		return KeyApproval{}, fmt.Errorf("invalid approval public key: %w", err)
+	}
+	approval.Address = headers[headerApprovalAddress]
+	approval.Signature = body[i+1:]
+	return approval, nil
+}
+
+// VerifyApproval decodes an ASCII-armored approval and verifies it is a valid,
+// unexpired approval of the operation on all the keys, signed by one of the
+// approvers.
+func (c DualControlConfig) VerifyApproval(armored, op string, keys []string, now time.Time) (KeyApproval, error) {
+	approval, err := UnarmorKeyApproval(armored)
+	if err != nil {
+		return KeyApproval{}, err
+	}
+
+	if addr := sdk.AccAddress(approval.PubKey.Address()).String(); addr != approval.Address {
+		return KeyApproval{}, fmt.Errorf("approval address %s doesn't match its public key address %s", approval.Address, addr)
+	}
+	approver := false
+	for _, addr := range c.Approvers {
+		approver = approver || addr == approval.Address
+	}
+	if !approver {
+		return KeyApproval{}, fmt.Errorf("%s is not an approver", approval.Address)
+	}
+
+	bz, err := KeyApprovalSignBytes(approval)
+	if err != nil {
+		return KeyApproval{}, err
+	}
+	if !approval.PubKey.VerifySignature(bz, approval.Signature) {
+		return KeyApproval{}, errors.New("invalid approval signature")
+	}
+
+	if approval.Operation != op {
+		return KeyApproval{}, fmt.Errorf("approval is for the %s operation, not %s", approval.Operation, op)
+	}
+	if !now.Before(approval.Expires) {
+		return KeyApproval{}, fmt.Errorf("approval expired at %s", approval.Expires.Format(time.RFC3339))
+	}
+	approved := make(map[string]bool, len(approval.Keys))
+	for _, name := range approval.Keys {
+		approved[name] = true
+	}
+	for _, name := range keys {
+		if !approved[AllKeys] && !approved[name] {
+			return KeyApproval{}, fmt.Errorf("approval doesn't cover key %s", name)
+		}
+	}
+	return approval, nil
+}
+
+// normalizeApprovalKeys returns the sorted key names, without duplicates.
+func normalizeApprovalKeys(keys []string) []string {
+	set := make(map[string]bool, len(keys))
+	normalized := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if !set[k] {
+			set[k] = true
+			normalized = append(normalized, k)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// KeyAuditEntry is a line of the audit log.
+type KeyAuditEntry struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Keys      []string  `json:"keys"`
+	Approver  string    `json:"approver,omitempty"`
+	// Approval is the hex encoded SHA-256 of the approval sign bytes, which
+	// identifies the approval whatever its armor.
+	Approval string `json:"approval,omitempty"`
+	Outcome  string `json:"outcome"`
+	Error    string `json:"error,omitempty"`
+}
+
+// appendKeyAuditLog appends the entry to the audit log, as a JSON line.
+func appendKeyAuditLog(file string, entry KeyAuditEntry) error {
+	bz, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := f.Write(append(bz, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// approvalUsed reports whether the audit log records a successful operation
+// with the approval of the given hash.
+func approvalUsed(file, hash string) (bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry KeyAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Approval == hash && entry.Outcome == auditOutcomeSuccess {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// keyApprovalHash returns the hex encoded SHA-256 of the approval sign bytes.
+// Unlike the armored approval, they can't be altered without invalidating the
+// signature, so the hash identifies the approval in the audit log.
+func keyApprovalHash(approval KeyApproval) (string, error) {
+	bz, err := KeyApprovalSignBytes(approval)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(bz)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// keyOperationApproval is the approval of a destructive key operation, whose
+// outcome is to be recorded in the audit log. A nil approval records nothing,
+// when dual control is not enabled.
+type keyOperationApproval struct {
+	auditLog string
+	entry    KeyAuditEntry
+}
+
+// requireKeyApproval checks, when dual control is enabled for the operation,
+// the approval given with --approval-file. Rejected approvals are recorded in
+// the audit log, and the outcome of approved operations must be recorded with
+// done.
+func requireKeyApproval(cmd *cobra.Command, clientCtx client.Context, op string, keys []string) (*keyOperationApproval, error) {
+	cfg, err := LoadDualControlConfig(clientCtx.KeyringDir)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil || !cfg.Requires(op) {
+		return nil, nil
+	}
+
+	a := &keyOperationApproval{
+		auditLog: cfg.AuditLog,
+		entry:    KeyAuditEntry{Operation: op, Keys: normalizeApprovalKeys(keys)},
+	}
+
+	file, _ := cmd.Flags().GetString(flagApprovalFile)
+	if file == "" {
+		return nil, a.reject(fmt.Errorf("dual control is enabled: %s requires an approval, given with --%s", op, flagApprovalFile))
+	}
+	armored, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read approval file: %w", err)
+	}
+
+	approval, err := cfg.VerifyApproval(string(armored), op, keys, time.Now())
+	if err != nil {
+		return nil, a.reject(err)
+	}
+	a.entry.Approver = approval.Address
+	if a.entry.Approval, err = keyApprovalHash(approval); err != nil {
+		return nil, err
+	}
+
+	used, err := approvalUsed(cfg.AuditLog, a.entry.Approval)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, a.reject(errors.New("approval was already used"))
+	}
+	return a, nil
+}
+
+// reject records the rejection of the approval and returns err, as an
+// ErrPolicyViolation.
+func (a *keyOperationApproval) reject(err error) error {
+	err = &Error{Kind: ErrPolicyViolation, Err: err}
+	if logErr := a.record(auditOutcomeRejected, err); logErr != nil {
+		return fmt.Errorf("%w; %v", err, logErr)
+	}
+	return err
+}
+
+// done records the outcome of the approved operation and returns opErr, or an
+// error failing to record it.
+func (a *keyOperationApproval) done(opErr error) error {
+	if a == nil {
+		return opErr
+	}
+
+	outcome := auditOutcomeSuccess
+	if opErr != nil {
+		outcome = auditOutcomeFailure
+	}
+	if err := a.record(outcome, opErr); err != nil {
+		if opErr != nil {
+			return fmt.Errorf("%w; %v", opErr, err)
+		}
+		return err
+	}
+	return opErr
+}
+
+func (a *keyOperationApproval) record(outcome string, err error) error {
+	entry := a.entry
+	entry.Time = time.Now().UTC()
+	entry.Outcome = outcome
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return appendKeyAuditLog(a.auditLog, entry)
+}
+
+// addApprovalFileFlag adds the --approval-file flag to a destructive command.
+func addApprovalFileFlag(cmd *cobra.Command) {
+	cmd.Flags().String(flagApprovalFile, "", "Path of the approval of the operation by an approver key, required when dual control is enabled")
+}
+
+// ApproveKeyOperationCommand returns the command approving a destructive key
+// operation with an approver key.
+func ApproveKeyOperationCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve <operation> <name>...",
+		Short: "Approve a delete, export or migrate operation on keys of a dual control keyring",
+		Long: `Sign, with the approver key given with --approver, the approval of a delete, export
+or migrate operation on the named keys of another keyring, and print it as an
+ASCII-armored block. Approve migrate with the key name "*", as it applies to all
+keys.
+
+Dual control is enabled on a keyring by the ` + DualControlConfigFileName + ` file of its
+directory, listing the addresses of the approver keys and optionally the operations
+requiring an approval, all of them by default:
+
+    {
+      "approvers":  ["baron1..."],
+      "operations": ["delete", "export", "migrate"],
+      "audit_log":  "/var/log/barond/keyring-audit.log"
+    }
+
+The approval is then given to the operation with --approval-file. It is valid once,
+until it expires, and each operation is recorded in the audit log, by default
+` + KeyAuditLogFileName + ` in the keyring directory.`,
+		Example: `  barond keys approve delete validator --approver security-officer > approval.asc
+  barond keys delete validator --approval-file approval.asc`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			approver, _ := cmd.Flags().GetString(flagApprover)
+			ttl, _ := cmd.Flags().GetDuration(flagApprovalTTL)
+			armored, err := ApproveKeyOperation(clientCtx.Keyring, approver, args[0], args[1:], ttl)
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(strings.TrimSpace(armored))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagApprover, "", "Name of the approver key signing the approval")
+	cmd.Flags().Duration(flagApprovalTTL, defaultApprovalTTL, "Validity of the approval, at most "+maxApprovalTTL.String())
+	_ = cmd.MarkFlagRequired(flagApprover)
+
+	return cmd
+}
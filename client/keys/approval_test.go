@@ -0,0 +1,132 @@
+package keys
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/baron-chain/cosmos-sdk/client"
+	clienttestutil "github.com/baron-chain/cosmos-sdk/client/testutil"
+	"github.com/baron-chain/cosmos-sdk/crypto/hd"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+)
+
+func TestVerifyApproval(t *testing.T) {
+	kr := keyring.NewInMemory(clienttestutil.MakeTestCodec(t))
+	officer, _, err := kr.NewMnemonic("officer", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+	_, _, err = kr.NewMnemonic("other", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+	addr, err := officer.GetAddress()
+	require.NoError(t, err)
+	cfg := DualControlConfig{Approvers: []string{addr.String()}}
+	require.NoError(t, cfg.Validate())
+
+	armored, err := ApproveKeyOperation(kr, "officer", ApprovalOpDelete, []string{"validator", "relayer"}, time.Hour)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(armored, "-----BEGIN "+blockTypeKeyApproval+"-----"))
+
+	approval, err := cfg.VerifyApproval(armored, ApprovalOpDelete, []string{"relayer"}, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, addr.String(), approval.Address)
+	require.Equal(t, []string{"relayer", "validator"}, approval.Keys)
+
+	_, err = cfg.VerifyApproval(armored, ApprovalOpExport, []string{"relayer"}, time.Now())
+	require.ErrorContains(t, err, "not export")
+	_, err = cfg.VerifyApproval(armored, ApprovalOpDelete, []string{"relayer", "faucet"}, time.Now())
+	require.ErrorContains(t, err, "doesn't cover key faucet")
+	_, err = cfg.VerifyApproval(armored, ApprovalOpDelete, []string{"relayer"}, time.Now().Add(2*time.Hour))
+	require.ErrorContains(t, err, "expired")
+
+	other, err := ApproveKeyOperation(kr, "other", ApprovalOpDelete, []string{"relayer"}, time.Hour)
+	require.NoError(t, err)
+	_, err = cfg.VerifyApproval(other, ApprovalOpDelete, []string{"relayer"}, time.Now())
+	require.ErrorContains(t, err, "is not an approver")
+
+	// the approved keys are covered by the signature
+	tampered, err := UnarmorKeyApproval(armored)
+	require.NoError(t, err)
+	tampered.Keys = append(tampered.Keys, "faucet")
+	tamperedArmored, err := ArmorKeyApproval(tampered)
+	require.NoError(t, err)
+	_, err = cfg.VerifyApproval(tamperedArmored, ApprovalOpDelete, []string{"faucet"}, time.Now())
+	require.ErrorContains(t, err, "invalid approval signature")
+
+	_, err = ApproveKeyOperation(kr, "officer", "rename", []string{"relayer"}, time.Hour)
+	require.ErrorContains(t, err, "unknown operation")
+	_, err = ApproveKeyOperation(kr, "officer", ApprovalOpDelete, []string{"relayer"}, 48*time.Hour)
+	require.ErrorContains(t, err, "invalid approval ttl")
+}
+
+func TestRequireKeyApproval(t *testing.T) {
+	kr := keyring.NewInMemory(clienttestutil.MakeTestCodec(t))
+	officer, _, err := kr.NewMnemonic("officer", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+	addr, err := officer.GetAddress()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	clientCtx := client.Context{}.WithKeyringDir(dir)
+	newCmd := func(approvalFile string) *cobra.Command {
+		cmd := &cobra.Command{}
+		addApprovalFileFlag(cmd)
+		require.NoError(t, cmd.Flags().Set(flagApprovalFile, approvalFile))
+		return cmd
+	}
+
+	// dual control is disabled without config
+	approval, err := requireKeyApproval(newCmd(""), clientCtx, ApprovalOpDelete, []string{"validator"})
+	require.NoError(t, err)
+	require.Nil(t, approval)
+	require.NoError(t, approval.done(nil))
+
+	cfg, err := json.Marshal(DualControlConfig{Approvers: []string{addr.String()}, Operations: []string{ApprovalOpDelete}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DualControlConfigFileName), cfg, 0o600))
+
+	approval, err = requireKeyApproval(newCmd(""), clientCtx, ApprovalOpExport, []string{"validator"})
+	require.NoError(t, err)
+	require.Nil(t, approval)
+
+	_, err = requireKeyApproval(newCmd(""), clientCtx, ApprovalOpDelete, []string{"validator"})
+	require.ErrorContains(t, err, "requires an approval")
+
+	armored, err := ApproveKeyOperation(kr, "officer", ApprovalOpDelete, []string{"validator"}, time.Hour)
+	require.NoError(t, err)
+	approvalFile := filepath.Join(dir, "approval.asc")
+	require.NoError(t, os.WriteFile(approvalFile, []byte(armored), 0o600))
+
+	approval, err = requireKeyApproval(newCmd(approvalFile), clientCtx, ApprovalOpDelete, []string{"validator"})
+	require.NoError(t, err)
+	require.NoError(t, approval.done(nil))
+
+	// approvals are valid once
+	_, err = requireKeyApproval(newCmd(approvalFile), clientCtx, ApprovalOpDelete, []string{"validator"})
+	require.ErrorContains(t, err, "already used")
+
+	// even with whitespace around the armor
+	require.NoError(t, os.WriteFile(approvalFile, []byte("\n\n  "+armored+"\n\n"), 0o600))
+	_, err = requireKeyApproval(newCmd(approvalFile), clientCtx, ApprovalOpDelete, []string{"validator"})
+	require.ErrorContains(t, err, "already used")
+
+	bz, err := os.ReadFile(filepath.Join(dir, KeyAuditLogFileName))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(bz)), "\n")
+	require.Len(t, lines, 4)
+	var outcomes []string
+	for _, line := range lines {
+		var entry KeyAuditEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		require.Equal(t, ApprovalOpDelete, entry.Operation)
+		require.Equal(t, []string{"validator"}, entry.Keys)
+		outcomes = append(outcomes, entry.Outcome)
+	}
+	require.Equal(t, []string{auditOutcomeRejected, auditOutcomeSuccess, auditOutcomeRejected, auditOutcomeRejected}, outcomes)
+}
@@ -0,0 +1,429 @@
+package keys
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-sdk/client"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	sdkerrors "github.com/baron-chain/cosmos-sdk/types/errors"
+)
+
+const (
+	flagBackupDest     = "dest"
+	flagBackupInterval = "interval"
+	flagBackupKeep     = "keep"
+	flagBackupOnce     = "once"
+	flagPassphraseFile = "passphrase-file"
+	flagS3Endpoint     = "s3-endpoint"
+
+	blockTypeKeyringBackup = "BARON CHAIN KEYRING BACKUP"
+
+	keyringBackupVersion  = "1"
+	keyringBackupPrefix   = "keyring-backup-"
+	keyringBackupExt      = ".asc"
+	keyringBackupTimeFmt  = "20060102T150405Z"
+	defaultBackupInterval = 24 * time.Hour
+	minBackupInterval     = time.Minute
+	defaultBackupKeep     = 7
+)
+
+// KeyringBackup is the content of an encrypted keyring backup bundle. Private
+// keys are armored as by "keys export", encrypted with the passphrase of the
+// bundle, and the keys without a private key in the keyring, such as ledger,
+// offline and multisig keys, hold their armored public key.
+type KeyringBackup struct {
+	CreatedAt time.Time            `json:"created_at"`
+	Backend   string               `json:"backend"`
+	Keys      []KeyringBackupEntry `json:"keys"`
+}
+
+// KeyringBackupEntry is a key of a keyring backup.
+type KeyringBackupEntry struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Address      string `json:"address"`
+	PrivKeyArmor string `json:"priv_key_armor,omitempty"`
+	PubKeyArmor  string `json:"pub_key_armor,omitempty"`
+}
+
+// NewKeyringBackup returns the backup of all the keys of the keyring, sorted
+// by name, whose private keys are encrypted with the passphrase.
+func NewKeyringBackup(kr keyring.Keyring, passphrase string, createdAt time.Time) (KeyringBackup, error) {
+	records, err := kr.List()
+	if err != nil {
+		return KeyringBackup{}, err
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+
+	backup := KeyringBackup{CreatedAt: createdAt.UTC(), Backend: kr.Backend(), Keys: []KeyringBackupEntry{}}
+	for _, k := range records {
+		addr, err := k.GetAddress()
+		if err != nil {
+			return KeyringBackup{}, err
+		}
+
+		entry := KeyringBackupEntry{Name: k.Name, Type: k.GetType().String(), Address: addr.String()}
+		if k.GetType() == keyring.TypeLocal {
+			entry.PrivKeyArmor, err = kr.ExportPrivKeyArmor(k.Name, passphrase)
+		} else {
+			entry.PubKeyArmor, err = kr.ExportPubKeyArmor(k.Name)
+		}
+		if err != nil {
+			return KeyringBackup{}, fmt.Errorf("failed to export key %s: %w", k.Name, err)
+		}
+		backup.Keys = append(backup.Keys, entry)
+	}
+	return backup, nil
+}
+
+// EncryptArmorKeyringBackup encrypts the backup with a key derived from the
+// passphrase by the KDF of the crypto policy, and armors it.
+func EncryptArmorKeyringBackup(backup KeyringBackup, passphrase string) (string, error) {
+	bz, err := json.Marshal(backup)
+	if err != nil {
+		return "", err
+	}
+	return encryptArmor(blockTypeKeyringBackup, keyringBackupVersion, bz, passphrase)
+}
+
+// UnarmorDecryptKeyringBackup decrypts a backup armored by
+// EncryptArmorKeyringBackup.
+func UnarmorDecryptKeyringBackup(armored, passphrase string) (KeyringBackup, error) {
+	bz, err := unarmorDecrypt(armored, blockTypeKeyringBackup, keyringBackupVersion, passphrase)
+	if err != nil {
+		return KeyringBackup{}, err
+	}
+
+	var backup KeyringBackup
+	if err := json.Unmarshal(bz, &backup); err != nil {
+		return KeyringBackup{}, fmt.Errorf("invalid keyring backup: %w", err)
+	}
+	return backup, nil
+}
+
+// RestoreKeyringBackup imports the keys of the backup which are not in the
+// keyring yet, and returns their names. Existing keys are left untouched.
+func RestoreKeyringBackup(kr keyring.Keyring, backup KeyringBackup, passphrase string) ([]string, error) {
+	var restored []string
+	for _, entry := range backup.Keys {
+		if _, err := kr.Key(entry.Name); err == nil {
+			continue
+		} else if !errors.Is(err, sdkerrors.ErrKeyNotFound) {
+			return restored, err
+		}
+
+		var err error
+		if entry.PrivKeyArmor != "" {
+			err = kr.ImportPrivKey(entry.Name, entry.PrivKeyArmor, passphrase)
+		} else {
+			err = kr.ImportPubKey(entry.Name, entry.PubKeyArmor)
+		}
+		if err != nil {
+			return restored, fmt.Errorf("failed to restore key %s: %w", entry.Name, err)
+		}
+		restored = append(restored, entry.Name)
+	}
+	return restored, nil
+}
+
+// readPassphraseFile reads a passphrase from the first line of a file, so that
+// it doesn't need to be typed by an operator.
+func readPassphraseFile(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("--%s is required", flagPassphraseFile)
+	}
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase file: %w", err)
+	}
+
+	passphrase, _, _ := strings.Cut(string(bz), "\n")
+	passphrase = strings.TrimSuffix(passphrase, "\r")
+	if passphrase == "" {
+		return "", errors.New("the passphrase file is empty")
+	}
+	return passphrase, nil
+}
+
+// keyringAutoBackup writes encrypted backups of a keyring to a destination,
+// and rotates the old ones.
+type keyringAutoBackup struct {
+	kr         keyring.Keyring
+	dest       backupDestination
+	passphrase string
+	keep       int
+	now        func() time.Time
+}
+
+// run writes a backup, reads it back to check it decrypts to the backup of the
+// keyring, then deletes the oldest backups beyond the number to keep. Backups
+// are only rotated once the new one is verified.
+func (b *keyringAutoBackup) run(ctx context.Context) (string, error) {
+	now := b.now().UTC()
+	backup, err := NewKeyringBackup(b.kr, b.passphrase, now)
+	if err != nil {
+		return "", err
+	}
+	armored, err := EncryptArmorKeyringBackup(backup, b.passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	name := keyringBackupPrefix + now.Format(keyringBackupTimeFmt) + keyringBackupExt
+	if err := b.dest.Put(ctx, name, []byte(armored)); err != nil {
+		return "", fmt.Errorf("failed to write backup %s: %w", name, err)
+	}
+	if err := b.verify(ctx, name, backup); err != nil {
+		return "", fmt.Errorf("verification of backup %s failed: %w", name, err)
+	}
+
+	return name, b.rotate(ctx)
+}
+
+func (b *keyringAutoBackup) verify(ctx context.Context, name string, backup KeyringBackup) error {
+	armored, err := b.dest.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	read, err := UnarmorDecryptKeyringBackup(string(armored), b.passphrase)
+	if err != nil {
+		return err
+	}
+
+	expected, err := json.Marshal(backup)
+	if err != nil {
+		return err
+	}
+	actual, err := json.Marshal(read)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, actual) {
+		return errors.New("the backup read back differs from the one written")
+	}
+	return nil
+}
+
+// rotate deletes the oldest backups beyond the number to keep. Backup names
+// sort by creation time.
+func (b *keyringAutoBackup) rotate(ctx context.Context) error {
+	if b.keep <= 0 {
+		return nil
+	}
+
+	names, err := b.dest.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+	var backups []string
+	for _, name := range names {
+		if strings.HasPrefix(name, keyringBackupPrefix) && strings.HasSuffix(name, keyringBackupExt) {
+			backups = append(backups, name)
+		}
+	}
+	sort.Strings(backups)
+
+	for len(backups) > b.keep {
+		if err := b.dest.Delete(ctx, backups[0]); err != nil {
+			return fmt.Errorf("failed to delete backup %s: %w", backups[0], err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// AutoBackupCommand returns the command periodically backing up the keyring.
+func AutoBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autobackup",
+		Short: "Periodically write encrypted backups of the keyring",
+		Long: `Run a daemon writing an encrypted backup bundle of all the keys of the keyring at
+every --interval, to a local directory or to an S3 (or S3 compatible) bucket given as
+s3://<bucket>/<prefix>. S3 credentials and region are resolved by the AWS default
+credential chain.
+
+Private keys are exported as by "keys export" and the bundle is encrypted with the
+passphrase read from the first line of --passphrase-file, with the KDF of the crypto
+policy. Ledger, offline and multisig keys only hold their public key. Every backup is
+read back and decrypted once written, and only then are the oldest backups beyond
+--keep deleted. Failed backups are logged and retried at the next interval.
+
+Use a keyring backend which doesn't prompt for a passphrase, such as os or test, and
+restore a backup with "keys restore-backup". Under dual control of exports, the daemon
+requires the approval of the export of all the keys, "*", once when it starts. On
+validator hosts, run it as a systemd service, e.g.:
+
+    [Unit]
+    Description=Baron Chain keyring backup
+    After=network-online.target
+
+    [Service]
+    User=baron
+    ExecStart=/usr/local/bin/barond keys autobackup --keyring-backend os --dest s3://baron-backups/validator-1 --passphrase-file /etc/barond/backup-passphrase
+    Restart=on-failure
+
+    [Install]
+    WantedBy=multi-user.target`,
+		Example: `  barond keys autobackup --dest /var/backups/barond-keys --passphrase-file ~/.barond/backup-passphrase
+  barond keys autobackup --interval 6h --keep 28 --dest s3://baron-backups/validator-1 --passphrase-file /etc/barond/backup-passphrase
+  barond keys autobackup --once --dest /mnt/usb --passphrase-file ~/.barond/backup-passphrase`,
+		Args: cobra.NoArgs,
+		RunE: runAutoBackupCmd,
+	}
+
+	cmd.Flags().String(flagBackupDest, "", "Directory or s3://<bucket>/<prefix> to write backups to")
+	cmd.Flags().Duration(flagBackupInterval, defaultBackupInterval, "Interval between backups")
+	cmd.Flags().Int(flagBackupKeep, defaultBackupKeep, "Number of backups to keep, 0 to keep all of them")
+	cmd.Flags().Bool(flagBackupOnce, false, "Write a single backup and exit")
+	cmd.Flags().String(flagPassphraseFile, "", "File whose first line is the passphrase encrypting the backups")
+	cmd.Flags().String(flagS3Endpoint, "", "Endpoint of an S3 compatible service")
+	_ = cmd.MarkFlagRequired(flagBackupDest)
+	_ = cmd.MarkFlagRequired(flagPassphraseFile)
+	addApprovalFileFlag(cmd)
+
+	return cmd
+}
+
+func runAutoBackupCmd(cmd *cobra.Command, _ []string) error {
+	clientCtx, err := client.GetClientQueryContext(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to get client context: %w", err)
+	}
+
+	interval, _ := cmd.Flags().GetDuration(flagBackupInterval)
+	if interval < minBackupInterval {
+		return fmt.Errorf("--%s must be at least %s, got %s", flagBackupInterval, minBackupInterval, interval)
+	}
+	keep, _ := cmd.Flags().GetInt(flagBackupKeep)
+	if keep < 0 {
+		return fmt.Errorf("--%s can't be negative, got %d", flagBackupKeep, keep)
+	}
+
+	passphraseFile, _ := cmd.Flags().GetString(flagPassphraseFile)
+	passphrase, err := readPassphraseFile(passphraseFile)
+	if err != nil {
+		return err
+	}
+
+	destFlag, _ := cmd.Flags().GetString(flagBackupDest)
+	endpoint, _ := cmd.Flags().GetString(flagS3Endpoint)
+	dest, err := newBackupDestination(destFlag, endpoint)
+	if err != nil {
+		return err
+	}
+
+	b := &keyringAutoBackup{
+		kr:         clientCtx.Keyring,
+		dest:       dest,
+		passphrase: passphrase,
+		keep:       keep,
+		now:        time.Now,
+	}
+
+	// the backups export all the keys, including those added while running
+	approval, err := requireKeyApproval(cmd, clientCtx, ApprovalOpExport, []string{AllKeys})
+	if err != nil {
+		return err
+	}
+	once, _ := cmd.Flags().GetBool(flagBackupOnce)
+	return approval.done(runAutoBackup(cmd, b, destFlag, interval, once))
+}
+
+// runAutoBackup writes a single backup if once is set, or a backup at every
+// interval until the command is interrupted.
+func runAutoBackup(cmd *cobra.Command, b *keyringAutoBackup, destFlag string, interval time.Duration, once bool) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if once {
+		name, err := b.run(ctx)
+		if err != nil {
+			return err
+		}
+		cmd.PrintErrf("Wrote keyring backup %s to %s\n", name, destFlag)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cmd.PrintErrf("Backing up keyring to %s every %s\n", destFlag, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if name, err := b.run(ctx); err != nil {
+			cmd.PrintErrf("Keyring backup failed: %v\n", err)
+		} else {
+			cmd.PrintErrf("Wrote keyring backup %s\n", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RestoreBackupCommand returns the command restoring the keys of a keyring
+// backup.
+func RestoreBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore-backup <backup-file>",
+		Short: "Restore the keys of a keyring backup written by autobackup",
+		Long: `Decrypt a keyring backup bundle written by "keys autobackup" with the passphrase read
+from the first line of --passphrase-file, and import its keys which are not in the
+keyring yet. Existing keys are left untouched.`,
+		Example: "  barond keys restore-backup keyring-backup-20261017T000000Z.asc --passphrase-file ~/.barond/backup-passphrase",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get client context: %w", err)
+			}
+
+			passphraseFile, _ := cmd.Flags().GetString(flagPassphraseFile)
+			passphrase, err := readPassphraseFile(passphraseFile)
+			if err != nil {
+				return err
+			}
+
+			armored, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read backup: %w", err)
+			}
+			backup, err := UnarmorDecryptKeyringBackup(string(armored), passphrase)
+			if err != nil {
+				return err
+			}
+
+			restored, err := RestoreKeyringBackup(clientCtx.Keyring, backup, passphrase)
+			for _, name := range restored {
+				cmd.PrintErrf("Restored key %s\n", name)
+			}
+			if err != nil {
+				return err
+			}
+			cmd.PrintErrf("Restored %d of the %d keys of the backup of %s\n", len(restored), len(backup.Keys), backup.CreatedAt.Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagPassphraseFile, "", "File whose first line is the passphrase of the backup")
+	_ = cmd.MarkFlagRequired(flagPassphraseFile)
+
+	return enforceKeyNamespaces(cmd)
+}
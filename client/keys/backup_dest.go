@@ -0,0 +1,198 @@
+package keys
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const s3Scheme = "s3://"
+
+// backupDestination stores keyring backups by name.
+type backupDestination interface {
+	Put(ctx context.Context, name string, body []byte) error
+	Get(ctx context.Context, name string) ([]byte, error)
+	Delete(ctx context.Context, name string) error
+	List(ctx context.Context) ([]string, error)
+}
+
+// newBackupDestination returns the destination of the --dest flag, a local
+// directory or s3://<bucket>/<prefix>.
+func newBackupDestination(dest, s3Endpoint string) (backupDestination, error) {
+	if dest == "" {
+		return nil, fmt.Errorf("--%s is required", flagBackupDest)
+	}
+	if !strings.HasPrefix(dest, s3Scheme) {
+		if s3Endpoint != "" {
+			return nil, fmt.Errorf("--%s requires an s3:// --%s", flagS3Endpoint, flagBackupDest)
+		}
+		return newDirBackupDestination(dest)
+	}
+
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(dest, s3Scheme), "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid --%s %q: missing bucket", flagBackupDest, dest)
+	}
+	return newS3BackupDestination(bucket, prefix, s3Endpoint)
+}
+
+// dirBackupDestination stores backups as files of a local directory, only
+// accessible to the current user.
+type dirBackupDestination struct {
+	dir string
+}
+
+var _ backupDestination = dirBackupDestination{}
+
+func newDirBackupDestination(dir string) (dirBackupDestination, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return dirBackupDestination{}, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	return dirBackupDestination{dir: dir}, nil
+}
+
+// Put writes the backup to a temporary file, synced to disk, which is renamed
+// to its name, so that backups are never partially written.
+func (d dirBackupDestination) Put(_ context.Context, name string, body []byte) error {
+	f, err := os.CreateTemp(d.dir, "."+name+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(d.dir, name))
+}
+
+func (d dirBackupDestination) Get(_ context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(d.dir, name))
+}
+
+func (d dirBackupDestination) Delete(_ context.Context, name string) error {
+	return os.Remove(filepath.Join(d.dir, name))
+}
+
+func (d dirBackupDestination) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// s3BackupDestination stores backups as objects of an S3 (or S3 compatible)
+// bucket, under a prefix.
+type s3BackupDestination struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+var _ backupDestination = (*s3BackupDestination)(nil)
+
+// newS3BackupDestination returns a destination storing backups in the bucket.
+// Credentials and region are resolved by the AWS default credential chain.
+func newS3BackupDestination(bucket, prefix, endpoint string) (*s3BackupDestination, error) {
+	awsCfg := aws.NewConfig()
+	if endpoint != "" {
+		// S3 compatible services usually don't support virtual hosted buckets
+		awsCfg = awsCfg.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsCfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 session: %w", err)
+	}
+
+	return &s3BackupDestination{client: s3.New(sess), bucket: bucket, prefix: strings.Trim(prefix, "/")}, nil
+}
+
+func (s *s3BackupDestination) key(name string) string {
+	return path.Join(s.prefix, name)
+}
+
+func (s *s3BackupDestination) Put(ctx context.Context, name string, body []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+		Body:   bytes.NewReader(body),
+	})
+	return err
+}
+
+func (s *s3BackupDestination) Get(ctx context.Context, name string) ([]byte, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (s *s3BackupDestination) Delete(ctx context.Context, name string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	return err
+}
+
+// List lists the names of the objects directly under the prefix.
+func (s *s3BackupDestination) List(ctx context.Context) ([]string, error) {
+	prefix := ""
+	if s.prefix != "" {
+		prefix = s.prefix + "/"
+	}
+
+	var names []string
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(s.bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			names = append(names, strings.TrimPrefix(aws.StringValue(obj.Key), prefix))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -0,0 +1,115 @@
+package keys
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clienttestutil "github.com/baron-chain/cosmos-sdk/client/testutil"
+	"github.com/baron-chain/cosmos-sdk/crypto/hd"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+	sdkerrors "github.com/baron-chain/cosmos-sdk/types/errors"
+)
+
+// corruptingDestination flips a byte of the backups written to it.
+type corruptingDestination struct {
+	dirBackupDestination
+}
+
+func (d corruptingDestination) Put(ctx context.Context, name string, body []byte) error {
+	corrupted := append([]byte(nil), body...)
+	corrupted[len(corrupted)/2] ^= 1
+	return d.dirBackupDestination.Put(ctx, name, corrupted)
+}
+
+func TestKeyringAutoBackup(t *testing.T) {
+	cdc := clienttestutil.MakeTestCodec(t)
+	kr, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, t.TempDir(), nil, cdc)
+	require.NoError(t, err)
+	for _, name := range []string{"validator", "relayer"} {
+		_, _, err := kr.NewMnemonic(name, keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+		require.NoError(t, err)
+	}
+
+	destDir := filepath.Join(t.TempDir(), "backups")
+	dest, err := newDirBackupDestination(destDir)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	b := &keyringAutoBackup{kr: kr, dest: dest, passphrase: "backup passphrase", keep: 2, now: func() time.Time { return now }}
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		name, err := b.run(context.Background())
+		require.NoError(t, err)
+		names = append(names, name)
+		now = now.Add(24 * time.Hour)
+	}
+	require.Equal(t, "keyring-backup-20261017T000000Z.asc", names[0])
+
+	// the oldest backup was rotated
+	listed, err := dest.List(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, names[1:], listed)
+	info, err := os.Stat(filepath.Join(destDir, names[2]))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	armored, err := dest.Get(context.Background(), names[2])
+	require.NoError(t, err)
+	backup, err := UnarmorDecryptKeyringBackup(string(armored), "backup passphrase")
+	require.NoError(t, err)
+	require.Equal(t, keyring.BackendTest, backup.Backend)
+	require.Len(t, backup.Keys, 2)
+	require.Equal(t, "relayer", backup.Keys[0].Name)
+	require.NotEmpty(t, backup.Keys[0].PrivKeyArmor)
+	_, err = UnarmorDecryptKeyringBackup(string(armored), "wrong passphrase")
+	require.ErrorIs(t, err, sdkerrors.ErrWrongPassword)
+
+	// the keys are restored into an empty keyring
+	restoredKr, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, t.TempDir(), nil, cdc)
+	require.NoError(t, err)
+	restored, err := RestoreKeyringBackup(restoredKr, backup, "backup passphrase")
+	require.NoError(t, err)
+	require.Equal(t, []string{"relayer", "validator"}, restored)
+	for _, name := range restored {
+		expected, err := kr.Key(name)
+		require.NoError(t, err)
+		actual, err := restoredKr.Key(name)
+		require.NoError(t, err)
+		require.Equal(t, expected.PubKey, actual.PubKey)
+	}
+	restored, err = RestoreKeyringBackup(restoredKr, backup, "backup passphrase")
+	require.NoError(t, err)
+	require.Empty(t, restored)
+
+	// a backup which doesn't read back is reported, and doesn't rotate the others
+	b.dest = corruptingDestination{dest}
+	_, err = b.run(context.Background())
+	require.ErrorContains(t, err, "verification of backup keyring-backup-20261020T000000Z.asc failed")
+	listed, err = dest.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, listed, 3)
+}
+
+func TestReadPassphraseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passphrase")
+	require.NoError(t, os.WriteFile(path, []byte("secret\r\nignored\n"), 0o600))
+	passphrase, err := readPassphraseFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "secret", passphrase)
+
+	require.NoError(t, os.WriteFile(path, []byte("\n"), 0o600))
+	_, err = readPassphraseFile(path)
+	require.ErrorContains(t, err, "empty")
+
+	_, err = newBackupDestination("s3:///prefix", "")
+	require.ErrorContains(t, err, "missing bucket")
+	_, err = newBackupDestination(t.TempDir(), "http://localhost:9000")
+	require.ErrorContains(t, err, "requires an s3://")
+}
@@ -0,0 +1,190 @@
+package keys
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagCopy       = "copy"
+	flagClearAfter = "clear-after"
+
+	defaultClearAfter = 45 * time.Second
+)
+
+// Clipboard is the system clipboard.
+type Clipboard interface {
+	Copy(text string) error
+	Paste() (string, error)
+	Clear() error
+}
+
+func addCopyFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(flagCopy, false, "Copy the address to the system clipboard, with --address, and clear it after --clear-after")
+	cmd.Flags().Duration(flagClearAfter, defaultClearAfter, "Clear the clipboard after this duration, if it still holds the copied address (0 keeps it)")
+}
+
+// commandClipboard drives the system clipboard with the commands of the
+// platform, e.g. pbcopy and pbpaste on macOS.
+type commandClipboard struct {
+	copyCmd  []string
+	pasteCmd []string
+	// clearCmd clears the clipboard, which is otherwise cleared by copying
+	// an empty text
+	clearCmd []string
+}
+
+var _ Clipboard = commandClipboard{}
+
+// systemClipboard returns the clipboard of the platform: pbcopy on macOS, clip
+// and PowerShell on Windows, and wl-clipboard on Wayland or xclip or xsel on
+// X11 elsewhere.
+func systemClipboard() (Clipboard, error) {
+	return detectClipboard(runtime.GOOS, os.Getenv, exec.LookPath)
+}
+
+func detectClipboard(goos string, getenv func(string) string, lookPath func(string) (string, error)) (Clipboard, error) {
+	has := func(name string) bool {
+		_, err := lookPath(name)
+		return err == nil
+	}
+
+	switch goos {
+	case "darwin":
+		return commandClipboard{copyCmd: []string{"pbcopy"}, pasteCmd: []string{"pbpaste"}}, nil
+
+	case "windows":
+		return commandClipboard{
+			copyCmd:  []string{"clip"},
+			pasteCmd: []string{"powershell", "-NoProfile", "-Command", "Get-Clipboard"},
+			clearCmd: []string{"powershell", "-NoProfile", "-Command", "Set-Clipboard -Value $null"},
+		}, nil
+	}
+
+	if getenv("WAYLAND_DISPLAY") != "" && has("wl-copy") && has("wl-paste") {
+		return commandClipboard{
+			copyCmd:  []string{"wl-copy"},
+			pasteCmd: []string{"wl-paste", "--no-newline"},
+			clearCmd: []string{"wl-copy", "--clear"},
+		}, nil
+	}
+	if getenv("DISPLAY") != "" {
+		switch {
+		case has("xclip"):
+			return commandClipboard{
+				copyCmd:  []string{"xclip", "-selection", "clipboard"},
+				pasteCmd: []string{"xclip", "-selection", "clipboard", "-o"},
+				clearCmd: []string{"xclip", "-selection", "clipboard", "-i", os.DevNull},
+			}, nil
+		case has("xsel"):
+			return commandClipboard{
+				copyCmd:  []string{"xsel", "--clipboard", "--input"},
+				pasteCmd: []string{"xsel", "--clipboard", "--output"},
+				clearCmd: []string{"xsel", "--clipboard", "--clear"},
+			}, nil
+		}
+	}
+	return nil, errors.New("no clipboard available, install wl-clipboard on Wayland, or xclip or xsel on X11")
+}
+
+func (c commandClipboard) Copy(text string) error {
+	return runClipboardCmd(c.copyCmd, strings.NewReader(text), nil)
+}
+
+func (c commandClipboard) Paste() (string, error) {
+	var out bytes.Buffer
+	if err := runClipboardCmd(c.pasteCmd, nil, &out); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out.String(), "\r\n"), nil
+}
+
+func (c commandClipboard) Clear() error {
+	if len(c.clearCmd) == 0 {
+		return c.Copy("")
+	}
+	return runClipboardCmd(c.clearCmd, nil, nil)
+}
+
+func runClipboardCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	// the copy commands of X11 and Wayland fork a process serving the
+	// clipboard, which inherits stderr: Run would wait for it to exit if
+	// stderr was a pipe, so it is written to a file
+	stderr, err := os.CreateTemp("", "clipboard-stderr")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(stderr.Name())
+	defer stderr.Close()
+
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // fixed clipboard commands
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		msg, _ := os.ReadFile(stderr.Name())
+		return fmt.Errorf("%s failed: %w: %s", args[0], err, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// copyWithAutoClear copies text to the clipboard, waits for clearAfter, or
+// for ctx to be done, and then clears the clipboard, unless something else
+// was copied meanwhile. A zero clearAfter leaves the text in the clipboard.
+func copyWithAutoClear(ctx context.Context, cb Clipboard, text string, clearAfter time.Duration, w io.Writer) error {
+	if err := cb.Copy(text); err != nil {
+		return fmt.Errorf("failed to copy to the clipboard: %w", err)
+	}
+	if clearAfter <= 0 {
+		fmt.Fprintln(w, "Copied to the clipboard")
+		return nil
+	}
+
+	fmt.Fprintf(w, "Copied to the clipboard, it will be cleared in %s\n", clearAfter)
+	timer := time.NewTimer(clearAfter)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	if current, err := cb.Paste(); err == nil && current != text {
+		return nil
+	}
+	if err := cb.Clear(); err != nil {
+		return fmt.Errorf("failed to clear the clipboard: %w", err)
+	}
+	fmt.Fprintln(w, "Clipboard cleared")
+	return nil
+}
+
+// copyAddress copies the address to the system clipboard, with the flags of
+// the command. The clipboard is cleared early on interrupt.
+func copyAddress(cmd *cobra.Command, address string) error {
+	cb, err := systemClipboard()
+	if err != nil {
+		return err
+	}
+	clearAfter, _ := cmd.Flags().GetDuration(flagClearAfter)
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return copyWithAutoClear(ctx, cb, address, clearAfter, cmd.ErrOrStderr())
+}
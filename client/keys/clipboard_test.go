@@ -0,0 +1,117 @@
+package keys
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClipboard struct {
+	text    string
+	cleared bool
+}
+
+func (c *fakeClipboard) Copy(text string) error { c.text = text; return nil }
+func (c *fakeClipboard) Paste() (string, error) { return c.text, nil }
+func (c *fakeClipboard) Clear() error           { c.text, c.cleared = "", true; return nil }
+
+func TestDetectClipboard(t *testing.T) {
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	testCases := []struct {
+		name    string
+		goos    string
+		env     map[string]string
+		bins    []string
+		expCopy string
+	}{
+		{"macOS", "darwin", nil, nil, "pbcopy"},
+		{"windows", "windows", nil, nil, "clip"},
+		{"wayland", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, []string{"wl-copy", "wl-paste", "xclip"}, "wl-copy"},
+		{"xwayland without wl-clipboard", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, []string{"xclip"}, "xclip"},
+		{"x11 xsel", "freebsd", map[string]string{"DISPLAY": ":0"}, []string{"xsel"}, "xsel"},
+		{"headless", "linux", nil, []string{"xclip"}, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cb, err := detectClipboard(tc.goos, env(tc.env), installed(tc.bins...))
+			if tc.expCopy == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expCopy, cb.(commandClipboard).copyCmd[0])
+			if tc.goos != "darwin" && tc.goos != "windows" {
+				require.NotEmpty(t, cb.(commandClipboard).clearCmd)
+			}
+		})
+	}
+}
+
+func TestRunClipboardCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+
+	// like xclip, the command leaves a process holding stderr, which must
+	// not be waited for
+	start := time.Now()
+	require.NoError(t, runClipboardCmd([]string{"sh", "-c", "sleep 5 >/dev/null & exit 0"}, nil, nil))
+	require.Less(t, time.Since(start), 4*time.Second)
+
+	err := runClipboardCmd([]string{"sh", "-c", "echo no display >&2; exit 1"}, nil, nil)
+	require.ErrorContains(t, err, "sh failed")
+	require.ErrorContains(t, err, "no display")
+}
+
+func TestCopyWithAutoClear(t *testing.T) {
+	ctx := context.Background()
+	const addr = "baron1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+
+	cb := &fakeClipboard{}
+	require.NoError(t, copyWithAutoClear(ctx, cb, addr, time.Millisecond, io.Discard))
+	require.True(t, cb.cleared)
+	require.Empty(t, cb.text)
+
+	// a zero duration keeps the address
+	cb = &fakeClipboard{}
+	require.NoError(t, copyWithAutoClear(ctx, cb, addr, 0, io.Discard))
+	require.False(t, cb.cleared)
+	require.Equal(t, addr, cb.text)
+
+	// the clipboard is cleared early when interrupted
+	cb = &fakeClipboard{}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.NoError(t, copyWithAutoClear(cancelled, cb, addr, time.Hour, io.Discard))
+	require.True(t, cb.cleared)
+}
+
+// changingClipboard simulates the user copying something else while the
+// address waits to be cleared.
+type changingClipboard struct{ fakeClipboard }
+
+func (c *changingClipboard) Paste() (string, error) { return "something else", nil }
+
+func TestCopyWithAutoClearKeepsNewContent(t *testing.T) {
+	cb := &changingClipboard{}
+	require.NoError(t, copyWithAutoClear(context.Background(), cb, "baron1...", time.Millisecond, io.Discard))
+	require.False(t, cb.cleared)
+}
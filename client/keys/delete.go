@@ -2,7 +2,15 @@ package keys
 //BC MOD
 import (
 	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
 
+	isatty "github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 
 	"github.com/cosmos/cosmos-sdk/client"
@@ -11,61 +19,218 @@ import (
 )
 
 const (
-	flagYes   = "yes"
-	flagForce = "force"
+	flagYes        = "yes"
+	flagForce      = "force"
+	flagAllWithTag = "all-with-tag"
 )
 
+// inputIsInteractive reports whether the confirmation is read from a terminal.
+var inputIsInteractive = func(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+}
+
 // DeleteKeyCommand deletes a key from the key store.
 func DeleteKeyCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete <name>...",
+		Use:   "delete [<name-or-pattern>...]",
 		Short: "Delete the given keys",
 		Long: `Delete keys from the Keybase backend.
 
+Keys are selected by name, by pattern such as test-*, as accepted by path.Match, and
+with --all-with-tag by label, as read from the labels file of the inventory command.
+A tag is either key=value, or key to match any value, and keys must have all the given
+tags. The selected keys are listed and deleted after a single confirmation, which must
+be given with --yes when the input is not a terminal. When dual control is enabled,
+the deletion requires an approval of the selected keys, given with --approval-file,
+as signed by the approve command.
+
 Note that removing offline or ledger keys will remove
 only the public key references stored locally, i.e.
 private keys stored in a ledger device cannot be deleted with the CLI.
 `,
-		Args: cobra.MinimumNArgs(1),
+		Example: `  barond keys delete relayer
+  barond keys delete test-* --yes
+  barond keys delete --all-with-tag env=devnet --yes`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if tags, _ := cmd.Flags().GetStringArray(flagAllWithTag); len(args) == 0 && len(tags) == 0 {
+				return fmt.Errorf("requires at least one key name or pattern, or --%s", flagAllWithTag)
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			buf := bufio.NewReader(cmd.InOrStdin())
 			clientCtx, err := client.GetClientQueryContext(cmd)
 			if err != nil {
 				return err
 			}
 
-			for _, name := range args {
-				k, err := clientCtx.Keyring.Key(name)
-				if err != nil {
+			tags, _ := cmd.Flags().GetStringArray(flagAllWithTag)
+			var labels map[string]map[string]string
+			if len(tags) > 0 {
+				labelsFile, _ := cmd.Flags().GetString(flagLabels)
+				if labels, err = loadKeyLabels(labelsFile, clientCtx.KeyringDir); err != nil {
 					return err
 				}
+			}
 
-				// confirm deletion, unless -y is passed
-				if skip, _ := cmd.Flags().GetBool(flagYes); !skip {
-					if yes, err := input.GetConfirmation("Key reference will be deleted. Continue?", buf, cmd.ErrOrStderr()); err != nil {
-						return err
-					} else if !yes {
-						continue
-					}
-				}
+			records, err := selectKeysToDelete(clientCtx.Keyring, args, tags, labels)
+			if err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				cmd.PrintErrln(localize(cmd, msgDeleteNoMatch))
+				return nil
+			}
 
-				if err := clientCtx.Keyring.Delete(name); err != nil {
-					return err
+			cmd.PrintErrln(localize(cmd, msgDeleteList))
+			for _, k := range records {
+				addr, _ := k.GetAddress()
+				cmd.PrintErrf("  %s\t%s\t%s\n", k.Name, k.GetType(), addr)
+			}
+
+			names := make([]string, 0, len(records))
+			for _, k := range records {
+				names = append(names, k.Name)
+			}
+			approval, err := requireKeyApproval(cmd, clientCtx, ApprovalOpDelete, names)
+			if err != nil {
+				return err
+			}
+
+			// confirm deletion, unless -y is passed
+			if skip, _ := cmd.Flags().GetBool(flagYes); !skip {
+				if !inputIsInteractive(cmd.InOrStdin()) {
+					return fmt.Errorf("input is not a terminal, confirm the deletion with --%s", flagYes)
 				}
 
-				if k.GetType() == keyring.TypeLedger || k.GetType() == keyring.TypeOffline {
-					cmd.PrintErrln("Public key reference deleted")
-					continue
+				buf := bufio.NewReader(cmd.InOrStdin())
+				prompt := localize(cmd, msgDeleteConfirm, len(records))
+				if yes, err := input.GetConfirmation(prompt, buf, cmd.ErrOrStderr()); err != nil {
+					return err
+				} else if !yes {
+					return nil
 				}
-				cmd.PrintErrln("Key deleted forever (uh oh!)")
 			}
 
-			return nil
+			return approval.done(deleteKeys(cmd, clientCtx.Keyring, records))
 		},
 	}
 
-	cmd.Flags().BoolP(flagYes, "y", false, "Skip confirmation prompt when deleting offline or ledger key references")
+	cmd.Flags().BoolP(flagYes, "y", false, "Skip confirmation prompt, required when the input is not a terminal")
 	cmd.Flags().BoolP(flagForce, "f", false, "Remove the key unconditionally without asking for the passphrase. Deprecated.")
+	cmd.Flags().StringArray(flagAllWithTag, nil, "Delete all the keys with the given label, as key=value or key (repeatable)")
+	cmd.Flags().String(flagLabels, "", "Path of the JSON key labels file (default: <keyring-dir>/"+KeyLabelsFileName+")")
+	addApprovalFileFlag(cmd)
 
 	return cmd
 }
+
+func deleteKeys(cmd *cobra.Command, kr keyring.Keyring, records []*keyring.Record) error {
+	for _, k := range records {
+		if err := kr.Delete(k.Name); err != nil {
+			return err
+		}
+
+		if k.GetType() == keyring.TypeLedger || k.GetType() == keyring.TypeOffline {
+			cmd.PrintErrln(localize(cmd, msgDeletedReference, k.Name))
+			continue
+		}
+		cmd.PrintErrln(localize(cmd, msgDeletedForever, k.Name))
+	}
+
+	return nil
+}
+
+// selectKeysToDelete returns the records matching the given names, patterns or
+// tags, without duplicates. Names must exist and patterns must match at least
+// one key.
+func selectKeysToDelete(kr keyring.Keyring, args, tags []string, labels map[string]map[string]string) ([]*keyring.Record, error) {
+	var (
+		selected []*keyring.Record
+		seen     = make(map[string]bool)
+	)
+	add := func(k *keyring.Record) {
+		if !seen[k.Name] {
+			seen[k.Name] = true
+			selected = append(selected, k)
+		}
+	}
+
+	var all []*keyring.Record
+	listAll := func() ([]*keyring.Record, error) {
+		if all != nil {
+			return all, nil
+		}
+		records, err := kr.List()
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+		all = records
+		return all, nil
+	}
+
+	for _, arg := range args {
+		if !strings.ContainsAny(arg, `*?[\`) {
+			k, err := kr.Key(arg)
+			if err != nil {
+				return nil, err
+			}
+			add(k)
+			continue
+		}
+
+		if _, err := path.Match(arg, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+		}
+		records, err := listAll()
+		if err != nil {
+			return nil, err
+		}
+
+		matched := false
+		for _, k := range records {
+			if ok, _ := path.Match(arg, k.Name); ok {
+				matched = true
+				add(k)
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no keys match pattern %q", arg)
+		}
+	}
+
+	if len(tags) == 0 {
+		return selected, nil
+	}
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" || strings.HasPrefix(tag, "=") {
+			return nil, errors.New("invalid empty tag")
+		}
+	}
+
+	records, err := listAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range records {
+		if hasAllTags(labels[k.Name], tags) {
+			add(k)
+		}
+	}
+
+	return selected, nil
+}
+
+// hasAllTags reports whether labels have all the tags, given as key=value or
+// key.
+func hasAllTags(labels map[string]string, tags []string) bool {
+	for _, tag := range tags {
+		key, value, hasValue := strings.Cut(tag, "=")
+		v, ok := labels[key]
+		if !ok || (hasValue && v != value) {
+			return false
+		}
+	}
+	return true
+}
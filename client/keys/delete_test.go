@@ -3,6 +3,10 @@ package keys
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -64,7 +68,7 @@ func Test_runDeleteCmd(t *testing.T) {
 	})
 	err = cmd.Execute()
 	require.Error(t, err)
-	require.Equal(t, "EOF", err.Error())
+	require.Equal(t, "input is not a terminal, confirm the deletion with --yes", err.Error())
 
 	_, err = kb.Key(fakeKeyName1)
 	require.NoError(t, err)
@@ -95,3 +99,61 @@ func Test_runDeleteCmd(t *testing.T) {
 	_, err = kb.Key(fakeKeyName2)
 	require.Error(t, err) // Key2 is gone
 }
+
+func Test_runDeleteCmdBatch(t *testing.T) {
+	kbHome := t.TempDir()
+	cdc := clienttestutil.MakeTestCodec(t)
+	kb, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, kbHome, nil, cdc)
+	require.NoError(t, err)
+
+	for _, name := range []string{"test-1", "test-2", "relayer", "validator", "faucet"} {
+		_, _, err := kb.NewMnemonic(name, keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+		require.NoError(t, err)
+	}
+	labels := `{"relayer": {"env": "devnet", "role": "relayer"}, "validator": {"env": "prod"}, "faucet": {"env": "devnet"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(kbHome, KeyLabelsFileName), []byte(labels), 0o600))
+
+	clientCtx := client.Context{}.
+		WithKeyringDir(kbHome).
+		WithCodec(cdc)
+	ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+
+	runDelete := func(in string, args ...string) error {
+		cmd := DeleteKeyCommand()
+		cmd.Flags().AddFlagSet(Commands(kbHome).PersistentFlags())
+		testutil.ApplyMockIODiscardOutErr(cmd)
+		cmd.SetIn(strings.NewReader(in))
+		cmd.SetArgs(append(args,
+			fmt.Sprintf("--%s=%s", flags.FlagHome, kbHome),
+			fmt.Sprintf("--%s=%s", flags.FlagKeyringBackend, keyring.BackendTest),
+		))
+		return cmd.ExecuteContext(ctx)
+	}
+	exists := func(name string) bool {
+		_, err := kb.Key(name)
+		return err == nil
+	}
+
+	require.Error(t, runDelete(""))
+	require.EqualError(t, runDelete("", "nokey-*", "--yes"), `no keys match pattern "nokey-*"`)
+	require.Error(t, runDelete("", "test-[", "--yes"))
+
+	// a single confirmation is asked for all the keys
+	interactive := inputIsInteractive
+	inputIsInteractive = func(io.Reader) bool { return true }
+	t.Cleanup(func() { inputIsInteractive = interactive })
+
+	require.NoError(t, runDelete("n\n", "test-*"))
+	require.True(t, exists("test-1"))
+	require.NoError(t, runDelete("y\n", "test-*", "relayer"))
+	require.False(t, exists("test-1"))
+	require.False(t, exists("test-2"))
+	require.False(t, exists("relayer"))
+
+	// tags select the keys having all of them
+	require.NoError(t, runDelete("", "--all-with-tag", "env=devnet", "--all-with-tag", "role", "--yes"))
+	require.True(t, exists("faucet"))
+	require.NoError(t, runDelete("", "--all-with-tag", "env=devnet", "--yes"))
+	require.False(t, exists("faucet"))
+	require.True(t, exists("validator"))
+}
@@ -0,0 +1,260 @@
+package keys
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/baron-chain/cometbft-bc/libs/cli"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagSamples      = "samples"
+	flagForceEntropy = "force-entropy"
+
+	// EntropySourceSystem is the name of the operating system entropy source.
+	EntropySourceSystem = "system"
+	// EntropySourceQuantumSafe is the name of the system entropy source after
+	// the quantum-safe enhancement applied by the mnemonic command.
+	EntropySourceQuantumSafe = "quantum-safe"
+
+	// EntropyHealthThreshold is the p-value under which a statistical test is
+	// considered failed. It is far below the usual 0.01 significance level so
+	// that a healthy source practically never blocks key generation.
+	EntropyHealthThreshold = 1e-6
+
+	defaultEntropySampleSize = 4096
+	minEntropySampleSize     = 1280 // at least 5 expected occurrences per byte value
+	enhancedEntropyBlockSize = 32
+)
+
+// ErrEntropyUnhealthy is returned when the entropy health check fails.
+var ErrEntropyUnhealthy = errors.New("entropy source failed the health check")
+
+// EntropyTestResult is the outcome of a single statistical test.
+type EntropyTestResult struct {
+	Name   string  `json:"name"`
+	PValue float64 `json:"p_value"`
+	Passed bool    `json:"passed"`
+}
+
+// EntropyReport gathers the statistical test results for one entropy source.
+type EntropyReport struct {
+	Source  string              `json:"source"`
+	Bytes   int                 `json:"bytes"`
+	Results []EntropyTestResult `json:"results"`
+}
+
+// Passed returns true if every test of the report passed.
+func (r EntropyReport) Passed() bool {
+	for _, res := range r.Results {
+		if !res.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// EntropyCheckCommand runs the entropy health check and prints its report.
+func EntropyCheckCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "entropy-check",
+		Short: "Run statistical health tests on the entropy used for key generation",
+		Long: `Sample the system entropy source and the quantum-safe enhanced entropy used by the
+mnemonic command, and run the monobit, runs and chi-square tests on both samples.
+
+A test fails when its p-value is below the health threshold. Key generation is refused
+while the check fails, unless --force-entropy is passed to the generating command.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			samples, _ := cmd.Flags().GetInt(flagSamples)
+			reports, err := RunEntropyHealthCheck(rand.Reader, samples)
+			if err != nil {
+				return err
+			}
+
+			output, _ := cmd.Flags().GetString(cli.OutputFlag)
+			if output == OutputFormatJSON {
+				if err := outputKeyData(cmd.OutOrStdout(), reports, OutputFormatJSON); err != nil {
+					return err
+				}
+			} else {
+				printEntropyReports(cmd, reports)
+			}
+
+			for _, report := range reports {
+				if !report.Passed() {
+					return ErrEntropyUnhealthy
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int(flagSamples, defaultEntropySampleSize, "Number of bytes to sample from each entropy source")
+	return cmd
+}
+
+func printEntropyReports(cmd *cobra.Command, reports []EntropyReport) {
+	for _, report := range reports {
+		cmd.Printf("Source: %s (%d bytes)\n", report.Source, report.Bytes)
+		for _, res := range report.Results {
+			status := "PASS"
+			if !res.Passed {
+				status = "FAIL"
+			}
+			cmd.Printf("  %-12s p=%.6f  %s\n", res.Name, res.PValue, status)
+		}
+	}
+}
+
+// RunEntropyHealthCheck samples n bytes from source, both raw and passed through
+// the quantum-safe enhancement, and runs the statistical tests on each sample.
+func RunEntropyHealthCheck(source io.Reader, n int) ([]EntropyReport, error) {
+	if n < minEntropySampleSize {
+		return nil, fmt.Errorf("entropy sample must be at least %d bytes, got %d", minEntropySampleSize, n)
+	}
+
+	sample := make([]byte, n)
+	if _, err := io.ReadFull(source, sample); err != nil {
+		return nil, fmt.Errorf("failed to read entropy: %w", err)
+	}
+
+	enhanced := make([]byte, 0, n)
+	for i := 0; i < n; i += enhancedEntropyBlockSize {
+		end := i + enhancedEntropyBlockSize
+		if end > n {
+			end = n
+		}
+		enhanced = append(enhanced, enhanceEntropyForQuantumSafety(sample[i:end])...)
+	}
+
+	return []EntropyReport{
+		CheckEntropy(EntropySourceSystem, sample),
+		CheckEntropy(EntropySourceQuantumSafe, enhanced),
+	}, nil
+}
+
+// CheckEntropy runs the monobit, runs and chi-square tests on sample.
+func CheckEntropy(source string, sample []byte) EntropyReport {
+	results := []EntropyTestResult{
+		newEntropyTestResult("monobit", monobitTest(sample)),
+		newEntropyTestResult("runs", runsTest(sample)),
+		newEntropyTestResult("chi-square", chiSquareTest(sample)),
+	}
+	return EntropyReport{Source: source, Bytes: len(sample), Results: results}
+}
+
+// ensureHealthyEntropy refuses to go on with key generation when the system
+// entropy fails the health check, unless forced.
+func ensureHealthyEntropy(cmd *cobra.Command) error {
+	if force, _ := cmd.Flags().GetBool(flagForceEntropy); force {
+		return nil
+	}
+
+	reports, err := RunEntropyHealthCheck(rand.Reader, defaultEntropySampleSize)
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		if !report.Passed() {
+			return fmt.Errorf("%w: %s source, run `keys entropy-check` for details or pass --%s to proceed anyway",
+				ErrEntropyUnhealthy, report.Source, flagForceEntropy)
+		}
+	}
+	return nil
+}
+
+func newEntropyTestResult(name string, pValue float64) EntropyTestResult {
+	return EntropyTestResult{Name: name, PValue: pValue, Passed: pValue >= EntropyHealthThreshold}
+}
+
+// monobitTest is the NIST SP 800-22 frequency test: the proportion of ones and
+// zeros in the bit sequence should be close to one half.
+func monobitTest(sample []byte) float64 {
+	n := float64(len(sample) * 8)
+	if n == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, b := range sample {
+		ones := float64(popcount(b))
+		sum += ones - (8 - ones)
+	}
+
+	return math.Erfc(math.Abs(sum) / math.Sqrt(n) / math.Sqrt2)
+}
+
+// runsTest is the NIST SP 800-22 runs test: the number of uninterrupted
+// sequences of identical bits should match the one of a random sequence.
+func runsTest(sample []byte) float64 {
+	n := len(sample) * 8
+	if n == 0 {
+		return 0
+	}
+
+	ones := 0
+	for _, b := range sample {
+		ones += popcount(b)
+	}
+
+	pi := float64(ones) / float64(n)
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(float64(n)) {
+		// the frequency prerequisite is not met, the runs test is not applicable
+		return 0
+	}
+
+	runs := 1
+	prev := bitAt(sample, 0)
+	for i := 1; i < n; i++ {
+		bit := bitAt(sample, i)
+		if bit != prev {
+			runs++
+		}
+		prev = bit
+	}
+
+	expected := 2 * float64(n) * pi * (1 - pi)
+	return math.Erfc(math.Abs(float64(runs)-expected) / (2 * math.Sqrt(2*float64(n)) * pi * (1 - pi)))
+}
+
+// chiSquareTest checks that byte values are uniformly distributed. The p-value
+// of the statistic (255 degrees of freedom) uses the Wilson-Hilferty approximation.
+func chiSquareTest(sample []byte) float64 {
+	if len(sample) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for _, b := range sample {
+		counts[b]++
+	}
+
+	expected := float64(len(sample)) / 256
+	chi2 := 0.0
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi2 += d * d / expected
+	}
+
+	k := 255.0
+	z := (math.Cbrt(chi2/k) - (1 - 2/(9*k))) / math.Sqrt(2/(9*k))
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
+
+func bitAt(sample []byte, i int) byte {
+	return (sample[i/8] >> (7 - uint(i%8))) & 1
+}
+
+func popcount(b byte) int {
+	count := 0
+	for ; b != 0; b &= b - 1 {
+		count++
+	}
+	return count
+}
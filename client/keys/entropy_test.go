@@ -0,0 +1,52 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEntropy(t *testing.T) {
+	random := make([]byte, defaultEntropySampleSize)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		sample []byte
+		passed bool
+	}{
+		{"random", random, true},
+		{"zeros", make([]byte, defaultEntropySampleSize), false},
+		{"ones", bytes.Repeat([]byte{0xff}, defaultEntropySampleSize), false},
+		{"alternating bits", bytes.Repeat([]byte{0xaa}, defaultEntropySampleSize), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckEntropy(EntropySourceSystem, tt.sample)
+			require.Len(t, report.Results, 3)
+			require.Equal(t, tt.passed, report.Passed())
+		})
+	}
+}
+
+func TestRunEntropyHealthCheck(t *testing.T) {
+	reports, err := RunEntropyHealthCheck(rand.Reader, defaultEntropySampleSize)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	require.Equal(t, EntropySourceSystem, reports[0].Source)
+	require.Equal(t, EntropySourceQuantumSafe, reports[1].Source)
+	for _, report := range reports {
+		require.Equal(t, defaultEntropySampleSize, report.Bytes)
+		require.True(t, report.Passed())
+	}
+
+	_, err = RunEntropyHealthCheck(rand.Reader, minEntropySampleSize-1)
+	require.Error(t, err)
+
+	_, err = RunEntropyHealthCheck(bytes.NewReader(make([]byte, 10)), defaultEntropySampleSize)
+	require.Error(t, err)
+}
@@ -0,0 +1,206 @@
+package keys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/99designs/keyring"
+	"github.com/cometbft/cometbft/libs/cli"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// The error kinds of the key commands, matched by errors.Is on the errors they
+// return. Each kind exits the process with its own code, see Error.ExitCode.
+var (
+	// ErrNotFound is returned when a key doesn't exist.
+	ErrNotFound = errors.New("key not found")
+	// ErrExists is returned when a key to create already exists.
+	ErrExists = errors.New("key already exists")
+	// ErrBackendLocked is returned when the keyring backend can't be
+	// unlocked, e.g. the OS credential store denied the access.
+	ErrBackendLocked = errors.New("keyring backend locked")
+	// ErrPolicyViolation is returned when the operation is denied by the
+	// crypto policy or the dual control of the keyring.
+	ErrPolicyViolation = errors.New("policy violation")
+	// ErrBadPassphrase is returned when the passphrase of a key or of the
+	// keyring is wrong.
+	ErrBadPassphrase = errors.New("bad passphrase")
+)
+
+// The process exit codes of the key commands. Other errors exit with code 1.
+const (
+	ExitCodeNotFound        = 3
+	ExitCodeExists          = 4
+	ExitCodeBackendLocked   = 5
+	ExitCodePolicyViolation = 6
+	ExitCodeBadPassphrase   = 7
+
+	exitCodeOther = 1
+)
+
+// keyErrorKinds are the error kinds with their categories in the JSON error
+// output and their exit codes.
+var keyErrorKinds = []struct {
+	kind     error
+	category string
+	exitCode int
+}{
+	{ErrNotFound, "not_found", ExitCodeNotFound},
+	{ErrExists, "exists", ExitCodeExists},
+	{ErrBackendLocked, "backend_locked", ExitCodeBackendLocked},
+	{ErrPolicyViolation, "policy_violation", ExitCodePolicyViolation},
+	{ErrBadPassphrase, "bad_passphrase", ExitCodeBadPassphrase},
+}
+
+// Error is returned by the key commands. Kind is one of ErrNotFound,
+// ErrExists, ErrBackendLocked, ErrPolicyViolation and ErrBadPassphrase, or nil
+// for other failures, and Err is the cause, both matched by errors.Is and
+// errors.As:
+//
+//	if errors.Is(err, keys.ErrNotFound) {
+//		// create the key
+//	}
+type Error struct {
+	Kind error
+	Err  error
+}
+
+// newKeyError returns an *Error of the given kind with a formatted cause.
+func newKeyError(kind error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the cause of the failure.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of the failure.
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+// Category returns the category of the failure in the JSON error output, e.g.
+// "not_found", or "other".
+func (e *Error) Category() string {
+	for _, k := range keyErrorKinds {
+		if e.Kind == k.kind {
+			return k.category
+		}
+	}
+	return "other"
+}
+
+// ExitCode returns the process exit code of the failure.
+func (e *Error) ExitCode() int {
+	for _, k := range keyErrorKinds {
+		if e.Kind == k.kind {
+			return k.exitCode
+		}
+	}
+	return exitCodeOther
+}
+
+// MarshalJSON encodes the failure as the JSON error output of the key
+// commands, e.g. {"error":{"category":"not_found","exit_code":3,"message":"..."}}.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	type jsonError struct {
+		Category string `json:"category"`
+		ExitCode int    `json:"exit_code"`
+		Message  string `json:"message"`
+	}
+	return json.Marshal(struct {
+		Error jsonError `json:"error"`
+	}{jsonError{Category: e.Category(), ExitCode: e.ExitCode(), Message: e.Error()}})
+}
+
+// keyringLockedMessages and badPassphraseMessages are the messages of the
+// keyring backend errors which have no sentinel error, e.g. of the PKCS#11
+// tokens and the Secret Service.
+var (
+	keyringLockedMessages = []string{"CKR_PIN_LOCKED", "is locked", "prompt dismissed", "interaction not allowed"}
+	badPassphraseMessages = []string{"CKR_PIN_INCORRECT", "too many failed passphrase attempts", "incorrect passphrase"}
+)
+
+// classifyKeyError returns err as an *Error, with the kind of its cause.
+func classifyKeyError(err error) *Error {
+	var keyErr *Error
+	if errors.As(err, &keyErr) && keyErr.Kind != nil {
+		if keyErr == err {
+			return keyErr
+		}
+		return &Error{Kind: keyErr.Kind, Err: err}
+	}
+
+	kind := func() error {
+		switch {
+		case errors.Is(err, sdkerrors.ErrKeyNotFound), errors.Is(err, keyring.ErrKeyNotFound):
+			return ErrNotFound
+		case errors.Is(err, sdkerrors.ErrWrongPassword):
+			return ErrBadPassphrase
+		case errors.Is(err, crypto.ErrPolicyViolation):
+			return ErrPolicyViolation
+		case errors.Is(err, keyring.ErrAccessDenied), errors.Is(err, keyring.ErrMetadataNeedsCredentials):
+			return ErrBackendLocked
+		}
+
+		msg := err.Error()
+		for _, m := range badPassphraseMessages {
+			if strings.Contains(msg, m) {
+				return ErrBadPassphrase
+			}
+		}
+		for _, m := range keyringLockedMessages {
+			if strings.Contains(msg, m) {
+				return ErrBackendLocked
+			}
+		}
+		// the keyring reports existing keys and addresses without sentinel
+		if strings.Contains(msg, "already exists") && !errors.Is(err, os.ErrExist) {
+			return ErrExists
+		}
+		return nil
+	}()
+	return &Error{Kind: kind, Err: err}
+}
+
+// handleKeyErrors makes the key commands under cmd return their errors as an
+// *Error, whose exit code the application exits with, and print them as JSON
+// to stderr under --output json instead of the usage.
+func handleKeyErrors(cmd *cobra.Command) {
+	for _, sub := range cmd.Commands() {
+		handleKeyErrors(sub)
+	}
+	if cmd.RunE == nil {
+		return
+	}
+
+	runE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := runE(cmd, args)
+		if err == nil {
+			return nil
+		}
+
+		keyErr := classifyKeyError(err)
+		cmd.SilenceUsage = true
+		if output, _ := cmd.Flags().GetString(cli.OutputFlag); output == OutputFormatJSON {
+			bz, jsonErr := json.Marshal(keyErr)
+			if jsonErr == nil {
+				cmd.SilenceErrors = true
+				fmt.Fprintln(cmd.ErrOrStderr(), string(bz))
+			}
+		}
+		return keyErr
+	}
+}
@@ -0,0 +1,88 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cometbft/cometbft/libs/cli"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func TestClassifyKeyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		kind     error
+		category string
+		exitCode int
+	}{
+		{"not found", fmt.Errorf("validator: %w", sdkerrors.ErrKeyNotFound), ErrNotFound, "not_found", ExitCodeNotFound},
+		{"exists", errors.New("public key already exists in keybase"), ErrExists, "exists", ExitCodeExists},
+		{"tagged", newKeyError(ErrExists, "key '%s' already exists", "validator"), ErrExists, "exists", ExitCodeExists},
+		{"wrapped tagged", fmt.Errorf("rename: %w", newKeyError(ErrNotFound, "key not found")), ErrNotFound, "not_found", ExitCodeNotFound},
+		{"wrong password", fmt.Errorf("import: %w", sdkerrors.ErrWrongPassword), ErrBadPassphrase, "bad_passphrase", ExitCodeBadPassphrase},
+		{"locked", errors.New("pkcs11: 0xA4: CKR_PIN_LOCKED"), ErrBackendLocked, "backend_locked", ExitCodeBackendLocked},
+		{"other", errors.New("failed to connect to the receiver"), nil, "other", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyErr := classifyKeyError(tt.err)
+			require.Equal(t, tt.err.Error(), keyErr.Error())
+			require.ErrorIs(t, keyErr, tt.err)
+			if tt.kind != nil {
+				require.ErrorIs(t, keyErr, tt.kind)
+			}
+			require.Equal(t, tt.category, keyErr.Category())
+			require.Equal(t, tt.exitCode, keyErr.ExitCode())
+		})
+	}
+}
+
+func TestHandleKeyErrors(t *testing.T) {
+	newCmd := func() (*cobra.Command, *bytes.Buffer) {
+		root := &cobra.Command{Use: "keys"}
+		root.PersistentFlags().String(cli.OutputFlag, OutputFormatText, "Output format")
+		root.AddCommand(&cobra.Command{
+			Use: "show",
+			RunE: func(*cobra.Command, []string) error {
+				return fmt.Errorf("validator: %w", sdkerrors.ErrKeyNotFound)
+			},
+		})
+		handleKeyErrors(root)
+
+		var stderr bytes.Buffer
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&stderr)
+		return root, &stderr
+	}
+
+	cmd, stderr := newCmd()
+	cmd.SetArgs([]string{"show"})
+	err := cmd.Execute()
+	var keyErr *Error
+	require.ErrorAs(t, err, &keyErr)
+	require.Equal(t, ExitCodeNotFound, keyErr.ExitCode())
+	require.NotContains(t, stderr.String(), "Usage:")
+
+	cmd, stderr = newCmd()
+	cmd.SetArgs([]string{"show", "--" + cli.OutputFlag, OutputFormatJSON})
+	require.ErrorIs(t, cmd.Execute(), ErrNotFound)
+
+	var out struct {
+		Error struct {
+			Category string `json:"category"`
+			ExitCode int    `json:"exit_code"`
+			Message  string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(stderr.Bytes(), &out))
+	require.Equal(t, "not_found", out.Error.Category)
+	require.Equal(t, ExitCodeNotFound, out.Error.ExitCode)
+	require.Contains(t, out.Error.Message, "validator")
+}
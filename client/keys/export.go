@@ -30,7 +30,22 @@ private key material is exported in an INSECURE fashion that is designed to
 allow users to import their keys in hot wallets. This feature is for advanced
 users only that are confident about how to handle private keys work and are
 FULLY AWARE OF THE RISKS. If you are unsure, you may want to do some research
-and export your keys in ASCII-armored encrypted format.`,
+and export your keys in ASCII-armored encrypted format.
+
+With --output-file, the armored key is written to a file instead, and with
+--chunk-size, in parts of that size listed with their checksums in a
+<output-file>.parts.json manifest, which is imported in place of the key file.
+An interrupted chunked export is resumed with --resume, which only rewrites the
+missing or corrupt parts.
+
+When dual control is enabled, the export requires an approval of the key, given
+with --approval-file, as signed by the approve command.
+
+With --webauthn, the armored key is also wrapped with a WebAuthn credential of a
+platform authenticator or security key, through a page served on localhost to
+open in a browser, so that importing it requires a user presence check by the
+authenticator in addition to the passphrase. A new credential is registered
+unless --webauthn-credential is given.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clientCtx, err := client.GetClientQueryContext(cmd)
@@ -41,37 +56,95 @@ and export your keys in ASCII-armored encrypted format.`,
 			unarmored, _ := cmd.Flags().GetBool(flagUnarmoredHex)
 			unsafe, _ := cmd.Flags().GetBool(flagUnsafe)
 
-			if unarmored && unsafe {
-				return exportUnsafeUnarmored(cmd, args[0], buf, clientCtx.Keyring)
-			} else if unarmored || unsafe {
+			if unarmored != unsafe {
 				return fmt.Errorf("the flags %s and %s must be used together", flagUnsafe, flagUnarmoredHex)
 			}
 
-			encryptPassword, err := input.GetPassword("Enter passphrase to encrypt the exported key:", buf)
+			approval, err := requireKeyApproval(cmd, clientCtx, ApprovalOpExport, args[:1])
 			if err != nil {
 				return err
 			}
 
-			armored, err := clientCtx.Keyring.ExportPrivKeyArmor(args[0], encryptPassword)
+			fileOpts, toFile, err := getExportFileOptions(cmd)
 			if err != nil {
 				return err
 			}
 
-			cmd.Println(armored)
-
-			return nil
+			if unarmored && unsafe {
+				if toFile {
+					return fmt.Errorf("--%s is only supported for armored exports", flagOutputFile)
+				}
+				if wrap, _ := cmd.Flags().GetBool(flagWebAuthn); wrap {
+					return fmt.Errorf("--%s is only supported for armored exports", flagWebAuthn)
+				}
+				return approval.done(exportUnsafeUnarmored(cmd, args[0], buf, clientCtx.Keyring))
+			}
+			if toFile {
+				return approval.done(exportArmoredToFile(cmd, args[0], buf, clientCtx.Keyring, fileOpts))
+			}
+			return approval.done(exportArmored(cmd, args[0], buf, clientCtx.Keyring))
 		},
 	}
 
 	cmd.Flags().Bool(flagUnarmoredHex, false, "Export unarmored hex privkey. Requires --unsafe.")
 	cmd.Flags().Bool(flagUnsafe, false, "Enable unsafe operations. This flag must be switched on along with all unsafe operation-specific options.")
+	addApprovalFileFlag(cmd)
+	addExportFileFlags(cmd)
+	addWebAuthnFlags(cmd, true)
 
 	return cmd
 }
 
+func exportArmored(cmd *cobra.Command, uid string, buf *bufio.Reader, kr keyring.Keyring) error {
+	armored, err := armorForExport(cmd, uid, buf, kr)
+	if err != nil {
+		return err
+	}
+
+	cmd.Println(armored)
+
+	return nil
+}
+
+func exportArmoredToFile(cmd *cobra.Command, uid string, buf *bufio.Reader, kr keyring.Keyring, opts exportFileOptions) error {
+	var (
+		path string
+		err  error
+	)
+	if staged := opts.stagedExport(); staged != "" {
+		// the staged key is already encrypted, the passphrase isn't needed
+		path, err = writeKeyParts(opts, staged, cmd.ErrOrStderr())
+	} else {
+		var armored string
+		if armored, err = armorForExport(cmd, uid, buf, kr); err != nil {
+			return err
+		}
+		path, err = writeKeyExport(opts, armored+"\n", cmd.ErrOrStderr())
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd.PrintErrf("Key %s exported, import it from %s\n", uid, path)
+	return nil
+}
+
+func armorForExport(cmd *cobra.Command, uid string, buf *bufio.Reader, kr keyring.Keyring) (string, error) {
+	encryptPassword, err := input.GetPassword("Enter passphrase to encrypt the exported key:", buf)
+	if err != nil {
+		return "", err
+	}
+
+	armored, err := kr.ExportPrivKeyArmor(uid, encryptPassword)
+	if err != nil {
+		return "", err
+	}
+	return wrapKeyWebAuthn(cmd, uid, armored)
+}
+
 func exportUnsafeUnarmored(cmd *cobra.Command, uid string, buf *bufio.Reader, kr keyring.Keyring) error {
 	// confirm deletion, unless -y is passed
-	if yes, err := input.GetConfirmation("WARNING: The private key will be exported as an unarmored hexadecimal string. USE AT YOUR OWN RISK. Continue?", buf, cmd.ErrOrStderr()); err != nil {
+	if yes, err := input.GetConfirmation(localize(cmd, msgExportUnarmoredWarn), buf, cmd.ErrOrStderr()); err != nil {
 		return err
 	} else if !yes {
 		return nil
@@ -0,0 +1,262 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagLocale = "locale"
+
+	// EnvLocale is the environment variable selecting the locale of the
+	// prompts and messages of the keys commands, when --locale is not given.
+	// LC_ALL, LC_MESSAGES and LANG are used next.
+	EnvLocale = "BARON_KEYS_LOCALE"
+
+	defaultLocale = "en"
+)
+
+// messageID identifies a localized prompt or message.
+type messageID string
+
+const (
+	msgOverrideExisting          messageID = "override-existing"
+	msgMnemonicImportant         messageID = "mnemonic-important"
+	msgMnemonicDisplay           messageID = "mnemonic-display"
+	msgMnemonicReenter           messageID = "mnemonic-reenter"
+	msgMnemonicVerified          messageID = "mnemonic-verified"
+	msgMnemonicChunk             messageID = "mnemonic-chunk"
+	msgMnemonicQuiz              messageID = "mnemonic-quiz"
+	msgMnemonicQuizFailed        messageID = "mnemonic-quiz-failed"
+	msgMnemonicPagerContinue     messageID = "mnemonic-pager-continue"
+	msgMnemonicEncryptPassphrase messageID = "mnemonic-encrypt-passphrase"
+	msgMnemonicEncryptRepeat     messageID = "mnemonic-encrypt-repeat"
+	msgMnemonicWritten           messageID = "mnemonic-written"
+	msgEntropyWarning            messageID = "entropy-warning"
+	msgEntropyPrompt             messageID = "entropy-prompt"
+	msgEntropyConfirm            messageID = "entropy-confirm"
+	msgDeleteNoMatch             messageID = "delete-no-match"
+	msgDeleteList                messageID = "delete-list"
+	msgDeleteConfirm             messageID = "delete-confirm"
+	msgDeletedReference          messageID = "deleted-reference"
+	msgDeletedForever            messageID = "deleted-forever"
+	msgExportUnarmoredWarn       messageID = "export-unarmored-warning"
+	msgRenameLedgerConfirm       messageID = "rename-ledger-confirm"
+	msgRenameOfflineConfirm      messageID = "rename-offline-confirm"
+	msgRenameConfirm             messageID = "rename-confirm"
+	msgRenameCancelled           messageID = "rename-cancelled"
+)
+
+// messageCatalogs holds the messages of every supported locale, as format
+// strings taking the same arguments in the same order as the English ones.
+// Confirmations are still answered with y or yes in every locale, as shown by
+// the [y/N] suffix of the prompts. Messages missing from a catalog fall back
+// to English.
+var messageCatalogs = map[string]map[messageID]string{
+	"en": {
+		msgOverrideExisting:          "override the existing name %s",
+		msgMnemonicImportant:         "**Important** write this mnemonic phrase in a safe place.\nIt is the only way to recover your account if you ever forget your password.",
+		msgMnemonicDisplay:           "Your quantum-safe mnemonic phrase (keep this secure):",
+		msgMnemonicReenter:           "Re-enter your mnemonic phrase to confirm it was recorded correctly:",
+		msgMnemonicVerified:          "Mnemonic verified.",
+		msgMnemonicChunk:             "Words %d to %d of %d:",
+		msgMnemonicQuiz:              "Enter word #%d to confirm it was recorded:",
+		msgMnemonicQuizFailed:        "Incorrect word, check the phrase and try again.",
+		msgMnemonicPagerContinue:     "Press enter once the phrase is recorded, the screen will be cleared.",
+		msgMnemonicEncryptPassphrase: "Enter passphrase to encrypt the mnemonic:",
+		msgMnemonicEncryptRepeat:     "Repeat the passphrase:",
+		msgMnemonicWritten:           "Encrypted mnemonic written to %s",
+		msgEntropyWarning:            "WARNING: User-provided entropy is not recommended for production use.",
+		msgEntropyPrompt:             "Please enter at least %d characters of entropy (more is better):",
+		msgEntropyConfirm:            "Confirm entropy input length: %d chars",
+		msgDeleteNoMatch:             "No keys match, nothing to delete",
+		msgDeleteList:                "The following keys will be deleted:",
+		msgDeleteConfirm:             "%d key reference(s) will be deleted. Continue?",
+		msgDeletedReference:          "%s: public key reference deleted",
+		msgDeletedForever:            "%s: key deleted forever (uh oh!)",
+		msgExportUnarmoredWarn:       "WARNING: The private key will be exported as an unarmored hexadecimal string. USE AT YOUR OWN RISK. Continue?",
+		msgRenameLedgerConfirm:       "Rename ledger key reference from '%s' to '%s'?",
+		msgRenameOfflineConfirm:      "Rename offline key reference from '%s' to '%s'?",
+		msgRenameConfirm:             "Rename key from '%s' to '%s'?",
+		msgRenameCancelled:           "Rename cancelled",
+	},
+	"es": {
+		msgOverrideExisting:          "sobrescribir el nombre existente %s",
+		msgMnemonicImportant:         "**Importante** guarde esta frase mnemotécnica en un lugar seguro.\nEs la única forma de recuperar su cuenta si olvida su contraseña.",
+		msgMnemonicDisplay:           "Su frase mnemotécnica resistente a la computación cuántica (manténgala segura):",
+		msgMnemonicReenter:           "Vuelva a introducir su frase mnemotécnica para confirmar que la anotó correctamente:",
+		msgMnemonicVerified:          "Frase mnemotécnica verificada.",
+		msgMnemonicChunk:             "Palabras %d a %d de %d:",
+		msgMnemonicQuiz:              "Introduzca la palabra n.º %d para confirmar que la anotó:",
+		msgMnemonicQuizFailed:        "Palabra incorrecta, revise la frase e inténtelo de nuevo.",
+		msgMnemonicPagerContinue:     "Pulse Intro cuando haya anotado la frase, la pantalla se borrará.",
+		msgMnemonicEncryptPassphrase: "Introduzca la contraseña para cifrar la frase mnemotécnica:",
+		msgMnemonicEncryptRepeat:     "Repita la contraseña:",
+		msgMnemonicWritten:           "Frase mnemotécnica cifrada escrita en %s",
+		msgEntropyWarning:            "ADVERTENCIA: no se recomienda usar entropía proporcionada por el usuario en producción.",
+		msgEntropyPrompt:             "Introduzca al menos %d caracteres de entropía (cuantos más, mejor):",
+		msgEntropyConfirm:            "Confirme la longitud de la entropía introducida: %d caracteres",
+		msgDeleteNoMatch:             "Ninguna clave coincide, no hay nada que eliminar",
+		msgDeleteList:                "Se eliminarán las siguientes claves:",
+		msgDeleteConfirm:             "Se eliminarán %d referencia(s) de clave. ¿Continuar?",
+		msgDeletedReference:          "%s: referencia de clave pública eliminada",
+		msgDeletedForever:            "%s: clave eliminada para siempre",
+		msgExportUnarmoredWarn:       "ADVERTENCIA: la clave privada se exportará como una cadena hexadecimal sin cifrar. ÚSELO BAJO SU PROPIO RIESGO. ¿Continuar?",
+		msgRenameLedgerConfirm:       "¿Renombrar la referencia de clave ledger de '%s' a '%s'?",
+		msgRenameOfflineConfirm:      "¿Renombrar la referencia de clave sin conexión de '%s' a '%s'?",
+		msgRenameConfirm:             "¿Renombrar la clave de '%s' a '%s'?",
+		msgRenameCancelled:           "Cambio de nombre cancelado",
+	},
+	"fr": {
+		msgOverrideExisting:          "remplacer le nom existant %s",
+		msgMnemonicImportant:         "**Important** notez cette phrase mnémonique en lieu sûr.\nC'est le seul moyen de récupérer votre compte si vous oubliez votre mot de passe.",
+		msgMnemonicDisplay:           "Votre phrase mnémonique résistante au quantique (conservez-la en sécurité) :",
+		msgMnemonicReenter:           "Saisissez à nouveau votre phrase mnémonique pour confirmer qu'elle a été correctement notée :",
+		msgMnemonicVerified:          "Phrase mnémonique vérifiée.",
+		msgMnemonicChunk:             "Mots %d à %d sur %d :",
+		msgMnemonicQuiz:              "Saisissez le mot n° %d pour confirmer qu'il a été noté :",
+		msgMnemonicQuizFailed:        "Mot incorrect, vérifiez la phrase et réessayez.",
+		msgMnemonicPagerContinue:     "Appuyez sur Entrée une fois la phrase notée, l'écran sera effacé.",
+		msgMnemonicEncryptPassphrase: "Saisissez la phrase de passe pour chiffrer la phrase mnémonique :",
+		msgMnemonicEncryptRepeat:     "Répétez la phrase de passe :",
+		msgMnemonicWritten:           "Phrase mnémonique chiffrée écrite dans %s",
+		msgEntropyWarning:            "AVERTISSEMENT : l'entropie fournie par l'utilisateur est déconseillée en production.",
+		msgEntropyPrompt:             "Saisissez au moins %d caractères d'entropie (plus il y en a, mieux c'est) :",
+		msgEntropyConfirm:            "Confirmez la longueur de l'entropie saisie : %d caractères",
+		msgDeleteNoMatch:             "Aucune clé ne correspond, rien à supprimer",
+		msgDeleteList:                "Les clés suivantes seront supprimées :",
+		msgDeleteConfirm:             "%d référence(s) de clé seront supprimées. Continuer ?",
+		msgDeletedReference:          "%s : référence de clé publique supprimée",
+		msgDeletedForever:            "%s : clé supprimée définitivement",
+		msgExportUnarmoredWarn:       "AVERTISSEMENT : la clé privée sera exportée en hexadécimal non chiffré. À VOS RISQUES ET PÉRILS. Continuer ?",
+		msgRenameLedgerConfirm:       "Renommer la référence de clé ledger '%s' en '%s' ?",
+		msgRenameOfflineConfirm:      "Renommer la référence de clé hors ligne '%s' en '%s' ?",
+		msgRenameConfirm:             "Renommer la clé '%s' en '%s' ?",
+		msgRenameCancelled:           "Renommage annulé",
+	},
+	"de": {
+		msgOverrideExisting:          "den vorhandenen Namen %s überschreiben",
+		msgMnemonicImportant:         "**Wichtig** bewahren Sie diese Mnemonic-Phrase an einem sicheren Ort auf.\nSie ist die einzige Möglichkeit, Ihr Konto wiederherzustellen, falls Sie Ihr Passwort vergessen.",
+		msgMnemonicDisplay:           "Ihre quantensichere Mnemonic-Phrase (sicher aufbewahren):",
+		msgMnemonicReenter:           "Geben Sie Ihre Mnemonic-Phrase erneut ein, um zu bestätigen, dass sie korrekt notiert wurde:",
+		msgMnemonicVerified:          "Mnemonic-Phrase bestätigt.",
+		msgMnemonicChunk:             "Wörter %d bis %d von %d:",
+		msgMnemonicQuiz:              "Geben Sie Wort Nr. %d ein, um zu bestätigen, dass es notiert wurde:",
+		msgMnemonicQuizFailed:        "Falsches Wort, prüfen Sie die Phrase und versuchen Sie es erneut.",
+		msgMnemonicPagerContinue:     "Drücken Sie die Eingabetaste, sobald die Phrase notiert ist. Der Bildschirm wird danach gelöscht.",
+		msgMnemonicEncryptPassphrase: "Passphrase zum Verschlüsseln der Mnemonic-Phrase eingeben:",
+		msgMnemonicEncryptRepeat:     "Passphrase wiederholen:",
+		msgMnemonicWritten:           "Verschlüsselte Mnemonic-Phrase nach %s geschrieben",
+		msgEntropyWarning:            "WARNUNG: Vom Benutzer eingegebene Entropie wird für den Produktivbetrieb nicht empfohlen.",
+		msgEntropyPrompt:             "Bitte geben Sie mindestens %d Zeichen Entropie ein (mehr ist besser):",
+		msgEntropyConfirm:            "Länge der eingegebenen Entropie bestätigen: %d Zeichen",
+		msgDeleteNoMatch:             "Keine passenden Schlüssel, nichts zu löschen",
+		msgDeleteList:                "Die folgenden Schlüssel werden gelöscht:",
+		msgDeleteConfirm:             "%d Schlüsselreferenz(en) werden gelöscht. Fortfahren?",
+		msgDeletedReference:          "%s: Referenz auf öffentlichen Schlüssel gelöscht",
+		msgDeletedForever:            "%s: Schlüssel endgültig gelöscht",
+		msgExportUnarmoredWarn:       "WARNUNG: Der private Schlüssel wird als unverschlüsselte Hexadezimalzeichenkette exportiert. AUF EIGENE GEFAHR. Fortfahren?",
+		msgRenameLedgerConfirm:       "Ledger-Schlüsselreferenz von '%s' in '%s' umbenennen?",
+		msgRenameOfflineConfirm:      "Offline-Schlüsselreferenz von '%s' in '%s' umbenennen?",
+		msgRenameConfirm:             "Schlüssel von '%s' in '%s' umbenennen?",
+		msgRenameCancelled:           "Umbenennen abgebrochen",
+	},
+	"zh": {
+		msgOverrideExisting:          "覆盖已存在的名称 %s",
+		msgMnemonicImportant:         "**重要** 请将此助记词记录在安全的地方。\n如果您忘记密码，这是恢复账户的唯一方式。",
+		msgMnemonicDisplay:           "您的抗量子助记词（请妥善保管）：",
+		msgMnemonicReenter:           "请再次输入助记词以确认已正确记录：",
+		msgMnemonicVerified:          "助记词已验证。",
+		msgMnemonicChunk:             "第 %d 至 %d 个单词，共 %d 个：",
+		msgMnemonicQuiz:              "请输入第 %d 个单词以确认已记录：",
+		msgMnemonicQuizFailed:        "单词不正确，请检查助记词后重试。",
+		msgMnemonicPagerContinue:     "记录好助记词后请按回车键，屏幕将被清除。",
+		msgMnemonicEncryptPassphrase: "请输入用于加密助记词的密码：",
+		msgMnemonicEncryptRepeat:     "请再次输入密码：",
+		msgMnemonicWritten:           "加密的助记词已写入 %s",
+		msgEntropyWarning:            "警告：不建议在生产环境中使用用户提供的熵。",
+		msgEntropyPrompt:             "请输入至少 %d 个字符的熵（越多越好）：",
+		msgEntropyConfirm:            "确认输入的熵长度：%d 个字符",
+		msgDeleteNoMatch:             "没有匹配的密钥，无需删除",
+		msgDeleteList:                "将删除以下密钥：",
+		msgDeleteConfirm:             "将删除 %d 个密钥引用。是否继续？",
+		msgDeletedReference:          "%s：公钥引用已删除",
+		msgDeletedForever:            "%s：密钥已永久删除",
+		msgExportUnarmoredWarn:       "警告：私钥将以未加密的十六进制字符串导出。风险自负。是否继续？",
+		msgRenameLedgerConfirm:       "将 ledger 密钥引用从 '%s' 重命名为 '%s'？",
+		msgRenameOfflineConfirm:      "将离线密钥引用从 '%s' 重命名为 '%s'？",
+		msgRenameConfirm:             "将密钥从 '%s' 重命名为 '%s'？",
+		msgRenameCancelled:           "已取消重命名",
+	},
+}
+
+// supportedLocales returns the locales with a message catalog, sorted.
+func supportedLocales() []string {
+	locales := make([]string, 0, len(messageCatalogs))
+	for locale := range messageCatalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// normalizeLocale returns the supported locale of a POSIX or BCP 47 locale
+// name such as es_ES.UTF-8 or zh-Hans, if any.
+func normalizeLocale(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexAny(name, ".@"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "c" || name == "posix" {
+		return defaultLocale, true
+	}
+
+	lang, _, _ := strings.Cut(strings.ReplaceAll(name, "_", "-"), "-")
+	if _, ok := messageCatalogs[lang]; ok {
+		return lang, true
+	}
+	return "", false
+}
+
+// localeOf returns the locale of the prompts and messages of the command,
+// from --locale, EnvLocale, then the POSIX locale variables, defaulting to
+// English when none of them is supported.
+func localeOf(cmd *cobra.Command) string {
+	if name, _ := cmd.Flags().GetString(flagLocale); name != "" {
+		if locale, ok := normalizeLocale(name); ok {
+			return locale
+		}
+		return defaultLocale
+	}
+
+	if locale, ok := normalizeLocale(os.Getenv(EnvLocale)); ok {
+		return locale
+	}
+	// the first set POSIX variable takes precedence, as in setlocale
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if name := os.Getenv(env); name != "" {
+			if locale, ok := normalizeLocale(name); ok {
+				return locale
+			}
+			break
+		}
+	}
+	return defaultLocale
+}
+
+// localize formats the message in the locale of the command. Messages
+// missing from the catalog of the locale are formatted in English.
+func localize(cmd *cobra.Command, id messageID, args ...interface{}) string {
+	format, ok := messageCatalogs[localeOf(cmd)][id]
+	if !ok {
+		format = messageCatalogs[defaultLocale][id]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
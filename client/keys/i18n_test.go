@@ -0,0 +1,56 @@
+package keys
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageCatalogs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	english := messageCatalogs[defaultLocale]
+
+	for locale, catalog := range messageCatalogs {
+		require.Len(t, catalog, len(english), "locale %s", locale)
+		for id, format := range catalog {
+			require.Contains(t, english, id, "locale %s", locale)
+			require.Equal(t, verbs.FindAllString(english[id], -1), verbs.FindAllString(format, -1), "locale %s, message %s", locale, id)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String(flagLocale, "", "")
+		return cmd
+	}
+	t.Setenv(EnvLocale, "")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "")
+
+	cmd := newCmd()
+	require.Equal(t, "Rename key from 'a' to 'b'?", localize(cmd, msgRenameConfirm, "a", "b"))
+
+	// the first set POSIX variable is used
+	t.Setenv("LANG", "de_DE.UTF-8")
+	require.Equal(t, "de", localeOf(cmd))
+	t.Setenv("LC_ALL", "C")
+	require.Equal(t, "en", localeOf(cmd))
+	t.Setenv("LC_ALL", "pt_BR.UTF-8")
+	require.Equal(t, "en", localeOf(cmd))
+
+	t.Setenv(EnvLocale, "zh-Hans")
+	require.Equal(t, "zh", localeOf(cmd))
+
+	require.NoError(t, cmd.Flags().Set(flagLocale, "es_ES"))
+	require.Equal(t, "Se eliminarán 2 referencia(s) de clave. ¿Continuar?", localize(cmd, msgDeleteConfirm, 2))
+	require.NoError(t, cmd.Flags().Set(flagLocale, "xx"))
+	require.Equal(t, "en", localeOf(cmd))
+
+	// commands without the flag use the environment
+	require.Equal(t, "zh", localeOf(&cobra.Command{}))
+}
@@ -0,0 +1,85 @@
+package keys
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const flagIfNotExists = "if-not-exists"
+
+func addIfNotExistsFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(flagIfNotExists, false, "Succeed without changes, printing the existing key in JSON, if the name holds the same public key; fail if it holds another key")
+}
+
+// existingKeyIfNotExists returns the key of the name if --if-not-exists is set
+// and the key exists, and nil otherwise.
+func existingKeyIfNotExists(cmd *cobra.Command, kb keyring.Keyring, name string) (*keyring.Record, error) {
+	if ifNotExists, _ := cmd.Flags().GetBool(flagIfNotExists); !ifNotExists {
+		return nil, nil
+	}
+	k, err := kb.Key(name)
+	if errors.Is(err, sdkerrors.ErrKeyNotFound) {
+		return nil, nil
+	}
+	return k, err
+}
+
+// printExistingKey prints the existing key of --if-not-exists in JSON, after
+// checking it has the public key of the key to add, derived in memory. An
+// ErrExists error is returned if it hasn't.
+func printExistingKey(cmd *cobra.Command, existing, derived *keyring.Record) error {
+	if derived != nil {
+		existingPubKey, err := existing.GetPubKey()
+		if err != nil {
+			return err
+		}
+		derivedPubKey, err := derived.GetPubKey()
+		if err != nil {
+			return err
+		}
+		if !existingPubKey.Equals(derivedPubKey) {
+			return newKeyError(ErrExists, "key %s already exists with another public key", existing.Name)
+		}
+	}
+
+	out, err := keyring.MkAccKeyOutput(existing)
+	if err != nil {
+		return err
+	}
+	bz, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	cmd.Println(string(bz))
+	return nil
+}
+
+// importKeyring returns the keyring to import the key of the name into, and
+// the existing key of --if-not-exists, if any, in which case the keyring is an
+// in-memory keyring deriving the imported key to compare it with.
+func importKeyring(cmd *cobra.Command, clientCtx client.Context, name string) (keyring.Keyring, *keyring.Record, error) {
+	existing, err := existingKeyIfNotExists(cmd, clientCtx.Keyring, name)
+	if err != nil || existing == nil {
+		return clientCtx.Keyring, nil, err
+	}
+	return keyring.NewInMemory(clientCtx.Codec, PQCKeyringOption()), existing, nil
+}
+
+// printImported prints the existing key of --if-not-exists, if any, after
+// checking it matches the key imported into kr.
+func printImported(cmd *cobra.Command, kr keyring.Keyring, existing *keyring.Record, name string) error {
+	if existing == nil {
+		return nil
+	}
+	imported, err := kr.Key(name)
+	if err != nil {
+		return err
+	}
+	return printExistingKey(cmd, existing, imported)
+}
@@ -0,0 +1,164 @@
+package keys
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	clienttestutil "github.com/cosmos/cosmos-sdk/client/testutil"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestAddIfNotExists(t *testing.T) {
+	const (
+		mnemonic      = "decide praise business actor peasant farm drastic weather extend front hurt later song give verb rhythm worry fun pond reform school tumble august one"
+		otherMnemonic = "equip will roof matter pink blind book anxiety banner elbow sun young"
+	)
+
+	cmd := AddKeyCommand()
+	cmd.Flags().AddFlagSet(Commands("home").PersistentFlags())
+
+	mockIn, mockOut := testutil.ApplyMockIO(cmd)
+	kbHome := t.TempDir()
+	cdc := clienttestutil.MakeTestCodec(t)
+
+	kb, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, kbHome, mockIn, cdc)
+	require.NoError(t, err)
+
+	clientCtx := client.Context{}.WithKeyringDir(kbHome).WithInput(mockIn).WithCodec(cdc)
+	ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+
+	run := func(name, input string, extraArgs ...string) error {
+		mockOut.Reset()
+		mockIn.Reset(input)
+		cmd.SetArgs(append([]string{
+			name,
+			fmt.Sprintf("--%s=%s", flags.FlagHome, kbHome),
+			fmt.Sprintf("--%s=%s", flags.FlagKeyType, hd.Secp256k1Type),
+			fmt.Sprintf("--%s=%s", flags.FlagKeyringBackend, keyring.BackendTest),
+		}, extraArgs...))
+		return cmd.ExecuteContext(ctx)
+	}
+
+	require.NoError(t, run("validator", mnemonic+"\n", "--recover"))
+	k, err := kb.Key("validator")
+	require.NoError(t, err)
+	addr, err := k.GetAddress()
+	require.NoError(t, err)
+
+	// the same key is not added again
+	require.NoError(t, run("validator", mnemonic+"\n", "--recover", "--if-not-exists"))
+	require.Contains(t, mockOut.String(), addr.String())
+
+	// another key under the same name fails, and keeps the existing key
+	err = run("validator", otherMnemonic+"\n", "--recover", "--if-not-exists")
+	require.ErrorIs(t, err, ErrExists)
+	k, err = kb.Key("validator")
+	require.NoError(t, err)
+	got, err := k.GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, addr, got)
+
+	// a generated key never replaces the existing key
+	require.NoError(t, run("validator", "", "--if-not-exists"))
+	require.Contains(t, mockOut.String(), addr.String())
+
+	// without an existing key, the key is added
+	require.NoError(t, run("operator", otherMnemonic+"\n", "--recover", "--if-not-exists"))
+	_, err = kb.Key("operator")
+	require.NoError(t, err)
+}
+
+func TestImportIfNotExists(t *testing.T) {
+	const otherMnemonic = "decide praise business actor peasant farm drastic weather extend front hurt later song give verb rhythm worry fun pond reform school tumble august one"
+
+	kbHome := t.TempDir()
+	cdc := clienttestutil.MakeTestCodec(t)
+
+	// exportKey writes the armored key of the mnemonic to a file
+	exportKey := func(mnemonic string) string {
+		kb := keyring.NewInMemory(cdc)
+		_, err := kb.NewAccount("source", mnemonic, "", sdk.FullFundraiserPath, hd.Secp256k1)
+		require.NoError(t, err)
+		armor, err := kb.ExportPrivKeyArmor("source", "passphrase")
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "key.asc")
+		require.NoError(t, os.WriteFile(path, []byte(armor), 0o600))
+		return path
+	}
+	keyFile, otherKeyFile := exportKey(testdata.TestMnemonic), exportKey(otherMnemonic)
+
+	run := func(cmd *cobra.Command, input string, args ...string) (string, error) {
+		cmd.Flags().AddFlagSet(Commands("home").PersistentFlags())
+		mockIn, mockOut := testutil.ApplyMockIO(cmd)
+		clientCtx := client.Context{}.WithKeyringDir(kbHome).WithInput(mockIn).WithCodec(cdc).
+			WithKeyringOptions(PQCKeyringOption())
+		ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+
+		mockIn.Reset(input)
+		cmd.SetArgs(append(args,
+			fmt.Sprintf("--%s=%s", flags.FlagHome, kbHome),
+			fmt.Sprintf("--%s=%s", flags.FlagKeyringBackend, keyring.BackendTest),
+		))
+		err := cmd.ExecuteContext(ctx)
+		return mockOut.String(), err
+	}
+	address := func(name string) sdk.AccAddress {
+		kb, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, kbHome, nil, cdc, PQCKeyringOption())
+		require.NoError(t, err)
+		k, err := kb.Key(name)
+		require.NoError(t, err)
+		addr, err := k.GetAddress()
+		require.NoError(t, err)
+		return addr
+	}
+
+	t.Run("import", func(t *testing.T) {
+		_, err := run(ImportKeyCommand(), "passphrase\n", "validator", keyFile)
+		require.NoError(t, err)
+		addr := address("validator")
+
+		// the same key is not imported again
+		out, err := run(ImportKeyCommand(), "passphrase\n", "validator", keyFile, "--if-not-exists")
+		require.NoError(t, err)
+		require.Contains(t, out, addr.String())
+
+		// another key under the same name fails, and keeps the existing key
+		_, err = run(ImportKeyCommand(), "passphrase\n", "validator", otherKeyFile, "--if-not-exists")
+		require.ErrorIs(t, err, ErrExists)
+		require.Equal(t, addr, address("validator"))
+
+		// without --if-not-exists, importing over the key fails
+		_, err = run(ImportKeyCommand(), "passphrase\n", "validator", keyFile)
+		require.Error(t, err)
+	})
+
+	t.Run("import-hex", func(t *testing.T) {
+		hexKey, otherHexKey := strings.Repeat("01", hd.PQCSeedSize), strings.Repeat("02", hd.PQCSeedSize)
+		algo := fmt.Sprintf("--%s=%s", flagKeyAlgorithm, hd.DilithiumType)
+
+		_, err := run(ImportHexCommand(), "", "pq", hexKey, algo)
+		require.NoError(t, err)
+		addr := address("pq")
+
+		out, err := run(ImportHexCommand(), "", "pq", hexKey, algo, "--if-not-exists")
+		require.NoError(t, err)
+		require.Contains(t, out, addr.String())
+
+		_, err = run(ImportHexCommand(), "", "pq", otherHexKey, algo, "--if-not-exists")
+		require.ErrorIs(t, err, ErrExists)
+		require.Equal(t, addr, address("pq"))
+	})
+}
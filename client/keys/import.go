@@ -2,92 +2,223 @@ package keys
 
 import (
     "bufio"
+    "errors"
     "fmt"
+    "io"
     "os"
+    "strings"
 
+    "github.com/ProtonMail/go-crypto/openpgp"
     "github.com/spf13/cobra"
     "github.com/baron-chain/cosmos-sdk/client"
     "github.com/baron-chain/cosmos-sdk/client/flags"
     "github.com/baron-chain/cosmos-sdk/client/input"
+    "github.com/baron-chain/cosmos-sdk/crypto"
+    "github.com/baron-chain/cosmos-sdk/crypto/convert"
     "github.com/baron-chain/cosmos-sdk/crypto/keyring"
-    "github.com/baron-chain/cometbft-bc/crypto/kyber"
 )
 
 const (
     flagKeyAlgorithm = "key-algorithm"
+    flagFromEnv      = "from-env"
+    flagPGP          = "pgp"
+    flagPGPKeyring   = "pgp-keyring"
     defaultAlgorithm = "kyber"
+
+    // stdinArg is the key material argument reading it from stdin.
+    stdinArg = "-"
+    // redacted replaces key material in error messages.
+    redacted = "[REDACTED]"
 )
 
 func ImportKeyCommand() *cobra.Command {
     cmd := &cobra.Command{
-        Use:   "import <name> <keyfile>",
+        Use:   "import <name> [keyfile]",
         Short: "Import quantum-safe private keys",
-        Long:  "Import a quantum-safe private key (Kyber/Dilithium supported) into the local keybase",
-        Args:  cobra.ExactArgs(2),
+        Long: `Import a quantum-safe private key (Kyber/Dilithium supported) into the local keybase.
+
+The key is armored as by "keys export", or in the "BARON CHAIN QUANTUM KEY" armor,
+whose algorithm header names the key algorithm, and its salt and kdf headers the
+derivation of the key encrypting it with its passphrase.
+
+The armored key is read from the keyfile, from stdin when the keyfile is "-", or
+from the environment variable given with --from-env, so that automation can import
+keys without writing them to disk. Key material is redacted from error messages.
+
+A key exported with "keys export --chunk-size" is imported from its .parts.json
+manifest, which verifies the parts, and lists those to copy again if any is
+missing or corrupt.
+
+A key exported with "keys export --webauthn" is imported with --webauthn, which
+unwraps it with its WebAuthn credential through a page served on localhost to
+open in a browser, after a user presence check by the authenticator. The
+unwrapped key is then decrypted with its passphrase.
+
+A key stored in a PGP vault is imported with --pgp from the OpenPGP message, armored
+or binary, encrypted with a passphrase or to a PGP key of the keyring given with
+--pgp-keyring, e.g. as exported by "gpg --export-secret-keys". The message holds an
+armored key, imported with its passphrase, or a hex encoded secp256k1 key, which is
+armored with the passphrase entered on the fly.
+
+With --if-not-exists, importing a key again succeeds without changes and prints the
+existing key in JSON, while importing another key under an existing name fails.`,
+        Example: `  barond keys import validator validator.asc
+  barond keys import validator validator.asc.parts.json
+  barond keys import validator validator.key.gpg --pgp --pgp-keyring ops-secret.asc
+  barond keys import validator - < validator.asc
+  barond keys import validator --from-env VALIDATOR_KEY`,
+        Args: cobra.RangeArgs(1, 2),
         RunE: func(cmd *cobra.Command, args []string) error {
             clientCtx, err := client.GetClientQueryContext(cmd)
             if err != nil {
                 return fmt.Errorf("failed to get client context: %w", err)
             }
+            buf := bufio.NewReader(clientCtx.Input)
+
+            keyMaterial, err := readKeyMaterial(cmd, buf, args, true)
+            if err != nil {
+                return err
+            }
 
-            keyBytes, err := os.ReadFile(args[1])
+            keyMaterial, unwrapped, err := unwrapKeyWebAuthn(cmd, keyMaterial)
             if err != nil {
-                return fmt.Errorf("failed to read keyfile: %w", err)
+                return err
+            }
+
+            pgp, _ := cmd.Flags().GetBool(flagPGP)
+            var pgpPassphrase string
+            if pgp {
+                if pgpPassphrase, err = input.GetPassword("Enter PGP passphrase:", buf); err != nil {
+                    return fmt.Errorf("failed to read PGP passphrase: %w", err)
+                }
             }
 
-            passphrase, err := input.GetPassword("Enter passphrase:", bufio.NewReader(clientCtx.Input))
+            passphrase, err := input.GetPassword("Enter passphrase:", buf)
             if err != nil {
                 return fmt.Errorf("failed to read passphrase: %w", err)
             }
 
-            algorithm, _ := cmd.Flags().GetString(flagKeyAlgorithm)
-            return importKey(clientCtx.Keyring, args[0], keyBytes, passphrase, algorithm)
+            if pgp {
+                keyMaterial, err = convertPGPKey(cmd, keyMaterial, pgpPassphrase, passphrase)
+                if err != nil {
+                    return scrubSecrets(err, pgpPassphrase, passphrase)
+                }
+                // the PGP message holds or is converted to an armored key
+                unwrapped = true
+            }
+
+            kr, existing, err := importKeyring(cmd, clientCtx, args[0])
+            if err != nil {
+                return err
+            }
+
+            if unwrapped {
+                // the unwrapped key is armored as by keys export
+                err = kr.ImportPrivKey(args[0], keyMaterial, passphrase)
+            } else {
+                // the algorithm is read from the armor, and only checked when
+                // given explicitly
+                var algorithm string
+                if cmd.Flags().Changed(flagKeyAlgorithm) {
+                    algorithm, _ = cmd.Flags().GetString(flagKeyAlgorithm)
+                }
+                err = importKey(kr, args[0], keyMaterial, passphrase, algorithm)
+            }
+            if err != nil {
+                return scrubSecrets(err, keyMaterial, passphrase)
+            }
+            return printImported(cmd, kr, existing, args[0])
         },
     }
 
-    cmd.Flags().String(flagKeyAlgorithm, defaultAlgorithm, "Quantum-safe algorithm (kyber/dilithium)")
-    return cmd
+    cmd.Flags().String(flagKeyAlgorithm, defaultAlgorithm, "Quantum-safe algorithm (kyber/dilithium), checked against the algorithm of the armor when given")
+    cmd.Flags().String(flagFromEnv, "", "Read the armored key from this environment variable instead of a keyfile")
+    cmd.Flags().Bool(flagPGP, false, "Decrypt the key from an OpenPGP message")
+    cmd.Flags().String(flagPGPKeyring, "", "PGP keyring holding the secret key the message is encrypted to, unneeded for messages encrypted with a passphrase")
+    addWebAuthnFlags(cmd, false)
+    addIfNotExistsFlag(cmd)
+    return enforceKeyNamespaces(cmd)
 }
 
-func importKey(kr keyring.Keyring, name string, keyBytes []byte, passphrase, algorithm string) error {
-    switch algorithm {
-    case "kyber":
-        return importKyberKey(kr, name, keyBytes, passphrase)
-    case "dilithium":
-        return importDilithiumKey(kr, name, keyBytes, passphrase)
-    default:
-        return fmt.Errorf("unsupported key algorithm: %s", algorithm)
+// convertPGPKey decrypts the OpenPGP message holding a key with pgpPassphrase,
+// which decrypts the secret key of --pgp-keyring the message is encrypted to,
+// if any, and returns the key armored, with passphrase for hex keys.
+func convertPGPKey(cmd *cobra.Command, message, pgpPassphrase, passphrase string) (string, error) {
+    var keys openpgp.EntityList
+    if path, _ := cmd.Flags().GetString(flagPGPKeyring); path != "" {
+        f, err := os.Open(path)
+        if err != nil {
+            return "", fmt.Errorf("failed to open PGP keyring: %w", err)
+        }
+        defer f.Close()
+        if keys, err = crypto.ReadPGPKeyRing(f); err != nil {
+            return "", fmt.Errorf("failed to read PGP keyring: %w", err)
+        }
     }
+
+    return crypto.ConvertPGPPrivKey([]byte(message), []byte(pgpPassphrase), keys, passphrase)
 }
 
-func importKyberKey(kr keyring.Keyring, name string, keyBytes []byte, passphrase string) error {
-    key, err := kyber.DecryptPrivateKey(keyBytes, []byte(passphrase))
+// importKey imports a private key armor of either family, e.g. the "BARON CHAIN
+// QUANTUM KEY" armor, which the keyring converts with crypto/convert. The key
+// algorithm must be algorithm, if not empty.
+func importKey(kr keyring.Keyring, name, armor, passphrase, algorithm string) error {
+    info, err := convert.Detect([]byte(armor))
     if err != nil {
-        return fmt.Errorf("failed to decrypt Kyber key: %w", err)
+        return fmt.Errorf("failed to read key: %w", err)
+    }
+    if info.Format == convert.FormatRaw || info.Kind != convert.KindPrivKey {
+        return fmt.Errorf("expected a private key armor, got a %s %s key", info.Format, info.Kind)
+    }
+    if algorithm != "" && info.Algo != algorithm {
+        return fmt.Errorf("key algorithm %s doesn't match --%s %s", info.Algo, flagKeyAlgorithm, algorithm)
     }
 
-    return kr.ImportPrivKey(name, key.String(), passphrase)
+    return kr.ImportPrivKey(name, armor, passphrase)
 }
 
 func ImportHexCommand() *cobra.Command {
     cmd := &cobra.Command{
-        Use:   "import-hex <name> <hex>",
+        Use:   "import-hex <name> [hex]",
         Short: "Import quantum-safe hex keys",
-        Long:  "Import hex encoded quantum-safe private key (Kyber/Dilithium supported)",
-        Args:  cobra.ExactArgs(2),
+        Long: `Import hex encoded quantum-safe private key (Kyber/Dilithium supported).
+
+The hex key is read from the argument, from the first line of stdin when the
+argument is "-", or from the environment variable given with --from-env, which
+keeps it out of the shell history and process list. Key material is redacted
+from error messages. With --if-not-exists, importing the key again succeeds without
+changes and prints the existing key in JSON.`,
+        Example: `  barond keys import-hex validator - < validator.hex
+  barond keys import-hex validator --from-env VALIDATOR_HEX_KEY`,
+        Args: cobra.RangeArgs(1, 2),
         RunE: func(cmd *cobra.Command, args []string) error {
             clientCtx, err := client.GetClientQueryContext(cmd)
             if err != nil {
                 return fmt.Errorf("failed to get client context: %w", err)
             }
 
+            hexKey, err := readKeyMaterial(cmd, bufio.NewReader(clientCtx.Input), args, false)
+            if err != nil {
+                return err
+            }
+
+            kr, existing, err := importKeyring(cmd, clientCtx, args[0])
+            if err != nil {
+                return err
+            }
+
             algorithm, _ := cmd.Flags().GetString(flagKeyAlgorithm)
-            return importHexKey(clientCtx.Keyring, args[0], args[1], algorithm)
+            if err := importHexKey(kr, args[0], hexKey, algorithm); err != nil {
+                return scrubSecrets(err, hexKey)
+            }
+            return printImported(cmd, kr, existing, args[0])
         },
     }
 
     cmd.Flags().String(flagKeyAlgorithm, defaultAlgorithm, "Quantum-safe algorithm (kyber/dilithium)")
-    return cmd
+    cmd.Flags().String(flagFromEnv, "", "Read the hex key from this environment variable instead of the argument")
+    addIfNotExistsFlag(cmd)
+    return enforceKeyNamespaces(cmd)
 }
 
 func importHexKey(kr keyring.Keyring, name, hexKey, algorithm string) error {
@@ -100,3 +231,111 @@ func importHexKey(kr keyring.Keyring, name, hexKey, algorithm string) error {
         return fmt.Errorf("unsupported key algorithm: %s", algorithm)
     }
 }
+
+// readKeyMaterial returns the key material given by the second argument, or by
+// the --from-env environment variable. Armored key material is read from the
+// keyfile argument, or from stdin up to the armor end line when it is "-", so
+// that a passphrase can follow it. Hex key material is the argument itself, or
+// the first line of stdin when it is "-".
+func readKeyMaterial(cmd *cobra.Command, buf *bufio.Reader, args []string, armored bool) (string, error) {
+    envVar, _ := cmd.Flags().GetString(flagFromEnv)
+    switch {
+    case envVar != "" && len(args) > 1:
+        return "", fmt.Errorf("--%s can't be used with a key argument", flagFromEnv)
+    case envVar != "":
+        value, ok := os.LookupEnv(envVar)
+        if !ok || strings.TrimSpace(value) == "" {
+            return "", fmt.Errorf("environment variable %s is not set", envVar)
+        }
+        return strings.TrimSpace(value), nil
+    case len(args) < 2:
+        return "", fmt.Errorf("expected a key argument or --%s", flagFromEnv)
+    }
+
+    switch {
+    case args[1] == stdinArg && armored:
+        return readArmorFromStdin(buf)
+    case args[1] == stdinArg:
+        line, err := buf.ReadString('\n')
+        if err != nil && !errors.Is(err, io.EOF) {
+            return "", fmt.Errorf("failed to read key from stdin: %w", err)
+        }
+        if strings.TrimSpace(line) == "" {
+            return "", errors.New("no key read from stdin")
+        }
+        return strings.TrimSpace(line), nil
+    case armored:
+        key, err := readKeyFile(args[1], cmd.ErrOrStderr())
+        if err != nil {
+            return "", fmt.Errorf("failed to read keyfile: %w", err)
+        }
+        return key, nil
+    default:
+        return args[1], nil
+    }
+}
+
+// readArmorFromStdin reads an armored key from stdin, up to and including its
+// end line.
+func readArmorFromStdin(buf *bufio.Reader) (string, error) {
+    var sb strings.Builder
+    for {
+        line, err := buf.ReadString('\n')
+        sb.WriteString(line)
+        if strings.HasPrefix(strings.TrimSpace(line), "-----END ") {
+            return sb.String(), nil
+        }
+        if errors.Is(err, io.EOF) {
+            if strings.TrimSpace(sb.String()) == "" {
+                return "", errors.New("no key read from stdin")
+            }
+            return sb.String(), nil
+        }
+        if err != nil {
+            return "", fmt.Errorf("failed to read key from stdin: %w", err)
+        }
+    }
+}
+
+// scrubbedError is an error whose message had secrets redacted. It unwraps to
+// the original error so that it can still be matched with errors.Is.
+type scrubbedError struct {
+    msg string
+    err error
+}
+
+func (e scrubbedError) Error() string { return e.msg }
+
+func (e scrubbedError) Unwrap() error { return e.err }
+
+// scrubSecrets redacts the secrets, and each of their lines, from the message
+// of err, since some decoders and keyring backends echo their input in errors.
+func scrubSecrets(err error, secrets ...string) error {
+    if err == nil {
+        return nil
+    }
+
+    var fragments []string
+    for _, secret := range secrets {
+        // very short secrets would redact unrelated parts of the message
+        if secret = strings.TrimSpace(secret); len(secret) >= 4 {
+            fragments = append(fragments, secret)
+        }
+        for _, line := range strings.Split(secret, "\n") {
+            // short lines, e.g. armor headers, are not secret
+            if line = strings.TrimSpace(line); len(line) >= 8 {
+                fragments = append(fragments, line)
+            }
+        }
+    }
+
+    msg := err.Error()
+    scrubbed := msg
+    for _, fragment := range fragments {
+        scrubbed = strings.ReplaceAll(scrubbed, fragment, redacted)
+    }
+    if scrubbed == msg {
+        return err
+    }
+    return scrubbedError{msg: scrubbed, err: err}
+}
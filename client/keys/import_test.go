@@ -1,16 +1,22 @@
 package keys
 
 import (
+    "bufio"
     "context"
+    "errors"
     "fmt"
     "os"
+    "strings"
     "path/filepath"
     "testing"
 
+    "github.com/spf13/cobra"
     "github.com/stretchr/testify/require"
     "github.com/baron-chain/cosmos-sdk/client"
     "github.com/baron-chain/cosmos-sdk/client/flags"
     clienttestutil "github.com/baron-chain/cosmos-sdk/client/testutil"
+    "github.com/baron-chain/cosmos-sdk/codec"
+    "github.com/baron-chain/cosmos-sdk/crypto/convert"
     "github.com/baron-chain/cosmos-sdk/crypto/keyring"
     "github.com/baron-chain/cosmos-sdk/testutil"
     sdk "github.com/baron-chain/cosmos-sdk/types"
@@ -24,6 +30,7 @@ func TestImportCmd(t *testing.T) {
         keyringBackend string
         userInput      string
         keyAlgorithm   string
+        armorAlgorithm string
         expectError    bool
     }{
         {
@@ -51,20 +58,16 @@ func TestImportCmd(t *testing.T) {
             userInput:      "123456789\n12345678\n12345678\n",
             keyAlgorithm:   "kyber",
         },
+        {
+            name:           "algorithm mismatch",
+            keyringBackend: keyring.BackendTest,
+            userInput:      "123456789\n",
+            keyAlgorithm:   "kyber",
+            armorAlgorithm: "dilithium",
+            expectError:    true,
+        },
     }
 
-    // Quantum-safe test key (Kyber format)
-    quantumSafeKey := `-----BEGIN BARON CHAIN QUANTUM KEY-----
-algorithm: kyber
-salt: Q790BB721D1C094260EA84F5E5B72289
-kdf: argon2id
-
-HbP+c6JmeJy9JXe2rbbF1QtCX1gLqGcDQPBXiCtFvP7/8wTZtVOPj8vREzhZ9ElO
-3P7YnrzPQThG0Q+ZnRSbl9MAS8uFAM4mqm5r/Ys=
-=f3l4
------END BARON CHAIN QUANTUM KEY-----
-`
-
     for _, tc := range testCases {
         t.Run(tc.name, func(t *testing.T) {
             cmd := ImportKeyCommand()
@@ -87,6 +90,13 @@ HbP+c6JmeJy9JXe2rbbF1QtCX1gLqGcDQPBXiCtFvP7/8wTZtVOPj8vREzhZ9ElO
                 os.RemoveAll(kbHome)
             })
 
+            // the key is exported in the "BARON CHAIN QUANTUM KEY" armor, with
+            // its algorithm, salt and kdf headers
+            armorAlgorithm := tc.armorAlgorithm
+            if armorAlgorithm == "" {
+                armorAlgorithm = tc.keyAlgorithm
+            }
+            quantumSafeKey := exportQuantumKey(t, cdc, armorAlgorithm, "123456789")
             keyfile := filepath.Join(kbHome, "key.asc")
             require.NoError(t, os.WriteFile(keyfile, []byte(quantumSafeKey), 0o600))
 
@@ -108,6 +118,26 @@ HbP+c6JmeJy9JXe2rbbF1QtCX1gLqGcDQPBXiCtFvP7/8wTZtVOPj8vREzhZ9ElO
     }
 }
 
+// exportQuantumKey returns a new key of the given algorithm in the quantum
+// armor, encrypted with passphrase.
+func exportQuantumKey(t *testing.T, cdc codec.Codec, algorithm, passphrase string) string {
+    t.Helper()
+
+    kb := keyring.NewInMemory(cdc, PQCKeyringOption())
+    algo, err := keyring.NewSigningAlgoFromString(algorithm, PQCSigningAlgos)
+    require.NoError(t, err)
+    _, _, err = kb.NewMnemonic("source", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, algo)
+    require.NoError(t, err)
+
+    armor, err := kb.ExportPrivKeyArmor("source", passphrase)
+    require.NoError(t, err)
+    quantumArmor, err := convert.ToQuantumArmor(armor)
+    require.NoError(t, err)
+    require.Contains(t, quantumArmor, "-----BEGIN BARON CHAIN QUANTUM KEY-----")
+    require.Contains(t, quantumArmor, "\nalgorithm: "+algorithm+"\n")
+    return quantumArmor
+}
+
 func TestImportHexCmd(t *testing.T) {
     cdc := clienttestutil.MakeTestCodec(t)
     
@@ -168,3 +198,51 @@ func TestImportHexCmd(t *testing.T) {
         })
     }
 }
+
+func TestReadKeyMaterial(t *testing.T) {
+    armor := "-----BEGIN BARON CHAIN QUANTUM KEY-----\nalgorithm: kyber\n\nHbP+c6JmeJy9\n-----END BARON CHAIN QUANTUM KEY-----\n"
+
+    newCmd := func(args ...string) *cobra.Command {
+        cmd := ImportKeyCommand()
+        require.NoError(t, cmd.ParseFlags(args))
+        return cmd
+    }
+
+    // the armor is read up to its end line, leaving the passphrase to read
+    buf := bufio.NewReader(strings.NewReader(armor + "passphrase\n"))
+    key, err := readKeyMaterial(newCmd(), buf, []string{"name", "-"}, true)
+    require.NoError(t, err)
+    require.Equal(t, armor, key)
+    rest, err := buf.ReadString('\n')
+    require.NoError(t, err)
+    require.Equal(t, "passphrase\n", rest)
+
+    buf = bufio.NewReader(strings.NewReader("0x7b3e5795\nmore\n"))
+    key, err = readKeyMaterial(newCmd(), buf, []string{"name", "-"}, false)
+    require.NoError(t, err)
+    require.Equal(t, "0x7b3e5795", key)
+
+    t.Setenv("TEST_IMPORT_KEY", " 0x7b3e5795\n")
+    key, err = readKeyMaterial(newCmd("--from-env=TEST_IMPORT_KEY"), nil, []string{"name"}, false)
+    require.NoError(t, err)
+    require.Equal(t, "0x7b3e5795", key)
+
+    _, err = readKeyMaterial(newCmd("--from-env=TEST_IMPORT_KEY"), nil, []string{"name", "key"}, false)
+    require.ErrorContains(t, err, "can't be used with a key argument")
+    _, err = readKeyMaterial(newCmd("--from-env=TEST_IMPORT_UNSET"), nil, []string{"name"}, false)
+    require.EqualError(t, err, "environment variable TEST_IMPORT_UNSET is not set")
+    _, err = readKeyMaterial(newCmd(), nil, []string{"name"}, false)
+    require.ErrorContains(t, err, "expected a key argument")
+}
+
+func TestScrubSecrets(t *testing.T) {
+    require.NoError(t, scrubSecrets(nil, "secret"))
+
+    cause := errors.New("invalid key 0x7b3e5795 with line HbP+c6JmeJy9")
+    err := scrubSecrets(fmt.Errorf("import failed: %w", cause), "0x7b3e5795", "kdf: argon2id\nHbP+c6JmeJy9\n")
+    require.EqualError(t, err, "import failed: invalid key [REDACTED] with line [REDACTED]")
+    require.ErrorIs(t, err, cause)
+
+    // errors without secrets are returned as is
+    require.Equal(t, cause, scrubSecrets(cause, "other secret"))
+}
@@ -0,0 +1,259 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-sdk/client"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+)
+
+const (
+	flagLabels        = "labels"
+	flagSignInventory = "sign"
+
+	// KeyLabelsFileName is the name of the file, within the keyring directory,
+	// the inventory reads key labels from when --labels is not given.
+	KeyLabelsFileName = "keyring-labels.json"
+)
+
+// KeyInventory is the public inventory of a keyring. It never holds secrets.
+type KeyInventory struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Backend     string              `json:"backend"`
+	Keys        []KeyInventoryEntry `json:"keys"`
+}
+
+// KeyInventoryEntry is the public information of a key of the inventory.
+type KeyInventoryEntry struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Address   string `json:"address"`
+	Algorithm string `json:"algorithm"`
+	// Fingerprint is the hex encoded SHA-256 of the public key bytes.
+	Fingerprint string `json:"fingerprint"`
+	// CreatedAt is only known for the keyring backends storing keys in files,
+	// i.e. file and test.
+	CreatedAt *time.Time        `json:"created_at,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// SignedKeyInventory is a key inventory signed with a key of the keyring. The
+// signature is over the inventory JSON encoding, with sorted keys and without
+// whitespace.
+type SignedKeyInventory struct {
+	Inventory KeyInventory          `json:"inventory"`
+	Signature KeyInventorySignature `json:"signature"`
+}
+
+// KeyInventorySignature is the signature of a SignedKeyInventory.
+type KeyInventorySignature struct {
+	Key         string `json:"key"`
+	Address     string `json:"address"`
+	Algorithm   string `json:"algorithm"`
+	Fingerprint string `json:"fingerprint"`
+	Signature   []byte `json:"signature"`
+}
+
+// InventoryCommand returns the command exporting the public keyring inventory.
+func InventoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Export the public inventory of the keyring for monitoring",
+		Long: `Export the names, addresses, algorithms, public key fingerprints, creation
+timestamps and labels of the keys of the keyring, to be shipped into config-management
+and monitoring inventories. No secret is ever included.
+
+Creation timestamps are only known for the file and test backends. Labels are read
+from the JSON file given with --labels, by default keyring-labels.json in the keyring
+directory when it exists, mapping key names to their labels:
+
+    {
+      "validator": {"env": "prod", "role": "validator"}
+    }
+
+With --sign, the inventory is signed with the given key of the keyring, over its JSON
+encoding with sorted keys and without whitespace, so that consumers can check where
+it comes from.`,
+		Example: `  barond keys inventory --output json
+  barond keys inventory --output json --sign inventory-signer`,
+		Args: cobra.NoArgs,
+		RunE: runInventoryCmd,
+	}
+
+	cmd.Flags().String(flagLabels, "", "Path of the JSON key labels file (default: <keyring-dir>/"+KeyLabelsFileName+")")
+	cmd.Flags().String(flagSignInventory, "", "Name of the key to sign the inventory with")
+
+	return cmd
+}
+
+func runInventoryCmd(cmd *cobra.Command, _ []string) error {
+	clientCtx, err := client.GetClientQueryContext(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to get client context: %w", err)
+	}
+
+	labelsFile, _ := cmd.Flags().GetString(flagLabels)
+	labels, err := loadKeyLabels(labelsFile, clientCtx.KeyringDir)
+	if err != nil {
+		return err
+	}
+
+	inv, err := BuildKeyInventory(clientCtx.Keyring, clientCtx.KeyringDir, labels)
+	if err != nil {
+		return err
+	}
+
+	format := clientCtx.OutputFormat
+	if format == "" {
+		format = OutputFormatText
+	}
+
+	signer, _ := cmd.Flags().GetString(flagSignInventory)
+	if signer == "" {
+		return outputKeyData(cmd.OutOrStdout(), inv, format)
+	}
+
+	signed, err := SignKeyInventory(clientCtx.Keyring, inv, signer)
+	if err != nil {
+		return err
+	}
+	return outputKeyData(cmd.OutOrStdout(), signed, format)
+}
+
+// loadKeyLabels reads the key labels file, or the default one of the keyring
+// directory when file is empty. A missing default file means no labels.
+func loadKeyLabels(file, keyringDir string) (map[string]map[string]string, error) {
+	explicit := file != ""
+	if !explicit {
+		file = filepath.Join(keyringDir, KeyLabelsFileName)
+	}
+
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read labels file: %w", err)
+	}
+
+	var labels map[string]map[string]string
+	if err := json.Unmarshal(bz, &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse labels file %s: %w", file, err)
+	}
+	return labels, nil
+}
+
+// BuildKeyInventory returns the public inventory of the keys of kr, stored in
+// keyringDir, with the given labels by key name.
+func BuildKeyInventory(kr keyring.Keyring, keyringDir string, labels map[string]map[string]string) (KeyInventory, error) {
+	records, err := kr.List()
+	if err != nil {
+		return KeyInventory{}, fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	inv := KeyInventory{
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		Backend:     kr.Backend(),
+		Keys:        make([]KeyInventoryEntry, 0, len(records)),
+	}
+	for _, k := range records {
+		entry, err := newKeyInventoryEntry(k)
+		if err != nil {
+			return KeyInventory{}, fmt.Errorf("failed to inventory key %s: %w", k.Name, err)
+		}
+		entry.CreatedAt = keyCreationTime(kr.Backend(), keyringDir, k.Name)
+		entry.Labels = labels[k.Name]
+		inv.Keys = append(inv.Keys, entry)
+	}
+	sort.Slice(inv.Keys, func(i, j int) bool { return inv.Keys[i].Name < inv.Keys[j].Name })
+
+	return inv, nil
+}
+
+func newKeyInventoryEntry(k *keyring.Record) (KeyInventoryEntry, error) {
+	pub, err := k.GetPubKey()
+	if err != nil {
+		return KeyInventoryEntry{}, err
+	}
+
+	fingerprint := sha256.Sum256(pub.Bytes())
+	return KeyInventoryEntry{
+		Name:        k.Name,
+		Type:        k.GetType().String(),
+		Address:     sdk.AccAddress(pub.Address()).String(),
+		Algorithm:   pub.Type(),
+		Fingerprint: hex.EncodeToString(fingerprint[:]),
+	}, nil
+}
+
+// keyCreationTime returns the modification time of the key file of the file
+// and test backends, which is never rewritten after the key is created, and
+// nil for the other backends.
+func keyCreationTime(backend, keyringDir, name string) *time.Time {
+	switch backend {
+	case keyring.BackendFile, keyring.BackendTest:
+	default:
+		return nil
+	}
+
+	info, err := os.Stat(filepath.Join(keyringDir, "keyring-"+backend, name+".info"))
+	if err != nil {
+		return nil
+	}
+	created := info.ModTime().UTC()
+	return &created
+}
+
+// SignKeyInventory signs inv with the key uid of kr.
+func SignKeyInventory(kr keyring.Keyring, inv KeyInventory, uid string) (SignedKeyInventory, error) {
+	k, err := kr.Key(uid)
+	if err != nil {
+		return SignedKeyInventory{}, fmt.Errorf("failed to get inventory signing key: %w", err)
+	}
+	signer, err := newKeyInventoryEntry(k)
+	if err != nil {
+		return SignedKeyInventory{}, err
+	}
+
+	bz, err := KeyInventorySignBytes(inv)
+	if err != nil {
+		return SignedKeyInventory{}, err
+	}
+	sig, _, err := kr.Sign(uid, bz)
+	if err != nil {
+		return SignedKeyInventory{}, fmt.Errorf("failed to sign inventory: %w", err)
+	}
+
+	return SignedKeyInventory{
+		Inventory: inv,
+		Signature: KeyInventorySignature{
+			Key:         signer.Name,
+			Address:     signer.Address,
+			Algorithm:   signer.Algorithm,
+			Fingerprint: signer.Fingerprint,
+			Signature:   sig,
+		},
+	}, nil
+}
+
+// KeyInventorySignBytes returns the bytes an inventory signature is over: its
+// JSON encoding with sorted keys and without whitespace.
+func KeyInventorySignBytes(inv KeyInventory) ([]byte, error) {
+	bz, err := json.Marshal(inv)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.SortJSON(bz)
+}
@@ -0,0 +1,73 @@
+package keys
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clienttestutil "github.com/baron-chain/cosmos-sdk/client/testutil"
+	"github.com/baron-chain/cosmos-sdk/crypto/hd"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+)
+
+func TestKeyInventory(t *testing.T) {
+	dir := t.TempDir()
+	kr, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, dir, nil, clienttestutil.MakeTestCodec(t))
+	require.NoError(t, err)
+
+	for _, name := range []string{"validator", "relayer"} {
+		_, _, err := kr.NewMnemonic(name, keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+		require.NoError(t, err)
+	}
+
+	labelsFile := filepath.Join(dir, KeyLabelsFileName)
+	require.NoError(t, os.WriteFile(labelsFile, []byte(`{"validator": {"env": "prod"}}`), 0o600))
+	labels, err := loadKeyLabels("", dir)
+	require.NoError(t, err)
+
+	inv, err := BuildKeyInventory(kr, dir, labels)
+	require.NoError(t, err)
+	require.Equal(t, keyring.BackendTest, inv.Backend)
+	require.Len(t, inv.Keys, 2)
+
+	entry := inv.Keys[1]
+	require.Equal(t, "validator", entry.Name)
+	require.Equal(t, "local", entry.Type)
+	require.Equal(t, "secp256k1", entry.Algorithm)
+	require.Len(t, entry.Fingerprint, 64)
+	require.NotNil(t, entry.CreatedAt)
+	require.Equal(t, map[string]string{"env": "prod"}, entry.Labels)
+	require.Nil(t, inv.Keys[0].Labels)
+
+	k, err := kr.Key("validator")
+	require.NoError(t, err)
+	addr, err := k.GetAddress()
+	require.NoError(t, err)
+	require.Equal(t, addr.String(), entry.Address)
+
+	signed, err := SignKeyInventory(kr, inv, "relayer")
+	require.NoError(t, err)
+	require.Equal(t, "relayer", signed.Signature.Key)
+
+	bz, err := KeyInventorySignBytes(signed.Inventory)
+	require.NoError(t, err)
+	signer, err := kr.Key("relayer")
+	require.NoError(t, err)
+	pub, err := signer.GetPubKey()
+	require.NoError(t, err)
+	require.True(t, pub.VerifySignature(bz, signed.Signature.Signature))
+
+	_, err = SignKeyInventory(kr, inv, "unknown")
+	require.Error(t, err)
+
+	// a missing default labels file means no labels, a missing explicit one
+	// is an error
+	labels, err = loadKeyLabels("", t.TempDir())
+	require.NoError(t, err)
+	require.Nil(t, labels)
+	_, err = loadKeyLabels(filepath.Join(t.TempDir(), "labels.json"), dir)
+	require.Error(t, err)
+}
@@ -17,12 +17,18 @@ func ListKeysCmd() *cobra.Command {
     cmd := &cobra.Command{
         Use:   "list",
         Short: "List all quantum-safe keys",
-        Long:  `List all quantum-safe public keys stored in the keyring with their names, addresses and algorithms.`,
-        RunE:  runListCmd,
+        Long: `List all quantum-safe public keys stored in the keyring with their names, addresses and algorithms.
+
+Keys are organized in namespaces by their names, e.g. ops/validator1 is in the namespace
+ops. With --namespace, only the keys of the namespace and of its sub-namespaces are listed.`,
+        Example: `  barond keys list --namespace ops`,
+        RunE:    runListCmd,
     }
 
     cmd.Flags().BoolP(flagListNames, "n", false, "List names only")
     cmd.Flags().BoolP(flagShowAlgo, "a", false, "Show key algorithm (kyber/dilithium)")
+    cmd.Flags().String(flagNamespace, "", "Only list the keys of this namespace, e.g. ops")
+    addAddressFormatFlag(cmd)
     return cmd
 }
 
@@ -36,20 +42,52 @@ func runListCmd(cmd *cobra.Command, _ []string) error {
     if err != nil {
         return fmt.Errorf("failed to list keys: %w", err)
     }
+    if namespace, _ := cmd.Flags().GetString(flagNamespace); namespace != "" {
+        records = filterKeyNamespace(records, namespace)
+    }
+
+    showNames, _ := cmd.Flags().GetBool(flagListNames)
+    showAlgo, _ := cmd.Flags().GetBool(flagShowAlgo)
+    addrFormat, err := getAddressFormat(cmd)
+    if err != nil {
+        return err
+    }
+
+    // hardware token backends also surface the slots of their module
+    if lister, ok := clientCtx.Keyring.(keyring.SlotLister); ok && !showNames && clientCtx.OutputFormat != "json" {
+        if err := printSlots(cmd, lister); err != nil {
+            return err
+        }
+    }
 
     if len(records) == 0 {
         cmd.Printf("No quantum-safe keys found in keyring\n")
         return nil
     }
 
-    showNames, _ := cmd.Flags().GetBool(flagListNames)
-    showAlgo, _ := cmd.Flags().GetBool(flagShowAlgo)
-
     if showNames {
         return printKeyNames(cmd, records)
     }
 
-    return printKeyDetails(cmd, records, showAlgo, clientCtx.OutputFormat)
+    return printKeyDetails(cmd, records, showAlgo, addrFormat, clientCtx.OutputFormat)
+}
+
+func printSlots(cmd *cobra.Command, lister keyring.SlotLister) error {
+    slots, err := lister.Slots()
+    if err != nil {
+        return fmt.Errorf("failed to list token slots: %w", err)
+    }
+
+    for _, slot := range slots {
+        if !slot.TokenPresent {
+            cmd.Printf("Slot %d: %s (no token)\n", slot.ID, slot.Description)
+            continue
+        }
+        cmd.Printf("Slot %d: %s\n  Token: %s (model %s, serial %s)\n",
+            slot.ID, slot.Description, slot.TokenLabel, slot.TokenModel, slot.TokenSerial)
+    }
+    cmd.Printf("\n")
+    return nil
 }
 
 func printKeyNames(cmd *cobra.Command, records []*keyring.Record) error {
@@ -59,18 +97,23 @@ func printKeyNames(cmd *cobra.Command, records []*keyring.Record) error {
     return nil
 }
 
-func printKeyDetails(cmd *cobra.Command, records []*keyring.Record, showAlgo bool, format string) error {
+func printKeyDetails(cmd *cobra.Command, records []*keyring.Record, showAlgo bool, addrFormat, format string) error {
     if format == "json" {
-        return printJSON(cmd, records, showAlgo)
+        return printJSON(cmd, records, showAlgo, addrFormat)
     }
 
     for _, k := range records {
+        addr, err := recordAccAddress(k, addrFormat)
+        if err != nil {
+            return fmt.Errorf("failed to get address of %s: %w", k.Name, err)
+        }
+
         if showAlgo {
             cmd.Printf("Name: %s\nAddress: %s\nAlgorithm: %s\n\n", 
-                k.Name, k.GetAddress(), getKeyAlgorithm(k))
+                k.Name, addr, getKeyAlgorithm(k))
         } else {
             cmd.Printf("Name: %s\nAddress: %s\n\n", 
-                k.Name, k.GetAddress())
+                k.Name, addr)
         }
     }
     return nil
@@ -107,7 +150,7 @@ func ListKeyTypesCmd() *cobra.Command {
     }
 }
 
-func printJSON(cmd *cobra.Command, records []*keyring.Record, showAlgo bool) error {
+func printJSON(cmd *cobra.Command, records []*keyring.Record, showAlgo bool, addrFormat string) error {
     type keyInfo struct {
         Name      string `json:"name"`
         Address   string `json:"address"`
@@ -116,9 +159,14 @@ func printJSON(cmd *cobra.Command, records []*keyring.Record, showAlgo bool) err
 
     var output []keyInfo
     for _, k := range records {
+        addr, err := recordAccAddress(k, addrFormat)
+        if err != nil {
+            return fmt.Errorf("failed to get address of %s: %w", k.Name, err)
+        }
+
         info := keyInfo{
             Name:    k.Name,
-            Address: k.GetAddress().String(),
+            Address: addr,
         }
         if showAlgo {
             info.Algorithm = getKeyAlgorithm(k)
@@ -27,6 +27,8 @@ The migration process:
 Options:
 - Use --dry-run to verify migration without making changes
 - Use --quantum-safe=[kyber|dilithium] to specify target algorithm
+- Use --approval-file to give the approval of the migration of all keys ("*"),
+  required when dual control is enabled
 
 Note: This is a one-way migration. Please backup your keys before proceeding.`,
         Args: cobra.NoArgs,
@@ -35,6 +37,7 @@ Note: This is a one-way migration. Please backup your keys before proceeding.`,
 
     cmd.Flags().Bool(flagDryRun, false, "Run migration in dry-run mode without making changes")
     cmd.Flags().String(flagQuantumKey, "kyber", "Target quantum-safe algorithm (kyber/dilithium)")
+    addApprovalFileFlag(cmd)
     
     return cmd
 }
@@ -56,8 +59,13 @@ func runMigrateCmd(cmd *cobra.Command, _ []string) error {
         return performDryRun(cmd, clientCtx, algorithm)
     }
 
-    migrated, err := migrateKeys(cmd, clientCtx, algorithm)
+    approval, err := requireKeyApproval(cmd, clientCtx, ApprovalOpMigrate, []string{AllKeys})
     if err != nil {
+        return err
+    }
+
+    migrated, err := migrateKeys(cmd, clientCtx, algorithm)
+    if err := approval.done(err); err != nil {
         return fmt.Errorf("migration failed: %w", err)
     }
 
@@ -15,11 +15,22 @@ const (
     flagUserEntropy     = "unsafe-entropy"
     flagEntropySize     = "entropy-size"
     flagQuantumSafe     = "quantum-safe"
+    flagWords           = "words"
+    flagVerify          = "verify"
     defaultEntropySize  = 256
     minEntropySize      = 256
     recommendedEntropy  = 512
 )
 
+// mnemonicWordCounts maps the supported mnemonic lengths to their BIP39 entropy size in bits.
+var mnemonicWordCounts = map[int]int{
+    12: 128,
+    15: 160,
+    18: 192,
+    21: 224,
+    24: 256,
+}
+
 // MnemonicKeyCommand generates a quantum-safe bip39 mnemonic
 func MnemonicKeyCommand() *cobra.Command {
     cmd := &cobra.Command{
@@ -30,9 +41,25 @@ Generate a quantum-safe BIP39 mnemonic (seed phrase) with enhanced entropy.
 By default, uses system-provided entropy with quantum-safe enhancements.
 For user-provided entropy, use --unsafe-entropy flag (not recommended).
 
+The phrase length can be chosen with --words, which takes precedence over --entropy-size.
+Phrases shorter than 24 words carry less than 256 bits of entropy and are only allowed
+with --quantum-safe=false. Use --verify to re-enter the phrase before the command finishes.
+The phrase uses the English BIP39 wordlist, the only one keys add --recover accepts.
+
+To keep the phrase out of the terminal scrollback, --pager shows it on a separate screen
+which is cleared once it is recorded. --chunk-words shows it a few words at a time, asking
+for one word of every chunk before the next one. --encrypt-to writes it to a new file,
+encrypted with a passphrase, instead of showing it.
+
+System entropy is checked with the same statistical tests as the entropy-check command
+and generation is refused if they fail, unless --force-entropy is given.
+
 Example:
 $ baron-chain keys mnemonic --entropy-size 512
 $ baron-chain keys mnemonic --quantum-safe
+$ baron-chain keys mnemonic --words 24 --verify
+$ baron-chain keys mnemonic --pager --chunk-words 4
+$ baron-chain keys mnemonic --encrypt-to ./mnemonic.asc
 `),
         RunE: generateMnemonic,
     }
@@ -40,6 +67,10 @@ $ baron-chain keys mnemonic --quantum-safe
     cmd.Flags().Bool(flagUserEntropy, false, "Use user-provided entropy (not recommended)")
     cmd.Flags().Int(flagEntropySize, defaultEntropySize, "Entropy size in bits (256, 384, or 512)")
     cmd.Flags().Bool(flagQuantumSafe, true, "Enable quantum-safe entropy enhancement")
+    cmd.Flags().Int(flagWords, 0, "Number of mnemonic words (12|15|18|21|24), overrides --entropy-size")
+    cmd.Flags().Bool(flagVerify, false, "Prompt to re-enter the mnemonic before finishing")
+    cmd.Flags().Bool(flagForceEntropy, false, "Generate the mnemonic even if the entropy health check fails")
+    addMnemonicDisplayFlags(cmd)
     
     return cmd
 }
@@ -50,16 +81,25 @@ func generateMnemonic(cmd *cobra.Command, _ []string) error {
         return fmt.Errorf("failed to get entropy size: %w", err)
     }
 
-    if entropySize < minEntropySize {
+    words, _ := cmd.Flags().GetInt(flagWords)
+    if words != 0 {
+        if entropySize, err = entropySizeForWords(words); err != nil {
+            return err
+        }
+    }
+
+    quantumSafe, _ := cmd.Flags().GetBool(flagQuantumSafe)
+    if entropySize < minEntropySize && quantumSafe {
         return fmt.Errorf("entropy size must be at least %d bits for quantum safety", minEntropySize)
     }
 
     var entropy []byte
+    buf := bufio.NewReader(cmd.InOrStdin())
     useUserEntropy, _ := cmd.Flags().GetBool(flagUserEntropy)
     
     if useUserEntropy {
-        entropy, err = getUserEntropy(cmd, entropySize)
-    } else {
+        entropy, err = readUserEntropy(cmd, buf, entropySize)
+    } else if err = ensureHealthyEntropy(cmd); err == nil {
         entropy, err = getSystemEntropy(entropySize)
     }
     
@@ -67,7 +107,6 @@ func generateMnemonic(cmd *cobra.Command, _ []string) error {
         return err
     }
 
-    quantumSafe, _ := cmd.Flags().GetBool(flagQuantumSafe)
     if quantumSafe {
         entropy = enhanceEntropyForQuantumSafety(entropy)
     }
@@ -77,8 +116,15 @@ func generateMnemonic(cmd *cobra.Command, _ []string) error {
         return fmt.Errorf("failed to generate mnemonic: %w", err)
     }
 
-    cmd.Println("\nYour quantum-safe mnemonic phrase (keep this secure):")
-    cmd.Println(mnemonic)
+    if err := displayMnemonic(cmd, buf, mnemonic); err != nil {
+        return err
+    }
+
+    if verify, _ := cmd.Flags().GetBool(flagVerify); verify {
+        if err := verifyMnemonic(cmd, buf, mnemonic); err != nil {
+            return err
+        }
+    }
     
     if entropySize < recommendedEntropy {
         cmd.Printf("\nNote: For maximum quantum safety, consider using --entropy-size=%d\n", recommendedEntropy)
@@ -88,16 +134,14 @@ func generateMnemonic(cmd *cobra.Command, _ []string) error {
 }
 
 func getUserEntropy(cmd *cobra.Command, entropySize int) ([]byte, error) {
+    return readUserEntropy(cmd, bufio.NewReader(cmd.InOrStdin()), entropySize)
+}
+
+func readUserEntropy(cmd *cobra.Command, buf *bufio.Reader, entropySize int) ([]byte, error) {
     minChars := entropySize / 6 // conservative estimate for base-64
-    buf := bufio.NewReader(cmd.InOrStdin())
-    
-    cmd.PrintErrln("\nWARNING: User-provided entropy is not recommended for production use.")
-    prompt := fmt.Sprintf(
-        "Please enter at least %d characters of entropy (more is better):",
-        minChars,
-    )
     
-    inputEntropy, err := input.GetString(prompt, buf)
+    cmd.PrintErrln("\n" + localize(cmd, msgEntropyWarning))
+    inputEntropy, err := input.GetString(localize(cmd, msgEntropyPrompt, minChars), buf)
     if err != nil {
         return nil, fmt.Errorf("failed to read entropy: %w", err)
     }
@@ -111,7 +155,7 @@ func getUserEntropy(cmd *cobra.Command, entropySize int) ([]byte, error) {
     }
 
     conf, err := input.GetConfirmation(
-        fmt.Sprintf("Confirm entropy input length: %d chars", len(inputEntropy)),
+        localize(cmd, msgEntropyConfirm, len(inputEntropy)),
         buf,
         cmd.ErrOrStderr(),
     )
@@ -147,3 +191,26 @@ func enhanceEntropyForQuantumSafety(entropy []byte) []byte {
     
     return result
 }
+
+func entropySizeForWords(words int) (int, error) {
+    size, ok := mnemonicWordCounts[words]
+    if !ok {
+        return 0, fmt.Errorf("invalid number of words %d, must be one of 12, 15, 18, 21 or 24", words)
+    }
+    return size, nil
+}
+
+// verifyMnemonic asks the user to re-enter the mnemonic and fails if it does not match.
+func verifyMnemonic(cmd *cobra.Command, buf *bufio.Reader, mnemonic string) error {
+    entered, err := input.GetString("\n"+localize(cmd, msgMnemonicReenter), buf)
+    if err != nil {
+        return fmt.Errorf("failed to read mnemonic: %w", err)
+    }
+
+    if strings.Join(strings.Fields(entered), " ") != strings.Join(strings.Fields(mnemonic), " ") {
+        return fmt.Errorf("mnemonic verification failed: the entered phrase does not match")
+    }
+
+    cmd.PrintErrln(localize(cmd, msgMnemonicVerified))
+    return nil
+}
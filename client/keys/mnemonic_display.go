@@ -0,0 +1,263 @@
+package keys
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-sdk/client/input"
+	"github.com/baron-chain/cosmos-sdk/crypto"
+	sdkerrors "github.com/baron-chain/cosmos-sdk/types/errors"
+)
+
+const (
+	flagPager      = "pager"
+	flagChunkWords = "chunk-words"
+	flagEncryptTo  = "encrypt-to"
+
+	blockTypeMnemonic = "BARON CHAIN MNEMONIC"
+
+	mnemonicArmorVersion = "1"
+	armorSaltSize        = 16
+	maxQuizAttempts      = 3
+
+	// enter and leave the alternate screen of the terminal, whose content is
+	// not kept in the scrollback, clearing it in between
+	ansiAltScreenEnter = "\x1b[?1049h\x1b[H\x1b[2J"
+	ansiAltScreenLeave = "\x1b[H\x1b[2J\x1b[3J\x1b[?1049l"
+	ansiClearScreen    = "\x1b[H\x1b[2J\x1b[3J"
+)
+
+// quizWordIndex returns the index, below n, of the word asked by a chunk quiz.
+var quizWordIndex = func(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// addMnemonicDisplayFlags adds the flags controlling how a new mnemonic is
+// shown to the user.
+func addMnemonicDisplayFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(flagPager, false, "Show the mnemonic on a separate terminal screen, cleared once it is recorded")
+	cmd.Flags().Int(flagChunkWords, 0, "Show the mnemonic this many words at a time, asking for one word of every chunk")
+	cmd.Flags().String(flagEncryptTo, "", "Write the mnemonic to this new file, encrypted with a passphrase, instead of showing it")
+}
+
+// displayMnemonic shows the mnemonic as requested by the flags of the
+// command, or writes it encrypted to a file.
+func displayMnemonic(cmd *cobra.Command, buf *bufio.Reader, mnemonic string) error {
+	pager, _ := cmd.Flags().GetBool(flagPager)
+	chunkWords, _ := cmd.Flags().GetInt(flagChunkWords)
+	encryptTo, _ := cmd.Flags().GetString(flagEncryptTo)
+
+	if chunkWords < 0 {
+		return fmt.Errorf("--%s must not be negative", flagChunkWords)
+	}
+	if encryptTo != "" {
+		if verify, _ := cmd.Flags().GetBool(flagVerify); pager || chunkWords > 0 || verify {
+			return fmt.Errorf("--%s can't be used with --%s, --%s or --%s", flagEncryptTo, flagPager, flagChunkWords, flagVerify)
+		}
+		return writeEncryptedMnemonic(cmd, buf, encryptTo, mnemonic)
+	}
+
+	out := cmd.OutOrStdout()
+	if pager {
+		fmt.Fprint(out, ansiAltScreenEnter)
+		// the screen is cleared even if a quiz fails
+		defer fmt.Fprint(out, ansiAltScreenLeave)
+	}
+
+	cmd.Println("\n" + localize(cmd, msgMnemonicDisplay))
+	if chunkWords == 0 {
+		cmd.Println(mnemonic)
+	} else {
+		words := strings.Fields(mnemonic)
+		for start := 0; start < len(words); start += chunkWords {
+			end := start + chunkWords
+			if end > len(words) {
+				end = len(words)
+			}
+
+			if pager && start > 0 {
+				fmt.Fprint(out, ansiClearScreen)
+			}
+			if err := showMnemonicChunk(cmd, buf, words, start, end); err != nil {
+				return err
+			}
+		}
+	}
+
+	if pager {
+		if _, err := input.GetString(localize(cmd, msgMnemonicPagerContinue), buf); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+	return nil
+}
+
+// showMnemonicChunk prints the words of the chunk, with their numbers, then
+// asks for one of them.
+func showMnemonicChunk(cmd *cobra.Command, buf *bufio.Reader, words []string, start, end int) error {
+	cmd.Println(localize(cmd, msgMnemonicChunk, start+1, end, len(words)))
+	for i := start; i < end; i++ {
+		cmd.Printf("%3d. %s\n", i+1, words[i])
+	}
+
+	offset, err := quizWordIndex(end - start)
+	if err != nil {
+		return err
+	}
+	i := start + offset
+
+	for attempt := 1; ; attempt++ {
+		entered, err := input.GetString(localize(cmd, msgMnemonicQuiz, i+1), buf)
+		if err != nil {
+			return fmt.Errorf("failed to read mnemonic word: %w", err)
+		}
+		if strings.TrimSpace(entered) == words[i] {
+			return nil
+		}
+		if attempt == maxQuizAttempts {
+			return fmt.Errorf("mnemonic verification failed: word #%d was not entered correctly", i+1)
+		}
+		cmd.PrintErrln(localize(cmd, msgMnemonicQuizFailed))
+	}
+}
+
+// writeEncryptedMnemonic prompts for a passphrase and writes the mnemonic
+// encrypted with it to the file at path, which must not exist.
+func writeEncryptedMnemonic(cmd *cobra.Command, buf *bufio.Reader, path, mnemonic string) error {
+	passphrase, err := input.GetPassword(localize(cmd, msgMnemonicEncryptPassphrase), buf)
+	if err != nil {
+		return err
+	}
+	repeated, err := input.GetPassword(localize(cmd, msgMnemonicEncryptRepeat), buf)
+	if err != nil {
+		return err
+	}
+	if passphrase != repeated {
+		return errors.New("passphrases don't match")
+	}
+
+	armored, err := EncryptArmorMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create mnemonic file: %w", err)
+	}
+	if _, err := f.WriteString(armored); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write mnemonic file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write mnemonic file: %w", err)
+	}
+
+	cmd.PrintErrln(localize(cmd, msgMnemonicWritten, path))
+	return nil
+}
+
+// EncryptArmorMnemonic encrypts the mnemonic with a key derived from the
+// passphrase by the KDF of the crypto policy, and armors it.
+func EncryptArmorMnemonic(mnemonic, passphrase string) (string, error) {
+	return encryptArmor(blockTypeMnemonic, mnemonicArmorVersion, []byte(mnemonic), passphrase)
+}
+
+// UnarmorDecryptMnemonic decrypts a mnemonic armored by EncryptArmorMnemonic.
+func UnarmorDecryptMnemonic(armored, passphrase string) (string, error) {
+	mnemonic, err := unarmorDecrypt(armored, blockTypeMnemonic, mnemonicArmorVersion, passphrase)
+	if err != nil {
+		return "", err
+	}
+	return string(mnemonic), nil
+}
+
+// encryptArmor encrypts the plaintext with a key derived from the passphrase by
+// the KDF of the crypto policy, and armors it as a block of the given type and
+// version.
+func encryptArmor(blockType, version string, plaintext []byte, passphrase string) (string, error) {
+	kdf, err := crypto.PolicyKDF()
+	if err != nil {
+		return "", err
+	}
+
+	salt := make([]byte, armorSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+	key, err := kdf.DeriveKey([]byte(passphrase), salt)
+	if err != nil {
+		return "", sdkerrors.Wrapf(err, "error generating %s key from passphrase", kdf.Name())
+	}
+
+	cipherName, encBytes, err := crypto.EncryptSymmetric(plaintext, key)
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]string{
+		"version": version,
+		"kdf":     kdf.Name(),
+		"salt":    fmt.Sprintf("%X", salt),
+	}
+	if params := kdf.Params(); params != "" {
+		header["kdf-params"] = params
+	}
+	if cipherName != crypto.CipherXSalsa20Poly1305 {
+		header["cipher"] = cipherName
+	}
+	return crypto.EncodeArmor(blockType, header, encBytes), nil
+}
+
+// unarmorDecrypt decrypts a block armored by encryptArmor.
+func unarmorDecrypt(armored, blockType, version, passphrase string) ([]byte, error) {
+	armorType, header, encBytes, err := crypto.DecodeArmor(armored)
+	if err != nil {
+		return nil, err
+	}
+	if armorType != blockType {
+		return nil, fmt.Errorf("unrecognized armor type %q, expected: %q", armorType, blockType)
+	}
+	if header["version"] != version {
+		return nil, fmt.Errorf("unsupported %s armor version %q", strings.ToLower(blockType), header["version"])
+	}
+
+	kdf, err := crypto.GetKDF(header["kdf"], header["kdf-params"])
+	if err != nil {
+		return nil, err
+	}
+	if err := crypto.GetPolicy().CheckKDF(kdf); err != nil {
+		return nil, err
+	}
+
+	salt, err := hex.DecodeString(header["salt"])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding salt: %w", err)
+	}
+	key, err := kdf.DeriveKey([]byte(passphrase), salt)
+	if err != nil {
+		return nil, sdkerrors.Wrapf(err, "error generating %s key from passphrase", kdf.Name())
+	}
+
+	plaintext, err := crypto.DecryptSymmetric(header["cipher"], encBytes, key)
+	if err != nil {
+		if errors.Is(err, crypto.ErrNotFIPSApproved) {
+			return nil, err
+		}
+		return nil, sdkerrors.ErrWrongPassword
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,98 @@
+package keys
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	sdkerrors "github.com/baron-chain/cosmos-sdk/types/errors"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestDisplayMnemonic(t *testing.T) {
+	// always ask for the last word of the chunk
+	defaultQuizWordIndex := quizWordIndex
+	quizWordIndex = func(n int) (int, error) { return n - 1, nil }
+	t.Cleanup(func() { quizWordIndex = defaultQuizWordIndex })
+
+	run := func(in string, args ...string) (string, error) {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool(flagVerify, false, "")
+		addMnemonicDisplayFlags(cmd)
+		require.NoError(t, cmd.ParseFlags(args))
+
+		out := new(bytes.Buffer)
+		cmd.SetOut(out)
+		cmd.SetErr(out)
+		err := displayMnemonic(cmd, bufio.NewReader(strings.NewReader(in)), testMnemonic)
+		return out.String(), err
+	}
+
+	out, err := run("")
+	require.NoError(t, err)
+	require.Contains(t, out, testMnemonic)
+	require.NotContains(t, out, "\x1b")
+
+	out, err = run("\n", "--pager")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, ansiAltScreenEnter))
+	require.True(t, strings.HasSuffix(out, ansiAltScreenLeave))
+
+	out, err = run("abandon\nabandon\nabout\n", "--chunk-words=5")
+	require.NoError(t, err)
+	require.Contains(t, out, "Words 11 to 12 of 12:")
+	require.Contains(t, out, " 12. about\n")
+	require.NotContains(t, out, testMnemonic)
+
+	// the quiz is retried, then fails
+	out, err = run("abandon\nabout\n", "--chunk-words=12")
+	require.NoError(t, err)
+	require.Contains(t, out, "Incorrect word")
+	_, err = run("x\nx\nx\n", "--chunk-words=12", "--pager")
+	require.ErrorContains(t, err, "word #12 was not entered correctly")
+
+	_, err = run("", "--chunk-words=-1")
+	require.Error(t, err)
+	_, err = run("", "--encrypt-to=x", "--pager")
+	require.Error(t, err)
+}
+
+func TestEncryptMnemonic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mnemonic.asc")
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool(flagVerify, false, "")
+	addMnemonicDisplayFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--encrypt-to=" + path}))
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+
+	in := "passphrase\npassphrase\n"
+	require.NoError(t, displayMnemonic(cmd, bufio.NewReader(strings.NewReader(in)), testMnemonic))
+	require.NotContains(t, out.String(), testMnemonic)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	armored, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(armored), "abandon")
+	mnemonic, err := UnarmorDecryptMnemonic(string(armored), "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, testMnemonic, mnemonic)
+	_, err = UnarmorDecryptMnemonic(string(armored), "wrong passphrase")
+	require.ErrorIs(t, err, sdkerrors.ErrWrongPassword)
+
+	// existing files are not overwritten
+	require.ErrorContains(t, displayMnemonic(cmd, bufio.NewReader(strings.NewReader(in)), testMnemonic), "failed to create mnemonic file")
+	require.Error(t, displayMnemonic(cmd, bufio.NewReader(strings.NewReader("passphrase\nmismatch1\n")), testMnemonic))
+}
@@ -1,12 +1,21 @@
 package keys
 
 import (
+    "bufio"
+    "context"
     "fmt"
     "strings"
     "testing"
 
+    "github.com/cometbft/cometbft/libs/cli"
     "github.com/stretchr/testify/require"
+    "github.com/baron-chain/cosmos-sdk/client"
+    "github.com/baron-chain/cosmos-sdk/client/flags"
+    clienttestutil "github.com/baron-chain/cosmos-sdk/client/testutil"
+    "github.com/baron-chain/cosmos-sdk/crypto/hd"
+    "github.com/baron-chain/cosmos-sdk/crypto/keyring"
     "github.com/baron-chain/cosmos-sdk/testutil"
+    sdk "github.com/baron-chain/cosmos-sdk/types"
 )
 
 func TestMnemonicGeneration(t *testing.T) {
@@ -64,6 +73,38 @@ func TestMnemonicGeneration(t *testing.T) {
             input:        strings.Repeat("quantum", 20) + "\ny\n",
             expectError:  false,
         },
+        {
+            name:        "24 words",
+            args:        []string{fmt.Sprintf("--%s=24", flagWords)},
+            expectError: false,
+        },
+        {
+            name:         "12 words requires quantum-safe disabled",
+            args:         []string{fmt.Sprintf("--%s=12", flagWords)},
+            expectError:  true,
+            errorMessage: fmt.Sprintf("entropy size must be at least %d bits for quantum safety", minEntropySize),
+        },
+        {
+            name: "12 words without quantum-safe",
+            args: []string{
+                fmt.Sprintf("--%s=12", flagWords),
+                fmt.Sprintf("--%s=false", flagQuantumSafe),
+            },
+            expectError: false,
+        },
+        {
+            name:         "invalid word count",
+            args:         []string{fmt.Sprintf("--%s=13", flagWords)},
+            expectError:  true,
+            errorMessage: "invalid number of words 13",
+        },
+        {
+            name:         "verify with wrong phrase",
+            args:         []string{fmt.Sprintf("--%s", flagVerify)},
+            input:        "not the phrase\n",
+            expectError:  true,
+            errorMessage: "mnemonic verification failed",
+        },
         {
             name: "quantum-safe disabled",
             args: []string{
@@ -192,3 +233,84 @@ func TestUserEntropyValidation(t *testing.T) {
         })
     }
 }
+
+func TestMnemonicVerify(t *testing.T) {
+    cmd := MnemonicKeyCommand()
+    mockIn := testutil.ApplyMockIODiscardOutErr(cmd)
+
+    mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+    mockIn.Reset(mnemonic + "\n")
+    require.NoError(t, verifyMnemonic(cmd, bufio.NewReader(cmd.InOrStdin()), mnemonic))
+
+    mockIn.Reset("  " + strings.ReplaceAll(mnemonic, " ", "   ") + "\n")
+    require.NoError(t, verifyMnemonic(cmd, bufio.NewReader(cmd.InOrStdin()), mnemonic))
+
+    mockIn.Reset("abandon about\n")
+    require.Error(t, verifyMnemonic(cmd, bufio.NewReader(cmd.InOrStdin()), mnemonic))
+}
+
+func TestMnemonicRecover(t *testing.T) {
+    for _, words := range []int{12, 24} {
+        t.Run(fmt.Sprintf("%d words", words), func(t *testing.T) {
+            cmd := MnemonicKeyCommand()
+            _, mockOut := testutil.ApplyMockIO(cmd)
+            cmd.SetArgs([]string{
+                fmt.Sprintf("--%s=%d", flagWords, words),
+                fmt.Sprintf("--%s=false", flagQuantumSafe),
+            })
+            require.NoError(t, cmd.Execute())
+
+            var mnemonic string
+            for _, line := range strings.Split(mockOut.String(), "\n") {
+                if len(strings.Fields(line)) == words {
+                    mnemonic = strings.TrimSpace(line)
+                }
+            }
+            require.NotEmpty(t, mnemonic)
+
+            // the generated mnemonic is accepted by keys add --recover
+            addCmd := AddKeyCommand()
+            addCmd.Flags().AddFlagSet(Commands("home").PersistentFlags())
+            cdc := clienttestutil.MakeTestCodec(t)
+            mockIn := testutil.ApplyMockIODiscardOutErr(addCmd)
+            kbHome := t.TempDir()
+
+            clientCtx := client.Context{}.WithKeyringDir(kbHome).WithInput(mockIn).WithCodec(cdc)
+            ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+            addCmd.SetArgs([]string{
+                "recovered",
+                fmt.Sprintf("--%s=%s", flags.FlagHome, kbHome),
+                fmt.Sprintf("--%s=%s", cli.OutputFlag, OutputFormatText),
+                fmt.Sprintf("--%s=%s", flags.FlagKeyType, hd.Secp256k1Type),
+                fmt.Sprintf("--%s=%s", flags.FlagKeyringBackend, keyring.BackendTest),
+                fmt.Sprintf("--%s", flagRecover),
+            })
+            mockIn.Reset(mnemonic + "\n")
+            require.NoError(t, addCmd.ExecuteContext(ctx))
+
+            kb, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, kbHome, mockIn, cdc)
+            require.NoError(t, err)
+            k, err := kb.Key("recovered")
+            require.NoError(t, err)
+            addr, err := k.GetAddress()
+            require.NoError(t, err)
+
+            expected, err := keyring.NewInMemory(cdc).NewAccount("expected", mnemonic, keyring.DefaultBIP39Passphrase, sdk.FullFundraiserPath, hd.Secp256k1)
+            require.NoError(t, err)
+            expectedAddr, err := expected.GetAddress()
+            require.NoError(t, err)
+            require.Equal(t, expectedAddr, addr)
+        })
+    }
+}
+
+func TestEntropySizeForWords(t *testing.T) {
+    for words, bits := range map[int]int{12: 128, 15: 160, 18: 192, 21: 224, 24: 256} {
+        size, err := entropySizeForWords(words)
+        require.NoError(t, err)
+        require.Equal(t, bits, size)
+    }
+
+    _, err := entropySizeForWords(25)
+    require.Error(t, err)
+}
@@ -0,0 +1,356 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+const (
+	flagNamespace = "namespace"
+
+	// KeyNamespacesFileName is the name of the file, within the keyring
+	// directory, setting the policies of the key namespaces.
+	KeyNamespacesFileName = "keyring-namespaces.json"
+
+	// NamespaceSeparator separates the namespaces of a key name, e.g. the key
+	// ops/validator1 is in the namespace ops. Key names are stored as is in the
+	// keyring backends, so that the keys of a namespace are those whose name
+	// has its prefix.
+	NamespaceSeparator = "/"
+)
+
+// KeyNamespacePolicy restricts the keys created in a namespace, and in its
+// sub-namespaces which have no policy of their own.
+type KeyNamespacePolicy struct {
+	// Backends are the keyring backends the keys of the namespace may be
+	// stored in, e.g. "os" or "pkcs11". All backends when empty.
+	Backends []string `json:"backends,omitempty"`
+	// Algorithms are the allowed key algorithms, as returned by the Type
+	// method of keys, e.g. "dilithium". All algorithms when empty. Multisig
+	// keys are not restricted, their members are.
+	Algorithms []string `json:"algorithms,omitempty"`
+}
+
+// KeyNamespacesConfig sets the policies of key namespaces, e.g.
+//
+//	{"namespaces": {"ops": {"backends": ["os", "pkcs11"], "algorithms": ["dilithium"]}}}
+type KeyNamespacesConfig struct {
+	Namespaces map[string]KeyNamespacePolicy `json:"namespaces"`
+}
+
+// LoadKeyNamespacesConfig reads the KeyNamespacesFileName file of the keyring
+// directory. It returns nil when the file doesn't exist, i.e. when no
+// namespace has a policy.
+func LoadKeyNamespacesConfig(keyringDir string) (*KeyNamespacesConfig, error) {
+	file := filepath.Join(keyringDir, KeyNamespacesFileName)
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read key namespaces config: %w", err)
+	}
+
+	var cfg KeyNamespacesConfig
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse key namespaces config %s: %w", file, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid key namespaces config %s: %w", file, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks the namespaces are valid, and their backends known.
+func (c KeyNamespacesConfig) Validate() error {
+	backends := []string{
+		keyring.BackendOS, keyring.BackendFile, keyring.BackendKWallet, keyring.BackendPass,
+		keyring.BackendTest, keyring.BackendMemory, keyring.BackendCommand, keyring.BackendPKCS11,
+	}
+	for ns, p := range c.Namespaces {
+		if err := ValidateKeyName(ns); err != nil {
+			return fmt.Errorf("invalid namespace %q: %w", ns, err)
+		}
+		for _, b := range p.Backends {
+			if !containsString(backends, b) {
+				return fmt.Errorf("unknown backend %q of namespace %s", b, ns)
+			}
+		}
+		for _, algo := range p.Algorithms {
+			if algo == "" {
+				return fmt.Errorf("empty algorithm of namespace %s", ns)
+			}
+		}
+	}
+	return nil
+}
+
+// Policy returns the policy of the key name: the one of its innermost
+// namespace which has a policy, and that namespace. It returns false when no
+// namespace of the key has a policy.
+func (c KeyNamespacesConfig) Policy(name string) (string, KeyNamespacePolicy, bool) {
+	for ns := KeyNamespace(name); ns != ""; ns = KeyNamespace(ns) {
+		if p, ok := c.Namespaces[ns]; ok {
+			return ns, p, true
+		}
+	}
+	return "", KeyNamespacePolicy{}, false
+}
+
+// KeyNamespace returns the namespace of a key name, e.g. ops for
+// ops/validator1, and ops for the namespace ops/eu, or "" when it has none.
+func KeyNamespace(name string) string {
+	i := strings.LastIndex(name, NamespaceSeparator)
+	if i < 0 {
+		return ""
+	}
+	return name[:i]
+}
+
+// InKeyNamespace reports whether the key name is in the namespace, or in one
+// of its sub-namespaces.
+func InKeyNamespace(name, namespace string) bool {
+	namespace = strings.TrimSuffix(namespace, NamespaceSeparator)
+	return namespace == "" || strings.HasPrefix(name, namespace+NamespaceSeparator)
+}
+
+// ValidateKeyName checks that none of the namespaces of a key name, nor its
+// last element, are empty, e.g. that it isn't ops//validator1 or ops/.
+func ValidateKeyName(name string) error {
+	for _, elem := range strings.Split(name, NamespaceSeparator) {
+		if strings.TrimSpace(elem) == "" {
+			return fmt.Errorf("key name %q has an empty namespace or name", name)
+		}
+	}
+	return nil
+}
+
+// namespacedKeyring enforces the validity of key names, and the policies of
+// their namespaces, on the keys it creates, imports and renames. Keys of a
+// disallowed algorithm are deleted once created, as their algorithm is only
+// known then.
+type namespacedKeyring struct {
+	keyring.Keyring
+	config *KeyNamespacesConfig
+}
+
+// enforceKeyNamespaces makes the command use a namespacedKeyring, with the
+// namespace policies of its keyring directory. It is only applied to the
+// commands creating keys, so that the others can still reach the optional
+// interfaces of the keyring.
+func enforceKeyNamespaces(cmd *cobra.Command) *cobra.Command {
+	runE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		clientCtx := client.GetClientContextFromCmd(cmd)
+		if clientCtx.Keyring != nil {
+			cfg, err := LoadKeyNamespacesConfig(clientCtx.KeyringDir)
+			if err != nil {
+				return err
+			}
+			kr := &namespacedKeyring{Keyring: clientCtx.Keyring, config: cfg}
+			if err := client.SetCmdClientContext(cmd, clientCtx.WithKeyring(kr)); err != nil {
+				return err
+			}
+		}
+		return runE(cmd, args)
+	}
+	return cmd
+}
+
+// Close closes the wrapped keyring, e.g. the session of the pkcs11 backend.
+func (kr *namespacedKeyring) Close() error {
+	return keyring.Close(kr.Keyring)
+}
+
+// checkName checks the name is valid, and the keys of its namespace may be
+// stored in the backend.
+func (kr *namespacedKeyring) checkName(name string) error {
+	if err := ValidateKeyName(name); err != nil {
+		return err
+	}
+	if kr.config == nil {
+		return nil
+	}
+	ns, p, ok := kr.config.Policy(name)
+	if ok && len(p.Backends) > 0 && !containsString(p.Backends, kr.Backend()) {
+		return newKeyError(ErrPolicyViolation, "keys of namespace %s can't be stored in the %s backend, only in %s",
+			ns, kr.Backend(), strings.Join(p.Backends, ", "))
+	}
+	return nil
+}
+
+// checkAlgo checks the algorithm of the key is allowed in its namespace.
+func (kr *namespacedKeyring) checkAlgo(name string, pub types.PubKey, keyType keyring.KeyType) error {
+	if kr.config == nil || keyType == keyring.TypeMulti {
+		return nil
+	}
+	ns, p, ok := kr.config.Policy(name)
+	if ok && len(p.Algorithms) > 0 && !containsString(p.Algorithms, pub.Type()) {
+		return newKeyError(ErrPolicyViolation, "%s keys are not allowed in namespace %s, only %s",
+			pub.Type(), ns, strings.Join(p.Algorithms, ", "))
+	}
+	return nil
+}
+
+// checkCreated checks the algorithm of the created key, which is deleted when
+// it isn't allowed.
+func (kr *namespacedKeyring) checkCreated(name string) error {
+	k, err := kr.Keyring.Key(name)
+	if err != nil {
+		return err
+	}
+	pub, err := k.GetPubKey()
+	if err != nil {
+		return err
+	}
+	if err := kr.checkAlgo(name, pub, k.GetType()); err != nil {
+		if delErr := kr.Keyring.Delete(name); delErr != nil {
+			return fmt.Errorf("%w, and failed to delete it: %v", err, delErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (kr *namespacedKeyring) NewMnemonic(uid string, language keyring.Language, hdPath, bip39Passphrase string, algo keyring.SignatureAlgo) (*keyring.Record, string, error) {
+	if err := kr.checkName(uid); err != nil {
+		return nil, "", err
+	}
+	k, mnemonic, err := kr.Keyring.NewMnemonic(uid, language, hdPath, bip39Passphrase, algo)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := kr.checkCreated(uid); err != nil {
+		return nil, "", err
+	}
+	return k, mnemonic, nil
+}
+
+func (kr *namespacedKeyring) NewAccount(uid, mnemonic, bip39Passphrase, hdPath string, algo keyring.SignatureAlgo) (*keyring.Record, error) {
+	if err := kr.checkName(uid); err != nil {
+		return nil, err
+	}
+	return kr.created(kr.Keyring.NewAccount(uid, mnemonic, bip39Passphrase, hdPath, algo))
+}
+
+func (kr *namespacedKeyring) SaveLedgerKey(uid string, algo keyring.SignatureAlgo, hrp string, coinType, account, index uint32) (*keyring.Record, error) {
+	if err := kr.checkName(uid); err != nil {
+		return nil, err
+	}
+	return kr.created(kr.Keyring.SaveLedgerKey(uid, algo, hrp, coinType, account, index))
+}
+
+func (kr *namespacedKeyring) SaveOfflineKey(uid string, pubkey types.PubKey) (*keyring.Record, error) {
+	if err := kr.checkName(uid); err != nil {
+		return nil, err
+	}
+	if err := kr.checkAlgo(uid, pubkey, keyring.TypeOffline); err != nil {
+		return nil, err
+	}
+	return kr.Keyring.SaveOfflineKey(uid, pubkey)
+}
+
+func (kr *namespacedKeyring) SaveMultisig(uid string, pubkey types.PubKey) (*keyring.Record, error) {
+	if err := kr.checkName(uid); err != nil {
+		return nil, err
+	}
+	return kr.Keyring.SaveMultisig(uid, pubkey)
+}
+
+func (kr *namespacedKeyring) ImportPrivKey(uid, armor, passphrase string) error {
+	if err := kr.checkName(uid); err != nil {
+		return err
+	}
+	if err := kr.Keyring.ImportPrivKey(uid, armor, passphrase); err != nil {
+		return err
+	}
+	return kr.checkCreated(uid)
+}
+
+func (kr *namespacedKeyring) ImportPrivKeyHex(uid, privKey, algoStr string) error {
+	if err := kr.checkName(uid); err != nil {
+		return err
+	}
+	if err := kr.Keyring.ImportPrivKeyHex(uid, privKey, algoStr); err != nil {
+		return err
+	}
+	return kr.checkCreated(uid)
+}
+
+func (kr *namespacedKeyring) ImportPubKey(uid, armor string) error {
+	if err := kr.checkName(uid); err != nil {
+		return err
+	}
+	if err := kr.Keyring.ImportPubKey(uid, armor); err != nil {
+		return err
+	}
+	return kr.checkCreated(uid)
+}
+
+// Rename checks the key may be moved to the namespace of its new name before
+// renaming it.
+func (kr *namespacedKeyring) Rename(from, to string) error {
+	if err := kr.checkName(to); err != nil {
+		return err
+	}
+	k, err := kr.Keyring.Key(from)
+	if err != nil {
+		return err
+	}
+	pub, err := k.GetPubKey()
+	if err != nil {
+		return err
+	}
+	if err := kr.checkAlgo(to, pub, k.GetType()); err != nil {
+		return err
+	}
+	return kr.Keyring.Rename(from, to)
+}
+
+// created checks the key created by a Keyring method returning it.
+func (kr *namespacedKeyring) created(k *keyring.Record, err error) (*keyring.Record, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err := kr.checkCreated(k.Name); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// filterKeyNamespace returns the records of the keys in the namespace, or in
+// its sub-namespaces, sorted by name.
+func filterKeyNamespace(records []*keyring.Record, namespace string) []*keyring.Record {
+	var filtered []*keyring.Record
+	for _, r := range records {
+		if InKeyNamespace(r.Name, namespace) {
+			filtered = append(filtered, r)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].Name < filtered[j].Name })
+	return filtered
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
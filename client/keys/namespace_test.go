@@ -0,0 +1,100 @@
+package keys
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clienttestutil "github.com/cosmos/cosmos-sdk/client/testutil"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestKeyNamespaces(t *testing.T) {
+	require.Equal(t, "ops/eu", KeyNamespace("ops/eu/validator1"))
+	require.Equal(t, "ops", KeyNamespace("ops/eu"))
+	require.Equal(t, "", KeyNamespace("validator1"))
+
+	require.True(t, InKeyNamespace("ops/eu/validator1", "ops"))
+	require.True(t, InKeyNamespace("ops/validator1", "ops/"))
+	require.False(t, InKeyNamespace("operator", "op"))
+	require.False(t, InKeyNamespace("ops", "ops"))
+
+	require.NoError(t, ValidateKeyName("ops/validator1"))
+	for _, invalid := range []string{"ops//validator1", "ops/", "/validator1", ""} {
+		require.Error(t, ValidateKeyName(invalid), invalid)
+	}
+
+	cfg := KeyNamespacesConfig{Namespaces: map[string]KeyNamespacePolicy{
+		"ops":    {Backends: []string{keyring.BackendOS}},
+		"ops/eu": {Algorithms: []string{"dilithium"}},
+	}}
+	require.NoError(t, cfg.Validate())
+	ns, p, ok := cfg.Policy("ops/eu/validator1")
+	require.True(t, ok)
+	require.Equal(t, "ops/eu", ns)
+	require.Equal(t, []string{"dilithium"}, p.Algorithms)
+	ns, _, ok = cfg.Policy("ops/us/validator1")
+	require.True(t, ok)
+	require.Equal(t, "ops", ns)
+	_, _, ok = cfg.Policy("dev/faucet")
+	require.False(t, ok)
+
+	require.Error(t, KeyNamespacesConfig{Namespaces: map[string]KeyNamespacePolicy{"ops": {Backends: []string{"vault"}}}}.Validate())
+}
+
+func TestLoadKeyNamespacesConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadKeyNamespacesConfig(dir)
+	require.NoError(t, err)
+	require.Nil(t, cfg)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, KeyNamespacesFileName), []byte(`{"namespaces":{"ops":{"backends":["os"]}}}`), 0o600))
+	cfg, err = LoadKeyNamespacesConfig(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"os"}, cfg.Namespaces["ops"].Backends)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, KeyNamespacesFileName), []byte(`{"namespaces":{"ops//x":{}}}`), 0o600))
+	_, err = LoadKeyNamespacesConfig(dir)
+	require.Error(t, err)
+}
+
+func TestNamespacedKeyring(t *testing.T) {
+	kr := &namespacedKeyring{
+		Keyring: keyring.NewInMemory(clienttestutil.MakeTestCodec(t)),
+		config: &KeyNamespacesConfig{Namespaces: map[string]KeyNamespacePolicy{
+			"ops": {Backends: []string{keyring.BackendOS}},
+			"dev": {Algorithms: []string{"dilithium"}},
+		}},
+	}
+	isPolicyViolation := func(err error) bool { return errors.Is(err, ErrPolicyViolation) }
+
+	_, err := kr.NewAccount("ops/validator1", testdata.TestMnemonic, "", sdk.FullFundraiserPath, hd.Secp256k1)
+	require.True(t, isPolicyViolation(err), err)
+
+	// the key of a disallowed algorithm is deleted once created
+	_, err = kr.NewAccount("dev/faucet", testdata.TestMnemonic, "", sdk.FullFundraiserPath, hd.Secp256k1)
+	require.True(t, isPolicyViolation(err), err)
+	_, err = kr.Key("dev/faucet")
+	require.Error(t, err)
+
+	_, err = kr.NewAccount("test/faucet", testdata.TestMnemonic, "", sdk.FullFundraiserPath, hd.Secp256k1)
+	require.NoError(t, err)
+	require.True(t, isPolicyViolation(kr.Rename("test/faucet", "dev/faucet")))
+
+	_, pub, _ := testdata.KeyTestPubAddr()
+	_, err = kr.SaveOfflineKey("dev/watch", pub)
+	require.True(t, isPolicyViolation(err), err)
+	_, err = kr.SaveOfflineKey("ops//watch", pub)
+	require.Error(t, err)
+
+	records, err := kr.List()
+	require.NoError(t, err)
+	require.Len(t, filterKeyNamespace(records, "test"), 1)
+	require.Empty(t, filterKeyNamespace(records, "dev"))
+}
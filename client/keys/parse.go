@@ -1,7 +1,6 @@
 package keys
 
 import (
-    "encoding/hex"
     "encoding/json"
     "fmt"
     "io"
@@ -20,20 +19,36 @@ const (
 
 // KeyOutput represents the key parsing output
 type KeyOutput struct {
-    HumanReadable string   `json:"human_readable,omitempty" yaml:"human_readable,omitempty"`
-    HexBytes      string   `json:"hex_bytes,omitempty" yaml:"hex_bytes,omitempty"`
-    Bech32Formats []string `json:"bech32_formats,omitempty" yaml:"bech32_formats,omitempty"`
+    HumanReadable  string   `json:"human_readable,omitempty" yaml:"human_readable,omitempty"`
+    HexBytes       string   `json:"hex_bytes,omitempty" yaml:"hex_bytes,omitempty"`
+    ChecksumHex    string   `json:"checksum_hex,omitempty" yaml:"checksum_hex,omitempty"`
+    Bech32Formats  []string `json:"bech32_formats,omitempty" yaml:"bech32_formats,omitempty"`
+    Bech32mFormats []string `json:"bech32m_formats,omitempty" yaml:"bech32m_formats,omitempty"`
 }
 
 func (ko KeyOutput) String() string {
+    var parts []string
     if ko.HumanReadable != "" {
-        return fmt.Sprintf("Human readable part: %v\nBytes (hex): %s", ko.HumanReadable, ko.HexBytes)
+        parts = append(parts, fmt.Sprintf("Human readable part: %v\nBytes (hex): %s", ko.HumanReadable, ko.HexBytes))
     }
-    out := make([]string, len(ko.Bech32Formats))
-    for i, format := range ko.Bech32Formats {
-        out[i] = fmt.Sprintf("  - %s", format)
+    if ko.ChecksumHex != "" {
+        parts = append(parts, fmt.Sprintf("EIP-55 hex: %s", ko.ChecksumHex))
     }
-    return fmt.Sprintf("Bech32 Formats:\n%s", strings.Join(out, "\n"))
+    if len(ko.Bech32Formats) > 0 {
+        parts = append(parts, fmt.Sprintf("Bech32 Formats:\n%s", formatList(ko.Bech32Formats)))
+    }
+    if len(ko.Bech32mFormats) > 0 {
+        parts = append(parts, fmt.Sprintf("Bech32m Formats:\n%s", formatList(ko.Bech32mFormats)))
+    }
+    return strings.Join(parts, "\n")
+}
+
+func formatList(items []string) string {
+    out := make([]string, len(items))
+    for i, item := range items {
+        out[i] = fmt.Sprintf("  - %s", item)
+    }
+    return strings.Join(out, "\n")
 }
 
 // ParseKeyStringCommand creates a command to parse address formats
@@ -42,15 +57,23 @@ func ParseKeyStringCommand() *cobra.Command {
         Use:   "parse <address>",
         Short: "Parse address between hex and bech32 formats",
         Long: `Convert addresses between hexadecimal and bech32 formats.
-Supports both classic and quantum-safe address formats.`,
+Supports both classic and quantum-safe address formats.
+
+Bech32m (BIP-350) addresses and 0x prefixed hex addresses are accepted too; mixed-case
+hex addresses must have a valid EIP-55 checksum. With --address-format bech32m, hex
+addresses are converted to bech32m instead of bech32, and bech32 addresses to their
+bech32m form. With --address-format hex, the EIP-55 checksummed hex is printed too.`,
         Example: `$ baron-chain keys parse cosmos1...
 $ baron-chain keys parse 0A090909...
-$ baron-chain keys parse baron1...`,
+$ baron-chain keys parse baron1...
+$ baron-chain keys parse baron1... --address-format hex
+$ baron-chain keys parse 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed --address-format bech32m`,
         Args: cobra.ExactArgs(1),
         RunE: parseKey,
     }
 
     cmd.Flags().String(flagFormat, "text", "Output format (text|json|yaml)")
+    addAddressFormatFlag(cmd)
     return cmd
 }
 
@@ -62,8 +85,12 @@ func parseKey(cmd *cobra.Command, args []string) error {
 
     config, _ := sdk.GetSealedConfig(cmd.Context())
     output, _ := cmd.Flags().GetString(flagFormat)
+    addrFormat, err := getAddressFormat(cmd)
+    if err != nil {
+        return err
+    }
     
-    result, err := parseAddress(addr, config)
+    result, err := parseAddress(addr, config, addrFormat)
     if err != nil {
         return fmt.Errorf("failed to parse address: %w", err)
     }
@@ -71,33 +98,62 @@ func parseKey(cmd *cobra.Command, args []string) error {
     return displayOutput(cmd.OutOrStdout(), result, output)
 }
 
-func parseAddress(addr string, config *sdk.Config) (*KeyOutput, error) {
-    // Try bech32 first
-    if hrp, bz, err := bech32.DecodeAndConvert(addr); err == nil {
-        return &KeyOutput{
+func parseAddress(addr string, config *sdk.Config, addrFormat string) (*KeyOutput, error) {
+    // Try bech32 and bech32m first
+    hrp, bz, err := bech32.DecodeAndConvert(addr)
+    if err != nil {
+        hrp, bz, err = bech32.DecodeAndConvertM(addr)
+    }
+    if err == nil {
+        result := &KeyOutput{
             HumanReadable: hrp,
             HexBytes:      fmt.Sprintf("%X", bz),
-        }, nil
+        }
+
+        switch addrFormat {
+        case AddressFormatHex:
+            result.ChecksumHex = ChecksumHex(bz)
+        case AddressFormatBech32m:
+            bech32mAddr, err := bech32.ConvertAndEncodeM(hrp, bz)
+            if err != nil {
+                return nil, err
+            }
+            result.Bech32mFormats = []string{bech32mAddr}
+        }
+        return result, nil
     }
 
     // Try hex
-    bz, err := hex.DecodeString(addr)
+    bz, err = decodeChecksumHex(addr)
     if err != nil {
-        return nil, fmt.Errorf("invalid address format: not bech32 or hex")
+        return nil, fmt.Errorf("invalid address format: not bech32, bech32m or hex: %w", err)
+    }
+
+    result := &KeyOutput{}
+    encode := bech32.ConvertAndEncode
+    if addrFormat == AddressFormatBech32m {
+        encode = bech32.ConvertAndEncodeM
     }
 
     formats := make([]string, 0)
     for _, prefix := range getBech32Prefixes(config) {
-        bech32Addr, err := bech32.ConvertAndEncode(prefix, bz)
+        bech32Addr, err := encode(prefix, bz)
         if err != nil {
             continue
         }
         formats = append(formats, bech32Addr)
     }
 
-    return &KeyOutput{
-        Bech32Formats: formats,
-    }, nil
+    switch addrFormat {
+    case AddressFormatBech32m:
+        result.Bech32mFormats = formats
+    case AddressFormatHex:
+        result.ChecksumHex = ChecksumHex(bz)
+        result.Bech32Formats = formats
+    default:
+        result.Bech32Formats = formats
+    }
+    return result, nil
 }
 
 func getBech32Prefixes(config *sdk.Config) []string {
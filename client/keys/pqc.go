@@ -0,0 +1,133 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/pqcparams"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+
+	"github.com/baron-chain/cosmos-sdk/crypto/keys/dilithium"
+	"github.com/baron-chain/cosmos-sdk/crypto/keys/kyber"
+)
+
+// PQCSigningAlgos are the quantum-safe signature algorithms whose keys can be
+// derived from a BIP39 mnemonic, and thus recovered with `keys add --recover`.
+var PQCSigningAlgos = keyring.SigningAlgoList{
+	hd.NewPQCAlgo(hd.DilithiumType, func(bz []byte) cryptotypes.PrivKey {
+		_, priv, err := hd.DilithiumKeyFromSeed(bz)
+		if err != nil {
+			panic(fmt.Errorf("failed to expand dilithium key seed: %w", err))
+		}
+		return &dilithium.PrivKey{Key: priv}
+	}),
+}
+
+// PQCEncryptionAlgos are the quantum-safe key encapsulation mechanisms whose
+// keys can be derived from a BIP39 mnemonic. Their keys can't sign, they are
+// only the recipients of encrypted messages, see crypto.EncryptToPubKey.
+var PQCEncryptionAlgos = keyring.SigningAlgoList{
+	hd.NewPQCAlgo(hd.KyberType, func(bz []byte) cryptotypes.PrivKey {
+		_, priv, err := hd.KyberKeyFromSeed(bz)
+		if err != nil {
+			panic(fmt.Errorf("failed to expand kyber key seed: %w", err))
+		}
+		return &kyber.PrivKey{Key: priv}
+	}),
+}
+
+// PQCAlgoParamSets are the parameter sets of the keys of the quantum-safe
+// algorithms.
+var PQCAlgoParamSets = map[hd.PubKeyType]pqcparams.ParamSet{
+	hd.DilithiumType: pqcparams.Dilithium3,
+	hd.KyberType:     pqcparams.Kyber768,
+}
+
+// PQCKeyringOption returns a keyring option enabling the derivation of
+// quantum-safe signing and encryption keys from mnemonics. Apps enable it for
+// the keyrings of the keys commands with client.Context.WithKeyringOptions.
+func PQCKeyringOption() keyring.Option {
+	return func(options *keyring.Options) {
+		for _, algo := range pqcAlgos() {
+			if !options.SupportedAlgos.Contains(algo) {
+				options.SupportedAlgos = append(options.SupportedAlgos, algo)
+			}
+		}
+	}
+}
+
+// pqcAlgos returns the quantum-safe signing and encryption algorithms.
+func pqcAlgos() keyring.SigningAlgoList {
+	algos := make(keyring.SigningAlgoList, 0, len(PQCSigningAlgos)+len(PQCEncryptionAlgos))
+	return append(append(algos, PQCSigningAlgos...), PQCEncryptionAlgos...)
+}
+
+// resolveSigningAlgo returns the signing algorithm named algoStr, reporting
+// quantum-safe algorithms the keyring was not configured with.
+func resolveSigningAlgo(kb keyring.Keyring, algoStr string) (keyring.SignatureAlgo, error) {
+	keyringAlgos, _ := kb.SupportedAlgorithms()
+	algo, err := keyring.NewSigningAlgoFromString(algoStr, keyringAlgos)
+	if err == nil {
+		return algo, nil
+	}
+
+	if _, pqcErr := keyring.NewSigningAlgoFromString(algoStr, pqcAlgos()); pqcErr == nil {
+		return nil, fmt.Errorf("algorithm %q is not enabled in this keyring, set keys.PQCKeyringOption() in the keyring options of the client context", algoStr)
+	}
+
+	return nil, err
+}
+
+// checkSecurityLevel checks that the keys of the quantum-safe algo meet the
+// security level, as parsed by pqcparams.ParseSecurityLevel.
+func checkSecurityLevel(algo keyring.SignatureAlgo, levelStr string) error {
+	level, err := pqcparams.ParseSecurityLevel(levelStr)
+	if err != nil {
+		return err
+	}
+
+	paramSet, ok := PQCAlgoParamSets[algo.Name()]
+	if !ok {
+		return fmt.Errorf("--%s requires a quantum-safe algorithm, %s keys are not quantum-safe", flagSecLevel, algo.Name())
+	}
+	params, err := pqcparams.Lookup(paramSet)
+	if err != nil {
+		return err
+	}
+	if params.SecurityLevel >= level {
+		return nil
+	}
+
+	recommended, err := pqcparams.Recommend(params.Family, level)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%s keys use %s, which is NIST level %d and doesn't meet the %s security level: it requires %s, which is not supported",
+		algo.Name(), params.Name, params.SecurityLevel, level, recommended.Name)
+}
+
+// recommendSigningAlgo returns the quantum-safe signing algorithm of the
+// keyring recommended for the security level, as parsed by
+// pqcparams.ParseSecurityLevel: the one with the smallest keys which meets it.
+func recommendSigningAlgo(kb keyring.Keyring, levelStr string) (keyring.SignatureAlgo, error) {
+	level, err := pqcparams.ParseSecurityLevel(levelStr)
+	if err != nil {
+		return nil, err
+	}
+	recommended, err := pqcparams.Recommend(pqcparams.FamilyDilithium, level)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, algo := range PQCSigningAlgos {
+		params, err := pqcparams.Lookup(PQCAlgoParamSets[algo.Name()])
+		if err != nil {
+			return nil, err
+		}
+		if params.Family == recommended.Family && params.SecurityLevel >= level {
+			return resolveSigningAlgo(kb, string(algo.Name()))
+		}
+	}
+	return nil, fmt.Errorf("the %s security level requires %s keys, which are not supported", level, recommended.Name)
+}
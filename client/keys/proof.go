@@ -0,0 +1,273 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-sdk/client"
+	"github.com/baron-chain/cosmos-sdk/codec/legacy"
+	"github.com/baron-chain/cosmos-sdk/crypto"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/baron-chain/cosmos-sdk/crypto/types"
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+)
+
+const (
+	flagNonce        = "nonce"
+	flagProofAddress = "address"
+
+	// keyProofDomain prefixes the signed nonce, so that a proof signature can't
+	// be replayed as the signature of a transaction or of another message.
+	keyProofDomain = "baron-chain key proof of possession:"
+
+	blockTypeKeyProof   = "BARON CHAIN KEY PROOF"
+	keyProofVersion     = "1"
+	headerProofVersion  = "version"
+	headerProofAddress  = "address"
+	headerProofType     = "type"
+	headerProofNonce    = "nonce"
+	headerProofPubKey   = "pub_key"
+	minKeyProofNonceLen = 16
+	maxKeyProofNonceLen = 1024
+)
+
+// KeyProof is a proof of possession of a key: the signature of a nonce given by
+// the verifier. It is exchanged as an ASCII-armored block.
+type KeyProof struct {
+	Address   string
+	PubKey    cryptotypes.PubKey
+	Nonce     []byte
+	Signature []byte
+}
+
+// KeyProofResult is the output of a verified proof.
+type KeyProofResult struct {
+	Address   string `json:"address"`
+	Algorithm string `json:"algorithm"`
+	Nonce     string `json:"nonce"`
+}
+
+// ProveKeyCommand returns the command proving the possession of a key.
+func ProveKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prove <name>",
+		Short: "Prove the possession of a key by signing a nonce",
+		Long: `Sign the nonce given by a remote service with a key of the keyring, and output an
+ASCII-armored proof holding the public key, the address, the nonce and the signature.
+
+The service verifies the proof with "keys verify-proof" and the nonce it gave, which
+shows the operator controls the key, without any transaction being built or signed.
+The nonce is signed with a domain prefix, so the proof can't be used as the signature
+of anything else. The nonce must be hex encoded, and between 16 and 1024 bytes long.`,
+		Example: `  barond keys prove validator --nonce 8f3b2c...`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			nonce, err := keyProofNonceFlag(cmd)
+			if err != nil {
+				return err
+			}
+
+			armored, err := ProveKeyPossession(clientCtx.Keyring, args[0], nonce)
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(armored)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagNonce, "", "Hex encoded nonce given by the verifier")
+	_ = cmd.MarkFlagRequired(flagNonce)
+
+	return cmd
+}
+
+// VerifyKeyProofCommand returns the command verifying a proof of possession
+// of a key.
+func VerifyKeyProofCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-proof [proof-file]",
+		Short: "Verify a proof of possession of a key",
+		Long: `Verify a proof made with "keys prove" against the nonce given to the prover, and
+output the address and algorithm of the proven key. The proof is read from the given
+file, or from the standard input when it is omitted or "-". No keyring is needed.
+
+With --address, the proof must also be for the given address.`,
+		Example: `  barond keys verify-proof proof.txt --nonce 8f3b2c... --address baron1...`,
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			nonce, err := keyProofNonceFlag(cmd)
+			if err != nil {
+				return err
+			}
+
+			var bz []byte
+			if len(args) == 0 || args[0] == "-" {
+				bz, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				bz, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read proof: %w", err)
+			}
+
+			proof, err := VerifyKeyProof(string(bz), nonce)
+			if err != nil {
+				return err
+			}
+			if addr, _ := cmd.Flags().GetString(flagProofAddress); addr != "" && addr != proof.Address {
+				return fmt.Errorf("proof is for address %s, expected %s", proof.Address, addr)
+			}
+
+			format := clientCtx.OutputFormat
+			if format == "" {
+				format = OutputFormatText
+			}
+			return outputKeyData(cmd.OutOrStdout(), KeyProofResult{
+				Address:   proof.Address,
+				Algorithm: proof.PubKey.Type(),
+				Nonce:     hex.EncodeToString(proof.Nonce),
+			}, format)
+		},
+	}
+
+	cmd.Flags().String(flagNonce, "", "Hex encoded nonce given to the prover")
+	cmd.Flags().String(flagProofAddress, "", "Expected address of the proven key")
+	_ = cmd.MarkFlagRequired(flagNonce)
+
+	return cmd
+}
+
+func keyProofNonceFlag(cmd *cobra.Command) ([]byte, error) {
+	s, _ := cmd.Flags().GetString(flagNonce)
+	nonce, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	if len(nonce) < minKeyProofNonceLen || len(nonce) > maxKeyProofNonceLen {
+		return nil, fmt.Errorf("invalid nonce length %d, must be between %d and %d bytes", len(nonce), minKeyProofNonceLen, maxKeyProofNonceLen)
+	}
+	return nonce, nil
+}
+
+// KeyProofSignBytes returns the bytes a proof signature is over: the nonce
+// prefixed with the key proof domain.
+func KeyProofSignBytes(nonce []byte) []byte {
+	return append([]byte(keyProofDomain), nonce...)
+}
+
+// ProveKeyPossession signs nonce with the key uid of kr and returns the
+// armored proof.
+func ProveKeyPossession(kr keyring.Keyring, uid string, nonce []byte) (string, error) {
+	k, err := kr.Key(uid)
+	if err != nil {
+		return "", err
+	}
+	pub, err := k.GetPubKey()
+	if err != nil {
+		return "", err
+	}
+
+	sig, _, err := kr.Sign(uid, KeyProofSignBytes(nonce))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign nonce: %w", err)
+	}
+
+	return ArmorKeyProof(KeyProof{
+		Address:   sdk.AccAddress(pub.Address()).String(),
+		PubKey:    pub,
+		Nonce:     nonce,
+		Signature: sig,
+	})
+}
+
+// ArmorKeyProof encodes proof as an ASCII-armored block.
+func ArmorKeyProof(proof KeyProof) (string, error) {
+	pubBytes, err := legacy.Cdc.Marshal(proof.PubKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	headers := map[string]string{
+		headerProofVersion: keyProofVersion,
+		headerProofAddress: proof.Address,
+		headerProofType:    proof.PubKey.Type(),
+		headerProofNonce:   hex.EncodeToString(proof.Nonce),
+		headerProofPubKey:  base64.StdEncoding.EncodeToString(pubBytes),
+	}
+	return crypto.EncodeArmor(blockTypeKeyProof, headers, proof.Signature), nil
+}
+
+// UnarmorKeyProof decodes an ASCII-armored proof, without verifying it.
+func UnarmorKeyProof(armored string) (KeyProof, error) {
+	blockType, headers, sig, err := crypto.DecodeArmor(armored)
+	if err != nil {
+		return KeyProof{}, fmt.Errorf("invalid proof: %w", err)
+	}
+	if blockType != blockTypeKeyProof {
+		return KeyProof{}, fmt.Errorf("unrecognized armor type %q, expected: %q", blockType, blockTypeKeyProof)
+	}
+	if headers[headerProofVersion] != keyProofVersion {
+		return KeyProof{}, fmt.Errorf("unrecognized proof version: %v", headers[headerProofVersion])
+	}
+
+	pubBytes, err := base64.StdEncoding.DecodeString(headers[headerProofPubKey])
+	if err != nil {
+		return KeyProof{}, fmt.Errorf("invalid proof public key: %w", err)
+	}
+	pub, err := legacy.PubKeyFromBytes(pubBytes)
+	if err != nil {
+		return KeyProof{}, fmt.Errorf("invalid proof public key: %w", err)
+	}
+	nonce, err := hex.DecodeString(headers[headerProofNonce])
+	if err != nil {
+		return KeyProof{}, fmt.Errorf("invalid proof nonce: %w", err)
+	}
+
+	return KeyProof{
+		Address:   headers[headerProofAddress],
+		PubKey:    pub,
+		Nonce:     nonce,
+		Signature: sig,
+	}, nil
+}
+
+// VerifyKeyProof decodes an ASCII-armored proof and verifies it is a valid
+// signature of nonce, by the key of the proof address.
+func VerifyKeyProof(armored string, nonce []byte) (KeyProof, error) {
+	proof, err := UnarmorKeyProof(armored)
+	if err != nil {
+		return KeyProof{}, err
+	}
+
+	if !bytes.Equal(proof.Nonce, nonce) {
+		return KeyProof{}, errors.New("proof is for another nonce")
+	}
+	if addr := sdk.AccAddress(proof.PubKey.Address()).String(); addr != proof.Address {
+		return KeyProof{}, fmt.Errorf("proof address %s doesn't match its public key address %s", proof.Address, addr)
+	}
+	if !proof.PubKey.VerifySignature(KeyProofSignBytes(nonce), proof.Signature) {
+		return KeyProof{}, errors.New("invalid proof signature")
+	}
+
+	return proof, nil
+}
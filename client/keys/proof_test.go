@@ -0,0 +1,81 @@
+package keys
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clienttestutil "github.com/baron-chain/cosmos-sdk/client/testutil"
+	"github.com/baron-chain/cosmos-sdk/crypto/hd"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+)
+
+func TestKeyProof(t *testing.T) {
+	kr := keyring.NewInMemory(clienttestutil.MakeTestCodec(t))
+	k, _, err := kr.NewMnemonic("validator", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+	addr, err := k.GetAddress()
+	require.NoError(t, err)
+
+	nonce := bytes.Repeat([]byte{0xab}, minKeyProofNonceLen)
+	armored, err := ProveKeyPossession(kr, "validator", nonce)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(armored, "-----BEGIN "+blockTypeKeyProof+"-----"))
+
+	proof, err := VerifyKeyProof(armored, nonce)
+	require.NoError(t, err)
+	require.Equal(t, addr.String(), proof.Address)
+	require.Equal(t, "secp256k1", proof.PubKey.Type())
+
+	// the signature is over the domain prefixed nonce only
+	pub, err := k.GetPubKey()
+	require.NoError(t, err)
+	require.True(t, pub.VerifySignature(KeyProofSignBytes(nonce), proof.Signature))
+	require.False(t, pub.VerifySignature(nonce, proof.Signature))
+
+	_, err = VerifyKeyProof(armored, bytes.Repeat([]byte{0xcd}, minKeyProofNonceLen))
+	require.ErrorContains(t, err, "another nonce")
+
+	// a proof claiming another address is rejected
+	other, _, err := kr.NewMnemonic("other", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+	otherAddr, err := other.GetAddress()
+	require.NoError(t, err)
+	proof.Address = otherAddr.String()
+	forged, err := ArmorKeyProof(proof)
+	require.NoError(t, err)
+	_, err = VerifyKeyProof(forged, nonce)
+	require.ErrorContains(t, err, "doesn't match its public key address")
+
+	// a signature of another key is rejected
+	otherPub, err := other.GetPubKey()
+	require.NoError(t, err)
+	proof.Address = otherAddr.String()
+	proof.PubKey = otherPub
+	forged, err = ArmorKeyProof(proof)
+	require.NoError(t, err)
+	_, err = VerifyKeyProof(forged, nonce)
+	require.ErrorContains(t, err, "invalid proof signature")
+
+	_, err = ProveKeyPossession(kr, "unknown", nonce)
+	require.Error(t, err)
+}
+
+func TestKeyProofCommands(t *testing.T) {
+	cmd := ProveKeyCommand()
+	require.NoError(t, cmd.Flags().Set(flagNonce, "0x"+strings.Repeat("ab", minKeyProofNonceLen)))
+	nonce, err := keyProofNonceFlag(cmd)
+	require.NoError(t, err)
+	require.Len(t, nonce, minKeyProofNonceLen)
+
+	require.NoError(t, cmd.Flags().Set(flagNonce, "abcd"))
+	_, err = keyProofNonceFlag(cmd)
+	require.ErrorContains(t, err, "invalid nonce length")
+
+	require.NoError(t, cmd.Flags().Set(flagNonce, "zz"))
+	_, err = keyProofNonceFlag(cmd)
+	require.ErrorContains(t, err, "invalid nonce")
+}
@@ -35,7 +35,7 @@ Example:
 
     cmd.Flags().BoolP(flagSkipConfirm, "y", false, "Skip rename confirmation")
     cmd.Flags().Bool(flagForce, false, "Force rename even if new name exists")
-    return cmd
+    return enforceKeyNamespaces(cmd)
 }
 
 func runRenameKey(cmd *cobra.Command, args []string) error {
@@ -49,14 +49,14 @@ func runRenameKey(cmd *cobra.Command, args []string) error {
     // Validate old key exists
     key, err := clientCtx.Keyring.Key(oldName)
     if err != nil {
-        return fmt.Errorf("key '%s' not found: %w", oldName, err)
+        return newKeyError(ErrNotFound, "key '%s' not found: %w", oldName, err)
     }
 
     // Check if new name already exists
     if _, err := clientCtx.Keyring.Key(newName); err == nil {
         force, _ := cmd.Flags().GetBool(flagForce)
         if !force {
-            return fmt.Errorf("key '%s' already exists, use --force to overwrite", newName)
+            return newKeyError(ErrExists, "key '%s' already exists, use --force to overwrite", newName)
         }
     }
 
@@ -80,11 +80,11 @@ func confirmRename(cmd *cobra.Command, oldName, newName string, keyType keyring.
     var prompt string
     switch keyType {
     case keyring.TypeLedger:
-        prompt = fmt.Sprintf("Rename ledger key reference from '%s' to '%s'?", oldName, newName)
+        prompt = localize(cmd, msgRenameLedgerConfirm, oldName, newName)
     case keyring.TypeOffline:
-        prompt = fmt.Sprintf("Rename offline key reference from '%s' to '%s'?", oldName, newName)
+        prompt = localize(cmd, msgRenameOfflineConfirm, oldName, newName)
     default:
-        prompt = fmt.Sprintf("Rename key from '%s' to '%s'?", oldName, newName)
+        prompt = localize(cmd, msgRenameConfirm, oldName, newName)
     }
 
     buf := bufio.NewReader(cmd.InOrStdin())
@@ -93,7 +93,7 @@ func confirmRename(cmd *cobra.Command, oldName, newName string, keyType keyring.
         return fmt.Errorf("failed to read confirmation: %w", err)
     }
     if !confirmed {
-        cmd.PrintErrln("Rename cancelled")
+        cmd.PrintErrln(localize(cmd, msgRenameCancelled))
         return fmt.Errorf("operation cancelled")
     }
 
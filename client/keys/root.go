@@ -1,9 +1,14 @@
 package keys
 
 import (
+    "fmt"
+    "strings"
+
     "github.com/spf13/cobra"
     "github.com/baron-chain/cometbft-bc/libs/cli"
+    "github.com/baron-chain/cosmos-sdk/client"
     "github.com/baron-chain/cosmos-sdk/client/flags"
+    "github.com/baron-chain/cosmos-sdk/crypto/keyring"
 )
 
 const (
@@ -28,12 +33,45 @@ Supported Keyring Backends:
     kwallet     KDE Wallet Manager (requires external setup)
     pass        Unix pass command line utility (requires GnuPG)
     test        Insecure disk storage (testing only)
+    command     Any secret store CLI, e.g. 1Password (configured in keyring-command.json)
+    pkcs11      PKCS#11 token such as YubiHSM or SoftHSM (configured in keyring-pkcs11.json)
 
 For external backend setup:
     KWallet: https://github.com/KDE/kwallet
     Pass:    https://www.passwordstore.org/
     GnuPG:   https://gnupg.org/
 
+The command backend reads keyring-command.json from the keyring directory. Each command
+is an argument list of templates over {{.Service}} and {{.Key}}; secrets are base64 text
+passed on stdin to "set" and read from stdout of "get":
+    {
+      "get":    ["op", "read", "op://baron/{{.Key}}/password"],
+      "set":    ["sh", "-c", "op item create --vault baron --title \"$1\" password=\"$(cat)\"", "-", "{{.Key}}"],
+      "remove": ["op", "item", "delete", "--vault", "baron", "{{.Key}}"],
+      "list":   ["sh", "-c", "op item list --vault baron --format json | jq -r '.[].title'"]
+    }
+
+The pkcs11 backend reads keyring-pkcs11.json from the keyring directory. Keys are the
+secp256k1 key pairs of the token, named after their label; they are generated with the
+token tools and cannot be created, imported or exported with these commands. The PIN is
+read from the pin_env environment variable, or prompted for:
+    {
+      "module":      "/usr/lib/softhsm/libsofthsm2.so",
+      "token_label": "baron",
+      "pin_env":     "BARON_PKCS11_PIN"
+    }
+Use "keys list --keyring-backend pkcs11" to show the slots of the module.
+
+Key names may be organized in namespaces, e.g. ops/validator1 and dev/faucet, listed with
+"keys list --namespace ops". The keyring-namespaces.json file of the keyring directory
+restricts the backends and algorithms of the keys created, imported or renamed into each
+namespace, and its sub-namespaces without a policy of their own:
+    {
+      "namespaces": {
+        "ops": {"backends": ["os", "pkcs11"], "algorithms": ["dilithium"]}
+      }
+    }
+
 Note: File backend will prompt for password on each access.`,
     }
 
@@ -47,22 +85,49 @@ Note: File backend will prompt for password on each access.`,
         ImportKeyCommand(),
         ImportKeyHexCommand(),
         ExportKeyCommand(),
+        SplitKeyCommand(),
+        ShareKeyCommand(),
+        ImportKeyShareCommand(),
+        ExportKeyShareCommand(),
+        CombineCommand(),
+        SendKeyCommand(),
+        ReceiveKeyCommand(),
         
         // Key Management
         ListKeysCmd(),
         ShowKeysCmd(),
         RenameKeyCommand(),
         DeleteKeyCommand(),
+        ShredKeysCommand(),
         
         // Utility Commands
         ListKeyTypesCmd(),
         ParseKeyStringCommand(),
         MigrateCommand(),
+        EntropyCheckCommand(),
+
+        // Local Services
+        ServeCommand(),
+        InventoryCommand(),
+        ProveKeyCommand(),
+        VerifyKeyProofCommand(),
+        ApproveKeyOperationCommand(),
+        AutoBackupCommand(),
+        RestoreBackupCommand(),
     )
 
+    // Release the keyring of the commands, e.g. the session of the pkcs11
+    // backend on its token
+    cmd.PersistentPostRunE = func(cmd *cobra.Command, _ []string) error {
+        return keyring.Close(client.GetClientContextFromCmd(cmd).Keyring)
+    }
+
     // Add persistent flags
     addPersistentFlags(cmd, defaultNodeHome)
 
+    // Classify the errors of all commands, for their exit codes
+    handleKeyErrors(cmd)
+
     return cmd
 }
 
@@ -81,6 +146,12 @@ func addPersistentFlags(cmd *cobra.Command, defaultNodeHome string) {
         "Output format (text|json)",
     )
 
+    persistentFlags.String(
+        flagLocale,
+        "",
+        fmt.Sprintf("Locale of prompts and messages (%s), defaults to $%s then the system locale", strings.Join(supportedLocales(), "|"), EnvLocale),
+    )
+
     // Add keyring-specific flags
     flags.AddKeyringFlags(persistentFlags)
 
@@ -110,18 +181,39 @@ func addQuantumSafeFlags(flags *pflag.FlagSet) {
 
 // GetCommands returns all available key commands
 func GetCommands() []*cobra.Command {
-    return []*cobra.Command{
+    cmds := []*cobra.Command{
         MnemonicKeyCommand(),
         AddKeyCommand(),
         ImportKeyCommand(),
         ImportKeyHexCommand(),
         ExportKeyCommand(),
+        SplitKeyCommand(),
+        ShareKeyCommand(),
+        ImportKeyShareCommand(),
+        ExportKeyShareCommand(),
+        CombineCommand(),
+        SendKeyCommand(),
+        ReceiveKeyCommand(),
         ListKeysCmd(),
         ShowKeysCmd(),
         RenameKeyCommand(),
         DeleteKeyCommand(),
+        ShredKeysCommand(),
         ListKeyTypesCmd(),
         ParseKeyStringCommand(),
         MigrateCommand(),
+        EntropyCheckCommand(),
+        ServeCommand(),
+        InventoryCommand(),
+        ProveKeyCommand(),
+        VerifyKeyProofCommand(),
+        ApproveKeyOperationCommand(),
+        AutoBackupCommand(),
+        RestoreBackupCommand(),
+    }
+
+    for _, cmd := range cmds {
+        handleKeyErrors(cmd)
     }
+    return cmds
 }
@@ -10,7 +10,7 @@ func TestCommands(t *testing.T) {
     t.Run("root commands initialization", func(t *testing.T) {
         cmds := Commands("home")
         require.NotNil(t, cmds)
-        require.Len(t, cmds.Commands(), 13) // Added PQC key command
+        require.Len(t, cmds.Commands(), 28) // Added PQC key, entropy-check, serve, inventory, key proof, key share, approval and backup commands
     })
 
     t.Run("pqc key generation", func(t *testing.T) {
@@ -0,0 +1,281 @@
+package keys
+
+import (
+	"bufio"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/input"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+)
+
+const (
+	flagTo              = "to"
+	flagTransferTimeout = "timeout"
+
+	defaultReceiveListen   = "0.0.0.0:0"
+	defaultTransferTimeout = 5 * time.Minute
+	// transferCodeDigits is the number of digits of the transfer codes. The
+	// handshake allows a single guess per transfer, so short codes are safe.
+	transferCodeDigits = 8
+)
+
+// SendKeyCommand returns the command sending a key to keys receive on another
+// machine.
+func SendKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send <name>",
+		Short: "Send a private key to another machine running keys receive",
+		Long: `Send a private key, exported in ASCII-armored encrypted format as by keys export, to
+another machine of the local network running "keys receive", without writing it to
+an intermediary file.
+
+The receiver shows its address, given with --to, and a short transfer code to type
+in when prompted. The code authenticates both machines with a SPAKE2 exchange,
+which an attacker on the network can only guess once before the receiver stops,
+and the transfer is encrypted with the combined secrets of the exchange and of a
+Kyber-768 key encapsulation, so that recorded transfers stay confidential from
+quantum adversaries. The receiver asks for the passphrase of the exported key to
+import it.
+
+When dual control is enabled, sending a key is an export, which requires an
+approval given with --approval-file.`,
+		Example: `  barond keys send validator --to 192.168.1.20:40123`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			buf := bufio.NewReader(clientCtx.Input)
+
+			to, _ := cmd.Flags().GetString(flagTo)
+			if to == "" {
+				return fmt.Errorf("the address of the receiver must be given with --%s", flagTo)
+			}
+			timeout, _ := cmd.Flags().GetDuration(flagTransferTimeout)
+
+			approval, err := requireKeyApproval(cmd, clientCtx, ApprovalOpExport, args[:1])
+			if err != nil {
+				return err
+			}
+			return approval.done(sendKey(cmd, args[0], to, timeout, buf, clientCtx.Keyring))
+		},
+	}
+
+	cmd.Flags().String(flagTo, "", "Address of the receiver, as shown by keys receive")
+	cmd.Flags().Duration(flagTransferTimeout, defaultTransferTimeout, "Time to complete the transfer")
+	addApprovalFileFlag(cmd)
+
+	return cmd
+}
+
+func sendKey(cmd *cobra.Command, uid, to string, timeout time.Duration, buf *bufio.Reader, kr keyring.Keyring) error {
+	code, err := input.GetString("Enter the transfer code shown by keys receive:", buf)
+	if err != nil {
+		return err
+	}
+	armored, err := armorForExport(cmd, uid, buf, kr)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", to, timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the receiver: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	if err := sendArmoredKey(conn, code, armored); err != nil {
+		return err
+	}
+	cmd.PrintErrf("Key %s sent to %s\n", uid, to)
+	return nil
+}
+
+// sendArmoredKey sends the armored key over conn, and waits for the receiver
+// to import it.
+func sendArmoredKey(conn io.ReadWriter, code, armored string) error {
+	keys, err := senderHandshake(conn, normalizeTransferCode(code))
+	if err != nil {
+		return err
+	}
+	if err := keys.writeSealed(conn, []byte(armored)); err != nil {
+		return err
+	}
+
+	// the receiver replies with the error of the import, if any
+	reply, err := keys.readSealed(conn)
+	if err != nil {
+		return fmt.Errorf("no reply from the receiver: %w", err)
+	}
+	if len(reply) > 0 {
+		return fmt.Errorf("the receiver failed to import the key: %s", reply)
+	}
+	return nil
+}
+
+// ReceiveKeyCommand returns the command receiving a key from keys send on
+// another machine.
+func ReceiveKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "receive <name>",
+		Short: "Receive a private key sent by keys send from another machine",
+		Long: `Wait for a private key sent by "keys send" from another machine of the local network,
+and import it under the given name.
+
+The command shows the addresses it listens on and a transfer code, to give to the
+sender. It accepts a single connection: a wrong code aborts the transfer, so that
+an attacker on the network can't guess it. Once received, the key is decrypted
+with the passphrase it was exported with.`,
+		Example: `  barond keys receive validator
+  barond keys receive validator --listen 192.168.1.20:40123`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			if _, err := clientCtx.Keyring.Key(args[0]); err == nil {
+				return newKeyError(ErrExists, "key '%s' already exists", args[0])
+			}
+
+			listen, _ := cmd.Flags().GetString(flagListen)
+			timeout, _ := cmd.Flags().GetDuration(flagTransferTimeout)
+			code, err := newTransferCode()
+			if err != nil {
+				return err
+			}
+			return receiveKey(cmd, args[0], listen, code, timeout, bufio.NewReader(clientCtx.Input), clientCtx.Keyring)
+		},
+	}
+
+	cmd.Flags().String(flagListen, defaultReceiveListen, "Address to listen on for the sender")
+	cmd.Flags().Duration(flagTransferTimeout, defaultTransferTimeout, "Time to wait for the sender and complete the transfer")
+
+	return enforceKeyNamespaces(cmd)
+}
+
+func receiveKey(cmd *cobra.Command, name, listen, code string, timeout time.Duration, buf *bufio.Reader, kr keyring.Keyring) error {
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	defer ln.Close()
+
+	addrs := transferAddresses(ln.Addr().(*net.TCPAddr))
+	cmd.PrintErrf("Waiting for the key on %s\n", strings.Join(addrs, ", "))
+	cmd.PrintErrf("Transfer code: %s\n", code)
+	cmd.PrintErrf("Run on the sending machine:\n  keys send <name> --to %s\n", addrs[0])
+
+	if err := ln.(*net.TCPListener).SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		return fmt.Errorf("no sender connected: %w", err)
+	}
+	// a single sender may try the code
+	ln.Close()
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	err = receiveArmoredKey(conn, code, func(armored string) error {
+		passphrase, err := input.GetPassword("Enter passphrase to decrypt the received key:", buf)
+		if err != nil {
+			return err
+		}
+		return scrubSecrets(kr.ImportPrivKey(name, armored, passphrase), armored, passphrase)
+	})
+	if err != nil {
+		return err
+	}
+	cmd.PrintErrf("Key %s received from %s\n", name, conn.RemoteAddr())
+	return nil
+}
+
+// receiveArmoredKey receives an armored key over conn, imports it with
+// importArmored and replies with the error of the import.
+func receiveArmoredKey(conn io.ReadWriter, code string, importArmored func(armored string) error) error {
+	keys, err := receiverHandshake(conn, normalizeTransferCode(code))
+	if err != nil {
+		return err
+	}
+	armored, err := keys.readSealed(conn)
+	if err != nil {
+		return err
+	}
+
+	importErr := importArmored(string(armored))
+	var reply []byte
+	if importErr != nil {
+		reply = []byte(importErr.Error())
+	}
+	if err := keys.writeSealed(conn, reply); err != nil {
+		if importErr != nil {
+			return fmt.Errorf("%w; %v", importErr, err)
+		}
+		return fmt.Errorf("failed to reply to the sender: %w", err)
+	}
+	return importErr
+}
+
+// newTransferCode returns a random transfer code, e.g. 4821-0937.
+func newTransferCode() (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(transferCodeDigits), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	digits := fmt.Sprintf("%0*d", transferCodeDigits, n)
+	return digits[:transferCodeDigits/2] + "-" + digits[transferCodeDigits/2:], nil
+}
+
+// normalizeTransferCode removes the separators of a transfer code.
+func normalizeTransferCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(code))
+}
+
+// transferAddresses returns the addresses of addr to connect to, those of the
+// network interfaces when it listens on all of them.
+func transferAddresses(addr *net.TCPAddr) []string {
+	if !addr.IP.IsUnspecified() {
+		return []string{addr.String()}
+	}
+
+	var addrs []string
+	ifaceAddrs, _ := net.InterfaceAddrs()
+	for _, a := range ifaceAddrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if addr.IP.To4() != nil && ipNet.IP.To4() == nil {
+			continue
+		}
+		addrs = append(addrs, net.JoinHostPort(ipNet.IP.String(), fmt.Sprint(addr.Port)))
+	}
+	if len(addrs) == 0 {
+		addrs = append(addrs, net.JoinHostPort("127.0.0.1", fmt.Sprint(addr.Port)))
+	}
+	return addrs
+}
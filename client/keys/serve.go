@@ -0,0 +1,436 @@
+package keys
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-sdk/client"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+)
+
+const (
+	flagListen = "listen"
+	flagPolicy = "policy"
+
+	// ServeOpList allows a caller to list the keys it has access to.
+	ServeOpList = "list"
+	// ServeOpShow allows a caller to show the public information of a key.
+	ServeOpShow = "show"
+	// ServeOpSign allows a caller to sign bytes with a key.
+	ServeOpSign = "sign"
+
+	defaultServeListen = "unix://keyring.sock"
+	maxServeBodySize   = 1 << 20
+)
+
+// ServePolicy lists the callers allowed to use the keys serve daemon.
+type ServePolicy struct {
+	Callers []ServeCaller `json:"callers"`
+}
+
+// ServeCaller is a caller of the keys serve daemon, authenticated by a bearer
+// token, and the operations it may perform on the keys matching its patterns.
+type ServeCaller struct {
+	// Name identifies the caller in the daemon logs.
+	Name string `json:"name"`
+	// TokenSHA256 is the hex encoded SHA-256 of the caller bearer token, so
+	// that the policy file doesn't hold the tokens themselves.
+	TokenSHA256 string `json:"token_sha256"`
+	// Operations are the operations the caller may perform, among list, show
+	// and sign.
+	Operations []string `json:"operations"`
+	// Keys are the patterns, as accepted by path.Match, of the key names the
+	// caller may access.
+	Keys []string `json:"keys"`
+}
+
+// LoadServePolicy reads and validates a policy file.
+func LoadServePolicy(file string) (ServePolicy, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return ServePolicy{}, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var policy ServePolicy
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&policy); err != nil {
+		return ServePolicy{}, fmt.Errorf("failed to parse policy file %s: %w", file, err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return ServePolicy{}, fmt.Errorf("invalid policy file %s: %w", file, err)
+	}
+	return policy, nil
+}
+
+// Validate checks callers have a unique name and token, known operations and
+// well-formed key patterns.
+func (p ServePolicy) Validate() error {
+	if len(p.Callers) == 0 {
+		return errors.New("no callers")
+	}
+
+	names := make(map[string]bool)
+	tokens := make(map[string]bool)
+	for _, c := range p.Callers {
+		if c.Name == "" {
+			return errors.New("caller without name")
+		}
+		if names[c.Name] {
+			return fmt.Errorf("duplicate caller %s", c.Name)
+		}
+		names[c.Name] = true
+
+		hash, err := hex.DecodeString(c.TokenSHA256)
+		if err != nil || len(hash) != sha256.Size {
+			return fmt.Errorf("caller %s: token_sha256 must be a hex encoded SHA-256 hash", c.Name)
+		}
+		if tokens[string(hash)] {
+			return fmt.Errorf("caller %s: token is shared with another caller", c.Name)
+		}
+		tokens[string(hash)] = true
+
+		for _, op := range c.Operations {
+			if op != ServeOpList && op != ServeOpShow && op != ServeOpSign {
+				return fmt.Errorf("caller %s: unknown operation %q", c.Name, op)
+			}
+		}
+		for _, pattern := range c.Keys {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("caller %s: invalid key pattern %q", c.Name, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// Authenticate returns the caller with the given bearer token, if any. Token
+// hashes are compared in constant time.
+func (p ServePolicy) Authenticate(token string) (ServeCaller, bool) {
+	if token == "" {
+		return ServeCaller{}, false
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	for _, c := range p.Callers {
+		expected, err := hex.DecodeString(c.TokenSHA256)
+		if err != nil {
+			continue
+		}
+		if subtle.ConstantTimeCompare(hash[:], expected) == 1 {
+			return c, true
+		}
+	}
+	return ServeCaller{}, false
+}
+
+// Allows reports whether the caller may perform op on the named key.
+func (c ServeCaller) Allows(op, name string) bool {
+	if !containsOp(c.Operations, op) {
+		return false
+	}
+	for _, pattern := range c.Keys {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOp(ops []string, op string) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeCommand returns the command running a local daemon exposing the
+// keyring to other local tooling.
+func ServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve keyring list, show and sign operations over a local socket",
+		Long: `Run a local daemon exposing the keyring to other local tooling over HTTP, so that
+it can list keys, show their public information and sign bytes without shelling out
+to the CLI. Private keys can never be exported through the daemon.
+
+The daemon listens on a unix socket, only accessible to the current user, or on a
+loopback TCP address, the only option on Windows. Callers authenticate with a bearer token and are restricted to
+the operations and key names of the policy file given with --policy:
+
+    {
+      "callers": [
+        {
+          "name":         "relayer",
+          "token_sha256": "<printf %s \"$TOKEN\" | sha256sum>",
+          "operations":   ["list", "show", "sign"],
+          "keys":         ["relayer-*"]
+        }
+      ]
+    }
+
+Endpoints:
+
+    GET  /keys              list the keys the caller has access to
+    GET  /keys/{name}       show a key
+    POST /keys/{name}/sign  sign {"sign_bytes": "<base64>"}
+
+Use a keyring backend which doesn't prompt for a passphrase, such as os or command.`,
+		Example: `  barond keys serve --listen unix:///run/user/1000/barond-keys.sock --policy ~/.barond/keys-policy.json
+  curl --unix-socket /run/user/1000/barond-keys.sock -H "Authorization: Bearer $TOKEN" http://localhost/keys`,
+		Args: cobra.NoArgs,
+		RunE: runServeCmd,
+	}
+
+	cmd.Flags().String(flagListen, defaultServeListen, "Address to listen on, unix://<path> or tcp://<loopback address>:<port>")
+	cmd.Flags().String(flagPolicy, "", "Path of the JSON caller policy file")
+	_ = cmd.MarkFlagRequired(flagPolicy)
+
+	return cmd
+}
+
+func runServeCmd(cmd *cobra.Command, _ []string) error {
+	clientCtx, err := client.GetClientQueryContext(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to get client context: %w", err)
+	}
+
+	policyFile, _ := cmd.Flags().GetString(flagPolicy)
+	policy, err := LoadServePolicy(policyFile)
+	if err != nil {
+		return err
+	}
+
+	addr, _ := cmd.Flags().GetString(flagListen)
+	ln, err := ListenLocal(addr)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Handler:           NewKeyServer(clientCtx.Keyring, policy, cmd.ErrOrStderr()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+	cmd.PrintErrf("Serving keyring on %s for %d callers\n", addr, len(policy.Callers))
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// ListenLocal listens on a unix socket, given as unix://<path> and created
+// with permissions restricted to the current user, or on a loopback TCP
+// address given as tcp://<host>:<port>. Non-loopback addresses are rejected,
+// and so are unix sockets on Windows.
+func ListenLocal(addr string) (net.Listener, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Host + u.Path
+		if socket == "" {
+			return nil, fmt.Errorf("invalid listen address %q: missing socket path", addr)
+		}
+		return listenUnix(socket)
+
+	case "tcp":
+		host := u.Hostname()
+		if host != "localhost" {
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsLoopback() {
+				return nil, fmt.Errorf("refusing to listen on non-loopback address %s", host)
+			}
+		}
+		return net.Listen("tcp", u.Host)
+
+	default:
+		return nil, fmt.Errorf("unsupported listen address %q, expected unix:// or tcp://", addr)
+	}
+}
+
+// SignRequest is the body of a sign request.
+type SignRequest struct {
+	SignBytes []byte `json:"sign_bytes"`
+}
+
+// SignResponse is the result of a sign request.
+type SignResponse struct {
+	Key       keyring.KeyOutput `json:"key"`
+	Signature []byte            `json:"signature"`
+}
+
+type keyServer struct {
+	kr     keyring.Keyring
+	policy ServePolicy
+	logger io.Writer
+
+	// keyring backends aren't safe for concurrent use
+	mtx sync.Mutex
+}
+
+// NewKeyServer returns the HTTP handler of the keys serve daemon, which
+// authenticates callers with the policy and logs requests to logger.
+func NewKeyServer(kr keyring.Keyring, policy ServePolicy, logger io.Writer) http.Handler {
+	return &keyServer{kr: kr, policy: policy, logger: logger}
+}
+
+func (s *keyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	caller, ok := s.policy.Authenticate(token)
+	if !ok {
+		writeServeError(w, http.StatusUnauthorized, "invalid or missing bearer token")
+		return
+	}
+
+	status := s.route(w, r, caller)
+	fmt.Fprintf(s.logger, "%s %s %s %s %d\n", time.Now().UTC().Format(time.RFC3339), caller.Name, r.Method, r.URL.Path, status)
+}
+
+// route dispatches the request and returns the response status.
+func (s *keyServer) route(w http.ResponseWriter, r *http.Request, caller ServeCaller) int {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "keys" {
+		return writeServeError(w, http.StatusNotFound, "not found")
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		return s.list(w, caller)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		return s.show(w, caller, parts[1])
+	case len(parts) == 3 && parts[2] == "sign" && r.Method == http.MethodPost:
+		return s.sign(w, r, caller, parts[1])
+	case len(parts) <= 3:
+		return writeServeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		return writeServeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *keyServer) list(w http.ResponseWriter, caller ServeCaller) int {
+	if !containsOp(caller.Operations, ServeOpList) {
+		return writeServeError(w, http.StatusForbidden, "list is not allowed")
+	}
+
+	s.mtx.Lock()
+	records, err := s.kr.List()
+	s.mtx.Unlock()
+	if err != nil {
+		return writeServeError(w, http.StatusInternalServerError, err.Error())
+	}
+
+	allowed := make([]*keyring.Record, 0, len(records))
+	for _, record := range records {
+		if caller.Allows(ServeOpList, record.Name) {
+			allowed = append(allowed, record)
+		}
+	}
+
+	out, err := keyring.MkAccKeysOutput(allowed)
+	if err != nil {
+		return writeServeError(w, http.StatusInternalServerError, err.Error())
+	}
+	return writeServeJSON(w, http.StatusOK, out)
+}
+
+func (s *keyServer) show(w http.ResponseWriter, caller ServeCaller, name string) int {
+	if !caller.Allows(ServeOpShow, name) {
+		return writeServeError(w, http.StatusForbidden, fmt.Sprintf("show %s is not allowed", name))
+	}
+
+	s.mtx.Lock()
+	record, err := s.kr.Key(name)
+	s.mtx.Unlock()
+	if err != nil {
+		return writeServeError(w, http.StatusNotFound, err.Error())
+	}
+
+	out, err := keyring.MkAccKeyOutput(record)
+	if err != nil {
+		return writeServeError(w, http.StatusInternalServerError, err.Error())
+	}
+	return writeServeJSON(w, http.StatusOK, out)
+}
+
+func (s *keyServer) sign(w http.ResponseWriter, r *http.Request, caller ServeCaller, name string) int {
+	if !caller.Allows(ServeOpSign, name) {
+		return writeServeError(w, http.StatusForbidden, fmt.Sprintf("sign with %s is not allowed", name))
+	}
+
+	var req SignRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxServeBodySize)).Decode(&req); err != nil {
+		return writeServeError(w, http.StatusBadRequest, fmt.Sprintf("invalid sign request: %s", err))
+	}
+	if len(req.SignBytes) == 0 {
+		return writeServeError(w, http.StatusBadRequest, "sign_bytes is empty")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	record, err := s.kr.Key(name)
+	if err != nil {
+		return writeServeError(w, http.StatusNotFound, err.Error())
+	}
+	sig, _, err := s.kr.Sign(name, req.SignBytes)
+	if err != nil {
+		return writeServeError(w, http.StatusInternalServerError, err.Error())
+	}
+
+	out, err := keyring.MkAccKeyOutput(record)
+	if err != nil {
+		return writeServeError(w, http.StatusInternalServerError, err.Error())
+	}
+	return writeServeJSON(w, http.StatusOK, SignResponse{Key: out, Signature: sig})
+}
+
+func writeServeJSON(w http.ResponseWriter, status int, v interface{}) int {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+	return status
+}
+
+func writeServeError(w http.ResponseWriter, status int, msg string) int {
+	return writeServeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,168 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clienttestutil "github.com/baron-chain/cosmos-sdk/client/testutil"
+	"github.com/baron-chain/cosmos-sdk/crypto/hd"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	sdk "github.com/baron-chain/cosmos-sdk/types"
+)
+
+func tokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestServePolicy(t *testing.T) {
+	policy := ServePolicy{Callers: []ServeCaller{
+		{Name: "relayer", TokenSHA256: tokenHash("relayer-token"), Operations: []string{ServeOpShow, ServeOpSign}, Keys: []string{"relayer-*"}},
+		{Name: "wallet", TokenSHA256: tokenHash("wallet-token"), Operations: []string{ServeOpList}, Keys: []string{"*"}},
+	}}
+	require.NoError(t, policy.Validate())
+
+	caller, ok := policy.Authenticate("relayer-token")
+	require.True(t, ok)
+	require.Equal(t, "relayer", caller.Name)
+	require.True(t, caller.Allows(ServeOpSign, "relayer-1"))
+	require.False(t, caller.Allows(ServeOpSign, "validator"))
+	require.False(t, caller.Allows(ServeOpList, "relayer-1"))
+
+	_, ok = policy.Authenticate("unknown")
+	require.False(t, ok)
+	_, ok = policy.Authenticate("")
+	require.False(t, ok)
+
+	invalid := []ServePolicy{
+		{},
+		{Callers: []ServeCaller{{Name: "a", TokenSHA256: "abcd"}}},
+		{Callers: []ServeCaller{{Name: "a", TokenSHA256: tokenHash("a"), Operations: []string{"export"}}}},
+		{Callers: []ServeCaller{{Name: "a", TokenSHA256: tokenHash("a"), Keys: []string{"["}}}},
+		{Callers: []ServeCaller{{Name: "a", TokenSHA256: tokenHash("a")}, {Name: "b", TokenSHA256: tokenHash("a")}}},
+		{Callers: []ServeCaller{{Name: "a", TokenSHA256: tokenHash("a")}, {Name: "a", TokenSHA256: tokenHash("b")}}},
+	}
+	for _, p := range invalid {
+		require.Error(t, p.Validate())
+	}
+
+	file := filepath.Join(t.TempDir(), "policy.json")
+	bz, err := json.Marshal(policy)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, bz, 0o600))
+	loaded, err := LoadServePolicy(file)
+	require.NoError(t, err)
+	require.Equal(t, policy, loaded)
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"callers": [], "tokens": []}`), 0o600))
+	_, err = LoadServePolicy(file)
+	require.Error(t, err)
+}
+
+func TestKeyServer(t *testing.T) {
+	kr := keyring.NewInMemory(clienttestutil.MakeTestCodec(t))
+	_, _, err := kr.NewMnemonic("relayer-1", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+	_, _, err = kr.NewMnemonic("validator", keyring.English, sdk.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+
+	policy := ServePolicy{Callers: []ServeCaller{
+		{Name: "relayer", TokenSHA256: tokenHash("relayer-token"), Operations: []string{ServeOpList, ServeOpShow, ServeOpSign}, Keys: []string{"relayer-*"}},
+		{Name: "monitor", TokenSHA256: tokenHash("monitor-token"), Operations: []string{ServeOpList}, Keys: []string{"*"}},
+	}}
+	logs := &bytes.Buffer{}
+	srv := httptest.NewServer(NewKeyServer(kr, policy, logs))
+	defer srv.Close()
+
+	do := func(method, path, token string, body interface{}) (int, []byte) {
+		var reqBody io.Reader
+		if body != nil {
+			bz, err := json.Marshal(body)
+			require.NoError(t, err)
+			reqBody = bytes.NewReader(bz)
+		}
+		req, err := http.NewRequest(method, srv.URL+path, reqBody)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		bz, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, bz
+	}
+
+	status, _ := do(http.MethodGet, "/keys", "", nil)
+	require.Equal(t, http.StatusUnauthorized, status)
+	status, _ = do(http.MethodGet, "/keys", "wrong-token", nil)
+	require.Equal(t, http.StatusUnauthorized, status)
+
+	// callers only see the keys matching their patterns
+	status, bz := do(http.MethodGet, "/keys", "relayer-token", nil)
+	require.Equal(t, http.StatusOK, status)
+	var keys []keyring.KeyOutput
+	require.NoError(t, json.Unmarshal(bz, &keys))
+	require.Len(t, keys, 1)
+	require.Equal(t, "relayer-1", keys[0].Name)
+
+	status, bz = do(http.MethodGet, "/keys", "monitor-token", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(bz, &keys))
+	require.Len(t, keys, 2)
+
+	status, _ = do(http.MethodGet, "/keys/relayer-1", "relayer-token", nil)
+	require.Equal(t, http.StatusOK, status)
+	status, _ = do(http.MethodGet, "/keys/validator", "relayer-token", nil)
+	require.Equal(t, http.StatusForbidden, status)
+	status, _ = do(http.MethodGet, "/keys/relayer-1", "monitor-token", nil)
+	require.Equal(t, http.StatusForbidden, status)
+	status, _ = do(http.MethodGet, "/keys/relayer-2", "relayer-token", nil)
+	require.Equal(t, http.StatusNotFound, status)
+
+	msg := []byte("sign bytes")
+	status, bz = do(http.MethodPost, "/keys/relayer-1/sign", "relayer-token", SignRequest{SignBytes: msg})
+	require.Equal(t, http.StatusOK, status)
+	var signed SignResponse
+	require.NoError(t, json.Unmarshal(bz, &signed))
+	record, err := kr.Key("relayer-1")
+	require.NoError(t, err)
+	pubKey, err := record.GetPubKey()
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature(msg, signed.Signature))
+
+	status, _ = do(http.MethodPost, "/keys/validator/sign", "relayer-token", SignRequest{SignBytes: msg})
+	require.Equal(t, http.StatusForbidden, status)
+	status, _ = do(http.MethodPost, "/keys/relayer-1/sign", "relayer-token", SignRequest{})
+	require.Equal(t, http.StatusBadRequest, status)
+
+	// private keys can't be exported
+	status, _ = do(http.MethodGet, "/keys/relayer-1/export", "relayer-token", nil)
+	require.Equal(t, http.StatusMethodNotAllowed, status)
+	status, _ = do(http.MethodDelete, "/keys/relayer-1", "relayer-token", nil)
+	require.Equal(t, http.StatusMethodNotAllowed, status)
+
+	require.Contains(t, logs.String(), "relayer POST /keys/relayer-1/sign 200")
+}
+
+func TestListenLocal(t *testing.T) {
+	ln, err := ListenLocal("tcp://127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	_, err = ListenLocal("tcp://0.0.0.0:0")
+	require.Error(t, err)
+	_, err = ListenLocal("http://127.0.0.1:0")
+	require.Error(t, err)
+}
@@ -0,0 +1,39 @@
+//go:build !windows
+// +build !windows
+
+package keys
+
+import (
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenUnix listens on a unix socket only accessible to the current user.
+func listenUnix(socket string) (net.Listener, error) {
+	// remove a socket left over by a daemon which didn't shut down cleanly
+	if fi, err := os.Lstat(socket); err == nil {
+		if fi.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("%s exists and is not a socket", socket)
+		}
+		if err := os.Remove(socket); err != nil {
+			return nil, err
+		}
+	}
+
+	// the umask makes the socket private from its creation. It applies to the
+	// whole process, which only listens before serving the keys.
+	oldMask := syscall.Umask(0o177)
+	ln, err := net.Listen("unix", socket)
+	syscall.Umask(oldMask)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(socket, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
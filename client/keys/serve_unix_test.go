@@ -0,0 +1,28 @@
+//go:build !windows
+// +build !windows
+
+package keys
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenLocalUnix(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "keys.sock")
+	ln, err := ListenLocal("unix://" + socket)
+	require.NoError(t, err)
+	fi, err := os.Stat(socket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	require.NoError(t, ln.Close())
+
+	// a regular file isn't replaced by the socket
+	file := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(file, []byte("{}"), 0o600))
+	_, err = ListenLocal("unix://" + file)
+	require.ErrorContains(t, err, "is not a socket")
+}
@@ -0,0 +1,15 @@
+//go:build windows
+// +build windows
+
+package keys
+
+import (
+	"errors"
+	"net"
+)
+
+// listenUnix rejects unix sockets, whose permissions can't be restricted to
+// the current user on Windows.
+func listenUnix(string) (net.Listener, error) {
+	return nil, errors.New("unix sockets are not supported on windows, listen on a loopback tcp:// address")
+}
@@ -0,0 +1,16 @@
+//go:build windows
+// +build windows
+
+package keys
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenLocalUnix(t *testing.T) {
+	_, err := ListenLocal("unix://" + filepath.Join(t.TempDir(), "keys.sock"))
+	require.ErrorContains(t, err, "not supported on windows")
+}
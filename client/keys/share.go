@@ -0,0 +1,239 @@
+package keys
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-sdk/client"
+	"github.com/baron-chain/cosmos-sdk/client/input"
+	"github.com/baron-chain/cosmos-sdk/crypto"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+)
+
+const (
+	flagThreshold       = "threshold"
+	flagShares          = "shares"
+	flagShareFilePrefix = "share-file-prefix"
+)
+
+// ShareKeyCommand shares a key of the keyring among several custodians, any
+// threshold of which can sign.
+func ShareKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "share <name>",
+		Short: "Share a private key among several keyrings, a threshold of which can sign",
+		Long: `Split a secp256k1 private key into --shares shares with Shamir's secret sharing, so
+that signing requires the shares of --threshold custodians, and fewer shares reveal
+nothing about the key. Each share is written, encrypted with its own passphrase, to
+the file <prefix>.<index>.
+
+Give each file to a custodian, who imports it into their keyring with
+"keys import-share", then delete the key with "keys delete" and any backup of it.
+Sharing protects the key at rest only: the shares never sign separately. To sign, a
+threshold of the custodians export their shares with "keys export-share" to the
+custodian trusted with the whole key, who rebuilds it into their keyring with
+"keys combine".`,
+		Example: "  barond keys share validator --threshold 2 --shares 3 --share-file-prefix validator.share",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			buf := bufio.NewReader(clientCtx.Input)
+
+			threshold, _ := cmd.Flags().GetUint32(flagThreshold)
+			total, _ := cmd.Flags().GetUint32(flagShares)
+			prefix, _ := cmd.Flags().GetString(flagShareFilePrefix)
+
+			approval, err := requireKeyApproval(cmd, clientCtx, ApprovalOpExport, args[:1])
+			if err != nil {
+				return err
+			}
+			return approval.done(shareKey(cmd, buf, clientCtx.Keyring, args[0], threshold, total, prefix))
+		},
+	}
+
+	cmd.Flags().Uint32(flagThreshold, 2, "Number of shares signing requires")
+	cmd.Flags().Uint32(flagShares, 3, "Number of shares to write")
+	cmd.Flags().String(flagShareFilePrefix, "", "Prefix of the paths of the files the encrypted shares are written to")
+	_ = cmd.MarkFlagRequired(flagShareFilePrefix)
+	addApprovalFileFlag(cmd)
+
+	return cmd
+}
+
+// shareKey exports the private key of the named key, splits it into shares,
+// and writes each share, encrypted, to the file <prefix>.<index>.
+func shareKey(cmd *cobra.Command, buf *bufio.Reader, kr keyring.Keyring, name string, threshold, total uint32, prefix string) error {
+	exporter, ok := kr.(unsafeExporter)
+	if !ok {
+		return errors.New("the keyring doesn't support exporting private keys")
+	}
+	privKey, err := exporter.ExportPrivateKeyObject(name)
+	if err != nil {
+		return err
+	}
+
+	shares, err := crypto.SharePrivKey(privKey, threshold, total)
+	if err != nil {
+		return err
+	}
+	for _, share := range shares {
+		path := fmt.Sprintf("%s.%d", prefix, share.Index)
+		if err := writeKeyShare(cmd, buf, path, share, fmt.Sprintf("share %d", share.Index)); err != nil {
+			return err
+		}
+	}
+
+	cmd.PrintErrf("Key %s is shared %d of %d, delete it from the keyring once the shares are imported\n", name, threshold, total)
+	return nil
+}
+
+// ImportKeyShareCommand imports a key share written by keys share into the
+// keyring.
+func ImportKeyShareCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import-share <name> <share-file>",
+		Short: "Import a key share written by keys share into the keyring",
+		Long: `Decrypt a key share written by "keys share" and store it in the keyring under the
+given name, with the address of the whole key. A keyring holds a single share of
+a key, and can't sign with it: combine the shares with "keys combine".`,
+		Example: "  barond keys import-share validator validator.share.2",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			kr, ok := clientCtx.Keyring.(keyring.ShareKeyring)
+			if !ok {
+				return errors.New("the keyring doesn't support key shares")
+			}
+
+			armored, err := os.ReadFile(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to read key share: %w", err)
+			}
+			passphrase, err := input.GetPassword("Enter passphrase to decrypt the key share:", bufio.NewReader(clientCtx.Input))
+			if err != nil {
+				return err
+			}
+			share, err := crypto.UnarmorDecryptKeyShare(string(armored), passphrase)
+			if err != nil {
+				return err
+			}
+
+			k, err := kr.SaveKeyShare(args[0], share)
+			if err != nil {
+				return err
+			}
+			return printKeyringRecord(cmd.OutOrStdout(), k, keyring.MkAccKeyOutput, clientCtx.OutputFormat)
+		},
+	}
+}
+
+// ExportKeyShareCommand exports a key share of the keyring, encrypted, to
+// combine it with the other shares of the key.
+func ExportKeyShareCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export-share <name>",
+		Short: "Export a key share of the keyring, encrypted, to combine the shares of a key",
+		Long: `Print the key share imported with "keys import-share", encrypted with a passphrase
+prompted for twice, to hand it to the custodian combining a threshold of the shares
+of the key into their keyring with "keys combine".`,
+		Example: "  barond keys export-share validator > validator.share.2",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			kr, ok := clientCtx.Keyring.(keyring.ShareKeyring)
+			if !ok {
+				return errors.New("the keyring doesn't support key shares")
+			}
+
+			buf := bufio.NewReader(clientCtx.Input)
+			passphrase, err := input.GetPassword("Enter passphrase to encrypt the key share:", buf)
+			if err != nil {
+				return err
+			}
+			repeated, err := input.GetPassword("Repeat the passphrase:", buf)
+			if err != nil {
+				return err
+			}
+			if passphrase != repeated {
+				return errors.New("passphrases don't match")
+			}
+
+			armored, err := kr.ExportKeyShareArmor(args[0], passphrase)
+			if err != nil {
+				return err
+			}
+			cmd.Println(armored)
+			return nil
+		},
+	}
+}
+
+// CombineCommand combines key shares into the key, stored in the keyring.
+func CombineCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "combine <name> <share-file>...",
+		Short: "Combine the shares of a key into the keyring",
+		Long: `Decrypt the share files of a key, written by "keys split" or "keys share", or
+exported with "keys export-share", and store the key they combine into in the
+keyring under the given name. The key is rebuilt from both shares of a split key,
+or from a threshold of the shares of a shared key, and checked against the public
+key of the shares. The passphrase of each share file is prompted for in turn.
+
+The shares are never used to sign separately: combine them on a machine trusted
+with the whole key, and sign with the combined key.`,
+		Example: "  barond keys combine validator validator.share.1 validator.share.3",
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			buf := bufio.NewReader(clientCtx.Input)
+
+			shares := make([]crypto.KeyShare, len(args)-1)
+			for i, path := range args[1:] {
+				armored, err := os.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("failed to read key share: %w", err)
+				}
+				passphrase, err := input.GetPassword(fmt.Sprintf("Enter passphrase to decrypt %s:", path), buf)
+				if err != nil {
+					return err
+				}
+				if shares[i], err = crypto.UnarmorDecryptKeyShare(string(armored), passphrase); err != nil {
+					return fmt.Errorf("invalid key share %s: %w", path, err)
+				}
+			}
+
+			privKey, err := crypto.CombineKeyShares(shares...)
+			if err != nil {
+				return err
+			}
+			if err := clientCtx.Keyring.ImportPrivKeyHex(args[0], hex.EncodeToString(privKey.Bytes()), privKey.Type()); err != nil {
+				return err
+			}
+
+			k, err := clientCtx.Keyring.Key(args[0])
+			if err != nil {
+				return err
+			}
+			return printKeyringRecord(cmd.OutOrStdout(), k, keyring.MkAccKeyOutput, clientCtx.OutputFormat)
+		},
+	}
+
+	return enforceKeyNamespaces(cmd)
+}
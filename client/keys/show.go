@@ -43,6 +43,8 @@ Multiple names or addresses create an ephemeral multisig key under "multi".`,
     flags.BoolP(FlagDevice, "d", false, "Output address in ledger device")
     flags.BoolP(FlagQuantumSafe, "q", true, "Use quantum-safe encryption")
     flags.Int(flagMultiSigThreshold, 1, "K out of N required signatures")
+    addAddressFormatFlag(cmd)
+    addCopyFlags(cmd)
 
     return cmd
 }
@@ -140,15 +142,39 @@ func displayKeyInfo(cmd *cobra.Command, k *keyring.Record, outputFormat string)
         return errors.New("cannot use both --address and --pubkey")
     }
 
+    // secrets are never copied, only addresses
+    isCopy, _ := cmd.Flags().GetBool(flagCopy)
+    if isCopy && !isShowAddr {
+        return fmt.Errorf("--%s requires --%s", flagCopy, FlagAddress)
+    }
+
     bechPrefix, _ := cmd.Flags().GetString(FlagBechPrefix)
     bechKeyOut, err := getBechKeyOut(bechPrefix)
     if err != nil {
         return err
     }
 
+    addrFormat, err := getAddressFormat(cmd)
+    if err != nil {
+        return err
+    }
+    bechKeyOut = withAddressFormat(bechKeyOut, addrFormat)
+
     if isShowDevice {
         return handleDeviceDisplay(k, bechPrefix, isShowPubKey)
     }
 
+    if isShowAddr {
+        ko, err := bechKeyOut(k)
+        if err != nil {
+            return err
+        }
+        fmt.Fprintln(cmd.OutOrStdout(), ko.Address)
+        if isCopy {
+            return copyAddress(cmd, ko.Address)
+        }
+        return nil
+    }
+
     return printKeyringRecord(cmd.OutOrStdout(), k, bechKeyOut, outputFormat)
 }
@@ -0,0 +1,325 @@
+package keys
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec/legacy"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	flagShredAll      = "all"
+	flagConfirmPhrase = "confirm-phrase"
+
+	// ShredConfirmPhrase is the phrase to pass to keys shred with
+	// --confirm-phrase, so that the keyring isn't destroyed by mistake.
+	ShredConfirmPhrase = "destroy all keys"
+
+	// keyShredDomain prefixes the signed statement of a destruction receipt,
+	// so that its signatures can't be replayed as those of anything else.
+	keyShredDomain      = "baron-chain key destruction receipt:"
+	shredReceiptVersion = "1"
+	// shredPasses is the number of random overwrites of the keyring files.
+	shredPasses = 3
+)
+
+// ShreddedKey is a key destroyed by keys shred.
+type ShreddedKey struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Address string `json:"address,omitempty"`
+	// PubKey is the amino encoded public key, base64 encoded.
+	PubKey string `json:"pub_key,omitempty"`
+}
+
+// ShredStatement is the statement of a destruction receipt, signed by the
+// destroyed keys before their destruction.
+type ShredStatement struct {
+	Version    string        `json:"version"`
+	Host       string        `json:"host"`
+	Backend    string        `json:"backend"`
+	ShreddedAt time.Time     `json:"shredded_at"`
+	Keys       []ShreddedKey `json:"keys"`
+}
+
+// ShredSignature is the signature of the statement of a destruction receipt
+// by a destroyed key.
+type ShredSignature struct {
+	Name      string `json:"name"`
+	Signature []byte `json:"signature"`
+}
+
+// ShredReceipt is the receipt of the destruction of a keyring by keys shred.
+// The local keys sign its statement before they are destroyed, which proves
+// that the host held them, and the receipt lists how the storage was wiped.
+// Ledger, offline and multisig keys only have their references destroyed, and
+// don't sign it.
+type ShredReceipt struct {
+	Statement  ShredStatement   `json:"statement"`
+	Signatures []ShredSignature `json:"signatures"`
+	// WipedFiles is the number of keyring files overwritten before their
+	// removal, for the file and test backends.
+	WipedFiles int `json:"wiped_files"`
+	// Errors are the failures to destroy keys or files, which didn't stop
+	// the destruction of the others.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ShredKeysCommand returns the command destroying all the keys of the
+// keyring, for incident response.
+func ShredKeysCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shred --all --confirm-phrase <phrase>",
+		Short: "Destroy all the keys of the keyring and output a signed destruction receipt",
+		Long: `Destroy all the keys of the keyring, for incident response on a compromised host, and
+output a JSON destruction receipt.
+
+The receipt lists the destroyed keys, and is signed by each local key before it is
+destroyed, so that it proves which keys the host held; verify it with the public keys
+it holds. Ledger, offline and multisig keys only have their references removed.
+
+With the file and test backends, the keyring files are overwritten with random bytes
+before they are removed. Overwriting doesn't reliably erase the data of SSDs, and of
+journaling or copy-on-write file systems, which may keep older copies: only the
+encryption of the file backend protects those. With the other backends, the keys are
+deleted from the secret store, which erases them as it does.
+
+The destruction can't be undone, and requires --all and --confirm-phrase "` + ShredConfirmPhrase + `".
+Under dual control of deletions, it also requires the approval of the deletion of all
+the keys, "*".`,
+		Example: `  barond keys shred --all --confirm-phrase "` + ShredConfirmPhrase + `" > shred-receipt.json`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if all, _ := cmd.Flags().GetBool(flagShredAll); !all {
+				return fmt.Errorf("keys shred destroys all the keys, and requires --%s", flagShredAll)
+			}
+			if phrase, _ := cmd.Flags().GetString(flagConfirmPhrase); phrase != ShredConfirmPhrase {
+				return fmt.Errorf("wrong --%s, confirm the destruction of all the keys with --%s %q", flagConfirmPhrase, flagConfirmPhrase, ShredConfirmPhrase)
+			}
+
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			approval, err := requireKeyApproval(cmd, clientCtx, ApprovalOpDelete, []string{AllKeys})
+			if err != nil {
+				return err
+			}
+			return approval.done(shredKeys(cmd, clientCtx))
+		},
+	}
+
+	cmd.Flags().Bool(flagShredAll, false, "Destroy all the keys of the keyring, the only mode of the command")
+	cmd.Flags().String(flagConfirmPhrase, "", fmt.Sprintf("Confirmation of the destruction, %q", ShredConfirmPhrase))
+	addApprovalFileFlag(cmd)
+
+	return cmd
+}
+
+// shredKeys destroys all the keys of the keyring of clientCtx and prints the
+// destruction receipt.
+func shredKeys(cmd *cobra.Command, clientCtx client.Context) error {
+	host, _ := os.Hostname()
+
+	receipt, err := ShredKeyring(clientCtx.Keyring, clientCtx.KeyringDir, host, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	for _, e := range receipt.Errors {
+		cmd.PrintErrf("warning: %s\n", e)
+	}
+
+	bz, err := json.MarshalIndent(receipt, "", "  ")
+	if err != nil {
+		return err
+	}
+	cmd.Println(string(bz))
+
+	cmd.PrintErrf("Destroyed %d keys\n", len(receipt.Statement.Keys))
+	return nil
+}
+
+// SignBytes returns the bytes the signatures of the receipt are over: the
+// JSON encoded statement prefixed with the key shred domain.
+func (s ShredStatement) SignBytes() ([]byte, error) {
+	bz, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(keyShredDomain), bz...), nil
+}
+
+// ShredKeyring destroys all the keys of kr, whose keyring directory is
+// keyringDir, and returns the receipt of their destruction, signed by the
+// local keys. The destruction isn't stopped by the failure to destroy a key
+// or a file, which is reported in the errors of the receipt.
+func ShredKeyring(kr keyring.Keyring, keyringDir, host string, now time.Time) (ShredReceipt, error) {
+	records, err := kr.List()
+	if err != nil {
+		return ShredReceipt{}, err
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+
+	receipt := ShredReceipt{
+		Statement: ShredStatement{
+			Version:    shredReceiptVersion,
+			Host:       host,
+			Backend:    kr.Backend(),
+			ShreddedAt: now,
+			Keys:       make([]ShreddedKey, 0, len(records)),
+		},
+		Signatures: []ShredSignature{},
+	}
+	for _, k := range records {
+		key := ShreddedKey{Name: k.Name, Type: k.GetType().String()}
+		if pub, err := k.GetPubKey(); err == nil {
+			key.Address = sdk.AccAddress(pub.Address()).String()
+			if pubBytes, err := legacy.Cdc.Marshal(pub); err == nil {
+				key.PubKey = base64.StdEncoding.EncodeToString(pubBytes)
+			}
+		}
+		receipt.Statement.Keys = append(receipt.Statement.Keys, key)
+	}
+
+	signBytes, err := receipt.Statement.SignBytes()
+	if err != nil {
+		return ShredReceipt{}, err
+	}
+	for _, k := range records {
+		if k.GetType() != keyring.TypeLocal {
+			continue
+		}
+		sig, _, err := kr.Sign(k.Name, signBytes)
+		if err != nil {
+			receipt.Errors = append(receipt.Errors, fmt.Sprintf("key %s didn't sign the receipt: %v", k.Name, err))
+			continue
+		}
+		receipt.Signatures = append(receipt.Signatures, ShredSignature{Name: k.Name, Signature: sig})
+	}
+
+	switch backend := kr.Backend(); backend {
+	case keyring.BackendFile, keyring.BackendTest:
+		// the keys are in the files of the backend directory, overwritten in
+		// place before their removal
+		wiped, errs := wipeDir(filepath.Join(keyringDir, "keyring-"+backend))
+		receipt.WipedFiles = wiped
+		receipt.Errors = append(receipt.Errors, errs...)
+
+	default:
+		for _, k := range records {
+			if err := kr.Delete(k.Name); err != nil {
+				receipt.Errors = append(receipt.Errors, fmt.Sprintf("failed to delete key %s: %v", k.Name, err))
+			}
+		}
+	}
+
+	return receipt, nil
+}
+
+// VerifyShredReceipt verifies that each signature of the receipt is a valid
+// signature of its statement by the destroyed key of the same name.
+func VerifyShredReceipt(receipt ShredReceipt) error {
+	signBytes, err := receipt.Statement.SignBytes()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]ShreddedKey, len(receipt.Statement.Keys))
+	for _, k := range receipt.Statement.Keys {
+		keys[k.Name] = k
+	}
+	for _, sig := range receipt.Signatures {
+		k, ok := keys[sig.Name]
+		if !ok {
+			return fmt.Errorf("signature of key %s, which isn't in the receipt", sig.Name)
+		}
+		pubBytes, err := base64.StdEncoding.DecodeString(k.PubKey)
+		if err != nil {
+			return fmt.Errorf("invalid public key of key %s: %w", k.Name, err)
+		}
+		pub, err := legacy.PubKeyFromBytes(pubBytes)
+		if err != nil {
+			return fmt.Errorf("invalid public key of key %s: %w", k.Name, err)
+		}
+		if addr := sdk.AccAddress(pub.Address()).String(); addr != k.Address {
+			return fmt.Errorf("address %s of key %s doesn't match its public key address %s", k.Address, k.Name, addr)
+		}
+		if !pub.VerifySignature(signBytes, sig.Signature) {
+			return fmt.Errorf("invalid signature of key %s", k.Name)
+		}
+	}
+	return nil
+}
+
+// wipeDir overwrites the regular files of dir with random bytes, syncing
+// each pass to the disk, and removes dir. It returns the number of files
+// overwritten and the failures, after trying all the files.
+func wipeDir(dir string) (int, []string) {
+	var (
+		wiped int
+		errs  []string
+	)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err.Error())
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if err := overwriteFile(path); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to overwrite %s: %v", path, err))
+			return nil
+		}
+		wiped++
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		errs = append(errs, fmt.Sprintf("failed to remove %s: %v", dir, err))
+	}
+	return wiped, errs
+}
+
+// overwriteFile overwrites the content of the file at path with random bytes.
+func overwriteFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < shredPasses; i++ {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(f, rand.Reader, info.Size()); err != nil {
+			return err
+		}
+		if err := f.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package keys
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	clienttestutil "github.com/cosmos/cosmos-sdk/client/testutil"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestShredKeyring(t *testing.T) {
+	dir := t.TempDir()
+	kr, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, dir, strings.NewReader(""), clienttestutil.MakeTestCodec(t))
+	require.NoError(t, err)
+
+	_, err = kr.NewAccount("validator", testdata.TestMnemonic, "", sdk.FullFundraiserPath, hd.Secp256k1)
+	require.NoError(t, err)
+	_, pub, _ := testdata.KeyTestPubAddr()
+	_, err = kr.SaveOfflineKey("watch", pub)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	receipt, err := ShredKeyring(kr, dir, "host-1", now)
+	require.NoError(t, err)
+	require.Empty(t, receipt.Errors)
+
+	require.Equal(t, "host-1", receipt.Statement.Host)
+	require.Equal(t, keyring.BackendTest, receipt.Statement.Backend)
+	require.Equal(t, now, receipt.Statement.ShreddedAt)
+	require.Len(t, receipt.Statement.Keys, 2)
+	require.Equal(t, "validator", receipt.Statement.Keys[0].Name)
+	require.Equal(t, "offline", receipt.Statement.Keys[1].Type)
+	// the offline key can't sign the receipt
+	require.Len(t, receipt.Signatures, 1)
+	require.Equal(t, "validator", receipt.Signatures[0].Name)
+	require.NoError(t, VerifyShredReceipt(receipt))
+	require.Positive(t, receipt.WipedFiles)
+
+	_, err = os.Stat(filepath.Join(dir, "keyring-test"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	records, err := kr.List()
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	receipt.Statement.Host = "host-2"
+	require.ErrorContains(t, VerifyShredReceipt(receipt), "invalid signature of key validator")
+}
+
+func TestShredKeysCommandConfirmation(t *testing.T) {
+	cmd := ShredKeysCommand()
+	cmd.SetArgs([]string{"--confirm-phrase", ShredConfirmPhrase})
+	require.ErrorContains(t, cmd.Execute(), "requires --all")
+
+	cmd = ShredKeysCommand()
+	cmd.SetArgs([]string{"--all", "--confirm-phrase", "yes"})
+	require.ErrorContains(t, cmd.Execute(), "wrong --confirm-phrase")
+}
+
+func TestShredKeysCommandDualControl(t *testing.T) {
+	dir := t.TempDir()
+	cdc := clienttestutil.MakeTestCodec(t)
+	kr, err := keyring.New(sdk.KeyringServiceName(), keyring.BackendTest, dir, strings.NewReader(""), cdc)
+	require.NoError(t, err)
+	officer, err := kr.NewAccount("officer", testdata.TestMnemonic, "", sdk.FullFundraiserPath, hd.Secp256k1)
+	require.NoError(t, err)
+	addr, err := officer.GetAddress()
+	require.NoError(t, err)
+
+	cfg, err := json.Marshal(DualControlConfig{Approvers: []string{addr.String()}, Operations: []string{ApprovalOpDelete}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DualControlConfigFileName), cfg, 0o600))
+
+	shred := func(args ...string) error {
+		cmd := ShredKeysCommand()
+		testutil.ApplyMockIODiscardOutErr(cmd)
+		clientCtx := client.Context{}.WithKeyring(kr).WithKeyringDir(dir).WithCodec(cdc)
+		ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+		cmd.SetArgs(append([]string{"--all", "--confirm-phrase", ShredConfirmPhrase}, args...))
+		return cmd.ExecuteContext(ctx)
+	}
+
+	require.ErrorContains(t, shred(), "requires an approval")
+	_, err = kr.Key("officer")
+	require.NoError(t, err)
+
+	armored, err := ApproveKeyOperation(kr, "officer", ApprovalOpDelete, []string{AllKeys}, time.Hour)
+	require.NoError(t, err)
+	approvalFile := filepath.Join(t.TempDir(), "approval.asc")
+	require.NoError(t, os.WriteFile(approvalFile, []byte(armored), 0o600))
+
+	require.NoError(t, shred("--approval-file", approvalFile))
+	records, err := kr.List()
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
@@ -0,0 +1,300 @@
+package keys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/circl/kem/kyber/kyber768"
+	"github.com/gtank/ristretto255"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// keyTransferDomain separates the hashes and keys of the key transfer
+	// protocol from those of other protocols.
+	keyTransferDomain  = "baron-chain/keys-transfer/v1"
+	keyTransferVersion = byte(1)
+
+	spake2ElementSize = 32
+	// maxTransferFrameSize bounds the frames read from the peer.
+	maxTransferFrameSize = 1 << 20
+)
+
+var (
+	// spake2M and spake2N are the SPAKE2 group elements blinding the shares
+	// of the sender and the receiver, whose discrete logarithms are unknown.
+	spake2M = hashToElement("M")
+	spake2N = hashToElement("N")
+
+	errTransferCode = errors.New("key transfer authentication failed: the transfer code is wrong, or the peer isn't the one it was given to")
+)
+
+func hashToElement(label string) *ristretto255.Element {
+	h := sha512.Sum512([]byte(keyTransferDomain + "/spake2/" + label))
+	return ristretto255.NewElement().FromUniformBytes(h[:])
+}
+
+// transferKeys are the keys derived by the key transfer handshake.
+type transferKeys struct {
+	senderConfirm   []byte
+	receiverConfirm []byte
+	aead            cipher.AEAD
+	// transcript is the hash of the handshake messages, authenticated as the
+	// additional data of the encrypted frames.
+	transcript []byte
+}
+
+// The key transfer handshake authenticates the peer as knowing the transfer
+// code and derives the keys encrypting the transfer. The sender and the
+// receiver run a SPAKE2 exchange over ristretto255 keyed by the code, which a
+// man in the middle can only guess once per transfer, and the receiver a
+// Kyber-768 key encapsulation, so that recorded transfers stay confidential
+// from quantum adversaries. The shared secrets of both are combined, and
+// confirmed by both peers. The messages are:
+//
+//	sender -> receiver: version, T = x*G + w*M
+//	receiver -> sender: S = y*G + w*N, Kyber-768 public key
+//	sender -> receiver: Kyber-768 ciphertext, sender confirmation
+//	receiver -> sender: receiver confirmation
+
+// senderHandshake runs the key transfer handshake as the sender.
+func senderHandshake(conn io.ReadWriter, code string) (*transferKeys, error) {
+	w := transferCodeScalar(code)
+	x, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	scheme := kyber768.Scheme()
+
+	t := blindedShare(x, w, spake2M)
+	if err := writeTransferFrame(conn, append([]byte{keyTransferVersion}, t...)); err != nil {
+		return nil, err
+	}
+
+	msg, err := readTransferFrame(conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(msg) != spake2ElementSize+scheme.PublicKeySize() {
+		return nil, fmt.Errorf("invalid key transfer handshake message of %d bytes", len(msg))
+	}
+	s, pk := msg[:spake2ElementSize], msg[spake2ElementSize:]
+	shared, err := unblindShare(x, w, spake2N, s)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := scheme.UnmarshalBinaryPublicKey(pk)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key transfer Kyber public key: %w", err)
+	}
+	ct, kyberSecret, err := scheme.Encapsulate(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := deriveTransferKeys(w, shared, kyberSecret, t, s, pk, ct)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTransferFrame(conn, append(append([]byte{}, ct...), keys.confirm(keys.senderConfirm)...)); err != nil {
+		return nil, err
+	}
+	msg, err = readTransferFrame(conn)
+	if errors.Is(err, io.EOF) {
+		// the receiver closes the connection when the confirmation fails
+		return nil, errTransferCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(msg, keys.confirm(keys.receiverConfirm)) {
+		return nil, errTransferCode
+	}
+	return keys, nil
+}
+
+// receiverHandshake runs the key transfer handshake as the receiver.
+func receiverHandshake(conn io.ReadWriter, code string) (*transferKeys, error) {
+	w := transferCodeScalar(code)
+	y, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	scheme := kyber768.Scheme()
+
+	msg, err := readTransferFrame(conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(msg) != 1+spake2ElementSize || msg[0] != keyTransferVersion {
+		return nil, fmt.Errorf("unsupported key transfer handshake, expected version %d", keyTransferVersion)
+	}
+	t := msg[1:]
+	shared, err := unblindShare(y, w, spake2M, t)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, privKey, err := scheme.GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	pk, err := pubKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	s := blindedShare(y, w, spake2N)
+	if err := writeTransferFrame(conn, append(append([]byte{}, s...), pk...)); err != nil {
+		return nil, err
+	}
+
+	if msg, err = readTransferFrame(conn); err != nil {
+		return nil, err
+	}
+	if len(msg) != scheme.CiphertextSize()+sha256.Size {
+		return nil, fmt.Errorf("invalid key transfer handshake message of %d bytes", len(msg))
+	}
+	ct, confirmation := msg[:scheme.CiphertextSize()], msg[scheme.CiphertextSize():]
+	kyberSecret, err := scheme.Decapsulate(privKey, ct)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := deriveTransferKeys(w, shared, kyberSecret, t, s, pk, ct)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(confirmation, keys.confirm(keys.senderConfirm)) {
+		return nil, errTransferCode
+	}
+	if err := writeTransferFrame(conn, keys.confirm(keys.receiverConfirm)); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func randomScalar() (*ristretto255.Scalar, error) {
+	bz := make([]byte, 64)
+	if _, err := rand.Read(bz); err != nil {
+		return nil, err
+	}
+	return ristretto255.NewScalar().FromUniformBytes(bz), nil
+}
+
+// transferCodeScalar maps a transfer code to the SPAKE2 password scalar.
+func transferCodeScalar(code string) *ristretto255.Scalar {
+	h := sha512.Sum512([]byte(keyTransferDomain + "/code/" + code))
+	return ristretto255.NewScalar().FromUniformBytes(h[:])
+}
+
+// blindedShare returns the encoding of x*G + w*blind.
+func blindedShare(x, w *ristretto255.Scalar, blind *ristretto255.Element) []byte {
+	share := ristretto255.NewElement().ScalarBaseMult(x)
+	share.Add(share, ristretto255.NewElement().ScalarMult(w, blind))
+	return share.Encode(nil)
+}
+
+// unblindShare returns x*(share - w*blind), the shared SPAKE2 element.
+func unblindShare(x, w *ristretto255.Scalar, blind *ristretto255.Element, share []byte) (*ristretto255.Element, error) {
+	e := ristretto255.NewElement()
+	if err := e.Decode(share); err != nil {
+		return nil, fmt.Errorf("invalid key transfer share: %w", err)
+	}
+	e.Subtract(e, ristretto255.NewElement().ScalarMult(w, blind))
+	e.ScalarMult(x, e)
+	if e.Equal(ristretto255.NewElement().Zero()) == 1 {
+		return nil, errors.New("invalid key transfer share")
+	}
+	return e, nil
+}
+
+// deriveTransferKeys derives the confirmation and encryption keys from the
+// SPAKE2 and Kyber shared secrets, bound to the handshake transcript.
+func deriveTransferKeys(w *ristretto255.Scalar, shared *ristretto255.Element, kyberSecret []byte, t, s, pk, ct []byte) (*transferKeys, error) {
+	transcript := sha256.New()
+	for _, part := range [][]byte{[]byte(keyTransferDomain), t, s, pk, ct} {
+		_ = binary.Write(transcript, binary.BigEndian, uint32(len(part)))
+		transcript.Write(part)
+	}
+	keys := &transferKeys{transcript: transcript.Sum(nil)}
+
+	ikm := append(append(shared.Encode(nil), w.Encode(nil)...), kyberSecret...)
+	kdf := hkdf.New(sha256.New, ikm, keys.transcript, []byte(keyTransferDomain))
+	okm := make([]byte, 3*32)
+	if _, err := io.ReadFull(kdf, okm); err != nil {
+		return nil, err
+	}
+	keys.senderConfirm, keys.receiverConfirm = okm[:32], okm[32:64]
+
+	block, err := aes.NewCipher(okm[64:])
+	if err != nil {
+		return nil, err
+	}
+	if keys.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// confirm returns the confirmation of the transcript with key.
+func (k *transferKeys) confirm(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(k.transcript)
+	return mac.Sum(nil)
+}
+
+// writeSealed encrypts msg and writes it in a frame.
+func (k *transferKeys) writeSealed(w io.Writer, msg []byte) error {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	return writeTransferFrame(w, k.aead.Seal(nonce, nonce, msg, k.transcript))
+}
+
+// readSealed reads a frame written with writeSealed and decrypts it.
+func (k *transferKeys) readSealed(r io.Reader) ([]byte, error) {
+	frame, err := readTransferFrame(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(frame) < k.aead.NonceSize() {
+		return nil, errors.New("truncated key transfer frame")
+	}
+	msg, err := k.aead.Open(nil, frame[:k.aead.NonceSize()], frame[k.aead.NonceSize():], k.transcript)
+	if err != nil {
+		return nil, errors.New("failed to decrypt key transfer frame")
+	}
+	return msg, nil
+}
+
+func writeTransferFrame(w io.Writer, msg []byte) error {
+	frame := make([]byte, 4, 4+len(msg))
+	binary.BigEndian.PutUint32(frame, uint32(len(msg)))
+	_, err := w.Write(append(frame, msg...))
+	return err
+}
+
+func readTransferFrame(r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxTransferFrameSize {
+		return nil, fmt.Errorf("key transfer frame of %d bytes exceeds the limit of %d bytes", n, maxTransferFrameSize)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
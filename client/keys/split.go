@@ -0,0 +1,133 @@
+package keys
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-sdk/client"
+	"github.com/baron-chain/cosmos-sdk/client/input"
+	"github.com/baron-chain/cosmos-sdk/crypto"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+)
+
+const (
+	flagLocalShareFile  = "local-share-file"
+	flagRemoteShareFile = "remote-share-file"
+)
+
+// SplitKeyCommand splits a key of the keyring between a local share and a
+// remote share.
+func SplitKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "split <name>",
+		Short: "Split a private key between the keyring and a remote custodian",
+		Long: `Split a secp256k1 private key into two shares, so that neither the local share nor
+the remote share, held by a remote custodian, can sign alone. Each share is written,
+encrypted with its own passphrase, to the given file.
+
+Give the remote share to its custodian, store the local share with the client, then
+delete the key with "keys delete" and any backup of it: a share alone reveals nothing
+about the key.
+
+Splitting protects the key at rest only: the shares never sign separately. To sign,
+gather both share files on a machine trusted with the whole key, and rebuild the key
+into its keyring with "keys combine". Dilithium keys are not supported yet.`,
+		Example: "  barond keys split validator --local-share-file validator.local --remote-share-file validator.remote",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			buf := bufio.NewReader(clientCtx.Input)
+
+			localFile, _ := cmd.Flags().GetString(flagLocalShareFile)
+			remoteFile, _ := cmd.Flags().GetString(flagRemoteShareFile)
+			if localFile == remoteFile {
+				return errors.New("the local and remote shares must be written to different files")
+			}
+
+			approval, err := requireKeyApproval(cmd, clientCtx, ApprovalOpExport, args[:1])
+			if err != nil {
+				return err
+			}
+			return approval.done(splitKey(cmd, buf, clientCtx.Keyring, args[0], localFile, remoteFile))
+		},
+	}
+
+	cmd.Flags().String(flagLocalShareFile, "", "Path of the file the encrypted local share is written to")
+	cmd.Flags().String(flagRemoteShareFile, "", "Path of the file the encrypted remote share is written to")
+	_ = cmd.MarkFlagRequired(flagLocalShareFile)
+	_ = cmd.MarkFlagRequired(flagRemoteShareFile)
+	addApprovalFileFlag(cmd)
+
+	return cmd
+}
+
+// splitKey exports the private key of the named key, splits it, and writes
+// each share, encrypted, to its file.
+func splitKey(cmd *cobra.Command, buf *bufio.Reader, kr keyring.Keyring, name, localFile, remoteFile string) error {
+	exporter, ok := kr.(unsafeExporter)
+	if !ok {
+		return errors.New("the keyring doesn't support exporting private keys")
+	}
+	privKey, err := exporter.ExportPrivateKeyObject(name)
+	if err != nil {
+		return err
+	}
+
+	local, remote, err := crypto.SplitPrivKey(privKey)
+	if err != nil {
+		return err
+	}
+
+	if err := writeKeyShare(cmd, buf, localFile, local, "local"); err != nil {
+		return err
+	}
+	if err := writeKeyShare(cmd, buf, remoteFile, remote, "remote"); err != nil {
+		return err
+	}
+
+	cmd.PrintErrf("Key %s is split, delete it from the keyring once the remote share is handed to its custodian\n", name)
+	return nil
+}
+
+// writeKeyShare encrypts the share with a passphrase prompted for twice, and
+// writes it to a new file only readable by the current user.
+func writeKeyShare(cmd *cobra.Command, buf *bufio.Reader, path string, share crypto.KeyShare, kind string) error {
+	passphrase, err := input.GetPassword(fmt.Sprintf("Enter passphrase to encrypt the %s share:", kind), buf)
+	if err != nil {
+		return err
+	}
+	repeated, err := input.GetPassword("Repeat the passphrase:", buf)
+	if err != nil {
+		return err
+	}
+	if passphrase != repeated {
+		return errors.New("passphrases don't match")
+	}
+
+	armored, err := crypto.EncryptArmorKeyShare(share, passphrase)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s share file: %w", kind, err)
+	}
+	if _, err := f.WriteString(armored); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s share file: %w", kind, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s share file: %w", kind, err)
+	}
+
+	cmd.PrintErrf("Wrote the %s share to %s\n", kind, path)
+	return nil
+}
@@ -0,0 +1,61 @@
+package keys
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/baron-chain/cosmos-sdk/client"
+	clienttestutil "github.com/baron-chain/cosmos-sdk/client/testutil"
+	"github.com/baron-chain/cosmos-sdk/crypto"
+	"github.com/baron-chain/cosmos-sdk/crypto/keyring"
+	"github.com/baron-chain/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/baron-chain/cosmos-sdk/testutil"
+)
+
+func TestCombineSplitKey(t *testing.T) {
+	cdc := clienttestutil.MakeTestCodec(t)
+	privKey := secp256k1.GenPrivKey()
+	local, remote, err := crypto.SplitPrivKey(privKey)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	var files []string
+	for i, share := range []crypto.KeyShare{local, remote} {
+		armored, err := crypto.EncryptArmorKeyShare(share, fmt.Sprintf("passphrase%d", i))
+		require.NoError(t, err)
+		path := filepath.Join(dir, fmt.Sprintf("validator.%d", i))
+		require.NoError(t, os.WriteFile(path, []byte(armored), 0o600))
+		files = append(files, path)
+	}
+
+	combine := func(input string, args ...string) (keyring.Keyring, error) {
+		kb := keyring.NewInMemory(cdc)
+		cmd := CombineCommand()
+		mockIn := testutil.ApplyMockIODiscardOutErr(cmd)
+		clientCtx := client.Context{}.WithKeyring(kb).WithInput(mockIn).WithCodec(cdc)
+		ctx := context.WithValue(context.Background(), client.ClientContextKey, &clientCtx)
+
+		mockIn.Reset(input)
+		cmd.SetArgs(args)
+		return kb, cmd.ExecuteContext(ctx)
+	}
+
+	// the shares are combined into the key, which signs alone
+	kb, err := combine("passphrase0\npassphrase1\n", "validator", files[0], files[1])
+	require.NoError(t, err)
+	msg := []byte("sign bytes")
+	sig, pub, err := kb.Sign("validator", msg)
+	require.NoError(t, err)
+	require.True(t, privKey.PubKey().Equals(pub))
+	require.True(t, pub.VerifySignature(msg, sig))
+
+	_, err = combine("passphrase0\nwrong\n", "validator", files[0], files[1])
+	require.Error(t, err)
+	_, err = combine("passphrase0\n", "validator", files[0])
+	require.ErrorContains(t, err, "expected 2 key shares")
+}
@@ -0,0 +1,431 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagOutputFile = "output-file"
+	flagChunkSize  = "chunk-size"
+	flagResume     = "resume"
+
+	keyPartsExt     = ".parts.json"
+	keyPartsVersion = 1
+	// keyStagingExt is the extension of the armored key staged during a
+	// chunked export, so that an interrupted export can be resumed. The armor
+	// is encrypted, so it can be kept on disk.
+	keyStagingExt = ".partial"
+
+	minChunkSize = 1024
+	// maxKeyFileSize bounds the size of the key files read by import, which
+	// are otherwise likely the wrong files.
+	maxKeyFileSize = 64 << 20
+	// progressMinSize is the size from which transfers report their progress.
+	progressMinSize = 1 << 20
+)
+
+// KeyExportPart is a part of a key exported with --chunk-size.
+type KeyExportPart struct {
+	// Name is the file name of the part, in the directory of the manifest.
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// SHA256 is the hex encoded SHA-256 of the part file.
+	SHA256 string `json:"sha256"`
+}
+
+// KeyExportManifest describes an armored key exported in parts with
+// --chunk-size. It is written once all the parts are, so that a key is
+// completely exported when its manifest exists.
+type KeyExportManifest struct {
+	ManifestVersion int `json:"manifest_version"`
+	// Size and SHA256 are the size and the hex encoded SHA-256 of the
+	// armored key, i.e. of the concatenated parts.
+	Size   int64           `json:"size"`
+	SHA256 string          `json:"sha256"`
+	Parts  []KeyExportPart `json:"parts"`
+}
+
+func addExportFileFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagOutputFile, "", "Write the armored key to this file instead of stdout")
+	cmd.Flags().String(flagChunkSize, "", "Split the key written to --output-file in parts of this size, e.g. 512KiB, verified by a <output-file>.parts.json manifest")
+	cmd.Flags().Bool(flagResume, false, "Resume an interrupted chunked export of the same --output-file, rewriting only the missing or corrupt parts")
+}
+
+// keyPartPath returns the path of the i-th part of an exported key, e.g.
+// validator.asc.part000 for validator.asc.
+func keyPartPath(outputFile string, i int) string {
+	return fmt.Sprintf("%s.part%03d", outputFile, i)
+}
+
+// keyPartsPath returns the path of the manifest of the parts of an exported
+// key, e.g. validator.asc.parts.json for validator.asc.
+func keyPartsPath(outputFile string) string {
+	return outputFile + keyPartsExt
+}
+
+// parseChunkSize parses a size such as 512KiB or 1MB.
+func parseChunkSize(s string) (int64, error) {
+	size, err := humanize.ParseBytes(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --%s %q: %w", flagChunkSize, s, err)
+	}
+	if size < minChunkSize || size > maxKeyFileSize {
+		return 0, fmt.Errorf("invalid --%s %q: must be between %s and %s", flagChunkSize, s,
+			humanize.IBytes(minChunkSize), humanize.IBytes(maxKeyFileSize))
+	}
+	return int64(size), nil
+}
+
+// exportFileOptions are the options of an export to a file.
+type exportFileOptions struct {
+	outputFile string
+	chunkSize  int64
+	resume     bool
+}
+
+// getExportFileOptions returns the --output-file options of the export
+// command, and whether the key is exported to a file.
+func getExportFileOptions(cmd *cobra.Command) (exportFileOptions, bool, error) {
+	outputFile, _ := cmd.Flags().GetString(flagOutputFile)
+	chunkSize, _ := cmd.Flags().GetString(flagChunkSize)
+	resume, _ := cmd.Flags().GetBool(flagResume)
+
+	switch {
+	case outputFile == "" && (chunkSize != "" || resume):
+		return exportFileOptions{}, false, fmt.Errorf("--%s and --%s require --%s", flagChunkSize, flagResume, flagOutputFile)
+	case resume && chunkSize == "":
+		return exportFileOptions{}, false, fmt.Errorf("--%s requires --%s", flagResume, flagChunkSize)
+	case outputFile == "":
+		return exportFileOptions{}, false, nil
+	}
+
+	opts := exportFileOptions{outputFile: outputFile, resume: resume}
+	if chunkSize != "" {
+		size, err := parseChunkSize(chunkSize)
+		if err != nil {
+			return exportFileOptions{}, false, err
+		}
+		opts.chunkSize = size
+	}
+	return opts, true, nil
+}
+
+// stagedExport returns the path of the armored key staged by an interrupted
+// chunked export, or "" if there is none to resume.
+func (o exportFileOptions) stagedExport() string {
+	if !o.resume {
+		return ""
+	}
+	staged := o.outputFile + keyStagingExt
+	if _, err := os.Stat(staged); err != nil {
+		return ""
+	}
+	return staged
+}
+
+// writeKeyExport writes an armored key to the output file, or stages it and
+// writes it in parts with a manifest when the chunk size is set. It returns
+// the path of the file to import.
+func writeKeyExport(opts exportFileOptions, armored string, progress io.Writer) (string, error) {
+	if opts.chunkSize == 0 {
+		err := writeFileAtomic(opts.outputFile, int64(len(armored)), strings.NewReader(armored), progress)
+		if err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", opts.outputFile, err)
+		}
+		return opts.outputFile, nil
+	}
+
+	// the manifest of a previous export would describe other parts
+	if err := os.Remove(keyPartsPath(opts.outputFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	staged := opts.outputFile + keyStagingExt
+	if err := writeFileAtomic(staged, int64(len(armored)), strings.NewReader(armored), io.Discard); err != nil {
+		return "", fmt.Errorf("failed to stage the export: %w", err)
+	}
+	return writeKeyParts(opts, staged, progress)
+}
+
+// writeKeyParts cuts the staged armored key in parts, skipping the parts an
+// interrupted export already wrote when resuming, and writes their manifest.
+// The staged key is removed once the export is complete.
+func writeKeyParts(opts exportFileOptions, staged string, progress io.Writer) (string, error) {
+	f, err := os.Open(staged)
+	if err != nil {
+		return "", fmt.Errorf("failed to open the staged export: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	size := info.Size()
+
+	bar := newProgressBar(progress, "Writing "+filepath.Base(opts.outputFile), size)
+	manifest := KeyExportManifest{ManifestVersion: keyPartsVersion, Size: size}
+	total := sha256.New()
+	for i, off := 0, int64(0); off < size; i, off = i+1, off+opts.chunkSize {
+		n := opts.chunkSize
+		if off+n > size {
+			n = size - off
+		}
+		path := keyPartPath(opts.outputFile, i)
+
+		sum, err := hashReader(io.TeeReader(io.NewSectionReader(f, off, n), total))
+		if err != nil {
+			return "", err
+		}
+		part := KeyExportPart{Name: filepath.Base(path), Size: n, SHA256: sum}
+		manifest.Parts = append(manifest.Parts, part)
+
+		if opts.resume && checkKeyPart(path, part) == nil {
+			bar.add(n)
+			continue
+		}
+		if err := writeFileAtomic(path, n, io.NewSectionReader(f, off, n), bar); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	bar.finish()
+	manifest.SHA256 = hex.EncodeToString(total.Sum(nil))
+
+	// remove the parts left by an export with a smaller chunk size
+	for i := len(manifest.Parts); ; i++ {
+		if err := os.Remove(keyPartPath(opts.outputFile, i)); err != nil {
+			break
+		}
+	}
+
+	bz, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := keyPartsPath(opts.outputFile)
+	if err := writeFileAtomic(path, int64(len(bz)), bytes.NewReader(bz), io.Discard); err != nil {
+		return "", fmt.Errorf("failed to write the parts manifest: %w", err)
+	}
+
+	f.Close()
+	if err := os.Remove(staged); err != nil {
+		return "", fmt.Errorf("failed to remove the staged export: %w", err)
+	}
+	return path, nil
+}
+
+// readKeyFile reads an armored key file, or the parts of a key exported with
+// --chunk-size when path is their manifest, reporting the progress of large
+// files to progress.
+func readKeyFile(path string, progress io.Writer) (string, error) {
+	if strings.HasSuffix(path, keyPartsExt) {
+		return readKeyParts(path, progress)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > maxKeyFileSize {
+		return "", fmt.Errorf("%s is not a key file, its size exceeds %s", path, humanize.IBytes(maxKeyFileSize))
+	}
+
+	var sb strings.Builder
+	sb.Grow(int(info.Size()))
+	bar := newProgressBar(progress, "Reading "+filepath.Base(path), info.Size())
+	if _, err := io.Copy(io.MultiWriter(&sb, bar), io.LimitReader(f, maxKeyFileSize)); err != nil {
+		return "", err
+	}
+	bar.finish()
+	return sb.String(), nil
+}
+
+// readKeyParts reads the parts of an exported key, verified against their
+// manifest. All the missing or corrupt parts are reported at once, so that
+// only they need to be copied again.
+func readKeyParts(manifestPath string, progress io.Writer) (string, error) {
+	bz, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read parts manifest: %w", err)
+	}
+	var manifest KeyExportManifest
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&manifest); err != nil {
+		return "", fmt.Errorf("invalid parts manifest: %w", err)
+	}
+	if manifest.ManifestVersion != keyPartsVersion {
+		return "", fmt.Errorf("unsupported parts manifest version %d, expected %d", manifest.ManifestVersion, keyPartsVersion)
+	}
+	if len(manifest.Parts) == 0 || manifest.Size > maxKeyFileSize {
+		return "", errors.New("invalid parts manifest: no parts, or too large")
+	}
+
+	var (
+		sb    strings.Builder
+		bad   []string
+		total = sha256.New()
+		bar   = newProgressBar(progress, "Reading "+filepath.Base(manifestPath), manifest.Size)
+	)
+	for _, part := range manifest.Parts {
+		if part.Name != filepath.Base(part.Name) {
+			return "", fmt.Errorf("invalid parts manifest: part name %q is not a file name", part.Name)
+		}
+		path := filepath.Join(filepath.Dir(manifestPath), part.Name)
+		if err := checkKeyPart(path, part); err != nil {
+			bad = append(bad, err.Error())
+			continue
+		}
+		if len(bad) > 0 {
+			continue
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(io.MultiWriter(&sb, total, bar), f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(bad) > 0 {
+		return "", fmt.Errorf("copy these parts again and retry:\n  %s", strings.Join(bad, "\n  "))
+	}
+	bar.finish()
+
+	if int64(sb.Len()) != manifest.Size || hex.EncodeToString(total.Sum(nil)) != manifest.SHA256 {
+		return "", errors.New("the parts don't match the checksum of the exported key")
+	}
+	return sb.String(), nil
+}
+
+// checkKeyPart checks the part file has the size and SHA-256 of the part.
+func checkKeyPart(path string, part KeyExportPart) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s: missing", part.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", part.Name, err)
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err != nil || info.Size() != part.Size {
+		return fmt.Errorf("%s: expected %d bytes", part.Name, part.Size)
+	}
+	sum, err := hashReader(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", part.Name, err)
+	}
+	if sum != part.SHA256 {
+		return fmt.Errorf("%s: checksum mismatch", part.Name)
+	}
+	return nil
+}
+
+func hashReader(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeFileAtomic streams size bytes of r to a temporary file, synced to disk,
+// which is renamed to path, so that files are never partially written.
+func writeFileAtomic(path string, size int64, r io.Reader, progress io.Writer) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return err
+	}
+	bar, ok := progress.(*progressBar)
+	if !ok {
+		bar = newProgressBar(progress, "Writing "+filepath.Base(path), size)
+		defer bar.finish()
+	}
+	if _, err := io.Copy(io.MultiWriter(f, bar), io.LimitReader(r, size)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// progressBar reports the progress of a transfer of total bytes to w, if it
+// is large enough to be worth it.
+type progressBar struct {
+	w       io.Writer
+	label   string
+	total   int64
+	done    int64
+	percent int64
+}
+
+func newProgressBar(w io.Writer, label string, total int64) *progressBar {
+	if total < progressMinSize {
+		w = io.Discard
+	}
+	return &progressBar{w: w, label: label, total: total, percent: -1}
+}
+
+// Write counts the bytes written, so that the bar can be the writer of a copy.
+func (b *progressBar) Write(p []byte) (int, error) {
+	b.add(int64(len(p)))
+	return len(p), nil
+}
+
+func (b *progressBar) add(n int64) {
+	b.done += n
+	percent := int64(100)
+	if b.total > 0 {
+		percent = b.done * 100 / b.total
+	}
+	if percent == b.percent {
+		return
+	}
+	b.percent = percent
+
+	const width = 30
+	filled := int(percent * width / 100)
+	fmt.Fprintf(b.w, "\r%s [%s%s] %s / %s (%d%%)", b.label,
+		strings.Repeat("=", filled), strings.Repeat(" ", width-filled),
+		humanize.IBytes(uint64(b.done)), humanize.IBytes(uint64(b.total)), percent)
+}
+
+// finish ends the line of the bar, once the transfer is complete.
+func (b *progressBar) finish() {
+	if b.percent >= 0 {
+		fmt.Fprintln(b.w)
+		b.percent = -1
+	}
+}
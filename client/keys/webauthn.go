@@ -0,0 +1,356 @@
+package keys
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+)
+
+const (
+	flagWebAuthn           = "webauthn"
+	flagWebAuthnCredential = "webauthn-credential"
+	flagWebAuthnTimeout    = "webauthn-timeout"
+
+	// webAuthnRPID is the relying party of the credentials of the keys
+	// commands, whose ceremonies are served on localhost.
+	webAuthnRPID           = "localhost"
+	defaultWebAuthnTimeout = 2 * time.Minute
+
+	webAuthnKindCreate = "webauthn.create"
+	webAuthnKindGet    = "webauthn.get"
+
+	// webAuthnFlagUserPresent is the UP flag of the authenticator data.
+	webAuthnFlagUserPresent = 0x01
+)
+
+// addWebAuthnFlags adds the flags wrapping exported keys, or unwrapping
+// imported keys, with a WebAuthn credential.
+func addWebAuthnFlags(cmd *cobra.Command, export bool) {
+	if export {
+		cmd.Flags().Bool(flagWebAuthn, false, "Wrap the exported key with a WebAuthn credential, so that importing it requires a user presence check by the authenticator")
+		cmd.Flags().String(flagWebAuthnCredential, "", "Base64url ID of the WebAuthn credential to wrap the key with; a new credential is registered if omitted")
+	} else {
+		cmd.Flags().Bool(flagWebAuthn, false, "Unwrap a key exported with --webauthn with its WebAuthn credential")
+	}
+	cmd.Flags().Duration(flagWebAuthnTimeout, defaultWebAuthnTimeout, "Time to complete the WebAuthn ceremony in the browser")
+}
+
+// wrapKeyWebAuthn wraps an armored key with the credential of the
+// --webauthn-credential flag, or a new credential of the key, if --webauthn
+// is set.
+func wrapKeyWebAuthn(cmd *cobra.Command, uid, armored string) (string, error) {
+	if wrap, _ := cmd.Flags().GetBool(flagWebAuthn); !wrap {
+		return armored, nil
+	}
+
+	timeout, _ := cmd.Flags().GetDuration(flagWebAuthnTimeout)
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+	authenticator := newBrowserAuthenticator(cmd.ErrOrStderr())
+
+	credential := crypto.WebAuthnCredential{RPID: webAuthnRPID}
+	if id, _ := cmd.Flags().GetString(flagWebAuthnCredential); id != "" {
+		bz, err := base64.RawURLEncoding.DecodeString(id)
+		if err != nil {
+			return "", fmt.Errorf("invalid --%s: %w", flagWebAuthnCredential, err)
+		}
+		credential.ID = bz
+	} else {
+		cmd.PrintErrln("Registering a WebAuthn credential for the key...")
+		bz, err := authenticator.Register(ctx, webAuthnRPID, uid)
+		if err != nil {
+			return "", fmt.Errorf("failed to register WebAuthn credential: %w", err)
+		}
+		credential.ID = bz
+		cmd.PrintErrf("Registered WebAuthn credential %s\n", base64.RawURLEncoding.EncodeToString(bz))
+	}
+
+	cmd.PrintErrln("Confirm your presence to the authenticator to wrap the key...")
+	return crypto.WrapArmorPrivKeyWebAuthn(ctx, armored, authenticator, credential)
+}
+
+// unwrapKeyWebAuthn unwraps an armored key with its WebAuthn credential if
+// --webauthn is set. A wrapped key is refused without it.
+func unwrapKeyWebAuthn(cmd *cobra.Command, armored string) (string, bool, error) {
+	_, wrapped := crypto.WebAuthnCredentialOf(armored)
+	unwrap, _ := cmd.Flags().GetBool(flagWebAuthn)
+	switch {
+	case wrapped && !unwrap:
+		return "", false, fmt.Errorf("the key is wrapped with a WebAuthn credential, import it with --%s", flagWebAuthn)
+	case !wrapped && unwrap:
+		return "", false, errors.New("the key isn't wrapped with a WebAuthn credential")
+	case !wrapped:
+		return armored, false, nil
+	}
+
+	timeout, _ := cmd.Flags().GetDuration(flagWebAuthnTimeout)
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+
+	cmd.PrintErrln("Confirm your presence to the authenticator to unwrap the key...")
+	unwrapped, err := crypto.UnwrapArmorPrivKeyWebAuthn(ctx, armored, newBrowserAuthenticator(cmd.ErrOrStderr()))
+	return unwrapped, true, err
+}
+
+// browserAuthenticator implements crypto.WebAuthnAuthenticator with the
+// platform authenticator, e.g. Touch ID, Windows Hello or a security key,
+// through a page served on localhost which runs the ceremonies in the browser
+// and posts their results back.
+type browserAuthenticator struct {
+	// prompt asks the user to open the page of a ceremony.
+	prompt func(url string)
+}
+
+func newBrowserAuthenticator(w io.Writer) browserAuthenticator {
+	return browserAuthenticator{prompt: func(url string) {
+		fmt.Fprintf(w, "Open %s in a browser to continue\n", url)
+	}}
+}
+
+// webAuthnOptions are the options of a ceremony fetched by the page.
+type webAuthnOptions struct {
+	Kind         string `json:"kind"`
+	RPID         string `json:"rp_id"`
+	Challenge    string `json:"challenge"`
+	UserID       string `json:"user_id,omitempty"`
+	UserName     string `json:"user_name,omitempty"`
+	CredentialID string `json:"credential_id,omitempty"`
+	Salt         string `json:"salt,omitempty"`
+}
+
+// webAuthnResult is the result of a ceremony posted by the page, with the
+// binary fields base64url encoded.
+type webAuthnResult struct {
+	Error             string `json:"error,omitempty"`
+	CredentialID      string `json:"credential_id"`
+	ClientDataJSON    string `json:"client_data_json"`
+	AuthenticatorData string `json:"authenticator_data,omitempty"`
+	PRFEnabled        bool   `json:"prf_enabled,omitempty"`
+	PRF               string `json:"prf,omitempty"`
+}
+
+func (a browserAuthenticator) Register(ctx context.Context, rpID, userName string) ([]byte, error) {
+	userID := make([]byte, 16)
+	if _, err := rand.Read(userID); err != nil {
+		return nil, err
+	}
+	res, err := a.ceremony(ctx, webAuthnOptions{
+		Kind:     webAuthnKindCreate,
+		RPID:     rpID,
+		UserID:   base64.RawURLEncoding.EncodeToString(userID),
+		UserName: userName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !res.PRFEnabled {
+		return nil, errors.New("the authenticator doesn't support the WebAuthn PRF extension")
+	}
+	return base64.RawURLEncoding.DecodeString(res.CredentialID)
+}
+
+func (a browserAuthenticator) PRF(ctx context.Context, rpID string, credentialID, salt []byte) ([]byte, error) {
+	res, err := a.ceremony(ctx, webAuthnOptions{
+		Kind:         webAuthnKindGet,
+		RPID:         rpID,
+		CredentialID: base64.RawURLEncoding.EncodeToString(credentialID),
+		Salt:         base64.RawURLEncoding.EncodeToString(salt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.PRF == "" {
+		return nil, errors.New("the authenticator returned no PRF output")
+	}
+	return base64.RawURLEncoding.DecodeString(res.PRF)
+}
+
+// ceremony serves the page of a ceremony until its result is posted, or ctx
+// is done.
+func (a browserAuthenticator) ceremony(ctx context.Context, opts webAuthnOptions) (webAuthnResult, error) {
+	challenge := make([]byte, 32)
+	token := make([]byte, 16)
+	if _, err := rand.Read(challenge); err != nil {
+		return webAuthnResult{}, err
+	}
+	if _, err := rand.Read(token); err != nil {
+		return webAuthnResult{}, err
+	}
+	opts.Challenge = base64.RawURLEncoding.EncodeToString(challenge)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return webAuthnResult{}, err
+	}
+	// the origin must be localhost, the relying party of the credentials
+	origin := fmt.Sprintf("http://localhost:%d", l.Addr().(*net.TCPAddr).Port)
+	prefix := "/" + base64.RawURLEncoding.EncodeToString(token) + "/"
+
+	results := make(chan webAuthnResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = webAuthnPage.Execute(w, prefix)
+	})
+	mux.HandleFunc(prefix+"options", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(opts)
+	})
+	mux.HandleFunc(prefix+"result", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var res webAuthnResult
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&res); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if res.Error == "" {
+			if err := verifyWebAuthnResult(opts, origin, res); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		select {
+		case results <- res:
+		default:
+			http.Error(w, "ceremony already completed", http.StatusConflict)
+			return
+		}
+		fmt.Fprintln(w, "You can close this page and return to the terminal.")
+	})
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go srv.Serve(l) //nolint:errcheck // closed below
+	defer srv.Close()
+
+	a.prompt(origin + prefix)
+	select {
+	case res := <-results:
+		if res.Error != "" {
+			return webAuthnResult{}, fmt.Errorf("browser: %s", res.Error)
+		}
+		return res, nil
+	case <-ctx.Done():
+		return webAuthnResult{}, fmt.Errorf("WebAuthn ceremony not completed: %w", ctx.Err())
+	}
+}
+
+// verifyWebAuthnResult checks that the client data of a ceremony result
+// matches the ceremony, and that the authenticator checked the presence of
+// the user for an assertion.
+func verifyWebAuthnResult(opts webAuthnOptions, origin string, res webAuthnResult) error {
+	bz, err := base64.RawURLEncoding.DecodeString(res.ClientDataJSON)
+	if err != nil {
+		return fmt.Errorf("invalid client data: %w", err)
+	}
+	var clientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+	if err := json.Unmarshal(bz, &clientData); err != nil {
+		return fmt.Errorf("invalid client data: %w", err)
+	}
+	switch {
+	case clientData.Type != opts.Kind:
+		return fmt.Errorf("unexpected ceremony %q, expected %q", clientData.Type, opts.Kind)
+	case clientData.Challenge != opts.Challenge:
+		return errors.New("challenge mismatch")
+	case clientData.Origin != origin:
+		return fmt.Errorf("unexpected origin %q", clientData.Origin)
+	}
+	if opts.Kind != webAuthnKindGet {
+		return nil
+	}
+
+	if res.CredentialID != opts.CredentialID {
+		return errors.New("assertion of another credential")
+	}
+	authData, err := base64.RawURLEncoding.DecodeString(res.AuthenticatorData)
+	if err != nil || len(authData) < 37 {
+		return errors.New("invalid authenticator data")
+	}
+	rpIDHash := sha256.Sum256([]byte(opts.RPID))
+	if !bytes.Equal(authData[:32], rpIDHash[:]) {
+		return errors.New("authenticator data of another relying party")
+	}
+	if authData[32]&webAuthnFlagUserPresent == 0 {
+		return errors.New("the authenticator didn't check the presence of the user")
+	}
+	return nil
+}
+
+// webAuthnPage runs the ceremony of its options with the browser WebAuthn
+// API, and posts its result.
+var webAuthnPage = template.Must(template.New("webauthn").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Baron Chain keys</title></head>
+<body>
+<p id="status">Follow the instructions of your authenticator...</p>
+<script>
+const prefix = {{.}};
+const dec = (s) => Uint8Array.from(atob(s.replace(/-/g, "+").replace(/_/g, "/")), (c) => c.charCodeAt(0));
+const enc = (b) => btoa(String.fromCharCode(...new Uint8Array(b))).replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+
+async function run() {
+  const opts = await (await fetch(prefix + "options")).json();
+  let result;
+  try {
+    if (opts.kind === "webauthn.create") {
+      const cred = await navigator.credentials.create({publicKey: {
+        rp: {id: opts.rp_id, name: "Baron Chain keys"},
+        user: {id: dec(opts.user_id), name: opts.user_name, displayName: opts.user_name},
+        challenge: dec(opts.challenge),
+        pubKeyCredParams: [{type: "public-key", alg: -7}, {type: "public-key", alg: -257}],
+        authenticatorSelection: {userVerification: "preferred", residentKey: "discouraged"},
+        extensions: {prf: {}},
+      }});
+      const ext = cred.getClientExtensionResults();
+      result = {
+        credential_id: enc(cred.rawId),
+        client_data_json: enc(cred.response.clientDataJSON),
+        prf_enabled: !!(ext.prf && ext.prf.enabled),
+      };
+    } else {
+      const cred = await navigator.credentials.get({publicKey: {
+        rpId: opts.rp_id,
+        challenge: dec(opts.challenge),
+        allowCredentials: [{type: "public-key", id: dec(opts.credential_id)}],
+        userVerification: "preferred",
+        extensions: {prf: {eval: {first: dec(opts.salt)}}},
+      }});
+      const ext = cred.getClientExtensionResults();
+      result = {
+        credential_id: enc(cred.rawId),
+        client_data_json: enc(cred.response.clientDataJSON),
+        authenticator_data: enc(cred.response.authenticatorData),
+        prf: ext.prf && ext.prf.results ? enc(ext.prf.results.first) : "",
+      };
+    }
+  } catch (e) {
+    result = {error: String(e)};
+  }
+  const resp = await fetch(prefix + "result", {method: "POST", body: JSON.stringify(result)});
+  document.getElementById("status").textContent = await resp.text();
+}
+run();
+</script>
+</body>
+</html>
+`))
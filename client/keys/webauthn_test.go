@@ -0,0 +1,129 @@
+package keys
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBrowser runs the ceremonies of the pages it is prompted to open like a
+// browser with a platform authenticator, whose PRF output is the hash of the
+// salt. The result is altered by tamper before being posted.
+func fakeBrowser(t *testing.T, tamper func(res *webAuthnResult, authData []byte)) (browserAuthenticator, chan string) {
+	responses := make(chan string, 1)
+	return browserAuthenticator{prompt: func(pageURL string) {
+		go func() {
+			resp, err := http.Get(pageURL + "options")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			var opts webAuthnOptions
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&opts))
+
+			u, err := url.Parse(pageURL)
+			require.NoError(t, err)
+			clientData, err := json.Marshal(map[string]string{
+				"type":      opts.Kind,
+				"challenge": opts.Challenge,
+				"origin":    u.Scheme + "://" + u.Host,
+			})
+			require.NoError(t, err)
+
+			res := webAuthnResult{
+				CredentialID:   "Y3JlZGVudGlhbA",
+				ClientDataJSON: base64.RawURLEncoding.EncodeToString(clientData),
+				PRFEnabled:     true,
+			}
+			rpIDHash := sha256.Sum256([]byte(opts.RPID))
+			authData := append(rpIDHash[:], webAuthnFlagUserPresent, 0, 0, 0, 1)
+			if opts.Kind == webAuthnKindGet {
+				res.CredentialID = opts.CredentialID
+				salt, err := base64.RawURLEncoding.DecodeString(opts.Salt)
+				require.NoError(t, err)
+				prf := sha256.Sum256(salt)
+				res.PRF = base64.RawURLEncoding.EncodeToString(prf[:])
+			}
+			if tamper != nil {
+				tamper(&res, authData)
+			}
+			res.AuthenticatorData = base64.RawURLEncoding.EncodeToString(authData)
+
+			body, err := json.Marshal(res)
+			require.NoError(t, err)
+			resp, err = http.Post(pageURL+"result", "application/json", bytes.NewReader(body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			msg, _ := io.ReadAll(resp.Body)
+			responses <- strings.TrimSpace(string(msg))
+		}()
+	}}, responses
+}
+
+func TestBrowserAuthenticator(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	authenticator, responses := fakeBrowser(t, nil)
+	id, err := authenticator.Register(ctx, webAuthnRPID, "validator")
+	require.NoError(t, err)
+	require.Equal(t, []byte("credential"), id)
+	require.Contains(t, <-responses, "close this page")
+
+	salt := []byte("salt")
+	prf, err := authenticator.PRF(ctx, webAuthnRPID, id, salt)
+	require.NoError(t, err)
+	want := sha256.Sum256(salt)
+	require.Equal(t, want[:], prf)
+	<-responses
+
+	// assertions without a user presence check are rejected
+	authenticator, responses = fakeBrowser(t, func(_ *webAuthnResult, authData []byte) {
+		authData[32] = 0
+	})
+	shortCtx, shortCancel := context.WithTimeout(ctx, time.Second)
+	defer shortCancel()
+	_, err = authenticator.PRF(shortCtx, webAuthnRPID, id, salt)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Contains(t, <-responses, "didn't check the presence of the user")
+
+	// browser errors are reported
+	authenticator, responses = fakeBrowser(t, func(res *webAuthnResult, _ []byte) {
+		res.Error = "NotAllowedError: The operation either timed out or was not allowed."
+	})
+	_, err = authenticator.PRF(ctx, webAuthnRPID, id, salt)
+	require.ErrorContains(t, err, "NotAllowedError")
+	<-responses
+}
+
+func TestVerifyWebAuthnResult(t *testing.T) {
+	opts := webAuthnOptions{Kind: webAuthnKindCreate, RPID: webAuthnRPID, Challenge: "abc"}
+	clientData := func(typ, challenge, origin string) string {
+		bz, _ := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": origin})
+		return base64.RawURLEncoding.EncodeToString(bz)
+	}
+	origin := "http://localhost:1234"
+
+	require.NoError(t, verifyWebAuthnResult(opts, origin, webAuthnResult{ClientDataJSON: clientData(webAuthnKindCreate, "abc", origin)}))
+	require.ErrorContains(t, verifyWebAuthnResult(opts, origin, webAuthnResult{ClientDataJSON: clientData(webAuthnKindGet, "abc", origin)}), "unexpected ceremony")
+	require.ErrorContains(t, verifyWebAuthnResult(opts, origin, webAuthnResult{ClientDataJSON: clientData(webAuthnKindCreate, "xyz", origin)}), "challenge mismatch")
+	require.ErrorContains(t, verifyWebAuthnResult(opts, origin, webAuthnResult{ClientDataJSON: clientData(webAuthnKindCreate, "abc", "https://evil.example")}), "unexpected origin")
+
+	opts.Kind = webAuthnKindGet
+	opts.CredentialID = "Y3JlZA"
+	otherRP := sha256.Sum256([]byte("evil.example"))
+	res := webAuthnResult{
+		CredentialID:      "Y3JlZA",
+		ClientDataJSON:    clientData(webAuthnKindGet, "abc", origin),
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(append(otherRP[:], webAuthnFlagUserPresent, 0, 0, 0, 0)),
+	}
+	require.ErrorContains(t, verifyWebAuthnResult(opts, origin, res), "another relying party")
+}
@@ -0,0 +1,301 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	bankv1beta1 "cosmossdk.io/api/cosmos/bank/v1beta1"
+	basev1beta1 "cosmossdk.io/api/cosmos/base/v1beta1"
+	"cosmossdk.io/core/coins"
+	"cosmossdk.io/math"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+	sdk "github.com/baron-chain/cosmos-bc-47/types"
+	"github.com/baron-chain/cosmos-bc-47/types/query"
+	banktypes "github.com/baron-chain/cosmos-bc-47/x/bank/types"
+)
+
+const flagDenom = "denom"
+
+// CoinOutput is an amount of a denom, with its human-readable rendering in
+// the display denom of its metadata.
+type CoinOutput struct {
+	Denom   string   `json:"denom"`
+	Amount  math.Int `json:"amount"`
+	Display string   `json:"display"`
+}
+
+// CoinsOutput is the result of the balances and supply queries.
+type CoinsOutput struct {
+	Address string       `json:"address,omitempty"`
+	Coins   []CoinOutput `json:"coins"`
+}
+
+func (co CoinsOutput) String() string {
+	var b strings.Builder
+
+	if co.Address != "" {
+		fmt.Fprintf(&b, "Address: %s\n", co.Address)
+	}
+	if len(co.Coins) == 0 {
+		fmt.Fprintf(&b, "No coins\n")
+		return b.String()
+	}
+
+	for _, coin := range co.Coins {
+		fmt.Fprintf(&b, "  %-30s (%s%s)\n", coin.Display, coin.Amount, coin.Denom)
+	}
+
+	return b.String()
+}
+
+// BalancesCommand returns the command querying the balances of an account,
+// rendered in the display denoms of the bank metadata.
+func BalancesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "balances <address>",
+		Short: "Query the balances of a Baron Chain account",
+		Long: `Query all the balances of an account from the bank module. Amounts are printed
+in the display denom of their bank metadata, e.g. 1.5 baron for 1500000ubaron, next
+to their base amount. Denoms without metadata are printed as is.`,
+		Example: `$ barond query balances baron1...
+$ barond query balances baron1... --denom ubaron --height 1200`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get query context: %w", err)
+			}
+
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid address %q: %w", args[0], err)
+			}
+
+			denom, _ := cmd.Flags().GetString(flagDenom)
+			result, err := QueryBalances(cmd.Context(), clientCtx, addr, denom)
+			if err != nil {
+				return err
+			}
+
+			return printObject(cmd, clientCtx, result)
+		},
+	}
+
+	addCoinsQueryFlags(cmd, "Only query the balance of this denom")
+
+	return cmd
+}
+
+// SupplyCommand returns the command querying the total supply, rendered in
+// the display denoms of the bank metadata.
+func SupplyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "supply",
+		Short: "Query the total supply of Baron Chain coins",
+		Long: `Query the total supply of all coins from the bank module. Amounts are printed
+in the display denom of their bank metadata, next to their base amount. Denoms
+without metadata are printed as is.`,
+		Example: `$ barond query supply
+$ barond query supply --denom ubaron -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get query context: %w", err)
+			}
+
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
+			denom, _ := cmd.Flags().GetString(flagDenom)
+			result, err := QuerySupply(cmd.Context(), clientCtx, denom)
+			if err != nil {
+				return err
+			}
+
+			return printObject(cmd, clientCtx, result)
+		},
+	}
+
+	addCoinsQueryFlags(cmd, "Only query the supply of this denom")
+
+	return cmd
+}
+
+func addCoinsQueryFlags(cmd *cobra.Command, denomUsage string) {
+	cmd.Flags().String(flags.FlagNode, defaultNodeEndpoint, "Baron Chain node RPC endpoint")
+	cmd.Flags().StringP(flags.FlagOutput, "o", "text", "Output format (text|json)")
+	cmd.Flags().Int64(flags.FlagHeight, 0, "Height to query the state at (the node must not have pruned it)")
+	cmd.Flags().String(flagDenom, "", denomUsage)
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
+}
+
+// QueryBalances returns the balances of the account at the client context
+// height, or only its balance of denom if not empty.
+func QueryBalances(ctx context.Context, clientCtx client.Context, addr sdk.AccAddress, denom string) (CoinsOutput, error) {
+	queryClient := banktypes.NewQueryClient(clientCtx)
+
+	var balances sdk.Coins
+	if denom != "" {
+		res, err := queryClient.Balance(ctx, &banktypes.QueryBalanceRequest{Address: addr.String(), Denom: denom})
+		if err != nil {
+			return CoinsOutput{}, wrapRPCError("query balance", err)
+		}
+		if res.Balance != nil {
+			balances = sdk.NewCoins(*res.Balance)
+		}
+	} else {
+		var nextKey []byte
+		for {
+			res, err := queryClient.AllBalances(ctx, &banktypes.QueryAllBalancesRequest{
+				Address:    addr.String(),
+				Pagination: &query.PageRequest{Key: nextKey},
+			})
+			if err != nil {
+				return CoinsOutput{}, wrapRPCError("query balances", err)
+			}
+
+			balances = append(balances, res.Balances...)
+			if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+				break
+			}
+			nextKey = res.Pagination.NextKey
+		}
+	}
+
+	coinsOut, err := queryCoinOutputs(ctx, queryClient, balances)
+	if err != nil {
+		return CoinsOutput{}, err
+	}
+	return CoinsOutput{Address: addr.String(), Coins: coinsOut}, nil
+}
+
+// QuerySupply returns the total supply at the client context height, or only
+// the supply of denom if not empty.
+func QuerySupply(ctx context.Context, clientCtx client.Context, denom string) (CoinsOutput, error) {
+	queryClient := banktypes.NewQueryClient(clientCtx)
+
+	var supply sdk.Coins
+	if denom != "" {
+		res, err := queryClient.SupplyOf(ctx, &banktypes.QuerySupplyOfRequest{Denom: denom})
+		if err != nil {
+			return CoinsOutput{}, wrapRPCError("query supply", err)
+		}
+		supply = sdk.NewCoins(res.Amount)
+	} else {
+		var nextKey []byte
+		for {
+			res, err := queryClient.TotalSupply(ctx, &banktypes.QueryTotalSupplyRequest{
+				Pagination: &query.PageRequest{Key: nextKey},
+			})
+			if err != nil {
+				return CoinsOutput{}, wrapRPCError("query supply", err)
+			}
+
+			supply = append(supply, res.Supply...)
+			if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+				break
+			}
+			nextKey = res.Pagination.NextKey
+		}
+	}
+
+	coinsOut, err := queryCoinOutputs(ctx, queryClient, supply)
+	if err != nil {
+		return CoinsOutput{}, err
+	}
+	return CoinsOutput{Coins: coinsOut}, nil
+}
+
+// queryCoinOutputs renders the coins with the denom metadata of the bank
+// module. The metadata of all denoms is queried at once, as chains register
+// few of them.
+func queryCoinOutputs(ctx context.Context, queryClient banktypes.QueryClient, coinList sdk.Coins) ([]CoinOutput, error) {
+	if len(coinList) == 0 {
+		return []CoinOutput{}, nil
+	}
+
+	metadata, err := queryDenomsMetadata(ctx, queryClient)
+	if err != nil {
+		return nil, err
+	}
+	return formatCoinOutputs(coinList, metadata)
+}
+
+// queryDenomsMetadata returns the metadata of all the denoms of the bank
+// module.
+func queryDenomsMetadata(ctx context.Context, queryClient banktypes.QueryClient) ([]banktypes.Metadata, error) {
+	var (
+		metadata []banktypes.Metadata
+		nextKey  []byte
+	)
+	for {
+		res, err := queryClient.DenomsMetadata(ctx, &banktypes.QueryDenomsMetadataRequest{
+			Pagination: &query.PageRequest{Key: nextKey},
+		})
+		if err != nil {
+			return nil, wrapRPCError("query denoms metadata", err)
+		}
+
+		metadata = append(metadata, res.Metadatas...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return metadata, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// formatCoinOutputs renders each coin in the display denom of the metadata
+// having one of its units, with core/coins formatting.
+func formatCoinOutputs(coinList sdk.Coins, metadata []banktypes.Metadata) ([]CoinOutput, error) {
+	byDenom := make(map[string]*bankv1beta1.Metadata)
+	for _, md := range metadata {
+		apiMd := toAPIMetadata(md)
+		byDenom[md.Base] = apiMd
+		for _, unit := range md.DenomUnits {
+			if _, ok := byDenom[unit.Denom]; !ok {
+				byDenom[unit.Denom] = apiMd
+			}
+		}
+	}
+
+	out := make([]CoinOutput, len(coinList))
+	for i, coin := range coinList {
+		display, err := coins.FormatCoins(
+			[]*basev1beta1.Coin{{Denom: coin.Denom, Amount: coin.Amount.String()}},
+			[]*bankv1beta1.Metadata{byDenom[coin.Denom]},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format %s: %w", coin, err)
+		}
+
+		out[i] = CoinOutput{Denom: coin.Denom, Amount: coin.Amount, Display: display}
+	}
+	return out, nil
+}
+
+func toAPIMetadata(md banktypes.Metadata) *bankv1beta1.Metadata {
+	units := make([]*bankv1beta1.DenomUnit, len(md.DenomUnits))
+	for i, unit := range md.DenomUnits {
+		units[i] = &bankv1beta1.DenomUnit{Denom: unit.Denom, Exponent: unit.Exponent, Aliases: unit.Aliases}
+	}
+
+	return &bankv1beta1.Metadata{
+		Description: md.Description,
+		DenomUnits:  units,
+		Base:        md.Base,
+		Display:     md.Display,
+		Name:        md.Name,
+		Symbol:      md.Symbol,
+	}
+}
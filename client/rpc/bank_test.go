@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/baron-chain/cosmos-bc-47/types"
+	banktypes "github.com/baron-chain/cosmos-bc-47/x/bank/types"
+)
+
+func TestFormatCoinOutputs(t *testing.T) {
+	metadata := []banktypes.Metadata{{
+		Base:    "ubaron",
+		Display: "baron",
+		DenomUnits: []*banktypes.DenomUnit{
+			{Denom: "ubaron", Exponent: 0},
+			{Denom: "mbaron", Exponent: 3},
+			{Denom: "baron", Exponent: 6},
+		},
+	}}
+
+	coinList := sdk.NewCoins(
+		sdk.NewInt64Coin("ubaron", 1_500_000),
+		sdk.NewInt64Coin("mbaron", 250),
+		sdk.NewInt64Coin("stake", 1_000_000),
+	)
+
+	out, err := formatCoinOutputs(coinList, metadata)
+	require.NoError(t, err)
+	require.Equal(t, []CoinOutput{
+		{Denom: "mbaron", Amount: sdk.NewInt(250), Display: "0.25 baron"},
+		{Denom: "stake", Amount: sdk.NewInt(1_000_000), Display: "1'000'000 stake"},
+		{Denom: "ubaron", Amount: sdk.NewInt(1_500_000), Display: "1.5 baron"},
+	}, out)
+
+	out, err = formatCoinOutputs(sdk.NewCoins(), metadata)
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
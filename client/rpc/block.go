@@ -29,6 +29,10 @@ func BlockCommand() *cobra.Command {
 				return fmt.Errorf("failed to get query context: %w", err)
 			}
 
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
 			height, err := parseHeight(args)
 			if err != nil {
 				return err
@@ -39,12 +43,14 @@ func BlockCommand() *cobra.Command {
 				return fmt.Errorf("failed to fetch block data: %w", err)
 			}
 
-			return clientCtx.PrintBytes(output)
+			return printJSON(cmd, clientCtx, output)
 		},
 	}
 
 	cmd.Flags().StringP(flagNode, "n", defaultNodeEndpoint, "Baron Chain node to connect to")
 	flags.AddQueryFlagsToCmd(cmd)
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
 	
 	return cmd
 }
@@ -75,7 +81,7 @@ func fetchBlockData(clientCtx client.Context, height *int64) (json.RawMessage, e
 	ctx := context.Background()
 	block, err := node.Block(ctx, height)
 	if err != nil {
-		return nil, err
+		return nil, wrapRPCError("query block", err)
 	}
 
 	return json.Marshal(block)
@@ -90,7 +96,7 @@ func GetChainHeight(clientCtx client.Context) (int64, error) {
 	ctx := context.Background()
 	status, err := node.Status(ctx)
 	if err != nil {
-		return -1, fmt.Errorf("failed to get node status: %w", err)
+		return -1, wrapRPCError("query node status", err)
 	}
 
 	return status.SyncInfo.LatestBlockHeight, nil
@@ -0,0 +1,119 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+
+	rpctypes "github.com/baron-chain/cometbft-bc/rpc/jsonrpc/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrNodeUnreachable is returned when the node RPC endpoint cannot be
+	// connected to.
+	ErrNodeUnreachable = errors.New("node unreachable")
+	// ErrHeightPruned is returned when the node no longer has the data of the
+	// requested height, or never had it because it was state synced.
+	ErrHeightPruned = errors.New("height pruned")
+	// ErrTimeout is returned when the node didn't answer, or the awaited
+	// event didn't happen, in time.
+	ErrTimeout = errors.New("timed out")
+)
+
+// prunedHeightMessages are the CometBFT RPC error messages reporting that
+// the data of a height is not available on the node.
+var prunedHeightMessages = []string{
+	"is not available, lowest height is",
+	"could not find validator set for height",
+	"could not find results for height",
+}
+
+// Error is returned by the RPC query helpers of this package. Kind is one of
+// ErrNodeUnreachable, ErrHeightPruned and ErrTimeout and Err is the cause,
+// both matched by errors.Is and errors.As:
+//
+//	if errors.Is(err, rpc.ErrHeightPruned) {
+//		// query an archive node instead
+//	}
+type Error struct {
+	// Op is the failed operation, e.g. "query validators".
+	Op   string
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("failed to %s: %s: %v", e.Op, e.Kind, e.Err)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of the failure.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// wrapRPCError wraps the error of an RPC operation into an *Error when its
+// failure mode is known, and adds the operation to it otherwise.
+func wrapRPCError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if kind := rpcErrorKind(err); kind != nil {
+		return &Error{Op: op, Kind: kind, Err: err}
+	}
+	return fmt.Errorf("failed to %s: %w", op, err)
+}
+
+// rpcErrorKind classifies err, returning nil when its failure mode is unknown.
+func rpcErrorKind(err error) error {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrTimeout
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return ErrNodeUnreachable
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return ErrNodeUnreachable
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ErrNodeUnreachable
+	}
+
+	// gRPC queries, e.g. of the staking module
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable:
+			return ErrNodeUnreachable
+		case codes.DeadlineExceeded:
+			return ErrTimeout
+		}
+	}
+
+	var rpcErr *rpctypes.RPCError
+	if errors.As(err, &rpcErr) {
+		for _, msg := range prunedHeightMessages {
+			if strings.Contains(rpcErr.Data, msg) {
+				return ErrHeightPruned
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+
+	rpctypes "github.com/baron-chain/cometbft-bc/rpc/jsonrpc/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWrapRPCError(t *testing.T) {
+	refused := &url.Error{Op: "Post", URL: "http://localhost:26657", Err: &net.OpError{
+		Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED,
+	}}
+
+	testCases := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"connection refused", refused, ErrNodeUnreachable},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "node.invalid"}, ErrNodeUnreachable},
+		{"deadline exceeded", fmt.Errorf("post failed: %w", context.DeadlineExceeded), ErrTimeout},
+		{"grpc unavailable", status.Error(codes.Unavailable, "connection closed"), ErrNodeUnreachable},
+		{"grpc deadline", status.Error(codes.DeadlineExceeded, "deadline exceeded"), ErrTimeout},
+		{"pruned block", &rpctypes.RPCError{Code: -32603, Message: "Internal error", Data: "height 5 is not available, lowest height is 100"}, ErrHeightPruned},
+		{"pruned validators", &rpctypes.RPCError{Code: -32603, Message: "Internal error", Data: "could not find validator set for height #5"}, ErrHeightPruned},
+		{"future height", &rpctypes.RPCError{Code: -32603, Message: "Internal error", Data: "height 500 must be less than or equal to the current blockchain height 100"}, nil},
+		{"unknown", errors.New("boom"), nil},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := wrapRPCError("query validators", tc.err)
+			require.ErrorIs(t, err, tc.err)
+			require.Contains(t, err.Error(), "failed to query validators")
+
+			var rpcErr *Error
+			if tc.kind == nil {
+				require.False(t, errors.As(err, &rpcErr))
+				for _, kind := range []error{ErrNodeUnreachable, ErrHeightPruned, ErrTimeout} {
+					require.NotErrorIs(t, err, kind)
+				}
+				return
+			}
+
+			require.ErrorIs(t, err, tc.kind)
+			require.True(t, errors.As(err, &rpcErr))
+			require.Equal(t, "query validators", rpcErr.Op)
+		})
+	}
+
+	require.NoError(t, wrapRPCError("query validators", nil))
+}
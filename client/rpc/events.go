@@ -0,0 +1,155 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	abci "github.com/baron-chain/cometbft-bc/abci/types"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+	"github.com/cosmos/gogoproto/jsonpb"
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/codec"
+	codectypes "github.com/baron-chain/cosmos-bc-47/codec/types"
+)
+
+const flagRaw = "raw"
+
+// addRawFlag adds the --raw flag to a command printing tx results.
+func addRawFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(flagRaw, false, "Print the tx response as returned by the node, without decoding the tx and its events")
+}
+
+// DecodedEvent is an ABCI event. Typed events, whose type is a proto message
+// name, are decoded into their JSON form, other events keep their attributes.
+type DecodedEvent struct {
+	Type       string             `json:"type"`
+	Typed      json.RawMessage    `json:"typed,omitempty"`
+	Attributes []DecodedAttribute `json:"attributes,omitempty"`
+}
+
+// DecodedAttribute is an attribute of an event which is not typed. Display is
+// the rendering of the coins of amount attributes in their display denoms,
+// set by the txs search.
+type DecodedAttribute struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// DecodedTxResponse is a tx result with its tx and events decoded into JSON,
+// resolving the Any-packed messages with the interface registry.
+type DecodedTxResponse struct {
+	Height    int64           `json:"height,string"`
+	TxHash    string          `json:"txhash"`
+	Codespace string          `json:"codespace,omitempty"`
+	Code      uint32          `json:"code"`
+	Data      string          `json:"data,omitempty"`
+	RawLog    string          `json:"raw_log,omitempty"`
+	Info      string          `json:"info,omitempty"`
+	GasWanted int64           `json:"gas_wanted,string"`
+	GasUsed   int64           `json:"gas_used,string"`
+	Tx        json.RawMessage `json:"tx,omitempty"`
+	Events    []DecodedEvent  `json:"events"`
+}
+
+// DecodeTxResult decodes the tx bytes and the events of a tx result with the
+// tx config and the interface registry of the client context.
+func DecodeTxResult(clientCtx client.Context, height int64, txBytes []byte, result abci.ResponseDeliverTx) (*DecodedTxResponse, error) {
+	res := &DecodedTxResponse{
+		Height:    height,
+		Codespace: result.Codespace,
+		Code:      result.Code,
+		Data:      strings.ToUpper(hex.EncodeToString(result.Data)),
+		RawLog:    result.Log,
+		Info:      result.Info,
+		GasWanted: result.GasWanted,
+		GasUsed:   result.GasUsed,
+		Events:    DecodeEvents(clientCtx.InterfaceRegistry, result.Events),
+	}
+
+	if txBytes == nil {
+		return res, nil
+	}
+	res.TxHash = fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash())
+
+	if clientCtx.TxConfig == nil {
+		return nil, fmt.Errorf("no tx config to decode the tx, use --%s", flagRaw)
+	}
+	tx, err := clientCtx.TxConfig.TxDecoder()(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tx %s, use --%s: %w", res.TxHash, flagRaw, err)
+	}
+	if res.Tx, err = clientCtx.TxConfig.TxJSONEncoder()(tx); err != nil {
+		return nil, fmt.Errorf("failed to encode tx %s: %w", res.TxHash, err)
+	}
+	return res, nil
+}
+
+// DecodeEvents decodes the typed events, leaving the other events as is.
+func DecodeEvents(registry codectypes.InterfaceRegistry, events []abci.Event) []DecodedEvent {
+	decoded := make([]DecodedEvent, 0, len(events))
+	for _, event := range events {
+		if typed, err := decodeTypedEvent(registry, event); err == nil {
+			decoded = append(decoded, DecodedEvent{Type: event.Type, Typed: typed})
+			continue
+		}
+
+		attrs := make([]DecodedAttribute, 0, len(event.Attributes))
+		for _, attr := range event.Attributes {
+			attrs = append(attrs, DecodedAttribute{Key: string(attr.Key), Value: string(attr.Value)})
+		}
+		decoded = append(decoded, DecodedEvent{Type: event.Type, Attributes: attrs})
+	}
+	return decoded
+}
+
+// decodeTypedEvent decodes a typed event, emitted with sdk.TypedEventToEvent,
+// into the JSON of its message. The message type is resolved by the interface
+// registry, or else by the proto registry, and Any fields by the interface
+// registry.
+func decodeTypedEvent(registry codectypes.InterfaceRegistry, event abci.Event) (json.RawMessage, error) {
+	msg, err := resolveEventMessage(registry, event.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	attrMap := make(map[string]json.RawMessage, len(event.Attributes))
+	for _, attr := range event.Attributes {
+		attrMap[string(attr.Key)] = json.RawMessage(attr.Value)
+	}
+	bz, err := json.Marshal(attrMap)
+	if err != nil {
+		return nil, err
+	}
+
+	// events carry extra attributes, such as the msg_index set by baseapp
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true, AnyResolver: registry}
+	if err := unmarshaler.Unmarshal(strings.NewReader(string(bz)), msg); err != nil {
+		return nil, err
+	}
+	return codec.ProtoMarshalJSON(msg, registry)
+}
+
+func resolveEventMessage(registry codectypes.InterfaceRegistry, eventType string) (proto.Message, error) {
+	if registry != nil {
+		if msg, err := registry.Resolve("/" + eventType); err == nil {
+			return msg, nil
+		}
+	}
+
+	typ := proto.MessageType(eventType)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("event type %q is not a proto message", eventType)
+	}
+	msg, ok := reflect.New(typ.Elem()).Interface().(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("event type %q is not a proto message", eventType)
+	}
+	return msg, nil
+}
@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	abci "github.com/baron-chain/cometbft-bc/abci/types"
+	"github.com/stretchr/testify/require"
+
+	codectypes "github.com/baron-chain/cosmos-bc-47/codec/types"
+	"github.com/baron-chain/cosmos-bc-47/testutil/testdata"
+	sdk "github.com/baron-chain/cosmos-bc-47/types"
+)
+
+func TestDecodeEvents(t *testing.T) {
+	registry := codectypes.NewInterfaceRegistry()
+	testdata.RegisterInterfaces(registry)
+
+	animal, err := codectypes.NewAnyWithValue(&testdata.Dog{Size_: "big", Name: "spot"})
+	require.NoError(t, err)
+	typed, err := sdk.TypedEventToEvent(&testdata.HasAnimal{Animal: animal, X: 3})
+	require.NoError(t, err)
+	// baseapp appends the index of the msg which emitted the event
+	typed.Attributes = append(typed.Attributes, abci.EventAttribute{Key: "msg_index", Value: "0"})
+
+	events := DecodeEvents(registry, []abci.Event{
+		abci.Event(typed),
+		{Type: "transfer", Attributes: []abci.EventAttribute{{Key: "amount", Value: "10stake"}}},
+		{Type: "testpb.Dog", Attributes: []abci.EventAttribute{{Key: "size", Value: "not json"}}},
+	})
+	require.Len(t, events, 3)
+
+	require.Equal(t, "testpb.HasAnimal", events[0].Type)
+	require.Empty(t, events[0].Attributes)
+	var hasAnimal map[string]interface{}
+	require.NoError(t, json.Unmarshal(events[0].Typed, &hasAnimal))
+	require.Equal(t, map[string]interface{}{
+		"animal": map[string]interface{}{"@type": "/testpb.Dog", "size": "big", "name": "spot"},
+		"x":      "3",
+	}, hasAnimal)
+
+	// untyped events, and typed events which fail to decode, keep their attributes
+	require.Nil(t, events[1].Typed)
+	require.Equal(t, []DecodedAttribute{{Key: "amount", Value: "10stake"}}, events[1].Attributes)
+	require.Nil(t, events[2].Typed)
+	require.Equal(t, []DecodedAttribute{{Key: "size", Value: "not json"}}, events[2].Attributes)
+}
@@ -0,0 +1,259 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+)
+
+const (
+	finalizedHeightsSubscriber      = subscriberID + "-finalized-heights"
+	defaultFinalizedPollInterval    = 10 * time.Second
+	defaultFinalizedResubscribeWait = time.Second
+)
+
+var errNoHeader = errors.New("no header returned")
+
+// FinalizedHeightsClient is the subset of the node RPC client used by
+// WatchFinalizedHeights, implemented by the CometBFT HTTP client.
+type FinalizedHeightsClient interface {
+	EventsClient
+	Header(ctx context.Context, height *int64) (*coretypes.ResultHeader, error)
+}
+
+// FinalizedHeight is a height committed by the chain. CometBFT blocks are
+// final once committed.
+type FinalizedHeight struct {
+	Header tmtypes.Header
+	// Recovered is set when the header was queried from the node, because its
+	// event was missed, e.g. while the websocket was reconnecting.
+	Recovered bool
+}
+
+// WatchOptions configures WatchFinalizedHeightsWithOptions. Zero values
+// select the defaults.
+type WatchOptions struct {
+	// FromHeight is the first height passed to the callback, e.g. the one
+	// after the last height processed before a restart. The heights from it
+	// to the latest one are recovered first. By default, the watch starts at
+	// the latest height.
+	FromHeight int64
+	// PollInterval is the time without events after which the latest height
+	// is queried, to recover the missed heights. While the subscription is
+	// down, the latest height is polled at this interval.
+	PollInterval time.Duration
+	// ResubscribeBackoff is the initial delay between the attempts to
+	// subscribe again after the subscription was lost, doubled after each
+	// failed attempt.
+	ResubscribeBackoff time.Duration
+	// OnError, if set, is called with the errors of the queries and
+	// subscriptions, which are retried.
+	OnError func(error)
+}
+
+// WatchFinalizedHeights calls fn with each height committed by the chain,
+// in order and without gaps, starting at the latest height. It subscribes to
+// the new block headers of client, resubscribes when the subscription is
+// lost, and queries the headers of the heights whose events were missed,
+// polling the latest height while the subscription is down.
+//
+// It returns nil when ctx is done, or the error returned by fn, which stops
+// the watch.
+func WatchFinalizedHeights(ctx context.Context, client FinalizedHeightsClient, fn func(FinalizedHeight) error) error {
+	return WatchFinalizedHeightsWithOptions(ctx, client, fn, WatchOptions{})
+}
+
+// WatchFinalizedHeightsWithOptions is WatchFinalizedHeights configured by
+// opts.
+func WatchFinalizedHeightsWithOptions(ctx context.Context, client FinalizedHeightsClient, fn func(FinalizedHeight) error, opts WatchOptions) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultFinalizedPollInterval
+	}
+	if opts.ResubscribeBackoff <= 0 {
+		opts.ResubscribeBackoff = defaultFinalizedResubscribeWait
+	}
+
+	w := &heightWatcher{client: client, fn: fn, opts: opts}
+	if opts.FromHeight > 0 {
+		w.last = opts.FromHeight - 1
+	}
+	err := w.run(ctx)
+	if ctx.Err() != nil && err == ctx.Err() {
+		return nil
+	}
+	return err
+}
+
+// heightWatcher delivers the finalized heights of a WatchFinalizedHeights
+// call.
+type heightWatcher struct {
+	client FinalizedHeightsClient
+	fn     func(FinalizedHeight) error
+	opts   WatchOptions
+
+	// last is the last height delivered, or the one before the first height
+	// to deliver, 0 when the watch starts at the latest height.
+	last int64
+}
+
+func (w *heightWatcher) run(ctx context.Context) error {
+	defer w.unsubscribe()
+
+	backoff := w.opts.ResubscribeBackoff
+	for {
+		events := w.subscribe(ctx)
+		if events != nil {
+			backoff = w.opts.ResubscribeBackoff
+		}
+		if err := w.follow(ctx, events, backoff); err != nil {
+			return err
+		}
+		if events == nil {
+			backoff *= 2
+			if backoff > maxReconnectBackoff {
+				backoff = maxReconnectBackoff
+			}
+		}
+	}
+}
+
+// subscribe subscribes to the new block headers, and returns nil if it
+// failed.
+func (w *heightWatcher) subscribe(ctx context.Context) <-chan coretypes.ResultEvent {
+	subscribeCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	// a lost subscription may still be registered by the node
+	_ = w.client.Unsubscribe(subscribeCtx, finalizedHeightsSubscriber, tmtypes.EventQueryNewBlockHeader.String())
+	events, err := w.client.Subscribe(subscribeCtx, finalizedHeightsSubscriber, tmtypes.EventQueryNewBlockHeader.String(), defaultSubscriptionBuffer)
+	if err != nil {
+		w.report(wrapRPCError("subscribe to new block headers", err))
+		return nil
+	}
+	return events
+}
+
+func (w *heightWatcher) unsubscribe() {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	_ = w.client.Unsubscribe(ctx, finalizedHeightsSubscriber, tmtypes.EventQueryNewBlockHeader.String())
+}
+
+// follow delivers the heights of the events until the subscription is lost,
+// recovering the missed heights after opts.PollInterval without events. With
+// no subscription, it polls the latest height until it is time to subscribe
+// again, after retryAfter.
+func (w *heightWatcher) follow(ctx context.Context, events <-chan coretypes.ResultEvent, retryAfter time.Duration) error {
+	poll := time.NewTicker(w.opts.PollInterval)
+	defer poll.Stop()
+
+	// the loss of the connection only matters while subscribed
+	var retry <-chan time.Time
+	var quit <-chan struct{}
+	if events != nil {
+		quit = w.client.Quit()
+	} else {
+		timer := time.NewTimer(retryAfter)
+		defer timer.Stop()
+		retry = timer.C
+	}
+
+	// the heights committed while not subscribed are recovered right away
+	if err := w.recover(ctx); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-retry:
+			return nil
+
+		case <-quit:
+			return nil
+
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, ok := event.Data.(tmtypes.EventDataNewBlockHeader)
+			if !ok {
+				continue
+			}
+			poll.Reset(w.opts.PollInterval)
+			if err := w.deliver(ctx, data.Header, false); err != nil {
+				return err
+			}
+
+		case <-poll.C:
+			if err := w.recover(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// recover delivers the heights up to the latest one.
+func (w *heightWatcher) recover(ctx context.Context) error {
+	queryCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	res, err := w.client.Header(queryCtx, nil)
+	if err == nil && res.Header == nil {
+		err = errNoHeader
+	}
+	if err != nil {
+		w.report(wrapRPCError("query latest header", err))
+		return nil
+	}
+	return w.deliver(ctx, *res.Header, true)
+}
+
+// deliver passes the heights after the last delivered one up to header to
+// fn, querying the headers of the heights in between. It returns the error of
+// fn. Heights already delivered, e.g. received again after resubscribing, are
+// skipped.
+func (w *heightWatcher) deliver(ctx context.Context, header tmtypes.Header, recovered bool) error {
+	if header.Height <= w.last {
+		return nil
+	}
+
+	for w.last > 0 && w.last+1 < header.Height {
+		height := w.last + 1
+		queryCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+		res, err := w.client.Header(queryCtx, &height)
+		cancel()
+		if err == nil && res.Header == nil {
+			err = errNoHeader
+		}
+		if err != nil {
+			// the gap is recovered later, the heights are delivered in order
+			w.report(wrapRPCError(fmt.Sprintf("query header at height %d", height), err))
+			return nil
+		}
+		if err := w.call(FinalizedHeight{Header: *res.Header, Recovered: true}); err != nil {
+			return err
+		}
+	}
+	return w.call(FinalizedHeight{Header: header, Recovered: recovered})
+}
+
+func (w *heightWatcher) call(h FinalizedHeight) error {
+	if err := w.fn(h); err != nil {
+		return err
+	}
+	w.last = h.Header.Height
+	return nil
+}
+
+func (w *heightWatcher) report(err error) {
+	if w.opts.OnError != nil {
+		w.opts.OnError(err)
+	}
+}
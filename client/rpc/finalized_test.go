@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+	"github.com/stretchr/testify/require"
+)
+
+// mockHeadersClient is a FinalizedHeightsClient whose chain is at height
+// latest, and whose header queries fail while failing is set.
+type mockHeadersClient struct {
+	*mockEventsClient
+
+	mtx     sync.Mutex
+	latest  int64
+	failing bool
+}
+
+func (c *mockHeadersClient) Header(_ context.Context, height *int64) (*coretypes.ResultHeader, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.failing {
+		return nil, errors.New("node unavailable")
+	}
+	h := c.latest
+	if height != nil {
+		h = *height
+	}
+	return &coretypes.ResultHeader{Header: &tmtypes.Header{Height: h}}, nil
+}
+
+func (c *mockHeadersClient) setLatest(height int64, failing bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.latest, c.failing = height, failing
+}
+
+// commit commits a height and publishes its new block header event, if
+// subscribed.
+func (c *mockHeadersClient) commit(t *testing.T, height int64) {
+	c.mtx.Lock()
+	c.latest = height
+	c.mtx.Unlock()
+
+	query := tmtypes.EventQueryNewBlockHeader.String()
+	require.Eventually(t, func() bool { return c.subscribed() == 1 }, 5*time.Second, time.Millisecond)
+	c.mockEventsClient.mtx.Lock()
+	ch := c.subs[query]
+	c.mockEventsClient.mtx.Unlock()
+	ch <- coretypes.ResultEvent{Query: query, Data: tmtypes.EventDataNewBlockHeader{Header: tmtypes.Header{Height: height}}}
+}
+
+// dropSubscription closes the subscription, as the websocket client does
+// when it loses the connection.
+func (c *mockHeadersClient) dropSubscription() {
+	c.mockEventsClient.mtx.Lock()
+	defer c.mockEventsClient.mtx.Unlock()
+
+	query := tmtypes.EventQueryNewBlockHeader.String()
+	close(c.subs[query])
+	delete(c.subs, query)
+}
+
+func receiveHeight(t *testing.T, heights <-chan FinalizedHeight) FinalizedHeight {
+	t.Helper()
+
+	select {
+	case h := <-heights:
+		return h
+	case <-time.After(5 * time.Second):
+		t.Fatal("no height received")
+		return FinalizedHeight{}
+	}
+}
+
+func TestWatchFinalizedHeights(t *testing.T) {
+	client := &mockHeadersClient{mockEventsClient: newMockEventsClient(), latest: 5}
+	heights := make(chan FinalizedHeight, 100)
+	stop := errors.New("stop")
+
+	done := make(chan error)
+	go func() {
+		done <- WatchFinalizedHeightsWithOptions(context.Background(), client, func(h FinalizedHeight) error {
+			heights <- h
+			if h.Header.Height == 12 {
+				return stop
+			}
+			return nil
+		}, WatchOptions{FromHeight: 3, PollInterval: time.Hour, ResubscribeBackoff: 10 * time.Millisecond})
+	}()
+
+	// the heights from FromHeight to the latest one are recovered first
+	for height := int64(3); height <= 5; height++ {
+		h := receiveHeight(t, heights)
+		require.Equal(t, height, h.Header.Height)
+		require.True(t, h.Recovered)
+	}
+
+	client.commit(t, 6)
+	h := receiveHeight(t, heights)
+	require.Equal(t, int64(6), h.Header.Height)
+	require.False(t, h.Recovered)
+
+	// missed events are recovered in order
+	client.commit(t, 9)
+	for height := int64(7); height <= 9; height++ {
+		h := receiveHeight(t, heights)
+		require.Equal(t, height, h.Header.Height)
+		require.Equal(t, height != 9, h.Recovered)
+	}
+
+	// the heights committed while the subscription is lost are recovered
+	// after resubscribing, and the duplicate events are skipped
+	client.setLatest(10, false)
+	client.dropSubscription()
+	require.Equal(t, int64(10), receiveHeight(t, heights).Header.Height)
+	client.commit(t, 10)
+	client.commit(t, 11)
+	require.Equal(t, int64(11), receiveHeight(t, heights).Header.Height)
+
+	// the error of the callback stops the watch
+	client.commit(t, 12)
+	require.Equal(t, int64(12), receiveHeight(t, heights).Header.Height)
+	require.ErrorIs(t, <-done, stop)
+	require.Zero(t, client.subscribed())
+}
+
+func TestWatchFinalizedHeightsQueryFailures(t *testing.T) {
+	client := &mockHeadersClient{mockEventsClient: newMockEventsClient(), latest: 20}
+	heights := make(chan FinalizedHeight, 100)
+	errs := make(chan error, 100)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- WatchFinalizedHeightsWithOptions(ctx, client, func(h FinalizedHeight) error {
+			heights <- h
+			return nil
+		}, WatchOptions{PollInterval: 20 * time.Millisecond, OnError: func(err error) { errs <- err }})
+	}()
+
+	// the watch starts at the latest height
+	require.Equal(t, int64(20), receiveHeight(t, heights).Header.Height)
+	client.commit(t, 21)
+	require.Equal(t, int64(21), receiveHeight(t, heights).Header.Height)
+
+	// a gap which can't be recovered yet is retried by polling, and the
+	// heights are still delivered in order
+	client.setLatest(23, true)
+	client.commit(t, 24)
+	require.ErrorContains(t, <-errs, "node unavailable")
+	client.setLatest(24, false)
+	for height := int64(22); height <= 24; height++ {
+		require.Equal(t, height, receiveHeight(t, heights).Header.Height)
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
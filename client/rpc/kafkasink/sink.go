@@ -0,0 +1,115 @@
+// Package kafkasink registers the kafka sink of the subscribe command, which
+// produces the events to a Kafka topic with the kafka-go client. Apps enable
+// it by importing the package:
+//
+//	import _ "github.com/baron-chain/cosmos-bc-47/client/rpc/kafkasink"
+package kafkasink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+
+	"github.com/baron-chain/cosmos-bc-47/client/rpc"
+)
+
+// Scheme is the scheme of the sink URLs of Kafka topics.
+const Scheme = "kafka"
+
+// batchTimeout is the time the writer waits for more messages before
+// producing a partial batch. The events are written a batch at a time, so
+// that there is nothing to wait for.
+const batchTimeout = 10 * time.Millisecond
+
+func init() {
+	rpc.RegisterEventSink(Scheme, Open)
+}
+
+// Sink produces the events to a partition of a Kafka topic.
+type Sink struct {
+	w *kafka.Writer
+}
+
+var _ rpc.EventSink = (*Sink)(nil)
+
+// Open opens a sink URL kafka://[user:password@]host:port/topic, with the
+// optional partition (0 by default) and acks (all by default, or 1 for the
+// leader only) query parameters. The user and password, if any, authenticate
+// with SASL/PLAIN.
+func Open(u *url.URL) (rpc.EventSink, error) {
+	w, err := newWriter(u)
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{w: w}, nil
+}
+
+func newWriter(u *url.URL) (*kafka.Writer, error) {
+	topic, err := rpc.SinkTopic(u)
+	if err != nil {
+		return nil, err
+	}
+
+	partition := 0
+	if p := u.Query().Get("partition"); p != "" {
+		if partition, err = strconv.Atoi(p); err != nil || partition < 0 {
+			return nil, fmt.Errorf("invalid Kafka partition %q", p)
+		}
+	}
+
+	acks := kafka.RequireAll
+	switch a := u.Query().Get("acks"); a {
+	case "", "all", "-1":
+	case "1":
+		acks = kafka.RequireOne
+	default:
+		// without acknowledgements, the checkpoint could get past events
+		// which were never written
+		return nil, fmt.Errorf("invalid Kafka acks %q, expected all or 1", a)
+	}
+
+	transport := &kafka.Transport{ClientID: "baron-chain-subscriber"}
+	if u.User != nil {
+		password, _ := u.User.Password()
+		transport.SASL = plain.Mechanism{Username: u.User.Username(), Password: password}
+	}
+
+	return &kafka.Writer{
+		Addr:  kafka.TCP(u.Host),
+		Topic: topic,
+		// the events are produced to a single partition, so that they are
+		// consumed in order
+		Balancer: kafka.BalancerFunc(func(kafka.Message, ...int) int {
+			return partition
+		}),
+		RequiredAcks: acks,
+		BatchTimeout: batchTimeout,
+		Transport:    transport,
+	}, nil
+}
+
+// Publish produces the messages, and returns once the brokers acknowledged
+// them all. On error, some of the messages may have been written, and are
+// produced again with the next attempt.
+func (s *Sink) Publish(ctx context.Context, msgs []rpc.SinkMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	kmsgs := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		kmsgs[i] = kafka.Message{Key: msg.Key, Value: msg.Value}
+	}
+	return s.w.WriteMessages(ctx, kmsgs...)
+}
+
+// Close flushes the pending messages and closes the connections to the
+// brokers.
+func (s *Sink) Close() error {
+	return s.w.Close()
+}
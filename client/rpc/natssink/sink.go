@@ -0,0 +1,141 @@
+// Package natssink registers the nats sink of the subscribe command, which
+// publishes the events to a NATS subject, or a JetStream stream, with the
+// nats.go client. Apps enable it by importing the package:
+//
+//	import _ "github.com/baron-chain/cosmos-bc-47/client/rpc/natssink"
+package natssink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/baron-chain/cosmos-bc-47/client/rpc"
+)
+
+// Scheme is the scheme of the sink URLs of NATS subjects.
+const Scheme = "nats"
+
+// flushTimeout bounds the wait for the server to process the messages
+// published to core NATS, when the context of Publish has no deadline.
+const flushTimeout = 10 * time.Second
+
+func init() {
+	rpc.RegisterEventSink(Scheme, Open)
+}
+
+// Sink publishes the events to a subject of a NATS server. A batch is
+// acknowledged once the server processed it with core NATS, or with
+// ?jetstream=true, once the JetStream stream the subject belongs to persisted
+// it.
+type Sink struct {
+	nc      *nats.Conn
+	subject string
+	// js is the JetStream context, nil for core NATS.
+	js nats.JetStreamContext
+}
+
+var _ rpc.EventSink = (*Sink)(nil)
+
+// Open opens a sink URL nats://[user:password@]host:port/subject, with the
+// optional jetstream=true query parameter.
+func Open(u *url.URL) (rpc.EventSink, error) {
+	subject, jetstream, err := parseURL(u)
+	if err != nil {
+		return nil, err
+	}
+
+	server := url.URL{Scheme: u.Scheme, User: u.User, Host: u.Host}
+	nc, err := nats.Connect(server.String(), nats.Name("baron-chain-subscriber"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS server %s: %w", u.Host, err)
+	}
+
+	s := &Sink{nc: nc, subject: subject}
+	if jetstream {
+		if s.js, err = nc.JetStream(); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// parseURL returns the subject of a sink URL, and whether it is published to
+// with JetStream.
+func parseURL(u *url.URL) (string, bool, error) {
+	subject, err := rpc.SinkTopic(u)
+	if err != nil {
+		return "", false, err
+	}
+	if strings.ContainsAny(subject, " \t\r\n*>") {
+		return "", false, fmt.Errorf("invalid NATS subject %q", subject)
+	}
+
+	var jetstream bool
+	if js := u.Query().Get("jetstream"); js != "" {
+		if jetstream, err = strconv.ParseBool(js); err != nil {
+			return "", false, fmt.Errorf("invalid jetstream %q", js)
+		}
+	}
+	return subject, jetstream, nil
+}
+
+// Publish publishes the messages and waits for their acknowledgement. The
+// key of each message is its Nats-Msg-Id, so that JetStream discards the
+// messages published again within its duplicate window.
+func (s *Sink) Publish(ctx context.Context, msgs []rpc.SinkMessage) error {
+	if s.js == nil {
+		for _, msg := range msgs {
+			if err := s.nc.PublishMsg(s.newMsg(msg)); err != nil {
+				return err
+			}
+		}
+
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, flushTimeout)
+			defer cancel()
+		}
+		return s.nc.FlushWithContext(ctx)
+	}
+
+	acks := make([]nats.PubAckFuture, 0, len(msgs))
+	for _, msg := range msgs {
+		ack, err := s.js.PublishMsgAsync(s.newMsg(msg))
+		if err != nil {
+			return err
+		}
+		acks = append(acks, ack)
+	}
+	for _, ack := range acks {
+		select {
+		case <-ack.Ok():
+		case err := <-ack.Err():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s *Sink) newMsg(msg rpc.SinkMessage) *nats.Msg {
+	m := nats.NewMsg(s.subject)
+	m.Data = msg.Value
+	if len(msg.Key) > 0 {
+		m.Header.Set(nats.MsgIdHdr, string(msg.Key))
+	}
+	return m
+}
+
+// Close closes the connection to the server.
+func (s *Sink) Close() error {
+	s.nc.Close()
+	return nil
+}
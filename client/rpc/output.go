@@ -0,0 +1,249 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+)
+
+const flagFields = "fields"
+
+// addFieldsFlag adds the --fields flag to an rpc query command.
+func addFieldsFlag(cmd *cobra.Command) {
+	cmd.Flags().String(flagFields, "", `Only print these comma separated fields of the response, e.g. "sync_info.latest_block_height,node_info.network" or "coins[*].display"`)
+}
+
+// fieldMask is a set of field paths of a JSON response. A path is a list of
+// object keys and array indexes, where "*" matches every key or index. A key
+// applied to an array is applied to each of its elements.
+type fieldMask struct {
+	paths    []string
+	selected bool
+	children map[string]*fieldMask
+}
+
+// parseFieldMask parses comma separated field paths in the dotted or JSONPath
+// style, e.g. "a.b", "$.a[0].b" or "a.*.b".
+func parseFieldMask(s string) (*fieldMask, error) {
+	mask := &fieldMask{}
+	for _, path := range strings.Split(s, ",") {
+		path = strings.TrimSpace(path)
+		segments, err := parseFieldPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		mask.paths = append(mask.paths, path)
+		mask.add(segments)
+	}
+	return mask, nil
+}
+
+func parseFieldPath(path string) ([]string, error) {
+	p := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	p = strings.NewReplacer("[", ".", "]", "").Replace(p)
+	if p == "" {
+		return nil, fmt.Errorf("invalid --%s: empty field path", flagFields)
+	}
+
+	segments := strings.Split(p, ".")
+	for _, seg := range segments {
+		if seg == "" {
+			return nil, fmt.Errorf("invalid --%s: empty field in path %q", flagFields, path)
+		}
+	}
+	return segments, nil
+}
+
+func (m *fieldMask) add(segments []string) {
+	if len(segments) == 0 {
+		m.selected = true
+		return
+	}
+
+	if m.children == nil {
+		m.children = make(map[string]*fieldMask)
+	}
+	child, ok := m.children[segments[0]]
+	if !ok {
+		child = &fieldMask{}
+		m.children[segments[0]] = child
+	}
+	child.add(segments[1:])
+}
+
+// merge returns the union of the masks.
+func (m *fieldMask) merge(others ...*fieldMask) *fieldMask {
+	merged := &fieldMask{}
+	for _, mask := range append([]*fieldMask{m}, others...) {
+		if mask == nil {
+			continue
+		}
+		merged.selected = merged.selected || mask.selected
+		for key, child := range mask.children {
+			if merged.children == nil {
+				merged.children = make(map[string]*fieldMask)
+			}
+			merged.children[key] = child.merge(merged.children[key])
+		}
+	}
+	return merged
+}
+
+// project returns the fields of v selected by the mask, and whether any was.
+func (m *fieldMask) project(v interface{}) (interface{}, bool) {
+	if m.selected {
+		return v, true
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{})
+		for key, child := range m.children {
+			if key == "*" {
+				for k, value := range v {
+					if projected, ok := child.project(value); ok {
+						out[k] = projected
+					}
+				}
+				continue
+			}
+			if value, ok := v[key]; ok {
+				if projected, ok := child.project(value); ok {
+					out[key] = projected
+				}
+			}
+		}
+		return out, len(out) > 0
+
+	case []interface{}:
+		// keys which are not indexes apply to every element
+		elems := &fieldMask{children: make(map[string]*fieldMask)}
+		for key, child := range m.children {
+			if _, err := strconv.Atoi(key); err != nil && key != "*" {
+				elems.children[key] = child
+			}
+		}
+
+		out := make([]interface{}, 0, len(v))
+		for i, value := range v {
+			mask := elems.merge(m.children["*"], m.children[strconv.Itoa(i)])
+			if projected, ok := mask.project(value); ok {
+				out = append(out, projected)
+			}
+		}
+		return out, len(v) == 0 || len(out) > 0
+
+	default:
+		return nil, false
+	}
+}
+
+// projectJSON returns the fields of the JSON response selected by the mask. It
+// returns an error if a path selects no field, which is likely a typo.
+func (m *fieldMask) projectJSON(bz []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(bz, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, path := range m.paths {
+		single, _ := parseFieldMask(path)
+		if _, ok := single.project(v); !ok {
+			return nil, fmt.Errorf("no field %s in the response", path)
+		}
+	}
+
+	projected, _ := m.project(v)
+	return json.Marshal(projected)
+}
+
+// getFieldMask returns the field mask of --fields, or nil if it is not set.
+func getFieldMask(cmd *cobra.Command) (*fieldMask, error) {
+	fields, _ := cmd.Flags().GetString(flagFields)
+	if fields == "" {
+		return nil, nil
+	}
+	return parseFieldMask(fields)
+}
+
+// printObject prints the result of a query like client.Context.PrintObjectLegacy,
+// projected on the --fields of the command.
+func printObject(cmd *cobra.Command, clientCtx client.Context, result interface{}) error {
+	mask, err := getFieldMask(cmd)
+	if err != nil {
+		return err
+	}
+	if mask == nil {
+		return clientCtx.PrintObjectLegacy(result)
+	}
+
+	bz, err := clientCtx.LegacyAmino.MarshalJSON(result)
+	if err != nil {
+		return err
+	}
+	return printProjected(clientCtx, mask, bz)
+}
+
+// printProto prints a proto response like client.Context.PrintProto,
+// projected on the --fields of the command.
+func printProto(cmd *cobra.Command, clientCtx client.Context, msg proto.Message) error {
+	mask, err := getFieldMask(cmd)
+	if err != nil {
+		return err
+	}
+	if mask == nil {
+		return clientCtx.PrintProto(msg)
+	}
+
+	bz, err := clientCtx.Codec.MarshalJSON(msg)
+	if err != nil {
+		return err
+	}
+	return printProjected(clientCtx, mask, bz)
+}
+
+// printRaw prints a JSON response like client.Context.PrintRaw, projected on
+// the --fields of the command.
+func printRaw(cmd *cobra.Command, clientCtx client.Context, bz json.RawMessage) error {
+	mask, err := getFieldMask(cmd)
+	if err != nil {
+		return err
+	}
+	if mask == nil {
+		return clientCtx.PrintRaw(bz)
+	}
+	return printProjected(clientCtx, mask, bz)
+}
+
+// printJSON prints a JSON response as is, whatever the output format, like
+// client.Context.PrintBytes, projected on the --fields of the command.
+func printJSON(cmd *cobra.Command, clientCtx client.Context, bz []byte) error {
+	mask, err := getFieldMask(cmd)
+	if err != nil {
+		return err
+	}
+	if mask == nil {
+		return clientCtx.PrintBytes(bz)
+	}
+
+	projected, err := mask.projectJSON(bz)
+	if err != nil {
+		return err
+	}
+	return clientCtx.PrintBytes(append(projected, '\n'))
+}
+
+func printProjected(clientCtx client.Context, mask *fieldMask, bz []byte) error {
+	projected, err := mask.projectJSON(bz)
+	if err != nil {
+		return err
+	}
+	return clientCtx.PrintRaw(projected)
+}
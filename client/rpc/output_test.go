@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldMaskProjectJSON(t *testing.T) {
+	response := []byte(`{
+		"node_info": {"network": "baron-1", "version": "0.37.0"},
+		"sync_info": {"latest_block_height": "1200", "catching_up": false},
+		"coins": [
+			{"denom": "ubaron", "amount": "1500000", "display": "1.5 baron"},
+			{"denom": "stake", "amount": "10", "display": "10 stake"}
+		],
+		"empty": []
+	}`)
+
+	testCases := []struct {
+		name   string
+		fields string
+		expOut string
+		expErr string
+	}{
+		{"nested fields", "sync_info.latest_block_height, node_info.network", `{"node_info":{"network":"baron-1"},"sync_info":{"latest_block_height":"1200"}}`, ""},
+		{"jsonpath root", "$.node_info", `{"node_info":{"network":"baron-1","version":"0.37.0"}}`, ""},
+		{"field of every element", "coins.display", `{"coins":[{"display":"1.5 baron"},{"display":"10 stake"}]}`, ""},
+		{"wildcard index", "coins[*].denom,coins[*].amount", `{"coins":[{"amount":"1500000","denom":"ubaron"},{"amount":"10","denom":"stake"}]}`, ""},
+		{"index", "coins[1].denom", `{"coins":[{"denom":"stake"}]}`, ""},
+		// empty arrays match any path, like the fields of their missing elements
+		{"wildcard key", "*.network", `{"node_info":{"network":"baron-1"},"empty":[]}`, ""},
+		{"empty array", "empty.denom", `{"empty":[]}`, ""},
+		{"unknown field", "sync_info.height", "", "no field sync_info.height in the response"},
+		{"field of a scalar", "node_info.network.name", "", "no field"},
+		{"empty path", "node_info,,", "", "empty field path"},
+		{"empty field", "node_info..network", "", "empty field in path"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mask, err := parseFieldMask(tc.fields)
+			if err == nil {
+				var out []byte
+				out, err = mask.projectJSON(response)
+				if err == nil {
+					require.JSONEq(t, tc.expOut, string(out))
+				}
+			}
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
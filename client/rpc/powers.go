@@ -0,0 +1,410 @@
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+	sdk "github.com/baron-chain/cosmos-bc-47/types"
+)
+
+const (
+	flagPowersFrom        = "from"
+	flagPowersTo          = "to"
+	flagPowersStep        = "step"
+	flagPowersFormat      = "out"
+	flagPowersOutputFile  = "output-file"
+	flagPowersConcurrency = "concurrency"
+	flagPowersResume      = "resume"
+
+	powersFormatCSV      = "csv"
+	powersFormatJSON     = "json"
+	defaultPowersStep    = 1
+	defaultPowersWorkers = 4
+	maxPowersConcurrency = 64
+)
+
+var validatorPowersCSVHeader = []string{"height", "address", "voting_power", "proposer_priority"}
+
+// ValidatorPower is a row of the validator powers dataset: the voting power of
+// a validator at a height.
+type ValidatorPower struct {
+	Height           int64           `json:"height"`
+	Address          sdk.ConsAddress `json:"address"`
+	VotingPower      int64           `json:"voting_power"`
+	ProposerPriority int64           `json:"proposer_priority"`
+}
+
+// ValidatorPowersCommand returns the command exporting the voting powers of
+// the validator sets over a range of heights.
+func ValidatorPowersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validator-powers",
+		Short: "Export the Baron Chain validator powers over a range of heights",
+		Long: `Query the validator set at every --step heights from --from to --to, the latest height
+by default, and write one row per validator and height with its voting power and
+proposer priority, as CSV or as JSON lines. Heights are queried with --concurrency
+requests at a time, and written in order.
+
+With --output-file and --resume, an interrupted export continues where it stopped:
+the rows of the last height of the file, which may be incomplete, are queried again
+and the following heights are appended. The node must not have pruned the heights.`,
+		Example: `$ barond query validator-powers --from 1000000 --to 1100000 --step 100 --out csv --output-file powers.csv
+$ barond query validator-powers --from 1000000 --step 1000 --out json --output-file powers.jsonl --resume`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get query context: %w", err)
+			}
+
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
+			from, _ := cmd.Flags().GetInt64(flagPowersFrom)
+			to, _ := cmd.Flags().GetInt64(flagPowersTo)
+			step, _ := cmd.Flags().GetInt64(flagPowersStep)
+			format, _ := cmd.Flags().GetString(flagPowersFormat)
+			outputFile, _ := cmd.Flags().GetString(flagPowersOutputFile)
+			concurrency, _ := cmd.Flags().GetInt(flagPowersConcurrency)
+			resume, _ := cmd.Flags().GetBool(flagPowersResume)
+
+			if from <= 0 {
+				return fmt.Errorf("--%s must be greater than 0, got %d", flagPowersFrom, from)
+			}
+			if step <= 0 {
+				return fmt.Errorf("--%s must be greater than 0, got %d", flagPowersStep, step)
+			}
+			if format != powersFormatCSV && format != powersFormatJSON {
+				return fmt.Errorf("--%s must be %s or %s, got %q", flagPowersFormat, powersFormatCSV, powersFormatJSON, format)
+			}
+			if concurrency <= 0 || concurrency > maxPowersConcurrency {
+				return fmt.Errorf("--%s must be between 1 and %d, got %d", flagPowersConcurrency, maxPowersConcurrency, concurrency)
+			}
+			if resume && outputFile == "" {
+				return fmt.Errorf("--%s requires --%s", flagPowersResume, flagPowersOutputFile)
+			}
+
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return fmt.Errorf("failed to get node: %w", err)
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			if to == 0 {
+				status, err := node.Status(ctx)
+				if err != nil {
+					return wrapRPCError("query node status", err)
+				}
+				to = status.SyncInfo.LatestBlockHeight
+			}
+			if to < from {
+				return fmt.Errorf("--%s %d is lower than --%s %d", flagPowersTo, to, flagPowersFrom, from)
+			}
+
+			out := cmd.OutOrStdout()
+			writeHeader := true
+			if outputFile != "" {
+				f, next, err := openValidatorPowersFile(outputFile, format, resume)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				if next > from {
+					// resume at the first height of the range from next
+					from += (next - from + step - 1) / step * step
+				}
+				if next > 0 {
+					cmd.PrintErrf("Resuming at height %d\n", from)
+				}
+				info, err := f.Stat()
+				if err != nil {
+					return err
+				}
+				writeHeader = info.Size() == 0
+				out = f
+			}
+
+			w := newValidatorPowersWriter(out, format, writeHeader)
+			err = ExportValidatorPowers(ctx, node, validatorPowersHeights(from, to, step), concurrency, w.write)
+			if flushErr := w.flush(); err == nil {
+				err = flushErr
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().String(flags.FlagNode, defaultNodeEndpoint, "Baron Chain node RPC endpoint")
+	cmd.Flags().Int64(flagPowersFrom, 0, "First height to export")
+	cmd.Flags().Int64(flagPowersTo, 0, "Last height to export (default the latest height)")
+	cmd.Flags().Int64(flagPowersStep, defaultPowersStep, "Number of heights between two exported heights")
+	cmd.Flags().String(flagPowersFormat, powersFormatCSV, "Format of the dataset (csv|json), json writing one object per line")
+	cmd.Flags().String(flagPowersOutputFile, "", "File to write the dataset to, instead of stdout")
+	cmd.Flags().Int(flagPowersConcurrency, defaultPowersWorkers, "Number of heights queried at a time")
+	cmd.Flags().Bool(flagPowersResume, false, "Continue the export of an interrupted run in --output-file")
+	_ = cmd.MarkFlagRequired(flagPowersFrom)
+	addChainProfileFlag(cmd)
+
+	return cmd
+}
+
+// validatorPowersHeights returns the heights from from to to, every step.
+func validatorPowersHeights(from, to, step int64) []int64 {
+	var heights []int64
+	for h := from; h <= to; h += step {
+		heights = append(heights, h)
+	}
+	return heights
+}
+
+// ValidatorPowersNode is the subset of the node RPC client used by
+// ExportValidatorPowers.
+type ValidatorPowersNode interface {
+	Validators(ctx context.Context, height *int64, page, perPage *int) (*coretypes.ResultValidators, error)
+}
+
+// ExportValidatorPowers queries the validator sets at the given heights, with
+// at most concurrency queries at a time, and calls write with the powers of
+// each height in the order of the heights. RPC failures are returned as an
+// *Error.
+func ExportValidatorPowers(ctx context.Context, node ValidatorPowersNode, heights []int64, concurrency int, write func([]ValidatorPower) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		powers []ValidatorPower
+		err    error
+	}
+	results := make([]chan result, len(heights))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+
+	// a slot is released once the height is written, so that the queries
+	// don't run ahead of the writes by more than concurrency heights
+	slots := make(chan struct{}, concurrency)
+	go func() {
+		for i, height := range heights {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(i int, height int64) {
+				powers, err := queryValidatorPowers(ctx, node, height)
+				results[i] <- result{powers, err}
+			}(i, height)
+		}
+	}()
+
+	for i := range heights {
+		var res result
+		select {
+		case res = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if res.err != nil {
+			return res.err
+		}
+		if err := write(res.powers); err != nil {
+			return err
+		}
+		<-slots
+	}
+	return nil
+}
+
+// queryValidatorPowers returns the powers of the validator set at height.
+func queryValidatorPowers(ctx context.Context, node ValidatorPowersNode, height int64) ([]ValidatorPower, error) {
+	var powers []ValidatorPower
+	perPage := validatorsPerRequest
+	for page := 1; ; page++ {
+		res, err := node.Validators(ctx, &height, &page, &perPage)
+		if err != nil {
+			return nil, wrapRPCError(fmt.Sprintf("query validators at height %d", height), err)
+		}
+
+		for _, val := range res.Validators {
+			powers = append(powers, ValidatorPower{
+				Height:           height,
+				Address:          sdk.ConsAddress(val.Address),
+				VotingPower:      val.VotingPower,
+				ProposerPriority: val.ProposerPriority,
+			})
+		}
+
+		if len(res.Validators) == 0 || page*perPage >= res.Total {
+			return powers, nil
+		}
+	}
+}
+
+// validatorPowersWriter writes the rows of the dataset as CSV or JSON lines.
+type validatorPowersWriter struct {
+	format string
+	buf    *bufio.Writer
+	csv    *csv.Writer
+	header bool
+}
+
+func newValidatorPowersWriter(w io.Writer, format string, header bool) *validatorPowersWriter {
+	buf := bufio.NewWriter(w)
+	return &validatorPowersWriter{format: format, buf: buf, csv: csv.NewWriter(buf), header: header}
+}
+
+// write writes the rows of a height, and flushes them so that an interrupted
+// export loses at most the height being written.
+func (w *validatorPowersWriter) write(powers []ValidatorPower) error {
+	if w.format == powersFormatJSON {
+		for _, p := range powers {
+			bz, err := json.Marshal(p)
+			if err != nil {
+				return err
+			}
+			w.buf.Write(bz)
+			w.buf.WriteByte('\n')
+		}
+		return w.flush()
+	}
+
+	if w.header {
+		if err := w.csv.Write(validatorPowersCSVHeader); err != nil {
+			return err
+		}
+		w.header = false
+	}
+	for _, p := range powers {
+		record := []string{
+			strconv.FormatInt(p.Height, 10),
+			p.Address.String(),
+			strconv.FormatInt(p.VotingPower, 10),
+			strconv.FormatInt(p.ProposerPriority, 10),
+		}
+		if err := w.csv.Write(record); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+func (w *validatorPowersWriter) flush() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+// openValidatorPowersFile opens the dataset file for writing. Unless resume is
+// set, the file must not exist. Otherwise the rows of the last height of the
+// file, which may be incomplete, are truncated and that height is returned as
+// the next one to export, or 0 if the file holds no rows.
+func openValidatorPowersFile(path, format string, resume bool) (*os.File, int64, error) {
+	if !resume {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			return nil, 0, fmt.Errorf("%s already exists, pass --%s to continue its export", path, flagPowersResume)
+		}
+		return f, 0, err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, next, err := lastValidatorPowersHeight(f, format)
+	if err == nil {
+		err = f.Truncate(offset)
+	}
+	if err == nil {
+		_, err = f.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, 0, fmt.Errorf("failed to resume %s: %w", path, err)
+	}
+	return f, next, nil
+}
+
+// lastValidatorPowersHeight returns the height of the last rows of a dataset
+// and the offset of the first of them, or the offset of the end of the header
+// and 0 if it holds no rows. A last line without newline is incomplete.
+func lastValidatorPowersHeight(r io.Reader, format string) (int64, int64, error) {
+	var (
+		br                  = bufio.NewReader(r)
+		offset, startOffset int64
+		last                int64
+		lineNum             int
+	)
+	for {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			// the last line is incomplete, or the file ends with a newline
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		lineNum++
+
+		lineOffset := offset
+		offset += int64(len(line))
+		if format == powersFormatCSV && lineNum == 1 {
+			if last == 0 {
+				startOffset = offset
+			}
+			continue
+		}
+
+		height, err := parseValidatorPowersHeight(line, format)
+		if err != nil {
+			return 0, 0, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if height != last {
+			last, startOffset = height, lineOffset
+		}
+	}
+	return startOffset, last, nil
+}
+
+func parseValidatorPowersHeight(line, format string) (int64, error) {
+	if format == powersFormatJSON {
+		var p struct {
+			Height int64 `json:"height"`
+		}
+		if err := json.Unmarshal([]byte(line), &p); err != nil {
+			return 0, err
+		}
+		return p.Height, nil
+	}
+
+	record, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(record[0], 10, 64)
+}
@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/baron-chain/cosmos-bc-47/types"
+)
+
+func TestExportValidatorPowers(t *testing.T) {
+	var vals []*tmtypes.Validator
+	for i := 0; i < 150; i++ {
+		val, _ := tmtypes.RandValidator(false, int64(i+1))
+		vals = append(vals, val)
+	}
+	node := mockUptimeNode{latest: 100, joined: 50, vals: vals}
+
+	heights := validatorPowersHeights(10, 100, 20)
+	require.Equal(t, []int64{10, 30, 50, 70, 90}, heights)
+
+	var written []int64
+	err := ExportValidatorPowers(context.Background(), node, heights, 3, func(powers []ValidatorPower) error {
+		written = append(written, powers[0].Height)
+		// the validator at index 0 joined at height 50, and the validator sets
+		// span two pages
+		if powers[0].Height < 50 {
+			require.Len(t, powers, 149)
+		} else {
+			require.Len(t, powers, 150)
+		}
+		require.Equal(t, sdk.ConsAddress(node.valSet(powers[0].Height)[0].Address), powers[0].Address)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, heights, written)
+}
+
+func TestValidatorPowersWriter(t *testing.T) {
+	powers := []ValidatorPower{{Height: 5, Address: sdk.ConsAddress{1}, VotingPower: 10, ProposerPriority: -3}}
+
+	var buf bytes.Buffer
+	w := newValidatorPowersWriter(&buf, powersFormatCSV, true)
+	require.NoError(t, w.write(powers))
+	require.NoError(t, w.write(nil))
+	require.Equal(t, "height,address,voting_power,proposer_priority\n5,"+powers[0].Address.String()+",10,-3\n", buf.String())
+
+	buf.Reset()
+	w = newValidatorPowersWriter(&buf, powersFormatJSON, true)
+	require.NoError(t, w.write(powers))
+	require.Equal(t, `{"height":5,"address":"`+powers[0].Address.String()+`","voting_power":10,"proposer_priority":-3}`+"\n", buf.String())
+}
+
+func TestOpenValidatorPowersFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "powers.csv")
+	header := "height,address,voting_power,proposer_priority\n"
+	rows := header + "5,a,10,0\n5,b,10,0\n6,a,10,0\n6,b,1"
+	require.NoError(t, os.WriteFile(path, []byte(rows), 0o600))
+
+	_, _, err := openValidatorPowersFile(path, powersFormatCSV, false)
+	require.ErrorContains(t, err, "already exists")
+
+	// the rows of height 6, whose last one is incomplete, are truncated
+	f, next, err := openValidatorPowersFile(path, powersFormatCSV, true)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), next)
+	_, err = f.WriteString("6,a,10,0\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	bz, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, header+"5,a,10,0\n5,b,10,0\n6,a,10,0\n", string(bz))
+
+	// a file with the header only resumes from the start of the range
+	require.NoError(t, os.WriteFile(path, []byte(header), 0o600))
+	f, next, err = openValidatorPowersFile(path, powersFormatCSV, true)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.Equal(t, int64(0), next)
+
+	jsonPath := filepath.Join(t.TempDir(), "powers.jsonl")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(strings.Repeat(`{"height":7}`+"\n", 2)+`{"height":8}`+"\n"), 0o600))
+	f, next, err = openValidatorPowersFile(jsonPath, powersFormatJSON, true)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.Equal(t, int64(8), next)
+	bz, err = os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat(`{"height":7}`+"\n", 2), string(bz))
+}
@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/client/config"
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+)
+
+const flagChainProfile = "chain-profile"
+
+// addChainProfileFlag adds the --chain-profile flag to an rpc command.
+func addChainProfileFlag(cmd *cobra.Command) {
+	cmd.Flags().String(flagChainProfile, "", "Named endpoint of client.toml to query, e.g. mainnet, testnet or localnet")
+}
+
+// StatusNode is the subset of the node RPC client used to check the chain ID
+// of a chain profile.
+type StatusNode interface {
+	Status(ctx context.Context) (*coretypes.ResultStatus, error)
+}
+
+// applyChainProfile points the client context to the node of the chain
+// profile given with --chain-profile, if any, and checks the node serves the
+// chain ID of the profile. An explicit --node takes precedence over the node
+// of the profile, but is checked all the same.
+func applyChainProfile(cmd *cobra.Command, clientCtx client.Context) (client.Context, error) {
+	name, _ := cmd.Flags().GetString(flagChainProfile)
+	if name == "" {
+		return clientCtx, nil
+	}
+
+	profile, err := config.ReadChainProfile(clientCtx.HomeDir, name)
+	if err != nil {
+		return clientCtx, err
+	}
+
+	nodeURI := profile.Node
+	if cmd.Flags().Changed(flags.FlagNode) {
+		nodeURI, _ = cmd.Flags().GetString(flags.FlagNode)
+	}
+	if nodeURI != "" {
+		rpcClient, err := client.NewClientFromNode(nodeURI)
+		if err != nil {
+			return clientCtx, fmt.Errorf("invalid node of chain profile %s: %w", name, err)
+		}
+		clientCtx = clientCtx.WithNodeURI(nodeURI).WithClient(rpcClient)
+	}
+
+	if profile.ChainID == "" {
+		return clientCtx, nil
+	}
+	clientCtx = clientCtx.WithChainID(profile.ChainID)
+
+	node, err := clientCtx.GetNode()
+	if err != nil {
+		return clientCtx, fmt.Errorf("failed to get node: %w", err)
+	}
+	if err := CheckNodeChainID(cmd.Context(), node, name, profile.ChainID); err != nil {
+		return clientCtx, err
+	}
+	return clientCtx, nil
+}
+
+// CheckNodeChainID returns an error if the node doesn't report the chain ID
+// expected by the chain profile, e.g. a testnet profile pointing to a mainnet
+// node.
+func CheckNodeChainID(ctx context.Context, node StatusNode, profile, chainID string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	status, err := node.Status(ctx)
+	if err != nil {
+		return wrapRPCError("query node status", err)
+	}
+	if network := status.NodeInfo.Network; network != chainID {
+		return fmt.Errorf("chain profile %s expects chain-id %s, but the node reports %s", profile, chainID, network)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/baron-chain/cometbft-bc/p2p"
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+)
+
+type mockStatusNode struct {
+	network string
+}
+
+func (n mockStatusNode) Status(context.Context) (*coretypes.ResultStatus, error) {
+	return &coretypes.ResultStatus{NodeInfo: p2p.DefaultNodeInfo{Network: n.network}}, nil
+}
+
+func TestCheckNodeChainID(t *testing.T) {
+	node := mockStatusNode{network: "baron-mainnet-1"}
+	require.NoError(t, CheckNodeChainID(context.Background(), node, "mainnet", "baron-mainnet-1"))
+	require.EqualError(t, CheckNodeChainID(context.Background(), node, "testnet", "baron-testnet-1"),
+		"chain profile testnet expects chain-id baron-testnet-1, but the node reports baron-mainnet-1")
+}
+
+func TestApplyChainProfile(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "config"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config", "client.toml"), []byte(`
+node = "tcp://localhost:26657"
+
+[profiles.localnet]
+node = "tcp://localhost:36657"
+`), 0o600))
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String(flagNode, defaultNodeEndpoint, "")
+		addChainProfileFlag(cmd)
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+	clientCtx := client.Context{}.WithHomeDir(home)
+
+	// without a profile the context is unchanged
+	ctx, err := applyChainProfile(newCmd(), clientCtx)
+	require.NoError(t, err)
+	require.Empty(t, ctx.NodeURI)
+
+	ctx, err = applyChainProfile(newCmd("--chain-profile=localnet"), clientCtx)
+	require.NoError(t, err)
+	require.Equal(t, "tcp://localhost:36657", ctx.NodeURI)
+	require.NotNil(t, ctx.Client)
+
+	ctx, err = applyChainProfile(newCmd("--chain-profile=localnet", "--node=tcp://localhost:46657"), clientCtx)
+	require.NoError(t, err)
+	require.Equal(t, "tcp://localhost:46657", ctx.NodeURI)
+
+	_, err = applyChainProfile(newCmd("--chain-profile=mainnet"), clientCtx)
+	require.ErrorContains(t, err, `unknown chain profile "mainnet"`)
+}
@@ -51,6 +51,32 @@ func (s *IntegrationTestSuite) TestStatusCommand() {
 	s.Require().Contains(out.String(), fmt.Sprintf("\"moniker\":\"%s\"", validator.Moniker))
 }
 
+func (s *IntegrationTestSuite) TestValidatorCommandEnrich() {
+	validator := s.network.Validators[0]
+
+	out, err := clitestutil.ExecTestCLICmd(validator.ClientCtx, rpc.ValidatorCommand(), []string{"--output=json"})
+	s.Require().NoError(err)
+	s.Require().NotContains(out.String(), "\"staking\"")
+
+	out, err = clitestutil.ExecTestCLICmd(validator.ClientCtx, rpc.ValidatorCommand(), []string{"--enrich", "--output=json"})
+	s.Require().NoError(err)
+	s.Require().Contains(out.String(), fmt.Sprintf("\"moniker\":\"%s\"", validator.Moniker))
+	s.Require().Contains(out.String(), fmt.Sprintf("\"operator_address\":\"%s\"", validator.ValAddress))
+	s.Require().Contains(out.String(), "\"jailed\":false")
+}
+
+func (s *IntegrationTestSuite) TestSnapshotsOfferedCommand() {
+	validator := s.network.Validators[0]
+
+	out, err := clitestutil.ExecTestCLICmd(validator.ClientCtx, rpc.SnapshotsOfferedCommand(), []string{"--output=json"})
+	s.Require().NoError(err)
+	s.Require().Contains(out.String(), "\"snapshots\"")
+
+	res, err := rpc.QuerySnapshotsOffered(validator.ClientCtx)
+	s.Require().NoError(err)
+	s.Require().Empty(res.Snapshots)
+}
+
 func (s *IntegrationTestSuite) TestGRPCQuery() {
 	var header metadata.MD
 	validator := s.network.Validators[0]
@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SinkMessage is a message published to an EventSink.
+type SinkMessage struct {
+	// Key identifies the message to the broker, e.g. to pick a Kafka
+	// partition.
+	Key   []byte
+	Value []byte
+}
+
+// EventSink is a message broker the events of a subscription are exported
+// to. Publish must only return nil once the broker acknowledged all the
+// messages, since their offsets are checkpointed then: a message which was not
+// acknowledged is published again after a restart, which makes the delivery
+// at-least-once.
+type EventSink interface {
+	Publish(ctx context.Context, msgs []SinkMessage) error
+	Close() error
+}
+
+// EventSinkOpener opens the EventSink of a sink URL.
+type EventSinkOpener func(u *url.URL) (EventSink, error)
+
+var (
+	eventSinksMtx sync.RWMutex
+	eventSinks    = make(map[string]EventSinkOpener)
+)
+
+// RegisterEventSink registers the opener of the sink URLs of scheme, usually
+// from the init function of the package of the sink, like the kafkasink and
+// natssink packages. It replaces the opener registered before for scheme, if
+// any.
+func RegisterEventSink(scheme string, open EventSinkOpener) {
+	eventSinksMtx.Lock()
+	defer eventSinksMtx.Unlock()
+
+	eventSinks[strings.ToLower(scheme)] = open
+}
+
+// OpenEventSink opens the sink of a URL, e.g. kafka://localhost:9092/events
+// or nats://localhost:4222/chain.events, with the opener registered for its
+// scheme.
+func OpenEventSink(rawURL string) (EventSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sink URL: %w", err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid sink URL %q: no broker address", rawURL)
+	}
+
+	eventSinksMtx.RLock()
+	open, ok := eventSinks[strings.ToLower(u.Scheme)]
+	eventSinksMtx.RUnlock()
+	if !ok {
+		schemes := eventSinkSchemes()
+		if len(schemes) == 0 {
+			return nil, fmt.Errorf("unsupported sink %q, no sink is registered by the app", u.Scheme)
+		}
+		return nil, fmt.Errorf("unsupported sink %q, expected one of %s", u.Scheme, strings.Join(schemes, ", "))
+	}
+	return open(u)
+}
+
+func eventSinkSchemes() []string {
+	eventSinksMtx.RLock()
+	defer eventSinksMtx.RUnlock()
+
+	schemes := make([]string, 0, len(eventSinks))
+	for scheme := range eventSinks {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// SinkTopic returns the topic, or subject, of a sink URL: its path without
+// the leading slash.
+func SinkTopic(u *url.URL) (string, error) {
+	topic := strings.TrimPrefix(u.Path, "/")
+	if topic == "" || strings.Contains(topic, "/") {
+		return "", fmt.Errorf("invalid sink URL %s: expected a single topic in the path, e.g. %s://%s/events", u.Redacted(), u.Scheme, u.Host)
+	}
+	return topic, nil
+}
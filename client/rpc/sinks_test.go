@@ -0,0 +1,35 @@
+package rpc
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenEventSink(t *testing.T) {
+	_, err := OpenEventSink("amqp://localhost:5672/events")
+	require.ErrorContains(t, err, "unsupported sink")
+
+	_, err = OpenEventSink("test:///events")
+	require.ErrorContains(t, err, "no broker address")
+
+	// the sinks are opened with the opener registered for their scheme
+	var opened *url.URL
+	RegisterEventSink("test", func(u *url.URL) (EventSink, error) {
+		opened = u
+		return &recordingSink{}, nil
+	})
+	_, err = OpenEventSink("test://broker/events")
+	require.NoError(t, err)
+	require.Equal(t, "broker", opened.Host)
+	topic, err := SinkTopic(opened)
+	require.NoError(t, err)
+	require.Equal(t, "events", topic)
+
+	_, err = OpenEventSink("amqp://localhost:5672/events")
+	require.ErrorContains(t, err, "expected one of test")
+
+	_, err = SinkTopic(&url.URL{Scheme: "test", Host: "broker", Path: "/a/b"})
+	require.ErrorContains(t, err, "expected a single topic")
+}
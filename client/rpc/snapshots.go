@@ -0,0 +1,122 @@
+package rpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	abci "github.com/baron-chain/cometbft-bc/abci/types"
+	"github.com/baron-chain/cometbft-bc/libs/bytes"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+)
+
+// snapshotsQueryPath is the ABCI query path under which the app lists the
+// snapshots it offers to state-syncing peers.
+const snapshotsQueryPath = "/app/snapshots"
+
+// SnapshotOffering is a snapshot served by a node for state sync.
+type SnapshotOffering struct {
+	Height uint64         `json:"height"`
+	Format uint32         `json:"format"`
+	Chunks uint32         `json:"chunks"`
+	Hash   bytes.HexBytes `json:"hash"`
+}
+
+// SnapshotsOfferedOutput lists the snapshots served by a node.
+type SnapshotsOfferedOutput struct {
+	Node      string             `json:"node"`
+	Snapshots []SnapshotOffering `json:"snapshots"`
+}
+
+func (so SnapshotsOfferedOutput) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Node: %s\n", so.Node)
+	fmt.Fprintf(&b, "Total Snapshots: %d\n", len(so.Snapshots))
+
+	for _, snapshot := range so.Snapshots {
+		fmt.Fprintf(&b, "\nSnapshot Details:\n")
+		fmt.Fprintf(&b, "  Height: %d\n", snapshot.Height)
+		fmt.Fprintf(&b, "  Format: %d\n", snapshot.Format)
+		fmt.Fprintf(&b, "  Chunks: %d\n", snapshot.Chunks)
+		fmt.Fprintf(&b, "  Hash:   %s\n", snapshot.Hash)
+	}
+
+	return b.String()
+}
+
+// SnapshotsOfferedCommand returns the command listing the snapshots a node
+// serves to state-syncing peers.
+func SnapshotsOfferedCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshots-offered",
+		Short: "List the snapshots a Baron Chain node offers for state sync",
+		Long: `List the snapshots a node serves to state-syncing peers, newest first, with
+their height, format and number of chunks. Query the RPC endpoint of a candidate
+peer with --node to check it can be used as a state-sync source.`,
+		Example: "$ barond query snapshots-offered --node tcp://peer.example.com:26657",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get query context: %w", err)
+			}
+
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
+			result, err := QuerySnapshotsOffered(clientCtx)
+			if err != nil {
+				return fmt.Errorf("failed to query snapshots: %w", err)
+			}
+
+			return printObject(cmd, clientCtx, result)
+		},
+	}
+
+	cmd.Flags().String(flags.FlagNode, defaultNodeEndpoint, "Baron Chain node RPC endpoint")
+	cmd.Flags().StringP(flags.FlagOutput, "o", "text", "Output format (text|json)")
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
+
+	return cmd
+}
+
+// QuerySnapshotsOffered returns the snapshots served by the client context node.
+func QuerySnapshotsOffered(clientCtx client.Context) (SnapshotsOfferedOutput, error) {
+	bz, _, err := clientCtx.Query(snapshotsQueryPath)
+	if err != nil {
+		return SnapshotsOfferedOutput{}, err
+	}
+
+	var res abci.ResponseListSnapshots
+	if err := res.Unmarshal(bz); err != nil {
+		return SnapshotsOfferedOutput{}, fmt.Errorf("failed to decode snapshots: %w", err)
+	}
+
+	snapshots := make([]SnapshotOffering, 0, len(res.Snapshots))
+	for _, snapshot := range res.Snapshots {
+		snapshots = append(snapshots, SnapshotOffering{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunks: snapshot.Chunks,
+			Hash:   snapshot.Hash,
+		})
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if snapshots[i].Height != snapshots[j].Height {
+			return snapshots[i].Height > snapshots[j].Height
+		}
+		return snapshots[i].Format > snapshots[j].Format
+	})
+
+	return SnapshotsOfferedOutput{
+		Node:      clientCtx.NodeURI,
+		Snapshots: snapshots,
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/cosmos/gogoproto/proto"
 	"github.com/spf13/cobra"
 	"github.com/baron-chain/cometbft-bc/libs/bytes"
 	"github.com/baron-chain/cometbft-bc/p2p"
@@ -18,6 +19,10 @@ import (
 const (
 	defaultNodeEndpoint = "tcp://localhost:26657"
 	flagNode           = "node"
+
+	// capabilitiesQueryPath is the ABCI query path under which the app
+	// advertises its signature algorithms and snapshot settings.
+	capabilitiesQueryPath = "/app/capabilities"
 )
 
 type ValidatorInfo struct {
@@ -30,6 +35,31 @@ type NodeStatus struct {
 	NodeInfo      p2p.DefaultNodeInfo `json:"node_info"`
 	SyncInfo      coretypes.SyncInfo  `json:"sync_info"`
 	ValidatorInfo ValidatorInfo       `json:"validator_info"`
+	// Capabilities is nil if the node doesn't advertise them.
+	Capabilities *NodeCapabilities `json:"capabilities,omitempty"`
+}
+
+// NodeCapabilities are the capabilities advertised by the app of a node.
+type NodeCapabilities struct {
+	// SignatureAlgorithms are the type URLs of the public keys whose
+	// signatures the app verifies, e.g. "/cosmos.crypto.secp256k1.PubKey".
+	SignatureAlgorithms []string `json:"signature_algorithms"`
+	// SnapshotInterval and SnapshotKeepRecent are the state-sync snapshot
+	// settings of the node, zero if it takes no snapshots.
+	SnapshotInterval   uint64 `json:"snapshot_interval"`
+	SnapshotKeepRecent uint32 `json:"snapshot_keep_recent"`
+}
+
+// SupportsPubKey reports whether the node verifies signatures of the public
+// key type, e.g. before sending a transaction signed with a dilithium key.
+func (c NodeCapabilities) SupportsPubKey(pub cryptotypes.PubKey) bool {
+	typeURL := "/" + proto.MessageName(pub)
+	for _, algo := range c.SignatureAlgorithms {
+		if algo == typeURL {
+			return true
+		}
+	}
+	return false
 }
 
 func StatusCommand() *cobra.Command {
@@ -43,6 +73,10 @@ func StatusCommand() *cobra.Command {
 				return fmt.Errorf("failed to get query context: %w", err)
 			}
 
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
 			status, err := queryNodeStatus(clientCtx)
 			if err != nil {
 				return err
@@ -63,17 +97,24 @@ func StatusCommand() *cobra.Command {
 				},
 			}
 
+			// nodes running an older app don't advertise capabilities
+			if caps, err := QueryNodeCapabilities(clientCtx); err == nil {
+				nodeStatus.Capabilities = &caps
+			}
+
 			output, err := json.MarshalIndent(nodeStatus, "", "  ")
 			if err != nil {
 				return fmt.Errorf("failed to marshal status: %w", err)
 			}
 
-			return clientCtx.PrintBytes(output)
+			return printJSON(cmd, clientCtx, output)
 		},
 	}
 
 	cmd.Flags().StringP(flagNode, "n", defaultNodeEndpoint, "Baron Chain node to connect to")
 	flags.AddQueryFlagsToCmd(cmd)
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
 
 	return cmd
 }
@@ -86,12 +127,33 @@ func queryNodeStatus(clientCtx client.Context) (*coretypes.ResultStatus, error)
 
 	status, err := node.Status(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("failed to query node status: %w", err)
+		return nil, wrapRPCError("query node status", err)
 	}
 
 	return status, nil
 }
 
+// QueryNodeCapabilities returns the capabilities advertised by the app of the
+// client context node.
+func QueryNodeCapabilities(clientCtx client.Context) (NodeCapabilities, error) {
+	bz, _, err := clientCtx.Query(capabilitiesQueryPath)
+	if err != nil {
+		return NodeCapabilities{}, wrapRPCError("query node capabilities", err)
+	}
+	return parseNodeCapabilities(bz)
+}
+
+func parseNodeCapabilities(bz []byte) (NodeCapabilities, error) {
+	var caps NodeCapabilities
+	if err := json.Unmarshal(bz, &caps); err != nil {
+		return NodeCapabilities{}, fmt.Errorf("failed to decode capabilities: %w", err)
+	}
+	if caps.SignatureAlgorithms == nil {
+		caps.SignatureAlgorithms = []string{}
+	}
+	return caps, nil
+}
+
 func convertValidatorPubKey(status *coretypes.ResultStatus) (cryptotypes.PubKey, error) {
 	if status.ValidatorInfo.PubKey == nil {
 		return nil, nil
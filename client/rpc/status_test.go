@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/baron-chain/cosmos-bc-47/crypto/keys/ed25519"
+	"github.com/baron-chain/cosmos-bc-47/crypto/keys/secp256k1"
+)
+
+func TestParseNodeCapabilities(t *testing.T) {
+	caps, err := parseNodeCapabilities([]byte(`{"signature_algorithms":["/cosmos.crypto.secp256k1.PubKey"],"snapshot_interval":1000,"snapshot_keep_recent":2}`))
+	require.NoError(t, err)
+	require.Equal(t, NodeCapabilities{
+		SignatureAlgorithms: []string{"/cosmos.crypto.secp256k1.PubKey"},
+		SnapshotInterval:    1000,
+		SnapshotKeepRecent:  2,
+	}, caps)
+
+	require.True(t, caps.SupportsPubKey(secp256k1.GenPrivKey().PubKey()))
+	require.False(t, caps.SupportsPubKey(ed25519.GenPrivKey().PubKey()))
+
+	caps, err = parseNodeCapabilities([]byte(`{}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{}, caps.SignatureAlgorithms)
+
+	_, err = parseNodeCapabilities([]byte("unknown query"))
+	require.ErrorContains(t, err, "failed to decode capabilities")
+}
+
+func TestNodeStatusOmitsMissingCapabilities(t *testing.T) {
+	bz, err := json.Marshal(NodeStatus{})
+	require.NoError(t, err)
+	require.NotContains(t, string(bz), "capabilities")
+
+	bz, err = json.Marshal(NodeStatus{Capabilities: &NodeCapabilities{SnapshotInterval: 500}})
+	require.NoError(t, err)
+	require.Contains(t, string(bz), `"capabilities":{"signature_algorithms":null,"snapshot_interval":500,"snapshot_keep_recent":0}`)
+}
@@ -0,0 +1,219 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	abci "github.com/baron-chain/cometbft-bc/abci/types"
+	"github.com/baron-chain/cometbft-bc/crypto/merkle"
+	"github.com/baron-chain/cometbft-bc/libs/bytes"
+	tmcrypto "github.com/baron-chain/cometbft-bc/proto/tendermint/crypto"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+	"github.com/baron-chain/cosmos-bc-47/store/rootmulti"
+)
+
+const (
+	// VerificationVerified is the verification status of a proof which was
+	// verified against the app hash of the next block.
+	VerificationVerified = "verified"
+	// VerificationFailed is the verification status of an invalid proof.
+	VerificationFailed = "failed"
+	// VerificationUnavailable is the verification status of a proof which
+	// couldn't be verified, e.g. as the next block is not committed yet.
+	VerificationUnavailable = "unavailable"
+)
+
+// StoreProofOp is a proof operation of a store query.
+type StoreProofOp struct {
+	Type string         `json:"type"`
+	Key  bytes.HexBytes `json:"key"`
+	Data bytes.HexBytes `json:"data"`
+}
+
+// StoreQueryOutput is the value of a key of a store, with its proof when
+// requested.
+type StoreQueryOutput struct {
+	Store  string         `json:"store"`
+	Key    bytes.HexBytes `json:"key"`
+	Height int64          `json:"height"`
+	Exists bool           `json:"exists"`
+	Value  bytes.HexBytes `json:"value,omitempty"`
+
+	ProofOps []StoreProofOp `json:"proof_ops,omitempty"`
+	// AppHash is the app hash of the next block, which commits to the state
+	// at Height, the proof was verified against.
+	AppHash            bytes.HexBytes `json:"app_hash,omitempty"`
+	Verification       string         `json:"verification,omitempty"`
+	VerificationDetail string         `json:"verification_detail,omitempty"`
+}
+
+func (so StoreQueryOutput) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Store:  %s\n", so.Store)
+	fmt.Fprintf(&b, "Key:    %s\n", so.Key)
+	fmt.Fprintf(&b, "Height: %d\n", so.Height)
+	if so.Exists {
+		fmt.Fprintf(&b, "Value:  %s\n", so.Value)
+	} else {
+		fmt.Fprintf(&b, "Value:  <not found>\n")
+	}
+
+	if len(so.ProofOps) > 0 {
+		fmt.Fprintf(&b, "\nProof Ops:\n")
+		for _, op := range so.ProofOps {
+			fmt.Fprintf(&b, "  %s key=%s (%d bytes)\n", op.Type, op.Key, len(op.Data))
+		}
+	}
+	if so.Verification != "" {
+		fmt.Fprintf(&b, "\nApp Hash:     %s\n", so.AppHash)
+		fmt.Fprintf(&b, "Verification: %s\n", so.Verification)
+		if so.VerificationDetail != "" {
+			fmt.Fprintf(&b, "Detail:       %s\n", so.VerificationDetail)
+		}
+	}
+
+	return b.String()
+}
+
+// StoreQueryCommand returns the command querying the raw value of a key of a
+// store, optionally with its merkle proof.
+func StoreQueryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store <store-key> <hex-key>",
+		Short: "Query the raw value of a key of a Baron Chain store",
+		Long: `Query the raw value of a key of a module store, such as acc or bank, with an ABCI
+store query, so that state can be inspected without writing Go code. The key is hex
+encoded, and so is the value printed.
+
+With --prove, the node returns the merkle proof of the value, or of its absence, which
+is printed and verified against the app hash of the next block, which commits to the
+state at the queried height. The app hash is read from the same node, so the
+verification doesn't protect against a malicious node, but detects inconsistent
+state. The proof is unavailable until the next block is committed, query an older
+--height to verify it.`,
+		Example: `$ barond query store acc 01<hex-address>
+$ barond query store bank 00<hex-denom> --height 1200 --prove`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get query context: %w", err)
+			}
+
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
+			key, err := hex.DecodeString(strings.TrimPrefix(args[1], "0x"))
+			if err != nil {
+				return fmt.Errorf("invalid hex key %q: %w", args[1], err)
+			}
+
+			prove, _ := cmd.Flags().GetBool(flags.FlagProve)
+			result, err := QueryStore(cmd.Context(), clientCtx, args[0], key, prove)
+			if err != nil {
+				return err
+			}
+
+			return printObject(cmd, clientCtx, result)
+		},
+	}
+
+	cmd.Flags().String(flags.FlagNode, defaultNodeEndpoint, "Baron Chain node RPC endpoint")
+	cmd.Flags().StringP(flags.FlagOutput, "o", "text", "Output format (text|json)")
+	cmd.Flags().Int64(flags.FlagHeight, 0, "Height to query the state at (the node must not have pruned it)")
+	cmd.Flags().Bool(flags.FlagProve, false, "Query and verify the merkle proof of the value")
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
+
+	return cmd
+}
+
+// QueryStore queries the value of the key of the store at the client context
+// height. With prove, the proof of the value is verified against the app hash
+// of the next block.
+func QueryStore(ctx context.Context, clientCtx client.Context, storeKey string, key []byte, prove bool) (StoreQueryOutput, error) {
+	if storeKey == "" || strings.Contains(storeKey, "/") {
+		return StoreQueryOutput{}, fmt.Errorf("invalid store key %q", storeKey)
+	}
+	if len(key) == 0 {
+		return StoreQueryOutput{}, errors.New("empty key")
+	}
+
+	res, err := clientCtx.QueryABCI(abci.RequestQuery{
+		Path:   fmt.Sprintf("/store/%s/key", storeKey),
+		Data:   key,
+		Height: clientCtx.Height,
+		Prove:  prove,
+	})
+	if err != nil {
+		return StoreQueryOutput{}, fmt.Errorf("failed to query store %s: %w", storeKey, err)
+	}
+
+	out := StoreQueryOutput{
+		Store:  storeKey,
+		Key:    key,
+		Height: res.Height,
+		Exists: res.Value != nil,
+		Value:  res.Value,
+	}
+	if !prove {
+		return out, nil
+	}
+
+	if res.ProofOps == nil || len(res.ProofOps.Ops) == 0 {
+		out.Verification = VerificationUnavailable
+		out.VerificationDetail = "the node returned no proof"
+		return out, nil
+	}
+	for _, op := range res.ProofOps.Ops {
+		out.ProofOps = append(out.ProofOps, StoreProofOp{Type: op.Type, Key: op.Key, Data: op.Data})
+	}
+
+	node, err := clientCtx.GetNode()
+	if err != nil {
+		return StoreQueryOutput{}, fmt.Errorf("failed to get node: %w", err)
+	}
+	nextHeight := res.Height + 1
+	commit, err := node.Commit(ctx, &nextHeight)
+	if err != nil {
+		out.Verification = VerificationUnavailable
+		out.VerificationDetail = fmt.Sprintf("failed to get the commit of block %d: %v", nextHeight, err)
+		return out, nil
+	}
+
+	out.AppHash = commit.AppHash
+	if err := VerifyStoreProof(storeKey, key, res.Value, res.ProofOps, commit.AppHash); err != nil {
+		out.Verification = VerificationFailed
+		out.VerificationDetail = err.Error()
+		return out, nil
+	}
+	out.Verification = VerificationVerified
+	return out, nil
+}
+
+// VerifyStoreProof verifies the proof that the key of the store has the value,
+// or doesn't exist when value is nil, in the state committed to by appHash.
+func VerifyStoreProof(storeKey string, key, value []byte, proofOps *tmcrypto.ProofOps, appHash []byte) error {
+	if proofOps == nil {
+		return errors.New("no proof")
+	}
+
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(storeKey), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL).
+		String()
+
+	prt := rootmulti.DefaultProofRuntime()
+	if value == nil {
+		return prt.VerifyAbsence(proofOps, appHash, keyPath)
+	}
+	return prt.VerifyValue(proofOps, appHash, keyPath, value)
+}
@@ -0,0 +1,34 @@
+package rpc
+
+import (
+	"testing"
+
+	abci "github.com/baron-chain/cometbft-bc/abci/types"
+	"github.com/baron-chain/cometbft-bc/libs/log"
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/require"
+
+	"github.com/baron-chain/cosmos-bc-47/store/rootmulti"
+	storetypes "github.com/baron-chain/cosmos-bc-47/store/types"
+)
+
+func TestVerifyStoreProof(t *testing.T) {
+	store := rootmulti.NewStore(dbm.NewMemDB(), log.NewNopLogger())
+	key := storetypes.NewKVStoreKey("acc")
+	store.MountStoreWithDB(key, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadLatestVersion())
+	store.GetKVStore(key).Set([]byte("key"), []byte("value"))
+	commit := store.Commit()
+
+	res := store.Query(abci.RequestQuery{Path: "/acc/key", Data: []byte("key"), Height: commit.Version, Prove: true})
+	require.Equal(t, []byte("value"), res.Value)
+	require.NoError(t, VerifyStoreProof("acc", []byte("key"), res.Value, res.ProofOps, commit.Hash))
+	require.Error(t, VerifyStoreProof("acc", []byte("key"), []byte("other"), res.ProofOps, commit.Hash))
+	require.Error(t, VerifyStoreProof("bank", []byte("key"), res.Value, res.ProofOps, commit.Hash))
+
+	// the absence of a key is proven too
+	res = store.Query(abci.RequestQuery{Path: "/acc/key", Data: []byte("missing"), Height: commit.Version, Prove: true})
+	require.Nil(t, res.Value)
+	require.NoError(t, VerifyStoreProof("acc", []byte("missing"), nil, res.ProofOps, commit.Hash))
+	require.Error(t, VerifyStoreProof("acc", []byte("missing"), nil, res.ProofOps, []byte("app hash")))
+}
@@ -0,0 +1,610 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	abci "github.com/baron-chain/cometbft-bc/abci/types"
+	tmquery "github.com/baron-chain/cometbft-bc/libs/pubsub/query"
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+)
+
+const (
+	flagSink          = "sink"
+	flagCheckpoint    = "checkpoint"
+	flagSinkBatchSize = "batch-size"
+
+	defaultSinkBatchSize = 100
+	// subscribeBufferSize is the number of events buffered while they are
+	// printed, beyond which events are dropped.
+	subscribeBufferSize = 1000
+	checkpointsDir      = "subscriptions"
+	// exportPollInterval is the interval at which the node is polled for new
+	// blocks when exporting events, in case a new block event is missed.
+	exportPollInterval  = 5 * time.Second
+	newBlockHeaderQuery = "tm.event='NewBlockHeader'"
+)
+
+// The streams of the events of a subscription. Each stream has its own
+// offsets, and its own checkpoint when exported to a sink.
+const (
+	// TxStream is the stream of the Tx events, whose offsets are the height
+	// and index of their tx.
+	TxStream = "tx"
+	// BlockStream is the stream of the other events, whose offsets are their
+	// height and their order among the events of that height.
+	BlockStream = "block"
+)
+
+// The indexes of the block events in the block stream of their height, the
+// other events following them.
+const (
+	newBlockIndex       = 0
+	newBlockHeaderIndex = 1
+)
+
+// ErrSubscriptionClosed is returned when a subscription is closed while its
+// events are exported.
+var ErrSubscriptionClosed = errors.New("subscription closed")
+
+// EventOffset is the position of an event in its stream: its height, and the
+// index of the tx for tx events, or else the order of the event among the
+// events of the same height.
+type EventOffset struct {
+	Height int64 `json:"height,string"`
+	Index  int64 `json:"index"`
+}
+
+// After reports whether the offset is past other.
+func (o EventOffset) After(other EventOffset) bool {
+	return o.Height > other.Height || (o.Height == other.Height && o.Index > other.Index)
+}
+
+func (o EventOffset) String() string {
+	return fmt.Sprintf("%d:%d", o.Height, o.Index)
+}
+
+// SubscriptionEvent is an event of a subscription, with its tx and ABCI
+// events decoded.
+type SubscriptionEvent struct {
+	Query string `json:"query"`
+	// Stream is the stream of the event, tx or block.
+	Stream string      `json:"stream"`
+	Offset EventOffset `json:"offset"`
+	// Type is the tm.event of the event, e.g. Tx or NewBlock.
+	Type   string             `json:"type"`
+	Tx     *DecodedTxResponse `json:"tx,omitempty"`
+	Events []DecodedEvent     `json:"events,omitempty"`
+}
+
+// Key returns the key of the event, unique across its streams.
+func (e SubscriptionEvent) Key() string {
+	return e.Stream + "/" + e.Offset.String()
+}
+
+// EventExportNode is the node the events exported to a sink are read from,
+// implemented by the CometBFT HTTP client.
+type EventExportNode interface {
+	Status(ctx context.Context) (*coretypes.ResultStatus, error)
+	Block(ctx context.Context, height *int64) (*coretypes.ResultBlock, error)
+	BlockResults(ctx context.Context, height *int64) (*coretypes.ResultBlockResults, error)
+}
+
+// SubscribeCommand returns the command printing the events of a query as they
+// happen, or exporting them to a message broker.
+func SubscribeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subscribe <query>",
+		Short: "Print the events of a Baron Chain node matching a query, or export them to a message broker",
+		Long: `Subscribe to the events matching the query over the websocket of the node, and print
+them as JSON lines, with their tx and typed events decoded. Events are dropped when they
+are not printed fast enough.
+
+With --sink, the Tx, NewBlock and NewBlockHeader events matching the query are exported
+to a message broker instead, in batches of at most --batch-size events. They are read from
+the blocks of the node, height after height, so that none is missed while the connection
+to the node is lost. Each batch is published to kafka://host:port/topic or
+nats://[user:password@]host:port/subject, and once the broker acknowledged it, the last
+height exported and the offset of the last event of each stream, tx and block, are saved
+to the --checkpoint file. The export resumes from the checkpoint on restart, so that every
+event is delivered at least once; consumers can deduplicate them by key, which is
+<stream>/<height>:<index>. The first export starts after the latest block of the node.
+
+The kafka and nats sinks are available in the apps importing the client/rpc/kafkasink and
+client/rpc/natssink packages.`,
+		Example: `$ barond query subscribe "tm.event='Tx'"
+$ barond query subscribe "tm.event='Tx' AND message.module='bank'" --sink kafka://localhost:9092/bank-txs
+$ barond query subscribe "tm.event='NewBlock'" --sink "nats://localhost:4222/chain.blocks?jetstream=true"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get query context: %w", err)
+			}
+
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
+			query := args[0]
+			batchSize, _ := cmd.Flags().GetInt(flagSinkBatchSize)
+			if batchSize <= 0 || batchSize > subscribeBufferSize {
+				return fmt.Errorf("--%s must be between 1 and %d, got %d", flagSinkBatchSize, subscribeBufferSize, batchSize)
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			sinkURL, _ := cmd.Flags().GetString(flagSink)
+			if sinkURL == "" {
+				manager, err := NewSubscriptionManager(NewWebsocketDialer(clientCtx.NodeURI), SubscriptionManagerOptions{
+					BufferSize: subscribeBufferSize,
+				})
+				if err != nil {
+					return err
+				}
+				defer manager.Close() //nolint:errcheck
+
+				sub, err := subscribeWithTimeout(ctx, manager, query)
+				if err != nil {
+					return err
+				}
+				return printEvents(ctx, clientCtx, sub, cmd.OutOrStdout(), cmd.ErrOrStderr())
+			}
+
+			q, err := tmquery.New(query)
+			if err != nil {
+				return fmt.Errorf("invalid query %q: %w", query, err)
+			}
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return err
+			}
+			exportNode, ok := node.(EventExportNode)
+			if !ok {
+				return fmt.Errorf("the node client %T doesn't support block results queries", node)
+			}
+
+			sink, err := OpenEventSink(sinkURL)
+			if err != nil {
+				return err
+			}
+			defer sink.Close() //nolint:errcheck
+
+			path, _ := cmd.Flags().GetString(flagCheckpoint)
+			if path == "" {
+				path = defaultCheckpointPath(clientCtx.HomeDir, query, sinkURL)
+			}
+			checkpoint, err := loadSinkCheckpoint(path, query, sinkURL)
+			if err != nil {
+				return err
+			}
+			if checkpoint.Height > 0 {
+				cmd.PrintErrf("Resuming after height %d of %s\n", checkpoint.Height, path)
+			}
+
+			// the new block events only wake the export up, so that missing
+			// some of them is harmless
+			manager, err := NewSubscriptionManager(NewWebsocketDialer(clientCtx.NodeURI), SubscriptionManagerOptions{
+				BufferSize: 1,
+			})
+			if err != nil {
+				return err
+			}
+			defer manager.Close() //nolint:errcheck
+
+			sub, err := subscribeWithTimeout(ctx, manager, newBlockHeaderQuery)
+			if err != nil {
+				return err
+			}
+
+			return exportEvents(ctx, clientCtx, exportNode, q, sub.Events(), sink, checkpoint, batchSize, cmd.ErrOrStderr())
+		},
+	}
+
+	cmd.Flags().String(flags.FlagNode, defaultNodeEndpoint, "Baron Chain node RPC endpoint")
+	cmd.Flags().String(flagSink, "", "Export the events to a message broker, kafka://host:port/topic or nats://host:port/subject")
+	cmd.Flags().String(flagCheckpoint, "", "File the progress of the export to --sink is saved to (default <home>/subscriptions/<hash of the query and sink>.json)")
+	cmd.Flags().Int(flagSinkBatchSize, defaultSinkBatchSize, "Maximum number of events published to --sink at once")
+	addChainProfileFlag(cmd)
+
+	return cmd
+}
+
+func subscribeWithTimeout(ctx context.Context, manager *SubscriptionManager, query string) (*Subscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	return manager.Subscribe(ctx, query)
+}
+
+// printEvents prints the events of sub as JSON lines to w, as soon as they
+// are received, until ctx is done.
+func printEvents(ctx context.Context, clientCtx client.Context, sub *Subscription, w, warn io.Writer) error {
+	var (
+		offsets offsetTracker
+		dropped uint64
+	)
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return ErrSubscriptionClosed
+			}
+			msg, _, _, err := encodeSubscriptionEvent(clientCtx, sub.Query(), event, &offsets)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "%s\n", msg.Value); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+
+		if n := sub.Dropped(); n > dropped {
+			fmt.Fprintf(warn, "Warning: %d events were dropped because they are not printed fast enough\n", n-dropped)
+			dropped = n
+		}
+	}
+}
+
+// exportEvents publishes the events matching q of the blocks of the node to
+// sink in batches, height after height from the height of the checkpoint,
+// until ctx is done. The heights are read when wake receives an event, or at
+// least every exportPollInterval. The checkpoint is saved once the sink
+// acknowledged the events, and the events up to its offsets are skipped.
+func exportEvents(ctx context.Context, clientCtx client.Context, node EventExportNode, q *tmquery.Query, wake <-chan coretypes.ResultEvent, sink EventSink, checkpoint *sinkCheckpoint, batchSize int, warn io.Writer) error {
+	if checkpoint.Height == 0 {
+		status, err := node.Status(ctx)
+		if err != nil {
+			return wrapRPCError("query node status", err)
+		}
+		checkpoint.Height = status.SyncInfo.LatestBlockHeight
+	}
+
+	var (
+		offsets offsetTracker
+		batch   []SinkMessage
+		// published are the offsets of the last event of each stream of the
+		// batch
+		published = make(map[string]EventOffset)
+		saved     = checkpoint.Height
+	)
+	// publish publishes the batch, then saves the checkpoint, which covers
+	// all the events published so far
+	publish := func() error {
+		if len(batch) > 0 {
+			if err := sink.Publish(ctx, batch); err != nil {
+				return fmt.Errorf("failed to publish the events up to height %d: %w", checkpoint.Height, err)
+			}
+			for stream, offset := range published {
+				checkpoint.Offsets[stream] = offset
+			}
+			batch, published = nil, make(map[string]EventOffset)
+		} else if checkpoint.Height == saved {
+			return nil
+		}
+
+		if err := checkpoint.save(); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+		saved = checkpoint.Height
+		return nil
+	}
+
+	// export exports the events of the heights committed since the
+	// checkpoint
+	export := func() error {
+		status, err := node.Status(ctx)
+		if err != nil {
+			return wrapRPCError("query node status", err)
+		}
+
+		for height := checkpoint.Height + 1; height <= status.SyncInfo.LatestBlockHeight; height++ {
+			events, err := queryBlockEvents(ctx, node, height)
+			if err != nil {
+				return err
+			}
+			for _, event := range events {
+				if ok, err := q.Matches(event.Events); err != nil || !ok {
+					continue
+				}
+				msg, stream, offset, err := encodeSubscriptionEvent(clientCtx, q.String(), event, &offsets)
+				if err != nil {
+					return err
+				}
+				if checkpoint.published(stream, offset) {
+					continue
+				}
+
+				batch = append(batch, msg)
+				published[stream] = offset
+				if len(batch) >= batchSize {
+					if err := publish(); err != nil {
+						return err
+					}
+				}
+			}
+			checkpoint.Height = height
+		}
+		return publish()
+	}
+
+	poll := time.NewTicker(exportPollInterval)
+	defer poll.Stop()
+	for {
+		if err := export(); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !errors.Is(err, ErrNodeUnreachable) && !errors.Is(err, ErrTimeout) {
+				return err
+			}
+			// the events not published yet are read again once the node is
+			// back
+			checkpoint.Height, batch, published = saved, nil, make(map[string]EventOffset)
+			fmt.Fprintf(warn, "Warning: %v, retrying\n", err)
+		}
+
+		select {
+		case _, ok := <-wake:
+			if !ok {
+				return ErrSubscriptionClosed
+			}
+		case <-poll.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// queryBlockEvents returns the events of the block at height, as published
+// by the node when the block was committed: the NewBlock and NewBlockHeader
+// events, then the Tx event of each tx.
+func queryBlockEvents(ctx context.Context, node EventExportNode, height int64) ([]coretypes.ResultEvent, error) {
+	block, err := node.Block(ctx, &height)
+	if err != nil {
+		return nil, wrapRPCError(fmt.Sprintf("query block %d", height), err)
+	}
+	results, err := node.BlockResults(ctx, &height)
+	if err != nil {
+		return nil, wrapRPCError(fmt.Sprintf("query block results %d", height), err)
+	}
+	if block.Block == nil || len(results.TxsResults) != len(block.Block.Txs) {
+		return nil, fmt.Errorf("the results of block %d don't match its txs", height)
+	}
+
+	begin := abci.ResponseBeginBlock{Events: results.BeginBlockEvents}
+	end := abci.ResponseEndBlock{
+		Events:                results.EndBlockEvents,
+		ValidatorUpdates:      results.ValidatorUpdates,
+		ConsensusParamUpdates: results.ConsensusParamUpdates,
+	}
+	blockEvents := append(append([]abci.Event{}, results.BeginBlockEvents...), results.EndBlockEvents...)
+
+	events := []coretypes.ResultEvent{
+		{
+			Data:   tmtypes.EventDataNewBlock{Block: block.Block, ResultBeginBlock: begin, ResultEndBlock: end},
+			Events: compositeEvents(blockEvents, tmtypes.EventNewBlock),
+		},
+		{
+			Data: tmtypes.EventDataNewBlockHeader{
+				Header:           block.Block.Header,
+				NumTxs:           int64(len(block.Block.Txs)),
+				ResultBeginBlock: begin,
+				ResultEndBlock:   end,
+			},
+			Events: compositeEvents(blockEvents, tmtypes.EventNewBlockHeader),
+		},
+	}
+	for i, tx := range block.Block.Txs {
+		result := results.TxsResults[i]
+		txEvents := compositeEvents(result.Events, tmtypes.EventTx)
+		txEvents[tmtypes.TxHashKey] = []string{fmt.Sprintf("%X", tx.Hash())}
+		txEvents[tmtypes.TxHeightKey] = []string{fmt.Sprintf("%d", height)}
+
+		events = append(events, coretypes.ResultEvent{
+			Data: tmtypes.EventDataTx{TxResult: abci.TxResult{
+				Height: height,
+				Index:  uint32(i),
+				Tx:     tx,
+				Result: *result,
+			}},
+			Events: txEvents,
+		})
+	}
+	return events, nil
+}
+
+// compositeEvents returns the attributes of the events by their composite
+// key, type.key, along with the tm.event of eventType, which is how the event
+// bus of CometBFT matches the events with the queries.
+func compositeEvents(events []abci.Event, eventType string) map[string][]string {
+	composite := map[string][]string{tmtypes.EventTypeKey: {eventType}}
+	for _, event := range events {
+		if event.Type == "" {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == "" {
+				continue
+			}
+			key := event.Type + "." + attr.Key
+			composite[key] = append(composite[key], attr.Value)
+		}
+	}
+	return composite
+}
+
+// offsetTracker assigns the offsets of the events of a subscription.
+type offsetTracker struct {
+	height int64
+	count  int64
+}
+
+// next returns the stream and offset of event.
+func (t *offsetTracker) next(event coretypes.ResultEvent) (string, EventOffset) {
+	var height, index int64
+	switch data := event.Data.(type) {
+	case tmtypes.EventDataTx:
+		return TxStream, EventOffset{Height: data.Height, Index: int64(data.Index)}
+	case tmtypes.EventDataNewBlock:
+		if data.Block != nil {
+			height = data.Block.Height
+		}
+		index = newBlockIndex
+	case tmtypes.EventDataNewBlockHeader:
+		height, index = data.Header.Height, newBlockHeaderIndex
+	default:
+		// events without a height follow the block events of the last
+		// height
+		t.count++
+		return BlockStream, EventOffset{Height: t.height, Index: newBlockHeaderIndex + t.count}
+	}
+
+	if height != t.height {
+		t.height, t.count = height, 0
+	}
+	return BlockStream, EventOffset{Height: height, Index: index}
+}
+
+// encodeSubscriptionEvent decodes an event of a subscription into the JSON of
+// a SubscriptionEvent, keyed by its stream and offset.
+func encodeSubscriptionEvent(clientCtx client.Context, query string, event coretypes.ResultEvent, offsets *offsetTracker) (SinkMessage, string, EventOffset, error) {
+	out := SubscriptionEvent{Query: query}
+	out.Stream, out.Offset = offsets.next(event)
+	if types := event.Events[tmtypes.EventTypeKey]; len(types) > 0 {
+		out.Type = types[0]
+	}
+
+	switch data := event.Data.(type) {
+	case tmtypes.EventDataTx:
+		tx, err := DecodeTxResult(clientCtx, data.Height, data.Tx, data.Result)
+		if err != nil {
+			return SinkMessage{}, out.Stream, out.Offset, err
+		}
+		out.Tx = tx
+	case tmtypes.EventDataNewBlock:
+		out.Events = decodeBlockEvents(clientCtx, data.ResultBeginBlock, data.ResultEndBlock)
+	case tmtypes.EventDataNewBlockHeader:
+		out.Events = decodeBlockEvents(clientCtx, data.ResultBeginBlock, data.ResultEndBlock)
+	}
+
+	bz, err := json.Marshal(out)
+	if err != nil {
+		return SinkMessage{}, out.Stream, out.Offset, err
+	}
+	return SinkMessage{Key: []byte(out.Key()), Value: bz}, out.Stream, out.Offset, nil
+}
+
+func decodeBlockEvents(clientCtx client.Context, begin abci.ResponseBeginBlock, end abci.ResponseEndBlock) []DecodedEvent {
+	events := make([]abci.Event, 0, len(begin.Events)+len(end.Events))
+	events = append(events, begin.Events...)
+	events = append(events, end.Events...)
+	return DecodeEvents(clientCtx.InterfaceRegistry, events)
+}
+
+// sinkCheckpoint is the progress of the export of the events of a query to a
+// sink.
+type sinkCheckpoint struct {
+	Query string `json:"query"`
+	// Sink is the sink URL, without its password.
+	Sink string `json:"sink"`
+	// Height is the last height whose events were all published.
+	Height int64 `json:"height,string"`
+	// Offsets are the offsets of the last event published of each stream,
+	// which are past Height when the events of a height were published in
+	// several batches.
+	Offsets map[string]EventOffset `json:"offsets"`
+
+	path string
+}
+
+// published reports whether the event of the stream at offset was published.
+func (cp *sinkCheckpoint) published(stream string, offset EventOffset) bool {
+	last, ok := cp.Offsets[stream]
+	return offset.Height <= cp.Height || (ok && !offset.After(last))
+}
+
+// defaultCheckpointPath returns the checkpoint file of a query and sink in the
+// home directory.
+func defaultCheckpointPath(home, query, sinkURL string) string {
+	h := sha256.Sum256([]byte(query + "\n" + redactSinkURL(sinkURL)))
+	return filepath.Join(home, checkpointsDir, hex.EncodeToString(h[:8])+".json")
+}
+
+// loadSinkCheckpoint loads the checkpoint file at path, which must be of the
+// query and sink, or returns an empty checkpoint if it doesn't exist.
+func loadSinkCheckpoint(path, query, sinkURL string) (*sinkCheckpoint, error) {
+	cp := &sinkCheckpoint{
+		Query:   query,
+		Sink:    redactSinkURL(sinkURL),
+		Offsets: make(map[string]EventOffset),
+		path:    path,
+	}
+
+	bz, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var saved sinkCheckpoint
+	if err := json.Unmarshal(bz, &saved); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	if saved.Query != cp.Query || saved.Sink != cp.Sink {
+		return nil, fmt.Errorf("checkpoint %s is of the query %q to %s, use another --%s", path, saved.Query, saved.Sink, flagCheckpoint)
+	}
+	cp.Height = saved.Height
+	for stream, offset := range saved.Offsets {
+		cp.Offsets[stream] = offset
+	}
+	return cp, nil
+}
+
+// save writes the checkpoint atomically.
+func (cp *sinkCheckpoint) save() error {
+	bz, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cp.path), 0o700); err != nil {
+		return err
+	}
+	tmpPath := cp.path + ".tmp"
+	if err := os.WriteFile(tmpPath, bz, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, cp.path)
+}
+
+func redactSinkURL(sinkURL string) string {
+	u, err := url.Parse(sinkURL)
+	if err != nil {
+		return sinkURL
+	}
+	return u.Redacted()
+}
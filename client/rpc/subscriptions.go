@@ -0,0 +1,401 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	rpchttp "github.com/baron-chain/cometbft-bc/rpc/client/http"
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+)
+
+const (
+	defaultSubscriptionBuffer = 100
+	// defaultMaxSubscriptions is the default max_subscriptions_per_client of
+	// the node RPC config.
+	defaultMaxSubscriptions  = 5
+	defaultSubscribeInterval = 100 * time.Millisecond
+	defaultReconnectBackoff  = time.Second
+	maxReconnectBackoff      = 30 * time.Second
+)
+
+var (
+	// ErrTooManySubscriptions is returned when subscribing to more distinct
+	// queries than the node allows for a single client.
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
+	// ErrSubscriptionManagerClosed is returned when subscribing with a closed
+	// SubscriptionManager.
+	ErrSubscriptionManagerClosed = errors.New("subscription manager closed")
+)
+
+// EventsClient is the websocket connection a SubscriptionManager multiplexes
+// subscriptions over, implemented by the CometBFT HTTP client.
+type EventsClient interface {
+	Start() error
+	Stop() error
+	// Quit is closed when the connection is stopped, e.g. after failing to
+	// reconnect to the node.
+	Quit() <-chan struct{}
+	Subscribe(ctx context.Context, subscriber, query string, outCapacity ...int) (<-chan coretypes.ResultEvent, error)
+	Unsubscribe(ctx context.Context, subscriber, query string) error
+}
+
+// EventsDialer opens a new, not started, websocket connection to the node.
+type EventsDialer func() (EventsClient, error)
+
+// NewWebsocketDialer returns an EventsDialer connecting to the websocket
+// endpoint of the node RPC at nodeURI.
+func NewWebsocketDialer(nodeURI string) EventsDialer {
+	return func() (EventsClient, error) {
+		return rpchttp.New(nodeURI, websocketPath)
+	}
+}
+
+// SubscriptionManagerOptions configures a SubscriptionManager. Zero values
+// select the defaults.
+type SubscriptionManagerOptions struct {
+	// Subscriber identifies the client to the node.
+	Subscriber string
+	// BufferSize is the capacity of the channel of each subscription. Events
+	// received while it is full are dropped and counted, so that a slow
+	// consumer doesn't block the other subscriptions.
+	BufferSize int
+	// MaxSubscriptions is the maximum number of distinct queries subscribed
+	// to, which must not exceed the max_subscriptions_per_client of the node.
+	MaxSubscriptions int
+	// SubscribeInterval is the minimum interval between the subscribe
+	// requests sent to the node, including resubscriptions after reconnecting.
+	SubscribeInterval time.Duration
+	// ReconnectBackoff is the initial delay between reconnection attempts,
+	// doubled after each failed attempt.
+	ReconnectBackoff time.Duration
+}
+
+// SubscriptionManager multiplexes event subscriptions over a single websocket
+// connection to the node. Subscriptions to the same query share a single node
+// subscription, and each one gets its own channel. When the connection is lost
+// for good, the manager reconnects and resubscribes to all the queries.
+type SubscriptionManager struct {
+	dial EventsDialer
+	opts SubscriptionManagerOptions
+
+	// subscribeMtx serializes the subscribe requests, spaced by
+	// opts.SubscribeInterval.
+	subscribeMtx  sync.Mutex
+	lastSubscribe time.Time
+
+	mtx     sync.Mutex
+	client  EventsClient
+	queries map[string]*querySubscriptions
+	closed  bool
+	done    chan struct{}
+}
+
+// querySubscriptions are the subscriptions to a query, sharing the node
+// subscription.
+type querySubscriptions struct {
+	subs map[*Subscription]struct{}
+	// cancel stops forwarding the events of the current connection.
+	cancel context.CancelFunc
+}
+
+// Subscription is a subscription of a SubscriptionManager.
+type Subscription struct {
+	query   string
+	out     chan coretypes.ResultEvent
+	dropped atomic.Uint64
+	manager *SubscriptionManager
+}
+
+// Query returns the query subscribed to.
+func (s *Subscription) Query() string {
+	return s.query
+}
+
+// Events returns the channel of the subscription events. It is closed when
+// the subscription or its manager is closed.
+func (s *Subscription) Events() <-chan coretypes.ResultEvent {
+	return s.out
+}
+
+// Dropped returns the number of events dropped because the subscription
+// channel was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close closes the subscription, and unsubscribes from the query when it was
+// its last subscription.
+func (s *Subscription) Close() error {
+	return s.manager.unsubscribe(s)
+}
+
+// NewSubscriptionManager connects to the node with dial and returns a manager
+// of the subscriptions over that connection.
+func NewSubscriptionManager(dial EventsDialer, opts SubscriptionManagerOptions) (*SubscriptionManager, error) {
+	if opts.Subscriber == "" {
+		opts.Subscriber = subscriberID
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = defaultSubscriptionBuffer
+	}
+	if opts.MaxSubscriptions <= 0 {
+		opts.MaxSubscriptions = defaultMaxSubscriptions
+	}
+	if opts.SubscribeInterval <= 0 {
+		opts.SubscribeInterval = defaultSubscribeInterval
+	}
+	if opts.ReconnectBackoff <= 0 {
+		opts.ReconnectBackoff = defaultReconnectBackoff
+	}
+
+	client, err := connect(dial)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &SubscriptionManager{
+		dial:    dial,
+		opts:    opts,
+		client:  client,
+		queries: make(map[string]*querySubscriptions),
+		done:    make(chan struct{}),
+	}
+	go m.watch(client)
+
+	return m, nil
+}
+
+func connect(dial EventsDialer) (EventsClient, error) {
+	client, err := dial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create websocket client: %w", err)
+	}
+	if err := client.Start(); err != nil {
+		return nil, wrapRPCError("start websocket client", err)
+	}
+	return client, nil
+}
+
+// Subscribe subscribes to the events matching query.
+func (m *SubscriptionManager) Subscribe(ctx context.Context, query string) (*Subscription, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.closed {
+		return nil, ErrSubscriptionManagerClosed
+	}
+
+	sub := &Subscription{
+		query:   query,
+		out:     make(chan coretypes.ResultEvent, m.opts.BufferSize),
+		manager: m,
+	}
+
+	if qs, ok := m.queries[query]; ok {
+		qs.subs[sub] = struct{}{}
+		return sub, nil
+	}
+
+	if len(m.queries) >= m.opts.MaxSubscriptions {
+		return nil, fmt.Errorf("%w: the node allows %d queries per client", ErrTooManySubscriptions, m.opts.MaxSubscriptions)
+	}
+
+	qs := &querySubscriptions{subs: map[*Subscription]struct{}{sub: {}}}
+	if err := m.subscribe(ctx, m.client, query, qs); err != nil {
+		return nil, err
+	}
+	m.queries[query] = qs
+
+	return sub, nil
+}
+
+// subscribe subscribes to query on client and forwards its events to qs. It
+// must be called with m.mtx held.
+func (m *SubscriptionManager) subscribe(ctx context.Context, client EventsClient, query string, qs *querySubscriptions) error {
+	if err := m.waitSubscribeInterval(ctx); err != nil {
+		return wrapRPCError("subscribe to events", err)
+	}
+
+	events, err := client.Subscribe(ctx, m.opts.Subscriber, query, m.opts.BufferSize)
+	if err != nil {
+		return wrapRPCError("subscribe to events", err)
+	}
+
+	forwardCtx, cancel := context.WithCancel(context.Background())
+	qs.cancel = cancel
+	go m.forward(forwardCtx, client, query, events)
+
+	return nil
+}
+
+// waitSubscribeInterval rate limits the subscribe requests sent to the node.
+func (m *SubscriptionManager) waitSubscribeInterval(ctx context.Context) error {
+	m.subscribeMtx.Lock()
+	defer m.subscribeMtx.Unlock()
+
+	if wait := time.Until(m.lastSubscribe.Add(m.opts.SubscribeInterval)); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	m.lastSubscribe = time.Now()
+	return nil
+}
+
+// forward delivers the events of a query received on client to its
+// subscriptions, until ctx is canceled or the connection is lost.
+func (m *SubscriptionManager) forward(ctx context.Context, client EventsClient, query string, events <-chan coretypes.ResultEvent) {
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			m.mtx.Lock()
+			if qs, ok := m.queries[query]; ok && ctx.Err() == nil {
+				for sub := range qs.subs {
+					select {
+					case sub.out <- event:
+					default:
+						sub.dropped.Add(1)
+					}
+				}
+			}
+			m.mtx.Unlock()
+
+		case <-ctx.Done():
+			return
+
+		case <-client.Quit():
+			return
+		}
+	}
+}
+
+func (m *SubscriptionManager) unsubscribe(sub *Subscription) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	qs, ok := m.queries[sub.query]
+	if !ok {
+		return nil
+	}
+	if _, ok := qs.subs[sub]; !ok {
+		return nil
+	}
+
+	delete(qs.subs, sub)
+	close(sub.out)
+	if len(qs.subs) > 0 {
+		return nil
+	}
+
+	qs.cancel()
+	delete(m.queries, sub.query)
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	return wrapRPCError("unsubscribe from events", m.client.Unsubscribe(ctx, m.opts.Subscriber, sub.query))
+}
+
+// watch reconnects to the node and resubscribes to all the queries when
+// client is stopped, i.e. when it failed to reconnect by itself.
+func (m *SubscriptionManager) watch(client EventsClient) {
+	select {
+	case <-client.Quit():
+	case <-m.done:
+		return
+	}
+
+	backoff := m.opts.ReconnectBackoff
+	for {
+		select {
+		case <-time.After(backoff):
+		case <-m.done:
+			return
+		}
+
+		if next, ok := m.reconnect(); ok {
+			if next != nil {
+				go m.watch(next)
+			}
+			return
+		}
+
+		backoff *= 2
+		if backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
+// reconnect opens a new connection and resubscribes to all the queries over
+// it. It returns false when it has to be retried, and a nil connection when the
+// manager was closed meanwhile.
+func (m *SubscriptionManager) reconnect() (EventsClient, bool) {
+	client, err := connect(m.dial)
+	if err != nil {
+		return nil, false
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.closed {
+		_ = client.Stop()
+		return nil, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	for query, qs := range m.queries {
+		qs.cancel()
+		if err := m.subscribe(ctx, client, query, qs); err != nil {
+			_ = client.Stop()
+			return nil, false
+		}
+	}
+	m.client = client
+
+	return client, true
+}
+
+// Close closes all the subscriptions and the connection to the node.
+func (m *SubscriptionManager) Close() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	close(m.done)
+
+	for query, qs := range m.queries {
+		qs.cancel()
+		for sub := range qs.subs {
+			close(sub.out)
+		}
+		delete(m.queries, query)
+	}
+
+	select {
+	case <-m.client.Quit():
+		// the connection was lost and not reestablished yet
+		return nil
+	default:
+		return m.client.Stop()
+	}
+}
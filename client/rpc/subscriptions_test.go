@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// mockEventsClient is an EventsClient whose events are published by the test.
+type mockEventsClient struct {
+	mtx  sync.Mutex
+	subs map[string]chan coretypes.ResultEvent
+	quit chan struct{}
+}
+
+func newMockEventsClient() *mockEventsClient {
+	return &mockEventsClient{subs: make(map[string]chan coretypes.ResultEvent), quit: make(chan struct{})}
+}
+
+func (c *mockEventsClient) Start() error          { return nil }
+func (c *mockEventsClient) Quit() <-chan struct{} { return c.quit }
+
+func (c *mockEventsClient) Stop() error {
+	close(c.quit)
+	return nil
+}
+
+func (c *mockEventsClient) Subscribe(_ context.Context, _, query string, outCapacity ...int) (<-chan coretypes.ResultEvent, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	ch := make(chan coretypes.ResultEvent, outCapacity[0])
+	c.subs[query] = ch
+	return ch, nil
+}
+
+func (c *mockEventsClient) Unsubscribe(_ context.Context, _, query string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.subs, query)
+	return nil
+}
+
+func (c *mockEventsClient) publish(query string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	ch, ok := c.subs[query]
+	if ok {
+		ch <- coretypes.ResultEvent{Query: query}
+	}
+	return ok
+}
+
+func (c *mockEventsClient) subscribed() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return len(c.subs)
+}
+
+func receiveEvent(t *testing.T, sub *Subscription) coretypes.ResultEvent {
+	t.Helper()
+
+	select {
+	case event := <-sub.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+		return coretypes.ResultEvent{}
+	}
+}
+
+func TestSubscriptionManager(t *testing.T) {
+	var (
+		dialMtx sync.Mutex
+		clients []*mockEventsClient
+	)
+	dial := func() (EventsClient, error) {
+		dialMtx.Lock()
+		defer dialMtx.Unlock()
+
+		client := newMockEventsClient()
+		clients = append(clients, client)
+		return client, nil
+	}
+	lastClient := func() *mockEventsClient {
+		dialMtx.Lock()
+		defer dialMtx.Unlock()
+
+		return clients[len(clients)-1]
+	}
+
+	m, err := NewSubscriptionManager(dial, SubscriptionManagerOptions{
+		BufferSize:        1,
+		MaxSubscriptions:  2,
+		SubscribeInterval: time.Millisecond,
+		ReconnectBackoff:  time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	blocks1, err := m.Subscribe(ctx, "tm.event='NewBlock'")
+	require.NoError(t, err)
+	blocks2, err := m.Subscribe(ctx, "tm.event='NewBlock'")
+	require.NoError(t, err)
+	txs, err := m.Subscribe(ctx, "tm.event='Tx'")
+	require.NoError(t, err)
+
+	// subscriptions to the same query share the node subscription
+	client := lastClient()
+	require.Equal(t, 2, client.subscribed())
+	_, err = m.Subscribe(ctx, "tm.event='ValidatorSetUpdates'")
+	require.ErrorIs(t, err, ErrTooManySubscriptions)
+
+	require.True(t, client.publish("tm.event='NewBlock'"))
+	require.Equal(t, "tm.event='NewBlock'", receiveEvent(t, blocks1).Query)
+	require.Equal(t, "tm.event='NewBlock'", receiveEvent(t, blocks2).Query)
+
+	// a full subscription channel drops events without blocking the others
+	require.True(t, client.publish("tm.event='Tx'"))
+	require.Eventually(t, func() bool { return len(txs.Events()) == 1 }, 5*time.Second, time.Millisecond)
+	require.True(t, client.publish("tm.event='Tx'"))
+	require.Eventually(t, func() bool { return txs.Dropped() == 1 }, 5*time.Second, time.Millisecond)
+	receiveEvent(t, txs)
+
+	// the queries are resubscribed to after reconnecting
+	require.NoError(t, client.Stop())
+	require.Eventually(t, func() bool {
+		next := lastClient()
+		return next != client && next.subscribed() == 2
+	}, 5*time.Second, time.Millisecond)
+
+	client = lastClient()
+	require.True(t, client.publish("tm.event='Tx'"))
+	receiveEvent(t, txs)
+
+	// the node subscription is closed with its last subscription
+	require.NoError(t, blocks1.Close())
+	require.Equal(t, 2, client.subscribed())
+	require.NoError(t, blocks2.Close())
+	require.Equal(t, 1, client.subscribed())
+	_, ok := <-blocks1.Events()
+	require.False(t, ok)
+
+	require.NoError(t, m.Close())
+	_, ok = <-txs.Events()
+	require.False(t, ok)
+	require.NoError(t, txs.Close())
+
+	_, err = m.Subscribe(ctx, "tm.event='Tx'")
+	require.ErrorIs(t, err, ErrSubscriptionManagerClosed)
+}
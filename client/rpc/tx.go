@@ -3,18 +3,17 @@ package rpc
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
-	rpchttp "github.com/baron-chain/cometbft-bc/rpc/client/http"
 	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
 	tmtypes "github.com/baron-chain/cometbft-bc/types"
 	"github.com/baron-chain/cosmos-bc-47/client"
 	"github.com/baron-chain/cosmos-bc-47/client/flags"
 	sdk "github.com/baron-chain/cosmos-bc-47/types"
-	"github.com/baron-chain/cosmos-bc-47/types/errors"
 )
 
 const (
@@ -73,26 +72,33 @@ func QueryEventForTxCmd() *cobra.Command {
 				return fmt.Errorf("failed to get client context: %w", err)
 			}
 
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
 			txHash := args[0]
-			return queryTxEvent(cmd.Context(), clientCtx, txHash)
+			return queryTxEvent(cmd, clientCtx, txHash)
 		},
 	}
 
 	flags.AddTxFlagsToCmd(cmd)
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
+	addRawFlag(cmd)
 	return cmd
 }
 
-func queryTxEvent(ctx context.Context, clientCtx client.Context, txHash string) error {
-	wsClient, err := rpchttp.New(clientCtx.NodeURI, websocketPath)
+func queryTxEvent(cmd *cobra.Command, clientCtx client.Context, txHash string) error {
+	manager, err := NewSubscriptionManager(NewWebsocketDialer(clientCtx.NodeURI), SubscriptionManagerOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to create websocket client: %w", err)
+		return err
 	}
+	defer manager.Close() //nolint:errcheck
 
-	if err := wsClient.Start(); err != nil {
-		return fmt.Errorf("failed to start websocket client: %w", err)
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	defer wsClient.Stop() //nolint:errcheck
-
 	queryCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
@@ -103,28 +109,39 @@ func queryTxEvent(ctx context.Context, clientCtx client.Context, txHash string)
 		txHash,
 	)
 
-	eventCh, err := wsClient.Subscribe(queryCtx, subscriberID, query)
+	sub, err := manager.Subscribe(queryCtx, query)
 	if err != nil {
-		return fmt.Errorf("failed to subscribe to tx events: %w", err)
+		return err
 	}
-	defer wsClient.UnsubscribeAll(context.Background(), subscriberID) //nolint:errcheck
 
 	select {
-	case evt := <-eventCh:
+	case evt := <-sub.Events():
 		txEvent, ok := evt.Data.(tmtypes.EventDataTx)
 		if !ok {
 			return fmt.Errorf("received invalid event data type: %T", evt.Data)
 		}
 
+		if raw, _ := cmd.Flags().GetBool(flagRaw); !raw {
+			decoded, err := DecodeTxResult(clientCtx, txEvent.Height, txEvent.Tx, txEvent.Result)
+			if err != nil {
+				return err
+			}
+			out, err := json.Marshal(decoded)
+			if err != nil {
+				return err
+			}
+			return printRaw(cmd, clientCtx, out)
+		}
+
 		res := &coretypes.ResultBroadcastTxCommit{
 			DeliverTx: txEvent.Result,
 			Hash:      tmtypes.Tx(txEvent.Tx).Hash(),
 			Height:    txEvent.Height,
 		}
 
-		return clientCtx.PrintProto(createBroadcastTxResponse(res))
+		return printProto(cmd, clientCtx, createBroadcastTxResponse(res))
 
 	case <-queryCtx.Done():
-		return errors.ErrLogic.Wrap("timed out waiting for transaction event")
+		return wrapRPCError("wait for transaction event", queryCtx.Err())
 	}
 }
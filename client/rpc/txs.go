@@ -0,0 +1,272 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+	sdk "github.com/baron-chain/cosmos-bc-47/types"
+	banktypes "github.com/baron-chain/cosmos-bc-47/x/bank/types"
+)
+
+const (
+	flagEvents = "events"
+	flagOrder  = "order"
+
+	defaultTxSearchLimit = 30
+	// maxTxSearchLimit is the maximum number of txs per page of the
+	// tx_search RPC.
+	maxTxSearchLimit = 100
+)
+
+// amountAttributes are the keys of the event attributes holding coins, which
+// are rendered in the display denoms of the bank metadata.
+var amountAttributes = []string{"amount", "fee", "tip"}
+
+// TxSearchNode is the node RPC searching txs.
+type TxSearchNode interface {
+	TxSearch(ctx context.Context, query string, prove bool, page, perPage *int, orderBy string) (*coretypes.ResultTxSearch, error)
+}
+
+// TxSearchOutput is a page of the txs matching a search.
+type TxSearchOutput struct {
+	Query      string               `json:"query"`
+	TotalCount int                  `json:"total_count"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	PageTotal  int                  `json:"page_total"`
+	Txs        []*DecodedTxResponse `json:"txs"`
+}
+
+// TxSearchCommand returns the command searching txs by their events, with the
+// tx_search RPC of the node.
+func TxSearchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "txs",
+		Short: "Search Baron Chain transactions by events",
+		Long: `Search the transactions whose events match all the given filters, with the
+tx_search RPC of the node, which must index transactions. Each filter takes the
+form {event_type}.{attribute_key}={attribute_value}, filters are given by repeating
+--events or by joining them with '&'. The transactions and their events are
+decoded, and the amounts of the events are printed in the display denom of their
+bank metadata, e.g. 1.5 baron for 1500000ubaron.`,
+		Example: `$ barond query txs --events 'transfer.recipient=baron1...'
+$ barond query txs --events 'message.sender=baron1...' --events 'message.action=/cosmos.bank.v1beta1.MsgSend' --page 2 --limit 50
+$ barond query txs --events 'tx.height=1200' --order desc -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get query context: %w", err)
+			}
+
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
+			events, _ := cmd.Flags().GetStringArray(flagEvents)
+			query, err := buildTxSearchQuery(events)
+			if err != nil {
+				return err
+			}
+
+			page, _ := cmd.Flags().GetInt(flags.FlagPage)
+			limit, _ := cmd.Flags().GetInt(flags.FlagLimit)
+			order, _ := cmd.Flags().GetString(flagOrder)
+			if err := validateTxSearchPage(page, limit, order); err != nil {
+				return err
+			}
+
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return fmt.Errorf("failed to get node: %w", err)
+			}
+
+			if raw, _ := cmd.Flags().GetBool(flagRaw); raw {
+				res, err := node.TxSearch(cmd.Context(), query, false, &page, &limit, order)
+				if err != nil {
+					return wrapRPCError("search txs", err)
+				}
+				return printProto(cmd, clientCtx, newSearchTxsResult(res, page, limit))
+			}
+
+			result, err := SearchTxs(cmd.Context(), clientCtx, node, query, page, limit, order)
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(result)
+			if err != nil {
+				return err
+			}
+			return printRaw(cmd, clientCtx, bz)
+		},
+	}
+
+	cmd.Flags().String(flags.FlagNode, defaultNodeEndpoint, "Baron Chain node RPC endpoint")
+	cmd.Flags().StringP(flags.FlagOutput, "o", "text", "Output format (text|json)")
+	cmd.Flags().StringArray(flagEvents, nil, "Event filter of the form {event_type}.{attribute_key}={attribute_value}, repeatable")
+	cmd.Flags().Int(flags.FlagPage, 1, "Page of the results to query")
+	cmd.Flags().Int(flags.FlagLimit, defaultTxSearchLimit, fmt.Sprintf("Number of transactions per page, at most %d", maxTxSearchLimit))
+	cmd.Flags().String(flagOrder, "asc", "Order of the transactions by height (asc|desc)")
+	_ = cmd.MarkFlagRequired(flagEvents)
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
+	addRawFlag(cmd)
+
+	return cmd
+}
+
+// SearchTxs returns a page of the txs matching the tx_search query, decoded
+// with the client context, and with the amounts of their events rendered in
+// the display denoms of the bank metadata. Page starts at 1 and order is asc
+// or desc.
+func SearchTxs(ctx context.Context, clientCtx client.Context, node TxSearchNode, query string, page, limit int, order string) (*TxSearchOutput, error) {
+	if err := validateTxSearchPage(page, limit, order); err != nil {
+		return nil, err
+	}
+
+	res, err := node.TxSearch(ctx, query, false, &page, &limit, order)
+	if err != nil {
+		return nil, wrapRPCError("search txs", err)
+	}
+
+	out := &TxSearchOutput{
+		Query:      query,
+		TotalCount: res.TotalCount,
+		Page:       page,
+		Limit:      limit,
+		PageTotal:  (res.TotalCount + limit - 1) / limit,
+		Txs:        make([]*DecodedTxResponse, 0, len(res.Txs)),
+	}
+	for _, tx := range res.Txs {
+		decoded, err := DecodeTxResult(clientCtx, tx.Height, tx.Tx, tx.TxResult)
+		if err != nil {
+			return nil, err
+		}
+		out.Txs = append(out.Txs, decoded)
+	}
+
+	if !hasEventAmounts(out.Txs) {
+		return out, nil
+	}
+	metadata, err := queryDenomsMetadata(ctx, banktypes.NewQueryClient(clientCtx))
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range out.Txs {
+		formatEventAmounts(tx.Events, metadata)
+	}
+	return out, nil
+}
+
+// buildTxSearchQuery returns the tx_search query matching all the event
+// filters, each of the form type.key=value, or several of them joined by '&'.
+func buildTxSearchQuery(events []string) (string, error) {
+	var conditions []string
+	for _, arg := range events {
+		for _, event := range strings.Split(strings.Trim(arg, "'"), "&") {
+			key, value, ok := strings.Cut(event, "=")
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			if !ok || value == "" || strings.Contains(value, "=") {
+				return "", fmt.Errorf("invalid event filter %q, expected {event_type}.{attribute_key}={attribute_value}", event)
+			}
+			if typ, attr, ok := strings.Cut(key, "."); !ok || typ == "" || attr == "" || strings.ContainsAny(key, " '") {
+				return "", fmt.Errorf("invalid event filter %q, expected {event_type}.{attribute_key}={attribute_value}", event)
+			}
+			if strings.Contains(value, "'") {
+				return "", fmt.Errorf("invalid event filter %q: values can't contain quotes", event)
+			}
+
+			if key == tmtypes.TxHeightKey {
+				if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+					return "", fmt.Errorf("invalid event filter %q: the height must be an integer", event)
+				}
+				conditions = append(conditions, fmt.Sprintf("%s=%s", key, value))
+			} else {
+				conditions = append(conditions, fmt.Sprintf("%s='%s'", key, value))
+			}
+		}
+	}
+	if len(conditions) == 0 {
+		return "", fmt.Errorf("at least one event filter is required, use --%s", flagEvents)
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+func validateTxSearchPage(page, limit int, order string) error {
+	if page < 1 {
+		return fmt.Errorf("--%s must be at least 1, got %d", flags.FlagPage, page)
+	}
+	if limit < 1 || limit > maxTxSearchLimit {
+		return fmt.Errorf("--%s must be between 1 and %d, got %d", flags.FlagLimit, maxTxSearchLimit, limit)
+	}
+	if order != "asc" && order != "desc" {
+		return fmt.Errorf("--%s must be asc or desc, got %q", flagOrder, order)
+	}
+	return nil
+}
+
+// newSearchTxsResult returns the undecoded page of txs, as printed with --raw.
+func newSearchTxsResult(res *coretypes.ResultTxSearch, page, limit int) *sdk.SearchTxsResult {
+	txs := make([]*sdk.TxResponse, len(res.Txs))
+	for i, tx := range res.Txs {
+		txs[i] = sdk.NewResponseResultTx(tx, nil, "")
+	}
+	return sdk.NewSearchTxsResult(uint64(res.TotalCount), uint64(len(txs)), uint64(page), uint64(limit), txs)
+}
+
+func hasEventAmounts(txs []*DecodedTxResponse) bool {
+	for _, tx := range txs {
+		for _, event := range tx.Events {
+			for _, attr := range event.Attributes {
+				if containsString(amountAttributes, attr.Key) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// formatEventAmounts sets the display of the event attributes holding coins.
+// Attributes whose value doesn't parse as coins are left as is.
+func formatEventAmounts(events []DecodedEvent, metadata []banktypes.Metadata) {
+	for i := range events {
+		for j, attr := range events[i].Attributes {
+			if !containsString(amountAttributes, attr.Key) {
+				continue
+			}
+			coinList, err := sdk.ParseCoinsNormalized(attr.Value)
+			if err != nil || coinList.Empty() {
+				continue
+			}
+			coinsOut, err := formatCoinOutputs(coinList, metadata)
+			if err != nil {
+				continue
+			}
+
+			displays := make([]string, len(coinsOut))
+			for k, coin := range coinsOut {
+				displays[k] = coin.Display
+			}
+			events[i].Attributes[j].Display = strings.Join(displays, ", ")
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
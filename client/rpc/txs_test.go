@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	abci "github.com/baron-chain/cometbft-bc/abci/types"
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	banktypes "github.com/baron-chain/cosmos-bc-47/x/bank/types"
+)
+
+type mockTxSearchNode struct {
+	query         string
+	page, perPage int
+	orderBy       string
+	res           *coretypes.ResultTxSearch
+}
+
+func (n *mockTxSearchNode) TxSearch(_ context.Context, query string, _ bool, page, perPage *int, orderBy string) (*coretypes.ResultTxSearch, error) {
+	n.query, n.page, n.perPage, n.orderBy = query, *page, *perPage, orderBy
+	return n.res, nil
+}
+
+func TestBuildTxSearchQuery(t *testing.T) {
+	query, err := buildTxSearchQuery([]string{"'transfer.recipient=baron1abc&message.module=bank'", "tx.height=12"})
+	require.NoError(t, err)
+	require.Equal(t, "transfer.recipient='baron1abc' AND message.module='bank' AND tx.height=12", query)
+
+	for _, invalid := range [][]string{
+		nil,
+		{"transfer"},
+		{"transfer.recipient="},
+		{"recipient=baron1abc"},
+		{"transfer.recipient=a=b"},
+		{"transfer.recipient=a' OR tx.height>0"},
+		{"tx.height=abc"},
+	} {
+		_, err := buildTxSearchQuery(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestSearchTxs(t *testing.T) {
+	node := &mockTxSearchNode{res: &coretypes.ResultTxSearch{
+		TotalCount: 61,
+		Txs: []*coretypes.ResultTx{{
+			Height:   12,
+			TxResult: abci.ResponseDeliverTx{Events: []abci.Event{{Type: "message", Attributes: []abci.EventAttribute{{Key: "module", Value: "bank"}}}}},
+		}},
+	}}
+
+	out, err := SearchTxs(context.Background(), client.Context{}, node, "message.module='bank'", 3, 30, "desc")
+	require.NoError(t, err)
+	require.Equal(t, "message.module='bank'", node.query)
+	require.Equal(t, []interface{}{3, 30, "desc"}, []interface{}{node.page, node.perPage, node.orderBy})
+	require.Equal(t, 61, out.TotalCount)
+	require.Equal(t, 3, out.PageTotal)
+	require.Len(t, out.Txs, 1)
+	require.Equal(t, int64(12), out.Txs[0].Height)
+
+	_, err = SearchTxs(context.Background(), client.Context{}, node, "message.module='bank'", 0, 30, "asc")
+	require.Error(t, err)
+	_, err = SearchTxs(context.Background(), client.Context{}, node, "message.module='bank'", 1, 101, "asc")
+	require.Error(t, err)
+	_, err = SearchTxs(context.Background(), client.Context{}, node, "message.module='bank'", 1, 30, "random")
+	require.Error(t, err)
+}
+
+func TestFormatEventAmounts(t *testing.T) {
+	metadata := []banktypes.Metadata{{
+		Base:    "ubaron",
+		Display: "baron",
+		DenomUnits: []*banktypes.DenomUnit{
+			{Denom: "ubaron", Exponent: 0},
+			{Denom: "baron", Exponent: 6},
+		},
+	}}
+
+	events := []DecodedEvent{{Type: "transfer", Attributes: []DecodedAttribute{
+		{Key: "recipient", Value: "baron1abc"},
+		{Key: "amount", Value: "1500000ubaron,10stake"},
+	}}, {Type: "tx", Attributes: []DecodedAttribute{{Key: "fee", Value: "not coins"}}}}
+	require.True(t, hasEventAmounts([]*DecodedTxResponse{{Events: events}}))
+
+	formatEventAmounts(events, metadata)
+	require.Equal(t, []DecodedAttribute{
+		{Key: "recipient", Value: "baron1abc"},
+		{Key: "amount", Value: "1500000ubaron,10stake", Display: "10 stake, 1.5 baron"},
+	}, events[0].Attributes)
+	require.Equal(t, "", events[1].Attributes[0].Display)
+}
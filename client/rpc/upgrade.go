@@ -0,0 +1,243 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+	upgradetypes "github.com/baron-chain/cosmos-bc-47/x/upgrade/types"
+)
+
+const (
+	flagUpgradeWindow       = "window"
+	flagUpgradeInterval     = "interval"
+	flagBlockTimeBlocks     = "blocks"
+	defaultBlockTimeBlocks  = 100
+	maxBlockTimeBlocks      = 100000
+	minUpgradeWatchInterval = time.Second
+	upgradeWatchTimeLayout  = time.RFC3339
+)
+
+// ErrUpgradeWithinWindow is returned by the upgrade-watch command when the
+// scheduled upgrade is estimated to happen within the --window, so that
+// operator scripts can act on its nonzero exit code.
+var ErrUpgradeWithinWindow = errors.New("upgrade within window")
+
+// UpgradeWatchOutput is the upgrade plan scheduled by the upgrade module, with
+// its estimated time.
+type UpgradeWatchOutput struct {
+	Scheduled     bool   `json:"scheduled"`
+	Name          string `json:"name,omitempty"`
+	Height        int64  `json:"height,omitempty"`
+	Info          string `json:"info,omitempty"`
+	CurrentHeight int64  `json:"current_height"`
+	// BlocksRemaining is the number of blocks to commit before the upgrade
+	// height, 0 once the chain halted for the upgrade.
+	BlocksRemaining  int64  `json:"blocks_remaining"`
+	AverageBlockTime string `json:"average_block_time"`
+	// EstimatedTime is the time of the upgrade, extrapolated from the time of
+	// the latest block and the average block time.
+	EstimatedTime time.Time `json:"estimated_time"`
+}
+
+func (uo UpgradeWatchOutput) String() string {
+	if !uo.Scheduled {
+		return "No upgrade scheduled\n"
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Name:               %s\n", uo.Name)
+	fmt.Fprintf(&b, "Height:             %d\n", uo.Height)
+	fmt.Fprintf(&b, "Current Height:     %d\n", uo.CurrentHeight)
+	fmt.Fprintf(&b, "Blocks Remaining:   %d\n", uo.BlocksRemaining)
+	fmt.Fprintf(&b, "Average Block Time: %s\n", uo.AverageBlockTime)
+	fmt.Fprintf(&b, "Estimated Time:     %s\n", uo.EstimatedTime.Format(upgradeWatchTimeLayout))
+	if uo.Info != "" {
+		fmt.Fprintf(&b, "Info:               %s\n", uo.Info)
+	}
+
+	return b.String()
+}
+
+// Within reports whether the upgrade is scheduled and estimated to happen
+// within window of now, or is already due.
+func (uo UpgradeWatchOutput) Within(window time.Duration, now time.Time) bool {
+	if !uo.Scheduled {
+		return false
+	}
+	return uo.BlocksRemaining == 0 || !uo.EstimatedTime.After(now.Add(window))
+}
+
+// UpgradeWatchCommand returns the command watching the upgrade plan scheduled
+// by the upgrade module.
+func UpgradeWatchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade-watch",
+		Short: "Watch the upgrade scheduled on Baron Chain and estimate its time",
+		Long: `Query the upgrade plan scheduled by the upgrade module, and estimate the time of the
+upgrade from the average block time of the most recent blocks. With --interval, the
+plan is queried again at that interval until interrupted.
+
+With --window, the command exits with a nonzero code as soon as an upgrade is
+estimated to happen within that time, or is already due, so that operator
+automation, e.g. a cron job or a systemd unit, can prepare the node for it.
+Estimates assume that the block time stays the same until the upgrade.`,
+		Example: `$ barond query upgrade-watch
+$ barond query upgrade-watch --window 2h
+$ barond query upgrade-watch --interval 5m --window 30m -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get query context: %w", err)
+			}
+
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
+			blocks, _ := cmd.Flags().GetInt64(flagBlockTimeBlocks)
+			if blocks <= 0 || blocks > maxBlockTimeBlocks {
+				return fmt.Errorf("--%s must be between 1 and %d, got %d", flagBlockTimeBlocks, maxBlockTimeBlocks, blocks)
+			}
+			window, _ := cmd.Flags().GetDuration(flagUpgradeWindow)
+			if window < 0 {
+				return fmt.Errorf("--%s must not be negative", flagUpgradeWindow)
+			}
+			interval, _ := cmd.Flags().GetDuration(flagUpgradeInterval)
+			if interval != 0 && interval < minUpgradeWatchInterval {
+				return fmt.Errorf("--%s must be at least %s", flagUpgradeInterval, minUpgradeWatchInterval)
+			}
+
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return fmt.Errorf("failed to get node: %w", err)
+			}
+			// the block times are read from the headers, which the HTTP
+			// client queries, rather than from the whole blocks
+			watchNode, ok := node.(UpgradeWatchNode)
+			if !ok {
+				return fmt.Errorf("the node client %T doesn't support header queries", node)
+			}
+			queryClient := upgradetypes.NewQueryClient(clientCtx)
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			for {
+				result, err := QueryUpgradeWatch(ctx, queryClient, watchNode, blocks)
+				if err != nil {
+					return err
+				}
+				if err := printObject(cmd, clientCtx, result); err != nil {
+					return err
+				}
+
+				if window > 0 && result.Within(window, time.Now()) {
+					return fmt.Errorf("%w: upgrade %s at height %d estimated at %s",
+						ErrUpgradeWithinWindow, result.Name, result.Height, result.EstimatedTime.Format(upgradeWatchTimeLayout))
+				}
+				if interval == 0 {
+					return nil
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+
+	cmd.Flags().String(flags.FlagNode, defaultNodeEndpoint, "Baron Chain node RPC endpoint")
+	cmd.Flags().StringP(flags.FlagOutput, "o", "text", "Output format (text|json)")
+	cmd.Flags().Int64(flagBlockTimeBlocks, defaultBlockTimeBlocks, "Number of recent blocks to average the block time over")
+	cmd.Flags().Duration(flagUpgradeWindow, 0, "Exit with a nonzero code if an upgrade is estimated to happen within this time")
+	cmd.Flags().Duration(flagUpgradeInterval, 0, "Query the plan again at this interval, until interrupted or the upgrade is within the window, instead of once")
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
+
+	return cmd
+}
+
+// UpgradeWatchNode is the subset of the node RPC client used by
+// QueryUpgradeWatch.
+type UpgradeWatchNode interface {
+	Status(ctx context.Context) (*coretypes.ResultStatus, error)
+	Header(ctx context.Context, height *int64) (*coretypes.ResultHeader, error)
+}
+
+// QueryUpgradeWatch queries the upgrade plan scheduled by the upgrade module,
+// and estimates its time from the average block time of the given number of
+// recent blocks. RPC failures are returned as an *Error.
+func QueryUpgradeWatch(ctx context.Context, queryClient upgradetypes.QueryClient, node UpgradeWatchNode, blocks int64) (UpgradeWatchOutput, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := queryClient.CurrentPlan(ctx, &upgradetypes.QueryCurrentPlanRequest{})
+	if err != nil {
+		return UpgradeWatchOutput{}, wrapRPCError("query current upgrade plan", err)
+	}
+
+	status, err := node.Status(ctx)
+	if err != nil {
+		return UpgradeWatchOutput{}, wrapRPCError("query node status", err)
+	}
+	latest := status.SyncInfo.LatestBlockHeight
+
+	out := UpgradeWatchOutput{CurrentHeight: latest}
+	if res.Plan == nil {
+		return out, nil
+	}
+
+	avg, err := averageBlockTime(ctx, node, latest, status.SyncInfo.LatestBlockTime, blocks)
+	if err != nil {
+		return UpgradeWatchOutput{}, err
+	}
+	out.AverageBlockTime = avg.String()
+
+	out.Scheduled = true
+	out.Name = res.Plan.Name
+	out.Height = res.Plan.Height
+	out.Info = res.Plan.Info
+	out.EstimatedTime = status.SyncInfo.LatestBlockTime
+	// the chain halts at the upgrade height, before committing it
+	if remaining := res.Plan.Height - latest - 1; remaining > 0 {
+		out.BlocksRemaining = remaining
+		out.EstimatedTime = out.EstimatedTime.Add(time.Duration(remaining) * avg)
+	}
+
+	return out, nil
+}
+
+// averageBlockTime returns the average time between the given number of
+// blocks up to the latest height, committed at latestTime.
+func averageBlockTime(ctx context.Context, node UpgradeWatchNode, latest int64, latestTime time.Time, blocks int64) (time.Duration, error) {
+	start := latest - blocks
+	if start < 1 {
+		start = 1
+	}
+	if start >= latest {
+		return 0, nil
+	}
+
+	res, err := node.Header(ctx, &start)
+	if err != nil {
+		return 0, wrapRPCError(fmt.Sprintf("query header at height %d", start), err)
+	}
+	if res.Header == nil {
+		return 0, fmt.Errorf("failed to query header at height %d: %w", start, errNoHeader)
+	}
+	return latestTime.Sub(res.Header.Time) / time.Duration(latest-start), nil
+}
@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	upgradetypes "github.com/baron-chain/cosmos-bc-47/x/upgrade/types"
+)
+
+// mockUpgradeNode is a chain at height latest committing a block every 5
+// seconds, with the upgrade plan of plan scheduled.
+type mockUpgradeNode struct {
+	upgradetypes.QueryClient
+
+	latest int64
+	plan   *upgradetypes.Plan
+}
+
+func (n mockUpgradeNode) blockTime(height int64) time.Time {
+	return time.Unix(0, 0).Add(time.Duration(height) * 5 * time.Second)
+}
+
+func (n mockUpgradeNode) CurrentPlan(context.Context, *upgradetypes.QueryCurrentPlanRequest, ...grpc.CallOption) (*upgradetypes.QueryCurrentPlanResponse, error) {
+	return &upgradetypes.QueryCurrentPlanResponse{Plan: n.plan}, nil
+}
+
+func (n mockUpgradeNode) Status(context.Context) (*coretypes.ResultStatus, error) {
+	return &coretypes.ResultStatus{SyncInfo: coretypes.SyncInfo{
+		LatestBlockHeight: n.latest,
+		LatestBlockTime:   n.blockTime(n.latest),
+	}}, nil
+}
+
+func (n mockUpgradeNode) Header(_ context.Context, height *int64) (*coretypes.ResultHeader, error) {
+	return &coretypes.ResultHeader{Header: &tmtypes.Header{Height: *height, Time: n.blockTime(*height)}}, nil
+}
+
+func TestQueryUpgradeWatch(t *testing.T) {
+	node := mockUpgradeNode{latest: 1000}
+	out, err := QueryUpgradeWatch(context.Background(), node, node, 100)
+	require.NoError(t, err)
+	require.Equal(t, UpgradeWatchOutput{CurrentHeight: 1000}, out)
+	require.False(t, out.Within(time.Hour, node.blockTime(1000)))
+	require.Equal(t, "No upgrade scheduled\n", out.String())
+
+	node.plan = &upgradetypes.Plan{Name: "v2", Height: 1721, Info: "https://example.com/v2.json"}
+	out, err = QueryUpgradeWatch(context.Background(), node, node, 100)
+	require.NoError(t, err)
+	require.True(t, out.Scheduled)
+	require.Equal(t, "v2", out.Name)
+	require.Equal(t, int64(720), out.BlocksRemaining)
+	require.Equal(t, "5s", out.AverageBlockTime)
+	require.Equal(t, node.blockTime(1720), out.EstimatedTime)
+
+	now := node.blockTime(1000)
+	require.False(t, out.Within(59*time.Minute, now))
+	require.True(t, out.Within(time.Hour, now))
+
+	// the chain halted for the upgrade
+	node.latest = 1720
+	out, err = QueryUpgradeWatch(context.Background(), node, node, 100)
+	require.NoError(t, err)
+	require.Zero(t, out.BlocksRemaining)
+	require.True(t, out.Within(0, now))
+
+	// the average covers the blocks committed since genesis
+	node.latest = 11
+	out, err = QueryUpgradeWatch(context.Background(), node, node, 100)
+	require.NoError(t, err)
+	require.Equal(t, "5s", out.AverageBlockTime)
+	require.Equal(t, int64(1709), out.BlocksRemaining)
+}
@@ -0,0 +1,261 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	tmcrypto "github.com/baron-chain/cometbft-bc/crypto"
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client"
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+	sdk "github.com/baron-chain/cosmos-bc-47/types"
+)
+
+const (
+	flagUptimeBlocks     = "blocks"
+	defaultUptimeBlocks  = 1000
+	maxUptimeBlocks      = 100000
+	validatorsPerRequest = 100
+)
+
+// ValidatorUptimeOutput is the signing record of a validator over a range of
+// recent blocks.
+type ValidatorUptimeOutput struct {
+	Address     sdk.ConsAddress `json:"address"`
+	StartHeight int64           `json:"start_height"`
+	EndHeight   int64           `json:"end_height"`
+	// Signed counts the blocks whose commit holds a vote of the validator,
+	// including nil votes, which the slashing module doesn't count as missed.
+	Signed int64 `json:"signed"`
+	// Missed counts the blocks the validator was in the validator set of, but
+	// whose commit doesn't hold a vote of it.
+	Missed int64 `json:"missed"`
+	// NotInSet counts the blocks the validator was not in the validator set of.
+	NotInSet      int64   `json:"not_in_set"`
+	MissedHeights []int64 `json:"missed_heights,omitempty"`
+	// Uptime is the ratio of signed blocks over the blocks the validator was
+	// in the validator set of.
+	Uptime           sdk.Dec `json:"uptime"`
+	AverageBlockTime string  `json:"average_block_time"`
+}
+
+func (uo ValidatorUptimeOutput) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Address:            %s\n", uo.Address)
+	fmt.Fprintf(&b, "Heights:            %d-%d\n", uo.StartHeight, uo.EndHeight)
+	fmt.Fprintf(&b, "Signed:             %d\n", uo.Signed)
+	fmt.Fprintf(&b, "Missed:             %d\n", uo.Missed)
+	fmt.Fprintf(&b, "Not In Set:         %d\n", uo.NotInSet)
+	fmt.Fprintf(&b, "Uptime:             %s\n", uo.Uptime)
+	fmt.Fprintf(&b, "Average Block Time: %s\n", uo.AverageBlockTime)
+	if len(uo.MissedHeights) > 0 {
+		fmt.Fprintf(&b, "Missed Heights:     %v\n", uo.MissedHeights)
+	}
+
+	return b.String()
+}
+
+// ValidatorUptimeCommand returns the command reporting the signed and missed
+// blocks of a validator over recent blocks.
+func ValidatorUptimeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validator-uptime <consaddr> [height]",
+		Short: "Report the signed and missed blocks of a Baron Chain validator",
+		Long: `Walk the commits of the most recent blocks, or of the blocks up to the given height,
+and report how many of them the validator signed and missed, along with the average
+block time. The validator is identified by its bech32 consensus address, or by its hex
+address as shown by the node. Only the node RPC is used, so no indexer is needed, but
+the node must not have pruned the blocks walked.`,
+		Example: "$ barond query validator-uptime baronvalcons1... --blocks 1000",
+		Args:    cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get query context: %w", err)
+			}
+
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
+			addr, err := parseConsAddress(args[0])
+			if err != nil {
+				return err
+			}
+
+			height, err := parseOptionalHeight(args[1:])
+			if err != nil {
+				return err
+			}
+
+			blocks, _ := cmd.Flags().GetInt64(flagUptimeBlocks)
+			if blocks <= 0 || blocks > maxUptimeBlocks {
+				return fmt.Errorf("--%s must be between 1 and %d, got %d", flagUptimeBlocks, maxUptimeBlocks, blocks)
+			}
+
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return fmt.Errorf("failed to get node: %w", err)
+			}
+
+			result, err := QueryValidatorUptime(cmd.Context(), node, addr, blocks, height)
+			if err != nil {
+				return err
+			}
+
+			return printObject(cmd, clientCtx, result)
+		},
+	}
+
+	cmd.Flags().String(flags.FlagNode, defaultNodeEndpoint, "Baron Chain node RPC endpoint")
+	cmd.Flags().StringP(flags.FlagOutput, "o", "text", "Output format (text|json)")
+	cmd.Flags().Int64(flagUptimeBlocks, defaultUptimeBlocks, "Number of blocks to walk")
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
+
+	return cmd
+}
+
+// parseConsAddress parses a bech32 consensus address, or a hex validator
+// address.
+func parseConsAddress(s string) (sdk.ConsAddress, error) {
+	addr, err := sdk.ConsAddressFromBech32(s)
+	if err == nil {
+		return addr, nil
+	}
+
+	bz, hexErr := hex.DecodeString(s)
+	if hexErr != nil || len(bz) != tmcrypto.AddressSize {
+		return nil, fmt.Errorf("invalid consensus address %q: %w", s, err)
+	}
+	return sdk.ConsAddress(bz), nil
+}
+
+// UptimeNode is the subset of the node RPC client used by
+// QueryValidatorUptime.
+type UptimeNode interface {
+	Status(ctx context.Context) (*coretypes.ResultStatus, error)
+	Commit(ctx context.Context, height *int64) (*coretypes.ResultCommit, error)
+	Validators(ctx context.Context, height *int64, page, perPage *int) (*coretypes.ResultValidators, error)
+}
+
+// QueryValidatorUptime walks the commits of the given number of blocks up to
+// height, or up to the latest height if nil, and returns the signing record of
+// the validator. RPC failures are returned as an *Error.
+func QueryValidatorUptime(ctx context.Context, node UptimeNode, addr sdk.ConsAddress, blocks int64, height *int64) (ValidatorUptimeOutput, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	end := int64(0)
+	if height != nil {
+		end = *height
+	} else {
+		status, err := node.Status(ctx)
+		if err != nil {
+			return ValidatorUptimeOutput{}, wrapRPCError("query node status", err)
+		}
+		end = status.SyncInfo.LatestBlockHeight
+	}
+
+	start := end - blocks + 1
+	if start < 1 {
+		start = 1
+	}
+
+	out := ValidatorUptimeOutput{
+		Address:     addr,
+		StartHeight: start,
+		EndHeight:   end,
+		Uptime:      sdk.ZeroDec(),
+	}
+
+	// validator set membership, by validators hash, as sets rarely change
+	inSet := make(map[string]bool)
+	var startTime, endTime time.Time
+
+	for h := start; h <= end; h++ {
+		res, err := node.Commit(ctx, &h)
+		if err != nil {
+			return ValidatorUptimeOutput{}, wrapRPCError(fmt.Sprintf("query commit at height %d", h), err)
+		}
+		if res.SignedHeader.Header == nil || res.SignedHeader.Commit == nil {
+			return ValidatorUptimeOutput{}, fmt.Errorf("commit at height %d has no header", h)
+		}
+
+		if h == start {
+			startTime = res.Header.Time
+		}
+		endTime = res.Header.Time
+
+		if commitHasVote(res.Commit, addr) {
+			out.Signed++
+			continue
+		}
+
+		valsHash := string(res.Header.ValidatorsHash)
+		member, ok := inSet[valsHash]
+		if !ok {
+			if member, err = isInValidatorSet(ctx, node, h, addr); err != nil {
+				return ValidatorUptimeOutput{}, err
+			}
+			inSet[valsHash] = member
+		}
+
+		if member {
+			out.Missed++
+			out.MissedHeights = append(out.MissedHeights, h)
+		} else {
+			out.NotInSet++
+		}
+	}
+
+	if active := out.Signed + out.Missed; active > 0 {
+		out.Uptime = sdk.NewDec(out.Signed).QuoInt64(active)
+	}
+	if end > start {
+		out.AverageBlockTime = (endTime.Sub(startTime) / time.Duration(end-start)).String()
+	}
+
+	return out, nil
+}
+
+// commitHasVote reports whether the commit holds a vote of the validator, for
+// the block or nil. Absent votes don't hold the validator address.
+func commitHasVote(commit *tmtypes.Commit, addr sdk.ConsAddress) bool {
+	for _, sig := range commit.Signatures {
+		if sig.BlockIDFlag != tmtypes.BlockIDFlagAbsent && addr.Equals(sdk.ConsAddress(sig.ValidatorAddress)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInValidatorSet reports whether the validator is in the validator set at
+// the given height.
+func isInValidatorSet(ctx context.Context, node UptimeNode, height int64, addr sdk.ConsAddress) (bool, error) {
+	perPage := validatorsPerRequest
+	for page := 1; ; page++ {
+		res, err := node.Validators(ctx, &height, &page, &perPage)
+		if err != nil {
+			return false, wrapRPCError(fmt.Sprintf("query validators at height %d", height), err)
+		}
+
+		for _, val := range res.Validators {
+			if addr.Equals(sdk.ConsAddress(val.Address)) {
+				return true, nil
+			}
+		}
+
+		if len(res.Validators) == 0 || page*perPage >= res.Total {
+			return false, nil
+		}
+	}
+}
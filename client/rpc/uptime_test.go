@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coretypes "github.com/baron-chain/cometbft-bc/rpc/core/types"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/baron-chain/cosmos-bc-47/types"
+)
+
+// mockUptimeNode serves commits where the validator at index 0 of vals misses
+// the heights of missed, and is only in the validator set from height joined.
+type mockUptimeNode struct {
+	latest int64
+	joined int64
+	missed map[int64]bool
+	vals   []*tmtypes.Validator
+}
+
+func (n mockUptimeNode) Status(context.Context) (*coretypes.ResultStatus, error) {
+	return &coretypes.ResultStatus{SyncInfo: coretypes.SyncInfo{LatestBlockHeight: n.latest}}, nil
+}
+
+func (n mockUptimeNode) valSet(height int64) []*tmtypes.Validator {
+	if height < n.joined {
+		return n.vals[1:]
+	}
+	return n.vals
+}
+
+func (n mockUptimeNode) Commit(_ context.Context, height *int64) (*coretypes.ResultCommit, error) {
+	h := *height
+	vals := n.valSet(h)
+
+	sigs := make([]tmtypes.CommitSig, len(vals))
+	for i, val := range vals {
+		if val == n.vals[0] && n.missed[h] {
+			sigs[i] = tmtypes.NewCommitSigAbsent()
+			continue
+		}
+		sigs[i] = tmtypes.CommitSig{BlockIDFlag: tmtypes.BlockIDFlagCommit, ValidatorAddress: val.Address}
+	}
+
+	valsHash := []byte("before")
+	if h >= n.joined {
+		valsHash = []byte("after")
+	}
+	return &coretypes.ResultCommit{SignedHeader: tmtypes.SignedHeader{
+		Header: &tmtypes.Header{
+			Height:         h,
+			Time:           time.Unix(0, 0).Add(time.Duration(h) * 5 * time.Second),
+			ValidatorsHash: valsHash,
+		},
+		Commit: &tmtypes.Commit{Height: h, Signatures: sigs},
+	}}, nil
+}
+
+func (n mockUptimeNode) Validators(_ context.Context, height *int64, page, perPage *int) (*coretypes.ResultValidators, error) {
+	vals := n.valSet(*height)
+	start := (*page - 1) * *perPage
+	if start > len(vals) {
+		start = len(vals)
+	}
+	end := start + *perPage
+	if end > len(vals) {
+		end = len(vals)
+	}
+	return &coretypes.ResultValidators{BlockHeight: *height, Validators: vals[start:end], Count: end - start, Total: len(vals)}, nil
+}
+
+func TestQueryValidatorUptime(t *testing.T) {
+	var vals []*tmtypes.Validator
+	for i := 0; i < 3; i++ {
+		val, _ := tmtypes.RandValidator(false, 10)
+		vals = append(vals, val)
+	}
+	node := mockUptimeNode{latest: 20, joined: 6, missed: map[int64]bool{8: true, 15: true}, vals: vals}
+	addr := sdk.ConsAddress(vals[0].Address)
+
+	out, err := QueryValidatorUptime(context.Background(), node, addr, 10, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(11), out.StartHeight)
+	require.Equal(t, int64(20), out.EndHeight)
+	require.Equal(t, int64(9), out.Signed)
+	require.Equal(t, int64(1), out.Missed)
+	require.Equal(t, []int64{15}, out.MissedHeights)
+	require.Equal(t, sdk.NewDecWithPrec(9, 1), out.Uptime)
+	require.Equal(t, "5s", out.AverageBlockTime)
+
+	// blocks before the validator joined the set are not missed
+	height := int64(10)
+	out, err = QueryValidatorUptime(context.Background(), node, addr, 1000, &height)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), out.StartHeight)
+	require.Equal(t, int64(4), out.Signed)
+	require.Equal(t, int64(1), out.Missed)
+	require.Equal(t, int64(5), out.NotInSet)
+
+	parsed, err := parseConsAddress(addr.String())
+	require.NoError(t, err)
+	require.Equal(t, addr, parsed)
+	parsed, err = parseConsAddress(vals[0].Address.String())
+	require.NoError(t, err)
+	require.Equal(t, addr, parsed)
+	_, err = parseConsAddress("invalid")
+	require.Error(t, err)
+}
@@ -15,11 +15,13 @@ import (
 	cryptotypes "github.com/baron-chain/cosmos-bc-47/crypto/types"
 	sdk "github.com/baron-chain/cosmos-bc-47/types"
 	"github.com/baron-chain/cosmos-bc-47/types/query"
+	stakingtypes "github.com/baron-chain/cosmos-bc-47/x/staking/types"
 )
 
 const (
 	defaultNodeEndpoint = "tcp://localhost:26657"
 	defaultLimit       = 100
+	flagEnrich         = "enrich"
 )
 
 type ValidatorOutput struct {
@@ -27,6 +29,17 @@ type ValidatorOutput struct {
 	PubKey           cryptotypes.PubKey `json:"pub_key"`
 	ProposerPriority int64             `json:"proposer_priority"`
 	VotingPower      int64             `json:"voting_power"`
+	Staking          *ValidatorStaking  `json:"staking,omitempty"`
+}
+
+// ValidatorStaking holds the staking module data of a consensus validator,
+// joined to the validator set when --enrich is passed.
+type ValidatorStaking struct {
+	OperatorAddress string  `json:"operator_address"`
+	Moniker         string  `json:"moniker"`
+	Commission      sdk.Dec `json:"commission"`
+	Jailed          bool    `json:"jailed"`
+	Status          string  `json:"status"`
 }
 
 type ValidatorsOutput struct {
@@ -47,6 +60,13 @@ func (vo ValidatorsOutput) String() string {
 		fmt.Fprintf(&b, "  Public Key:        %s\n", val.PubKey)
 		fmt.Fprintf(&b, "  Proposer Priority: %d\n", val.ProposerPriority)
 		fmt.Fprintf(&b, "  Voting Power:      %d\n", val.VotingPower)
+		if val.Staking != nil {
+			fmt.Fprintf(&b, "  Operator Address:  %s\n", val.Staking.OperatorAddress)
+			fmt.Fprintf(&b, "  Moniker:           %s\n", val.Staking.Moniker)
+			fmt.Fprintf(&b, "  Commission:        %s\n", val.Staking.Commission)
+			fmt.Fprintf(&b, "  Jailed:            %t\n", val.Staking.Jailed)
+			fmt.Fprintf(&b, "  Status:            %s\n", val.Staking.Status)
+		}
 	}
 
 	return b.String()
@@ -56,7 +76,7 @@ func ValidatorCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "validator-set [height]",
 		Short:   "Get Baron Chain validator set at a given height",
-		Example: "$ barond query validator-set 1000",
+		Example: "$ barond query validator-set 1000\n$ barond query validator-set --enrich",
 		Args:    cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clientCtx, err := client.GetClientQueryContext(cmd)
@@ -64,6 +84,10 @@ func ValidatorCommand() *cobra.Command {
 				return fmt.Errorf("failed to get query context: %w", err)
 			}
 
+			if clientCtx, err = applyChainProfile(cmd, clientCtx); err != nil {
+				return err
+			}
+
 			height, err := parseOptionalHeight(args)
 			if err != nil {
 				return err
@@ -71,13 +95,14 @@ func ValidatorCommand() *cobra.Command {
 
 			page, _ := cmd.Flags().GetInt(flags.FlagPage)
 			limit, _ := cmd.Flags().GetInt(flags.FlagLimit)
+			enrich, _ := cmd.Flags().GetBool(flagEnrich)
 
-			result, err := QueryValidators(cmd.Context(), clientCtx, height, &page, &limit)
+			result, err := QueryValidators(cmd.Context(), clientCtx, height, &page, &limit, enrich)
 			if err != nil {
-				return fmt.Errorf("failed to query validators: %w", err)
+				return err
 			}
 
-			return clientCtx.PrintObjectLegacy(result)
+			return printObject(cmd, clientCtx, result)
 		},
 	}
 
@@ -85,6 +110,9 @@ func ValidatorCommand() *cobra.Command {
 	cmd.Flags().StringP(flags.FlagOutput, "o", "text", "Output format (text|json)")
 	cmd.Flags().Int(flags.FlagPage, query.DefaultPage, "Page number for paginated results")
 	cmd.Flags().Int(flags.FlagLimit, defaultLimit, "Number of results per page")
+	cmd.Flags().Bool(flagEnrich, false, "Join moniker, operator address, commission and jailed status from the staking module")
+	addChainProfileFlag(cmd)
+	addFieldsFlag(cmd)
 
 	return cmd
 }
@@ -120,7 +148,11 @@ func convertValidatorOutput(validator *tmtypes.Validator) (ValidatorOutput, erro
 	}, nil
 }
 
-func QueryValidators(ctx context.Context, clientCtx client.Context, height *int64, page, limit *int) (ValidatorsOutput, error) {
+// QueryValidators returns a page of the consensus validator set at the given height,
+// or at the latest height if nil. When enrich is set, each validator is joined with
+// its staking module data queried over gRPC at the same height. RPC failures
+// are returned as an *Error.
+func QueryValidators(ctx context.Context, clientCtx client.Context, height *int64, page, limit *int, enrich bool) (ValidatorsOutput, error) {
 	node, err := clientCtx.GetNode()
 	if err != nil {
 		return ValidatorsOutput{}, fmt.Errorf("failed to get node: %w", err)
@@ -128,7 +160,7 @@ func QueryValidators(ctx context.Context, clientCtx client.Context, height *int6
 
 	validatorsRes, err := node.Validators(ctx, height, page, limit)
 	if err != nil {
-		return ValidatorsOutput{}, fmt.Errorf("failed to query validators: %w", err)
+		return ValidatorsOutput{}, wrapRPCError("query validators", err)
 	}
 
 	total := uint64(0)
@@ -144,9 +176,75 @@ func QueryValidators(ctx context.Context, clientCtx client.Context, height *int6
 		}
 	}
 
+	if enrich {
+		if err := enrichValidators(ctx, clientCtx.WithHeight(validatorsRes.BlockHeight), validators); err != nil {
+			return ValidatorsOutput{}, err
+		}
+	}
+
 	return ValidatorsOutput{
 		BlockHeight: validatorsRes.BlockHeight,
 		Validators:  validators,
 		Total:       total,
 	}, nil
 }
+
+// enrichValidators fills the staking data of the given consensus validators,
+// matching them with the staking module validators by consensus address.
+func enrichValidators(ctx context.Context, clientCtx client.Context, validators []ValidatorOutput) error {
+	byConsAddr, err := queryStakingValidators(ctx, clientCtx)
+	if err != nil {
+		return err
+	}
+
+	for i := range validators {
+		val, ok := byConsAddr[validators[i].Address.String()]
+		if !ok {
+			continue
+		}
+
+		validators[i].Staking = &ValidatorStaking{
+			OperatorAddress: val.OperatorAddress,
+			Moniker:         val.GetMoniker(),
+			Commission:      val.Commission.Rate,
+			Jailed:          val.IsJailed(),
+			Status:          val.GetStatus().String(),
+		}
+	}
+
+	return nil
+}
+
+// queryStakingValidators returns all the staking module validators, indexed by
+// consensus address.
+func queryStakingValidators(ctx context.Context, clientCtx client.Context) (map[string]stakingtypes.Validator, error) {
+	queryClient := stakingtypes.NewQueryClient(clientCtx)
+	validators := make(map[string]stakingtypes.Validator)
+
+	var nextKey []byte
+	for {
+		res, err := queryClient.Validators(ctx, &stakingtypes.QueryValidatorsRequest{
+			Pagination: &query.PageRequest{Key: nextKey},
+		})
+		if err != nil {
+			return nil, wrapRPCError("query staking validators", err)
+		}
+
+		for _, val := range res.Validators {
+			if err := val.UnpackInterfaces(clientCtx.InterfaceRegistry); err != nil {
+				return nil, fmt.Errorf("failed to unpack validator %s: %w", val.OperatorAddress, err)
+			}
+
+			consAddr, err := val.GetConsAddr()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get consensus address of validator %s: %w", val.OperatorAddress, err)
+			}
+			validators[consAddr.String()] = val
+		}
+
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return validators, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
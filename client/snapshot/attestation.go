@@ -0,0 +1,182 @@
+package snapshot
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tmcrypto "github.com/baron-chain/cometbft-bc/crypto"
+	"github.com/baron-chain/cometbft-bc/crypto/ed25519"
+	"github.com/baron-chain/cometbft-bc/p2p"
+	"github.com/cloudflare/circl/sign/dilithium/mode3"
+
+	"github.com/baron-chain/cosmos-bc-47/version"
+)
+
+const (
+	// attestationDomain prefixes the signed manifest, so that an attestation
+	// signature can't be replayed as the signature of another message.
+	attestationDomain = "baron-chain snapshot attestation:"
+
+	// AttestationKeyFileName is the file of the attestation key of a node, in
+	// the directory of its node key.
+	AttestationKeyFileName = "snapshot_attestation_key.json"
+)
+
+// ArchiveAttestation is the signature of an archive manifest by a node, made
+// with its Dilithium3 attestation key, see LoadOrGenAttestationKey. The
+// Dilithium3 public key is itself signed by the ed25519 node key, binding the
+// attestation to the node ID which validators know their peers by.
+//
+// The manifest lists the snapshot hash and the chunk checksums, so a valid
+// attestation of a verified archive attests the whole snapshot.
+type ArchiveAttestation struct {
+	// AppCommit is the commit hash of the application binary which dumped the
+	// snapshot, whose version is the AppVersion of the manifest.
+	AppCommit  string `json:"app_commit"`
+	NodeID     string `json:"node_id"`
+	NodePubKey []byte `json:"node_pub_key"`
+	// KeySignature is the signature of PubKey by the node key.
+	KeySignature []byte `json:"key_signature"`
+	// PubKey is the Dilithium3 public key the manifest is signed with.
+	PubKey []byte `json:"pub_key"`
+	// Signature is the Dilithium3 signature of the manifest, without it.
+	Signature []byte `json:"signature"`
+}
+
+// attestationKeyFile is the JSON encoding of an attestation key file.
+type attestationKeyFile struct {
+	// Seed is the seed the Dilithium3 key is expanded from.
+	Seed []byte `json:"seed"`
+}
+
+// LoadOrGenAttestationKey loads the Dilithium3 attestation key of a node from
+// path, or generates it from fresh entropy and writes it there. The key is
+// not derived from the ed25519 node key, so that breaking the node key, e.g.
+// with a quantum computer, doesn't reveal it.
+func LoadOrGenAttestationKey(path string) (*mode3.PrivateKey, error) {
+	var seed [mode3.SeedSize]byte
+
+	bz, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		var f attestationKeyFile
+		if err := json.Unmarshal(bz, &f); err != nil {
+			return nil, fmt.Errorf("invalid attestation key file %s: %w", path, err)
+		}
+		if len(f.Seed) != mode3.SeedSize {
+			return nil, fmt.Errorf("invalid attestation key file %s: seed size %d, expected %d", path, len(f.Seed), mode3.SeedSize)
+		}
+		copy(seed[:], f.Seed)
+
+	case errors.Is(err, os.ErrNotExist):
+		if _, err := rand.Read(seed[:]); err != nil {
+			return nil, err
+		}
+		bz, err := json.Marshal(attestationKeyFile{Seed: seed[:]})
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, bz, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write attestation key file: %w", err)
+		}
+
+	default:
+		return nil, err
+	}
+
+	_, privKey := mode3.NewKeyFromSeed(&seed)
+	return privKey, nil
+}
+
+// attestationSignBytes returns the bytes signed by the attestation of the
+// manifest.
+func attestationSignBytes(m ArchiveManifest) ([]byte, error) {
+	attestation := *m.Attestation
+	attestation.Signature = nil
+	m.Attestation = &attestation
+
+	bz, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(attestationDomain), bz...), nil
+}
+
+// Attest signs the manifest with the attestation key of a node, whose public
+// key is signed by the ed25519 node key.
+func (m *ArchiveManifest) Attest(nodeKey tmcrypto.PrivKey, privKey *mode3.PrivateKey) error {
+	if nodeKey.Type() != ed25519.KeyType {
+		return fmt.Errorf("unsupported node key type %s, expected %s", nodeKey.Type(), ed25519.KeyType)
+	}
+
+	pubKey := privKey.Public().(*mode3.PublicKey)
+	keySignature, err := nodeKey.Sign(pubKey.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to sign the attestation key: %w", err)
+	}
+
+	m.Attestation = &ArchiveAttestation{
+		AppCommit:    version.NewInfo().GitCommit,
+		NodeID:       string(p2p.PubKeyToID(nodeKey.PubKey())),
+		NodePubKey:   nodeKey.PubKey().Bytes(),
+		KeySignature: keySignature,
+		PubKey:       pubKey.Bytes(),
+	}
+
+	signBytes, err := attestationSignBytes(*m)
+	if err != nil {
+		return err
+	}
+	m.Attestation.Signature = make([]byte, mode3.SignatureSize)
+	mode3.SignTo(privKey, signBytes, m.Attestation.Signature)
+	return nil
+}
+
+// VerifyAttestation checks the attestation of the manifest, and that it was
+// made by one of the trusted node IDs, unless none is given.
+func (m ArchiveManifest) VerifyAttestation(trustedNodes []string) error {
+	a := m.Attestation
+	if a == nil {
+		return fmt.Errorf("archive manifest has no attestation")
+	}
+
+	if len(a.NodePubKey) != ed25519.PubKeySize {
+		return fmt.Errorf("invalid attestation node public key size %d", len(a.NodePubKey))
+	}
+	nodePubKey := ed25519.PubKey(a.NodePubKey)
+	if id := string(p2p.PubKeyToID(nodePubKey)); id != a.NodeID {
+		return fmt.Errorf("attestation node ID %s doesn't match its node public key, of node %s", a.NodeID, id)
+	}
+	if !nodePubKey.VerifySignature(a.PubKey, a.KeySignature) {
+		return fmt.Errorf("invalid attestation key signature of node %s", a.NodeID)
+	}
+
+	var pubKey mode3.PublicKey
+	if err := pubKey.UnmarshalBinary(a.PubKey); err != nil {
+		return fmt.Errorf("invalid attestation public key: %w", err)
+	}
+	signBytes, err := attestationSignBytes(m)
+	if err != nil {
+		return err
+	}
+	if !mode3.Verify(&pubKey, signBytes, a.Signature) {
+		return fmt.Errorf("invalid attestation signature of node %s", a.NodeID)
+	}
+
+	if len(trustedNodes) == 0 {
+		return nil
+	}
+	for _, id := range trustedNodes {
+		if id == a.NodeID {
+			return nil
+		}
+	}
+	return fmt.Errorf("archive is attested by node %s, which is not trusted", a.NodeID)
+}
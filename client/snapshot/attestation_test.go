@@ -0,0 +1,97 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/baron-chain/cometbft-bc/crypto/ed25519"
+	"github.com/baron-chain/cometbft-bc/p2p"
+	"github.com/stretchr/testify/require"
+
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+)
+
+func TestArchiveAttestation(t *testing.T) {
+	nodeKey := ed25519.GenPrivKey()
+	nodeID := string(p2p.PubKeyToID(nodeKey.PubKey()))
+	attestationKey, err := LoadOrGenAttestationKey(filepath.Join(t.TempDir(), AttestationKeyFileName))
+	require.NoError(t, err)
+
+	snapshot := &snapshottypes.Snapshot{Height: 100, Format: 3, Chunks: 1, Hash: []byte{1, 2, 3}}
+	snapshot.Metadata.ChunkHashes = [][]byte{{4, 5, 6}}
+	newManifest := func() ArchiveManifest {
+		m := newArchiveManifest("baron-1", snapshot)
+		require.NoError(t, m.Attest(nodeKey, attestationKey))
+		return m
+	}
+
+	m := newManifest()
+	require.Equal(t, nodeID, m.Attestation.NodeID)
+	require.NoError(t, m.VerifyAttestation(nil))
+	require.NoError(t, m.VerifyAttestation([]string{"other", nodeID}))
+	require.ErrorContains(t, m.VerifyAttestation([]string{"other"}), "not trusted")
+
+	// the attestation survives the manifest encoding
+	bz, err := json.Marshal(m)
+	require.NoError(t, err)
+	var decoded ArchiveManifest
+	require.NoError(t, json.Unmarshal(bz, &decoded))
+	require.NoError(t, decoded.VerifyAttestation([]string{nodeID}))
+
+	// any change of the manifest invalidates the attestation
+	m = newManifest()
+	m.ChunkHashes[0] = "000000"
+	require.ErrorContains(t, m.VerifyAttestation(nil), "invalid attestation signature")
+	m = newManifest()
+	m.Attestation.AppCommit = "deadbeef"
+	require.ErrorContains(t, m.VerifyAttestation(nil), "invalid attestation signature")
+
+	// the attestation can't be claimed by another node
+	m = newManifest()
+	otherKey := ed25519.GenPrivKey()
+	m.Attestation.NodePubKey = otherKey.PubKey().Bytes()
+	m.Attestation.NodeID = string(p2p.PubKeyToID(otherKey.PubKey()))
+	require.ErrorContains(t, m.VerifyAttestation(nil), "invalid attestation key signature")
+	m = newManifest()
+	m.Attestation.NodeID = string(p2p.PubKeyToID(otherKey.PubKey()))
+	require.ErrorContains(t, m.VerifyAttestation(nil), "doesn't match its node public key")
+
+	require.ErrorContains(t, newArchiveManifest("baron-1", snapshot).VerifyAttestation(nil), "no attestation")
+}
+
+func TestLoadOrGenAttestationKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", AttestationKeyFileName)
+	key, err := LoadOrGenAttestationKey(path)
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// the key is loaded again, and is independent of other nodes
+	loaded, err := LoadOrGenAttestationKey(path)
+	require.NoError(t, err)
+	require.True(t, key.Equal(loaded))
+	other, err := LoadOrGenAttestationKey(filepath.Join(t.TempDir(), AttestationKeyFileName))
+	require.NoError(t, err)
+	require.False(t, key.Equal(other))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"seed":"AAAA"}`), 0o600))
+	_, err = LoadOrGenAttestationKey(path)
+	require.ErrorContains(t, err, "seed size")
+}
+
+func TestVerifyArchiveCmdAttestation(t *testing.T) {
+	archive := writeTestArchive(t, [][]byte{[]byte("chunk 0")}, true)
+
+	cmd := VerifyArchiveCmd()
+	cmd.SetArgs([]string{"archive.tar.gz", "--" + flagTrustedNodes + "=abc"})
+	cmd.SetOut(new(bytes.Buffer))
+	require.ErrorContains(t, cmd.Execute(), "requires --attestation")
+
+	manifest, err := verifyArchive(bytes.NewReader(archive))
+	require.NoError(t, err)
+	require.ErrorContains(t, manifest.VerifyAttestation(nil), "no attestation")
+}
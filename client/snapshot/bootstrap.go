@@ -0,0 +1,267 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	abci "github.com/baron-chain/cometbft-bc/abci/types"
+	"github.com/baron-chain/cometbft-bc/light"
+	rpchttp "github.com/baron-chain/cometbft-bc/rpc/client/http"
+	"github.com/baron-chain/cometbft-bc/statesync"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/server"
+	servertypes "github.com/baron-chain/cosmos-bc-47/server/types"
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+)
+
+const (
+	flagRPC         = "rpc"
+	flagTrustHeight = "trust-height"
+	flagTrustHash   = "trust-hash"
+	flagTrustPeriod = "trust-period"
+	flagHeight      = "height"
+	flagFormat      = "format"
+
+	// snapshotsQueryPath and snapshotChunkQueryPath are the ABCI query paths
+	// under which the app serves its snapshots over RPC.
+	snapshotsQueryPath     = "/app/snapshots"
+	snapshotChunkQueryPath = "/app/snapshot_chunk"
+)
+
+// BootstrapCmd returns a command which restores the app state from a snapshot
+// fetched from RPC nodes, verified against light client headers, and
+// bootstraps the CometBFT state at the snapshot height, without running the
+// node.
+func BootstrapCmd(appCreator servertypes.AppCreator) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Bootstrap Baron Chain node state from a snapshot served by RPC nodes",
+		Long: `Fetch a snapshot offered by the given RPC nodes, restore it into the application
+database and bootstrap the CometBFT state at the snapshot height, so that the node starts
+from the restored state instead of replaying or fast syncing the chain.
+
+The snapshot app hash is verified against headers verified by a light client, which
+needs at least 2 RPC nodes (the first one is the primary, the others witnesses) and a
+trusted header height and hash. By default the most recent snapshot offered by any of
+the nodes is restored. The node data directory must be empty.`,
+		Example: `  barond snapshots bootstrap --rpc tcp://node1:26657,tcp://node2:26657 \
+    --trust-height 1000000 --trust-hash 4F6D...`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			serverCtx := server.GetServerContextFromCmd(cmd)
+			cfg := serverCtx.Config
+
+			servers, _ := cmd.Flags().GetStringSlice(flagRPC)
+			trustHeight, _ := cmd.Flags().GetInt64(flagTrustHeight)
+			trustHashHex, _ := cmd.Flags().GetString(flagTrustHash)
+			trustPeriod, _ := cmd.Flags().GetDuration(flagTrustPeriod)
+			height, _ := cmd.Flags().GetUint64(flagHeight)
+			format, _ := cmd.Flags().GetUint32(flagFormat)
+
+			if len(servers) < 2 {
+				return fmt.Errorf("at least 2 RPC nodes are required for light client verification, got %d", len(servers))
+			}
+			if trustHeight <= 0 {
+				return fmt.Errorf("--%s must be positive", flagTrustHeight)
+			}
+			trustHash, err := hex.DecodeString(trustHashHex)
+			if err != nil || len(trustHash) == 0 {
+				return fmt.Errorf("invalid --%s %q", flagTrustHash, trustHashHex)
+			}
+
+			genState, err := server.LoadGenesisState(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to load genesis state: %w", err)
+			}
+			if genState.LastBlockHeight > 0 {
+				return fmt.Errorf("node state is already at height %d, bootstrap needs an empty data directory", genState.LastBlockHeight)
+			}
+
+			ctx := cmd.Context()
+			offered, err := findSnapshot(ctx, servers, height, format)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Restoring snapshot at height %d, format %d, %d chunks\n", offered.snapshot.Height, offered.snapshot.Format, offered.snapshot.Chunks)
+
+			stateProvider, err := statesync.NewLightClientStateProvider(
+				ctx,
+				genState.ChainID, genState.Version, genState.InitialHeight,
+				servers, light.TrustOptions{
+					Period: trustPeriod,
+					Height: trustHeight,
+					Hash:   trustHash,
+				}, serverCtx.Logger.With("module", "light"))
+			if err != nil {
+				return fmt.Errorf("failed to set up light client state provider: %w", err)
+			}
+
+			appHash, err := stateProvider.AppHash(ctx, offered.snapshot.Height)
+			if err != nil {
+				return fmt.Errorf("failed to verify app hash at height %d: %w", offered.snapshot.Height, err)
+			}
+
+			db, err := server.OpenDB(cfg.RootDir, server.GetAppDBBackend(serverCtx.Viper))
+			if err != nil {
+				return fmt.Errorf("failed to open DB: %w", err)
+			}
+			defer db.Close()
+
+			app := appCreator(serverCtx.Logger, db, nil, serverCtx.Viper)
+			if err := restoreSnapshot(ctx, app, offered); err != nil {
+				return err
+			}
+
+			restoredHash := app.CommitMultiStore().LastCommitID().Hash
+			if !bytes.Equal(restoredHash, appHash) {
+				return fmt.Errorf("restored app hash %X does not match the verified app hash %X at height %d, the application database must be removed",
+					restoredHash, appHash, offered.snapshot.Height)
+			}
+
+			if err := server.BootstrapStateFromProvider(ctx, cfg, stateProvider, offered.snapshot.Height); err != nil {
+				return fmt.Errorf("failed to bootstrap CometBFT state: %w", err)
+			}
+
+			cmd.Printf("Successfully bootstrapped node state at height %d, app hash %X\n", offered.snapshot.Height, appHash)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice(flagRPC, nil, "Comma separated RPC nodes to fetch the snapshot from and verify it against, at least 2")
+	cmd.Flags().Int64(flagTrustHeight, 0, "Height of the trusted header")
+	cmd.Flags().String(flagTrustHash, "", "Hex encoded hash of the trusted header")
+	cmd.Flags().Duration(flagTrustPeriod, 168*time.Hour, "Trust period of the light client, which should be lower than the unbonding period")
+	cmd.Flags().Uint64(flagHeight, 0, "Height of the snapshot to restore, defaults to the most recent snapshot offered")
+	cmd.Flags().Uint32(flagFormat, 0, "Format of the snapshot to restore, defaults to the highest format offered")
+
+	return cmd
+}
+
+// offeredSnapshot is a snapshot and the RPC nodes serving it.
+type offeredSnapshot struct {
+	snapshot *abci.Snapshot
+	clients  []*rpchttp.HTTP
+}
+
+// findSnapshot returns the most recent snapshot offered by the RPC nodes,
+// restricted to the given height and format if not zero.
+func findSnapshot(ctx context.Context, servers []string, height uint64, format uint32) (*offeredSnapshot, error) {
+	offers := make(map[string]*offeredSnapshot)
+	var best *offeredSnapshot
+
+	for _, addr := range servers {
+		client, err := rpchttp.New(addr, "/websocket")
+		if err != nil {
+			return nil, fmt.Errorf("invalid RPC node %s: %w", addr, err)
+		}
+
+		res, err := client.ABCIQuery(ctx, snapshotsQueryPath, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots of %s: %w", addr, err)
+		}
+		if !res.Response.IsOK() {
+			return nil, fmt.Errorf("failed to list snapshots of %s: %s", addr, res.Response.Log)
+		}
+
+		var list abci.ResponseListSnapshots
+		if err := list.Unmarshal(res.Response.Value); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshots of %s: %w", addr, err)
+		}
+
+		for _, snapshot := range list.Snapshots {
+			if (height != 0 && snapshot.Height != height) || (format != 0 && snapshot.Format != format) {
+				continue
+			}
+
+			// nodes only serve the same snapshot if they agree on its hash
+			key := fmt.Sprintf("%d/%d/%X", snapshot.Height, snapshot.Format, snapshot.Hash)
+			offer, ok := offers[key]
+			if !ok {
+				offer = &offeredSnapshot{snapshot: snapshot}
+				offers[key] = offer
+			}
+			offer.clients = append(offer.clients, client)
+
+			if best == nil || snapshot.Height > best.snapshot.Height ||
+				(snapshot.Height == best.snapshot.Height && snapshot.Format > best.snapshot.Format) {
+				best = offer
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no matching snapshot offered by %s", strings.Join(servers, ", "))
+	}
+	return best, nil
+}
+
+// restoreSnapshot restores the snapshot into the app, fetching each chunk from
+// the first node which serves it. Chunks are verified against the snapshot
+// chunk hashes by the snapshot manager.
+func restoreSnapshot(ctx context.Context, app servertypes.Application, offered *offeredSnapshot) error {
+	sm := app.SnapshotManager()
+	if sm == nil {
+		return fmt.Errorf("snapshot manager not configured")
+	}
+	if app.CommitMultiStore().LastCommitID().Version != 0 {
+		return fmt.Errorf("application database is not empty")
+	}
+
+	snapshot, err := snapshottypes.SnapshotFromABCI(offered.snapshot)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if err := sm.Restore(snapshot); err != nil {
+		return fmt.Errorf("failed to start snapshot restore: %w", err)
+	}
+
+	if len(snapshot.Metadata.ChunkHashes) != int(snapshot.Chunks) {
+		return fmt.Errorf("invalid snapshot: %d chunk hashes for %d chunks", len(snapshot.Metadata.ChunkHashes), snapshot.Chunks)
+	}
+
+	for index := uint32(0); index < snapshot.Chunks; index++ {
+		chunk, err := fetchChunk(ctx, offered, index, snapshot.Metadata.ChunkHashes[index])
+		if err != nil {
+			return err
+		}
+
+		done, err := sm.RestoreChunk(chunk)
+		if err != nil {
+			return fmt.Errorf("failed to restore chunk %d: %w", index, err)
+		}
+		if done {
+			return nil
+		}
+	}
+	return fmt.Errorf("snapshot restore did not complete after %d chunks", snapshot.Chunks)
+}
+
+// fetchChunk fetches a chunk from the first node serving it with the expected
+// hash, so that a node serving a corrupted chunk doesn't abort the restore.
+func fetchChunk(ctx context.Context, offered *offeredSnapshot, index uint32, expectedHash []byte) ([]byte, error) {
+	path := fmt.Sprintf("%s/%d/%d/%d", snapshotChunkQueryPath, offered.snapshot.Height, offered.snapshot.Format, index)
+
+	var lastErr error
+	for _, client := range offered.clients {
+		res, err := client.ABCIQuery(ctx, path, nil)
+		switch {
+		case err != nil:
+			lastErr = err
+		case !res.Response.IsOK():
+			lastErr = fmt.Errorf("%s", res.Response.Log)
+		default:
+			hash := sha256.Sum256(res.Response.Value)
+			if bytes.Equal(hash[:], expectedHash) {
+				return res.Response.Value, nil
+			}
+			lastErr = fmt.Errorf("chunk hash %X does not match the expected hash %X", hash, expectedHash)
+		}
+	}
+	return nil, fmt.Errorf("failed to fetch chunk %d: %w", index, lastErr)
+}
@@ -0,0 +1,310 @@
+package snapshot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+)
+
+const (
+	browseCmdUse   = "browse"
+	browseCmdShort = "Browse the Baron Chain snapshots interactively"
+	browseCmdLong  = `Open an interactive terminal session listing the snapshots of the configured
+snapshot store with their sizes and ages, and run actions on them by their number
+in the list:
+
+` + browseActions + `
+
+Sizes and ages are only known for the local snapshot store. Use the dump command
+to attest or split archives.`
+	browseActions = `  <n>, i[nspect] <n>   Show the metadata and chunk checksums of a snapshot
+  v[erify] <n>         Check the chunks of a snapshot against its checksums
+  x, dump <n> [file]   Dump a snapshot to an archive, <height>-<format>.tar.gz by default
+  d[elete] <n>         Delete a snapshot, after confirmation
+  l[ist]               List the snapshots again
+  h[elp]               Show the actions
+  q[uit]               End the session`
+	browseCmdExample = `  barond snapshots browse`
+
+	browsePrompt = "snapshots> "
+)
+
+// BrowseSnapshotsCmd returns the command browsing the snapshots of the
+// configured snapshot store interactively.
+func BrowseSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     browseCmdUse,
+		Short:   browseCmdShort,
+		Long:    browseCmdLong,
+		Example: browseCmdExample,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			store, err := GetSnapshotStore(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get snapshot store: %w", err)
+			}
+
+			browser := &snapshotBrowser{
+				store:   store,
+				in:      bufio.NewReader(cmd.InOrStdin()),
+				out:     cmd.OutOrStdout(),
+				now:     time.Now,
+				chainID: func() (string, error) { return archiveChainID(cmd) },
+			}
+			return browser.run()
+		},
+	}
+
+	cmd.Flags().String(flags.FlagChainID, "", "Chain ID written to the manifest of dumped archives, read from the genesis file by default")
+	return cmd
+}
+
+// snapshotStatter is implemented by the snapshot stores which know the size
+// and the creation time of their snapshots.
+type snapshotStatter interface {
+	Stat(height uint64, format uint32) (size int64, created time.Time, err error)
+}
+
+// Stat returns the size of the chunk files of a local snapshot, counting the
+// chunks shared with other snapshots by the chunk pool, and the modification
+// time of its directory, i.e. when it was saved or deduplicated.
+func (s *pooledSnapshotStore) Stat(height uint64, format uint32) (int64, time.Time, error) {
+	snapshot, err := s.Get(height, format)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if snapshot == nil {
+		return 0, time.Time{}, fmt.Errorf("snapshot at height %d format %d doesn't exist", height, format)
+	}
+
+	dirInfo, err := os.Stat(filepath.Dir(s.PathChunk(height, format, 0)))
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	var size int64
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		info, err := os.Stat(s.PathChunk(height, format, i))
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		size += info.Size()
+	}
+	return size, dirInfo.ModTime(), nil
+}
+
+// snapshotBrowser is an interactive session of the browse command, reading
+// actions from in.
+type snapshotBrowser struct {
+	store SnapshotStore
+	in    *bufio.Reader
+	out   io.Writer
+	now   func() time.Time
+	// chainID returns the chain ID written to the dumped archives.
+	chainID func() (string, error)
+
+	// snapshots are the listed snapshots, numbered from 1.
+	snapshots []*snapshottypes.Snapshot
+}
+
+// run lists the snapshots and runs the actions read until quit or the end of
+// the input.
+func (b *snapshotBrowser) run() error {
+	if err := b.list(); err != nil {
+		return err
+	}
+
+	for {
+		fmt.Fprint(b.out, browsePrompt)
+		line, readErr := b.in.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		quit, err := b.exec(strings.Fields(line))
+		if err != nil {
+			fmt.Fprintf(b.out, "Error: %v\n", err)
+		}
+		if quit || readErr == io.EOF {
+			fmt.Fprintln(b.out)
+			return nil
+		}
+	}
+}
+
+// exec runs an action, and returns whether the session ends.
+func (b *snapshotBrowser) exec(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	action := args[0]
+	if _, err := strconv.Atoi(action); err == nil {
+		action, args = "inspect", append([]string{"inspect"}, args...)
+	}
+
+	switch action {
+	case "q", "quit", "exit":
+		return true, nil
+
+	case "h", "help", "?":
+		fmt.Fprintln(b.out, browseActions)
+		return false, nil
+
+	case "l", "list":
+		return false, b.list()
+	}
+
+	snapshot, err := b.selected(args)
+	if err != nil {
+		return false, err
+	}
+
+	switch action {
+	case "i", "inspect":
+		b.inspect(snapshot)
+		return false, nil
+
+	case "v", "verify":
+		return false, b.verify(snapshot)
+
+	case "d", "delete":
+		return false, b.delete(snapshot)
+
+	case "x", "dump":
+		return false, b.dump(snapshot, args[2:])
+
+	default:
+		return false, fmt.Errorf("unknown action %q, type help for the list of actions", action)
+	}
+}
+
+// selected returns the snapshot whose number is the argument of the action.
+func (b *snapshotBrowser) selected(args []string) (*snapshottypes.Snapshot, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("%s requires the number of a snapshot", args[0])
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 1 || n > len(b.snapshots) {
+		return nil, fmt.Errorf("invalid snapshot number %q, expected 1 to %d", args[1], len(b.snapshots))
+	}
+	return b.snapshots[n-1], nil
+}
+
+// list lists the snapshots of the store again.
+func (b *snapshotBrowser) list() error {
+	snapshots, err := b.store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	b.snapshots = snapshots
+
+	if len(snapshots) == 0 {
+		fmt.Fprintln(b.out, "No snapshots found")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(b.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tHEIGHT\tFORMAT\tCHUNKS\tSIZE\tAGE")
+	for i, snapshot := range snapshots {
+		size, age := b.stat(snapshot)
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%s\t%s\n", i+1, snapshot.Height, snapshot.Format, snapshot.Chunks, size, age)
+	}
+	return tw.Flush()
+}
+
+// stat returns the rendered size and age of a snapshot, "-" when the store
+// doesn't know them and "?" when they can't be read.
+func (b *snapshotBrowser) stat(snapshot *snapshottypes.Snapshot) (string, string) {
+	statter, ok := b.store.(snapshotStatter)
+	if !ok {
+		return "-", "-"
+	}
+	size, created, err := statter.Stat(snapshot.Height, snapshot.Format)
+	if err != nil {
+		return "?", "?"
+	}
+	return humanize.IBytes(uint64(size)), humanize.RelTime(created, b.now(), "ago", "from now")
+}
+
+func (b *snapshotBrowser) inspect(snapshot *snapshottypes.Snapshot) {
+	size, age := b.stat(snapshot)
+	fmt.Fprintf(b.out, "Height:  %d\n", snapshot.Height)
+	fmt.Fprintf(b.out, "Format:  %d\n", snapshot.Format)
+	fmt.Fprintf(b.out, "Chunks:  %d\n", snapshot.Chunks)
+	fmt.Fprintf(b.out, "Hash:    %X\n", snapshot.Hash)
+	fmt.Fprintf(b.out, "Size:    %s\n", size)
+	fmt.Fprintf(b.out, "Age:     %s\n", age)
+	for i, hash := range snapshot.Metadata.ChunkHashes {
+		fmt.Fprintf(b.out, "  chunk %d: %X\n", i, hash)
+	}
+}
+
+// verify loads the chunks of a snapshot and checks them against the
+// checksums of its metadata.
+func (b *snapshotBrowser) verify(snapshot *snapshottypes.Snapshot) error {
+	if int(snapshot.Chunks) != len(snapshot.Metadata.ChunkHashes) {
+		return fmt.Errorf("snapshot has %d chunks but %d chunk checksums", snapshot.Chunks, len(snapshot.Metadata.ChunkHashes))
+	}
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		if _, err := loadVerifiedChunk(b.store, snapshot, i); err != nil {
+			return fmt.Errorf("chunk %d: %w", i, err)
+		}
+		fmt.Fprintf(b.out, "\rVerified %d/%d chunks", i+1, snapshot.Chunks)
+	}
+	fmt.Fprintf(b.out, "\nSnapshot at height %d format %d is valid\n", snapshot.Height, snapshot.Format)
+	return nil
+}
+
+func (b *snapshotBrowser) delete(snapshot *snapshottypes.Snapshot) error {
+	fmt.Fprintf(b.out, "Delete snapshot at height %d format %d? [y/N] ", snapshot.Height, snapshot.Format)
+	answer, err := b.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		fmt.Fprintln(b.out, "Not deleted")
+		return nil
+	}
+
+	if err := b.store.Delete(snapshot.Height, snapshot.Format); err != nil {
+		return fmt.Errorf("failed to delete snapshot at height %d format %d: %w", snapshot.Height, snapshot.Format, err)
+	}
+	fmt.Fprintf(b.out, "Deleted snapshot at height %d format %d\n", snapshot.Height, snapshot.Format)
+	return b.list()
+}
+
+func (b *snapshotBrowser) dump(snapshot *snapshottypes.Snapshot, args []string) error {
+	outputPath := fmt.Sprintf("%d-%d%s", snapshot.Height, snapshot.Format, archiveExt)
+	if len(args) > 0 {
+		outputPath = args[0]
+	}
+	chainID, err := b.chainID()
+	if err != nil {
+		return err
+	}
+
+	dumper := &snapshotDumper{
+		store:      b.store,
+		height:     snapshot.Height,
+		format:     snapshot.Format,
+		chainID:    chainID,
+		outputPath: outputPath,
+	}
+	if _, err := dumper.dump(); err != nil {
+		return fmt.Errorf("failed to dump snapshot: %w", err)
+	}
+	fmt.Fprintf(b.out, "Dumped snapshot to %s\n", outputPath)
+	return nil
+}
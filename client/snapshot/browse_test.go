@@ -0,0 +1,87 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/require"
+
+	"github.com/baron-chain/cosmos-bc-47/snapshots"
+)
+
+// browse runs a browse session of the given input lines, and returns its
+// output.
+func browse(t *testing.T, store SnapshotStore, lines ...string) string {
+	t.Helper()
+
+	var out bytes.Buffer
+	browser := &snapshotBrowser{
+		store:   store,
+		in:      bufio.NewReader(strings.NewReader(strings.Join(lines, "\n"))),
+		out:     &out,
+		now:     func() time.Time { return time.Now().Add(2 * time.Hour) },
+		chainID: func() (string, error) { return "baron-1", nil },
+	}
+	require.NoError(t, browser.run())
+	return out.String()
+}
+
+func TestSnapshotBrowser(t *testing.T) {
+	dir := t.TempDir()
+	snapshotStore, err := snapshots.NewStore(dbm.NewMemDB(), dir)
+	require.NoError(t, err)
+	store := &pooledSnapshotStore{Store: snapshotStore, dir: dir}
+
+	saveTestSnapshot(t, snapshotStore, 1, bytes.Repeat([]byte{'a'}, 1024), bytes.Repeat([]byte{'b'}, 1024))
+	saveTestSnapshot(t, snapshotStore, 2, bytes.Repeat([]byte{'c'}, 512))
+
+	out := browse(t, store, "list")
+	require.Contains(t, out, "#  HEIGHT  FORMAT  CHUNKS  SIZE     AGE")
+	require.Contains(t, out, "1  2       1       1       512 B    2 hours ago")
+	require.Contains(t, out, "2  1       1       2       2.0 KiB  2 hours ago")
+
+	out = browse(t, store, "2", "verify 2", "inspect 3", "frobnicate 1", "q")
+	require.Contains(t, out, "Height:  1\nFormat:  1\nChunks:  2\n")
+	require.Contains(t, out, "Verified 2/2 chunks\nSnapshot at height 1 format 1 is valid")
+	require.Contains(t, out, `Error: invalid snapshot number "3", expected 1 to 2`)
+	require.Contains(t, out, `Error: unknown action "frobnicate"`)
+
+	// corrupted chunks are reported
+	require.NoError(t, os.WriteFile(snapshotStore.PathChunk(1, 1, 1), []byte("corrupted"), 0o600))
+	out = browse(t, store, "v 2")
+	require.Contains(t, out, "Error: chunk 1: checksum mismatch")
+
+	archive := filepath.Join(t.TempDir(), "2.tar.gz")
+	out = browse(t, store, "dump 1 "+archive, "d 1", "n", "d 1", "y")
+	require.Contains(t, out, "Dumped snapshot to "+archive)
+	require.Contains(t, out, "Not deleted")
+	require.Contains(t, out, "Deleted snapshot at height 2 format 1")
+	snapshot, err := store.Get(2, 1)
+	require.NoError(t, err)
+	require.Nil(t, snapshot)
+
+	f, err := os.Open(archive)
+	require.NoError(t, err)
+	defer f.Close()
+	manifest, err := verifyArchive(f)
+	require.NoError(t, err)
+	require.Equal(t, "baron-1", manifest.ChainID)
+	require.Equal(t, uint64(2), manifest.Height)
+}
+
+func TestSnapshotBrowserRemoteStore(t *testing.T) {
+	store := newRemoteSnapshotStore(context.Background(), newMemObjectStore(), "snapshots")
+	require.Contains(t, browse(t, store), "No snapshots found")
+
+	saveItemsSnapshot(t, store, 5, 1, nil)
+	out := browse(t, store, "i 1")
+	require.Contains(t, out, "1  5       1       1       -     -")
+	require.Contains(t, out, "Size:    -\n")
+}
@@ -7,9 +7,21 @@ import (
 
 const (
 	snapshotCmdName        = "snapshots"
-	snapshotCmdShortDesc   = "Manage Baron Chain local snapshots"
-	snapshotCmdLongDesc    = `Manage local snapshots for Baron Chain state sync and backup.
-This command provides functionality to list, restore, export, and manage snapshots.`
+	snapshotCmdShortDesc   = "Manage Baron Chain snapshots"
+	snapshotCmdLongDesc    = `Manage snapshots for Baron Chain state sync and backup.
+This command provides functionality to list, restore, export, and manage snapshots.
+
+The list, dump, load, delete and prune commands operate on the snapshot store configured
+in the [snapshot-store] section of app.toml, which is either the node's local snapshot
+directory or a remote s3 or gcs bucket. Export and restore always use the local store.
+The dedup command stores the chunks which are identical across the local snapshots once,
+and the upgrade-format command rewrites the snapshots of older formats into a newer one.
+The browse command lists the snapshots interactively, to inspect, verify, dump or delete them.
+The publish-torrent command generates a .torrent with webseeds for a dumped archive, to
+distribute it with BitTorrent.
+
+The bootstrap command restores a snapshot served by RPC nodes, verified with a light
+client, and bootstraps the node state at its height without running state sync.`
 )
 
 // Cmd returns the snapshots management command group for Baron Chain
@@ -22,12 +34,19 @@ func Cmd(appCreator servertypes.AppCreator) *cobra.Command {
 	}
 
 	cmd.AddCommand(
-		ListSnapshotsCmd,
+		NewListSnapshotsCmd(),
+		BrowseSnapshotsCmd(),
 		RestoreSnapshotCmd(appCreator),
+		BootstrapCmd(appCreator),
 		ExportSnapshotCmd(appCreator),
 		DumpArchiveCmd(),
 		LoadArchiveCmd(),
+		VerifyArchiveCmd(),
+		PublishTorrentCmd(),
 		DeleteSnapshotCmd(),
+		PruneSnapshotsCmd(),
+		DedupSnapshotsCmd(),
+		UpgradeFormatCmd(),
 	)
 
 	return cmd
@@ -37,18 +56,42 @@ func getExamples() string {
 	return `  # List all available snapshots
   barond snapshots list
 
+  # Browse, verify and delete snapshots interactively
+  barond snapshots browse
+
   # Export a snapshot at a specific height
   barond snapshots export --height 1000000
 
   # Restore from a snapshot
   barond snapshots restore <snapshot-file>
 
+  # Bootstrap node state from a snapshot served by RPC nodes
+  barond snapshots bootstrap --rpc tcp://node1:26657,tcp://node2:26657 --trust-height 1000000 --trust-hash <hash>
+
   # Dump snapshot to archive
   barond snapshots dump <snapshot-name>
 
   # Load snapshot from archive
   barond snapshots load <archive-name>
 
+  # Verify archive chunks against its manifest
+  barond snapshots verify <archive-name>
+
+  # Verify an archive is attested by a trusted validator node
+  barond snapshots verify <archive-name> --attestation --trusted-nodes <node-id>
+
+  # Generate a torrent of an archive, downloaded from a mirror when there are no peers
+  barond snapshots publish-torrent 1000000 1 --webseed https://snapshots.example.com/
+
   # Delete a snapshot
-  barond snapshots delete <snapshot-name>`
+  barond snapshots delete <snapshot-name>
+
+  # Keep only the 2 most recent snapshot heights
+  barond snapshots prune --keep-recent 2
+
+  # Deduplicate the chunks of the local snapshots
+  barond snapshots dedup --apply
+
+  # Rewrite the snapshots of older formats into format 3
+  barond snapshots upgrade-format --to 3`
 }
@@ -0,0 +1,199 @@
+package snapshot
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+const flagApply = "apply"
+
+// DedupReport reports the chunks of the local snapshots which are identical
+// across heights.
+type DedupReport struct {
+	Snapshots    int
+	Chunks       int
+	UniqueChunks int
+	// TotalBytes is the size of all the chunk files.
+	TotalBytes int64
+	// UniqueBytes is the size of the distinct chunks, which is the disk usage
+	// of the chunks once deduplicated.
+	UniqueBytes int64
+	// SavedBytes is the size of the chunk files already deduplicated.
+	SavedBytes int64
+	// ReclaimableBytes is the size of the chunk files a deduplication frees.
+	ReclaimableBytes int64
+	// Deduplicated is the number of chunk files replaced by links to the pool,
+	// when deduplication was applied.
+	Deduplicated int
+}
+
+func (r DedupReport) String() string {
+	return fmt.Sprintf(`Snapshots:        %d
+Chunks:           %d (%s)
+Unique chunks:    %d (%s)
+Already saved:    %s
+Reclaimable:      %s`,
+		r.Snapshots,
+		r.Chunks, humanize.IBytes(uint64(r.TotalBytes)),
+		r.UniqueChunks, humanize.IBytes(uint64(r.UniqueBytes)),
+		humanize.IBytes(uint64(r.SavedBytes)),
+		humanize.IBytes(uint64(r.ReclaimableBytes)))
+}
+
+// DedupSnapshotsCmd returns a command to deduplicate the chunks of the local
+// snapshots.
+func DedupSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dedup",
+		Short: "Deduplicate identical chunks of the local Baron Chain snapshots",
+		Long: `Find the chunks which are identical across the heights and formats of the local
+snapshot store, and report the disk space their deduplication saves.
+
+With --apply, identical chunks are stored once in a content-addressed pool, in the
+chunks directory of the snapshot store, and the chunk files of the snapshots are
+replaced by hard links to it, so that the node still serves them. The pool records
+the chunk files referencing each pooled chunk, and pooled chunks are removed once
+the snapshots referencing them are deleted or pruned.
+
+Only the local snapshot store can be deduplicated, on a filesystem supporting hard
+links.`,
+		Example: `  # Report the disk space deduplication saves
+  barond snapshots dedup
+
+  # Deduplicate the chunks
+  barond snapshots dedup --apply`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			snapshotStore, err := GetSnapshotStore(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get snapshot store: %w", err)
+			}
+			local, ok := snapshotStore.(*pooledSnapshotStore)
+			if !ok {
+				return fmt.Errorf("only the %s snapshot store can be deduplicated", StoreBackendLocal)
+			}
+
+			apply, _ := cmd.Flags().GetBool(flagApply)
+			report, err := dedupSnapshots(local, apply)
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(report)
+			if apply {
+				cmd.Printf("Successfully deduplicated %d chunk files, freeing %s\n",
+					report.Deduplicated, humanize.IBytes(uint64(report.ReclaimableBytes)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagApply, false, "Replace identical chunks by links to a single copy, instead of only reporting the savings")
+	return cmd
+}
+
+// dedupSnapshots finds the identical chunks of the local snapshots, and
+// links them to the chunk pool when apply is set.
+func dedupSnapshots(store *pooledSnapshotStore, apply bool) (DedupReport, error) {
+	var report DedupReport
+
+	pool, err := loadChunkPool(store.dir)
+	if err != nil {
+		return report, err
+	}
+
+	// chunk files already linked to the pool don't need to be hashed again
+	pooled := make(map[string]string)
+	for hash, c := range pool.index.Chunks {
+		for _, ref := range c.Refs {
+			pooled[ref] = hash
+		}
+	}
+
+	list, err := store.List()
+	if err != nil {
+		return report, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	type chunkGroup struct {
+		size   int64
+		refs   []string
+		linked int
+	}
+	groups := make(map[string]*chunkGroup)
+	var hashes []string
+	for _, snapshot := range list {
+		report.Snapshots++
+		for i := uint32(0); i < snapshot.Chunks; i++ {
+			ref := chunkRef(snapshot.Height, snapshot.Format, i)
+
+			hash, linked := pooled[ref]
+			linked = linked && pool.linked(hash, ref)
+			var size int64
+			if linked {
+				size = pool.index.Chunks[hash].Size
+			} else if hash, size, err = hashChunkFile(filepath.Join(store.dir, ref)); err != nil {
+				return report, fmt.Errorf("failed to read chunk %d of snapshot at height %d format %d: %w",
+					i, snapshot.Height, snapshot.Format, err)
+			}
+
+			g, ok := groups[hash]
+			if !ok {
+				g = &chunkGroup{size: size}
+				groups[hash] = g
+				hashes = append(hashes, hash)
+			}
+			g.refs = append(g.refs, ref)
+			if linked {
+				g.linked++
+			}
+		}
+	}
+
+	for _, hash := range hashes {
+		g := groups[hash]
+		report.Chunks += len(g.refs)
+		report.UniqueChunks++
+		report.TotalBytes += int64(len(g.refs)) * g.size
+		report.UniqueBytes += g.size
+
+		// the linked chunk files share a single copy
+		copies := len(g.refs) - g.linked
+		if g.linked > 0 {
+			copies++
+		}
+		report.SavedBytes += int64(len(g.refs)-copies) * g.size
+		report.ReclaimableBytes += int64(copies-1) * g.size
+	}
+
+	if !apply {
+		return report, nil
+	}
+
+	for _, hash := range hashes {
+		g := groups[hash]
+		for _, ref := range g.refs {
+			replaced, err := pool.add(hash, ref, g.size)
+			if err != nil {
+				// save the chunks pooled so far, so that they are not
+				// collected as orphans by the next run
+				_ = pool.save()
+				return report, fmt.Errorf("failed to deduplicate chunk %s: %w", ref, err)
+			}
+			if replaced {
+				report.Deduplicated++
+			}
+		}
+	}
+
+	if _, _, err := pool.collect(); err != nil {
+		return report, err
+	}
+	if err := pool.save(); err != nil {
+		return report, fmt.Errorf("failed to save chunk pool index: %w", err)
+	}
+	return report, nil
+}
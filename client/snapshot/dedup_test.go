@@ -0,0 +1,103 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/require"
+
+	"github.com/baron-chain/cosmos-bc-47/snapshots"
+)
+
+func saveTestSnapshot(t *testing.T, store *snapshots.Store, height uint64, chunks ...[]byte) {
+	t.Helper()
+
+	ch := make(chan io.ReadCloser, len(chunks))
+	for _, chunk := range chunks {
+		ch <- io.NopCloser(bytes.NewReader(chunk))
+	}
+	close(ch)
+	_, err := store.Save(height, 1, ch)
+	require.NoError(t, err)
+}
+
+func TestDedupSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	snapshotStore, err := snapshots.NewStore(dbm.NewMemDB(), dir)
+	require.NoError(t, err)
+	store := &pooledSnapshotStore{Store: snapshotStore, dir: dir}
+
+	a, b, c := bytes.Repeat([]byte{'a'}, 100), bytes.Repeat([]byte{'b'}, 100), bytes.Repeat([]byte{'c'}, 100)
+	saveTestSnapshot(t, snapshotStore, 1, a, b)
+	saveTestSnapshot(t, snapshotStore, 2, a, c)
+	saveTestSnapshot(t, snapshotStore, 3, a, b)
+
+	report, err := dedupSnapshots(store, false)
+	require.NoError(t, err)
+	require.Equal(t, DedupReport{
+		Snapshots:        3,
+		Chunks:           6,
+		UniqueChunks:     3,
+		TotalBytes:       600,
+		UniqueBytes:      300,
+		ReclaimableBytes: 300,
+	}, report)
+	_, err = os.Stat(filepath.Join(dir, chunkPoolDir))
+	require.ErrorIs(t, err, os.ErrNotExist, "the report doesn't change the store")
+
+	report, err = dedupSnapshots(store, true)
+	require.NoError(t, err)
+	require.Equal(t, 3, report.Deduplicated)
+
+	// identical chunks are links to the pool, and still readable by the store
+	info1, err := os.Stat(snapshotStore.PathChunk(1, 1, 0))
+	require.NoError(t, err)
+	info3, err := os.Stat(snapshotStore.PathChunk(3, 1, 0))
+	require.NoError(t, err)
+	require.True(t, os.SameFile(info1, info3))
+	chunk, err := snapshotStore.LoadChunk(2, 1, 0)
+	require.NoError(t, err)
+	bz, err := io.ReadAll(chunk)
+	require.NoError(t, err)
+	require.NoError(t, chunk.Close())
+	require.Equal(t, a, bz)
+
+	report, err = dedupSnapshots(store, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(300), report.SavedBytes)
+	require.Zero(t, report.ReclaimableBytes)
+
+	// pooled chunks are removed once no snapshot references them
+	pool, err := loadChunkPool(dir)
+	require.NoError(t, err)
+	require.Len(t, pool.index.Chunks, 3)
+	require.NoError(t, store.Delete(1, 1))
+	pool, err = loadChunkPool(dir)
+	require.NoError(t, err)
+	require.Len(t, pool.index.Chunks, 3)
+	require.Equal(t, []string{"2/1/0", "3/1/0"}, pool.index.Chunks[hashOf(a)].Refs)
+
+	pruned, err := store.Prune(1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), pruned)
+
+	pool, err = loadChunkPool(dir)
+	require.NoError(t, err)
+	require.Len(t, pool.index.Chunks, 2)
+	require.NotContains(t, pool.index.Chunks, hashOf(c))
+	require.Equal(t, []string{"3/1/0"}, pool.index.Chunks[hashOf(a)].Refs)
+	require.Equal(t, []string{"3/1/1"}, pool.index.Chunks[hashOf(b)].Refs)
+	_, err = os.Stat(pool.path(hashOf(c)))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func hashOf(bz []byte) string {
+	hash := sha256.Sum256(bz)
+	return hex.EncodeToString(hash[:])
+}
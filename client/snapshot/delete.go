@@ -5,13 +5,12 @@ import (
 	"strconv"
 
 	"github.com/spf13/cobra"
-	"github.com/baron-chain/cosmos-bc-47/server"
 )
 
 const (
 	deleteCommandUse     = "delete <height> <format>"
-	deleteCommandShort   = "Delete a local Baron Chain snapshot"
-	deleteCommandLong    = `Delete a local snapshot from the Baron Chain node.
+	deleteCommandShort   = "Delete a Baron Chain snapshot"
+	deleteCommandLong    = `Delete a snapshot from the configured snapshot store.
 
 Arguments:
   height - The height of the snapshot to delete
@@ -42,8 +41,7 @@ func DeleteSnapshotCmd() *cobra.Command {
 				return fmt.Errorf("invalid format: %w", err)
 			}
 
-			ctx := server.GetServerContextFromCmd(cmd)
-			snapshotStore, err := server.GetSnapshotStore(ctx.Viper)
+			snapshotStore, err := GetSnapshotStore(cmd)
 			if err != nil {
 				return fmt.Errorf("failed to get snapshot store: %w", err)
 			}
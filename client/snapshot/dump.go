@@ -2,38 +2,74 @@ package snapshot
 
 import (
 	"archive/tar"
-	"compress/gzip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
 	"strconv"
 
+	tmcrypto "github.com/baron-chain/cometbft-bc/crypto"
+	"github.com/baron-chain/cometbft-bc/p2p"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
+	"github.com/cloudflare/circl/sign/dilithium/mode3"
 	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/client/flags"
 	"github.com/baron-chain/cosmos-bc-47/server"
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
 )
 
 const (
 	dumpCmdUse     = "dump <height> <format>"
 	dumpCmdShort   = "Dump Baron Chain snapshot as portable archive"
 	dumpCmdLong    = `Export a Baron Chain snapshot to a portable gzipped tar archive.
-The archive will contain the snapshot metadata and all associated chunk files.`
+The archive contains a manifest.json file describing the snapshot (chain-id, app
+version, height, chunk SHA-256 checksums, creation time and tool version), followed
+by the snapshot metadata and all associated chunk files. The chain-id is read from
+the node genesis file unless given with --chain-id.
+
+With --attest, the manifest also holds the commit hash of the binary and is signed
+with the Dilithium3 attestation key of the node, certified by its node key, so that
+validators receiving the archive can check with "snapshots verify --attestation"
+which node dumped it. The attestation key is generated on first use, in the
+snapshot_attestation_key.json file next to the node key.
+
+With --split-size, the archive is written in sequential parts of at most that size,
+e.g. 1000000-1.part000.tar.gz, 1000000-1.part001.tar.gz, so that multi-terabyte
+snapshots can be uploaded to object stores with object size limits. Chunks are not
+split across parts, and the sizes and SHA-256 checksums of the parts are written to
+a split manifest, e.g. 1000000-1.parts.json, to give to the load command.`
 	dumpCmdExample = `  # Dump snapshot at height 1000000 with format 1
   barond snapshots dump 1000000 1
 
   # Dump snapshot with custom output file
-  barond snapshots dump 1000000 1 -o custom_backup.tar.gz`
+  barond snapshots dump 1000000 1 -o custom_backup.tar.gz
+
+  # Dump snapshot attested by the node key
+  barond snapshots dump 1000000 1 --attest
+
+  # Dump snapshot in parts of at most 2GB
+  barond snapshots dump 1000000 1 --split-size 2GB`
 
 	defaultFileMode = 0o644
 	flagOutput      = "output"
 	flagOutputShort = "o"
+	flagAttest      = "attest"
 )
 
 type snapshotDumper struct {
-	store     server.SnapshotStore
-	height    uint64
-	format    uint32
+	store      SnapshotStore
+	height     uint64
+	format     uint32
+	chainID    string
 	outputPath string
+	// nodeKey and attestationKey attest the archive, when set
+	nodeKey        tmcrypto.PrivKey
+	attestationKey *mode3.PrivateKey
+	// splitSize is the maximum size of the archive parts, when set
+	splitSize int64
 }
 
 func DumpArchiveCmd() *cobra.Command {
@@ -47,6 +83,9 @@ func DumpArchiveCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP(flagOutput, flagOutputShort, "", "Output file path")
+	cmd.Flags().String(flags.FlagChainID, "", "Chain ID written to the archive manifest, read from the genesis file by default")
+	cmd.Flags().Bool(flagAttest, false, "Sign the archive manifest with the attestation key of the node, certified by the node key")
+	cmd.Flags().String(flagSplitSize, "", "Split the archive in parts of at most this size, e.g. 2GB or 500MiB")
 	return cmd
 }
 
@@ -61,8 +100,7 @@ func runDumpCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid format: %w", err)
 	}
 
-	ctx := server.GetServerContextFromCmd(cmd)
-	store, err := server.GetSnapshotStore(ctx.Viper)
+	store, err := GetSnapshotStore(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to get snapshot store: %w", err)
 	}
@@ -72,116 +110,173 @@ func runDumpCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	if outputPath == "" {
-		outputPath = fmt.Sprintf("%d-%d.tar.gz", height, format)
+		outputPath = fmt.Sprintf("%d-%d%s", height, format, archiveExt)
+	}
+
+	var splitSize int64
+	if s, _ := cmd.Flags().GetString(flagSplitSize); s != "" {
+		if splitSize, err = parseSplitSize(s); err != nil {
+			return err
+		}
+	}
+
+	chainID, err := archiveChainID(cmd)
+	if err != nil {
+		return err
 	}
 
 	dumper := &snapshotDumper{
 		store:      store,
 		height:     height,
 		format:     format,
+		chainID:    chainID,
 		outputPath: outputPath,
+		splitSize:  splitSize,
+	}
+
+	if attest, _ := cmd.Flags().GetBool(flagAttest); attest {
+		nodeKeyFile := server.GetServerContextFromCmd(cmd).Config.NodeKeyFile()
+		nodeKey, err := p2p.LoadNodeKey(nodeKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load node key: %w", err)
+		}
+		attestationKey, err := LoadOrGenAttestationKey(filepath.Join(filepath.Dir(nodeKeyFile), AttestationKeyFileName))
+		if err != nil {
+			return fmt.Errorf("failed to load attestation key: %w", err)
+		}
+		dumper.nodeKey, dumper.attestationKey = nodeKey.PrivKey, attestationKey
 	}
 
-	if err := dumper.dump(); err != nil {
+	parts, err := dumper.dump()
+	if err != nil {
 		return fmt.Errorf("failed to dump snapshot: %w", err)
 	}
 
+	if splitSize > 0 {
+		cmd.Printf("Successfully dumped snapshot to %d parts, described by %s\n", len(parts), splitManifestPath(outputPath))
+		return nil
+	}
 	cmd.Printf("Successfully dumped snapshot to %s\n", outputPath)
 	return nil
 }
 
-func (d *snapshotDumper) dump() error {
+// archiveChainID returns the chain ID written to the archive manifests, given
+// with --chain-id or read from the genesis file.
+func archiveChainID(cmd *cobra.Command) (string, error) {
+	if chainID, _ := cmd.Flags().GetString(flags.FlagChainID); chainID != "" {
+		return chainID, nil
+	}
+	genDoc, err := tmtypes.GenesisDocFromFile(server.GetServerContextFromCmd(cmd).Config.GenesisFile())
+	if err != nil {
+		return "", fmt.Errorf("failed to read chain-id from genesis file, use --%s: %w", flags.FlagChainID, err)
+	}
+	return genDoc.ChainID, nil
+}
+
+// dump writes the archive, and returns its parts when it is split.
+func (d *snapshotDumper) dump() ([]ArchivePart, error) {
 	snapshot, err := d.store.Get(d.height, d.format)
 	if err != nil {
-		return fmt.Errorf("failed to get snapshot: %w", err)
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
 	}
 	if snapshot == nil {
-		return fmt.Errorf("snapshot at height %d format %d doesn't exist", d.height, d.format)
+		return nil, fmt.Errorf("snapshot at height %d format %d doesn't exist", d.height, d.format)
 	}
 
 	snapshotBytes, err := snapshot.Marshal()
 	if err != nil {
-		return fmt.Errorf("failed to marshal snapshot: %w", err)
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
 	}
 
-	file, err := os.Create(d.outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+	manifest := newArchiveManifest(d.chainID, snapshot)
+	if d.nodeKey != nil {
+		if err := manifest.Attest(d.nodeKey, d.attestationKey); err != nil {
+			return nil, fmt.Errorf("failed to attest snapshot: %w", err)
+		}
 	}
-	defer file.Close()
 
-	gzipWriter, err := gzip.NewWriterLevel(file, gzip.BestSpeed)
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to create gzip writer: %w", err)
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
 	}
-	defer gzipWriter.Close()
 
-	tarWriter := tar.NewWriter(gzipWriter)
-	defer tarWriter.Close()
+	w := newArchiveWriter(d.outputPath, d.splitSize)
+	defer w.abort()
 
-	if err := d.writeSnapshotMetadata(tarWriter, snapshotBytes); err != nil {
-		return err
+	if err := w.writeFile(ManifestFileName, manifestBytes); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
 	}
 
-	if err := d.writeChunkFiles(tarWriter, snapshot.Chunks); err != nil {
-		return err
+	if err := w.writeFile(SnapshotFileName, snapshotBytes); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot metadata: %w", err)
 	}
 
-	return nil
+	if err := d.writeChunkFiles(w, snapshot); err != nil {
+		return nil, err
+	}
+
+	parts, err := w.close()
+	if err != nil {
+		return nil, err
+	}
+	if d.splitSize == 0 {
+		return nil, nil
+	}
+
+	if _, err := writeSplitManifest(d.outputPath, manifest, parts); err != nil {
+		return nil, err
+	}
+	return parts, nil
 }
 
-func (d *snapshotDumper) writeSnapshotMetadata(tw *tar.Writer, data []byte) error {
+func writeArchiveFile(tw *tar.Writer, name string, data []byte) error {
 	header := &tar.Header{
-		Name: SnapshotFileName,
+		Name: name,
 		Mode: defaultFileMode,
 		Size: int64(len(data)),
 	}
 
 	if err := tw.WriteHeader(header); err != nil {
-		return fmt.Errorf("failed to write snapshot header: %w", err)
-	}
-
-	if _, err := tw.Write(data); err != nil {
-		return fmt.Errorf("failed to write snapshot data: %w", err)
+		return err
 	}
 
-	return nil
+	_, err := tw.Write(data)
+	return err
 }
 
-func (d *snapshotDumper) writeChunkFiles(tw *tar.Writer, chunks uint32) error {
-	for i := uint32(0); i < chunks; i++ {
-		chunkPath := d.store.PathChunk(d.height, d.format, i)
-		
-		if err := d.writeChunkFile(tw, chunkPath, i); err != nil {
+func (d *snapshotDumper) writeChunkFiles(w *archiveWriter, snapshot *snapshottypes.Snapshot) error {
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		if err := d.writeChunkFile(w, i, snapshot.Metadata.ChunkHashes); err != nil {
 			return fmt.Errorf("failed to write chunk %d: %w", i, err)
 		}
 	}
 	return nil
 }
 
-func (d *snapshotDumper) writeChunkFile(tw *tar.Writer, path string, index uint32) error {
-	file, err := os.Open(path)
+func (d *snapshotDumper) writeChunkFile(w *archiveWriter, index uint32, chunkHashes [][]byte) error {
+	chunk, err := d.store.LoadChunk(d.height, d.format, index)
 	if err != nil {
-		return fmt.Errorf("failed to open chunk file: %w", err)
+		return fmt.Errorf("failed to load chunk: %w", err)
 	}
-	defer file.Close()
-
-	info, err := file.Stat()
-	if err != nil {
-		return fmt.Errorf("failed to stat chunk file: %w", err)
+	if chunk == nil {
+		return fmt.Errorf("chunk doesn't exist")
 	}
+	defer chunk.Close()
 
-	header := &tar.Header{
-		Name: strconv.FormatUint(uint64(index), 10),
-		Mode: defaultFileMode,
-		Size: info.Size(),
+	// remote chunks have no known size, the tar header needs it upfront
+	data, err := io.ReadAll(chunk)
+	if err != nil {
+		return fmt.Errorf("failed to read chunk: %w", err)
 	}
 
-	if err := tw.WriteHeader(header); err != nil {
-		return fmt.Errorf("failed to write chunk header: %w", err)
+	// the manifest lists the checksums of the snapshot metadata, make sure the
+	// store returned the chunks they were computed from
+	hash := sha256.Sum256(data)
+	if int(index) < len(chunkHashes) && !bytes.Equal(hash[:], chunkHashes[index]) {
+		return fmt.Errorf("checksum mismatch, the stored chunk is corrupted")
 	}
 
-	if _, err := io.Copy(tw, file); err != nil {
+	if err := w.writeFile(strconv.FormatUint(uint64(index), 10), data); err != nil {
 		return fmt.Errorf("failed to write chunk data: %w", err)
 	}
 
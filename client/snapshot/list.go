@@ -4,8 +4,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/baron-chain/cosmos-bc-47/server"
-	"github.com/baron-chain/cosmos-bc-47/store/snapshots"
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +21,7 @@ func NewListSnapshotsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List available Baron Chain snapshots",
-		Long:  "Display all available snapshots with detailed information including height, format, chunks, and size",
+		Long:  "Display all snapshots of the configured snapshot store with detailed information including height, format, chunks, and hash",
 		RunE:  listSnapshots,
 	}
 	
@@ -32,11 +31,10 @@ func NewListSnapshotsCmd() *cobra.Command {
 }
 
 func listSnapshots(cmd *cobra.Command, args []string) error {
-	ctx := server.GetServerContextFromCmd(cmd)
 	showDetail, _ := cmd.Flags().GetBool("detail")
 	minHeight, _ := cmd.Flags().GetInt64("min-height")
 
-	store, err := server.GetSnapshotStore(ctx.Viper)
+	store, err := GetSnapshotStore(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to access snapshot store: %w", err)
 	}
@@ -59,30 +57,26 @@ func listSnapshots(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		info := formatSnapshotInfo(snap, showDetail)
+		cmd.Println(formatSnapshotInfo(snap, showDetail))
 		if showDetail {
-			cmd.Printf("Height: %d\nFormat: %d\nChunks: %d\nHash: %X\nSize: %d bytes\nTimestamp: %s\n-------------------\n",
-				snap.Height, snap.Format, snap.Chunks, snap.Hash, snap.Metadata.Size, snap.Metadata.Timestamp)
-		} else {
-			cmd.Printf("Height: %d | Format: %d | Chunks: %d\n", 
-				snap.Height, snap.Format, snap.Chunks)
+			cmd.Println("-------------------")
 		}
 	}
 
 	return nil
 }
 
-func formatSnapshotInfo(snap *snapshots.Snapshot, detailed bool) string {
+func formatSnapshotInfo(snap *snapshottypes.Snapshot, detailed bool) string {
 	if !detailed {
-		return fmt.Sprintf("Height: %d, Format: %d, Chunks: %d", 
+		return fmt.Sprintf("Height: %d | Format: %d | Chunks: %d",
 			snap.Height, snap.Format, snap.Chunks)
 	}
 
-	return fmt.Sprintf("Height: %d\nFormat: %d\nChunks: %d\nHash: %X\nSize: %d bytes\nTimestamp: %s",
-		snap.Height, snap.Format, snap.Chunks, snap.Hash, snap.Metadata.Size, snap.Metadata.Timestamp)
+	return fmt.Sprintf("Height: %d\nFormat: %d\nChunks: %d\nHash: %X",
+		snap.Height, snap.Format, snap.Chunks, snap.Hash)
 }
 
-func validateSnapshot(snap *snapshots.Snapshot) error {
+func validateSnapshot(snap *snapshottypes.Snapshot) error {
 	if snap == nil {
 		return fmt.Errorf("invalid snapshot: nil")
 	}
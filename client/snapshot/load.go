@@ -1,19 +1,16 @@
 package snapshot
 //BC MOD
 import (
-	"archive/tar"
 	"bytes"
-	"compress/gzip"
 	"fmt"
 	"io"
-	"os"
 	"reflect"
-	"strconv"
 
-	"github.com/cosmos/cosmos-sdk/server"
+	tmtypes "github.com/baron-chain/cometbft-bc/types"
 	"github.com/spf13/cobra"
 
-	snapshottypes "github.com/cosmos/cosmos-sdk/snapshots/types"
+	"github.com/baron-chain/cosmos-bc-47/server"
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
 )
 
 const SnapshotFileName = "_snapshot"
@@ -21,45 +18,41 @@ const SnapshotFileName = "_snapshot"
 // LoadArchiveCmd load a portable archive format snapshot into snapshot store
 func LoadArchiveCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "load <archive-file>",
+		Use:   "load <archive-file>...",
 		Short: "Load a snapshot archive file (.tar.gz) into snapshot store",
-		Args:  cobra.ExactArgs(1),
+		Long: `Load a snapshot archive written by the dump command into the snapshot store. The
+chunks are verified against the checksums of the archive manifest, and the archive must
+be of the chain of the node genesis file, when there is one.
+
+Archives dumped with --split-size are loaded from their split manifest, e.g.
+1000000-1.parts.json, which verifies the checksums of the parts, or from their part
+files, in order.`,
+		Example: `  barond snapshots load 1000000-1.tar.gz
+  barond snapshots load 1000000-1.parts.json
+  barond snapshots load 1000000-1.part000.tar.gz 1000000-1.part001.tar.gz`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := server.GetServerContextFromCmd(cmd)
-			snapshotStore, err := server.GetSnapshotStore(ctx.Viper)
+			snapshotStore, err := GetSnapshotStore(cmd)
 			if err != nil {
 				return err
 			}
 
-			path := args[0]
-			fp, err := os.Open(path)
-			if err != nil {
-				return fmt.Errorf("failed to open archive file: %w", err)
-			}
-			reader, err := gzip.NewReader(fp)
-			if err != nil {
-				return fmt.Errorf("failed to create gzip reader: %w", err)
-			}
-
-			var snapshot snapshottypes.Snapshot
-			tr := tar.NewReader(reader)
+			archive, closeFiles, err := openArchiveFiles(args)
 			if err != nil {
-				return fmt.Errorf("failed to create tar reader: %w", err)
+				return err
 			}
+			defer closeFiles()
+			snapshot := archive.snapshot
 
-			hdr, err := tr.Next()
-			if err != nil {
-				return fmt.Errorf("failed to read snapshot file header: %w", err)
-			}
-			if hdr.Name != SnapshotFileName {
-				return fmt.Errorf("invalid archive, expect file: snapshot, got: %s", hdr.Name)
-			}
-			bz, err := io.ReadAll(tr)
-			if err != nil {
-				return fmt.Errorf("failed to read snapshot file: %w", err)
-			}
-			if err := snapshot.Unmarshal(bz); err != nil {
-				return fmt.Errorf("failed to unmarshal snapshot: %w", err)
+			if archive.manifest != nil {
+				// archives of other chains can't be restored, when the node
+				// genesis is available make sure it is the same chain
+				genDoc, err := tmtypes.GenesisDocFromFile(server.GetServerContextFromCmd(cmd).Config.GenesisFile())
+				if err == nil && genDoc.ChainID != archive.manifest.ChainID {
+					return fmt.Errorf("archive is a snapshot of chain %s, the node is on chain %s", archive.manifest.ChainID, genDoc.ChainID)
+				}
+			} else {
+				cmd.Println("archive has no manifest, chunk checksums are not verified")
 			}
 
 			// make sure the channel is unbuffered, because the tar reader can't do concurrency
@@ -76,22 +69,16 @@ func LoadArchiveCmd() *cobra.Command {
 				quitChan <- savedSnapshot
 			}()
 
-			for i := uint32(0); i < snapshot.Chunks; i++ {
-				hdr, err = tr.Next()
-				if err != nil {
-					if err == io.EOF {
-						break
-					}
-					return err
+			for {
+				bz, err := archive.nextChunk()
+				if err == io.EOF {
+					break
 				}
-
-				if hdr.Name != strconv.FormatInt(int64(i), 10) {
-					return fmt.Errorf("invalid archive, expect file: %d, got: %s", i, hdr.Name)
-				}
-
-				bz, err := io.ReadAll(tr)
 				if err != nil {
-					return fmt.Errorf("failed to read chunk file: %w", err)
+					close(chunks)
+					<-quitChan
+					_ = snapshotStore.Delete(snapshot.Height, snapshot.Format)
+					return err
 				}
 				chunks <- io.NopCloser(bytes.NewReader(bz))
 			}
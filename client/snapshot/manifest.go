@@ -0,0 +1,243 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+	"github.com/baron-chain/cosmos-bc-47/version"
+)
+
+const (
+	// ManifestFileName is the name of the archive manifest, the first file of
+	// the archives written by the dump command.
+	ManifestFileName = "manifest.json"
+	// ManifestVersion is the version of the archive manifest format.
+	ManifestVersion = 1
+)
+
+// ArchiveManifest describes a snapshot archive. Archives dumped before the
+// manifest was introduced only hold the snapshot metadata and chunks.
+type ArchiveManifest struct {
+	ManifestVersion int    `json:"manifest_version"`
+	ChainID         string `json:"chain_id"`
+	// AppVersion is the version of the application binary which dumped the
+	// snapshot.
+	AppVersion string `json:"app_version"`
+	Height     uint64 `json:"height"`
+	Format     uint32 `json:"format"`
+	// Hash is the hex encoded hash of the snapshot.
+	Hash string `json:"hash"`
+	// ChunkHashes are the hex encoded SHA-256 of the chunks, in order.
+	ChunkHashes []string  `json:"chunk_hashes"`
+	CreatedAt   time.Time `json:"created_at"`
+	// ToolVersion is the version of the SDK the archive was dumped with.
+	ToolVersion string `json:"tool_version"`
+	// Attestation is set on archives dumped with --attest.
+	Attestation *ArchiveAttestation `json:"attestation,omitempty"`
+}
+
+// newArchiveManifest returns the manifest of the archive of snapshot.
+func newArchiveManifest(chainID string, snapshot *snapshottypes.Snapshot) ArchiveManifest {
+	info := version.NewInfo()
+
+	chunkHashes := make([]string, len(snapshot.Metadata.ChunkHashes))
+	for i, hash := range snapshot.Metadata.ChunkHashes {
+		chunkHashes[i] = hex.EncodeToString(hash)
+	}
+
+	return ArchiveManifest{
+		ManifestVersion: ManifestVersion,
+		ChainID:         chainID,
+		AppVersion:      info.Version,
+		Height:          snapshot.Height,
+		Format:          snapshot.Format,
+		Hash:            hex.EncodeToString(snapshot.Hash),
+		ChunkHashes:     chunkHashes,
+		CreatedAt:       time.Now().UTC().Truncate(time.Second),
+		ToolVersion:     info.CosmosSdkVersion,
+	}
+}
+
+// Validate checks the manifest describes snapshot.
+func (m ArchiveManifest) Validate(snapshot *snapshottypes.Snapshot) error {
+	if m.ManifestVersion != ManifestVersion {
+		return fmt.Errorf("unsupported manifest version %d, expected %d", m.ManifestVersion, ManifestVersion)
+	}
+	if m.Height != snapshot.Height || m.Format != snapshot.Format {
+		return fmt.Errorf("manifest is for snapshot at height %d format %d, archive holds height %d format %d",
+			m.Height, m.Format, snapshot.Height, snapshot.Format)
+	}
+	if m.Hash != hex.EncodeToString(snapshot.Hash) {
+		return fmt.Errorf("manifest snapshot hash %s doesn't match the archive snapshot hash %X", m.Hash, snapshot.Hash)
+	}
+	if len(m.ChunkHashes) != int(snapshot.Chunks) {
+		return fmt.Errorf("manifest lists %d chunks, snapshot has %d", len(m.ChunkHashes), snapshot.Chunks)
+	}
+	for i, hash := range m.ChunkHashes {
+		if i < len(snapshot.Metadata.ChunkHashes) && hash != hex.EncodeToString(snapshot.Metadata.ChunkHashes[i]) {
+			return fmt.Errorf("manifest hash of chunk %d doesn't match the snapshot metadata", i)
+		}
+	}
+	return nil
+}
+
+// archiveReader reads the archives written by the dump command, verifying the
+// chunks against the manifest when the archive has one.
+type archiveReader struct {
+	tr       *tar.Reader
+	manifest *ArchiveManifest
+	snapshot snapshottypes.Snapshot
+	next     uint32
+
+	// parts are the parts of an archive dumped with --split-size, the current
+	// one being parts[0].
+	parts    []io.Reader
+	finished bool
+}
+
+// newArchiveReader reads the manifest, if any, and the snapshot metadata of an
+// archive, given as a single reader or as the readers of its parts, in order.
+func newArchiveReader(parts ...io.Reader) (*archiveReader, error) {
+	if len(parts) == 0 {
+		return nil, errors.New("no archive to read")
+	}
+
+	ar := &archiveReader{parts: parts}
+	if err := ar.openPart(); err != nil {
+		return nil, err
+	}
+
+	name, bz, err := ar.readFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	if name == ManifestFileName {
+		var manifest ArchiveManifest
+		dec := json.NewDecoder(bytes.NewReader(bz))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("invalid archive manifest: %w", err)
+		}
+		ar.manifest = &manifest
+
+		if name, bz, err = ar.readFile(); err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+	}
+
+	if name != SnapshotFileName {
+		return nil, fmt.Errorf("invalid archive, expect file: %s, got: %s", SnapshotFileName, name)
+	}
+	if err := ar.snapshot.Unmarshal(bz); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+
+	if ar.manifest != nil {
+		if err := ar.manifest.Validate(&ar.snapshot); err != nil {
+			return nil, fmt.Errorf("invalid archive: %w", err)
+		}
+	}
+
+	return ar, nil
+}
+
+func (ar *archiveReader) openPart() error {
+	gz, err := gzip.NewReader(ar.parts[0])
+	if err != nil {
+		return fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	ar.tr = tar.NewReader(gz)
+	return nil
+}
+
+// nextPart reads the current part to its end and moves to the next one. It
+// returns io.EOF when there are no more parts.
+func (ar *archiveReader) nextPart() error {
+	if _, err := io.Copy(io.Discard, ar.parts[0]); err != nil {
+		return err
+	}
+	ar.parts = ar.parts[1:]
+	if len(ar.parts) == 0 {
+		return io.EOF
+	}
+	return ar.openPart()
+}
+
+func (ar *archiveReader) readFile() (string, []byte, error) {
+	hdr, err := ar.tr.Next()
+	for errors.Is(err, io.EOF) && len(ar.parts) > 1 {
+		if err = ar.nextPart(); err != nil {
+			return "", nil, err
+		}
+		hdr, err = ar.tr.Next()
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	bz, err := io.ReadAll(ar.tr)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read file %s: %w", hdr.Name, err)
+	}
+	return hdr.Name, bz, nil
+}
+
+// nextChunk returns the next chunk of the archive, and io.EOF after the last
+// one.
+func (ar *archiveReader) nextChunk() ([]byte, error) {
+	if ar.next >= ar.snapshot.Chunks {
+		if err := ar.finish(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	name, bz, err := ar.readFile()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid archive, missing chunk %d", ar.next)
+		}
+		return nil, err
+	}
+	if name != strconv.FormatUint(uint64(ar.next), 10) {
+		return nil, fmt.Errorf("invalid archive, expect file: %d, got: %s", ar.next, name)
+	}
+
+	if ar.manifest != nil {
+		hash := sha256.Sum256(bz)
+		if hex.EncodeToString(hash[:]) != ar.manifest.ChunkHashes[ar.next] {
+			return nil, fmt.Errorf("invalid archive, checksum mismatch of chunk %d", ar.next)
+		}
+	}
+
+	ar.next++
+	return bz, nil
+}
+
+// finish reads the last part of a split archive to its end, so that it is
+// verified, and checks there are no parts left.
+func (ar *archiveReader) finish() error {
+	if ar.finished || len(ar.parts) == 0 {
+		return nil
+	}
+	ar.finished = true
+
+	if len(ar.parts) > 1 {
+		return fmt.Errorf("invalid archive, %d unexpected parts after the last chunk", len(ar.parts)-1)
+	}
+	if err := ar.nextPart(); !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
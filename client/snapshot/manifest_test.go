@@ -0,0 +1,87 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+)
+
+// writeTestArchive writes an archive of the given chunks, the way the dump
+// command does, with a manifest when withManifest is set.
+func writeTestArchive(t *testing.T, chunks [][]byte, withManifest bool) []byte {
+	t.Helper()
+
+	snapshot := &snapshottypes.Snapshot{Height: 100, Format: 3, Chunks: uint32(len(chunks)), Hash: []byte{1, 2, 3}}
+	for _, chunk := range chunks {
+		hash := sha256.Sum256(chunk)
+		snapshot.Metadata.ChunkHashes = append(snapshot.Metadata.ChunkHashes, hash[:])
+	}
+	snapshotBytes, err := snapshot.Marshal()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	if withManifest {
+		manifest, err := json.Marshal(newArchiveManifest("baron-1", snapshot))
+		require.NoError(t, err)
+		require.NoError(t, writeArchiveFile(tw, ManifestFileName, manifest))
+	}
+	require.NoError(t, writeArchiveFile(tw, SnapshotFileName, snapshotBytes))
+	for i, chunk := range chunks {
+		require.NoError(t, writeArchiveFile(tw, strconv.Itoa(i), chunk))
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestArchiveManifest(t *testing.T) {
+	chunks := [][]byte{[]byte("chunk 0"), []byte("chunk 1")}
+
+	manifest, err := verifyArchive(bytes.NewReader(writeTestArchive(t, chunks, true)))
+	require.NoError(t, err)
+	require.Equal(t, "baron-1", manifest.ChainID)
+	require.Equal(t, uint64(100), manifest.Height)
+	require.Equal(t, uint32(3), manifest.Format)
+	require.Equal(t, "010203", manifest.Hash)
+	require.Len(t, manifest.ChunkHashes, 2)
+	require.False(t, manifest.CreatedAt.IsZero())
+
+	// archives without manifest are read, but can't be verified
+	archive, err := newArchiveReader(bytes.NewReader(writeTestArchive(t, chunks, false)))
+	require.NoError(t, err)
+	require.Nil(t, archive.manifest)
+	require.Equal(t, uint32(2), archive.snapshot.Chunks)
+	_, err = verifyArchive(bytes.NewReader(writeTestArchive(t, chunks, false)))
+	require.ErrorContains(t, err, "cannot be verified")
+
+	// a corrupted chunk doesn't match its checksum
+	corrupted := writeTestArchive(t, chunks, true)
+	archive, err = newArchiveReader(bytes.NewReader(corrupted))
+	require.NoError(t, err)
+	archive.manifest.ChunkHashes[1] = archive.manifest.ChunkHashes[0]
+	_, err = archive.nextChunk()
+	require.NoError(t, err)
+	_, err = archive.nextChunk()
+	require.ErrorContains(t, err, "checksum mismatch of chunk 1")
+
+	// the manifest must describe the archive snapshot
+	snapshot := &snapshottypes.Snapshot{Height: 100, Format: 3, Chunks: 2, Hash: []byte{1, 2, 3}}
+	m := newArchiveManifest("baron-1", snapshot)
+	require.Error(t, m.Validate(snapshot))
+	m.ChunkHashes = []string{"00", "11"}
+	require.NoError(t, m.Validate(snapshot))
+	snapshot.Height = 101
+	require.Error(t, m.Validate(snapshot))
+}
@@ -0,0 +1,284 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/baron-chain/cosmos-bc-47/snapshots"
+)
+
+const (
+	// chunkPoolDir is the directory of the chunk pool, in the local snapshot
+	// directory.
+	chunkPoolDir = "chunks"
+	// chunkPoolIndexFile is the index of the chunk pool, in chunkPoolDir.
+	chunkPoolIndexFile = "index.json"
+)
+
+// chunkPool is a content-addressed pool of the chunks of the local snapshot
+// store. Each distinct chunk is stored once, as chunks/<ab>/<sha256>, and the
+// chunk files of the snapshots are hard links to it, so that the layout of
+// the store, read by the node, doesn't change. The index records the chunk
+// files referencing each pooled chunk, and is reconciled with the files on
+// disk by collect, as snapshots may also be deleted by the node.
+type chunkPool struct {
+	snapshotDir string
+	index       chunkPoolIndex
+}
+
+type chunkPoolIndex struct {
+	Chunks map[string]*pooledChunk `json:"chunks"`
+}
+
+// pooledChunk is an entry of the chunk pool index.
+type pooledChunk struct {
+	Size int64 `json:"size"`
+	// Refs are the paths of the chunk files linked to the pooled chunk,
+	// relative to the snapshot directory, e.g. 1000/1/3.
+	Refs []string `json:"refs"`
+}
+
+// loadChunkPool loads the chunk pool of the local snapshot directory. The
+// pool is empty if the store was never deduplicated.
+func loadChunkPool(snapshotDir string) (*chunkPool, error) {
+	p := &chunkPool{
+		snapshotDir: snapshotDir,
+		index:       chunkPoolIndex{Chunks: make(map[string]*pooledChunk)},
+	}
+
+	bz, err := os.ReadFile(p.indexPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunk pool index: %w", err)
+	}
+	if err := json.Unmarshal(bz, &p.index); err != nil {
+		return nil, fmt.Errorf("invalid chunk pool index %s: %w", p.indexPath(), err)
+	}
+	if p.index.Chunks == nil {
+		p.index.Chunks = make(map[string]*pooledChunk)
+	}
+	return p, nil
+}
+
+func (p *chunkPool) dir() string {
+	return filepath.Join(p.snapshotDir, chunkPoolDir)
+}
+
+func (p *chunkPool) indexPath() string {
+	return filepath.Join(p.dir(), chunkPoolIndexFile)
+}
+
+// path returns the path of a pooled chunk.
+func (p *chunkPool) path(hash string) string {
+	return filepath.Join(p.dir(), hash[:2], hash)
+}
+
+// add links the chunk file into the pool, and replaces it with a link to the
+// pooled chunk when the pool already holds an identical chunk. It returns
+// whether the chunk file was replaced.
+func (p *chunkPool) add(hash, ref string, size int64) (bool, error) {
+	chunkPath := filepath.Join(p.snapshotDir, ref)
+	poolPath := p.path(hash)
+
+	c, ok := p.index.Chunks[hash]
+	if !ok {
+		c = &pooledChunk{Size: size}
+		p.index.Chunks[hash] = c
+	}
+	if !containsRef(c.Refs, ref) {
+		c.Refs = append(c.Refs, ref)
+		sort.Strings(c.Refs)
+	}
+
+	poolInfo, err := os.Stat(poolPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(poolPath), 0o755); err != nil {
+			return false, err
+		}
+		return false, os.Link(chunkPath, poolPath)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	chunkInfo, err := os.Stat(chunkPath)
+	if err != nil {
+		return false, err
+	}
+	if os.SameFile(poolInfo, chunkInfo) {
+		return false, nil
+	}
+
+	// replace the chunk file atomically, so that it is never missing
+	tmpPath := chunkPath + ".dedup"
+	if err := os.Link(poolPath, tmpPath); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmpPath, chunkPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return false, err
+	}
+	return true, nil
+}
+
+// linked reports whether the chunk file is a link to the pooled chunk.
+func (p *chunkPool) linked(hash, ref string) bool {
+	poolInfo, err := os.Stat(p.path(hash))
+	if err != nil {
+		return false
+	}
+	chunkInfo, err := os.Stat(filepath.Join(p.snapshotDir, ref))
+	if err != nil {
+		return false
+	}
+	return os.SameFile(poolInfo, chunkInfo)
+}
+
+// collect drops the references of the chunk files which were deleted, or
+// replaced by other files, and removes the pooled chunks which are no longer
+// referenced. It returns the number of chunks removed and their size.
+func (p *chunkPool) collect() (removed int, freed int64, err error) {
+	for hash, c := range p.index.Chunks {
+		refs := c.Refs[:0]
+		for _, ref := range c.Refs {
+			if p.linked(hash, ref) {
+				refs = append(refs, ref)
+			}
+		}
+		c.Refs = refs
+		if len(refs) > 0 {
+			continue
+		}
+
+		if err := os.Remove(p.path(hash)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, freed, err
+		}
+		delete(p.index.Chunks, hash)
+		removed++
+		freed += c.Size
+	}
+
+	// remove the pooled chunks missing from the index, e.g. after a failure
+	// to save it
+	entries, err := filepath.Glob(filepath.Join(p.dir(), "*", "*"))
+	if err != nil {
+		return removed, freed, err
+	}
+	for _, path := range entries {
+		if _, ok := p.index.Chunks[filepath.Base(path)]; ok {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			if err := os.Remove(path); err != nil {
+				return removed, freed, err
+			}
+			removed++
+			freed += info.Size()
+		}
+	}
+	return removed, freed, nil
+}
+
+// save writes the index of the pool, if it isn't empty or an index exists.
+func (p *chunkPool) save() error {
+	if len(p.index.Chunks) == 0 {
+		if _, err := os.Stat(p.indexPath()); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+	}
+
+	bz, err := json.MarshalIndent(p.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(p.dir(), 0o755); err != nil {
+		return err
+	}
+	tmpPath := p.indexPath() + ".tmp"
+	if err := os.WriteFile(tmpPath, bz, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, p.indexPath())
+}
+
+// chunkRef returns the path of a chunk file relative to the snapshot
+// directory, as recorded in the pool index.
+func chunkRef(height uint64, format, chunk uint32) string {
+	return strings.Join([]string{
+		strconv.FormatUint(height, 10),
+		strconv.FormatUint(uint64(format), 10),
+		strconv.FormatUint(uint64(chunk), 10),
+	}, "/")
+}
+
+func containsRef(refs []string, ref string) bool {
+	i := sort.SearchStrings(refs, ref)
+	return i < len(refs) && refs[i] == ref
+}
+
+// hashChunkFile returns the hex SHA-256 checksum and the size of a chunk file.
+func hashChunkFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// pooledSnapshotStore is the local snapshot store, which keeps the chunk pool
+// consistent when snapshots are deleted or pruned.
+type pooledSnapshotStore struct {
+	*snapshots.Store
+	dir string
+}
+
+var _ SnapshotStore = (*pooledSnapshotStore)(nil)
+
+func (s *pooledSnapshotStore) Delete(height uint64, format uint32) error {
+	if err := s.Store.Delete(height, format); err != nil {
+		return err
+	}
+	return s.collectChunks()
+}
+
+func (s *pooledSnapshotStore) Prune(retain uint32) (uint64, error) {
+	pruned, err := s.Store.Prune(retain)
+	if err != nil {
+		return pruned, err
+	}
+	return pruned, s.collectChunks()
+}
+
+// collectChunks removes the pooled chunks no longer referenced by snapshots.
+func (s *pooledSnapshotStore) collectChunks() error {
+	pool, err := loadChunkPool(s.dir)
+	if err != nil {
+		return err
+	}
+	if len(pool.index.Chunks) == 0 {
+		return nil
+	}
+
+	if _, _, err := pool.collect(); err != nil {
+		return fmt.Errorf("failed to remove unreferenced pooled chunks: %w", err)
+	}
+	return pool.save()
+}
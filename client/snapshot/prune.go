@@ -0,0 +1,44 @@
+package snapshot
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+const flagKeepRecent = "keep-recent"
+
+// PruneSnapshotsCmd returns a command to prune old snapshots
+func PruneSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Prune old Baron Chain snapshots",
+		Long: `Delete all but the most recent snapshot heights from the configured snapshot store.
+All the formats of a retained height are kept.`,
+		Example: `  # Keep only the 2 most recent snapshot heights
+  barond snapshots prune --keep-recent 2`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			keepRecent, err := cmd.Flags().GetUint32(flagKeepRecent)
+			if err != nil {
+				return err
+			}
+
+			snapshotStore, err := GetSnapshotStore(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get snapshot store: %w", err)
+			}
+
+			pruned, err := snapshotStore.Prune(keepRecent)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Successfully pruned %d snapshots\n", pruned)
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint32(flagKeepRecent, 2, "Number of most recent snapshot heights to keep")
+	return cmd
+}
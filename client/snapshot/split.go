@@ -0,0 +1,351 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	flagSplitSize = "split-size"
+
+	archiveExt        = ".tar.gz"
+	splitManifestExt  = ".parts.json"
+	splitManifestVers = 1
+
+	// maxArchiveOverhead bounds the size added to a part by the tar header and
+	// padding of a file, the gzip sync marker flushed after it, and the tar and
+	// gzip trailers.
+	maxArchiveOverhead = 4096
+)
+
+// ArchivePart is a part of an archive dumped with --split-size.
+type ArchivePart struct {
+	// Name is the file name of the part, in the directory of the split
+	// manifest.
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// SHA256 is the hex encoded SHA-256 of the part file.
+	SHA256 string `json:"sha256"`
+}
+
+// SplitArchiveManifest describes an archive dumped in parts with --split-size.
+// Each part is a gzipped tar archive: the first one holds the archive manifest
+// and the snapshot metadata, followed by the chunks, and the next ones the
+// following chunks.
+type SplitArchiveManifest struct {
+	ManifestVersion int             `json:"manifest_version"`
+	Archive         ArchiveManifest `json:"archive"`
+	Parts           []ArchivePart   `json:"parts"`
+}
+
+// parseSplitSize parses a size such as 2GB or 500MiB.
+func parseSplitSize(s string) (int64, error) {
+	size, err := humanize.ParseBytes(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --%s %q: %w", flagSplitSize, s, err)
+	}
+	if size < 2*maxArchiveOverhead || size > 1<<62 {
+		return 0, fmt.Errorf("invalid --%s %q: must be at least %d bytes", flagSplitSize, s, 2*maxArchiveOverhead)
+	}
+	return int64(size), nil
+}
+
+// archivePartPath returns the path of the i-th part of an archive, e.g.
+// 1000-1.part000.tar.gz for 1000-1.tar.gz.
+func archivePartPath(outputPath string, i int) string {
+	return fmt.Sprintf("%s.part%03d%s", strings.TrimSuffix(outputPath, archiveExt), i, archiveExt)
+}
+
+// splitManifestPath returns the path of the split manifest of an archive, e.g.
+// 1000-1.parts.json for 1000-1.tar.gz.
+func splitManifestPath(outputPath string) string {
+	return strings.TrimSuffix(outputPath, archiveExt) + splitManifestExt
+}
+
+// archiveWriter writes the files of an archive, into sequential parts of at
+// most splitSize bytes when splitSize is set. Files are not split, so that
+// each part is a valid archive.
+type archiveWriter struct {
+	outputPath string
+	splitSize  int64
+
+	parts []ArchivePart
+	file  *os.File
+	out   *hashingWriter
+	gz    *gzip.Writer
+	tw    *tar.Writer
+	files int
+}
+
+func newArchiveWriter(outputPath string, splitSize int64) *archiveWriter {
+	return &archiveWriter{outputPath: outputPath, splitSize: splitSize}
+}
+
+// writeFile writes a file to the current part, or to a new part when it could
+// make the current one exceed the split size.
+func (w *archiveWriter) writeFile(name string, data []byte) error {
+	if w.splitSize > 0 {
+		bound := int64(len(data)) + int64(len(data))/1024 + maxArchiveOverhead
+		if bound > w.splitSize {
+			return fmt.Errorf("file %s of %d bytes doesn't fit in a part of --%s %d bytes", name, len(data), flagSplitSize, w.splitSize)
+		}
+
+		if w.tw != nil && w.files > 0 {
+			if err := w.flush(); err != nil {
+				return err
+			}
+			if w.out.n+bound > w.splitSize {
+				if err := w.closePart(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if w.tw == nil {
+		if err := w.openPart(); err != nil {
+			return err
+		}
+	}
+	w.files++
+	return writeArchiveFile(w.tw, name, data)
+}
+
+func (w *archiveWriter) partPath() string {
+	if w.splitSize == 0 {
+		return w.outputPath
+	}
+	return archivePartPath(w.outputPath, len(w.parts))
+}
+
+func (w *archiveWriter) openPart() error {
+	file, err := os.Create(w.partPath())
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	w.file = file
+	w.out = &hashingWriter{w: file, hash: sha256.New()}
+	if w.gz, err = gzip.NewWriterLevel(w.out, gzip.BestSpeed); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to create gzip writer: %w", err)
+	}
+	w.tw = tar.NewWriter(w.gz)
+	w.files = 0
+	return nil
+}
+
+func (w *archiveWriter) flush() error {
+	if err := w.tw.Flush(); err != nil {
+		return err
+	}
+	return w.gz.Flush()
+}
+
+func (w *archiveWriter) closePart() error {
+	if err := w.tw.Close(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	if err := w.gz.Close(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+
+	w.parts = append(w.parts, ArchivePart{
+		Name:   filepath.Base(w.file.Name()),
+		Size:   w.out.n,
+		SHA256: hex.EncodeToString(w.out.hash.Sum(nil)),
+	})
+	w.tw = nil
+	return nil
+}
+
+// close closes the last part and returns the parts written.
+func (w *archiveWriter) close() ([]ArchivePart, error) {
+	if w.tw != nil {
+		if err := w.closePart(); err != nil {
+			return nil, err
+		}
+	}
+	return w.parts, nil
+}
+
+// abort closes the current part, leaving the written files as they are.
+func (w *archiveWriter) abort() {
+	if w.tw != nil {
+		w.file.Close()
+		w.tw = nil
+	}
+}
+
+// writeSplitManifest writes the manifest of the parts of an archive next to
+// them.
+func writeSplitManifest(outputPath string, manifest ArchiveManifest, parts []ArchivePart) (string, error) {
+	bz, err := json.MarshalIndent(SplitArchiveManifest{
+		ManifestVersion: splitManifestVers,
+		Archive:         manifest,
+		Parts:           parts,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal split manifest: %w", err)
+	}
+
+	path := splitManifestPath(outputPath)
+	if err := os.WriteFile(path, bz, defaultFileMode); err != nil {
+		return "", fmt.Errorf("failed to write split manifest: %w", err)
+	}
+	return path, nil
+}
+
+// openArchiveFiles opens an archive given by the arguments of the load and
+// verify commands: either a single archive file, the split manifest of an
+// archive dumped with --split-size, or its part files, in order. When the
+// split manifest is given, the parts are verified against it as they are
+// read, and its archive manifest must be the one of the first part.
+func openArchiveFiles(args []string) (*archiveReader, func(), error) {
+	var (
+		paths    = args
+		expected []ArchivePart
+		split    *SplitArchiveManifest
+	)
+	if len(args) == 1 && strings.HasSuffix(args[0], ".json") {
+		bz, err := os.ReadFile(args[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read split manifest: %w", err)
+		}
+		split = &SplitArchiveManifest{}
+		dec := json.NewDecoder(bytes.NewReader(bz))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(split); err != nil {
+			return nil, nil, fmt.Errorf("invalid split manifest: %w", err)
+		}
+		if split.ManifestVersion != splitManifestVers {
+			return nil, nil, fmt.Errorf("unsupported split manifest version %d, expected %d", split.ManifestVersion, splitManifestVers)
+		}
+		if len(split.Parts) == 0 {
+			return nil, nil, errors.New("invalid split manifest: no parts")
+		}
+
+		paths = make([]string, len(split.Parts))
+		for i, part := range split.Parts {
+			if part.Name != filepath.Base(part.Name) {
+				return nil, nil, fmt.Errorf("invalid split manifest: part name %q is not a file name", part.Name)
+			}
+			paths[i] = filepath.Join(filepath.Dir(args[0]), part.Name)
+		}
+		expected = split.Parts
+	}
+
+	var files []*os.File
+	closeFiles := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+
+	readers := make([]io.Reader, len(paths))
+	for i, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			closeFiles()
+			return nil, nil, fmt.Errorf("failed to open archive file: %w", err)
+		}
+		files = append(files, f)
+
+		readers[i] = f
+		if expected != nil {
+			readers[i] = &verifyingReader{r: f, hash: sha256.New(), part: expected[i]}
+		}
+	}
+
+	archive, err := newArchiveReader(readers...)
+	if err != nil {
+		closeFiles()
+		return nil, nil, err
+	}
+
+	if split != nil {
+		if err := checkSplitManifest(split, archive.manifest); err != nil {
+			closeFiles()
+			return nil, nil, err
+		}
+	}
+	return archive, closeFiles, nil
+}
+
+// checkSplitManifest checks the split manifest describes the archive of the
+// given manifest.
+func checkSplitManifest(split *SplitArchiveManifest, manifest *ArchiveManifest) error {
+	if manifest == nil {
+		return fmt.Errorf("invalid archive, the first part has no %s", ManifestFileName)
+	}
+
+	expected, err := json.Marshal(split.Archive)
+	if err != nil {
+		return err
+	}
+	actual, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, actual) {
+		return errors.New("invalid archive, the split manifest doesn't match the manifest of the first part")
+	}
+	return nil
+}
+
+// hashingWriter counts and hashes the bytes written to w.
+type hashingWriter struct {
+	w    io.Writer
+	hash hash.Hash
+	n    int64
+}
+
+func (h *hashingWriter) Write(p []byte) (int, error) {
+	n, err := h.w.Write(p)
+	h.hash.Write(p[:n])
+	h.n += int64(n)
+	return n, err
+}
+
+// verifyingReader checks, once r is read to the end, that it had the size and
+// SHA-256 of the part.
+type verifyingReader struct {
+	r    io.Reader
+	hash hash.Hash
+	n    int64
+	part ArchivePart
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.hash.Write(p[:n])
+	v.n += int64(n)
+
+	if errors.Is(err, io.EOF) {
+		if v.n != v.part.Size {
+			return n, fmt.Errorf("invalid archive, part %s has %d bytes, expected %d", v.part.Name, v.n, v.part.Size)
+		}
+		if hex.EncodeToString(v.hash.Sum(nil)) != v.part.SHA256 {
+			return n, fmt.Errorf("invalid archive, checksum mismatch of part %s", v.part.Name)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,105 @@
+package snapshot
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+)
+
+func TestSplitArchive(t *testing.T) {
+	chunks := make([][]byte, 5)
+	snapshot := &snapshottypes.Snapshot{Height: 100, Format: 3, Chunks: uint32(len(chunks)), Hash: []byte{1, 2, 3}}
+	for i := range chunks {
+		// random chunks don't compress, so that they fill the parts
+		chunks[i] = make([]byte, 6000)
+		_, err := rand.Read(chunks[i])
+		require.NoError(t, err)
+		hash := sha256.Sum256(chunks[i])
+		snapshot.Metadata.ChunkHashes = append(snapshot.Metadata.ChunkHashes, hash[:])
+	}
+	snapshotBytes, err := snapshot.Marshal()
+	require.NoError(t, err)
+	manifest := newArchiveManifest("baron-1", snapshot)
+	manifestBytes, err := json.Marshal(manifest)
+	require.NoError(t, err)
+
+	splitSize := int64(16 * 1024)
+	outputPath := filepath.Join(t.TempDir(), "100-3.tar.gz")
+	w := newArchiveWriter(outputPath, splitSize)
+	require.NoError(t, w.writeFile(ManifestFileName, manifestBytes))
+	require.NoError(t, w.writeFile(SnapshotFileName, snapshotBytes))
+	for i, chunk := range chunks {
+		require.NoError(t, w.writeFile(strconv.Itoa(i), chunk))
+	}
+	parts, err := w.close()
+	require.NoError(t, err)
+	require.Greater(t, len(parts), 1)
+	require.Equal(t, "100-3.part000.tar.gz", parts[0].Name)
+
+	var partPaths []string
+	for i, part := range parts {
+		require.LessOrEqual(t, part.Size, splitSize)
+		partPaths = append(partPaths, archivePartPath(outputPath, i))
+	}
+	splitPath, err := writeSplitManifest(outputPath, manifest, parts)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(filepath.Dir(outputPath), "100-3.parts.json"), splitPath)
+
+	readChunks := func(args []string) ([][]byte, error) {
+		archive, closeFiles, err := openArchiveFiles(args)
+		if err != nil {
+			return nil, err
+		}
+		defer closeFiles()
+
+		var read [][]byte
+		for {
+			bz, err := archive.nextChunk()
+			if err != nil {
+				if err.Error() == "EOF" {
+					return read, nil
+				}
+				return nil, err
+			}
+			read = append(read, bz)
+		}
+	}
+
+	read, err := readChunks([]string{splitPath})
+	require.NoError(t, err)
+	require.Equal(t, chunks, read)
+	read, err = readChunks(partPaths)
+	require.NoError(t, err)
+	require.Equal(t, chunks, read)
+
+	// parts must be complete and given in order
+	_, err = readChunks(partPaths[:len(partPaths)-1])
+	require.ErrorContains(t, err, "missing chunk")
+	_, err = readChunks(append([]string{partPaths[1], partPaths[0]}, partPaths[2:]...))
+	require.Error(t, err)
+
+	// a part which doesn't match the split manifest is rejected
+	bz, err := os.ReadFile(partPaths[1])
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(partPaths[1], append(bz, 0), 0o600))
+	_, err = readChunks([]string{splitPath})
+	require.ErrorContains(t, err, "part 100-3.part001.tar.gz has")
+
+	// a chunk larger than the split size can't be written
+	w = newArchiveWriter(filepath.Join(t.TempDir(), "100-3.tar.gz"), splitSize)
+	require.ErrorContains(t, w.writeFile("0", make([]byte, splitSize)), "doesn't fit")
+	w.abort()
+
+	_, err = parseSplitSize("2GB")
+	require.NoError(t, err)
+	_, err = parseSplitSize("1KB")
+	require.ErrorContains(t, err, "must be at least")
+}
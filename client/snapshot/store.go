@@ -0,0 +1,80 @@
+package snapshot
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/server"
+	"github.com/baron-chain/cosmos-bc-47/server/config"
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+)
+
+const (
+	// StoreBackendLocal keeps snapshots in the node's data/snapshots directory.
+	StoreBackendLocal = "local"
+	// StoreBackendS3 keeps snapshots in an S3 (or S3 compatible) bucket.
+	StoreBackendS3 = "s3"
+	// StoreBackendGCS keeps snapshots in a Google Cloud Storage bucket.
+	StoreBackendGCS = "gcs"
+)
+
+// SnapshotStore is a repository of snapshots the snapshots commands operate on.
+// The local snapshots.Store implements it, wrapped to maintain the chunk pool
+// of deduplicated snapshots, remote backends store the same metadata and
+// chunks as objects in a bucket.
+type SnapshotStore interface {
+	// List lists snapshots, newest first.
+	List() ([]*snapshottypes.Snapshot, error)
+	// Get returns the snapshot metadata, or nil if the snapshot does not exist.
+	Get(height uint64, format uint32) (*snapshottypes.Snapshot, error)
+	// LoadChunk returns a chunk of a snapshot, or nil if it does not exist.
+	// The caller must close it.
+	LoadChunk(height uint64, format, chunk uint32) (io.ReadCloser, error)
+	// Save saves a snapshot from its chunks, and returns its metadata.
+	Save(height uint64, format uint32, chunks <-chan io.ReadCloser) (*snapshottypes.Snapshot, error)
+	// Delete deletes a snapshot.
+	Delete(height uint64, format uint32) error
+	// Prune removes all but the given number of most recent snapshot heights,
+	// and returns the number of snapshots removed.
+	Prune(retain uint32) (uint64, error)
+}
+
+// GetSnapshotStore returns the snapshot store configured in the [snapshot-store]
+// section of the app config.
+func GetSnapshotStore(cmd *cobra.Command) (SnapshotStore, error) {
+	serverCtx := server.GetServerContextFromCmd(cmd)
+	cfg, err := config.GetConfig(serverCtx.Viper)
+	if err != nil {
+		return nil, err
+	}
+
+	storeCfg := cfg.SnapshotStore
+	switch storeCfg.Backend {
+	case "", StoreBackendLocal:
+		store, err := server.GetSnapshotStore(serverCtx.Viper)
+		if err != nil {
+			return nil, err
+		}
+		return &pooledSnapshotStore{Store: store, dir: server.GetSnapshotDir(serverCtx.Viper)}, nil
+
+	case StoreBackendS3:
+		objects, err := newS3ObjectStore(storeCfg)
+		if err != nil {
+			return nil, err
+		}
+		return newRemoteSnapshotStore(cmd.Context(), objects, storeCfg.Prefix), nil
+
+	case StoreBackendGCS:
+		objects, err := newGCSObjectStore(cmd.Context(), storeCfg)
+		if err != nil {
+			return nil, err
+		}
+		return newRemoteSnapshotStore(cmd.Context(), objects, storeCfg.Prefix), nil
+
+	default:
+		return nil, fmt.Errorf("unknown snapshot store backend %q, expected %s, %s or %s",
+			storeCfg.Backend, StoreBackendLocal, StoreBackendS3, StoreBackendGCS)
+	}
+}
@@ -0,0 +1,81 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	"github.com/baron-chain/cosmos-bc-47/server/config"
+)
+
+// gcsObjectStore is an objectStore backed by a Google Cloud Storage bucket.
+type gcsObjectStore struct {
+	bucket *storage.BucketHandle
+}
+
+var _ objectStore = (*gcsObjectStore)(nil)
+
+// newGCSObjectStore returns an objectStore for the configured bucket. Credentials
+// are resolved from the Google application default credentials.
+func newGCSObjectStore(ctx context.Context, cfg config.SnapshotStoreConfig) (*gcsObjectStore, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("snapshot store bucket is required for the gcs backend")
+	}
+
+	var opts []option.ClientOption
+	if cfg.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(cfg.Endpoint))
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcs client: %w", err)
+	}
+
+	return &gcsObjectStore{bucket: client.Bucket(cfg.Bucket)}, nil
+}
+
+func (s *gcsObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := s.bucket.Object(key).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, errObjectNotFound
+	}
+	return reader, err
+}
+
+func (s *gcsObjectStore) Put(ctx context.Context, key string, body []byte) error {
+	writer := s.bucket.Object(key).NewWriter(ctx)
+	if _, err := writer.Write(body); err != nil {
+		_ = writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+func (s *gcsObjectStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.Object(key).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return errObjectNotFound
+	}
+	return err
+}
+
+func (s *gcsObjectStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	it := s.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, attrs.Name)
+	}
+}
@@ -0,0 +1,229 @@
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/gogoproto/proto"
+
+	"github.com/baron-chain/cosmos-bc-47/snapshots"
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+)
+
+// errObjectNotFound is returned by an objectStore when a key does not exist.
+var errObjectNotFound = errors.New("object not found")
+
+// objectStore is the minimal bucket API a remote snapshot store is built on.
+type objectStore interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Put(ctx context.Context, key string, body []byte) error
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// remoteSnapshotStore is a SnapshotStore keeping snapshots in a bucket, with
+// the same layout as the local store: <prefix>/<height>/<format>/<chunk>. The
+// snapshot metadata is written last to <prefix>/<height>/<format>/_snapshot,
+// so that partially uploaded snapshots are never listed.
+type remoteSnapshotStore struct {
+	ctx     context.Context
+	objects objectStore
+	prefix  string
+}
+
+var _ SnapshotStore = (*remoteSnapshotStore)(nil)
+
+func newRemoteSnapshotStore(ctx context.Context, objects objectStore, prefix string) *remoteSnapshotStore {
+	return &remoteSnapshotStore{ctx: ctx, objects: objects, prefix: strings.Trim(prefix, "/")}
+}
+
+func (s *remoteSnapshotStore) List() ([]*snapshottypes.Snapshot, error) {
+	prefix := s.prefix
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	keys, err := s.objects.List(s.ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	list := make([]*snapshottypes.Snapshot, 0)
+	for _, key := range keys {
+		if path.Base(key) != SnapshotFileName {
+			continue
+		}
+
+		snapshot, err := s.getMetadata(key)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot != nil {
+			list = append(list, snapshot)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Height != list[j].Height {
+			return list[i].Height > list[j].Height
+		}
+		return list[i].Format > list[j].Format
+	})
+	return list, nil
+}
+
+func (s *remoteSnapshotStore) Get(height uint64, format uint32) (*snapshottypes.Snapshot, error) {
+	return s.getMetadata(s.metadataKey(height, format))
+}
+
+func (s *remoteSnapshotStore) LoadChunk(height uint64, format, chunk uint32) (io.ReadCloser, error) {
+	body, err := s.objects.Get(s.ctx, s.chunkKey(height, format, chunk))
+	if errors.Is(err, errObjectNotFound) {
+		return nil, nil
+	}
+	return body, err
+}
+
+func (s *remoteSnapshotStore) Save(height uint64, format uint32, chunks <-chan io.ReadCloser) (*snapshottypes.Snapshot, error) {
+	defer snapshots.DrainChunks(chunks)
+	if height == 0 {
+		return nil, fmt.Errorf("snapshot height cannot be 0")
+	}
+
+	existing, err := s.Get(height, format)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("snapshot already exists for height %d format %d", height, format)
+	}
+
+	snapshot := &snapshottypes.Snapshot{
+		Height: height,
+		Format: format,
+	}
+	snapshotHasher := sha256.New()
+	index := uint32(0)
+	for chunkBody := range chunks {
+		chunk, err := io.ReadAll(chunkBody)
+		_ = chunkBody.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot chunk %d: %w", index, err)
+		}
+
+		if err := s.objects.Put(s.ctx, s.chunkKey(height, format, index), chunk); err != nil {
+			return nil, fmt.Errorf("failed to upload snapshot chunk %d: %w", index, err)
+		}
+
+		chunkHash := sha256.Sum256(chunk)
+		snapshotHasher.Write(chunk)
+		snapshot.Metadata.ChunkHashes = append(snapshot.Metadata.ChunkHashes, chunkHash[:])
+		index++
+	}
+	snapshot.Chunks = index
+	snapshot.Hash = snapshotHasher.Sum(nil)
+
+	bz, err := proto.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot metadata: %w", err)
+	}
+	if err := s.objects.Put(s.ctx, s.metadataKey(height, format), bz); err != nil {
+		return nil, fmt.Errorf("failed to upload snapshot metadata: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (s *remoteSnapshotStore) Delete(height uint64, format uint32) error {
+	// delete the metadata first so that the snapshot is no longer listed even
+	// if deleting its chunks fails halfway
+	if err := s.objects.Delete(s.ctx, s.metadataKey(height, format)); err != nil && !errors.Is(err, errObjectNotFound) {
+		return fmt.Errorf("failed to delete snapshot metadata for height %d format %d: %w", height, format, err)
+	}
+
+	keys, err := s.objects.List(s.ctx, s.key(height, format)+"/")
+	if err != nil {
+		return fmt.Errorf("failed to list snapshot chunks for height %d format %d: %w", height, format, err)
+	}
+
+	for _, key := range keys {
+		if err := s.objects.Delete(s.ctx, key); err != nil && !errors.Is(err, errObjectNotFound) {
+			return fmt.Errorf("failed to delete snapshot chunk %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (s *remoteSnapshotStore) Prune(retain uint32) (uint64, error) {
+	list, err := s.List()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune snapshots: %w", err)
+	}
+
+	pruned := uint64(0)
+	skip := make(map[uint64]bool)
+	for _, snapshot := range list {
+		if skip[snapshot.Height] || uint32(len(skip)) < retain {
+			skip[snapshot.Height] = true
+			continue
+		}
+
+		if err := s.Delete(snapshot.Height, snapshot.Format); err != nil {
+			return pruned, fmt.Errorf("failed to prune snapshots: %w", err)
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+func (s *remoteSnapshotStore) getMetadata(key string) (*snapshottypes.Snapshot, error) {
+	body, err := s.objects.Get(s.ctx, key)
+	if errors.Is(err, errObjectNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch snapshot metadata %s: %w", key, err)
+	}
+	defer body.Close()
+
+	bz, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch snapshot metadata %s: %w", key, err)
+	}
+
+	snapshot := &snapshottypes.Snapshot{}
+	if err := proto.Unmarshal(bz, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot metadata %s: %w", key, err)
+	}
+	if snapshot.Metadata.ChunkHashes == nil {
+		snapshot.Metadata.ChunkHashes = [][]byte{}
+	}
+	return snapshot, nil
+}
+
+// key joins the store prefix and the given key elements.
+func (s *remoteSnapshotStore) key(elems ...interface{}) string {
+	parts := make([]string, 0, len(elems)+1)
+	if s.prefix != "" {
+		parts = append(parts, s.prefix)
+	}
+	for _, elem := range elems {
+		parts = append(parts, fmt.Sprint(elem))
+	}
+	return strings.Join(parts, "/")
+}
+
+func (s *remoteSnapshotStore) metadataKey(height uint64, format uint32) string {
+	return s.key(height, format, SnapshotFileName)
+}
+
+func (s *remoteSnapshotStore) chunkKey(height uint64, format, chunk uint32) string {
+	return s.key(height, format, strconv.FormatUint(uint64(chunk), 10))
+}
@@ -0,0 +1,134 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memObjectStore is an in-memory objectStore.
+type memObjectStore struct {
+	mtx     sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemObjectStore() *memObjectStore {
+	return &memObjectStore{objects: make(map[string][]byte)}
+}
+
+func (m *memObjectStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	body, ok := m.objects[key]
+	if !ok {
+		return nil, errObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+func (m *memObjectStore) Put(_ context.Context, key string, body []byte) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.objects[key] = body
+	return nil
+}
+
+func (m *memObjectStore) Delete(_ context.Context, key string) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memObjectStore) List(_ context.Context, prefix string) ([]string, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var keys []string
+	for key := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func makeChunks(chunks ...[]byte) <-chan io.ReadCloser {
+	ch := make(chan io.ReadCloser, len(chunks))
+	for _, chunk := range chunks {
+		ch <- io.NopCloser(bytes.NewReader(chunk))
+	}
+	close(ch)
+	return ch
+}
+
+func TestRemoteSnapshotStore(t *testing.T) {
+	objects := newMemObjectStore()
+	store := newRemoteSnapshotStore(context.Background(), objects, "/snapshots/")
+
+	for _, height := range []uint64{1, 2, 3} {
+		snapshot, err := store.Save(height, 1, makeChunks([]byte{1, 2}, []byte{byte(height)}))
+		require.NoError(t, err)
+		require.Equal(t, uint32(2), snapshot.Chunks)
+		require.Len(t, snapshot.Metadata.ChunkHashes, 2)
+	}
+	_, err := store.Save(2, 2, makeChunks([]byte{4}))
+	require.NoError(t, err)
+	require.Contains(t, objects.objects, "snapshots/2/2/_snapshot")
+	require.Contains(t, objects.objects, "snapshots/2/2/0")
+
+	_, err = store.Save(1, 1, makeChunks([]byte{1}))
+	require.Error(t, err, "snapshot already exists")
+
+	list, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+	require.Equal(t, uint64(3), list[0].Height)
+	require.Equal(t, uint64(2), list[1].Height)
+	require.Equal(t, uint32(2), list[1].Format)
+	require.Equal(t, uint64(1), list[3].Height)
+
+	snapshot, err := store.Get(3, 1)
+	require.NoError(t, err)
+	require.Equal(t, list[0], snapshot)
+
+	snapshot, err = store.Get(4, 1)
+	require.NoError(t, err)
+	require.Nil(t, snapshot)
+
+	chunk, err := store.LoadChunk(3, 1, 1)
+	require.NoError(t, err)
+	data, err := io.ReadAll(chunk)
+	require.NoError(t, err)
+	require.Equal(t, []byte{3}, data)
+
+	chunk, err = store.LoadChunk(3, 1, 2)
+	require.NoError(t, err)
+	require.Nil(t, chunk)
+
+	require.NoError(t, store.Delete(3, 1))
+	snapshot, err = store.Get(3, 1)
+	require.NoError(t, err)
+	require.Nil(t, snapshot)
+	keys, err := objects.List(context.Background(), "snapshots/3/")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	// heights 2 (both formats) is retained, height 1 is pruned
+	pruned, err := store.Prune(1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), pruned)
+
+	list, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	for _, snapshot := range list {
+		require.Equal(t, uint64(2), snapshot.Height)
+	}
+}
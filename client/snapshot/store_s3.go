@@ -0,0 +1,97 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/baron-chain/cosmos-bc-47/server/config"
+)
+
+// s3ObjectStore is an objectStore backed by an S3 (or S3 compatible) bucket.
+type s3ObjectStore struct {
+	client *s3.S3
+	bucket string
+}
+
+var _ objectStore = (*s3ObjectStore)(nil)
+
+// newS3ObjectStore returns an objectStore for the configured bucket. Credentials
+// are resolved by the AWS default credential chain.
+func newS3ObjectStore(cfg config.SnapshotStoreConfig) (*s3ObjectStore, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("snapshot store bucket is required for the s3 backend")
+	}
+
+	awsCfg := aws.NewConfig()
+	if cfg.Region != "" {
+		awsCfg = awsCfg.WithRegion(cfg.Region)
+	}
+	if cfg.Endpoint != "" {
+		// S3 compatible services usually don't support virtual hosted buckets
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint).WithS3ForcePathStyle(true)
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsCfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 session: %w", err)
+	}
+
+	return &s3ObjectStore{client: s3.New(sess), bucket: cfg.Bucket}, nil
+}
+
+func (s *s3ObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, errObjectNotFound
+		}
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *s3ObjectStore) Put(ctx context.Context, key string, body []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(body),
+	})
+	return err
+}
+
+func (s *s3ObjectStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *s3ObjectStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.StringValue(obj.Key))
+		}
+		return true
+	})
+	return keys, err
+}
@@ -0,0 +1,594 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec // SHA-1 is the piece and info hash of BitTorrent v1
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+const (
+	publishTorrentCmdUse   = "publish-torrent <height> <format>"
+	publishTorrentCmdShort = "Generate a .torrent with webseeds for a dumped snapshot archive"
+	publishTorrentCmdLong  = `Generate a BitTorrent metainfo file for a snapshot archive written by the dump
+command, so that the community can share the distribution of snapshots instead of a
+single server paying for all the bandwidth.
+
+The archive is verified against its manifest, whose height and format must be the
+given ones, before it is hashed. Archives dumped with --split-size are published as
+a multi-file torrent of their parts and split manifest, given with --archive.
+
+The --webseed URLs are HTTP(S) mirrors of the archive (BEP 19), which clients
+download from when there are no peers: a URL ending with / is the directory of the
+archive, and other URLs the archive itself. Trackers are optional, clients find peers
+with the DHT.
+
+The torrent only depends on the archive and the piece length, so that nodes dumping
+the same archive publish the same info-hash. The info-hash of the written file is
+checked against the hashed archive, and against --info-hash when given, e.g. the one
+published by another node. The command prints the info-hash and the magnet link.`
+	publishTorrentCmdExample = `  # Publish the archive of the snapshot at height 1000000 format 1
+  barond snapshots publish-torrent 1000000 1 --webseed https://snapshots.example.com/
+
+  # Publish a split archive, checking the info-hash published by another node
+  barond snapshots publish-torrent 1000000 1 --archive 1000000-1.parts.json \
+    --webseed https://snapshots.example.com/ --info-hash <info-hash>`
+
+	flagArchive     = "archive"
+	flagWebseed     = "webseed"
+	flagTracker     = "tracker"
+	flagPieceLength = "piece-length"
+	flagInfoHash    = "info-hash"
+
+	torrentExt = ".torrent"
+
+	// minPieceLength and maxPieceLength bound the piece length, a power of
+	// two, picked for the archive to have about targetPieces pieces unless
+	// given with --piece-length.
+	minPieceLength = 256 << 10
+	maxPieceLength = 16 << 20
+	targetPieces   = 2000
+
+	// maxBencodeDepth bounds the nesting of the decoded metainfo.
+	maxBencodeDepth = 32
+)
+
+// torrentFile is a file of a snapshot torrent.
+type torrentFile struct {
+	// path is the local path of the file.
+	path string
+	// name is the path of the file in the torrent, under its name.
+	name   string
+	length int64
+}
+
+// snapshotTorrent is the metainfo of a snapshot archive torrent.
+type snapshotTorrent struct {
+	name        string
+	files       []torrentFile
+	pieceLength int64
+	// pieces are the concatenated SHA-1 of the pieces of the files.
+	pieces []byte
+	// multiFile is set for split archives, whose files are in the directory
+	// of the name of the torrent.
+	multiFile bool
+}
+
+// PublishTorrentCmd returns the command generating the torrent of a dumped
+// snapshot archive.
+func PublishTorrentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     publishTorrentCmdUse,
+		Short:   publishTorrentCmdShort,
+		Long:    publishTorrentCmdLong,
+		Example: publishTorrentCmdExample,
+		Args:    cobra.ExactArgs(2),
+		RunE:    runPublishTorrentCmd,
+	}
+
+	cmd.Flags().String(flagArchive, "", "Archive file or split manifest to publish, <height>-<format>.tar.gz by default")
+	cmd.Flags().StringSlice(flagWebseed, nil, "HTTP(S) URLs of the mirrors of the archive, a URL ending with / being a directory")
+	cmd.Flags().StringSlice(flagTracker, nil, "Announce URLs of the trackers")
+	cmd.Flags().String(flagPieceLength, "", "Piece length, a power of two e.g. 4MiB, picked from the archive size by default")
+	cmd.Flags().String(flagInfoHash, "", "Expected info-hash of the torrent, in hex")
+	cmd.Flags().StringP(flagOutput, flagOutputShort, "", "Output file path, the archive path with the .torrent extension by default")
+	return cmd
+}
+
+func runPublishTorrentCmd(cmd *cobra.Command, args []string) error {
+	height, err := parseHeight(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid height: %w", err)
+	}
+	format, err := parseFormat(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+
+	archivePath, _ := cmd.Flags().GetString(flagArchive)
+	if archivePath == "" {
+		archivePath = fmt.Sprintf("%d-%d%s", height, format, archiveExt)
+	}
+	webseeds, _ := cmd.Flags().GetStringSlice(flagWebseed)
+	for _, ws := range webseeds {
+		if u, err := url.Parse(ws); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid --%s %q, expected an HTTP(S) URL", flagWebseed, ws)
+		}
+	}
+	trackers, _ := cmd.Flags().GetStringSlice(flagTracker)
+	expectedHash, _ := cmd.Flags().GetString(flagInfoHash)
+	var pieceLength int64
+	if s, _ := cmd.Flags().GetString(flagPieceLength); s != "" {
+		if pieceLength, err = parsePieceLength(s); err != nil {
+			return err
+		}
+	}
+
+	archive, closeFiles, err := openArchiveFiles([]string{archivePath})
+	if err != nil {
+		return err
+	}
+	manifest, err := readVerifiedArchive(archive)
+	closeFiles()
+	if err != nil {
+		return err
+	}
+	if manifest.Height != height || manifest.Format != format {
+		return fmt.Errorf("archive %s holds the snapshot at height %d format %d, not %d format %d", archivePath, manifest.Height, manifest.Format, height, format)
+	}
+
+	files, multiFile, err := archiveTorrentFiles(archivePath)
+	if err != nil {
+		return err
+	}
+	torrent, err := newSnapshotTorrent(files, multiFile, pieceLength)
+	if err != nil {
+		return err
+	}
+	infoHash, err := torrent.infoHash()
+	if err != nil {
+		return err
+	}
+
+	outputPath, _ := cmd.Flags().GetString(flagOutput)
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(strings.TrimSuffix(archivePath, archiveExt), splitManifestExt) + torrentExt
+	}
+	comment := fmt.Sprintf("%s snapshot at height %d format %d", manifest.ChainID, height, format)
+	bz, err := torrent.metainfo(webseeds, trackers, comment)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outputPath, bz, defaultFileMode); err != nil {
+		return fmt.Errorf("failed to write torrent: %w", err)
+	}
+
+	if err := verifyTorrentFile(outputPath, infoHash); err != nil {
+		return err
+	}
+	if expectedHash != "" && !strings.EqualFold(expectedHash, hex.EncodeToString(infoHash)) {
+		return fmt.Errorf("info-hash %x of the torrent doesn't match the expected --%s %s, the archives differ", infoHash, flagInfoHash, expectedHash)
+	}
+
+	cmd.Printf("Torrent written to %s\n", outputPath)
+	cmd.Printf("Info-hash:   %x\n", infoHash)
+	cmd.Printf("Pieces:      %d of %s\n", len(torrent.pieces)/sha1.Size, humanize.IBytes(uint64(torrent.pieceLength)))
+	cmd.Printf("Magnet link: %s\n", magnetLink(infoHash, torrent.name, webseeds, trackers))
+	return nil
+}
+
+// parsePieceLength parses a piece length such as 4MiB.
+func parsePieceLength(s string) (int64, error) {
+	n, err := humanize.ParseBytes(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --%s %q: %w", flagPieceLength, s, err)
+	}
+	if n < 16<<10 || n > maxPieceLength || n&(n-1) != 0 {
+		return 0, fmt.Errorf("invalid --%s %q: must be a power of two from 16KiB to %s", flagPieceLength, s, humanize.IBytes(maxPieceLength))
+	}
+	return int64(n), nil
+}
+
+// archiveTorrentFiles returns the files of the torrent of an archive: the
+// archive file, or the parts and the split manifest of a split archive, which
+// is published as a multi-file torrent.
+func archiveTorrentFiles(archivePath string) ([]torrentFile, bool, error) {
+	if !strings.HasSuffix(archivePath, ".json") {
+		info, err := os.Stat(archivePath)
+		if err != nil {
+			return nil, false, err
+		}
+		return []torrentFile{{path: archivePath, name: filepath.Base(archivePath), length: info.Size()}}, false, nil
+	}
+
+	bz, err := os.ReadFile(archivePath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read split manifest: %w", err)
+	}
+	var split SplitArchiveManifest
+	if err := json.Unmarshal(bz, &split); err != nil {
+		return nil, false, fmt.Errorf("invalid split manifest: %w", err)
+	}
+
+	dir := filepath.Dir(archivePath)
+	files := make([]torrentFile, 0, len(split.Parts)+1)
+	for _, part := range split.Parts {
+		files = append(files, torrentFile{path: filepath.Join(dir, part.Name), name: part.Name, length: part.Size})
+	}
+	files = append(files, torrentFile{path: archivePath, name: filepath.Base(archivePath), length: int64(len(bz))})
+	return files, true, nil
+}
+
+// newSnapshotTorrent hashes the files into pieces of pieceLength, or of a
+// length picked from their size when zero.
+func newSnapshotTorrent(files []torrentFile, multiFile bool, pieceLength int64) (*snapshotTorrent, error) {
+	if len(files) == 0 {
+		return nil, errors.New("torrent has no files")
+	}
+	var total int64
+	for _, f := range files {
+		total += f.length
+	}
+	if pieceLength == 0 {
+		pieceLength = minPieceLength
+		for pieceLength < maxPieceLength && total/pieceLength > targetPieces {
+			pieceLength *= 2
+		}
+	}
+
+	t := &snapshotTorrent{name: files[0].name, files: files, pieceLength: pieceLength, multiFile: multiFile}
+	if multiFile {
+		t.name = strings.TrimSuffix(files[len(files)-1].name, splitManifestExt)
+	}
+
+	h := &pieceHasher{pieceLength: pieceLength, hash: sha1.New()} //nolint:gosec
+	for _, f := range files {
+		if err := h.hashFile(f); err != nil {
+			return nil, err
+		}
+	}
+	t.pieces = h.sum()
+	return t, nil
+}
+
+// info returns the info dictionary of the torrent, whose SHA-1 is its
+// info-hash.
+func (t *snapshotTorrent) info() map[string]interface{} {
+	info := map[string]interface{}{
+		"name":         t.name,
+		"piece length": t.pieceLength,
+		"pieces":       t.pieces,
+	}
+	if !t.multiFile {
+		info["length"] = t.files[0].length
+		return info
+	}
+
+	files := make([]interface{}, len(t.files))
+	for i, f := range t.files {
+		files[i] = map[string]interface{}{
+			"length": f.length,
+			"path":   []interface{}{f.name},
+		}
+	}
+	info["files"] = files
+	return info
+}
+
+// infoHash returns the info-hash of the torrent.
+func (t *snapshotTorrent) infoHash() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := bencode(&buf, t.info()); err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(buf.Bytes()) //nolint:gosec
+	return sum[:], nil
+}
+
+// metainfo returns the bencoded metainfo file of the torrent. It has no
+// creation date, so that it only depends on its arguments.
+func (t *snapshotTorrent) metainfo(webseeds, trackers []string, comment string) ([]byte, error) {
+	m := map[string]interface{}{
+		"info":       t.info(),
+		"comment":    comment,
+		"created by": "barond snapshots publish-torrent",
+	}
+	if len(webseeds) > 0 {
+		m["url-list"] = stringList(webseeds)
+	}
+	if len(trackers) > 0 {
+		m["announce"] = trackers[0]
+		tiers := make([]interface{}, len(trackers))
+		for i, tr := range trackers {
+			tiers[i] = []interface{}{tr}
+		}
+		m["announce-list"] = tiers
+	}
+
+	var buf bytes.Buffer
+	if err := bencode(&buf, m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// verifyTorrentFile checks that the info-hash of the torrent file is
+// infoHash, and that its info dictionary is well-formed.
+func verifyTorrentFile(path string, infoHash []byte) error {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read torrent: %w", err)
+	}
+	rawInfo, err := torrentInfoBytes(bz)
+	if err != nil {
+		return fmt.Errorf("invalid torrent %s: %w", path, err)
+	}
+	if sum := sha1.Sum(rawInfo); !bytes.Equal(sum[:], infoHash) { //nolint:gosec
+		return fmt.Errorf("info-hash %x of torrent %s doesn't match the info-hash %x of the archive", sum, path, infoHash)
+	}
+
+	v, _, err := bdecode(rawInfo, 0)
+	if err != nil {
+		return fmt.Errorf("invalid torrent %s: %w", path, err)
+	}
+	info, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid torrent %s: info is not a dictionary", path)
+	}
+	pieces, _ := info["pieces"].(string)
+	if len(pieces) == 0 || len(pieces)%sha1.Size != 0 {
+		return fmt.Errorf("invalid torrent %s: invalid pieces", path)
+	}
+	return nil
+}
+
+// magnetLink returns the magnet link of a torrent, with its webseeds and
+// trackers.
+func magnetLink(infoHash []byte, name string, webseeds, trackers []string) string {
+	link := "magnet:?xt=urn:btih:" + hex.EncodeToString(infoHash) + "&dn=" + url.QueryEscape(name)
+	for _, ws := range webseeds {
+		link += "&ws=" + url.QueryEscape(ws)
+	}
+	for _, tr := range trackers {
+		link += "&tr=" + url.QueryEscape(tr)
+	}
+	return link
+}
+
+// pieceHasher hashes the concatenated files of a torrent into pieces.
+type pieceHasher struct {
+	pieceLength int64
+	hash        hash.Hash
+	// n is the number of bytes of the current piece.
+	n      int64
+	pieces []byte
+}
+
+func (h *pieceHasher) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		n := h.pieceLength - h.n
+		if int64(len(p)) < n {
+			n = int64(len(p))
+		}
+		h.hash.Write(p[:n])
+		h.n += n
+		p = p[n:]
+		if h.n == h.pieceLength {
+			h.pieces = h.hash.Sum(h.pieces)
+			h.hash.Reset()
+			h.n = 0
+		}
+	}
+	return written, nil
+}
+
+// hashFile hashes the file, which must have its expected length.
+func (h *pieceHasher) hashFile(f torrentFile) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	n, err := io.Copy(h, file)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", f.path, err)
+	}
+	if n != f.length {
+		return fmt.Errorf("file %s has %d bytes, expected %d", f.path, n, f.length)
+	}
+	return nil
+}
+
+// sum returns the hashes of the pieces, the last one being shorter.
+func (h *pieceHasher) sum() []byte {
+	if h.n > 0 {
+		h.pieces = h.hash.Sum(h.pieces)
+		h.hash.Reset()
+		h.n = 0
+	}
+	return h.pieces
+}
+
+func stringList(s []string) []interface{} {
+	l := make([]interface{}, len(s))
+	for i, v := range s {
+		l[i] = v
+	}
+	return l
+}
+
+// bencode writes the bencoding of v, of integers, strings, byte slices, lists
+// and dictionaries with string keys, which are sorted.
+func bencode(w *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case int64:
+		w.WriteString("i" + strconv.FormatInt(v, 10) + "e")
+	case int:
+		w.WriteString("i" + strconv.Itoa(v) + "e")
+	case string:
+		w.WriteString(strconv.Itoa(len(v)) + ":" + v)
+	case []byte:
+		w.WriteString(strconv.Itoa(len(v)) + ":")
+		w.Write(v)
+	case []interface{}:
+		w.WriteByte('l')
+		for _, e := range v {
+			if err := bencode(w, e); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('e')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.WriteByte('d')
+		for _, k := range keys {
+			if err := bencode(w, k); err != nil {
+				return err
+			}
+			if err := bencode(w, v[k]); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('e')
+	default:
+		return fmt.Errorf("cannot bencode %T", v)
+	}
+	return nil
+}
+
+// bdecode decodes the bencoded value at the start of data, and returns it
+// with the rest of data. Integers are decoded as int64, strings as string,
+// lists as []interface{} and dictionaries as map[string]interface{}.
+func bdecode(data []byte, depth int) (interface{}, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	if depth > maxBencodeDepth {
+		return nil, nil, errors.New("bencoded value nested too deeply")
+	}
+
+	switch c := data[0]; {
+	case c == 'i':
+		end := bytes.IndexByte(data, 'e')
+		if end < 0 {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		n, err := strconv.ParseInt(string(data[1:end]), 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid bencoded integer: %w", err)
+		}
+		return n, data[end+1:], nil
+
+	case c >= '0' && c <= '9':
+		colon := bytes.IndexByte(data, ':')
+		if colon < 0 {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		n, err := strconv.Atoi(string(data[:colon]))
+		if err != nil || n < 0 {
+			return nil, nil, fmt.Errorf("invalid bencoded string length %q", data[:colon])
+		}
+		rest := data[colon+1:]
+		if n > len(rest) {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		return string(rest[:n]), rest[n:], nil
+
+	case c == 'l':
+		list := []interface{}{}
+		rest := data[1:]
+		for len(rest) > 0 && rest[0] != 'e' {
+			var (
+				e   interface{}
+				err error
+			)
+			if e, rest, err = bdecode(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			list = append(list, e)
+		}
+		if len(rest) == 0 {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		return list, rest[1:], nil
+
+	case c == 'd':
+		dict := map[string]interface{}{}
+		rest, err := decodeDict(data, depth, func(key string, value interface{}, _ []byte) {
+			dict[key] = value
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return dict, rest, nil
+
+	default:
+		return nil, nil, fmt.Errorf("invalid bencoded value starting with %q", c)
+	}
+}
+
+// decodeDict decodes the bencoded dictionary at the start of data, calling fn
+// with each key, its value and the bencoding of the value, and returns the
+// rest of data.
+func decodeDict(data []byte, depth int, fn func(key string, value interface{}, raw []byte)) ([]byte, error) {
+	rest := data[1:]
+	for len(rest) > 0 && rest[0] != 'e' {
+		k, valueStart, err := bdecode(rest, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, errors.New("bencoded dictionary key is not a string")
+		}
+		var value interface{}
+		if value, rest, err = bdecode(valueStart, depth+1); err != nil {
+			return nil, err
+		}
+		fn(key, value, valueStart[:len(valueStart)-len(rest)])
+	}
+	if len(rest) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return rest[1:], nil
+}
+
+// torrentInfoBytes returns the bencoding of the info dictionary of a
+// metainfo file, whose SHA-1 is the info-hash of the torrent.
+func torrentInfoBytes(metainfo []byte) ([]byte, error) {
+	if len(metainfo) == 0 || metainfo[0] != 'd' {
+		return nil, errors.New("metainfo is not a bencoded dictionary")
+	}
+	var info []byte
+	_, err := decodeDict(metainfo, 0, func(key string, _ interface{}, raw []byte) {
+		if key == "info" {
+			info = raw
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if info == nil || info[0] != 'd' {
+		return nil, errors.New("metainfo has no info dictionary")
+	}
+	return info, nil
+}
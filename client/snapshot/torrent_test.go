@@ -0,0 +1,100 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBencode(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, bencode(&buf, map[string]interface{}{
+		"b": []interface{}{int64(-3), "x"},
+		"a": []byte("spam"),
+	}))
+	require.Equal(t, "d1:a4:spam1:bli-3e1:xee", buf.String())
+
+	v, rest, err := bdecode(append(buf.Bytes(), "tail"...), 0)
+	require.NoError(t, err)
+	require.Equal(t, "tail", string(rest))
+	require.Equal(t, map[string]interface{}{"a": "spam", "b": []interface{}{int64(-3), "x"}}, v)
+
+	for _, invalid := range []string{"", "i12", "5:abc", "l1:a", "d1:ai1e", "di1ei2ee", "x"} {
+		_, _, err := bdecode([]byte(invalid), 0)
+		require.Error(t, err, invalid)
+	}
+	_, _, err = bdecode(bytes.Repeat([]byte("l"), maxBencodeDepth+2), 0)
+	require.Error(t, err)
+}
+
+func TestSnapshotTorrent(t *testing.T) {
+	dir := t.TempDir()
+	var (
+		files []torrentFile
+		all   []byte
+	)
+	for _, name := range []string{"100-3.part000.tar.gz", "100-3.part001.tar.gz", "100-3.parts.json"} {
+		data := make([]byte, 40000)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		files = append(files, torrentFile{path: path, name: name, length: int64(len(data))})
+		all = append(all, data...)
+	}
+
+	torrent, err := newSnapshotTorrent(files, true, 16<<10)
+	require.NoError(t, err)
+	require.Equal(t, "100-3", torrent.name)
+
+	// pieces span the files, the last one being shorter
+	var pieces []byte
+	for i := 0; i < len(all); i += 16 << 10 {
+		end := i + 16<<10
+		if end > len(all) {
+			end = len(all)
+		}
+		sum := sha1.Sum(all[i:end]) //nolint:gosec
+		pieces = append(pieces, sum[:]...)
+	}
+	require.Equal(t, pieces, torrent.pieces)
+
+	infoHash, err := torrent.infoHash()
+	require.NoError(t, err)
+	bz, err := torrent.metainfo([]string{"https://snapshots.example.com/"}, []string{"udp://tracker.example.com:1337"}, "baron-1 snapshot")
+	require.NoError(t, err)
+	path := filepath.Join(dir, "100-3.torrent")
+	require.NoError(t, os.WriteFile(path, bz, 0o600))
+	require.NoError(t, verifyTorrentFile(path, infoHash))
+
+	// the info-hash only depends on the files and the piece length
+	other, err := newSnapshotTorrent(files, true, 16<<10)
+	require.NoError(t, err)
+	otherHash, err := other.infoHash()
+	require.NoError(t, err)
+	require.Equal(t, infoHash, otherHash)
+
+	tampered := bytes.Replace(bz, []byte("5:100-3"), []byte("5:100-4"), 1)
+	require.NoError(t, os.WriteFile(path, tampered, 0o600))
+	require.ErrorContains(t, verifyTorrentFile(path, infoHash), "doesn't match")
+
+	require.Equal(t,
+		"magnet:?xt=urn:btih:0102&dn=100-3&ws=https%3A%2F%2Fsnapshots.example.com%2F",
+		magnetLink([]byte{1, 2}, "100-3", []string{"https://snapshots.example.com/"}, nil))
+}
+
+func TestParsePieceLength(t *testing.T) {
+	n, err := parsePieceLength("4MiB")
+	require.NoError(t, err)
+	require.Equal(t, int64(4<<20), n)
+
+	for _, invalid := range []string{"3MiB", "1KiB", "64MiB", "x"} {
+		_, err := parsePieceLength(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,280 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/baron-chain/cosmos-bc-47/snapshots"
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+)
+
+const (
+	flagTo        = "to"
+	flagDeleteOld = "delete-old"
+)
+
+// formatMigration rewrites an item of a snapshot from a format into the next
+// one. It returns nil to drop the item.
+type formatMigration func(item *snapshottypes.SnapshotItem) (*snapshottypes.SnapshotItem, error)
+
+// formatMigrations are the migrations from each format into the next one. The
+// formats share the stream layout, zlib compressed length-prefixed items cut
+// into 10MB chunks, and differ in the items they hold.
+var formatMigrations = map[uint32]formatMigration{
+	// format 2 adds the items of the snapshot extensions after the stores,
+	// which format 1 snapshots have none of
+	1: func(item *snapshottypes.SnapshotItem) (*snapshottypes.SnapshotItem, error) {
+		return item, nil
+	},
+	// format 3 has no items of the store/v2alpha1 stores, which were removed,
+	// so the snapshots holding them can't be migrated
+	2: func(item *snapshottypes.SnapshotItem) (*snapshottypes.SnapshotItem, error) {
+		switch item.Item.(type) {
+		case *snapshottypes.SnapshotItem_KV, *snapshottypes.SnapshotItem_Schema: //nolint:staticcheck // deprecated items
+			return nil, errors.New("the snapshot holds store/v2alpha1 items, which format 3 doesn't support")
+		default:
+			return item, nil
+		}
+	},
+}
+
+// UpgradeResult is the result of the upgrade of a snapshot.
+type UpgradeResult struct {
+	Height     uint64
+	FromFormat uint32
+	Snapshot   *snapshottypes.Snapshot
+	// Skipped is set when the snapshot already existed in the target format.
+	Skipped bool
+}
+
+// UpgradeFormatCmd returns a command to rewrite the snapshots of older formats
+// into a newer one.
+func UpgradeFormatCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade-format",
+		Short: "Rewrite Baron Chain snapshots of older formats into a newer format",
+		Long: `Rewrite the snapshots of the configured snapshot store whose format is older than --to
+into that format, so that the historical snapshots can still be served and restored after
+a snapshot format bump.
+
+The items of each snapshot are read from its chunks, migrated from one format to the next,
+and written into new chunks, which are saved as a new snapshot at the same height. The
+chunks of the original snapshot are verified against its metadata before being migrated.
+The original snapshots are kept, unless --delete-old is set.`,
+		Example: `  # Upgrade all the snapshots to the current format
+  barond snapshots upgrade-format --to 3
+
+  # Upgrade the snapshot at height 1000000, and delete the original one
+  barond snapshots upgrade-format --to 3 --height 1000000 --delete-old`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			to, _ := cmd.Flags().GetUint32(flagTo)
+			height, _ := cmd.Flags().GetUint64(flagHeight)
+			deleteOld, _ := cmd.Flags().GetBool(flagDeleteOld)
+
+			snapshotStore, err := GetSnapshotStore(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to get snapshot store: %w", err)
+			}
+
+			results, err := upgradeSnapshots(snapshotStore, to, height, deleteOld)
+			for _, res := range results {
+				if res.Skipped {
+					cmd.Printf("Height %d: format %d already exists, skipped format %d\n", res.Height, to, res.FromFormat)
+					continue
+				}
+				cmd.Printf("Height %d: upgraded format %d to %d, %d chunks, hash %X\n",
+					res.Height, res.FromFormat, to, res.Snapshot.Chunks, res.Snapshot.Hash)
+			}
+			if err != nil {
+				return err
+			}
+			if len(results) == 0 {
+				cmd.Printf("No snapshots older than format %d\n", to)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint32(flagTo, snapshottypes.CurrentFormat, "Format to upgrade the snapshots to")
+	cmd.Flags().Uint64(flagHeight, 0, "Only upgrade the snapshots at this height")
+	cmd.Flags().Bool(flagDeleteOld, false, "Delete the original snapshots once upgraded")
+	return cmd
+}
+
+// upgradeSnapshots upgrades the snapshots older than format to, or only those
+// at height if it is set, and returns the results of the snapshots upgraded
+// until the first error.
+func upgradeSnapshots(store SnapshotStore, to uint32, height uint64, deleteOld bool) ([]UpgradeResult, error) {
+	if to == 0 || to > snapshottypes.CurrentFormat {
+		return nil, fmt.Errorf("invalid --%s %d, the current format is %d", flagTo, to, snapshottypes.CurrentFormat)
+	}
+
+	list, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var results []UpgradeResult
+	for _, snapshot := range list {
+		if snapshot.Format >= to || (height != 0 && snapshot.Height != height) {
+			continue
+		}
+
+		res := UpgradeResult{Height: snapshot.Height, FromFormat: snapshot.Format}
+		existing, err := store.Get(snapshot.Height, to)
+		if err != nil {
+			return results, fmt.Errorf("failed to get snapshot: %w", err)
+		}
+		if existing != nil {
+			res.Skipped = true
+		} else if res.Snapshot, err = upgradeSnapshot(store, snapshot, to); err != nil {
+			return results, fmt.Errorf("failed to upgrade snapshot at height %d format %d: %w", snapshot.Height, snapshot.Format, err)
+		}
+
+		if deleteOld {
+			if err := store.Delete(snapshot.Height, snapshot.Format); err != nil {
+				return results, fmt.Errorf("failed to delete snapshot at height %d format %d: %w", snapshot.Height, snapshot.Format, err)
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// upgradeSnapshot rewrites a snapshot into format to, and saves it at the same
+// height. The partially saved snapshot is deleted on failure.
+func upgradeSnapshot(store SnapshotStore, snapshot *snapshottypes.Snapshot, to uint32) (*snapshottypes.Snapshot, error) {
+	var migrations []formatMigration
+	for format := snapshot.Format; format < to; format++ {
+		migrate, ok := formatMigrations[format]
+		if !ok {
+			return nil, fmt.Errorf("no migration from format %d to %d", format, format+1)
+		}
+		migrations = append(migrations, migrate)
+	}
+
+	done := make(chan struct{})
+	reader, err := snapshots.NewStreamReader(loadVerifiedChunks(store, snapshot, done))
+	if err != nil {
+		close(done)
+		return nil, err
+	}
+	defer func() {
+		close(done)
+		reader.Close()
+	}()
+
+	chunks := make(chan io.ReadCloser)
+	migrated := make(chan error, 1)
+	go func() {
+		migrated <- migrateItems(reader, chunks, migrations)
+	}()
+
+	saved, err := store.Save(snapshot.Height, to, chunks)
+	if migrateErr := <-migrated; err == nil {
+		err = migrateErr
+	}
+	if err != nil {
+		_ = store.Delete(snapshot.Height, to)
+		return nil, err
+	}
+	return saved, nil
+}
+
+// migrateItems reads the items of a snapshot, migrates them, and writes them
+// into new chunks sent to chunks.
+func migrateItems(reader *snapshots.StreamReader, chunks chan<- io.ReadCloser, migrations []formatMigration) error {
+	writer := snapshots.NewStreamWriter(chunks)
+	if writer == nil {
+		return errors.New("failed to create snapshot stream writer")
+	}
+
+	for {
+		item := &snapshottypes.SnapshotItem{}
+		err := reader.ReadMsg(item)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to read snapshot item: %w", err)
+			writer.CloseWithError(err)
+			return err
+		}
+
+		for _, migrate := range migrations {
+			if item, err = migrate(item); err != nil || item == nil {
+				break
+			}
+		}
+		if err != nil {
+			writer.CloseWithError(err)
+			return err
+		}
+		if item == nil {
+			continue
+		}
+
+		if err := writer.WriteMsg(item); err != nil {
+			writer.CloseWithError(err)
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// loadVerifiedChunks sends the chunks of a snapshot to the returned channel,
+// after verifying them against the chunk hashes of its metadata, until done
+// is closed. A chunk which fails to load is sent as a reader returning the
+// error.
+func loadVerifiedChunks(store SnapshotStore, snapshot *snapshottypes.Snapshot, done <-chan struct{}) <-chan io.ReadCloser {
+	chunks := make(chan io.ReadCloser)
+	go func() {
+		defer close(chunks)
+		for i := uint32(0); i < snapshot.Chunks; i++ {
+			data, err := loadVerifiedChunk(store, snapshot, i)
+			var chunk io.ReadCloser = io.NopCloser(bytes.NewReader(data))
+			if err != nil {
+				pr, pw := io.Pipe()
+				pw.CloseWithError(fmt.Errorf("chunk %d: %w", i, err))
+				chunk = pr
+			}
+
+			select {
+			case chunks <- chunk:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return chunks
+}
+
+func loadVerifiedChunk(store SnapshotStore, snapshot *snapshottypes.Snapshot, index uint32) ([]byte, error) {
+	chunk, err := store.LoadChunk(snapshot.Height, snapshot.Format, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chunk: %w", err)
+	}
+	if chunk == nil {
+		return nil, errors.New("chunk doesn't exist")
+	}
+	defer chunk.Close()
+
+	data, err := io.ReadAll(chunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunk: %w", err)
+	}
+	hash := sha256.Sum256(data)
+	if int(index) < len(snapshot.Metadata.ChunkHashes) && !bytes.Equal(hash[:], snapshot.Metadata.ChunkHashes[index]) {
+		return nil, errors.New("checksum mismatch, the stored chunk is corrupted")
+	}
+	return data, nil
+}
@@ -0,0 +1,126 @@
+package snapshot
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/require"
+
+	"github.com/baron-chain/cosmos-bc-47/snapshots"
+	snapshottypes "github.com/baron-chain/cosmos-bc-47/snapshots/types"
+)
+
+// saveItemsSnapshot saves a snapshot of the items in the given format.
+func saveItemsSnapshot(t *testing.T, store SnapshotStore, height uint64, format uint32, items []*snapshottypes.SnapshotItem) *snapshottypes.Snapshot {
+	t.Helper()
+
+	ch := make(chan io.ReadCloser)
+	go func() {
+		writer := snapshots.NewStreamWriter(ch)
+		for _, item := range items {
+			require.NoError(t, writer.WriteMsg(item))
+		}
+		require.NoError(t, writer.Close())
+	}()
+	snapshot, err := store.Save(height, format, ch)
+	require.NoError(t, err)
+	return snapshot
+}
+
+// readItems reads the items of a snapshot.
+func readItems(t *testing.T, store SnapshotStore, height uint64, format uint32) []*snapshottypes.SnapshotItem {
+	t.Helper()
+
+	snapshot, err := store.Get(height, format)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+
+	done := make(chan struct{})
+	defer close(done)
+	reader, err := snapshots.NewStreamReader(loadVerifiedChunks(store, snapshot, done))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	var items []*snapshottypes.SnapshotItem
+	for {
+		item := &snapshottypes.SnapshotItem{}
+		err := reader.ReadMsg(item)
+		if errors.Is(err, io.EOF) {
+			return items
+		}
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+}
+
+func TestUpgradeSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	snapshotStore, err := snapshots.NewStore(dbm.NewMemDB(), dir)
+	require.NoError(t, err)
+	store := &pooledSnapshotStore{Store: snapshotStore, dir: dir}
+
+	items := []*snapshottypes.SnapshotItem{
+		{Item: &snapshottypes.SnapshotItem_Store{Store: &snapshottypes.SnapshotStoreItem{Name: "bank"}}},
+		{Item: &snapshottypes.SnapshotItem_IAVL{IAVL: &snapshottypes.SnapshotIAVLItem{Key: []byte("k"), Value: []byte("v"), Version: 2}}},
+	}
+	saveItemsSnapshot(t, store, 1, 1, items)
+	saveItemsSnapshot(t, store, 2, 2, items)
+	current := saveItemsSnapshot(t, store, 3, 3, items)
+	saveItemsSnapshot(t, store, 4, 2, append(items, &snapshottypes.SnapshotItem{
+		Item: &snapshottypes.SnapshotItem_KV{KV: &snapshottypes.SnapshotKVItem{Key: []byte("k")}}, //nolint:staticcheck // deprecated item
+	}))
+
+	_, err = upgradeSnapshots(store, snapshottypes.CurrentFormat+1, 0, false)
+	require.ErrorContains(t, err, "invalid --to")
+
+	// snapshots holding store/v2alpha1 items can't be upgraded, and are left as is
+	_, err = upgradeSnapshots(store, 3, 4, false)
+	require.ErrorContains(t, err, "store/v2alpha1")
+	upgraded, err := store.Get(4, 3)
+	require.NoError(t, err)
+	require.Nil(t, upgraded)
+	_, err = os.Stat(snapshotStore.PathChunk(4, 3, 0))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.NoError(t, store.Delete(4, 2))
+
+	results, err := upgradeSnapshots(store, 3, 0, true)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, res := range results {
+		require.False(t, res.Skipped)
+		// the items are rewritten as the node would have written them
+		require.Equal(t, current.Hash, res.Snapshot.Hash)
+		require.Equal(t, items, readItems(t, store, res.Height, 3))
+
+		old, err := store.Get(res.Height, res.FromFormat)
+		require.NoError(t, err)
+		require.Nil(t, old, "the old snapshot is deleted")
+	}
+
+	// snapshots already upgraded are skipped
+	saveItemsSnapshot(t, store, 2, 1, items)
+	results, err = upgradeSnapshots(store, 3, 0, false)
+	require.NoError(t, err)
+	require.Equal(t, []UpgradeResult{{Height: 2, FromFormat: 1, Skipped: true}}, results)
+}
+
+func TestUpgradeSnapshotCorruptedChunk(t *testing.T) {
+	dir := t.TempDir()
+	snapshotStore, err := snapshots.NewStore(dbm.NewMemDB(), dir)
+	require.NoError(t, err)
+	store := &pooledSnapshotStore{Store: snapshotStore, dir: dir}
+
+	saveItemsSnapshot(t, store, 1, 2, []*snapshottypes.SnapshotItem{
+		{Item: &snapshottypes.SnapshotItem_Store{Store: &snapshottypes.SnapshotStoreItem{Name: "bank"}}},
+	})
+	require.NoError(t, os.WriteFile(snapshotStore.PathChunk(1, 2, 0), []byte("corrupted"), 0o600))
+
+	_, err = upgradeSnapshots(store, 3, 0, false)
+	require.ErrorContains(t, err, "checksum mismatch")
+	upgraded, err := store.Get(1, 3)
+	require.NoError(t, err)
+	require.Nil(t, upgraded)
+}
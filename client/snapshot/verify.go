@@ -0,0 +1,109 @@
+package snapshot
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagAttestation  = "attestation"
+	flagTrustedNodes = "trusted-nodes"
+)
+
+// VerifyArchiveCmd returns the command verifying a snapshot archive against
+// its manifest, without loading it into the snapshot store.
+func VerifyArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <archive-file>...",
+		Short: "Verify a snapshot archive file (.tar.gz) against its manifest",
+		Long: `Verify that a snapshot archive written by the dump command is complete and that
+its chunks match the SHA-256 checksums of its manifest, and print the manifest. Archives
+dumped without a manifest cannot be verified. Archives dumped with --split-size are
+verified from their split manifest, which also verifies the checksums of the parts, or
+from their part files, in order.
+
+With --attestation, the archive must also be attested, see "snapshots dump --attest",
+and the attestation signature is checked. Use --trusted-nodes to only accept archives
+attested by the given node IDs, e.g. the nodes of known validators.`,
+		Example: `  barond snapshots verify 1000000-1.tar.gz
+  barond snapshots verify 1000000-1.tar.gz --attestation --trusted-nodes <node-id>,<node-id>
+  barond snapshots verify 1000000-1.parts.json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checkAttestation, _ := cmd.Flags().GetBool(flagAttestation)
+			trustedNodes, _ := cmd.Flags().GetStringSlice(flagTrustedNodes)
+			if len(trustedNodes) > 0 && !checkAttestation {
+				return fmt.Errorf("--%s requires --%s", flagTrustedNodes, flagAttestation)
+			}
+
+			archive, closeFiles, err := openArchiveFiles(args)
+			if err != nil {
+				return err
+			}
+			defer closeFiles()
+
+			manifest, err := readVerifiedArchive(archive)
+			if err != nil {
+				return err
+			}
+
+			if checkAttestation {
+				if err := manifest.VerifyAttestation(trustedNodes); err != nil {
+					return fmt.Errorf("invalid archive attestation: %w", err)
+				}
+			}
+
+			cmd.Printf("Chain ID:     %s\n", manifest.ChainID)
+			cmd.Printf("App version:  %s\n", manifest.AppVersion)
+			cmd.Printf("Height:       %d\n", manifest.Height)
+			cmd.Printf("Format:       %d\n", manifest.Format)
+			cmd.Printf("Chunks:       %d\n", len(manifest.ChunkHashes))
+			cmd.Printf("Hash:         %s\n", manifest.Hash)
+			cmd.Printf("Created at:   %s\n", manifest.CreatedAt)
+			cmd.Printf("Tool version: %s\n", manifest.ToolVersion)
+			if a := manifest.Attestation; a != nil {
+				cmd.Printf("App commit:   %s\n", a.AppCommit)
+				cmd.Printf("Attested by:  %s\n", a.NodeID)
+			}
+			if checkAttestation {
+				cmd.Println("Archive and attestation are valid")
+			} else {
+				cmd.Println("Archive is valid")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(flagAttestation, false, "Require a valid attestation of the archive")
+	cmd.Flags().StringSlice(flagTrustedNodes, nil, "Node IDs whose attestations are trusted, any node by default")
+
+	return cmd
+}
+
+// verifyArchive reads a whole archive, verifying its chunks against its
+// manifest, and returns the manifest.
+func verifyArchive(r io.Reader) (*ArchiveManifest, error) {
+	archive, err := newArchiveReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return readVerifiedArchive(archive)
+}
+
+// readVerifiedArchive reads the chunks of an archive, verifying them against
+// its manifest, and returns the manifest.
+func readVerifiedArchive(archive *archiveReader) (*ArchiveManifest, error) {
+	if archive.manifest == nil {
+		return nil, fmt.Errorf("archive has no %s, it was dumped by an older version and cannot be verified", ManifestFileName)
+	}
+
+	for {
+		if _, err := archive.nextChunk(); err == io.EOF {
+			return archive.manifest, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
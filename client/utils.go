@@ -2,6 +2,8 @@ package client
 //BC MOD //BC MOD [ER:#1811] [ER:#1811] [ER:#1811] [ER:#1811] [ER:#1811] [ER:#1811]
 import (
 	"encoding/base64"
+	"net/http"
+	"strings"
 
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
 	"github.com/spf13/pflag"
@@ -73,10 +75,27 @@ func ReadPageRequest(flagSet *pflag.FlagSet) (*query.PageRequest, error) {
 	}, nil
 }
 
+// nodeProxy returns the proxy of the JSON RPC requests to a node, if any.
+var nodeProxy = http.ProxyFromEnvironment
+
 // NewClientFromNode sets up Client implementation that communicates with a Tendermint node over
-// JSON RPC and WebSockets
+// JSON RPC and WebSockets. The node URI is a tcp://, http:// or https:// address, or the path of
+// a unix socket as unix://<path>, e.g. forwarded from a bastion with ssh -L. The JSON RPC
+// requests to tcp, http and https nodes go through the proxy of the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables, if set, tcp nodes being reached over http.
 func NewClientFromNode(nodeURI string) (*rpchttp.HTTP, error) {
-	return rpchttp.New(nodeURI, "/websocket")
+	if strings.HasPrefix(nodeURI, "unix://") {
+		return rpchttp.New(nodeURI, "/websocket")
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Proxy: nodeProxy,
+			// as the default client of the node, against GZIP bombs
+			DisableCompression: true,
+		},
+	}
+	return rpchttp.NewWithClient(nodeURI, "/websocket", httpClient)
 }
 
 // FlagSetWithPageKeyDecoded returns the provided flagSet with the page-key value base64 decoded (if it exists).
@@ -0,0 +1,151 @@
+// Package aggsig verifies many signatures in one call. Dilithium signatures
+// can't be aggregated, so they are verified in parallel, and identical entries
+// only once, as are the signatures of the other schemes. A Cache remembers the
+// verified signatures, so that verifying them ahead of time, e.g. for all the
+// transactions of a proposed block or the vote attestations of a commit,
+// spares their verification later.
+package aggsig
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"runtime"
+	"sync"
+)
+
+// PubKey is a public key verifying signatures, e.g. a cryptotypes.PubKey or a
+// DilithiumPubKey.
+type PubKey interface {
+	VerifySignature(msg, sig []byte) bool
+	Bytes() []byte
+	Type() string
+}
+
+// Entry is a signature to verify.
+type Entry struct {
+	PubKey PubKey
+	Msg    []byte
+	Sig    []byte
+}
+
+// digest identifies the entry in a Cache and among the entries of a batch.
+func (e Entry) digest() [sha256.Size]byte {
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(e.PubKey.Type()), e.PubKey.Bytes(), e.Msg, e.Sig} {
+		_ = binary.Write(h, binary.BigEndian, uint32(len(part)))
+		h.Write(part)
+	}
+	var d [sha256.Size]byte
+	h.Sum(d[:0])
+	return d
+}
+
+// BatchVerifier verifies the signatures added to it in one call. It is not
+// safe for concurrent use.
+type BatchVerifier struct {
+	entries []Entry
+	workers int
+	cache   *Cache
+}
+
+// NewBatchVerifier returns a BatchVerifier verifying the signatures with up to
+// workers goroutines, GOMAXPROCS if workers isn't positive.
+func NewBatchVerifier(workers int) *BatchVerifier {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &BatchVerifier{workers: workers}
+}
+
+// WithCache makes the verifier skip the signatures of cache, and add the
+// ones it verifies to it.
+func (bv *BatchVerifier) WithCache(cache *Cache) *BatchVerifier {
+	bv.cache = cache
+	return bv
+}
+
+// Add adds a signature to verify.
+func (bv *BatchVerifier) Add(pubKey PubKey, msg, sig []byte) {
+	bv.entries = append(bv.entries, Entry{PubKey: pubKey, Msg: msg, Sig: sig})
+}
+
+// Len returns the number of signatures added.
+func (bv *BatchVerifier) Len() int {
+	return len(bv.entries)
+}
+
+// Verify verifies the signatures added, and returns whether all are valid and
+// the validity of each of them, in the order they were added.
+func (bv *BatchVerifier) Verify() (bool, []bool) {
+	valid := Verify(bv.entries, bv.workers, bv.cache)
+	for _, ok := range valid {
+		if !ok {
+			return false, valid
+		}
+	}
+	return true, valid
+}
+
+// Verify verifies entries with up to workers goroutines, GOMAXPROCS if
+// workers isn't positive, and returns the validity of each of them. The
+// entries of cache, if not nil, are valid, and the valid entries are added to
+// it.
+func Verify(entries []Entry, workers int, cache *Cache) []bool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	valid := make([]bool, len(entries))
+
+	// identical entries are verified once
+	digests := make([][sha256.Size]byte, len(entries))
+	first := make(map[[sha256.Size]byte]int, len(entries))
+	var idxs []int
+	for i, e := range entries {
+		digests[i] = e.digest()
+		if cache != nil && cache.contains(digests[i]) {
+			valid[i] = true
+			continue
+		}
+		if _, ok := first[digests[i]]; ok {
+			continue
+		}
+		first[digests[i]] = i
+		idxs = append(idxs, i)
+	}
+	verifyParallel(entries, idxs, valid, workers)
+
+	for i := range entries {
+		if j, ok := first[digests[i]]; ok {
+			valid[i] = valid[j]
+		}
+		if valid[i] && cache != nil {
+			cache.add(digests[i])
+		}
+	}
+	return valid
+}
+
+// verifyParallel verifies the entries at idxs individually with up to workers
+// goroutines, and sets their validity.
+func verifyParallel(entries []Entry, idxs []int, valid []bool, workers int) {
+	if workers > len(idxs) {
+		workers = len(idxs)
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				valid[i] = entries[i].PubKey.VerifySignature(entries[i].Msg, entries[i].Sig)
+			}
+		}()
+	}
+	for _, i := range idxs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
@@ -0,0 +1,113 @@
+package aggsig_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/cloudflare/circl/sign/dilithium"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto/aggsig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+func dilithiumEntry(t testing.TB, msg []byte) aggsig.Entry {
+	mode := dilithium.Mode3
+	pub, priv, err := mode.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	pubKey, err := aggsig.NewDilithiumPubKey(mode.Name(), pub.Bytes())
+	require.NoError(t, err)
+	return aggsig.Entry{PubKey: pubKey, Msg: msg, Sig: mode.Sign(priv, msg)}
+}
+
+func TestBatchVerifier(t *testing.T) {
+	bv := aggsig.NewBatchVerifier(4)
+	var entries []aggsig.Entry
+	for i := 0; i < 5; i++ {
+		msg := []byte(fmt.Sprintf("tx %d", i))
+		entries = append(entries, dilithiumEntry(t, msg))
+
+		secpKey := secp256k1.GenPrivKey()
+		sig, err := secpKey.Sign(msg)
+		require.NoError(t, err)
+		entries = append(entries, aggsig.Entry{PubKey: secpKey.PubKey(), Msg: msg, Sig: sig})
+	}
+	for _, e := range entries {
+		bv.Add(e.PubKey, e.Msg, e.Sig)
+	}
+	// duplicated entries are valid too
+	bv.Add(entries[0].PubKey, entries[0].Msg, entries[0].Sig)
+	require.Equal(t, len(entries)+1, bv.Len())
+
+	ok, valid := bv.Verify()
+	require.True(t, ok)
+	require.Len(t, valid, len(entries)+1)
+
+	// the invalid signatures are found, of dilithium and secp256k1 keys
+	invalid := map[int]bool{0: true, 3: true}
+	bv = aggsig.NewBatchVerifier(0)
+	for i, e := range entries {
+		msg := e.Msg
+		if invalid[i] {
+			msg = []byte("forged")
+		}
+		bv.Add(e.PubKey, msg, e.Sig)
+	}
+	ok, valid = bv.Verify()
+	require.False(t, ok)
+	for i := range entries {
+		require.Equal(t, !invalid[i], valid[i], "entry %d", i)
+	}
+
+	_, err := aggsig.NewDilithiumPubKey("Dilithium3", []byte("short"))
+	require.ErrorContains(t, err, "invalid Dilithium3 public key size")
+	_, err = aggsig.NewDilithiumPubKey("Falcon", nil)
+	require.ErrorContains(t, err, "unknown Dilithium mode")
+}
+
+func TestCache(t *testing.T) {
+	entries := []aggsig.Entry{dilithiumEntry(t, []byte("a")), dilithiumEntry(t, []byte("b"))}
+	forged := aggsig.Entry{PubKey: entries[1].PubKey, Msg: []byte("c"), Sig: entries[1].Sig}
+
+	cache := aggsig.NewCache(2)
+	valid := aggsig.Verify(append(entries, forged), 0, cache)
+	require.Equal(t, []bool{true, true, false}, valid)
+	require.Equal(t, 2, cache.Len())
+	require.True(t, cache.Contains(entries[0].PubKey, entries[0].Msg, entries[0].Sig))
+	require.False(t, cache.Contains(forged.PubKey, forged.Msg, forged.Sig))
+
+	// the oldest signatures are evicted
+	third := dilithiumEntry(t, []byte("d"))
+	aggsig.Verify([]aggsig.Entry{third}, 0, cache)
+	require.Equal(t, 2, cache.Len())
+	require.False(t, cache.Contains(entries[0].PubKey, entries[0].Msg, entries[0].Sig))
+
+	// the cached signatures of a key aren't verified again
+	key := secp256k1.GenPrivKey()
+	sig, err := key.Sign([]byte("e"))
+	require.NoError(t, err)
+	aggsig.Verify([]aggsig.Entry{{PubKey: key.PubKey(), Msg: []byte("e"), Sig: sig}}, 0, cache)
+	cached := aggsig.WithCache(key.PubKey(), cache)
+	require.True(t, cached.VerifySignature([]byte("e"), sig))
+	require.False(t, cached.VerifySignature([]byte("f"), sig))
+	require.Equal(t, key.PubKey().Address(), cached.Address())
+
+	cache.Reset()
+	require.Zero(t, cache.Len())
+}
+
+func BenchmarkVerify(b *testing.B) {
+	entries := make([]aggsig.Entry, 64)
+	for i := range entries {
+		entries[i] = dilithiumEntry(b, []byte(fmt.Sprintf("tx %d", i)))
+	}
+
+	for _, workers := range []int{1, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				aggsig.Verify(entries, workers, nil)
+			}
+		})
+	}
+}
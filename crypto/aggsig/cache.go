@@ -0,0 +1,96 @@
+package aggsig
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+// Cache is a bounded set of verified signatures, safe for concurrent use.
+// When full, the oldest signatures are evicted first.
+type Cache struct {
+	mtx     sync.Mutex
+	size    int
+	entries map[[sha256.Size]byte]struct{}
+	order   [][sha256.Size]byte
+}
+
+// NewCache returns a Cache of up to size signatures.
+func NewCache(size int) *Cache {
+	return &Cache{size: size, entries: make(map[[sha256.Size]byte]struct{})}
+}
+
+// Contains returns whether the signature of msg by pubKey was verified.
+func (c *Cache) Contains(pubKey PubKey, msg, sig []byte) bool {
+	return c.contains(Entry{PubKey: pubKey, Msg: msg, Sig: sig}.digest())
+}
+
+// Len returns the number of signatures of the cache.
+func (c *Cache) Len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return len(c.entries)
+}
+
+// Reset removes all the signatures of the cache.
+func (c *Cache) Reset() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries = make(map[[sha256.Size]byte]struct{})
+	c.order = nil
+}
+
+func (c *Cache) contains(d [sha256.Size]byte) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	_, ok := c.entries[d]
+	return ok
+}
+
+func (c *Cache) add(d [sha256.Size]byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.entries[d]; ok || c.size <= 0 {
+		return
+	}
+	if len(c.order) >= c.size {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[d] = struct{}{}
+	c.order = append(c.order, d)
+}
+
+// cachedPubKey is a public key whose signatures of a Cache are valid.
+type cachedPubKey struct {
+	cryptotypes.PubKey
+	cache *Cache
+}
+
+// WithCache returns pubKey, verifying the signatures of cache without
+// verifying them again.
+func WithCache(pubKey cryptotypes.PubKey, cache *Cache) cryptotypes.PubKey {
+	if cache == nil {
+		return pubKey
+	}
+	return cachedPubKey{PubKey: pubKey, cache: cache}
+}
+
+func (pk cachedPubKey) VerifySignature(msg, sig []byte) bool {
+	return pk.cache.Contains(pk.PubKey, msg, sig) || pk.PubKey.VerifySignature(msg, sig)
+}
+
+type contextKey struct{}
+
+// ContextKey is the key of the Cache of the contexts of the transactions, set
+// by the BaseApp after verifying the signatures of a block ahead of time.
+var ContextKey = contextKey{}
+
+// CacheFromContext returns the Cache of ctx, nil if none.
+func CacheFromContext(ctx interface {
+	Value(key interface{}) interface{}
+}) *Cache {
+	cache, _ := ctx.Value(ContextKey).(*Cache)
+	return cache
+}
@@ -0,0 +1,36 @@
+package aggsig
+
+import (
+	"fmt"
+
+	"github.com/cloudflare/circl/sign/dilithium"
+)
+
+// DilithiumPubKey is a packed Dilithium public key of a mode, e.g. the
+// attestation keys of validators, to verify with the other entries.
+type DilithiumPubKey struct {
+	mode dilithium.Mode
+	key  dilithium.PublicKey
+	raw  []byte
+}
+
+// NewDilithiumPubKey unpacks a public key of the Dilithium mode of the given
+// name, e.g. Dilithium3.
+func NewDilithiumPubKey(modeName string, packed []byte) (*DilithiumPubKey, error) {
+	mode := dilithium.ModeByName(modeName)
+	if mode == nil {
+		return nil, fmt.Errorf("unknown Dilithium mode %s", modeName)
+	}
+	if len(packed) != mode.PublicKeySize() {
+		return nil, fmt.Errorf("invalid %s public key size %d, expected %d", modeName, len(packed), mode.PublicKeySize())
+	}
+	return &DilithiumPubKey{mode: mode, key: mode.PublicKeyFromBytes(packed), raw: packed}, nil
+}
+
+func (pk *DilithiumPubKey) VerifySignature(msg, sig []byte) bool {
+	return len(sig) == pk.mode.SignatureSize() && pk.mode.Verify(pk.key, msg, sig)
+}
+
+func (pk *DilithiumPubKey) Bytes() []byte { return pk.raw }
+
+func (pk *DilithiumPubKey) Type() string { return pk.mode.Name() }
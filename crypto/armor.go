@@ -10,9 +10,7 @@ import (
 	"golang.org/x/crypto/openpgp/armor" //nolint:staticcheck
 
 	"github.com/cosmos/cosmos-sdk/codec/legacy"
-	"github.com/cosmos/cosmos-sdk/crypto/keys/bcrypt"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
-	"github.com/cosmos/cosmos-sdk/crypto/xsalsa20symmetric"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
@@ -25,9 +23,20 @@ const (
 	headerType       = "type"
 	headerKDF        = "kdf"
 	headerSalt       = "salt"
-	bcryptKDF        = "bcrypt"
+	headerKDFParams  = "kdf-params"
 	version0         = "0.0.0"
 	version1         = "0.0.1"
+	version2         = "0.0.2"
+)
+
+// The names of the private key armor headers and the block type of public key
+// armors, for the tests of armors, see armortest.RunArmorConformance.
+const (
+	ArmorHeaderKDF       = headerKDF
+	ArmorHeaderKDFParams = headerKDFParams
+	ArmorHeaderSalt      = headerSalt
+	ArmorHeaderCipher    = headerCipher
+	ArmorBlockTypePubKey = blockTypePubKey
 )
 
 // BcryptSecurityParameter defines the security level for bcrypt key generation
@@ -67,43 +76,61 @@ func UnarmorInfoBytes(armorStr string) ([]byte, error) {
 	return bz, nil
 }
 
-// UnarmorPubKeyBytes decrypts armored public key bytes and returns the key bytes, algorithm and any error
+// UnarmorPubKeyBytes decrypts armored public key bytes and returns the key bytes, algorithm and any error.
+// The metadata of the key, if any, is verified, see UnarmorPubKeyBytesWithMetadata.
 func UnarmorPubKeyBytes(armorStr string) ([]byte, string, error) {
-	bz, header, err := unarmorBytes(armorStr, blockTypePubKey)
+	bz, algo, _, err := UnarmorPubKeyBytesWithMetadata(armorStr)
+	return bz, algo, err
+}
+
+// EncryptArmorPrivKey encrypts and armors a private key, using bcrypt with
+// BcryptSecurityParameter to derive the encryption key, or the KDF selected by
+// the crypto policy when it doesn't allow bcrypt. It returns an error if the
+// key or the KDF is not allowed by the policy.
+func EncryptArmorPrivKey(privKey cryptotypes.PrivKey, passphrase, algo string) (string, error) {
+	kdf, err := PolicyKDF()
 	if err != nil {
-		return nil, "", fmt.Errorf("couldn't unarmor bytes: %v", err)
+		return "", sdkerrors.Wrap(err, "invalid key encryption KDF")
 	}
 
-	switch header[headerVersion] {
-	case version0:
-		return bz, defaultAlgo, nil
-	case version1:
-		algo := header[headerType]
-		if algo == "" {
-			algo = defaultAlgo
-		}
-		return bz, algo, nil
-	case "":
-		return nil, "", fmt.Errorf("header's version field is empty")
-	default:
-		return nil, "", fmt.Errorf("unrecognized version: %v", header[headerVersion])
-	}
+	return EncryptArmorPrivKeyWithKDF(privKey, passphrase, algo, kdf)
 }
 
-// EncryptArmorPrivKey encrypts and armors a private key
-func EncryptArmorPrivKey(privKey cryptotypes.PrivKey, passphrase, algo string) string {
-	saltBytes, encBytes := encryptPrivKey(privKey, passphrase)
+// EncryptArmorPrivKeyWithKDF encrypts and armors a private key, using the given
+// KDF to derive the encryption key. The KDF must be registered with RegisterKDF
+// for the key to be decrypted. The key algorithm and the KDF must be allowed by
+// the crypto policy.
+func EncryptArmorPrivKeyWithKDF(privKey cryptotypes.PrivKey, passphrase, algo string, kdf KDF) (string, error) {
+	p := GetPolicy()
+	if err := p.CheckAlgo(privKey.Type()); err != nil {
+		return "", err
+	}
+	if err := p.CheckKDF(kdf); err != nil {
+		return "", err
+	}
+
+	saltBytes, cipherName, encBytes, err := encryptPrivKey(privKey, passphrase, kdf)
+	if err != nil {
+		return "", err
+	}
+
 	header := map[string]string{
-		headerKDF:  bcryptKDF,
+		headerKDF:  kdf.Name(),
 		headerSalt: fmt.Sprintf("%X", saltBytes),
 	}
+	setCipherHeader(header, cipherName)
+	if params := kdf.Params(); params != "" {
+		header[headerKDFParams] = params
+	}
 	if algo != "" {
 		header[headerType] = algo
 	}
-	return EncodeArmor(blockTypePrivKey, header, encBytes)
+	return EncodeArmor(blockTypePrivKey, header, encBytes), nil
 }
 
-// UnarmorDecryptPrivKey decrypts an armored private key and returns the key, algorithm and any error
+// UnarmorDecryptPrivKey decrypts an armored private key and returns the key, algorithm and any error.
+// The key algorithm and the KDF must be allowed by the crypto policy, and
+// in FIPS mode the cipher must be AES-256-GCM.
 func UnarmorDecryptPrivKey(armorStr, passphrase string) (privKey cryptotypes.PrivKey, algo string, err error) {
 	blockType, header, encBytes, err := DecodeArmor(armorStr)
 	if err != nil {
@@ -114,12 +141,37 @@ func UnarmorDecryptPrivKey(armorStr, passphrase string) (privKey cryptotypes.Pri
 		return nil, "", err
 	}
 
+	kdf, err := GetKDF(header[headerKDF], header[headerKDFParams])
+	if err != nil {
+		return nil, "", err
+	}
+
+	// checked before deriving the key, which is costly
+	p := GetPolicy()
+	if err := p.CheckKDF(kdf); err != nil {
+		return nil, "", err
+	}
+	if err := checkFIPSCipher(armorCipher(header)); err != nil {
+		return nil, "", err
+	}
+	if algo := header[headerType]; algo != "" {
+		if err := p.CheckAlgo(algo); err != nil {
+			return nil, "", err
+		}
+	}
+
 	saltBytes, err := hex.DecodeString(header[headerSalt])
 	if err != nil {
 		return nil, "", fmt.Errorf("error decoding salt: %v", err.Error())
 	}
 
-	privKey, err = decryptPrivKey(saltBytes, encBytes, passphrase)
+	privKey, err = decryptPrivKey(saltBytes, header[headerCipher], encBytes, passphrase, kdf)
+	if err == nil {
+		// the header type is not authenticated
+		if policyErr := p.CheckAlgo(privKey.Type()); policyErr != nil {
+			return nil, "", policyErr
+		}
+	}
 	if header[headerType] == "" {
 		header[headerType] = defaultAlgo
 	}
@@ -169,10 +221,6 @@ func validatePrivKeyHeader(blockType string, header map[string]string) error {
 		return fmt.Errorf("unrecognized armor type: %v", blockType)
 	}
 
-	if header[headerKDF] != bcryptKDF {
-		return fmt.Errorf("unrecognized KDF type: %v", header[headerKDF])
-	}
-
 	if header[headerSalt] == "" {
 		return fmt.Errorf("missing salt bytes")
 	}
@@ -193,26 +241,39 @@ func unarmorBytes(armorStr, blockType string) ([]byte, map[string]string, error)
 	return bz, header, nil
 }
 
-func encryptPrivKey(privKey cryptotypes.PrivKey, passphrase string) (saltBytes []byte, encBytes []byte) {
+// armorCipher returns the cipher of an armor, xsalsa20-poly1305 when it has
+// no cipher header.
+func armorCipher(header map[string]string) string {
+	if c := header[headerCipher]; c != "" {
+		return c
+	}
+	return CipherXSalsa20Poly1305
+}
+
+func encryptPrivKey(privKey cryptotypes.PrivKey, passphrase string, kdf KDF) (saltBytes []byte, cipherName string, encBytes []byte, err error) {
 	saltBytes = crypto.CRandBytes(16)
-	key, err := bcrypt.GenerateFromPassword(saltBytes, []byte(passphrase), BcryptSecurityParameter)
+	key, err := kdf.DeriveKey([]byte(passphrase), saltBytes)
 	if err != nil {
-		panic(sdkerrors.Wrap(err, "error generating bcrypt key from passphrase"))
+		return nil, "", nil, sdkerrors.Wrapf(err, "error generating %s key from passphrase", kdf.Name())
 	}
+	defer zero(key)
 
-	key = crypto.Sha256(key)
 	privKeyBytes := legacy.Cdc.MustMarshal(privKey)
-	return saltBytes, xsalsa20symmetric.EncryptSymmetric(privKeyBytes, key)
+	cipherName, encBytes, err = EncryptSymmetric(privKeyBytes, key)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return saltBytes, cipherName, encBytes, nil
 }
 
-func decryptPrivKey(saltBytes []byte, encBytes []byte, passphrase string) (cryptotypes.PrivKey, error) {
-	key, err := bcrypt.GenerateFromPassword(saltBytes, []byte(passphrase), BcryptSecurityParameter)
+func decryptPrivKey(saltBytes []byte, cipherName string, encBytes []byte, passphrase string, kdf KDF) (cryptotypes.PrivKey, error) {
+	key, err := kdf.DeriveKey([]byte(passphrase), saltBytes)
 	if err != nil {
-		return nil, sdkerrors.Wrap(err, "error generating bcrypt key from passphrase")
+		return nil, sdkerrors.Wrapf(err, "error generating %s key from passphrase", kdf.Name())
 	}
+	defer zero(key)
 
-	key = crypto.Sha256(key)
-	privKeyBytes, err := xsalsa20symmetric.DecryptSymmetric(encBytes, key)
+	privKeyBytes, err := DecryptSymmetric(cipherName, encBytes, key)
 	if err != nil {
 		if err.Error() == "Ciphertext decryption failed" {
 			return nil, sdkerrors.ErrWrongPassword
@@ -34,7 +34,8 @@ const (
 
 func TestPrivKeyArmorOperations(t *testing.T) {
 	priv := secp256k1.GenPrivKey()
-	armored := crypto.EncryptArmorPrivKey(priv, testPassphrase, "")
+	armored, err := crypto.EncryptArmorPrivKey(priv, testPassphrase, "")
+	require.NoError(t, err)
 
 	t.Run("wrong passphrase", func(t *testing.T) {
 		_, _, err := crypto.UnarmorDecryptPrivKey(armored, "wrongpassphrase")
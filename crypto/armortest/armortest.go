@@ -0,0 +1,211 @@
+// Package armortest checks the private key armors of a chain, see
+// RunArmorConformance.
+package armortest
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+// conformancePassphrase is the passphrase of the keys armored by
+// RunArmorConformance.
+const conformancePassphrase = "armor conformance passphrase"
+
+// ArmorProfile is the armor configuration of a chain, checked by
+// RunArmorConformance.
+type ArmorProfile struct {
+	// KDF is the KDF private keys are encrypted with. Nil selects the KDF of
+	// the crypto policy, see crypto.PolicyKDF.
+	KDF crypto.KDF
+	// PrivKeys are keys of each algorithm the chain armors.
+	PrivKeys []cryptotypes.PrivKey
+	// Fixtures are private keys armored by earlier versions of the chain,
+	// which must still be decrypted.
+	Fixtures []ArmorFixture
+}
+
+// ArmorFixture is an armored private key of an ArmorProfile.
+type ArmorFixture struct {
+	Name       string
+	Armor      string
+	Passphrase string
+	// PrivKey is the key the armor decrypts to, if known.
+	PrivKey cryptotypes.PrivKey
+}
+
+// RunArmorConformance checks the armor profile of a chain against the current
+// crypto policy, KDF registry and cipher, so that adding or changing
+// algorithms doesn't silently break the keys already armored. Chains run it
+// from their tests:
+//
+//	func TestArmorConformance(t *testing.T) {
+//		armortest.RunArmorConformance(t, armortest.ArmorProfile{
+//			PrivKeys: []cryptotypes.PrivKey{secp256k1.GenPrivKey()},
+//			Fixtures: []armortest.ArmorFixture{{Name: "v1", Armor: v1Armor, Passphrase: "test"}},
+//		})
+//	}
+//
+// It checks that each key round-trips through crypto.EncryptArmorPrivKeyWithKDF
+// and crypto.UnarmorDecryptPrivKey, that the KDF is registered with the
+// parameters it writes, that wrong passphrases, tampered ciphertexts and
+// malformed headers are rejected, that armors without kdf-params are
+// decrypted with the KDF default parameters, and that the fixtures are still
+// decrypted.
+func RunArmorConformance(t *testing.T, profile ArmorProfile) {
+	t.Helper()
+
+	kdf := profile.KDF
+	if kdf == nil {
+		var err error
+		if kdf, err = crypto.PolicyKDF(); err != nil {
+			t.Fatalf("policy KDF: %v", err)
+		}
+	}
+	if len(profile.PrivKeys) == 0 {
+		t.Fatal("the armor profile has no private keys")
+	}
+
+	t.Run("kdf registry", func(t *testing.T) {
+		registered, err := crypto.GetKDF(kdf.Name(), kdf.Params())
+		if err != nil {
+			t.Fatalf("KDF %s is not registered with parameters %q: %v", kdf.Name(), kdf.Params(), err)
+		}
+		if registered.Params() != kdf.Params() {
+			t.Fatalf("registered KDF %s has parameters %q, expected %q", kdf.Name(), registered.Params(), kdf.Params())
+		}
+
+		// armors have 16 bytes salts, which bcrypt requires
+		salt := []byte("conformance salt")
+		key, err := kdf.DeriveKey([]byte(conformancePassphrase), salt)
+		if err != nil {
+			t.Fatalf("deriving key: %v", err)
+		}
+		if len(key) != crypto.KDFKeySize {
+			t.Fatalf("KDF %s derived a %d bytes key, expected %d", kdf.Name(), len(key), crypto.KDFKeySize)
+		}
+		if ok, err := crypto.VerifyKey(registered, []byte(conformancePassphrase), salt, key); err != nil || !ok {
+			t.Fatalf("registered KDF %s derives another key: %v", kdf.Name(), err)
+		}
+	})
+
+	for _, privKey := range profile.PrivKeys {
+		privKey := privKey
+		t.Run(privKey.Type(), func(t *testing.T) {
+			armored, err := crypto.EncryptArmorPrivKeyWithKDF(privKey, conformancePassphrase, privKey.Type(), kdf)
+			if err != nil {
+				t.Fatalf("armoring key: %v", err)
+			}
+			checkArmorRoundTrip(t, armored, conformancePassphrase, privKey)
+			checkArmorErrors(t, armored)
+
+			pubArmor := crypto.ArmorPubKeyBytes(privKey.PubKey().Bytes(), privKey.Type())
+			bz, algo, err := crypto.UnarmorPubKeyBytes(pubArmor)
+			if err != nil {
+				t.Fatalf("unarmoring public key: %v", err)
+			}
+			if !bytes.Equal(bz, privKey.PubKey().Bytes()) || algo != privKey.Type() {
+				t.Fatalf("public key armor round-trip returned a %s key %X", algo, bz)
+			}
+		})
+	}
+
+	t.Run("default kdf parameters", func(t *testing.T) {
+		defaultKDF, err := crypto.GetKDF(kdf.Name(), "")
+		if err != nil {
+			t.Fatalf("KDF %s default parameters: %v", kdf.Name(), err)
+		}
+		privKey := profile.PrivKeys[0]
+		armored, err := crypto.EncryptArmorPrivKeyWithKDF(privKey, conformancePassphrase, privKey.Type(), defaultKDF)
+		if errors.Is(err, crypto.ErrPolicyViolation) {
+			t.Skipf("the default parameters of KDF %s are not allowed: %v", kdf.Name(), err)
+		}
+		if err != nil {
+			t.Fatalf("armoring key: %v", err)
+		}
+		checkArmorRoundTrip(t, reencodeArmor(t, armored, func(header map[string]string) {
+			delete(header, crypto.ArmorHeaderKDFParams)
+		}), conformancePassphrase, privKey)
+	})
+
+	for _, fixture := range profile.Fixtures {
+		fixture := fixture
+		t.Run("fixture "+fixture.Name, func(t *testing.T) {
+			if fixture.PrivKey != nil {
+				checkArmorRoundTrip(t, fixture.Armor, fixture.Passphrase, fixture.PrivKey)
+				return
+			}
+			if _, _, err := crypto.UnarmorDecryptPrivKey(fixture.Armor, fixture.Passphrase); err != nil {
+				t.Fatalf("decrypting fixture: %v", err)
+			}
+		})
+	}
+}
+
+// checkArmorRoundTrip checks the armor decrypts to the private key.
+func checkArmorRoundTrip(t *testing.T, armored, passphrase string, privKey cryptotypes.PrivKey) {
+	t.Helper()
+
+	decrypted, algo, err := crypto.UnarmorDecryptPrivKey(armored, passphrase)
+	if err != nil {
+		t.Fatalf("decrypting armor: %v", err)
+	}
+	if !decrypted.Equals(privKey) {
+		t.Fatalf("armor decrypted to another %s key", decrypted.Type())
+	}
+	if algo != privKey.Type() {
+		t.Fatalf("armor has algorithm %s, expected %s", algo, privKey.Type())
+	}
+}
+
+// checkArmorErrors checks the wrong passphrases, tampered ciphertexts and
+// malformed headers of the armor are rejected.
+func checkArmorErrors(t *testing.T, armored string) {
+	t.Helper()
+
+	if _, _, err := crypto.UnarmorDecryptPrivKey(armored, "wrong "+conformancePassphrase); err == nil {
+		t.Error("decrypting with a wrong passphrase succeeded")
+	}
+
+	blockType, header, data, err := crypto.DecodeArmor(armored)
+	if err != nil {
+		t.Fatalf("decoding armor: %v", err)
+	}
+	tampered := append([]byte(nil), data...)
+	tampered[len(tampered)-1] ^= 1
+	if _, _, err := crypto.UnarmorDecryptPrivKey(crypto.EncodeArmor(blockType, header, tampered), conformancePassphrase); err == nil {
+		t.Error("decrypting a tampered ciphertext succeeded")
+	}
+	if _, _, err := crypto.UnarmorDecryptPrivKey(crypto.EncodeArmor(crypto.ArmorBlockTypePubKey, header, data), conformancePassphrase); err == nil {
+		t.Error("decrypting an armor of another block type succeeded")
+	}
+
+	for name, edit := range map[string]func(map[string]string){
+		"missing salt": func(h map[string]string) { delete(h, crypto.ArmorHeaderSalt) },
+		"invalid salt": func(h map[string]string) { h[crypto.ArmorHeaderSalt] = "not hex" },
+		"unknown kdf":  func(h map[string]string) { h[crypto.ArmorHeaderKDF] = "unknown" },
+		"invalid kdf parameters": func(h map[string]string) {
+			h[crypto.ArmorHeaderKDFParams] = "unknown=1"
+		},
+		"unknown cipher": func(h map[string]string) { h[crypto.ArmorHeaderCipher] = "unknown" },
+	} {
+		if _, _, err := crypto.UnarmorDecryptPrivKey(reencodeArmor(t, armored, edit), conformancePassphrase); err == nil {
+			t.Errorf("decrypting an armor with %s succeeded", name)
+		}
+	}
+}
+
+// reencodeArmor returns the armor with the header edited.
+func reencodeArmor(t *testing.T, armored string, edit func(header map[string]string)) string {
+	t.Helper()
+
+	blockType, header, data, err := crypto.DecodeArmor(armored)
+	if err != nil {
+		t.Fatalf("decoding armor: %v", err)
+	}
+	edit(header)
+	return crypto.EncodeArmor(blockType, header, data)
+}
@@ -0,0 +1,277 @@
+// Package convert converts key files between the legacy "TENDERMINT" armors,
+// the "BARON CHAIN QUANTUM" armors and raw keystore bytes, so that the keyring,
+// client/keys and external tools share one implementation.
+//
+// Both armor families carry the same body: private key armors hold the amino
+// encoded private key encrypted with a passphrase derived key, e.g. with the kdf
+// and salt headers, and public key armors hold the protobuf Any encoded public
+// key. The quantum armors name the key algorithm in an algorithm header rather
+// than the type header of the legacy armors. Converting between them only
+// changes the armor block type and that header, so encrypted private keys are
+// converted without their passphrase.
+//
+// Raw keystore bytes are the unencrypted armor bodies: the amino encoded
+// private key, or the protobuf Any encoded public key.
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/legacy"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+// Format is the format of a key file.
+type Format string
+
+const (
+	// FormatLegacyArmor is the "TENDERMINT PRIVATE KEY" and "TENDERMINT PUBLIC
+	// KEY" armor format.
+	FormatLegacyArmor Format = "legacy-armor"
+	// FormatQuantumArmor is the "BARON CHAIN QUANTUM KEY" and "BARON CHAIN
+	// QUANTUM PUBLIC KEY" armor format.
+	FormatQuantumArmor Format = "quantum-armor"
+	// FormatRaw is the raw keystore bytes format.
+	FormatRaw Format = "raw"
+)
+
+// Kind is the kind of key held by a key file.
+type Kind string
+
+const (
+	KindPrivKey Kind = "private"
+	KindPubKey  Kind = "public"
+)
+
+const (
+	BlockTypeLegacyPrivKey  = "TENDERMINT PRIVATE KEY"
+	BlockTypeLegacyPubKey   = "TENDERMINT PUBLIC KEY"
+	BlockTypeQuantumPrivKey = "BARON CHAIN QUANTUM KEY"
+	BlockTypeQuantumPubKey  = "BARON CHAIN QUANTUM PUBLIC KEY"
+
+	headerType      = "type"
+	headerAlgorithm = "algorithm"
+)
+
+// ErrUnknownFormat is returned when key file bytes are neither a supported
+// armor nor raw keystore bytes.
+var ErrUnknownFormat = errors.New("unknown key file format")
+
+// Info describes a key file.
+type Info struct {
+	Format Format
+	Kind   Kind
+	// Algo is the key algorithm, e.g. secp256k1. It is read from the type or
+	// algorithm header of armors, which doesn't require decrypting private
+	// keys.
+	Algo string
+}
+
+var blockTypes = map[string]Info{
+	BlockTypeLegacyPrivKey:  {Format: FormatLegacyArmor, Kind: KindPrivKey},
+	BlockTypeLegacyPubKey:   {Format: FormatLegacyArmor, Kind: KindPubKey},
+	BlockTypeQuantumPrivKey: {Format: FormatQuantumArmor, Kind: KindPrivKey},
+	BlockTypeQuantumPubKey:  {Format: FormatQuantumArmor, Kind: KindPubKey},
+}
+
+// defaultAlgo is the algorithm of the armors without type header.
+const defaultAlgo = "secp256k1"
+
+var cdc = newCodec()
+
+func newCodec() codec.Codec {
+	registry := codectypes.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+	return codec.NewProtoCodec(registry)
+}
+
+// Detect returns the format, kind and algorithm of key file bytes.
+func Detect(bz []byte) (Info, error) {
+	if isArmor(bz) {
+		blockType, header, _, err := crypto.DecodeArmor(string(bz))
+		if err != nil {
+			return Info{}, fmt.Errorf("invalid armor: %w", err)
+		}
+
+		info, ok := blockTypes[blockType]
+		if !ok {
+			return Info{}, fmt.Errorf("%w: armor type %q", ErrUnknownFormat, blockType)
+		}
+		info.Algo = header[algoHeader(info.Format)]
+		if info.Algo == "" {
+			info.Algo = defaultAlgo
+		}
+		return info, nil
+	}
+
+	if priv, err := legacy.PrivKeyFromBytes(bz); err == nil {
+		return Info{Format: FormatRaw, Kind: KindPrivKey, Algo: priv.Type()}, nil
+	}
+	if pub, err := rawPubKey(bz); err == nil {
+		return Info{Format: FormatRaw, Kind: KindPubKey, Algo: pub.Type()}, nil
+	}
+
+	return Info{}, ErrUnknownFormat
+}
+
+// Convert converts key file bytes to the given format. The passphrase decrypts
+// private keys converted to raw bytes, and encrypts private keys converted from
+// raw bytes; it is unused otherwise.
+func Convert(bz []byte, to Format, passphrase string) ([]byte, error) {
+	info, err := Detect(bz)
+	if err != nil {
+		return nil, err
+	}
+	if info.Format == to {
+		return bz, nil
+	}
+
+	switch to {
+	case FormatLegacyArmor, FormatQuantumArmor:
+		if info.Format == FormatRaw {
+			return rawToArmor(bz, info.Kind, to, passphrase)
+		}
+		return rearmor(bz, info.Kind, to)
+
+	case FormatRaw:
+		return armorToRaw(bz, info.Kind, passphrase)
+
+	default:
+		return nil, fmt.Errorf("unsupported target format %q", to)
+	}
+}
+
+// ToLegacyArmor converts a private or public key armor of either family to a
+// legacy armor, e.g. before passing it to the keyring import functions.
+func ToLegacyArmor(armor string) (string, error) {
+	if !isArmor([]byte(armor)) {
+		return "", fmt.Errorf("%w: not an armor", ErrUnknownFormat)
+	}
+
+	bz, err := Convert([]byte(armor), FormatLegacyArmor, "")
+	if err != nil {
+		return "", err
+	}
+	return string(bz), nil
+}
+
+// ToQuantumArmor converts a private or public key armor of either family to a
+// quantum armor.
+func ToQuantumArmor(armor string) (string, error) {
+	if !isArmor([]byte(armor)) {
+		return "", fmt.Errorf("%w: not an armor", ErrUnknownFormat)
+	}
+
+	bz, err := Convert([]byte(armor), FormatQuantumArmor, "")
+	if err != nil {
+		return "", err
+	}
+	return string(bz), nil
+}
+
+func isArmor(bz []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(bz), []byte("-----BEGIN "))
+}
+
+func blockType(format Format, kind Kind) string {
+	switch {
+	case format == FormatQuantumArmor && kind == KindPrivKey:
+		return BlockTypeQuantumPrivKey
+	case format == FormatQuantumArmor:
+		return BlockTypeQuantumPubKey
+	case kind == KindPrivKey:
+		return BlockTypeLegacyPrivKey
+	default:
+		return BlockTypeLegacyPubKey
+	}
+}
+
+// algoHeader returns the header naming the key algorithm in armors of the
+// given format.
+func algoHeader(format Format) string {
+	if format == FormatQuantumArmor {
+		return headerAlgorithm
+	}
+	return headerType
+}
+
+// rearmor changes the block type and algorithm header of an armor, keeping its
+// other headers and body.
+func rearmor(bz []byte, kind Kind, to Format) ([]byte, error) {
+	from, header, body, err := crypto.DecodeArmor(string(bz))
+	if err != nil {
+		return nil, fmt.Errorf("invalid armor: %w", err)
+	}
+
+	fromHeader := algoHeader(blockTypes[from].Format)
+	algo := header[fromHeader]
+	if algo == "" {
+		algo = defaultAlgo
+	}
+	delete(header, fromHeader)
+	header[algoHeader(to)] = algo
+
+	return []byte(crypto.EncodeArmor(blockType(to, kind), header, body)), nil
+}
+
+func armorToRaw(bz []byte, kind Kind, passphrase string) ([]byte, error) {
+	legacyArmor, err := rearmor(bz, kind, FormatLegacyArmor)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == KindPubKey {
+		pubBytes, _, err := crypto.UnarmorPubKeyBytes(string(legacyArmor))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := rawPubKey(pubBytes); err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		return pubBytes, nil
+	}
+
+	priv, _, err := crypto.UnarmorDecryptPrivKey(string(legacyArmor), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt private key: %w", err)
+	}
+	return legacy.Cdc.Marshal(priv)
+}
+
+func rawToArmor(bz []byte, kind Kind, to Format, passphrase string) ([]byte, error) {
+	var armor string
+	if kind == KindPubKey {
+		pub, err := rawPubKey(bz)
+		if err != nil {
+			return nil, err
+		}
+		armor = crypto.ArmorPubKeyBytes(bz, pub.Type())
+	} else {
+		priv, err := legacy.PrivKeyFromBytes(bz)
+		if err != nil {
+			return nil, err
+		}
+		if armor, err = crypto.EncryptArmorPrivKey(priv, passphrase, priv.Type()); err != nil {
+			return nil, err
+		}
+	}
+
+	if to == FormatLegacyArmor {
+		return []byte(armor), nil
+	}
+	return rearmor([]byte(armor), kind, to)
+}
+
+func rawPubKey(bz []byte) (cryptotypes.PubKey, error) {
+	var pub cryptotypes.PubKey
+	if err := cdc.UnmarshalInterface(bz, &pub); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
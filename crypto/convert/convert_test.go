@@ -0,0 +1,128 @@
+package convert_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/codec/legacy"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/convert"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+func TestConvertPrivKey(t *testing.T) {
+	priv := secp256k1.GenPrivKey()
+	legacyArmor, err := crypto.EncryptArmorPrivKey(priv, "passphrase", priv.Type())
+	require.NoError(t, err)
+
+	info, err := convert.Detect([]byte(legacyArmor))
+	require.NoError(t, err)
+	require.Equal(t, convert.Info{Format: convert.FormatLegacyArmor, Kind: convert.KindPrivKey, Algo: "secp256k1"}, info)
+
+	// armors are converted without the passphrase
+	quantumArmor, err := convert.ToQuantumArmor(legacyArmor)
+	require.NoError(t, err)
+	blockType, header, _, err := crypto.DecodeArmor(quantumArmor)
+	require.NoError(t, err)
+	require.Equal(t, "BARON CHAIN QUANTUM KEY", blockType)
+	require.Equal(t, "secp256k1", header["algorithm"])
+	require.NotEmpty(t, header["salt"])
+	require.NotEmpty(t, header["kdf"])
+	require.NotContains(t, header, "type")
+	info, err = convert.Detect([]byte(quantumArmor))
+	require.NoError(t, err)
+	require.Equal(t, convert.Info{Format: convert.FormatQuantumArmor, Kind: convert.KindPrivKey, Algo: "secp256k1"}, info)
+
+	back, err := convert.ToLegacyArmor(quantumArmor)
+	require.NoError(t, err)
+	decrypted, _, err := crypto.UnarmorDecryptPrivKey(back, "passphrase")
+	require.NoError(t, err)
+	require.True(t, priv.Equals(decrypted))
+
+	// raw bytes are decrypted and encrypted with the passphrase
+	_, err = convert.Convert([]byte(quantumArmor), convert.FormatRaw, "wrong")
+	require.Error(t, err)
+	raw, err := convert.Convert([]byte(quantumArmor), convert.FormatRaw, "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, legacy.Cdc.MustMarshal(priv), raw)
+
+	info, err = convert.Detect(raw)
+	require.NoError(t, err)
+	require.Equal(t, convert.Info{Format: convert.FormatRaw, Kind: convert.KindPrivKey, Algo: "secp256k1"}, info)
+
+	rearmored, err := convert.Convert(raw, convert.FormatQuantumArmor, "other")
+	require.NoError(t, err)
+	back, err = convert.ToLegacyArmor(string(rearmored))
+	require.NoError(t, err)
+	decrypted, _, err = crypto.UnarmorDecryptPrivKey(back, "other")
+	require.NoError(t, err)
+	require.True(t, priv.Equals(decrypted))
+}
+
+func TestConvertPubKey(t *testing.T) {
+	registry := codectypes.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+	cdc := codec.NewProtoCodec(registry)
+
+	pub := secp256k1.GenPrivKey().PubKey()
+	raw, err := cdc.MarshalInterface(pub)
+	require.NoError(t, err)
+
+	info, err := convert.Detect(raw)
+	require.NoError(t, err)
+	require.Equal(t, convert.Info{Format: convert.FormatRaw, Kind: convert.KindPubKey, Algo: "secp256k1"}, info)
+
+	quantumArmor, err := convert.Convert(raw, convert.FormatQuantumArmor, "")
+	require.NoError(t, err)
+	require.Contains(t, string(quantumArmor), convert.BlockTypeQuantumPubKey)
+
+	legacyArmor, err := convert.ToLegacyArmor(string(quantumArmor))
+	require.NoError(t, err)
+	bz, algo, err := crypto.UnarmorPubKeyBytes(legacyArmor)
+	require.NoError(t, err)
+	require.Equal(t, raw, bz)
+	require.Equal(t, "secp256k1", algo)
+
+	back, err := convert.Convert([]byte(legacyArmor), convert.FormatRaw, "")
+	require.NoError(t, err)
+	require.Equal(t, raw, back)
+}
+
+func TestDetectQuantumKey(t *testing.T) {
+	armor := `-----BEGIN BARON CHAIN QUANTUM KEY-----
+algorithm: kyber
+salt: Q790BB721D1C094260EA84F5E5B72289
+kdf: argon2id
+
+HbP+c6JmeJy9JXe2rbbF1QtCX1gLqGcDQPBXiCtFvP7/8wTZtVOPj8vREzhZ9ElO
+3P7YnrzPQThG0Q+ZnRSbl9MAS8uFAM4mqm5r/Ys=
+=f3l4
+-----END BARON CHAIN QUANTUM KEY-----
+`
+	info, err := convert.Detect([]byte(armor))
+	require.NoError(t, err)
+	require.Equal(t, convert.Info{Format: convert.FormatQuantumArmor, Kind: convert.KindPrivKey, Algo: "kyber"}, info)
+
+	legacyArmor, err := convert.ToLegacyArmor(armor)
+	require.NoError(t, err)
+	blockType, header, _, err := crypto.DecodeArmor(legacyArmor)
+	require.NoError(t, err)
+	require.Equal(t, "TENDERMINT PRIVATE KEY", blockType)
+	require.Equal(t, map[string]string{"type": "kyber", "salt": "Q790BB721D1C094260EA84F5E5B72289", "kdf": "argon2id"}, header)
+}
+
+func TestDetectUnknown(t *testing.T) {
+	_, err := convert.Detect([]byte("not a key"))
+	require.ErrorIs(t, err, convert.ErrUnknownFormat)
+
+	_, err = convert.Detect([]byte(crypto.ArmorInfoBytes([]byte("info"))))
+	require.ErrorIs(t, err, convert.ErrUnknownFormat)
+
+	_, err = convert.ToQuantumArmor(strings.Repeat("a", 32))
+	require.ErrorIs(t, err, convert.ErrUnknownFormat)
+}
@@ -0,0 +1,378 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/cloudflare/circl/kem/kyber/kyber768"
+	"golang.org/x/crypto/hkdf"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/xsalsa20symmetric"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	blockTypeEncryptedMessage = "BARON CHAIN ENCRYPTED MESSAGE"
+	headerKEM                 = "kem"
+	headerRecipients          = "recipients"
+
+	// KEMSecp256k1ECIES is the key encapsulation used for secp256k1 recipients:
+	// an ephemeral ECDH exchange on the secp256k1 curve.
+	KEMSecp256k1ECIES = "secp256k1-ecies"
+	// KEMKyber768 is the key encapsulation used for kyber recipients.
+	KEMKyber768 = "kyber768"
+
+	keyTypeSecp256k1 = "secp256k1"
+	keyTypeKyber     = "kyber"
+
+	encryptedMessageKeySize = 32
+	encryptedMessageInfo    = "baron-chain/encrypted-message/v1/"
+	payloadKeyInfo          = "baron-chain/encrypted-message/v2/payload"
+)
+
+// EncryptToPubKey encrypts plaintext so that only the holder of the private key
+// matching pub can read it, and returns it as a "BARON CHAIN ENCRYPTED MESSAGE"
+// armored string. secp256k1 recipients use ECIES, kyber recipients use the
+// Kyber-768 KEM. The payload itself is sealed with xsalsa20-poly1305. As none
+// of the KEMs is FIPS approved, it returns ErrNotFIPSApproved in FIPS mode.
+func EncryptToPubKey(pub cryptotypes.PubKey, plaintext []byte) (string, error) {
+	kem, encapsulation, secret, err := encapsulate(pub)
+	if err != nil {
+		return "", err
+	}
+	if err := checkFIPSKEM(kem); err != nil {
+		return "", err
+	}
+
+	key, err := deriveMessageKey(kem, secret, encapsulation, pub.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]string{
+		headerVersion: version1,
+		headerType:    pub.Type(),
+		headerKEM:     kem,
+	}
+	body := append(encapsulation, xsalsa20symmetric.EncryptSymmetric(plaintext, key)...)
+
+	return EncodeArmor(blockTypeEncryptedMessage, header, body), nil
+}
+
+// EncryptToPubKeys encrypts plaintext once for several recipients, e.g. the
+// operators of a validator, so that the holder of any of the private keys
+// matching pubs can read it. Like age, the payload is sealed with a random file
+// key, which is wrapped for each recipient in a stanza of the armored message,
+// using the KEM of its key type. Recipients may have different key types.
+func EncryptToPubKeys(pubs []cryptotypes.PubKey, plaintext []byte) (string, error) {
+	if len(pubs) == 0 {
+		return "", fmt.Errorf("no recipient")
+	}
+
+	fileKey := make([]byte, encryptedMessageKeySize)
+	if _, err := rand.Read(fileKey); err != nil {
+		return "", err
+	}
+
+	var stanzas bytes.Buffer
+	seen := make(map[string]bool, len(pubs))
+	for _, pub := range pubs {
+		if seen[pub.Type()+string(pub.Bytes())] {
+			return "", fmt.Errorf("duplicate recipient %X", pub.Bytes())
+		}
+		seen[pub.Type()+string(pub.Bytes())] = true
+
+		kem, encapsulation, secret, err := encapsulate(pub)
+		if err != nil {
+			return "", err
+		}
+		if err := checkFIPSKEM(kem); err != nil {
+			return "", err
+		}
+
+		wrapKey, err := deriveMessageKey(kem, secret, encapsulation, pub.Bytes())
+		if err != nil {
+			return "", err
+		}
+		writeStanza(&stanzas, recipientStanza{
+			keyType:       pub.Type(),
+			kem:           kem,
+			encapsulation: encapsulation,
+			wrappedKey:    xsalsa20symmetric.EncryptSymmetric(fileKey, wrapKey),
+		})
+	}
+
+	payloadKey, err := derivePayloadKey(fileKey, stanzas.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]string{
+		headerVersion:    version2,
+		headerRecipients: strconv.Itoa(len(pubs)),
+	}
+	body := append(stanzas.Bytes(), xsalsa20symmetric.EncryptSymmetric(plaintext, payloadKey)...)
+
+	return EncodeArmor(blockTypeEncryptedMessage, header, body), nil
+}
+
+// DecryptWithPrivKey decrypts a message produced by EncryptToPubKey or
+// EncryptToPubKeys with the private key of a recipient.
+func DecryptWithPrivKey(priv cryptotypes.PrivKey, armorStr string) ([]byte, error) {
+	body, header, err := unarmorBytes(armorStr, blockTypeEncryptedMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	switch header[headerVersion] {
+	case version1:
+	case version2:
+		return decryptMultiRecipient(priv, header, body)
+	default:
+		return nil, fmt.Errorf("unrecognized version: %v", header[headerVersion])
+	}
+
+	if header[headerType] != priv.Type() {
+		return nil, fmt.Errorf("message was encrypted to a %s key, got a %s key", header[headerType], priv.Type())
+	}
+
+	kem := header[headerKEM]
+	if err := checkFIPSKEM(kem); err != nil {
+		return nil, err
+	}
+	encapsulation, secret, err := decapsulate(priv, kem, body)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := deriveMessageKey(kem, secret, encapsulation, priv.PubKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := xsalsa20symmetric.DecryptSymmetric(body[len(encapsulation):], key)
+	if err != nil {
+		return nil, sdkerrors.Wrap(err, "message is not addressed to this key or was tampered with")
+	}
+
+	return plaintext, nil
+}
+
+// encapsulate establishes a shared secret with the owner of pub and returns the
+// KEM used along with the encapsulation the recipient needs to recover it.
+func encapsulate(pub cryptotypes.PubKey) (kem string, encapsulation, secret []byte, err error) {
+	switch pub.Type() {
+	case keyTypeSecp256k1:
+		recipient, err := btcec.ParsePubKey(pub.Bytes())
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("invalid secp256k1 public key: %w", err)
+		}
+
+		ephemeral, err := btcec.NewPrivateKey()
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		secret = btcec.GenerateSharedSecret(ephemeral, recipient)
+		return KEMSecp256k1ECIES, ephemeral.PubKey().SerializeCompressed(), secret, nil
+
+	case keyTypeKyber:
+		scheme := kyber768.Scheme()
+		recipient, err := scheme.UnmarshalBinaryPublicKey(pub.Bytes())
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("invalid kyber public key: %w", err)
+		}
+
+		ct, ss, err := scheme.Encapsulate(recipient)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return KEMKyber768, ct, ss, nil
+
+	default:
+		return "", nil, nil, fmt.Errorf("encryption to %s keys is not supported", pub.Type())
+	}
+}
+
+// decapsulate recovers the shared secret from the head of an encrypted message
+// body and returns the encapsulation it was read from.
+func decapsulate(priv cryptotypes.PrivKey, kem string, body []byte) (encapsulation, secret []byte, err error) {
+	switch kem {
+	case KEMSecp256k1ECIES:
+		if len(body) < btcec.PubKeyBytesLenCompressed {
+			return nil, nil, fmt.Errorf("encrypted message is too short")
+		}
+
+		encapsulation = body[:btcec.PubKeyBytesLenCompressed]
+		ephemeral, err := btcec.ParsePubKey(encapsulation)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid ephemeral public key: %w", err)
+		}
+
+		recipient, _ := btcec.PrivKeyFromBytes(priv.Bytes())
+		return encapsulation, btcec.GenerateSharedSecret(recipient, ephemeral), nil
+
+	case KEMKyber768:
+		scheme := kyber768.Scheme()
+		if len(body) < scheme.CiphertextSize() {
+			return nil, nil, fmt.Errorf("encrypted message is too short")
+		}
+
+		recipient, err := scheme.UnmarshalBinaryPrivateKey(priv.Bytes())
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid kyber private key: %w", err)
+		}
+
+		encapsulation = body[:scheme.CiphertextSize()]
+		secret, err = scheme.Decapsulate(recipient, encapsulation)
+		if err != nil {
+			return nil, nil, err
+		}
+		return encapsulation, secret, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unrecognized KEM type: %v", kem)
+	}
+}
+
+// deriveMessageKey derives the symmetric message key from a KEM shared secret,
+// binding it to the encapsulation and the recipient public key.
+func deriveMessageKey(kem string, secret, encapsulation, recipient []byte) ([]byte, error) {
+	salt := make([]byte, 0, len(encapsulation)+len(recipient))
+	salt = append(salt, encapsulation...)
+	salt = append(salt, recipient...)
+
+	key := make([]byte, encryptedMessageKeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(encryptedMessageInfo+kem)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// recipientStanza wraps the file key of a multi-recipient message for one
+// recipient.
+type recipientStanza struct {
+	keyType       string
+	kem           string
+	encapsulation []byte
+	wrappedKey    []byte
+}
+
+// writeStanza appends a stanza to a message body, each field prefixed with its
+// big endian uint16 length.
+func writeStanza(w *bytes.Buffer, s recipientStanza) {
+	for _, field := range [][]byte{[]byte(s.keyType), []byte(s.kem), s.encapsulation, s.wrappedKey} {
+		_ = binary.Write(w, binary.BigEndian, uint16(len(field)))
+		w.Write(field)
+	}
+}
+
+// readStanzas reads n stanzas from the head of a message body and returns them
+// along with the rest of the body.
+func readStanzas(body []byte, n int) ([]recipientStanza, []byte, error) {
+	r := bytes.NewReader(body)
+	readField := func() ([]byte, error) {
+		var size uint16
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, fmt.Errorf("encrypted message is too short")
+		}
+		field := make([]byte, size)
+		if _, err := io.ReadFull(r, field); err != nil {
+			return nil, fmt.Errorf("encrypted message is too short")
+		}
+		return field, nil
+	}
+
+	stanzas := make([]recipientStanza, n)
+	for i := range stanzas {
+		fields := make([][]byte, 4)
+		for j := range fields {
+			field, err := readField()
+			if err != nil {
+				return nil, nil, err
+			}
+			fields[j] = field
+		}
+		stanzas[i] = recipientStanza{
+			keyType:       string(fields[0]),
+			kem:           string(fields[1]),
+			encapsulation: fields[2],
+			wrappedKey:    fields[3],
+		}
+	}
+	return stanzas, body[len(body)-r.Len():], nil
+}
+
+// decryptMultiRecipient decrypts a message produced by EncryptToPubKeys. As
+// stanzas don't identify their recipient, the key tries to unwrap the file key
+// of each stanza of its type.
+func decryptMultiRecipient(priv cryptotypes.PrivKey, header map[string]string, body []byte) ([]byte, error) {
+	n, err := strconv.Atoi(header[headerRecipients])
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("invalid recipients header: %q", header[headerRecipients])
+	}
+	// each stanza takes at least the length prefixes of its fields
+	if n > len(body)/8 {
+		return nil, fmt.Errorf("encrypted message is too short")
+	}
+
+	stanzas, ciphertext, err := readStanzas(body, n)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileKey []byte
+	for _, s := range stanzas {
+		if s.keyType != priv.Type() {
+			continue
+		}
+		if err := checkFIPSKEM(s.kem); err != nil {
+			return nil, err
+		}
+
+		encapsulation, secret, err := decapsulate(priv, s.kem, s.encapsulation)
+		if err != nil || len(encapsulation) != len(s.encapsulation) {
+			continue
+		}
+		wrapKey, err := deriveMessageKey(s.kem, secret, encapsulation, priv.PubKey().Bytes())
+		if err != nil {
+			return nil, err
+		}
+		if fileKey, err = xsalsa20symmetric.DecryptSymmetric(s.wrappedKey, wrapKey); err == nil {
+			break
+		}
+	}
+	if len(fileKey) != encryptedMessageKeySize {
+		return nil, fmt.Errorf("message is not addressed to this key")
+	}
+
+	payloadKey, err := derivePayloadKey(fileKey, body[:len(body)-len(ciphertext)])
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := xsalsa20symmetric.DecryptSymmetric(ciphertext, payloadKey)
+	if err != nil {
+		return nil, sdkerrors.Wrap(err, "message was tampered with")
+	}
+	return plaintext, nil
+}
+
+// derivePayloadKey derives the key sealing the payload of a multi-recipient
+// message from its file key, binding it to the stanzas, so that they can't be
+// altered, e.g. to add a recipient, without breaking the payload.
+func derivePayloadKey(fileKey, stanzas []byte) ([]byte, error) {
+	salt := sha256.Sum256(stanzas)
+
+	key := make([]byte, encryptedMessageKeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, fileKey, salt[:], []byte(payloadKeyInfo)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
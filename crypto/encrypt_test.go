@@ -0,0 +1,179 @@
+package crypto_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+func TestEncryptToPubKey(t *testing.T) {
+	priv := secp256k1.GenPrivKey()
+	plaintext := []byte("operator to operator")
+
+	armored, err := crypto.EncryptToPubKey(priv.PubKey(), plaintext)
+	require.NoError(t, err)
+	require.Contains(t, armored, "BARON CHAIN ENCRYPTED MESSAGE")
+
+	t.Run("recipient key", func(t *testing.T) {
+		decrypted, err := crypto.DecryptWithPrivKey(priv, armored)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		_, err := crypto.DecryptWithPrivKey(secp256k1.GenPrivKey(), armored)
+		require.Error(t, err)
+	})
+
+	t.Run("other key type", func(t *testing.T) {
+		_, err := crypto.DecryptWithPrivKey(ed25519.GenPrivKey(), armored)
+		require.Error(t, err)
+	})
+
+	t.Run("unsupported recipient", func(t *testing.T) {
+		_, err := crypto.EncryptToPubKey(ed25519.GenPrivKey().PubKey(), plaintext)
+		require.Error(t, err)
+	})
+
+	t.Run("fresh encapsulation per message", func(t *testing.T) {
+		other, err := crypto.EncryptToPubKey(priv.PubKey(), plaintext)
+		require.NoError(t, err)
+		require.NotEqual(t, armored, other)
+	})
+}
+
+func TestEncryptToPubKeys(t *testing.T) {
+	alice, bob := secp256k1.GenPrivKey(), secp256k1.GenPrivKey()
+	plaintext := []byte("validator ops shared secret")
+
+	armored, err := crypto.EncryptToPubKeys([]cryptotypes.PubKey{alice.PubKey(), bob.PubKey()}, plaintext)
+	require.NoError(t, err)
+	require.Contains(t, armored, "BARON CHAIN ENCRYPTED MESSAGE")
+
+	t.Run("each recipient", func(t *testing.T) {
+		for _, priv := range []cryptotypes.PrivKey{alice, bob} {
+			decrypted, err := crypto.DecryptWithPrivKey(priv, armored)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, decrypted)
+		}
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		_, err := crypto.DecryptWithPrivKey(secp256k1.GenPrivKey(), armored)
+		require.ErrorContains(t, err, "not addressed to this key")
+	})
+
+	t.Run("tampered stanzas", func(t *testing.T) {
+		blockType, header, body, err := crypto.DecodeArmor(armored)
+		require.NoError(t, err)
+		// the wrapped key of the second stanza ends right before the payload
+		tampered := append([]byte{}, body...)
+		tampered[len(tampered)-len(plaintext)-41] ^= 1
+		_, err = crypto.DecryptWithPrivKey(alice, crypto.EncodeArmor(blockType, header, tampered))
+		require.ErrorContains(t, err, "tampered")
+	})
+
+	t.Run("no recipient", func(t *testing.T) {
+		_, err := crypto.EncryptToPubKeys(nil, plaintext)
+		require.Error(t, err)
+	})
+
+	t.Run("duplicate recipient", func(t *testing.T) {
+		_, err := crypto.EncryptToPubKeys([]cryptotypes.PubKey{alice.PubKey(), alice.PubKey()}, plaintext)
+		require.ErrorContains(t, err, "duplicate recipient")
+	})
+
+	t.Run("unsupported recipient", func(t *testing.T) {
+		_, err := crypto.EncryptToPubKeys([]cryptotypes.PubKey{alice.PubKey(), ed25519.GenPrivKey().PubKey()}, plaintext)
+		require.Error(t, err)
+	})
+}
+
+func TestEncryptToKyberPubKey(t *testing.T) {
+	priv := genKyberPrivKey(t)
+	plaintext := []byte("quantum-safe operator to operator")
+
+	armored, err := crypto.EncryptToPubKey(priv.PubKey(), plaintext)
+	require.NoError(t, err)
+	_, header, _, err := crypto.DecodeArmor(armored)
+	require.NoError(t, err)
+	require.Equal(t, crypto.KEMKyber768, header["kem"])
+
+	t.Run("recipient key", func(t *testing.T) {
+		decrypted, err := crypto.DecryptWithPrivKey(priv, armored)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		_, err := crypto.DecryptWithPrivKey(genKyberPrivKey(t), armored)
+		require.Error(t, err)
+	})
+
+	t.Run("other key type", func(t *testing.T) {
+		_, err := crypto.DecryptWithPrivKey(secp256k1.GenPrivKey(), armored)
+		require.Error(t, err)
+	})
+
+	t.Run("mixed recipients", func(t *testing.T) {
+		secp := secp256k1.GenPrivKey()
+		armored, err := crypto.EncryptToPubKeys([]cryptotypes.PubKey{secp.PubKey(), priv.PubKey()}, plaintext)
+		require.NoError(t, err)
+		for _, recipient := range []cryptotypes.PrivKey{secp, priv} {
+			decrypted, err := crypto.DecryptWithPrivKey(recipient, armored)
+			require.NoError(t, err)
+			require.Equal(t, plaintext, decrypted)
+		}
+		_, err = crypto.DecryptWithPrivKey(genKyberPrivKey(t), armored)
+		require.ErrorContains(t, err, "not addressed to this key")
+	})
+}
+
+// genKyberPrivKey returns a new Kyber-768 key of the "kyber" type, whose
+// implementation the chain defines outside of this module.
+func genKyberPrivKey(t *testing.T) *kyberPrivKey {
+	seed := make([]byte, hd.PQCSeedSize)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	pub, priv, err := hd.KyberKeyFromSeed(seed)
+	require.NoError(t, err)
+	return &kyberPrivKey{key: priv, pub: &kyberPubKey{key: pub}}
+}
+
+type kyberPubKey struct{ key []byte }
+
+func (k *kyberPubKey) Reset()                         { *k = kyberPubKey{} }
+func (k *kyberPubKey) String() string                 { return fmt.Sprintf("kyber{%X}", k.key) }
+func (*kyberPubKey) ProtoMessage()                    {}
+func (k *kyberPubKey) Address() cryptotypes.Address   { return cryptotypes.Address(k.key[:20]) }
+func (k *kyberPubKey) Bytes() []byte                  { return k.key }
+func (*kyberPubKey) VerifySignature(_, _ []byte) bool { return false }
+func (k *kyberPubKey) Equals(other cryptotypes.PubKey) bool {
+	return other.Type() == k.Type() && bytes.Equal(other.Bytes(), k.key)
+}
+func (*kyberPubKey) Type() string { return "kyber" }
+
+type kyberPrivKey struct {
+	key []byte
+	pub *kyberPubKey
+}
+
+func (k *kyberPrivKey) Reset()                     { *k = kyberPrivKey{} }
+func (*kyberPrivKey) String() string               { return "kyber{...}" }
+func (*kyberPrivKey) ProtoMessage()                {}
+func (k *kyberPrivKey) Bytes() []byte              { return k.key }
+func (*kyberPrivKey) Sign([]byte) ([]byte, error)  { return nil, fmt.Errorf("kyber keys don't sign") }
+func (k *kyberPrivKey) PubKey() cryptotypes.PubKey { return k.pub }
+func (*kyberPrivKey) Type() string                 { return "kyber" }
+func (k *kyberPrivKey) Equals(other cryptotypes.LedgerPrivKey) bool {
+	return other.Type() == k.Type() && bytes.Equal(other.Bytes(), k.key)
+}
@@ -0,0 +1,175 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/cosmos/cosmos-sdk/crypto/xsalsa20symmetric"
+)
+
+const (
+	// CipherXSalsa20Poly1305 is the cipher of armors without a cipher header,
+	// used by default outside of FIPS mode.
+	CipherXSalsa20Poly1305 = "xsalsa20-poly1305"
+	// CipherAES256GCM is the cipher used in FIPS mode.
+	CipherAES256GCM = "aes-256-gcm"
+
+	headerCipher = "cipher"
+
+	gcmNonceSize = 12
+)
+
+// ErrNotFIPSApproved is returned in FIPS mode when a cipher, KDF or KEM which
+// is not FIPS approved is used, e.g. to decrypt a key armored with
+// xsalsa20-poly1305 and bcrypt. It wraps ErrPolicyViolation.
+var ErrNotFIPSApproved = fmt.Errorf("%w: not FIPS approved", ErrPolicyViolation)
+
+// fipsMinKDFParams are the minimum parameters of the KDFs allowed in FIPS
+// mode: PBKDF2-HMAC-SHA256 with the iterations recommended by OWASP, and
+// argon2id with the second recommended option of RFC 9106.
+var fipsMinKDFParams = map[string]map[string]uint64{
+	KDFPBKDF2:   {"iter": 600_000},
+	KDFArgon2id: {"t": 3, "m": 64 * 1024},
+}
+
+var fipsMode atomic.Bool
+
+func init() {
+	fipsMode.Store(fipsOnly)
+}
+
+// FIPSMode reports whether FIPS mode is enabled. In FIPS mode keys are armored
+// with AES-256-GCM and PBKDF2 or argon2id, and armors using xsalsa20-poly1305,
+// bcrypt, scrypt or KDF parameters below fipsMinKDFParams are rejected with
+// ErrNotFIPSApproved. Messages can't be encrypted to keys, as none of the KEMs
+// is FIPS approved.
+func FIPSMode() bool {
+	return fipsMode.Load()
+}
+
+// SetFIPSMode enables or disables FIPS mode at runtime. It returns an error
+// when disabling it in binaries built with the fipsonly build tag.
+func SetFIPSMode(enabled bool) error {
+	if fipsOnly && !enabled {
+		return errors.New("FIPS mode can't be disabled in binaries built with the fipsonly tag")
+	}
+	fipsMode.Store(enabled)
+	return nil
+}
+
+// checkFIPSKDF returns an ErrNotFIPSApproved error in FIPS mode if the KDF is
+// not approved, or its parameters are below the approved ones.
+func checkFIPSKDF(kdf KDF) error {
+	if !FIPSMode() {
+		return nil
+	}
+
+	name := kdf.Name()
+	minParams, ok := fipsMinKDFParams[name]
+	if !ok {
+		return fmt.Errorf("%w: KDF %q, use %s or %s", ErrNotFIPSApproved, name, KDFPBKDF2, KDFArgon2id)
+	}
+
+	params, err := parseKDFParamValues(kdf.Params())
+	if err != nil {
+		return fmt.Errorf("%w: %s KDF parameters can't be checked: %v", ErrNotFIPSApproved, name, err)
+	}
+	for key, min := range minParams {
+		if v := params[key]; v < min {
+			return fmt.Errorf("%w: %s KDF parameter %s=%d is below the minimum %d", ErrNotFIPSApproved, name, key, v, min)
+		}
+	}
+	return nil
+}
+
+// checkFIPSCipher returns an ErrNotFIPSApproved error in FIPS mode if the
+// cipher is not approved.
+func checkFIPSCipher(cipherName string) error {
+	if FIPSMode() && cipherName != CipherAES256GCM {
+		return fmt.Errorf("%w: cipher %s, the armor must be re-encrypted with %s", ErrNotFIPSApproved, cipherName, CipherAES256GCM)
+	}
+	return nil
+}
+
+// checkFIPSKEM returns an ErrNotFIPSApproved error in FIPS mode.
+func checkFIPSKEM(kem string) error {
+	if FIPSMode() {
+		return fmt.Errorf("%w: KEM %s", ErrNotFIPSApproved, kem)
+	}
+	return nil
+}
+
+// EncryptSymmetric seals plaintext with a 32 bytes key, using AES-256-GCM in
+// FIPS mode and xsalsa20-poly1305 otherwise. It returns the cipher used, to
+// be written to the cipher header of the armor by setCipherHeader.
+func EncryptSymmetric(plaintext, key []byte) (cipherName string, ciphertext []byte, err error) {
+	if !FIPSMode() {
+		return CipherXSalsa20Poly1305, xsalsa20symmetric.EncryptSymmetric(plaintext, key), nil
+	}
+
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, gcmNonceSize, gcmNonceSize+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", nil, err
+	}
+	return CipherAES256GCM, aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// DecryptSymmetric opens a ciphertext sealed by EncryptSymmetric with the
+// given cipher. An empty cipher is xsalsa20-poly1305, for armors without a
+// cipher header. In FIPS mode only AES-256-GCM is accepted.
+func DecryptSymmetric(cipherName string, ciphertext, key []byte) ([]byte, error) {
+	if cipherName == "" {
+		cipherName = CipherXSalsa20Poly1305
+	}
+	if err := checkFIPSCipher(cipherName); err != nil {
+		return nil, err
+	}
+
+	switch cipherName {
+	case CipherXSalsa20Poly1305:
+		return xsalsa20symmetric.DecryptSymmetric(ciphertext, key)
+	case CipherAES256GCM:
+		aead, err := newAESGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(ciphertext) <= gcmNonceSize+aead.Overhead() {
+			return nil, errors.New("ciphertext is too short")
+		}
+		plaintext, err := aead.Open(nil, ciphertext[:gcmNonceSize], ciphertext[gcmNonceSize:], nil)
+		if err != nil {
+			return nil, errors.New("ciphertext decryption failed")
+		}
+		return plaintext, nil
+	default:
+		return nil, fmt.Errorf("unrecognized cipher: %s", cipherName)
+	}
+}
+
+// setCipherHeader writes the cipher to the armor header. xsalsa20-poly1305 is
+// left implicit, so that armors stay readable by older versions.
+func setCipherHeader(header map[string]string, cipherName string) {
+	if cipherName != CipherXSalsa20Poly1305 {
+		header[headerCipher] = cipherName
+	}
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KDFKeySize {
+		return nil, fmt.Errorf("AES-256 key must be %d bytes long, got %d", KDFKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,8 @@
+//go:build !fipsonly
+// +build !fipsonly
+
+package crypto
+
+// fipsOnly is set by the fipsonly build tag. Without it, FIPS mode is
+// disabled by default and can be enabled at runtime with SetFIPSMode.
+const fipsOnly = false
@@ -0,0 +1,8 @@
+//go:build fipsonly
+// +build fipsonly
+
+package crypto
+
+// fipsOnly is set by the fipsonly build tag, which enables FIPS mode at init
+// and doesn't let it be disabled.
+const fipsOnly = true
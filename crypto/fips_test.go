@@ -0,0 +1,111 @@
+package crypto_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+// setTestFIPSMode sets FIPS mode for the duration of the test. Tests which
+// disable it are skipped in fipsonly builds.
+func setTestFIPSMode(t *testing.T, enabled bool) {
+	t.Helper()
+
+	prev := crypto.FIPSMode()
+	if err := crypto.SetFIPSMode(enabled); err != nil {
+		t.Skip(err)
+	}
+	t.Cleanup(func() { require.NoError(t, crypto.SetFIPSMode(prev)) })
+}
+
+func TestPBKDF2KDF(t *testing.T) {
+	_, err := crypto.NewPBKDF2KDF(999)
+	require.Error(t, err)
+
+	kdf, err := crypto.GetKDF(crypto.KDFPBKDF2, "")
+	require.NoError(t, err)
+	require.Equal(t, "iter=600000", kdf.Params())
+
+	kdf, err = crypto.NewPBKDF2KDF(1000)
+	require.NoError(t, err)
+	key, err := kdf.DeriveKey([]byte(testPassphrase), []byte("salt"))
+	require.NoError(t, err)
+	require.Len(t, key, crypto.KDFKeySize)
+}
+
+func TestFIPSModeArmor(t *testing.T) {
+	setTestFIPSMode(t, false)
+	priv := secp256k1.GenPrivKey()
+
+	legacyArmor, err := crypto.EncryptArmorPrivKey(priv, testPassphrase, "")
+	require.NoError(t, err)
+	argon2id, err := crypto.GetKDF(crypto.KDFArgon2id, "")
+	require.NoError(t, err)
+	xsalsaArmor, err := crypto.EncryptArmorPrivKeyWithKDF(priv, testPassphrase, "", argon2id)
+	require.NoError(t, err)
+
+	require.NoError(t, crypto.SetFIPSMode(true))
+	require.True(t, crypto.FIPSMode())
+
+	// bcrypt and xsalsa20-poly1305 armors are rejected
+	_, _, err = crypto.UnarmorDecryptPrivKey(legacyArmor, testPassphrase)
+	require.ErrorIs(t, err, crypto.ErrNotFIPSApproved)
+	require.ErrorIs(t, err, crypto.ErrPolicyViolation)
+	require.ErrorContains(t, err, `KDF "bcrypt"`)
+	_, _, err = crypto.UnarmorDecryptPrivKey(xsalsaArmor, testPassphrase)
+	require.ErrorIs(t, err, crypto.ErrNotFIPSApproved)
+	require.ErrorContains(t, err, "cipher xsalsa20-poly1305")
+
+	weak, err := crypto.NewPBKDF2KDF(1000)
+	require.NoError(t, err)
+	_, err = crypto.EncryptArmorPrivKeyWithKDF(priv, testPassphrase, "", weak)
+	require.ErrorIs(t, err, crypto.ErrNotFIPSApproved)
+
+	// keys are armored with AES-256-GCM and PBKDF2
+	armored, err := crypto.EncryptArmorPrivKey(priv, testPassphrase, "")
+	require.NoError(t, err)
+	_, header, _, err := crypto.DecodeArmor(armored)
+	require.NoError(t, err)
+	require.Equal(t, crypto.CipherAES256GCM, header["cipher"])
+	require.Equal(t, crypto.KDFPBKDF2, header["kdf"])
+
+	decrypted, _, err := crypto.UnarmorDecryptPrivKey(armored, testPassphrase)
+	require.NoError(t, err)
+	require.True(t, priv.Equals(decrypted))
+	_, _, err = crypto.UnarmorDecryptPrivKey(armored, "wrong")
+	require.Error(t, err)
+
+	// which can still be decrypted outside of FIPS mode
+	require.NoError(t, crypto.SetFIPSMode(false))
+	decrypted, _, err = crypto.UnarmorDecryptPrivKey(armored, testPassphrase)
+	require.NoError(t, err)
+	require.True(t, priv.Equals(decrypted))
+}
+
+func TestFIPSModeTimelockKeyShareAndMessages(t *testing.T) {
+	setTestFIPSMode(t, true)
+	priv := secp256k1.GenPrivKey()
+
+	local, _, err := crypto.SplitPrivKey(priv)
+	require.NoError(t, err)
+	armored, err := crypto.EncryptArmorKeyShare(local, testPassphrase)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(armored, "cipher: aes-256-gcm"))
+	share, err := crypto.UnarmorDecryptKeyShare(armored, testPassphrase)
+	require.NoError(t, err)
+	require.Equal(t, local, share)
+
+	timelocked, err := crypto.EncryptArmorPrivKeyTimelock(priv, testPassphrase, "", crypto.TimelockParams{Squarings: 100})
+	require.NoError(t, err)
+	decrypted, _, err := crypto.UnarmorDecryptPrivKeyTimelock(context.Background(), timelocked, testPassphrase)
+	require.NoError(t, err)
+	require.True(t, priv.Equals(decrypted))
+
+	_, err = crypto.EncryptToPubKey(priv.PubKey(), []byte("hello"))
+	require.ErrorIs(t, err, crypto.ErrNotFIPSApproved)
+}
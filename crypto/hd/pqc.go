@@ -0,0 +1,149 @@
+package hd
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cloudflare/circl/kem/kyber/kyber768"
+	"github.com/cloudflare/circl/sign/dilithium/mode3"
+	"github.com/cosmos/go-bip39"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// KyberType represents the Kyber-768 post-quantum key encapsulation
+	// mechanism. Its keys can't sign, they are only used for encryption.
+	KyberType = PubKeyType("kyber")
+	// DilithiumType represents the Dilithium3 post-quantum signature system.
+	DilithiumType = PubKeyType("dilithium")
+
+	// PQCSeedSize is the size of the seed derived for a post-quantum key pair.
+	PQCSeedSize = 32
+
+	pqcMasterKeyPrefix  = "Baron PQC seed/"
+	pqcExpandInfoPrefix = "baron-chain/pqc/"
+)
+
+// PQCAlgo derives post-quantum key pairs from BIP39 mnemonics. Derive returns a
+// PQCSeedSize seed from which the key package expands the key pair, see
+// KyberKeyFromSeed and DilithiumKeyFromSeed.
+type PQCAlgo struct {
+	keyType  PubKeyType
+	generate GenerateFn
+}
+
+// NewPQCAlgo returns the derivation of post-quantum keys of the given key type,
+// signing keys for DilithiumType and encryption keys for KyberType.
+// generate builds the private key from the seed returned by Derive.
+func NewPQCAlgo(keyType PubKeyType, generate GenerateFn) PQCAlgo {
+	return PQCAlgo{keyType: keyType, generate: generate}
+}
+
+// Name returns the key type derived by the algorithm.
+func (a PQCAlgo) Name() PubKeyType {
+	return a.keyType
+}
+
+// Derive derives and returns the key pair seed for the given mnemonic and HD path.
+func (a PQCAlgo) Derive() DeriveFn {
+	return func(mnemonic string, bip39Passphrase, hdPath string) ([]byte, error) {
+		seed, err := bip39.NewSeedWithErrorChecking(mnemonic, bip39Passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		return DerivePQCSeed(a.keyType, seed, hdPath)
+	}
+}
+
+// Generate generates a private key from the given key pair seed.
+func (a PQCAlgo) Generate() GenerateFn {
+	return a.generate
+}
+
+// DerivePQCSeed derives a PQCSeedSize key pair seed for keyType by following hdPath
+// from the BIP39 seed. The derivation mirrors BIP32 private derivation with HMAC-SHA512,
+// but the master key is domain-separated by key type and, since post-quantum keys have
+// no public derivation, every path element is derived as if it were hardened.
+func DerivePQCSeed(keyType PubKeyType, seed []byte, hdPath string) ([]byte, error) {
+	secret, chainCode := i64([]byte(pqcMasterKeyPrefix+string(keyType)), seed)
+
+	hdPath = strings.TrimRightFunc(hdPath, func(r rune) bool { return r == filepath.Separator })
+	if hdPath != "" {
+		parts := strings.Split(hdPath, "/")
+		switch {
+		case parts[0] == hdPath:
+			return nil, fmt.Errorf("path '%s' doesn't contain '/' separators", hdPath)
+		case strings.TrimSpace(parts[0]) == "m":
+			parts = parts[1:]
+		}
+
+		for i, part := range parts {
+			if part == "" {
+				return nil, fmt.Errorf("path %q with split element #%d is an empty string", hdPath, i)
+			}
+
+			idx, err := strconv.ParseUint(strings.TrimSuffix(part, "'"), 10, 31)
+			if err != nil {
+				return nil, fmt.Errorf("invalid BIP 32 path %s: %w", hdPath, err)
+			}
+
+			data := append([]byte{byte(0)}, secret[:]...)
+			data = append(data, uint32ToBytes(uint32(idx)|0x80000000)...)
+			secret, chainCode = i64(chainCode[:], data)
+		}
+	}
+
+	derived := make([]byte, PQCSeedSize)
+	copy(derived, secret[:])
+	return derived, nil
+}
+
+// KyberKeyFromSeed deterministically expands a key pair seed into a packed
+// Kyber-768 public and private key.
+func KyberKeyFromSeed(seed []byte) (pub, priv []byte, err error) {
+	scheme := kyber768.Scheme()
+	expanded, err := expandPQCSeed(KyberType, seed, scheme.SeedSize())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pk, sk := scheme.DeriveKeyPair(expanded)
+	if pub, err = pk.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	if priv, err = sk.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	return pub, priv, nil
+}
+
+// DilithiumKeyFromSeed deterministically expands a key pair seed into a packed
+// Dilithium3 public and private key.
+func DilithiumKeyFromSeed(seed []byte) (pub, priv []byte, err error) {
+	expanded, err := expandPQCSeed(DilithiumType, seed, mode3.SeedSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keySeed [mode3.SeedSize]byte
+	copy(keySeed[:], expanded)
+	pk, sk := mode3.NewKeyFromSeed(&keySeed)
+	return pk.Bytes(), sk.Bytes(), nil
+}
+
+func expandPQCSeed(keyType PubKeyType, seed []byte, size int) ([]byte, error) {
+	if len(seed) != PQCSeedSize {
+		return nil, fmt.Errorf("expected a seed of length %d, got length: %d", PQCSeedSize, len(seed))
+	}
+
+	expanded := make([]byte, size)
+	if _, err := io.ReadFull(hkdf.New(sha512.New, seed, nil, []byte(pqcExpandInfoPrefix+string(keyType))), expanded); err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
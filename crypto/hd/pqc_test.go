@@ -0,0 +1,75 @@
+package hd_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cosmos/go-bip39"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+)
+
+const pqcTestMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestDerivePQCSeedVectors(t *testing.T) {
+	seed := bip39.NewSeed(pqcTestMnemonic, "")
+
+	testCases := []struct {
+		keyType    hd.PubKeyType
+		path       string
+		seed       string
+		pubKeyHash string
+	}{
+		{hd.KyberType, "", "a70227a829bdbc15128568f059ec92515fe0eeb8fdc127e597794628553b83d2", "dd3e131c00bc5dbfee306ce0449b558d66846789c171d19758280a97f088fc59"},
+		{hd.KyberType, "m/44'/118'/0'/0/0", "2de65151449ec0fdec7a697baa837317d2222932cc26757894a2c7ed6067a22e", "2604ef85ad7a773314643f53addde9f9c7e78e153347e0a66be9f524e8421117"},
+		{hd.KyberType, "m/44'/118'/0'/0/1", "b8c9901bf329d635416d105c3c8a3f1bafa11bdb38345c85b70bea5a7889d679", "33f4d96d334caf82677983988aee7a9b202867dfc5349e63cd5d994182cf99ab"},
+		{hd.DilithiumType, "", "a583fee197ea1731e914081899bf308c5fe5205172180d3f3c055daab75593c4", "ac75c59bd7384543c356c4ad9d8e69c3813d45f556776281a50f7105f911b3e6"},
+		{hd.DilithiumType, "m/44'/118'/0'/0/0", "e374257f83d0d283e28bf521ed1796fc5643ea9de0006dde175c285b860b632a", "32c9ce1fd41ea911aed20b6adc0396e92d2601c3844a6e701b9a5f366bb72baf"},
+		{hd.DilithiumType, "m/44'/118'/0'/0/1", "5f25fbeee5c4f44c5be7dbe425cda0e16a62d5c00bbf6af7124bf40668864014", "270da3de91360ebb30fc37015c6c218c4c16d24c50369b958e209fc58e549a61"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(string(tc.keyType)+tc.path, func(t *testing.T) {
+			derived, err := hd.DerivePQCSeed(tc.keyType, seed, tc.path)
+			require.NoError(t, err)
+			require.Equal(t, tc.seed, hex.EncodeToString(derived))
+
+			var pub []byte
+			if tc.keyType == hd.KyberType {
+				pub, _, err = hd.KyberKeyFromSeed(derived)
+			} else {
+				pub, _, err = hd.DilithiumKeyFromSeed(derived)
+			}
+			require.NoError(t, err)
+
+			hash := sha256.Sum256(pub)
+			require.Equal(t, tc.pubKeyHash, hex.EncodeToString(hash[:]))
+		})
+	}
+}
+
+func TestPQCAlgoDerive(t *testing.T) {
+	algo := hd.NewPQCAlgo(hd.DilithiumType, nil)
+	require.Equal(t, hd.DilithiumType, algo.Name())
+
+	path := hd.CreateHDPath(118, 0, 0).String()
+	derived, err := algo.Derive()(pqcTestMnemonic, "", path)
+	require.NoError(t, err)
+	require.Equal(t, "e374257f83d0d283e28bf521ed1796fc5643ea9de0006dde175c285b860b632a", hex.EncodeToString(derived))
+
+	withPassphrase, err := algo.Derive()(pqcTestMnemonic, "passphrase", path)
+	require.NoError(t, err)
+	require.NotEqual(t, derived, withPassphrase)
+
+	_, err = algo.Derive()("invalid mnemonic", "", path)
+	require.Error(t, err)
+
+	_, err = hd.DerivePQCSeed(hd.DilithiumType, bip39.NewSeed(pqcTestMnemonic, ""), "m/44'/x'/0'/0/0")
+	require.Error(t, err)
+
+	_, _, err = hd.DilithiumKeyFromSeed([]byte("short"))
+	require.Error(t, err)
+}
@@ -0,0 +1,372 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/bcrypt"
+)
+
+const (
+	// KDFBcrypt is the name of the bcrypt KDF, used by default to encrypt
+	// armored private keys.
+	KDFBcrypt = "bcrypt"
+	// KDFArgon2id is the name of the argon2id KDF.
+	KDFArgon2id = "argon2id"
+	// KDFScrypt is the name of the scrypt KDF.
+	KDFScrypt = "scrypt"
+	// KDFPBKDF2 is the name of the PBKDF2 KDF, with HMAC-SHA-256.
+	KDFPBKDF2 = "pbkdf2"
+
+	// KDFKeySize is the size of the keys derived by a KDF.
+	KDFKeySize = 32
+
+	// maxKDFParamsLength bounds the length of the KDF parameters read from an
+	// armor header.
+	maxKDFParamsLength = 256
+
+	bcryptMaxCost    = 20
+	argon2MaxTime    = 64
+	argon2MaxMemory  = 1024 * 1024 // 1 GiB, in KiB
+	argon2MaxThreads = 255
+	scryptMaxLogN    = 22
+	scryptMaxR       = 64
+	scryptMaxP       = 16
+	scryptMaxMemory  = 1 << 30 // 1 GiB, in bytes
+	pbkdf2MinIter    = 1000    // NIST SP 800-132
+	pbkdf2MaxIter    = 10_000_000
+)
+
+// KDF derives a symmetric key from a passphrase and a salt. Implementations
+// must run in a time which only depends on their parameters and the length of
+// their inputs, and never on the contents of the passphrase.
+type KDF interface {
+	// Name returns the name the KDF is registered with, which is written to
+	// the kdf armor header.
+	Name() string
+	// Params returns the KDF parameters, which are written to the kdf-params
+	// armor header and parsed by the KDF factory when decrypting.
+	Params() string
+	// DeriveKey derives a KDFKeySize bytes key.
+	DeriveKey(passphrase, salt []byte) ([]byte, error)
+}
+
+// KDFFactory returns a KDF configured with the parameters read from an armor
+// header. An empty string selects the KDF default parameters. Factories parse
+// untrusted input and must reject parameters making key derivation
+// unreasonably expensive.
+type KDFFactory func(params string) (KDF, error)
+
+var (
+	kdfsMtx sync.RWMutex
+	kdfs    = map[string]KDFFactory{
+		KDFBcrypt:   newBcryptKDF,
+		KDFArgon2id: newArgon2idKDF,
+		KDFScrypt:   newScryptKDF,
+		KDFPBKDF2:   newPBKDF2KDF,
+	}
+)
+
+// RegisterKDF registers a KDF factory under the given name, which allows
+// chains to plug in other KDFs, e.g. ones backed by hardware. It returns an
+// error if the name is already registered.
+func RegisterKDF(name string, factory KDFFactory) error {
+	if name == "" || factory == nil {
+		return fmt.Errorf("invalid KDF registration %q", name)
+	}
+
+	kdfsMtx.Lock()
+	defer kdfsMtx.Unlock()
+
+	if _, ok := kdfs[name]; ok {
+		return fmt.Errorf("KDF %q is already registered", name)
+	}
+	kdfs[name] = factory
+	return nil
+}
+
+// RegisteredKDFs returns the names of the registered KDFs, sorted.
+func RegisteredKDFs() []string {
+	kdfsMtx.RLock()
+	defer kdfsMtx.RUnlock()
+
+	names := make([]string, 0, len(kdfs))
+	for name := range kdfs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetKDF returns the KDF registered under the given name, configured with the
+// given parameters.
+func GetKDF(name, params string) (KDF, error) {
+	kdfsMtx.RLock()
+	factory, ok := kdfs[name]
+	kdfsMtx.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unrecognized KDF type: %v", name)
+	}
+
+	if len(params) > maxKDFParamsLength {
+		return nil, fmt.Errorf("%s KDF parameters too long", name)
+	}
+	return factory(params)
+}
+
+// VerifyKey derives a key from the passphrase and salt and reports whether it
+// is equal to the expected key. The comparison runs in constant time.
+func VerifyKey(kdf KDF, passphrase, salt, expected []byte) (bool, error) {
+	key, err := kdf.DeriveKey(passphrase, salt)
+	if err != nil {
+		return false, err
+	}
+	defer zero(key)
+
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
+
+// ParseKDFParams parses KDF parameters of the form "k1=v1,k2=v2" where every
+// value is an unsigned integer. Keys must be unique and listed in allowed.
+func ParseKDFParams(params string, allowed ...string) (map[string]uint64, error) {
+	parsed := make(map[string]uint64)
+	if params == "" {
+		return parsed, nil
+	}
+
+	for _, kv := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid KDF parameter %q", kv)
+		}
+		if !containsString(allowed, key) {
+			return nil, fmt.Errorf("unknown KDF parameter %q", key)
+		}
+		if _, ok := parsed[key]; ok {
+			return nil, fmt.Errorf("duplicate KDF parameter %q", key)
+		}
+
+		v, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid KDF parameter %q: %w", kv, err)
+		}
+		parsed[key] = v
+	}
+	return parsed, nil
+}
+
+// kdfParam returns the parameter value, or def if it is not set, and checks it
+// lies within [min, max].
+func kdfParam(params map[string]uint64, key string, def, min, max uint64) (uint64, error) {
+	v, ok := params[key]
+	if !ok {
+		return def, nil
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("KDF parameter %s=%d is outside allowed range [%d, %d]", key, v, min, max)
+	}
+	return v, nil
+}
+
+// bcryptKDF derives keys as the SHA-256 of the bcrypt hash of the passphrase.
+type bcryptKDF struct {
+	cost int
+}
+
+// NewBcryptKDF returns a bcrypt KDF with the given cost.
+func NewBcryptKDF(cost int) (KDF, error) {
+	return newBcryptKDF(fmt.Sprintf("cost=%d", cost))
+}
+
+func newBcryptKDF(params string) (KDF, error) {
+	parsed, err := ParseKDFParams(params, "cost")
+	if err != nil {
+		return nil, err
+	}
+
+	// keys armored before kdf-params was introduced don't have a cost, and
+	// were encrypted with BcryptSecurityParameter
+	cost, err := kdfParam(parsed, "cost", uint64(BcryptSecurityParameter), 0, bcryptMaxCost)
+	if err != nil {
+		return nil, err
+	}
+
+	// like bcrypt.GenerateFromPassword, fall back to the default cost when
+	// the cost is too low
+	if cost < uint64(bcrypt.MinCost) {
+		cost = uint64(bcrypt.DefaultCost)
+	}
+	return bcryptKDF{cost: int(cost)}, nil
+}
+
+func (k bcryptKDF) Name() string { return KDFBcrypt }
+
+func (k bcryptKDF) Params() string { return fmt.Sprintf("cost=%d", k.cost) }
+
+func (k bcryptKDF) DeriveKey(passphrase, salt []byte) ([]byte, error) {
+	hash, err := bcrypt.GenerateFromPassword(salt, passphrase, k.cost)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(hash)
+
+	key := sha256.Sum256(hash)
+	return key[:], nil
+}
+
+// argon2idKDF derives keys with argon2id, as specified by RFC 9106.
+type argon2idKDF struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// NewArgon2idKDF returns an argon2id KDF with the given number of passes,
+// memory in KiB and degree of parallelism.
+func NewArgon2idKDF(time, memory uint32, threads uint8) (KDF, error) {
+	params := fmt.Sprintf("t=%d,m=%d,p=%d", time, memory, threads)
+	return newArgon2idKDF(params)
+}
+
+func newArgon2idKDF(params string) (KDF, error) {
+	parsed, err := ParseKDFParams(params, "t", "m", "p")
+	if err != nil {
+		return nil, err
+	}
+
+	// defaults to the second recommended option of RFC 9106
+	time, err := kdfParam(parsed, "t", 3, 1, argon2MaxTime)
+	if err != nil {
+		return nil, err
+	}
+	memory, err := kdfParam(parsed, "m", 64*1024, 8, argon2MaxMemory)
+	if err != nil {
+		return nil, err
+	}
+	threads, err := kdfParam(parsed, "p", 4, 1, argon2MaxThreads)
+	if err != nil {
+		return nil, err
+	}
+	if memory < 8*threads {
+		return nil, fmt.Errorf("argon2id memory must be at least 8 KiB per thread")
+	}
+
+	return argon2idKDF{time: uint32(time), memory: uint32(memory), threads: uint8(threads)}, nil
+}
+
+func (k argon2idKDF) Name() string { return KDFArgon2id }
+
+func (k argon2idKDF) Params() string {
+	return fmt.Sprintf("t=%d,m=%d,p=%d", k.time, k.memory, k.threads)
+}
+
+func (k argon2idKDF) DeriveKey(passphrase, salt []byte) ([]byte, error) {
+	return argon2.IDKey(passphrase, salt, k.time, k.memory, k.threads, KDFKeySize), nil
+}
+
+// scryptKDF derives keys with scrypt, as specified by RFC 7914.
+type scryptKDF struct {
+	logN uint8
+	r    int
+	p    int
+}
+
+// NewScryptKDF returns a scrypt KDF with a CPU/memory cost of 2^logN, block
+// size r and parallelization p.
+func NewScryptKDF(logN uint8, r, p int) (KDF, error) {
+	params := fmt.Sprintf("ln=%d,r=%d,p=%d", logN, r, p)
+	return newScryptKDF(params)
+}
+
+func newScryptKDF(params string) (KDF, error) {
+	parsed, err := ParseKDFParams(params, "ln", "r", "p")
+	if err != nil {
+		return nil, err
+	}
+
+	logN, err := kdfParam(parsed, "ln", 15, 1, scryptMaxLogN)
+	if err != nil {
+		return nil, err
+	}
+	r, err := kdfParam(parsed, "r", 8, 1, scryptMaxR)
+	if err != nil {
+		return nil, err
+	}
+	p, err := kdfParam(parsed, "p", 1, 1, scryptMaxP)
+	if err != nil {
+		return nil, err
+	}
+	if 128*r<<logN > scryptMaxMemory {
+		return nil, fmt.Errorf("scrypt parameters ln=%d,r=%d require more than %d bytes of memory", logN, r, scryptMaxMemory)
+	}
+
+	return scryptKDF{logN: uint8(logN), r: int(r), p: int(p)}, nil
+}
+
+func (k scryptKDF) Name() string { return KDFScrypt }
+
+func (k scryptKDF) Params() string {
+	return fmt.Sprintf("ln=%d,r=%d,p=%d", k.logN, k.r, k.p)
+}
+
+func (k scryptKDF) DeriveKey(passphrase, salt []byte) ([]byte, error) {
+	return scrypt.Key(passphrase, salt, 1<<k.logN, k.r, k.p, KDFKeySize)
+}
+
+// pbkdf2KDF derives keys with PBKDF2 and HMAC-SHA-256, as specified by
+// RFC 8018 and approved by NIST SP 800-132.
+type pbkdf2KDF struct {
+	iter int
+}
+
+// NewPBKDF2KDF returns a PBKDF2 KDF with the given number of iterations.
+func NewPBKDF2KDF(iter int) (KDF, error) {
+	return newPBKDF2KDF(fmt.Sprintf("iter=%d", iter))
+}
+
+func newPBKDF2KDF(params string) (KDF, error) {
+	parsed, err := ParseKDFParams(params, "iter")
+	if err != nil {
+		return nil, err
+	}
+
+	// defaults to the OWASP recommendation for PBKDF2-HMAC-SHA256
+	iter, err := kdfParam(parsed, "iter", 600_000, pbkdf2MinIter, pbkdf2MaxIter)
+	if err != nil {
+		return nil, err
+	}
+	return pbkdf2KDF{iter: int(iter)}, nil
+}
+
+func (k pbkdf2KDF) Name() string { return KDFPBKDF2 }
+
+func (k pbkdf2KDF) Params() string { return fmt.Sprintf("iter=%d", k.iter) }
+
+func (k pbkdf2KDF) DeriveKey(passphrase, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(passphrase, salt, k.iter, KDFKeySize, sha256.New), nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// zero overwrites b, to limit the lifetime of secrets in memory.
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
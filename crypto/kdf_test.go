@@ -0,0 +1,225 @@
+package crypto_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+var testSalt = []byte("0123456789abcdef")
+
+// cheapKDFs returns the built-in KDFs with parameters cheap enough for tests.
+func cheapKDFs(t testing.TB) []crypto.KDF {
+	bcryptKDF, err := crypto.NewBcryptKDF(4)
+	require.NoError(t, err)
+	argon2idKDF, err := crypto.NewArgon2idKDF(1, 64, 1)
+	require.NoError(t, err)
+	scryptKDF, err := crypto.NewScryptKDF(4, 8, 1)
+	require.NoError(t, err)
+	return []crypto.KDF{bcryptKDF, argon2idKDF, scryptKDF}
+}
+
+func TestKDFs(t *testing.T) {
+	for _, kdf := range cheapKDFs(t) {
+		kdf := kdf
+		t.Run(kdf.Name(), func(t *testing.T) {
+			key, err := kdf.DeriveKey([]byte(testPassphrase), testSalt)
+			require.NoError(t, err)
+			require.Len(t, key, crypto.KDFKeySize)
+
+			again, err := kdf.DeriveKey([]byte(testPassphrase), testSalt)
+			require.NoError(t, err)
+			require.Equal(t, key, again)
+
+			other, err := kdf.DeriveKey([]byte("other"), testSalt)
+			require.NoError(t, err)
+			require.NotEqual(t, key, other)
+
+			ok, err := crypto.VerifyKey(kdf, []byte(testPassphrase), testSalt, key)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			ok, err = crypto.VerifyKey(kdf, []byte("other"), testSalt, key)
+			require.NoError(t, err)
+			require.False(t, ok)
+
+			// the KDF can be rebuilt from its name and parameters
+			parsed, err := crypto.GetKDF(kdf.Name(), kdf.Params())
+			require.NoError(t, err)
+			require.Equal(t, kdf, parsed)
+		})
+	}
+}
+
+func TestArmorPrivKeyWithKDF(t *testing.T) {
+	priv := secp256k1.GenPrivKey()
+	for _, kdf := range cheapKDFs(t) {
+		armored, err := crypto.EncryptArmorPrivKeyWithKDF(priv, testPassphrase, testKeyType, kdf)
+		require.NoError(t, err)
+
+		_, header, _, err := crypto.DecodeArmor(armored)
+		require.NoError(t, err)
+		require.Equal(t, kdf.Name(), header["kdf"])
+		require.Equal(t, kdf.Params(), header["kdf-params"])
+
+		decrypted, algo, err := crypto.UnarmorDecryptPrivKey(armored, testPassphrase)
+		require.NoError(t, err)
+		require.Equal(t, testKeyType, algo)
+		require.True(t, priv.Equals(decrypted))
+
+		_, _, err = crypto.UnarmorDecryptPrivKey(armored, "wrongpassphrase")
+		require.Error(t, err)
+	}
+}
+
+type testKDF struct{ params string }
+
+func (k testKDF) Name() string   { return "test-kdf" }
+func (k testKDF) Params() string { return k.params }
+
+func (k testKDF) DeriveKey(passphrase, salt []byte) ([]byte, error) {
+	key := make([]byte, crypto.KDFKeySize)
+	copy(key, append(append([]byte{}, passphrase...), salt...))
+	return key, nil
+}
+
+func TestRegisterKDF(t *testing.T) {
+	_, err := crypto.GetKDF("test-kdf", "")
+	require.EqualError(t, err, "unrecognized KDF type: test-kdf")
+
+	require.NoError(t, crypto.RegisterKDF("test-kdf", func(params string) (crypto.KDF, error) {
+		return testKDF{params: params}, nil
+	}))
+	require.Contains(t, crypto.RegisteredKDFs(), "test-kdf")
+
+	require.Error(t, crypto.RegisterKDF("test-kdf", func(string) (crypto.KDF, error) { return nil, nil }))
+	require.Error(t, crypto.RegisterKDF(crypto.KDFBcrypt, func(string) (crypto.KDF, error) { return nil, nil }))
+	require.Error(t, crypto.RegisterKDF("", func(string) (crypto.KDF, error) { return nil, nil }))
+
+	priv := secp256k1.GenPrivKey()
+	armored, err := crypto.EncryptArmorPrivKeyWithKDF(priv, testPassphrase, "", testKDF{params: "device=1"})
+	require.NoError(t, err)
+	decrypted, _, err := crypto.UnarmorDecryptPrivKey(armored, testPassphrase)
+	require.NoError(t, err)
+	require.True(t, priv.Equals(decrypted))
+}
+
+func TestGetKDFParams(t *testing.T) {
+	testCases := []struct {
+		name   string
+		kdf    string
+		params string
+		expErr bool
+	}{
+		{"bcrypt defaults", crypto.KDFBcrypt, "", false},
+		{"bcrypt cost", crypto.KDFBcrypt, "cost=10", false},
+		{"bcrypt cost too high", crypto.KDFBcrypt, "cost=31", true},
+		{"bcrypt unknown param", crypto.KDFBcrypt, "rounds=10", true},
+		{"argon2id defaults", crypto.KDFArgon2id, "", false},
+		{"argon2id params", crypto.KDFArgon2id, "t=1,m=65536,p=4", false},
+		{"argon2id no time", crypto.KDFArgon2id, "t=0", true},
+		{"argon2id too much memory", crypto.KDFArgon2id, "m=4294967295", true},
+		{"argon2id too little memory per thread", crypto.KDFArgon2id, "m=16,p=4", true},
+		{"scrypt defaults", crypto.KDFScrypt, "", false},
+		{"scrypt params", crypto.KDFScrypt, "ln=15,r=8,p=1", false},
+		{"scrypt too much memory", crypto.KDFScrypt, "ln=22,r=64", true},
+		{"duplicate param", crypto.KDFScrypt, "ln=15,ln=16", true},
+		{"negative param", crypto.KDFScrypt, "ln=-1", true},
+		{"missing value", crypto.KDFScrypt, "ln", true},
+		{"trailing separator", crypto.KDFScrypt, "ln=15,", true},
+		{"too long", crypto.KDFScrypt, fmt.Sprintf("ln=%0300d", 15), true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := crypto.GetKDF(tc.kdf, tc.params)
+			if tc.expErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseKDFParams(t *testing.T) {
+	params, err := crypto.ParseKDFParams("", "a")
+	require.NoError(t, err)
+	require.Empty(t, params)
+
+	params, err = crypto.ParseKDFParams("a=1,b=4294967295", "a", "b")
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"a": 1, "b": 4294967295}, params)
+
+	_, err = crypto.ParseKDFParams("a=4294967296", "a")
+	require.Error(t, err)
+	_, err = crypto.ParseKDFParams("=1", "a")
+	require.Error(t, err)
+	_, err = crypto.ParseKDFParams("c=1", "a")
+	require.Error(t, err)
+}
+
+func FuzzParseKDFParams(f *testing.F) {
+	f.Add("")
+	f.Add("t=1,m=65536,p=4")
+	f.Add("ln=15,r=8,p=1")
+	f.Add("cost=12")
+	f.Add("t=1,t=2")
+	f.Add("=,=")
+
+	f.Fuzz(func(t *testing.T, params string) {
+		parsed, err := crypto.ParseKDFParams(params, "t", "m", "p", "ln", "r", "cost")
+		if err != nil {
+			return
+		}
+		for key := range parsed {
+			require.Contains(t, params, key+"=")
+		}
+	})
+}
+
+func FuzzGetKDF(f *testing.F) {
+	f.Add(crypto.KDFBcrypt, "cost=12")
+	f.Add(crypto.KDFArgon2id, "t=1,m=65536,p=4")
+	f.Add(crypto.KDFScrypt, "ln=15,r=8,p=1")
+	f.Add("unknown", "")
+
+	f.Fuzz(func(t *testing.T, name, params string) {
+		kdf, err := crypto.GetKDF(name, params)
+		if err != nil {
+			return
+		}
+
+		// accepted parameters round trip, without deriving a key whose cost
+		// is controlled by the fuzzer
+		again, err := crypto.GetKDF(kdf.Name(), kdf.Params())
+		require.NoError(t, err)
+		require.Equal(t, kdf, again)
+	})
+}
+
+func BenchmarkKDFDeriveKey(b *testing.B) {
+	bcryptKDF, err := crypto.GetKDF(crypto.KDFBcrypt, "")
+	require.NoError(b, err)
+	argon2idKDF, err := crypto.GetKDF(crypto.KDFArgon2id, "")
+	require.NoError(b, err)
+	scryptKDF, err := crypto.GetKDF(crypto.KDFScrypt, "")
+	require.NoError(b, err)
+
+	passphrase := []byte(testPassphrase)
+	for _, kdf := range []crypto.KDF{bcryptKDF, argon2idKDF, scryptKDF} {
+		kdf := kdf
+		b.Run(fmt.Sprintf("%s-%s", kdf.Name(), kdf.Params()), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := kdf.DeriveKey(passphrase, testSalt)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package keyring
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/99designs/keyring"
+)
+
+// CommandBackendConfigFileName is the name of the file, within the keyring
+// directory, the command backend reads its configuration from when it is not
+// given with the WithCommandBackend option.
+const CommandBackendConfigFileName = "keyring-command.json"
+
+// CommandBackendConfig configures the command keyring backend. Each command is
+// an argv slice whose elements are Go templates, rendered with:
+//
+//	{{.Service}}	the keyring service name, i.e. the application name
+//	{{.Key}}	the keyring item key
+//
+// Get must print the secret on stdout, Set receives it on stdin, and List must
+// print the keys of the service, one per line. Secrets are base64 encoded so
+// that they can be stored as text by any password manager. The commands are run
+// directly, not through a shell.
+type CommandBackendConfig struct {
+	Get    []string `json:"get"`
+	Set    []string `json:"set"`
+	Remove []string `json:"remove"`
+	List   []string `json:"list"`
+
+	// NotFoundExitCode is the exit code with which Get and Remove report a
+	// missing item. A successful Get with an empty output is also treated as a
+	// missing item. Zero disables the exit code check.
+	NotFoundExitCode int `json:"not_found_exit_code"`
+}
+
+// Validate checks that every command is set and its templates parse.
+func (c CommandBackendConfig) Validate() error {
+	for name, argv := range map[string][]string{"get": c.Get, "set": c.Set, "remove": c.Remove, "list": c.List} {
+		if len(argv) == 0 {
+			return fmt.Errorf("command keyring backend: %s command is not configured", name)
+		}
+		for _, arg := range argv {
+			if _, err := template.New(name).Option("missingkey=error").Parse(arg); err != nil {
+				return fmt.Errorf("command keyring backend: invalid %s command template %q: %w", name, arg, err)
+			}
+		}
+	}
+	return nil
+}
+
+// WithCommandBackend sets the configuration of the command keyring backend.
+func WithCommandBackend(cfg CommandBackendConfig) Option {
+	return func(options *Options) {
+		options.CommandBackend = &cfg
+	}
+}
+
+// commandKeyring is a keyring.Keyring which maps items onto the commands of
+// a secret store CLI, e.g. the 1Password or secret-tool CLIs.
+type commandKeyring struct {
+	service string
+	config  CommandBackendConfig
+}
+
+var _ keyring.Keyring = commandKeyring{}
+
+// newCommandKeyring returns a command backed keyring, configured by the
+// WithCommandBackend option or else by the CommandBackendConfigFileName file
+// of the keyring directory.
+func newCommandKeyring(appName, dir string, opts ...Option) (keyring.Keyring, error) {
+	var options Options
+	for _, optionFn := range opts {
+		optionFn(&options)
+	}
+
+	var cfg CommandBackendConfig
+	if options.CommandBackend != nil {
+		cfg = *options.CommandBackend
+	} else {
+		path := filepath.Join(dir, CommandBackendConfigFileName)
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("command keyring backend: failed to read configuration: %w", err)
+		}
+		if err := json.Unmarshal(bz, &cfg); err != nil {
+			return nil, fmt.Errorf("command keyring backend: invalid configuration %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return commandKeyring{service: appName, config: cfg}, nil
+}
+
+func (k commandKeyring) Get(key string) (keyring.Item, error) {
+	out, err := k.run(k.config.Get, key, nil)
+	if err != nil {
+		return keyring.Item{}, k.notFoundOr(err)
+	}
+
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return keyring.Item{}, keyring.ErrKeyNotFound
+	}
+
+	data, err := base64.StdEncoding.DecodeString(string(out))
+	if err != nil {
+		return keyring.Item{}, fmt.Errorf("command keyring backend: invalid secret for %s: %w", key, err)
+	}
+
+	return keyring.Item{Key: key, Data: data}, nil
+}
+
+func (k commandKeyring) GetMetadata(_ string) (keyring.Metadata, error) {
+	return keyring.Metadata{}, keyring.ErrMetadataNotSupported
+}
+
+func (k commandKeyring) Set(item keyring.Item) error {
+	secret := base64.StdEncoding.EncodeToString(item.Data)
+	_, err := k.run(k.config.Set, item.Key, strings.NewReader(secret))
+	return err
+}
+
+func (k commandKeyring) Remove(key string) error {
+	_, err := k.run(k.config.Remove, key, nil)
+	return k.notFoundOr(err)
+}
+
+func (k commandKeyring) Keys() ([]string, error) {
+	out, err := k.run(k.config.List, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if key := strings.TrimSpace(scanner.Text()); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, scanner.Err()
+}
+
+// commandError is returned when a backend command fails.
+type commandError struct {
+	name     string
+	exitCode int
+	stderr   string
+}
+
+func (e *commandError) Error() string {
+	return fmt.Sprintf("command keyring backend: %s exited with code %d: %s", e.name, e.exitCode, e.stderr)
+}
+
+// notFoundOr maps a command failure with the not found exit code to
+// keyring.ErrKeyNotFound.
+func (k commandKeyring) notFoundOr(err error) error {
+	var cmdErr *commandError
+	if k.config.NotFoundExitCode != 0 && errors.As(err, &cmdErr) && cmdErr.exitCode == k.config.NotFoundExitCode {
+		return keyring.ErrKeyNotFound
+	}
+	return err
+}
+
+// run renders the argv templates for key and runs the command, returning its
+// standard output.
+func (k commandKeyring) run(argvTemplate []string, key string, stdin *strings.Reader) ([]byte, error) {
+	data := struct{ Service, Key string }{Service: k.service, Key: key}
+
+	argv := make([]string, len(argvTemplate))
+	for i, arg := range argvTemplate {
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("command keyring backend: failed to render %q: %w", arg, err)
+		}
+		argv[i] = buf.String()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(argv[0], argv[1:]...) //nolint:gosec // the command is configured by the keyring owner
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, &commandError{name: argv[0], exitCode: exitErr.ExitCode(), stderr: strings.TrimSpace(stderr.String())}
+		}
+		return nil, fmt.Errorf("command keyring backend: failed to run %s: %w", argv[0], err)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,114 @@
+package keyring
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/99designs/keyring"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// fileStoreCommands returns a command backend storing each secret in a file
+// of dir, the way a password manager CLI would.
+func fileStoreCommands(dir string) CommandBackendConfig {
+	return CommandBackendConfig{
+		Get:              []string{"sh", "-c", `test -f "$1" || exit 3; cat "$1"`, "-", filepath.Join(dir, "{{.Service}}.{{.Key}}")},
+		Set:              []string{"sh", "-c", `cat > "$1"`, "-", filepath.Join(dir, "{{.Service}}.{{.Key}}")},
+		Remove:           []string{"sh", "-c", `test -f "$1" || exit 3; rm "$1"`, "-", filepath.Join(dir, "{{.Service}}.{{.Key}}")},
+		List:             []string{"sh", "-c", `cd "$1" && for f in {{.Service}}.*; do [ -f "$f" ] && echo "${f#{{.Service}}.}"; done; true`, "-", dir},
+		NotFoundExitCode: 3,
+	}
+}
+
+func TestCommandBackend(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is required to run the command backend test")
+	}
+
+	storeDir := t.TempDir()
+	kr, err := New("keybasename", BackendCommand, t.TempDir(), nil, getCodec(), WithCommandBackend(fileStoreCommands(storeDir)))
+	require.NoError(t, err)
+	require.Equal(t, BackendCommand, kr.Backend())
+
+	_, err = kr.Key("foo")
+	require.Error(t, err)
+	require.ErrorIs(t, err, sdkerrors.ErrKeyNotFound)
+
+	_, _, err = kr.NewMnemonic("foo", English, sdk.FullFundraiserPath, DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+
+	record, err := kr.Key("foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo", record.Name)
+
+	records, err := kr.List()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	addr, err := record.GetAddress()
+	require.NoError(t, err)
+	byAddr, err := kr.KeyByAddress(addr)
+	require.NoError(t, err)
+	require.Equal(t, record.Name, byAddr.Name)
+
+	require.NoError(t, kr.Delete("foo"))
+	records, err = kr.List()
+	require.NoError(t, err)
+	require.Empty(t, records)
+}
+
+func TestCommandBackendConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := New("keybasename", BackendCommand, dir, nil, getCodec())
+	require.Error(t, err, "configuration file is missing")
+
+	bz, err := json.Marshal(fileStoreCommands(t.TempDir()))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CommandBackendConfigFileName), bz, 0o600))
+
+	kr, err := New("keybasename", BackendCommand, dir, nil, getCodec())
+	require.NoError(t, err)
+	require.Equal(t, BackendCommand, kr.Backend())
+
+	invalid := fileStoreCommands(dir)
+	invalid.List = nil
+	_, err = New("keybasename", BackendCommand, dir, nil, getCodec(), WithCommandBackend(invalid))
+	require.Error(t, err)
+}
+
+func TestCommandKeyringNotFound(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is required to run the command backend test")
+	}
+
+	cfg := fileStoreCommands(t.TempDir())
+	kr := commandKeyring{service: "svc", config: cfg}
+
+	_, err := kr.Get("missing")
+	require.ErrorIs(t, err, keyring.ErrKeyNotFound)
+	require.ErrorIs(t, kr.Remove("missing"), keyring.ErrKeyNotFound)
+
+	require.NoError(t, kr.Set(keyring.Item{Key: "k", Data: []byte{0, 1, 2}}))
+	item, err := kr.Get("k")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 1, 2}, item.Data)
+
+	keys, err := kr.Keys()
+	require.NoError(t, err)
+	require.Equal(t, []string{"k"}, keys)
+
+	// without a not found exit code, failures are reported as is
+	cfg.NotFoundExitCode = 0
+	kr = commandKeyring{service: "svc", config: cfg}
+	_, err = kr.Get("missing")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, keyring.ErrKeyNotFound)
+}
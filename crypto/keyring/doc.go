@@ -37,4 +37,13 @@
 //		be unlocked and it should be used only for testing purposes.
 //	memory	Same instance as returned by NewInMemory. This backend uses a transient storage. Keys
 //		are discarded when the process terminates or the type instance is garbage collected.
+//	command	This backend runs the commands of a secret store CLI to store and retrieve keys, so that
+//		password managers without a dedicated backend (e.g. 1Password, secret-tool) can be
+//		used. The commands are set with the WithCommandBackend option or in the
+//		keyring-command.json file of the keyring directory, see CommandBackendConfig.
+//	pkcs11	This backend uses the secp256k1 keys of a PKCS#11 token, e.g. a YubiHSM or SoftHSM token.
+//		Keys are read-only: they are generated with the token tools and private keys never
+//		leave the token, which signs. The token is set with the WithPKCS11Backend option or in
+//		the keyring-pkcs11.json file of the keyring directory, see PKCS11BackendConfig.
+//		Executables must be built with cgo and the pkcs11 build tag.
 package keyring
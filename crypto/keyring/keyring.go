@@ -17,6 +17,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/input"
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/convert"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/bcrypt"
 	"github.com/cosmos/cosmos-sdk/crypto/ledger"
@@ -34,6 +35,8 @@ const (
 	BackendPass    = "pass"
 	BackendTest    = "test"
 	BackendMemory  = "memory"
+	BackendCommand = "command"
+	BackendPKCS11  = "pkcs11"
 )
 
 const (
@@ -54,7 +57,7 @@ var (
 
 // Keyring exposes operations over a backend supported by github.com/99designs/keyring.
 type Keyring interface {
-	// Get the backend type used in the keyring config: "file", "os", "kwallet", "pass", "test", "memory".
+	// Get the backend type used in the keyring config: "file", "os", "kwallet", "pass", "test", "memory", "command", "pkcs11".
 	Backend() string
 	// List all keys.
 	List() ([]*Record, error)
@@ -156,6 +159,10 @@ type Options struct {
 	// indicate whether Ledger should skip DER Conversion on signature,
 	// depending on which format (DER or BER) the Ledger app returns signatures
 	LedgerSigSkipDERConv bool
+	// configuration of the command backend, read from the keyring directory if nil
+	CommandBackend *CommandBackendConfig
+	// configuration of the pkcs11 backend, read from the keyring directory if nil
+	PKCS11Backend *PKCS11BackendConfig
 }
 
 // NewInMemory creates a transient keyring useful for testing
@@ -171,9 +178,19 @@ func NewInMemoryWithKeyring(kr keyring.Keyring, cdc codec.Codec, opts ...Option)
 	return newKeystore(kr, cdc, BackendMemory, opts...)
 }
 
+// Close releases the resources held by a keyring, e.g. the session of the
+// pkcs11 backend on its token. The keyring must not be used afterwards. It is a
+// no-op for the backends holding no resources.
+func Close(kr Keyring) error {
+	if c, ok := kr.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 // New creates a new instance of a keyring.
 // Keyring options can be applied when generating the new instance.
-// Available backends are "os", "file", "kwallet", "memory", "pass", "test".
+// Available backends are "os", "file", "kwallet", "memory", "pass", "test", "command", "pkcs11".
 func New(
 	appName, backend, rootDir string, userInput io.Reader, cdc codec.Codec, opts ...Option,
 ) (Keyring, error) {
@@ -195,6 +212,10 @@ func New(
 		db, err = keyring.Open(newKWalletBackendKeyringConfig(appName, rootDir, userInput))
 	case BackendPass:
 		db, err = keyring.Open(newPassBackendKeyringConfig(appName, rootDir, userInput))
+	case BackendCommand:
+		db, err = newCommandKeyring(appName, rootDir, opts...)
+	case BackendPKCS11:
+		return newPKCS11Keyring(rootDir, userInput, cdc, opts...)
 	default:
 		return nil, fmt.Errorf("unknown keyring backend %v", backend)
 	}
@@ -289,7 +310,11 @@ func (ks keystore) ExportPrivKeyArmor(uid, encryptPassphrase string) (armor stri
 		return "", err
 	}
 
-	return crypto.EncryptArmorPrivKey(priv, encryptPassphrase, priv.Type()), nil
+	kdf, err := crypto.PolicyKDF()
+	if err != nil {
+		return "", err
+	}
+	return crypto.EncryptArmorPrivKeyWithKDF(priv, encryptPassphrase, priv.Type(), kdf)
 }
 
 // ExportPrivateKeyObject exports an armored private key object.
@@ -323,6 +348,8 @@ func (ks keystore) ImportPrivKey(uid, armor, passphrase string) error {
 		}
 	}
 
+	armor = legacyArmor(armor)
+
 	privKey, _, err := crypto.UnarmorDecryptPrivKey(armor, passphrase)
 	if err != nil {
 		return errors.Wrap(err, "failed to decrypt private key")
@@ -336,6 +363,15 @@ func (ks keystore) ImportPrivKey(uid, armor, passphrase string) error {
 	return nil
 }
 
+// legacyArmor converts quantum armors to legacy armors, so that both can be
+// imported, and returns other strings unchanged.
+func legacyArmor(armor string) string {
+	if converted, err := convert.ToLegacyArmor(armor); err == nil {
+		return converted
+	}
+	return armor
+}
+
 func (ks keystore) ImportPrivKeyHex(uid, privKey, algoStr string) error {
 	if _, err := ks.Key(uid); err == nil {
 		return fmt.Errorf("cannot overwrite key: %s", uid)
@@ -352,6 +388,9 @@ func (ks keystore) ImportPrivKeyHex(uid, privKey, algoStr string) error {
 		return err
 	}
 	priv := algo.Generate()(decodedPriv)
+	if err := crypto.GetPolicy().CheckAlgo(priv.Type()); err != nil {
+		return err
+	}
 	_, err = ks.writeLocalKey(uid, priv)
 	if err != nil {
 		return err
@@ -364,7 +403,7 @@ func (ks keystore) ImportPubKey(uid, armor string) error {
 		return fmt.Errorf("cannot overwrite key: %s", uid)
 	}
 
-	pubBytes, _, err := crypto.UnarmorPubKeyBytes(armor)
+	pubBytes, _, err := crypto.UnarmorPubKeyBytes(legacyArmor(armor))
 	if err != nil {
 		return err
 	}
@@ -373,6 +412,9 @@ func (ks keystore) ImportPubKey(uid, armor string) error {
 	if err := ks.cdc.UnmarshalInterface(pubBytes, &pubKey); err != nil {
 		return err
 	}
+	if err := crypto.GetPolicy().CheckAlgo(pubKey.Type()); err != nil {
+		return err
+	}
 
 	_, err = ks.writeOfflineKey(uid, pubKey)
 	if err != nil {
@@ -405,6 +447,14 @@ func (ks keystore) Sign(uid string, msg []byte) ([]byte, types.PubKey, error) {
 	case k.GetLedger() != nil:
 		return SignWithLedger(k, msg)
 
+	case k.GetShare() != nil:
+		pub, err := k.GetPubKey()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, pub, ErrKeyShareSign
+
 		// multi or offline record
 	default:
 		pub, err := k.GetPubKey()
@@ -17,6 +17,7 @@ import (
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	"github.com/cosmos/cosmos-sdk/crypto"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/convert"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
@@ -301,6 +302,37 @@ func TestExportImportKeyRing(t *testing.T) {
 	require.True(t, key.Equals(key2))
 }
 
+func TestImportQuantumArmors(t *testing.T) {
+	kb, err := New("keybasename", "test", t.TempDir(), nil, getCodec())
+	require.NoError(t, err)
+
+	k, _, err := kb.NewMnemonic("john", English, sdk.FullFundraiserPath, DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+	addr, err := k.GetAddress()
+	require.NoError(t, err)
+
+	armor, err := kb.ExportPrivKeyArmor("john", "apassphrase")
+	require.NoError(t, err)
+	privArmor, err := convert.ToQuantumArmor(armor)
+	require.NoError(t, err)
+	armor, err = kb.ExportPubKeyArmor("john")
+	require.NoError(t, err)
+	pubArmor, err := convert.ToQuantumArmor(armor)
+	require.NoError(t, err)
+	require.NoError(t, kb.Delete("john"))
+
+	require.NoError(t, kb.ImportPrivKey("john", privArmor, "apassphrase"))
+	require.NoError(t, kb.ImportPubKey("john-pub", pubArmor))
+
+	for _, name := range []string{"john", "john-pub"} {
+		k, err := kb.Key(name)
+		require.NoError(t, err)
+		imported, err := k.GetAddress()
+		require.NoError(t, err)
+		require.Equal(t, addr, imported)
+	}
+}
+
 func TestExportImportPubKeyKeyRing(t *testing.T) {
 	cdc := getCodec()
 	kb, err := New("keybasename", "test", t.TempDir(), nil, cdc)
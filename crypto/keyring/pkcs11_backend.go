@@ -0,0 +1,241 @@
+package keyring
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/99designs/keyring"
+	"github.com/pkg/errors"
+
+	"github.com/cosmos/cosmos-sdk/client/input"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/pkcs11"
+	"github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// PKCS11BackendConfigFileName is the name of the file, within the keyring
+// directory, the pkcs11 backend reads its configuration from when it is not
+// given with the WithPKCS11Backend option.
+const PKCS11BackendConfigFileName = "keyring-pkcs11.json"
+
+var (
+	// ErrPKCS11ReadOnly is raised when the caller tries to create, import,
+	// rename or delete keys of the pkcs11 backend, which are managed with the
+	// token tools.
+	ErrPKCS11ReadOnly = errors.New("pkcs11 keyring backend is read-only, keys are managed with the token tools")
+
+	// ErrPKCS11Export is raised when the caller tries to export a private key
+	// of the pkcs11 backend, which never leave the token.
+	ErrPKCS11Export = errors.New("private keys cannot be exported from PKCS#11 tokens")
+)
+
+// PKCS11BackendConfig configures the pkcs11 keyring backend.
+type PKCS11BackendConfig struct {
+	// Module is the path of the PKCS#11 module shared library, e.g.
+	// /usr/lib/softhsm/libsofthsm2.so or /usr/lib/yubihsm_pkcs11.so.
+	Module string `json:"module"`
+	// TokenLabel is the label of the token holding the keys.
+	TokenLabel string `json:"token_label"`
+	// PINEnv is the environment variable holding the token user PIN. The PIN
+	// is prompted for when it is empty.
+	PINEnv string `json:"pin_env"`
+}
+
+// WithPKCS11Backend sets the configuration of the pkcs11 keyring backend.
+func WithPKCS11Backend(cfg PKCS11BackendConfig) Option {
+	return func(options *Options) {
+		options.PKCS11Backend = &cfg
+	}
+}
+
+// SlotLister is implemented by keyrings backed by hardware tokens, which
+// report the slots of their module.
+type SlotLister interface {
+	Slots() ([]pkcs11.SlotInfo, error)
+}
+
+// pkcs11Keyring is a read-only keyring of the secp256k1 keys of a PKCS#11
+// token. Its records are offline records of the token public keys, and
+// signing is delegated to the token.
+type pkcs11Keyring struct {
+	keystore
+
+	token pkcs11.Token
+	keys  map[string]pkcs11.KeyInfo
+}
+
+var (
+	_ Keyring    = pkcs11Keyring{}
+	_ SlotLister = pkcs11Keyring{}
+	_ io.Closer  = pkcs11Keyring{}
+)
+
+// newPKCS11Keyring opens the token configured by the WithPKCS11Backend option
+// or else by the PKCS11BackendConfigFileName file of the keyring directory, and
+// loads its keys.
+func newPKCS11Keyring(dir string, userInput io.Reader, cdc codec.Codec, opts ...Option) (Keyring, error) {
+	var options Options
+	for _, optionFn := range opts {
+		optionFn(&options)
+	}
+
+	var cfg PKCS11BackendConfig
+	if options.PKCS11Backend != nil {
+		cfg = *options.PKCS11Backend
+	} else {
+		path := filepath.Join(dir, PKCS11BackendConfigFileName)
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("pkcs11 keyring backend: failed to read configuration: %w", err)
+		}
+		if err := json.Unmarshal(bz, &cfg); err != nil {
+			return nil, fmt.Errorf("pkcs11 keyring backend: invalid configuration %s: %w", path, err)
+		}
+	}
+
+	pin := os.Getenv(cfg.PINEnv)
+	if cfg.PINEnv != "" && pin == "" {
+		return nil, fmt.Errorf("pkcs11 keyring backend: PIN environment variable %s is not set", cfg.PINEnv)
+	}
+	if cfg.PINEnv == "" {
+		var err error
+		pin, err = input.GetSecret(fmt.Sprintf("Enter PIN of PKCS#11 token %s:", cfg.TokenLabel), bufio.NewReader(userInput))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := pkcs11.Open(pkcs11.Config{Module: cfg.Module, TokenLabel: cfg.TokenLabel, PIN: pin})
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11 keyring backend: %w", err)
+	}
+
+	tokenKeys, err := token.Keys()
+	if err != nil {
+		token.Close()
+		return nil, fmt.Errorf("pkcs11 keyring backend: %w", err)
+	}
+
+	ks := pkcs11Keyring{
+		keystore: newKeystore(keyring.NewArrayKeyring(nil), cdc, BackendPKCS11, opts...),
+		token:    token,
+		keys:     make(map[string]pkcs11.KeyInfo),
+	}
+	for _, key := range tokenKeys {
+		// other curves are not supported by the chain
+		if !key.IsSecp256k1() {
+			continue
+		}
+
+		name := key.Label
+		if name == "" {
+			name = hex.EncodeToString(key.ID)
+		}
+
+		pub, err := key.PubKey()
+		if err == nil {
+			_, err = ks.writeOfflineKey(name, pub)
+		}
+		if err != nil {
+			token.Close()
+			return nil, fmt.Errorf("pkcs11 keyring backend: failed to load key %s: %w", name, err)
+		}
+		ks.keys[name] = key
+	}
+
+	return ks, nil
+}
+
+// Close logs out and closes the session on the token.
+func (ks pkcs11Keyring) Close() error {
+	return ks.token.Close()
+}
+
+// Slots returns the slots of the token module.
+func (ks pkcs11Keyring) Slots() ([]pkcs11.SlotInfo, error) {
+	return ks.token.Slots()
+}
+
+func (ks pkcs11Keyring) Sign(uid string, msg []byte) ([]byte, types.PubKey, error) {
+	k, err := ks.Key(uid)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err := k.GetPubKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sig, err := pkcs11.Sign(ks.token, ks.keys[k.Name], msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sig, pub, nil
+}
+
+func (ks pkcs11Keyring) SignByAddress(address sdk.Address, msg []byte) ([]byte, types.PubKey, error) {
+	k, err := ks.KeyByAddress(address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ks.Sign(k.Name, msg)
+}
+
+func (ks pkcs11Keyring) NewMnemonic(string, Language, string, string, SignatureAlgo) (*Record, string, error) {
+	return nil, "", ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) NewAccount(string, string, string, string, SignatureAlgo) (*Record, error) {
+	return nil, ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) SaveLedgerKey(string, SignatureAlgo, string, uint32, uint32, uint32) (*Record, error) {
+	return nil, ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) SaveOfflineKey(string, types.PubKey) (*Record, error) {
+	return nil, ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) SaveMultisig(string, types.PubKey) (*Record, error) {
+	return nil, ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) Delete(string) error {
+	return ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) DeleteByAddress(sdk.Address) error {
+	return ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) Rename(string, string) error {
+	return ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) ImportPrivKey(string, string, string) error {
+	return ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) ImportPrivKeyHex(string, string, string) error {
+	return ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) ImportPubKey(string, string) error {
+	return ErrPKCS11ReadOnly
+}
+
+func (ks pkcs11Keyring) ExportPrivKeyArmor(string, string) (string, error) {
+	return "", ErrPKCS11Export
+}
+
+func (ks pkcs11Keyring) ExportPrivKeyArmorByAddress(sdk.Address, string) (string, error) {
+	return "", ErrPKCS11Export
+}
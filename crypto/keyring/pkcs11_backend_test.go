@@ -0,0 +1,123 @@
+package keyring
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/pkcs11"
+	"github.com/cosmos/cosmos-sdk/crypto/pkcs11/pkcs11test"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// mockPKCS11Token makes the pkcs11 package open a mock token with a validator
+// key, for the duration of the test.
+func mockPKCS11Token(t *testing.T) (*pkcs11test.MockToken, *pkcs11.Config) {
+	token := pkcs11test.NewMockToken("baron")
+	_, err := token.GenerateKey("validator")
+	require.NoError(t, err)
+
+	opened := &pkcs11.Config{}
+	pkcs11.SetOpenToken(func(cfg pkcs11.Config) (pkcs11.Token, error) {
+		*opened = cfg
+		return token, nil
+	})
+	t.Cleanup(func() { pkcs11.SetOpenToken(nil) })
+
+	return token, opened
+}
+
+func TestPKCS11Backend(t *testing.T) {
+	token, opened := mockPKCS11Token(t)
+	t.Setenv("BARON_PKCS11_PIN", "1234")
+
+	kr, err := New("keybasename", BackendPKCS11, t.TempDir(), nil, getCodec(), WithPKCS11Backend(PKCS11BackendConfig{
+		Module:     "/usr/lib/softhsm/libsofthsm2.so",
+		TokenLabel: "baron",
+		PINEnv:     "BARON_PKCS11_PIN",
+	}))
+	require.NoError(t, err)
+	require.Equal(t, BackendPKCS11, kr.Backend())
+	require.Equal(t, pkcs11.Config{Module: "/usr/lib/softhsm/libsofthsm2.so", TokenLabel: "baron", PIN: "1234"}, *opened)
+
+	slots, err := kr.(SlotLister).Slots()
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	require.Equal(t, "baron", slots[0].TokenLabel)
+
+	records, err := kr.List()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "validator", records[0].Name)
+
+	msg := []byte("sign bytes")
+	sig, pub, err := kr.Sign("validator", msg)
+	require.NoError(t, err)
+	require.True(t, pub.VerifySignature(msg, sig))
+
+	addr, err := records[0].GetAddress()
+	require.NoError(t, err)
+	sig, _, err = kr.SignByAddress(addr, msg)
+	require.NoError(t, err)
+	require.True(t, pub.VerifySignature(msg, sig))
+
+	_, _, err = kr.Sign("unknown", msg)
+	require.Error(t, err)
+
+	// keys are managed with the token tools and never leave the token
+	_, _, err = kr.NewMnemonic("new", English, sdk.FullFundraiserPath, DefaultBIP39Passphrase, hd.Secp256k1)
+	require.ErrorIs(t, err, ErrPKCS11ReadOnly)
+	require.ErrorIs(t, kr.Delete("validator"), ErrPKCS11ReadOnly)
+	require.ErrorIs(t, kr.Rename("validator", "other"), ErrPKCS11ReadOnly)
+	_, err = kr.ExportPrivKeyArmor("validator", "passphrase")
+	require.ErrorIs(t, err, ErrPKCS11Export)
+
+	armor, err := kr.ExportPubKeyArmor("validator")
+	require.NoError(t, err)
+	require.NotEmpty(t, armor)
+
+	// closing the keyring closes the session on the token
+	require.False(t, token.Closed())
+	require.NoError(t, Close(kr))
+	require.True(t, token.Closed())
+	_, _, err = kr.Sign("validator", msg)
+	require.Error(t, err)
+}
+
+func TestPKCS11BackendConfigFile(t *testing.T) {
+	_, opened := mockPKCS11Token(t)
+
+	dir := t.TempDir()
+	bz, err := json.Marshal(PKCS11BackendConfig{Module: "/usr/lib/yubihsm_pkcs11.so", TokenLabel: "baron"})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PKCS11BackendConfigFileName), bz, 0o600))
+
+	// the PIN is prompted for without pin_env
+	kr, err := New("keybasename", BackendPKCS11, dir, strings.NewReader("5678\n"), getCodec())
+	require.NoError(t, err)
+	require.Equal(t, "5678", opened.PIN)
+	require.Equal(t, "/usr/lib/yubihsm_pkcs11.so", opened.Module)
+
+	_, err = kr.Key("validator")
+	require.NoError(t, err)
+
+	_, err = New("keybasename", BackendPKCS11, t.TempDir(), nil, getCodec())
+	require.Error(t, err)
+
+	// the token is not opened without a PIN
+	*opened = pkcs11.Config{}
+	_, err = New("keybasename", BackendPKCS11, t.TempDir(), nil, getCodec(), WithPKCS11Backend(PKCS11BackendConfig{
+		Module:     "/usr/lib/softhsm/libsofthsm2.so",
+		TokenLabel: "baron",
+		PINEnv:     "BARON_PKCS11_UNSET_PIN",
+	}))
+	require.ErrorContains(t, err, "BARON_PKCS11_UNSET_PIN is not set")
+	_, err = New("keybasename", BackendPKCS11, dir, strings.NewReader("\n"), getCodec())
+	require.Error(t, err)
+	require.Empty(t, opened.Module)
+}
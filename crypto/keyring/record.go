@@ -4,7 +4,9 @@ import (
 	"errors"
 
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto"
 	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	"github.com/cosmos/cosmos-sdk/types"
 )
@@ -12,6 +14,9 @@ import (
 // ErrPrivKeyExtr is used to output an error if extraction of a private key from Local item fails
 var ErrPrivKeyExtr = errors.New("private key extraction works only for Local")
 
+// ErrKeyShareExtr is used to output an error if extraction of a key share from a Share item fails
+var ErrKeyShareExtr = errors.New("key share extraction works only for Share")
+
 func newRecord(name string, pk cryptotypes.PubKey, item isRecord_Item) (*Record, error) {
 	any, err := codectypes.NewAnyWithValue(pk)
 	if err != nil {
@@ -59,6 +64,26 @@ func NewMultiRecord(name string, pk cryptotypes.PubKey) (*Record, error) {
 	return newRecord(name, pk, recordMultiItem)
 }
 
+// NewShareRecord creates a new Record with a share item, storing a share of a
+// private key split among several keyrings. The public key of the record is
+// the one of the whole key.
+func NewShareRecord(name string, share crypto.KeyShare) (*Record, error) {
+	if err := share.Validate(); err != nil {
+		return nil, err
+	}
+	pk := &secp256k1.PubKey{Key: share.PubKey}
+
+	recordShare := &Record_Share{
+		Scheme:    share.Scheme,
+		Index:     share.Index,
+		Threshold: share.Threshold,
+		GroupId:   share.GroupID,
+		Share:     share.Share,
+	}
+	recordShareItem := &Record_Share_{recordShare}
+	return newRecord(name, pk, recordShareItem)
+}
+
 // GetPubKey fetches a public key of the record
 func (k *Record) GetPubKey() (cryptotypes.PubKey, error) {
 	pk, ok := k.PubKey.GetCachedValue().(cryptotypes.PubKey)
@@ -89,6 +114,8 @@ func (k Record) GetType() KeyType {
 		return TypeMulti
 	case k.GetOffline() != nil:
 		return TypeOffline
+	case k.GetShare() != nil:
+		return TypeShare
 	default:
 		panic("unrecognized record type")
 	}
@@ -109,6 +136,28 @@ func (k *Record) UnpackInterfaces(unpacker codectypes.AnyUnpacker) error {
 	return nil
 }
 
+// GetKeyShare returns the key share of a share record.
+func (k *Record) GetKeyShare() (crypto.KeyShare, error) {
+	rs := k.GetShare()
+	if rs == nil {
+		return crypto.KeyShare{}, ErrKeyShareExtr
+	}
+	pk, err := k.GetPubKey()
+	if err != nil {
+		return crypto.KeyShare{}, err
+	}
+
+	share := crypto.KeyShare{
+		Scheme:    rs.Scheme,
+		Index:     rs.Index,
+		Threshold: rs.Threshold,
+		GroupID:   rs.GroupId,
+		PubKey:    pk.Bytes(),
+		Share:     rs.Share,
+	}
+	return share, share.Validate()
+}
+
 func extractPrivKeyFromRecord(k *Record) (cryptotypes.PrivKey, error) {
 	rl := k.GetLocal()
 	if rl == nil {
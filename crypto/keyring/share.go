@@ -0,0 +1,65 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+)
+
+// ErrKeyShareSign is raised when the caller tries to sign with a share record:
+// the shares of the key must be combined into the key first.
+var ErrKeyShareSign = errors.New("cannot sign with a key share, combine the shares into the key first")
+
+// ShareKeyring is implemented by keyrings storing shares of private keys split
+// among several keyrings, e.g. by crypto.SharePrivKey, so that signing
+// requires combining the shares of a threshold of them into the key.
+type ShareKeyring interface {
+	// SaveKeyShare stores a key share under uid. Records being indexed by the
+	// address of the whole key, a keyring stores a single share of a key.
+	SaveKeyShare(uid string, share crypto.KeyShare) (*Record, error)
+
+	// ExportKeyShareArmor returns the key share stored under uid, encrypted
+	// with passphrase by crypto.EncryptArmorKeyShare, to combine with the other
+	// shares of the key by crypto.CombineKeyShares.
+	ExportKeyShareArmor(uid, passphrase string) (string, error)
+}
+
+var _ ShareKeyring = keystore{}
+
+func (ks keystore) SaveKeyShare(uid string, share crypto.KeyShare) (*Record, error) {
+	if _, err := ks.Key(uid); err == nil {
+		return nil, fmt.Errorf("cannot overwrite key: %s", uid)
+	}
+
+	k, err := NewShareRecord(uid, share)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := k.GetPubKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := crypto.GetPolicy().CheckAlgo(pubKey.Type()); err != nil {
+		return nil, err
+	}
+
+	return k, ks.writeRecord(k)
+}
+
+func (ks keystore) ExportKeyShareArmor(uid, passphrase string) (string, error) {
+	k, err := ks.Key(uid)
+	if err != nil {
+		return "", err
+	}
+	share, err := k.GetKeyShare()
+	if err != nil {
+		return "", err
+	}
+
+	return crypto.EncryptArmorKeyShare(share, passphrase)
+}
+
+func (ks pkcs11Keyring) SaveKeyShare(string, crypto.KeyShare) (*Record, error) {
+	return nil, ErrPKCS11ReadOnly
+}
@@ -0,0 +1,60 @@
+package keyring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+func TestShareRecord(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	shares, err := crypto.SharePrivKey(privKey, 2, 3)
+	require.NoError(t, err)
+
+	// each share is held by its own keyring
+	msg := []byte("sign bytes")
+	var exported []crypto.KeyShare
+	for _, share := range shares[1:] {
+		kr := NewInMemory(getCodec())
+		k, err := kr.(ShareKeyring).SaveKeyShare("validator", share)
+		require.NoError(t, err)
+		require.Equal(t, TypeShare, k.GetType())
+		addr, err := k.GetAddress()
+		require.NoError(t, err)
+		require.Equal(t, privKey.PubKey().Address().Bytes(), addr.Bytes())
+
+		// the record survives the codec round trip of the backend
+		k, err = kr.Key("validator")
+		require.NoError(t, err)
+		stored, err := k.GetKeyShare()
+		require.NoError(t, err)
+		require.Equal(t, share, stored)
+
+		_, _, err = kr.Sign("validator", msg)
+		require.ErrorIs(t, err, ErrKeyShareSign)
+		_, err = kr.ExportPrivKeyArmor("validator", "passphrase")
+		require.Error(t, err)
+		_, err = kr.(ShareKeyring).SaveKeyShare("validator", share)
+		require.ErrorContains(t, err, "cannot overwrite key")
+
+		armored, err := kr.(ShareKeyring).ExportKeyShareArmor("validator", "passphrase")
+		require.NoError(t, err)
+		share, err := crypto.UnarmorDecryptKeyShare(armored, "passphrase")
+		require.NoError(t, err)
+		exported = append(exported, share)
+	}
+
+	combined, err := crypto.CombineKeyShares(exported...)
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(combined))
+
+	kr := NewInMemory(getCodec())
+	_, _, err = kr.NewMnemonic("local", English, "m/44'/118'/0'/0/0", DefaultBIP39Passphrase, hd.Secp256k1)
+	require.NoError(t, err)
+	_, err = kr.(ShareKeyring).ExportKeyShareArmor("local", "passphrase")
+	require.ErrorIs(t, err, ErrKeyShareExtr)
+}
@@ -0,0 +1,130 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	blockTypePGPMessage = "PGP MESSAGE"
+	armorBegin          = "-----BEGIN "
+	// maxPGPPlaintextSize bounds the decrypted key material.
+	maxPGPPlaintextSize = 1 << 20
+)
+
+// ErrPGPKeyNotFound is returned when a PGP message is encrypted to keys of
+// which none is in the given PGP keyring.
+var ErrPGPKeyNotFound = errors.New("no PGP key to decrypt the message")
+
+// ReadPGPKeyRing reads a PGP keyring, ASCII-armored or binary, e.g. as
+// exported by gpg --export-secret-keys.
+func ReadPGPKeyRing(r io.Reader) (openpgp.EntityList, error) {
+	bz, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(bz), []byte(armorBegin)) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(bz))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(bz))
+}
+
+// DecryptPGPMessage decrypts an OpenPGP message, ASCII-armored or binary,
+// encrypted with a passphrase or to a key of keys, whose private key is then
+// decrypted with passphrase. Signatures of the message are not verified, but
+// its integrity is.
+func DecryptPGPMessage(message []byte, passphrase []byte, keys openpgp.EntityList) ([]byte, error) {
+	var r io.Reader = bytes.NewReader(message)
+	if bytes.HasPrefix(bytes.TrimSpace(message), []byte(armorBegin)) {
+		block, err := armor.Decode(bytes.NewReader(bytes.TrimSpace(message)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid PGP armor: %w", err)
+		}
+		if block.Type != blockTypePGPMessage {
+			return nil, fmt.Errorf("unrecognized armor type %q, expected: %q", block.Type, blockTypePGPMessage)
+		}
+		r = block.Body
+	}
+
+	// the prompt is called again after a wrong passphrase, which is reported
+	// instead of looping
+	prompted := false
+	prompt := func(pgpKeys []openpgp.Key, symmetric bool) ([]byte, error) {
+		if prompted {
+			return nil, sdkerrors.ErrWrongPassword
+		}
+		prompted = true
+		if symmetric {
+			return passphrase, nil
+		}
+		for _, k := range pgpKeys {
+			if k.PrivateKey == nil || !k.PrivateKey.Encrypted {
+				continue
+			}
+			if err := k.PrivateKey.Decrypt(passphrase); err != nil {
+				return nil, sdkerrors.ErrWrongPassword
+			}
+		}
+		return nil, nil
+	}
+
+	md, err := openpgp.ReadMessage(r, keys, prompt, nil)
+	switch {
+	case errors.Is(err, pgperrors.ErrKeyIncorrect):
+		return nil, ErrPGPKeyNotFound
+	case err != nil:
+		return nil, fmt.Errorf("failed to decrypt PGP message: %w", err)
+	case !md.IsEncrypted:
+		return nil, errors.New("PGP message is not encrypted")
+	}
+
+	plaintext, err := io.ReadAll(io.LimitReader(md.UnverifiedBody, maxPGPPlaintextSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt PGP message: %w", err)
+	}
+	if len(plaintext) > maxPGPPlaintextSize {
+		return nil, fmt.Errorf("PGP message exceeds the limit of %d bytes", maxPGPPlaintextSize)
+	}
+	return plaintext, nil
+}
+
+// ConvertPGPPrivKey decrypts a private key stored in an OpenPGP message with
+// pgpPassphrase, as by DecryptPGPMessage, and returns it as an armored
+// private key, as by EncryptArmorPrivKey. The message holds an armored
+// private key, returned as is, with its own passphrase, or a hex encoded
+// secp256k1 private key, which is encrypted with passphrase.
+func ConvertPGPPrivKey(message, pgpPassphrase []byte, keys openpgp.EntityList, passphrase string) (string, error) {
+	plaintext, err := DecryptPGPMessage(message, pgpPassphrase, keys)
+	if err != nil {
+		return "", err
+	}
+	defer zero(plaintext)
+
+	s := strings.TrimSpace(string(plaintext))
+	if strings.HasPrefix(s, armorBegin+blockTypePrivKey) {
+		return s, nil
+	}
+
+	bz, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(bz) != secp256k1.PrivKeySize {
+		return "", errors.New("PGP message holds neither an armored private key nor a hex encoded secp256k1 private key")
+	}
+	defer zero(bz)
+
+	privKey := &secp256k1.PrivKey{Key: append([]byte{}, bz...)}
+	if err := GetPolicy().CheckAlgo(privKey.Type()); err != nil {
+		return "", err
+	}
+	return EncryptArmorPrivKey(privKey, passphrase, defaultAlgo)
+}
@@ -0,0 +1,76 @@
+package crypto_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// pgpEncrypt encrypts plaintext with passphrase, or to the entity, as an
+// ASCII-armored message.
+func pgpEncrypt(t *testing.T, plaintext []byte, passphrase []byte, to *openpgp.Entity) []byte {
+	var buf bytes.Buffer
+	aw, err := armor.Encode(&buf, "PGP MESSAGE", nil)
+	require.NoError(t, err)
+
+	var w interface {
+		Write([]byte) (int, error)
+		Close() error
+	}
+	if to == nil {
+		w, err = openpgp.SymmetricallyEncrypt(aw, passphrase, nil, nil)
+	} else {
+		w, err = openpgp.Encrypt(aw, []*openpgp.Entity{to}, nil, nil, nil)
+	}
+	require.NoError(t, err)
+	_, err = w.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, aw.Close())
+	return buf.Bytes()
+}
+
+func TestConvertPGPPrivKey(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	hexKey := []byte(hex.EncodeToString(privKey.Bytes()) + "\n")
+
+	// symmetric encryption of a hex key, converted to an armor encrypted with
+	// the new passphrase
+	msg := pgpEncrypt(t, hexKey, []byte("vault"), nil)
+	armored, err := crypto.ConvertPGPPrivKey(msg, []byte("vault"), nil, "new")
+	require.NoError(t, err)
+	decrypted, algo, err := crypto.UnarmorDecryptPrivKey(armored, "new")
+	require.NoError(t, err)
+	require.Equal(t, "secp256k1", algo)
+	require.True(t, privKey.Equals(decrypted))
+
+	_, err = crypto.ConvertPGPPrivKey(msg, []byte("wrong"), nil, "new")
+	require.ErrorIs(t, err, sdkerrors.ErrWrongPassword)
+
+	// encryption to a key of an armored key, returned as is
+	entity, err := openpgp.NewEntity("ops", "", "ops@example.com", nil)
+	require.NoError(t, err)
+	armoredKey, err := crypto.EncryptArmorPrivKey(privKey, "own", "secp256k1")
+	require.NoError(t, err)
+	msg = pgpEncrypt(t, []byte(armoredKey), nil, entity)
+	armored, err = crypto.ConvertPGPPrivKey(msg, nil, openpgp.EntityList{entity}, "new")
+	require.NoError(t, err)
+	require.Equal(t, armoredKey, armored)
+
+	other, err := openpgp.NewEntity("other", "", "other@example.com", nil)
+	require.NoError(t, err)
+	_, err = crypto.ConvertPGPPrivKey(msg, nil, openpgp.EntityList{other}, "new")
+	require.ErrorIs(t, err, crypto.ErrPGPKeyNotFound)
+
+	msg = pgpEncrypt(t, []byte("not a key"), []byte("vault"), nil)
+	_, err = crypto.ConvertPGPPrivKey(msg, []byte("vault"), nil, "new")
+	require.ErrorContains(t, err, "neither an armored private key")
+}
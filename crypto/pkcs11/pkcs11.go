@@ -0,0 +1,162 @@
+// Package pkcs11 gives access to secp256k1 keys stored on PKCS#11 tokens, such
+// as YubiHSM or SoftHSM tokens. Private keys never leave the token: public keys
+// are read from the token public key objects and signatures are computed by
+// the token with the CKM_ECDSA mechanism.
+//
+// The binding to PKCS#11 modules requires cgo and the pkcs11 build tag.
+package pkcs11
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	btcec "github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+// secp256k1Params is the DER encoding of the secp256k1 curve OID
+// 1.3.132.0.10, as found in the CKA_EC_PARAMS attribute of secp256k1 keys.
+var secp256k1Params = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+
+type (
+	// openTokenFn opens a session on the token configured by cfg. It allows
+	// to avoid the cgo dependency when PKCS#11 support is not enabled.
+	openTokenFn func(cfg Config) (Token, error)
+
+	// Config selects a token of a PKCS#11 module.
+	Config struct {
+		// Module is the path of the PKCS#11 module shared library, e.g.
+		// /usr/lib/softhsm/libsofthsm2.so.
+		Module string
+		// TokenLabel is the label of the token holding the keys.
+		TokenLabel string
+		// PIN is the user PIN of the token, which the session is logged in
+		// with. Tokens require a login to use their private keys.
+		PIN string
+	}
+
+	// SlotInfo describes a slot of a PKCS#11 module and the token it holds.
+	SlotInfo struct {
+		ID           uint   `json:"id"`
+		Description  string `json:"description"`
+		Manufacturer string `json:"manufacturer"`
+		TokenPresent bool   `json:"token_present"`
+		TokenLabel   string `json:"token_label,omitempty"`
+		TokenModel   string `json:"token_model,omitempty"`
+		TokenSerial  string `json:"token_serial,omitempty"`
+	}
+
+	// KeyInfo describes an EC key pair of a token.
+	KeyInfo struct {
+		// Label is the CKA_LABEL of the key, used as the key name.
+		Label string
+		// ID is the CKA_ID shared by the public and private key objects.
+		ID []byte
+		// ECParams is the CKA_EC_PARAMS of the key, the DER encoded curve.
+		ECParams []byte
+		// ECPoint is the CKA_EC_POINT of the public key, an uncompressed EC
+		// point either raw or wrapped in a DER octet string.
+		ECPoint []byte
+	}
+
+	// Token is a session on a PKCS#11 token.
+	Token interface {
+		// Slots returns the slots of the module the token belongs to.
+		Slots() ([]SlotInfo, error)
+		// Keys returns the EC key pairs of the token.
+		Keys() ([]KeyInfo, error)
+		// SignDigest signs a digest with the CKM_ECDSA mechanism and the
+		// private key with the given CKA_ID, and returns the r || s signature.
+		SignDigest(id []byte, digest []byte) ([]byte, error)
+		Close() error
+	}
+)
+
+// openToken is set by the PKCS#11 binding, or reports PKCS#11 support is not
+// available when the binding is not built.
+var openToken openTokenFn
+
+// SetOpenToken sets the function opening tokens, e.g. to use a mock token in
+// tests or another PKCS#11 binding.
+func SetOpenToken(fn func(cfg Config) (Token, error)) {
+	openToken = fn
+}
+
+// Open opens a session on the token configured by cfg.
+func Open(cfg Config) (Token, error) {
+	if cfg.Module == "" {
+		return nil, errors.New("PKCS#11 module is not configured")
+	}
+	if cfg.TokenLabel == "" {
+		return nil, errors.New("PKCS#11 token label is not configured")
+	}
+	if cfg.PIN == "" {
+		return nil, errors.New("PKCS#11 token PIN is empty")
+	}
+	return openToken(cfg)
+}
+
+// IsSecp256k1 reports whether the key is a secp256k1 key.
+func (k KeyInfo) IsSecp256k1() bool {
+	return bytes.Equal(k.ECParams, secp256k1Params)
+}
+
+// PubKey returns the secp256k1 public key of the key pair.
+func (k KeyInfo) PubKey() (*secp256k1.PubKey, error) {
+	if !k.IsSecp256k1() {
+		return nil, fmt.Errorf("PKCS#11 key %s is not a secp256k1 key", k.Label)
+	}
+
+	point := k.ECPoint
+	var wrapped []byte
+	if rest, err := asn1.Unmarshal(point, &wrapped); err == nil && len(rest) == 0 {
+		point = wrapped
+	}
+
+	pub, err := btcec.ParsePubKey(point)
+	if err != nil {
+		return nil, fmt.Errorf("PKCS#11 key %s has an invalid EC point: %w", k.Label, err)
+	}
+	return &secp256k1.PubKey{Key: pub.SerializeCompressed()}, nil
+}
+
+// Sign signs msg with the key on the token and returns the signature in the
+// format verified by secp256k1.PubKey: the r || s signature of the SHA-256 of
+// msg, with s normalized to the lower half of the curve order.
+func Sign(token Token, key KeyInfo, msg []byte) ([]byte, error) {
+	pub, err := key.PubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(msg)
+	sig, err := token.SignDigest(key.ID, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("PKCS#11 token failed to sign with %s: %w", key.Label, err)
+	}
+	if len(sig) != 64 {
+		return nil, fmt.Errorf("PKCS#11 token returned a %d bytes signature, expected 64", len(sig))
+	}
+
+	// tokens don't produce canonical signatures, which are required to prevent
+	// signature malleability
+	order := btcec.S256().N
+	s := new(big.Int).SetBytes(sig[32:])
+	if s.Cmp(new(big.Int).Rsh(order, 1)) > 0 {
+		s.Sub(order, s)
+	}
+
+	out := make([]byte, 64)
+	copy(out[:32], sig[:32])
+	s.FillBytes(out[32:])
+
+	if !pub.VerifySignature(msg, out) {
+		return nil, fmt.Errorf("PKCS#11 token returned an invalid signature for %s", key.Label)
+	}
+	return out, nil
+}
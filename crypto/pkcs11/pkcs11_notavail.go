@@ -0,0 +1,16 @@
+//go:build !cgo || !pkcs11
+// +build !cgo !pkcs11
+
+package pkcs11
+
+import (
+	"errors"
+)
+
+// If PKCS#11 support (build tag) has been enabled, which implies a CGO
+// dependency, openToken loads the PKCS#11 module at runtime.
+func init() {
+	openToken = func(Config) (Token, error) {
+		return nil, errors.New("support for PKCS#11 tokens is not available in this executable, build it with cgo and the pkcs11 build tag")
+	}
+}
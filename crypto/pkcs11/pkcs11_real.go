@@ -0,0 +1,208 @@
+//go:build cgo && pkcs11
+// +build cgo,pkcs11
+
+package pkcs11
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	p11 "github.com/miekg/pkcs11"
+)
+
+// If PKCS#11 support (build tag) has been enabled, which implies a CGO
+// dependency, openToken loads the PKCS#11 module at runtime.
+func init() {
+	openToken = openModuleToken
+}
+
+// moduleToken is a read-only session on a token of a PKCS#11 module.
+type moduleToken struct {
+	ctx     *p11.Ctx
+	session p11.SessionHandle
+
+	// PKCS#11 sessions must not be used concurrently
+	mtx sync.Mutex
+}
+
+func openModuleToken(cfg Config) (Token, error) {
+	ctx := p11.New(cfg.Module)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 module %s", cfg.Module)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("failed to initialize PKCS#11 module %s: %w", cfg.Module, err)
+	}
+
+	t := &moduleToken{ctx: ctx}
+	slot, err := t.findSlot(cfg.TokenLabel)
+	if err == nil {
+		t.session, err = ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION)
+	}
+	if err == nil {
+		err = ctx.Login(t.session, p11.CKU_USER, cfg.PIN)
+		if errors.Is(err, p11.Error(p11.CKR_USER_ALREADY_LOGGED_IN)) {
+			err = nil
+		}
+	}
+	if err != nil {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *moduleToken) findSlot(label string) (uint, error) {
+	slots, err := t.Slots()
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		if slot.TokenPresent && slot.TokenLabel == label {
+			return slot.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("PKCS#11 token %s not found", label)
+}
+
+func (t *moduleToken) Slots() ([]SlotInfo, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	ids, err := t.ctx.GetSlotList(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PKCS#11 slots: %w", err)
+	}
+
+	slots := make([]SlotInfo, 0, len(ids))
+	for _, id := range ids {
+		si, err := t.ctx.GetSlotInfo(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PKCS#11 slot %d info: %w", id, err)
+		}
+
+		slot := SlotInfo{
+			ID:           id,
+			Description:  strings.TrimSpace(si.SlotDescription),
+			Manufacturer: strings.TrimSpace(si.ManufacturerID),
+			TokenPresent: si.Flags&p11.CKF_TOKEN_PRESENT != 0,
+		}
+		if slot.TokenPresent {
+			ti, err := t.ctx.GetTokenInfo(id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get PKCS#11 slot %d token info: %w", id, err)
+			}
+			slot.TokenLabel = strings.TrimSpace(ti.Label)
+			slot.TokenModel = strings.TrimSpace(ti.Model)
+			slot.TokenSerial = strings.TrimSpace(ti.SerialNumber)
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+func (t *moduleToken) Keys() ([]KeyInfo, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	handles, err := t.findObjects(
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PUBLIC_KEY),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_EC),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]KeyInfo, 0, len(handles))
+	for _, h := range handles {
+		attrs, err := t.ctx.GetAttributeValue(t.session, h, []*p11.Attribute{
+			p11.NewAttribute(p11.CKA_LABEL, nil),
+			p11.NewAttribute(p11.CKA_ID, nil),
+			p11.NewAttribute(p11.CKA_EC_PARAMS, nil),
+			p11.NewAttribute(p11.CKA_EC_POINT, nil),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PKCS#11 public key: %w", err)
+		}
+
+		var key KeyInfo
+		for _, attr := range attrs {
+			switch attr.Type {
+			case p11.CKA_LABEL:
+				key.Label = string(attr.Value)
+			case p11.CKA_ID:
+				key.ID = attr.Value
+			case p11.CKA_EC_PARAMS:
+				key.ECParams = attr.Value
+			case p11.CKA_EC_POINT:
+				key.ECPoint = attr.Value
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (t *moduleToken) SignDigest(id []byte, digest []byte) ([]byte, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	handles, err := t.findObjects(
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PRIVATE_KEY),
+		p11.NewAttribute(p11.CKA_ID, id),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(handles) != 1 {
+		return nil, fmt.Errorf("found %d PKCS#11 private keys with id %X, expected 1", len(handles), id)
+	}
+
+	if err := t.ctx.SignInit(t.session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_ECDSA, nil)}, handles[0]); err != nil {
+		return nil, err
+	}
+	return t.ctx.Sign(t.session, digest)
+}
+
+func (t *moduleToken) findObjects(template ...*p11.Attribute) ([]p11.ObjectHandle, error) {
+	if err := t.ctx.FindObjectsInit(t.session, template); err != nil {
+		return nil, fmt.Errorf("failed to search PKCS#11 objects: %w", err)
+	}
+	defer func() { _ = t.ctx.FindObjectsFinal(t.session) }()
+
+	var handles []p11.ObjectHandle
+	for {
+		found, _, err := t.ctx.FindObjects(t.session, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search PKCS#11 objects: %w", err)
+		}
+		if len(found) == 0 {
+			return handles, nil
+		}
+		handles = append(handles, found...)
+	}
+}
+
+// Close logs out and closes the session, and unloads the module. Closing a
+// closed token is a no-op.
+func (t *moduleToken) Close() error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.ctx == nil {
+		return nil
+	}
+
+	_ = t.ctx.Logout(t.session)
+	err := t.ctx.CloseSession(t.session)
+	if ferr := t.ctx.Finalize(); err == nil {
+		err = ferr
+	}
+	t.ctx.Destroy()
+	t.ctx = nil
+	return err
+}
@@ -0,0 +1,68 @@
+package pkcs11_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto/pkcs11"
+	"github.com/cosmos/cosmos-sdk/crypto/pkcs11/pkcs11test"
+)
+
+func TestKeyInfoPubKey(t *testing.T) {
+	token := pkcs11test.NewMockToken("baron")
+	key, err := token.GenerateKey("validator")
+	require.NoError(t, err)
+
+	pub, err := key.PubKey()
+	require.NoError(t, err)
+	require.Len(t, pub.Key, 33)
+
+	// the EC point may also be returned without its octet string wrapping
+	raw := key
+	raw.ECPoint = key.ECPoint[2:]
+	rawPub, err := raw.PubKey()
+	require.NoError(t, err)
+	require.True(t, pub.Equals(rawPub))
+
+	p256 := key
+	p256.ECParams = []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}
+	require.False(t, p256.IsSecp256k1())
+	_, err = p256.PubKey()
+	require.Error(t, err)
+
+	invalid := key
+	invalid.ECPoint = []byte{0x04, 0x01}
+	_, err = invalid.PubKey()
+	require.Error(t, err)
+}
+
+func TestSign(t *testing.T) {
+	token := pkcs11test.NewMockToken("baron")
+	key, err := token.GenerateKey("validator")
+	require.NoError(t, err)
+	pub, err := key.PubKey()
+	require.NoError(t, err)
+
+	// tokens produce high S signatures half of the time
+	msg := []byte("sign bytes")
+	for i := 0; i < 32; i++ {
+		sig, err := pkcs11.Sign(token, key, msg)
+		require.NoError(t, err)
+		require.True(t, pub.VerifySignature(msg, sig))
+	}
+
+	unknown := key
+	unknown.ID = []byte("unknown")
+	_, err = pkcs11.Sign(token, unknown, msg)
+	require.Error(t, err)
+}
+
+func TestOpen(t *testing.T) {
+	_, err := pkcs11.Open(pkcs11.Config{TokenLabel: "baron"})
+	require.EqualError(t, err, "PKCS#11 module is not configured")
+	_, err = pkcs11.Open(pkcs11.Config{Module: "/usr/lib/softhsm/libsofthsm2.so"})
+	require.EqualError(t, err, "PKCS#11 token label is not configured")
+	_, err = pkcs11.Open(pkcs11.Config{Module: "/usr/lib/softhsm/libsofthsm2.so", TokenLabel: "baron"})
+	require.EqualError(t, err, "PKCS#11 token PIN is empty")
+}
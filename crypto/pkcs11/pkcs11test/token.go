@@ -0,0 +1,101 @@
+// Package pkcs11test provides a mock PKCS#11 token holding software keys, for
+// the tests of the users of the pkcs11 package.
+package pkcs11test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/asn1"
+	"fmt"
+
+	btcec "github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/cosmos/cosmos-sdk/crypto/pkcs11"
+)
+
+// secp256k1OID is the OID of the secp256k1 curve, found DER encoded in the
+// CKA_EC_PARAMS attribute of secp256k1 keys.
+var secp256k1OID = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+// MockToken is a Token holding software keys, meant to be used with
+// pkcs11.SetOpenToken in tests.
+type MockToken struct {
+	slots  []pkcs11.SlotInfo
+	keys   map[string]*btcec.PrivateKey
+	infos  []pkcs11.KeyInfo
+	closed bool
+}
+
+var _ pkcs11.Token = &MockToken{}
+
+// NewMockToken returns a mock token in slot 0 with the given label.
+func NewMockToken(label string) *MockToken {
+	return &MockToken{
+		slots: []pkcs11.SlotInfo{{
+			ID:           0,
+			Description:  "mock slot",
+			Manufacturer: "mock",
+			TokenPresent: true,
+			TokenLabel:   label,
+			TokenModel:   "mock",
+		}},
+		keys: make(map[string]*btcec.PrivateKey),
+	}
+}
+
+// GenerateKey generates a secp256k1 key pair with the given label on the
+// token, and returns its description.
+func (t *MockToken) GenerateKey(label string) (pkcs11.KeyInfo, error) {
+	priv, err := btcec.NewPrivateKey()
+	if err != nil {
+		return pkcs11.KeyInfo{}, err
+	}
+
+	params, err := asn1.Marshal(secp256k1OID)
+	if err != nil {
+		return pkcs11.KeyInfo{}, err
+	}
+	point, err := asn1.Marshal(priv.PubKey().SerializeUncompressed())
+	if err != nil {
+		return pkcs11.KeyInfo{}, err
+	}
+
+	id := []byte(fmt.Sprintf("%d", len(t.infos)))
+	info := pkcs11.KeyInfo{Label: label, ID: id, ECParams: params, ECPoint: point}
+	t.keys[string(id)] = priv
+	t.infos = append(t.infos, info)
+	return info, nil
+}
+
+// Closed reports whether the session on the token was closed.
+func (t *MockToken) Closed() bool { return t.closed }
+
+func (t *MockToken) Slots() ([]pkcs11.SlotInfo, error) { return t.slots, nil }
+
+func (t *MockToken) Keys() ([]pkcs11.KeyInfo, error) { return t.infos, nil }
+
+func (t *MockToken) SignDigest(id []byte, digest []byte) ([]byte, error) {
+	if t.closed {
+		return nil, fmt.Errorf("session is closed")
+	}
+
+	priv, ok := t.keys[string(id)]
+	if !ok {
+		return nil, fmt.Errorf("no private key with id %X", id)
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, priv.ToECDSA(), digest)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return sig, nil
+}
+
+func (t *MockToken) Close() error {
+	t.closed = true
+	return nil
+}
@@ -0,0 +1,181 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrPolicyViolation is returned when a key algorithm or KDF is not allowed by
+// the crypto policy.
+var ErrPolicyViolation = errors.New("crypto policy violation")
+
+// Policy restricts the key algorithms and KDFs used to armor, encrypt,
+// decrypt and import keys. It is set once at app init with SetPolicy, and lets
+// chains enforce e.g. post-quantum only keys, or forbid bcrypt, in a single
+// place. The zero Policy allows everything.
+//
+// Policies fail closed: a KDF whose parameters can't be checked against the
+// minimum ones is rejected.
+type Policy struct {
+	// AllowedAlgos are the allowed key algorithms, as returned by the Type
+	// method of keys, e.g. "secp256k1". Empty allows all algorithms.
+	AllowedAlgos []string
+	// AllowedKDFs are the names of the allowed KDFs, e.g. KDFArgon2id. Empty
+	// allows all the registered KDFs. The first allowed KDF is used to
+	// encrypt keys when bcrypt is not allowed, see PolicyKDF.
+	AllowedKDFs []string
+	// MinKDFParams are the minimum parameters by KDF name, e.g.
+	// {KDFArgon2id: {"m": 64 * 1024}}, using the names of the kdf-params
+	// armor header.
+	MinKDFParams map[string]map[string]uint64
+}
+
+var (
+	policyMtx sync.RWMutex
+	policy    Policy
+)
+
+// SetPolicy sets the global crypto policy.
+func SetPolicy(p Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	p.AllowedAlgos = append([]string(nil), p.AllowedAlgos...)
+	p.AllowedKDFs = append([]string(nil), p.AllowedKDFs...)
+	minParams := make(map[string]map[string]uint64, len(p.MinKDFParams))
+	for name, params := range p.MinKDFParams {
+		minParams[name] = make(map[string]uint64, len(params))
+		for k, v := range params {
+			minParams[name][k] = v
+		}
+	}
+	p.MinKDFParams = minParams
+
+	policyMtx.Lock()
+	defer policyMtx.Unlock()
+	policy = p
+	return nil
+}
+
+// GetPolicy returns the global crypto policy.
+func GetPolicy() Policy {
+	policyMtx.RLock()
+	defer policyMtx.RUnlock()
+	return policy
+}
+
+// Validate checks the policy is well-formed.
+func (p Policy) Validate() error {
+	for _, algo := range p.AllowedAlgos {
+		if algo == "" {
+			return errors.New("invalid crypto policy: empty algorithm")
+		}
+	}
+	for _, name := range p.AllowedKDFs {
+		if name == "" {
+			return errors.New("invalid crypto policy: empty KDF name")
+		}
+	}
+	for name := range p.MinKDFParams {
+		if len(p.AllowedKDFs) > 0 && !containsString(p.AllowedKDFs, name) {
+			return fmt.Errorf("invalid crypto policy: minimum parameters of KDF %s which is not allowed", name)
+		}
+	}
+	return nil
+}
+
+// CheckAlgo returns an ErrPolicyViolation error if the key algorithm is not
+// allowed.
+func (p Policy) CheckAlgo(algo string) error {
+	if len(p.AllowedAlgos) > 0 && !containsString(p.AllowedAlgos, algo) {
+		return fmt.Errorf("%w: key algorithm %q is not allowed", ErrPolicyViolation, algo)
+	}
+	return nil
+}
+
+// CheckKDF returns an ErrPolicyViolation error if the KDF is not allowed, or
+// its parameters are below the minimum ones. In FIPS mode, the KDF must also
+// be FIPS approved.
+func (p Policy) CheckKDF(kdf KDF) error {
+	if err := checkFIPSKDF(kdf); err != nil {
+		return err
+	}
+
+	name := kdf.Name()
+	if len(p.AllowedKDFs) > 0 && !containsString(p.AllowedKDFs, name) {
+		return fmt.Errorf("%w: KDF %q is not allowed", ErrPolicyViolation, name)
+	}
+
+	minParams := p.MinKDFParams[name]
+	if len(minParams) == 0 {
+		return nil
+	}
+
+	params, err := parseKDFParamValues(kdf.Params())
+	if err != nil {
+		return fmt.Errorf("%w: %s KDF parameters can't be checked: %v", ErrPolicyViolation, name, err)
+	}
+	for key, min := range minParams {
+		v, ok := params[key]
+		if !ok {
+			return fmt.Errorf("%w: %s KDF parameter %s is missing", ErrPolicyViolation, name, key)
+		}
+		if v < min {
+			return fmt.Errorf("%w: %s KDF parameter %s=%d is below the minimum %d", ErrPolicyViolation, name, key, v, min)
+		}
+	}
+	return nil
+}
+
+// parseKDFParamValues parses the parameters returned by KDF.Params, of the
+// form "k1=v1,k2=v2" where every value is an unsigned integer.
+func parseKDFParamValues(params string) (map[string]uint64, error) {
+	parsed := make(map[string]uint64)
+	for _, kv := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid KDF parameter %q", kv)
+		}
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid KDF parameter %q: %w", kv, err)
+		}
+		parsed[key] = v
+	}
+	return parsed, nil
+}
+
+// PolicyKDF returns the KDF used to encrypt keys under the global policy:
+// bcrypt with BcryptSecurityParameter when it is allowed, and the first
+// allowed KDF with its default parameters otherwise. In FIPS mode, PBKDF2 is
+// used when the policy doesn't list allowed KDFs.
+func PolicyKDF() (KDF, error) {
+	p := GetPolicy()
+
+	kdf, err := NewBcryptKDF(BcryptSecurityParameter)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckKDF(kdf); err == nil {
+		return kdf, nil
+	}
+
+	names := p.AllowedKDFs
+	if len(names) == 0 && FIPSMode() {
+		names = []string{KDFPBKDF2, KDFArgon2id}
+	}
+	for _, name := range names {
+		kdf, err := GetKDF(name, "")
+		if err != nil {
+			continue
+		}
+		if err := p.CheckKDF(kdf); err == nil {
+			return kdf, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no allowed KDF can be used with its default parameters", ErrPolicyViolation)
+}
@@ -0,0 +1,107 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+// setTestPolicy sets the crypto policy for the duration of the test.
+func setTestPolicy(t *testing.T, p crypto.Policy) {
+	t.Helper()
+
+	prev := crypto.GetPolicy()
+	require.NoError(t, crypto.SetPolicy(p))
+	t.Cleanup(func() { require.NoError(t, crypto.SetPolicy(prev)) })
+}
+
+func TestPolicy(t *testing.T) {
+	require.Error(t, crypto.SetPolicy(crypto.Policy{AllowedAlgos: []string{""}}))
+	require.Error(t, crypto.SetPolicy(crypto.Policy{
+		AllowedKDFs:  []string{crypto.KDFArgon2id},
+		MinKDFParams: map[string]map[string]uint64{crypto.KDFScrypt: {"ln": 15}},
+	}))
+
+	// the zero policy allows everything
+	var p crypto.Policy
+	require.NoError(t, p.CheckAlgo("anything"))
+	bcryptKDF, err := crypto.NewBcryptKDF(4)
+	require.NoError(t, err)
+	require.NoError(t, p.CheckKDF(bcryptKDF))
+
+	p = crypto.Policy{
+		AllowedAlgos: []string{"ed25519"},
+		AllowedKDFs:  []string{crypto.KDFArgon2id, crypto.KDFScrypt},
+		MinKDFParams: map[string]map[string]uint64{crypto.KDFArgon2id: {"m": 1024}},
+	}
+	require.NoError(t, p.CheckAlgo("ed25519"))
+	require.ErrorIs(t, p.CheckAlgo("secp256k1"), crypto.ErrPolicyViolation)
+	require.ErrorIs(t, p.CheckKDF(bcryptKDF), crypto.ErrPolicyViolation)
+
+	weak, err := crypto.NewArgon2idKDF(1, 64, 1)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.CheckKDF(weak), "below the minimum")
+	strong, err := crypto.NewArgon2idKDF(1, 1024, 1)
+	require.NoError(t, err)
+	require.NoError(t, p.CheckKDF(strong))
+
+	// parameters which can't be checked are rejected
+	p.MinKDFParams[crypto.KDFScrypt] = map[string]uint64{"x": 1}
+	scryptKDF, err := crypto.NewScryptKDF(10, 8, 1)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.CheckKDF(scryptKDF), "parameter x is missing")
+}
+
+func TestPolicyArmor(t *testing.T) {
+	secpKey := secp256k1.GenPrivKey()
+	edKey := ed25519.GenPrivKey()
+	argon2idKDF, err := crypto.NewArgon2idKDF(1, 64, 1)
+	require.NoError(t, err)
+
+	secpArmor, err := crypto.EncryptArmorPrivKeyWithKDF(secpKey, testPassphrase, secpKey.Type(), argon2idKDF)
+	require.NoError(t, err)
+	bcryptArmor, err := crypto.EncryptArmorPrivKey(edKey, testPassphrase, edKey.Type())
+	require.NoError(t, err)
+	pubArmor := crypto.ArmorPubKeyBytes(secpKey.PubKey().Bytes(), secpKey.Type())
+
+	setTestPolicy(t, crypto.Policy{
+		AllowedAlgos: []string{edKey.Type()},
+		AllowedKDFs:  []string{crypto.KDFArgon2id},
+	})
+
+	_, err = crypto.EncryptArmorPrivKeyWithKDF(secpKey, testPassphrase, secpKey.Type(), argon2idKDF)
+	require.ErrorIs(t, err, crypto.ErrPolicyViolation)
+	_, _, err = crypto.UnarmorDecryptPrivKey(secpArmor, testPassphrase)
+	require.ErrorIs(t, err, crypto.ErrPolicyViolation)
+	_, _, err = crypto.UnarmorPubKeyBytes(pubArmor)
+	require.ErrorIs(t, err, crypto.ErrPolicyViolation)
+
+	// bcrypt armors can't be decrypted any more, and keys are encrypted with
+	// the allowed KDF
+	_, _, err = crypto.UnarmorDecryptPrivKey(bcryptArmor, testPassphrase)
+	require.ErrorIs(t, err, crypto.ErrPolicyViolation)
+
+	kdf, err := crypto.PolicyKDF()
+	require.NoError(t, err)
+	require.Equal(t, crypto.KDFArgon2id, kdf.Name())
+
+	edArmor, err := crypto.EncryptArmorPrivKeyWithKDF(edKey, testPassphrase, edKey.Type(), argon2idKDF)
+	require.NoError(t, err)
+	decrypted, algo, err := crypto.UnarmorDecryptPrivKey(edArmor, testPassphrase)
+	require.NoError(t, err)
+	require.Equal(t, edKey.Type(), algo)
+	require.True(t, edKey.Equals(decrypted))
+
+	setTestPolicy(t, crypto.Policy{
+		AllowedKDFs:  []string{crypto.KDFArgon2id},
+		MinKDFParams: map[string]map[string]uint64{crypto.KDFArgon2id: {"m": 1 << 30}},
+	})
+	_, err = crypto.PolicyKDF()
+	require.ErrorIs(t, err, crypto.ErrPolicyViolation)
+	_, err = crypto.EncryptArmorPrivKey(edKey, testPassphrase, edKey.Type())
+	require.ErrorIs(t, err, crypto.ErrPolicyViolation)
+}
@@ -0,0 +1,155 @@
+package pqcparams
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudflare/circl/kem"
+	"github.com/cloudflare/circl/kem/kyber/kyber1024"
+	"github.com/cloudflare/circl/kem/kyber/kyber512"
+	"github.com/cloudflare/circl/kem/kyber/kyber768"
+	"github.com/cloudflare/circl/sign/dilithium"
+)
+
+// BenchmarkResult is the average duration of the operations of a parameter
+// set on the running machine.
+type BenchmarkResult struct {
+	Params     Params
+	Iterations int
+
+	KeyGen time.Duration
+	// Sign and Verify are set for signature schemes only.
+	Sign   time.Duration
+	Verify time.Duration
+	// Encapsulate and Decapsulate are set for key encapsulation mechanisms
+	// only.
+	Encapsulate time.Duration
+	Decapsulate time.Duration
+}
+
+// benchmarkMsg is the message signed by the benchmarks, of the size of a
+// typical sign doc.
+var benchmarkMsg = make([]byte, 512)
+
+// Benchmark runs the operations of the parameter set iterations times and
+// returns their average duration.
+func Benchmark(name ParamSet, iterations int) (BenchmarkResult, error) {
+	if iterations <= 0 {
+		return BenchmarkResult{}, errors.New("iterations must be positive")
+	}
+	params, err := Lookup(name)
+	if err != nil {
+		return BenchmarkResult{}, err
+	}
+
+	res := BenchmarkResult{Params: params, Iterations: iterations}
+	switch params.Family {
+	case FamilyKyber:
+		err = benchmarkKEM(&res, kemScheme(name))
+	case FamilyDilithium:
+		err = benchmarkSignature(&res, dilithiumMode(name))
+	}
+	if err != nil {
+		return BenchmarkResult{}, fmt.Errorf("failed to benchmark %s: %w", name, err)
+	}
+	return res, nil
+}
+
+// BenchmarkAll benchmarks all the known parameter sets.
+func BenchmarkAll(iterations int) ([]BenchmarkResult, error) {
+	results := make([]BenchmarkResult, 0, len(paramSets))
+	for _, p := range paramSets {
+		res, err := Benchmark(p.Name, iterations)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func kemScheme(name ParamSet) kem.Scheme {
+	switch name {
+	case Kyber512:
+		return kyber512.Scheme()
+	case Kyber768:
+		return kyber768.Scheme()
+	default:
+		return kyber1024.Scheme()
+	}
+}
+
+func dilithiumMode(name ParamSet) dilithium.Mode {
+	switch name {
+	case Dilithium2:
+		return dilithium.Mode2
+	case Dilithium3:
+		return dilithium.Mode3
+	default:
+		return dilithium.Mode5
+	}
+}
+
+func benchmarkKEM(res *BenchmarkResult, scheme kem.Scheme) error {
+	var keyGen, encaps, decaps time.Duration
+	for i := 0; i < res.Iterations; i++ {
+		start := time.Now()
+		pk, sk, err := scheme.GenerateKeyPair()
+		if err != nil {
+			return err
+		}
+		keyGen += time.Since(start)
+
+		start = time.Now()
+		ct, ss, err := scheme.Encapsulate(pk)
+		if err != nil {
+			return err
+		}
+		encaps += time.Since(start)
+
+		start = time.Now()
+		ss2, err := scheme.Decapsulate(sk, ct)
+		if err != nil {
+			return err
+		}
+		decaps += time.Since(start)
+
+		if string(ss) != string(ss2) {
+			return errors.New("decapsulated shared key differs from the encapsulated one")
+		}
+	}
+
+	n := time.Duration(res.Iterations)
+	res.KeyGen, res.Encapsulate, res.Decapsulate = keyGen/n, encaps/n, decaps/n
+	return nil
+}
+
+func benchmarkSignature(res *BenchmarkResult, mode dilithium.Mode) error {
+	var keyGen, sign, verify time.Duration
+	for i := 0; i < res.Iterations; i++ {
+		start := time.Now()
+		pk, sk, err := mode.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		keyGen += time.Since(start)
+
+		start = time.Now()
+		sig := mode.Sign(sk, benchmarkMsg)
+		sign += time.Since(start)
+
+		start = time.Now()
+		ok := mode.Verify(pk, benchmarkMsg, sig)
+		verify += time.Since(start)
+
+		if !ok {
+			return errors.New("signature verification failed")
+		}
+	}
+
+	n := time.Duration(res.Iterations)
+	res.KeyGen, res.Sign, res.Verify = keyGen/n, sign/n, verify/n
+	return nil
+}
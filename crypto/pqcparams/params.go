@@ -0,0 +1,149 @@
+// Package pqcparams describes the parameter sets of the post-quantum Kyber key
+// encapsulation mechanism and Dilithium signature scheme: their key, signature
+// and ciphertext sizes and NIST security levels. It benchmarks them on the
+// running machine and recommends the parameter set for a security level.
+package pqcparams
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Family is a post-quantum algorithm family. Its values are the key types of
+// the hd package.
+type Family string
+
+const (
+	// FamilyKyber is the Kyber key encapsulation mechanism.
+	FamilyKyber Family = "kyber"
+	// FamilyDilithium is the Dilithium signature scheme.
+	FamilyDilithium Family = "dilithium"
+)
+
+// ParamSet is the name of a parameter set.
+type ParamSet string
+
+const (
+	Kyber512   ParamSet = "kyber512"
+	Kyber768   ParamSet = "kyber768"
+	Kyber1024  ParamSet = "kyber1024"
+	Dilithium2 ParamSet = "dilithium2"
+	Dilithium3 ParamSet = "dilithium3"
+	Dilithium5 ParamSet = "dilithium5"
+)
+
+// SecurityLevel is a NIST post-quantum security category, from 1 to 5.
+type SecurityLevel int
+
+const (
+	// SecurityLevelLow is NIST category 1, as hard to break as AES-128.
+	SecurityLevelLow SecurityLevel = 1
+	// SecurityLevelMedium is NIST category 3, as hard to break as AES-192.
+	SecurityLevelMedium SecurityLevel = 3
+	// SecurityLevelHigh is NIST category 5, as hard to break as AES-256.
+	SecurityLevelHigh SecurityLevel = 5
+)
+
+// ParseSecurityLevel parses a security level, either low, medium or high, or
+// a NIST category from 1 to 5.
+func ParseSecurityLevel(s string) (SecurityLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "low":
+		return SecurityLevelLow, nil
+	case "medium":
+		return SecurityLevelMedium, nil
+	case "high":
+		return SecurityLevelHigh, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > 5 {
+		return 0, fmt.Errorf("invalid security level %q, expected low, medium, high or a NIST category from 1 to 5", s)
+	}
+	return SecurityLevel(n), nil
+}
+
+func (l SecurityLevel) String() string {
+	switch l {
+	case SecurityLevelLow:
+		return "low"
+	case SecurityLevelMedium:
+		return "medium"
+	case SecurityLevelHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("NIST level %d", int(l))
+	}
+}
+
+// Params is the metadata of a parameter set. Sizes are in bytes, for packed
+// keys.
+type Params struct {
+	Name          ParamSet
+	Family        Family
+	SecurityLevel SecurityLevel
+
+	PublicKeySize  int
+	PrivateKeySize int
+	// SignatureSize is the size of signatures, for signature schemes only.
+	SignatureSize int
+	// CiphertextSize and SharedKeySize are the sizes of encapsulated and
+	// shared keys, for key encapsulation mechanisms only.
+	CiphertextSize int
+	SharedKeySize  int
+}
+
+// paramSets are the known parameter sets, by family and increasing security
+// level.
+var paramSets = []Params{
+	{Name: Kyber512, Family: FamilyKyber, SecurityLevel: 1, PublicKeySize: 800, PrivateKeySize: 1632, CiphertextSize: 768, SharedKeySize: 32},
+	{Name: Kyber768, Family: FamilyKyber, SecurityLevel: 3, PublicKeySize: 1184, PrivateKeySize: 2400, CiphertextSize: 1088, SharedKeySize: 32},
+	{Name: Kyber1024, Family: FamilyKyber, SecurityLevel: 5, PublicKeySize: 1568, PrivateKeySize: 3168, CiphertextSize: 1568, SharedKeySize: 32},
+	{Name: Dilithium2, Family: FamilyDilithium, SecurityLevel: 2, PublicKeySize: 1312, PrivateKeySize: 2528, SignatureSize: 2420},
+	{Name: Dilithium3, Family: FamilyDilithium, SecurityLevel: 3, PublicKeySize: 1952, PrivateKeySize: 4000, SignatureSize: 3293},
+	{Name: Dilithium5, Family: FamilyDilithium, SecurityLevel: 5, PublicKeySize: 2592, PrivateKeySize: 4864, SignatureSize: 4595},
+}
+
+// All returns the known parameter sets, by family and increasing security
+// level.
+func All() []Params {
+	return append([]Params(nil), paramSets...)
+}
+
+// ByFamily returns the parameter sets of the family, by increasing security
+// level.
+func ByFamily(family Family) []Params {
+	var params []Params
+	for _, p := range paramSets {
+		if p.Family == family {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// Lookup returns the parameter set of the given name.
+func Lookup(name ParamSet) (Params, error) {
+	for _, p := range paramSets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Params{}, fmt.Errorf("unknown post-quantum parameter set %q", name)
+}
+
+// Recommend returns the parameter set of the family with the smallest keys
+// which meets the security level.
+func Recommend(family Family, level SecurityLevel) (Params, error) {
+	params := ByFamily(family)
+	if len(params) == 0 {
+		return Params{}, fmt.Errorf("unknown post-quantum algorithm family %q", family)
+	}
+	for _, p := range params {
+		if p.SecurityLevel >= level {
+			return p, nil
+		}
+	}
+	return Params{}, fmt.Errorf("no %s parameter set meets the %s security level", family, level)
+}
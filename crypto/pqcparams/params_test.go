@@ -0,0 +1,73 @@
+package pqcparams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamsMatchImplementation(t *testing.T) {
+	for _, p := range All() {
+		switch p.Family {
+		case FamilyKyber:
+			scheme := kemScheme(p.Name)
+			require.Equal(t, scheme.PublicKeySize(), p.PublicKeySize, p.Name)
+			require.Equal(t, scheme.PrivateKeySize(), p.PrivateKeySize, p.Name)
+			require.Equal(t, scheme.CiphertextSize(), p.CiphertextSize, p.Name)
+			require.Equal(t, scheme.SharedKeySize(), p.SharedKeySize, p.Name)
+		case FamilyDilithium:
+			mode := dilithiumMode(p.Name)
+			require.Equal(t, mode.PublicKeySize(), p.PublicKeySize, p.Name)
+			require.Equal(t, mode.PrivateKeySize(), p.PrivateKeySize, p.Name)
+			require.Equal(t, mode.SignatureSize(), p.SignatureSize, p.Name)
+		}
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	for _, tc := range []struct {
+		family   Family
+		level    string
+		expected ParamSet
+	}{
+		{FamilyKyber, "low", Kyber512},
+		{FamilyKyber, "medium", Kyber768},
+		{FamilyKyber, "high", Kyber1024},
+		{FamilyDilithium, "low", Dilithium2},
+		{FamilyDilithium, "2", Dilithium2},
+		{FamilyDilithium, "medium", Dilithium3},
+		{FamilyDilithium, "4", Dilithium5},
+		{FamilyDilithium, "HIGH", Dilithium5},
+	} {
+		level, err := ParseSecurityLevel(tc.level)
+		require.NoError(t, err)
+		p, err := Recommend(tc.family, level)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, p.Name, "%s %s", tc.family, tc.level)
+	}
+
+	_, err := ParseSecurityLevel("6")
+	require.ErrorContains(t, err, "invalid security level")
+	_, err = Recommend("falcon", SecurityLevelLow)
+	require.ErrorContains(t, err, "unknown post-quantum algorithm family")
+}
+
+func TestBenchmark(t *testing.T) {
+	res, err := Benchmark(Dilithium2, 2)
+	require.NoError(t, err)
+	require.Equal(t, Dilithium2, res.Params.Name)
+	require.Positive(t, res.Sign)
+	require.Positive(t, res.Verify)
+	require.Zero(t, res.Encapsulate)
+
+	res, err = Benchmark(Kyber512, 2)
+	require.NoError(t, err)
+	require.Positive(t, res.Encapsulate)
+	require.Positive(t, res.Decapsulate)
+	require.Zero(t, res.Sign)
+
+	_, err = Benchmark(Kyber512, 0)
+	require.Error(t, err)
+	_, err = Benchmark("kyber2048", 1)
+	require.ErrorContains(t, err, "unknown post-quantum parameter set")
+}
@@ -0,0 +1,199 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cosmos/cosmos-sdk/codec/legacy"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+const (
+	headerOwner          = "owner"
+	headerChainID        = "chain-id"
+	headerCreatedAt      = "created-at"
+	headerMetadataSigner = "metadata-signer"
+	headerMetadataSig    = "metadata-sig"
+
+	pubKeyMetadataDomain = "baron-chain/pubkey-metadata/v1"
+)
+
+// ErrInvalidPubKeyMetadata is returned when the metadata of an armored public
+// key doesn't match its signature, or is signed by a key which isn't trusted.
+var ErrInvalidPubKeyMetadata = errors.New("invalid public key metadata signature")
+
+// PubKeyMetadata is the signed context of an armored public key: who owns it,
+// for which chain, and since when. The metadata is signed together with the
+// key, so that it can't be altered once shared.
+type PubKeyMetadata struct {
+	Owner     string
+	ChainID   string
+	CreatedAt time.Time
+	// Signer is the key which signed the metadata, set on unarmor: the armored
+	// key itself, or the trusted signer given to
+	// UnarmorPubKeyBytesWithTrustedMetadata.
+	Signer cryptotypes.PubKey
+}
+
+// PubKeyMetadataSigner signs the metadata of an armored public key and returns
+// the signature and the public key which verifies it, like Keyring.Sign.
+type PubKeyMetadataSigner func(msg []byte) ([]byte, cryptotypes.PubKey, error)
+
+// ArmorPubKeyBytesWithMetadata armors public key bytes like ArmorPubKeyBytes,
+// with the metadata in the owner, chain-id and created-at headers, signed with
+// sign: by the private key of the armored public key, or by a key the readers
+// of the armor trust, see UnarmorPubKeyBytesWithTrustedMetadata. Empty fields
+// are omitted.
+func ArmorPubKeyBytesWithMetadata(bz []byte, algo string, md PubKeyMetadata, sign PubKeyMetadataSigner) (string, error) {
+	header := map[string]string{
+		headerVersion: version1,
+	}
+	if algo != "" {
+		header[headerType] = algo
+	}
+	for key, value := range map[string]string{headerOwner: md.Owner, headerChainID: md.ChainID} {
+		if value == "" {
+			continue
+		}
+		// armor headers are single lines, trimmed when decoded
+		if strings.IndexFunc(value, unicode.IsControl) >= 0 || strings.TrimSpace(value) != value {
+			return "", fmt.Errorf("invalid %s %q: control characters and surrounding spaces are not allowed", key, value)
+		}
+		header[key] = value
+	}
+	if !md.CreatedAt.IsZero() {
+		header[headerCreatedAt] = md.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	sig, signer, err := sign(pubKeyMetadataSignBytes(bz, header))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign public key metadata: %w", err)
+	}
+	signerBz, err := legacy.Cdc.Marshal(signer)
+	if err != nil {
+		return "", err
+	}
+	header[headerMetadataSigner] = hex.EncodeToString(signerBz)
+	header[headerMetadataSig] = base64.StdEncoding.EncodeToString(sig)
+
+	return EncodeArmor(blockTypePubKey, header, bz), nil
+}
+
+// UnarmorPubKeyBytesWithMetadata unarmors public key bytes like
+// UnarmorPubKeyBytes, and returns their metadata, or nil if the armor has
+// none. The metadata must be signed by the armored public key itself.
+func UnarmorPubKeyBytesWithMetadata(armorStr string) ([]byte, string, *PubKeyMetadata, error) {
+	return UnarmorPubKeyBytesWithTrustedMetadata(armorStr, nil)
+}
+
+// UnarmorPubKeyBytesWithTrustedMetadata is like UnarmorPubKeyBytesWithMetadata,
+// for metadata signed by the trusted signer, e.g. the key of the operator of
+// a chain, rather than by the armored public key, if signer is not nil.
+func UnarmorPubKeyBytesWithTrustedMetadata(armorStr string, signer cryptotypes.PubKey) ([]byte, string, *PubKeyMetadata, error) {
+	bz, header, err := unarmorBytes(armorStr, blockTypePubKey)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("couldn't unarmor bytes: %v", err)
+	}
+
+	switch header[headerVersion] {
+	case version0:
+		if err := GetPolicy().CheckAlgo(defaultAlgo); err != nil {
+			return nil, "", nil, err
+		}
+		return bz, defaultAlgo, nil, nil
+	case version1:
+		algo := header[headerType]
+		if algo == "" {
+			algo = defaultAlgo
+		}
+		if err := GetPolicy().CheckAlgo(algo); err != nil {
+			return nil, "", nil, err
+		}
+		md, err := verifyPubKeyMetadata(bz, header, signer)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return bz, algo, md, nil
+	case "":
+		return nil, "", nil, fmt.Errorf("header's version field is empty")
+	default:
+		return nil, "", nil, fmt.Errorf("unrecognized version: %v", header[headerVersion])
+	}
+}
+
+// verifyPubKeyMetadata verifies the signature of the metadata of an armored
+// public key by the trusted signer, or by the key itself if trusted is nil,
+// and returns the metadata, or nil if the armor has none. The metadata-signer
+// header only tells which key signed, and isn't trusted.
+func verifyPubKeyMetadata(bz []byte, header map[string]string, trusted cryptotypes.PubKey) (*PubKeyMetadata, error) {
+	if header[headerMetadataSig] == "" && header[headerMetadataSigner] == "" {
+		for _, key := range []string{headerOwner, headerChainID, headerCreatedAt} {
+			if header[key] != "" {
+				return nil, fmt.Errorf("%w: the %s header is not signed", ErrInvalidPubKeyMetadata, key)
+			}
+		}
+		return nil, nil
+	}
+
+	signerBz, err := hex.DecodeString(header[headerMetadataSigner])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid signer: %v", ErrInvalidPubKeyMetadata, err)
+	}
+	signer, err := legacy.PubKeyFromBytes(signerBz)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid signer: %v", ErrInvalidPubKeyMetadata, err)
+	}
+	switch {
+	case trusted != nil && !signer.Equals(trusted):
+		return nil, fmt.Errorf("%w: signed by %X, not by the trusted signer", ErrInvalidPubKeyMetadata, signer.Bytes())
+	case trusted == nil && !bytes.Equal(signer.Bytes(), bz):
+		return nil, fmt.Errorf("%w: not signed by the armored key", ErrInvalidPubKeyMetadata)
+	}
+	sig, err := base64.StdEncoding.DecodeString(header[headerMetadataSig])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPubKeyMetadata, err)
+	}
+	if !signer.VerifySignature(pubKeyMetadataSignBytes(bz, header), sig) {
+		return nil, ErrInvalidPubKeyMetadata
+	}
+
+	md := &PubKeyMetadata{
+		Owner:   header[headerOwner],
+		ChainID: header[headerChainID],
+		Signer:  signer,
+	}
+	if createdAt := header[headerCreatedAt]; createdAt != "" {
+		if md.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", headerCreatedAt, err)
+		}
+	}
+	return md, nil
+}
+
+// pubKeyMetadataSignBytes returns the bytes signed for the metadata of an
+// armored public key: the key bytes, its version and type headers, and the
+// metadata headers, each prefixed with its length.
+func pubKeyMetadataSignBytes(bz []byte, header map[string]string) []byte {
+	var buf bytes.Buffer
+	for _, field := range [][]byte{
+		[]byte(pubKeyMetadataDomain),
+		[]byte(header[headerVersion]),
+		[]byte(header[headerType]),
+		bz,
+		[]byte(header[headerOwner]),
+		[]byte(header[headerChainID]),
+		[]byte(header[headerCreatedAt]),
+	} {
+		var size [binary.MaxVarintLen64]byte
+		buf.Write(size[:binary.PutUvarint(size[:], uint64(len(field)))])
+		buf.Write(field)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,89 @@
+package crypto_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+func TestArmorPubKeyBytesWithMetadata(t *testing.T) {
+	priv := secp256k1.GenPrivKey()
+	sign := func(msg []byte) ([]byte, cryptotypes.PubKey, error) {
+		sig, err := priv.Sign(msg)
+		return sig, priv.PubKey(), err
+	}
+	bz := priv.PubKey().Bytes()
+	md := crypto.PubKeyMetadata{
+		Owner:     "validator ops",
+		ChainID:   "baron-1",
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+	}
+
+	armored, err := crypto.ArmorPubKeyBytesWithMetadata(bz, "secp256k1", md, sign)
+	require.NoError(t, err)
+	require.Contains(t, armored, "owner: validator ops")
+	require.Contains(t, armored, "created-at: 2024-05-01T10:00:00Z")
+
+	pubBz, algo, got, err := crypto.UnarmorPubKeyBytesWithMetadata(armored)
+	require.NoError(t, err)
+	require.Equal(t, bz, pubBz)
+	require.Equal(t, "secp256k1", algo)
+	require.Equal(t, md.Owner, got.Owner)
+	require.Equal(t, md.ChainID, got.ChainID)
+	require.True(t, md.CreatedAt.Equal(got.CreatedAt))
+	require.True(t, priv.PubKey().Equals(got.Signer))
+
+	// UnarmorPubKeyBytes verifies the metadata too
+	_, _, err = crypto.UnarmorPubKeyBytes(armored)
+	require.NoError(t, err)
+
+	t.Run("tampered metadata", func(t *testing.T) {
+		tampered := strings.Replace(armored, "chain-id: baron-1", "chain-id: baron-2", 1)
+		_, _, err := crypto.UnarmorPubKeyBytes(tampered)
+		require.ErrorIs(t, err, crypto.ErrInvalidPubKeyMetadata)
+	})
+
+	t.Run("unsigned metadata", func(t *testing.T) {
+		unsigned := crypto.EncodeArmor("TENDERMINT PUBLIC KEY", map[string]string{"version": "0.0.1", "owner": "mallory"}, bz)
+		_, _, err := crypto.UnarmorPubKeyBytes(unsigned)
+		require.ErrorIs(t, err, crypto.ErrInvalidPubKeyMetadata)
+	})
+
+	t.Run("no metadata", func(t *testing.T) {
+		_, _, got, err := crypto.UnarmorPubKeyBytesWithMetadata(crypto.ArmorPubKeyBytes(bz, "secp256k1"))
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("metadata signed by another key", func(t *testing.T) {
+		other := secp256k1.GenPrivKey()
+		signOther := func(msg []byte) ([]byte, cryptotypes.PubKey, error) {
+			sig, err := other.Sign(msg)
+			return sig, other.PubKey(), err
+		}
+		armored, err := crypto.ArmorPubKeyBytesWithMetadata(bz, "secp256k1", md, signOther)
+		require.NoError(t, err)
+
+		// the armor can't vouch for its own signer
+		_, _, err = crypto.UnarmorPubKeyBytes(armored)
+		require.ErrorIs(t, err, crypto.ErrInvalidPubKeyMetadata)
+		_, _, _, err = crypto.UnarmorPubKeyBytesWithTrustedMetadata(armored, secp256k1.GenPrivKey().PubKey())
+		require.ErrorIs(t, err, crypto.ErrInvalidPubKeyMetadata)
+
+		_, _, got, err := crypto.UnarmorPubKeyBytesWithTrustedMetadata(armored, other.PubKey())
+		require.NoError(t, err)
+		require.Equal(t, md.Owner, got.Owner)
+		require.True(t, other.PubKey().Equals(got.Signer))
+	})
+
+	t.Run("invalid owner", func(t *testing.T) {
+		_, err := crypto.ArmorPubKeyBytesWithMetadata(bz, "secp256k1", crypto.PubKeyMetadata{Owner: "ops\nchain-id: x"}, sign)
+		require.ErrorContains(t, err, "invalid owner")
+	})
+}
@@ -0,0 +1,164 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+const (
+	// SplitSchemeShamirSecp256k1 shares a secp256k1 private scalar with
+	// Shamir's secret sharing modulo the curve order, so that any threshold of
+	// the shares combine into the key.
+	SplitSchemeShamirSecp256k1 = "secp256k1-shamir"
+
+	// MaxKeyShares is the maximum number of shares of a key shared by
+	// SharePrivKey.
+	MaxKeyShares = 255
+
+	keyShareGroupIDSize = 16
+)
+
+// SharePrivKey shares a private key among total keyrings, so that signing
+// requires the shares of threshold of them and fewer shares reveal nothing
+// about the key. The shares have the indexes 1 to total and a random group ID,
+// distinguishing them from the shares of other splits of the same key.
+//
+// Like SplitPrivKey, this protects the key at rest only: the shares never sign
+// separately, and a threshold of them rebuild the key with CombineKeyShares.
+// Dilithium keys return ErrThresholdUnsupported.
+func SharePrivKey(privKey cryptotypes.PrivKey, threshold, total uint32) ([]KeyShare, error) {
+	if threshold < 2 || threshold > total || total > MaxKeyShares {
+		return nil, fmt.Errorf("invalid %d of %d key sharing, expected 2 <= threshold <= shares <= %d", threshold, total, MaxKeyShares)
+	}
+
+	switch privKey.Type() {
+	case keyTypeSecp256k1:
+	case keyTypeDilithium:
+		return nil, fmt.Errorf("%w: %s", ErrThresholdUnsupported, SplitSchemeDilithium3)
+	default:
+		return nil, fmt.Errorf("sharing %s keys is not supported", privKey.Type())
+	}
+
+	var x btcec.ModNScalar
+	if overflow := x.SetByteSlice(privKey.Bytes()); overflow || x.IsZero() {
+		return nil, fmt.Errorf("invalid secp256k1 private key")
+	}
+	defer x.Zero()
+
+	groupID := make([]byte, keyShareGroupIDSize)
+	if _, err := rand.Read(groupID); err != nil {
+		return nil, err
+	}
+
+	// the shares are the values at 1 to total of a random polynomial of degree
+	// threshold-1 whose value at 0 is x, drawn again until no share is zero
+	coeffs := make([]btcec.ModNScalar, threshold)
+	defer func() {
+		for i := range coeffs {
+			coeffs[i].Zero()
+		}
+	}()
+	coeffs[0].Set(&x)
+	for {
+		for i := 1; i < len(coeffs); i++ {
+			c, err := randomScalar(rand.Reader)
+			if err != nil {
+				return nil, err
+			}
+			coeffs[i] = c
+		}
+
+		pubKey := privKey.PubKey().Bytes()
+		shares := make([]KeyShare, 0, total)
+		for index := uint32(1); index <= total; index++ {
+			y := evalPolynomial(coeffs, index)
+			if y.IsZero() {
+				break
+			}
+			yBytes := y.Bytes()
+			y.Zero()
+			shares = append(shares, KeyShare{
+				Scheme:    SplitSchemeShamirSecp256k1,
+				Index:     index,
+				Threshold: threshold,
+				GroupID:   hex.EncodeToString(groupID),
+				PubKey:    pubKey,
+				Share:     yBytes[:],
+			})
+		}
+		if len(shares) == int(total) {
+			return shares, nil
+		}
+	}
+}
+
+// validateShareIndex checks the index of a share of the scheme, and its
+// threshold and group ID for the SplitSchemeShamirSecp256k1 scheme.
+func validateShareIndex(scheme string, index, threshold uint32, groupID string) error {
+	if scheme != SplitSchemeShamirSecp256k1 {
+		if index != KeyShareLocal && index != KeyShareRemote {
+			return fmt.Errorf("invalid key share index %d", index)
+		}
+		return nil
+	}
+
+	if index < 1 || index > MaxKeyShares {
+		return fmt.Errorf("invalid key share index %d", index)
+	}
+	if threshold < 2 || threshold > MaxKeyShares {
+		return fmt.Errorf("invalid key share threshold %d", threshold)
+	}
+	if groupID == "" {
+		return fmt.Errorf("key share %d has no group ID", index)
+	}
+	return nil
+}
+
+// evalPolynomial returns the value at x of the polynomial with the given
+// coefficients, from the constant one.
+func evalPolynomial(coeffs []btcec.ModNScalar, x uint32) btcec.ModNScalar {
+	var xs, y btcec.ModNScalar
+	xs.SetInt(x)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y.Mul(&xs).Add(&coeffs[i])
+	}
+	return y
+}
+
+// interpolateShares sets x to the value at 0 of the polynomial through the
+// shares, i.e. the shared key.
+func interpolateShares(x *btcec.ModNScalar, shares []KeyShare) error {
+	for i, pi := range shares {
+		y, err := parseScalarShare(pi.Share)
+		if err != nil {
+			return fmt.Errorf("key share %d: %w", pi.Index, err)
+		}
+
+		// the Lagrange coefficient of the share at 0
+		var xi, num, den btcec.ModNScalar
+		xi.SetInt(pi.Index)
+		num.SetInt(1)
+		den.SetInt(1)
+		for j, pj := range shares {
+			if i == j {
+				continue
+			}
+			var xj, diff btcec.ModNScalar
+			xj.SetInt(pj.Index)
+			num.Mul(&xj)
+			diff.NegateVal(&xi).Add(&xj)
+			den.Mul(&diff)
+		}
+		den.InverseNonConst()
+
+		y.Mul(&num).Mul(&den)
+		x.Add(&y)
+		y.Zero()
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package crypto_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+func TestSharePrivKey(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	shares, err := crypto.SharePrivKey(privKey, 3, 5)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+	for i, share := range shares {
+		require.NoError(t, share.Validate())
+		require.Equal(t, uint32(i+1), share.Index)
+		require.Equal(t, uint32(3), share.Threshold)
+		require.Equal(t, shares[0].GroupID, share.GroupID)
+		require.Equal(t, privKey.PubKey().Bytes(), share.PubKey)
+		require.NotEqual(t, privKey.Bytes(), share.Share)
+	}
+
+	// any threshold of the shares rebuild the key, in any order
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var selected []crypto.KeyShare
+		for _, i := range subset {
+			selected = append(selected, shares[i])
+		}
+		combined, err := crypto.CombineKeyShares(selected...)
+		require.NoError(t, err)
+		require.True(t, privKey.Equals(combined))
+	}
+
+	_, err = crypto.CombineKeyShares(shares[0], shares[1])
+	require.ErrorContains(t, err, "expected at least 3 key shares")
+	_, err = crypto.CombineKeyShares(shares[0], shares[1], shares[1])
+	require.ErrorContains(t, err, "duplicate key share")
+
+	// shares of different splits of the same key don't combine
+	others, err := crypto.SharePrivKey(privKey, 3, 5)
+	require.NoError(t, err)
+	_, err = crypto.CombineKeyShares(shares[0], shares[1], others[2])
+	require.ErrorContains(t, err, "different splits of the key")
+
+	_, err = crypto.SharePrivKey(privKey, 1, 3)
+	require.ErrorContains(t, err, "invalid 1 of 3 key sharing")
+	_, err = crypto.SharePrivKey(privKey, 4, 3)
+	require.ErrorContains(t, err, "invalid 4 of 3 key sharing")
+	_, err = crypto.SharePrivKey(ed25519.GenPrivKey(), 2, 3)
+	require.ErrorContains(t, err, "sharing ed25519 keys is not supported")
+}
+
+func TestEncryptArmorSharedKeyShare(t *testing.T) {
+	shares, err := crypto.SharePrivKey(secp256k1.GenPrivKey(), 2, 3)
+	require.NoError(t, err)
+
+	armored, err := crypto.EncryptArmorKeyShare(shares[2], testPassphrase)
+	require.NoError(t, err)
+	share, err := crypto.UnarmorDecryptKeyShare(armored, testPassphrase)
+	require.NoError(t, err)
+	require.Equal(t, shares[2], share)
+
+	share.GroupID = ""
+	require.False(t, errors.Is(share.Validate(), crypto.ErrThresholdUnsupported))
+	require.ErrorContains(t, share.Validate(), "has no group ID")
+}
@@ -0,0 +1,299 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	// SplitSchemeSecp256k1 splits a secp256k1 private scalar into two additive
+	// shares modulo the curve order.
+	SplitSchemeSecp256k1 = "secp256k1-2of2"
+	// SplitSchemeDilithium3 is reserved for threshold dilithium signatures.
+	// Its primitives return ErrThresholdUnsupported until a threshold scheme
+	// is implemented.
+	SplitSchemeDilithium3 = "dilithium3-threshold"
+
+	// KeyShareLocal is the index of the share held by the local keyring.
+	KeyShareLocal = 1
+	// KeyShareRemote is the index of the share held by the remote custodian.
+	KeyShareRemote = 2
+
+	blockTypeKeyShare   = "BARON CHAIN KEY SHARE"
+	keyShareVersion     = "1"
+	keyTypeDilithium    = "dilithium"
+	secp256k1ScalarSize = 32
+)
+
+// ErrThresholdUnsupported is returned by the split key primitives for schemes
+// which have no threshold signing implementation yet.
+var ErrThresholdUnsupported = errors.New("threshold signing is not supported yet")
+
+// KeyShare is one of the shares of a split private key.
+type KeyShare struct {
+	Scheme string `json:"scheme"`
+	// Index is KeyShareLocal or KeyShareRemote, or the number of the share
+	// from 1 for the SplitSchemeShamirSecp256k1 scheme.
+	Index uint32 `json:"index"`
+	// Threshold is the number of shares signing requires, and GroupID
+	// identifies the shares of the same split, for the
+	// SplitSchemeShamirSecp256k1 scheme.
+	Threshold uint32 `json:"threshold,omitempty"`
+	GroupID   string `json:"group_id,omitempty"`
+	// PubKey is the public key of the whole private key.
+	PubKey []byte `json:"pub_key"`
+	Share  []byte `json:"share"`
+}
+
+// SplitPrivKey splits a private key between the local keyring and a remote
+// custodian, so that neither of them stores a key able to sign alone.
+//
+// secp256k1 keys are split into two additive shares. This protects the key at
+// rest only: the shares never sign separately, and signing requires gathering
+// both of them and rebuilding the key with CombineKeyShares, on a machine
+// trusted with the whole key. Dilithium keys return ErrThresholdUnsupported.
+func SplitPrivKey(privKey cryptotypes.PrivKey) (local, remote KeyShare, err error) {
+	switch privKey.Type() {
+	case keyTypeSecp256k1:
+		var x btcec.ModNScalar
+		if overflow := x.SetByteSlice(privKey.Bytes()); overflow || x.IsZero() {
+			return KeyShare{}, KeyShare{}, fmt.Errorf("invalid secp256k1 private key")
+		}
+
+		// x = x1 + x2, with x2 uniformly random and neither share zero
+		var x1, x2 btcec.ModNScalar
+		for x1.IsZero() {
+			if x2, err = randomScalar(rand.Reader); err != nil {
+				return KeyShare{}, KeyShare{}, err
+			}
+			x1.NegateVal(&x2).Add(&x)
+		}
+		defer x.Zero()
+		defer x1.Zero()
+		defer x2.Zero()
+
+		pubKey := privKey.PubKey().Bytes()
+		x1Bytes, x2Bytes := x1.Bytes(), x2.Bytes()
+		local = KeyShare{Scheme: SplitSchemeSecp256k1, Index: KeyShareLocal, PubKey: pubKey, Share: x1Bytes[:]}
+		remote = KeyShare{Scheme: SplitSchemeSecp256k1, Index: KeyShareRemote, PubKey: pubKey, Share: x2Bytes[:]}
+		return local, remote, nil
+
+	case keyTypeDilithium:
+		return KeyShare{}, KeyShare{}, fmt.Errorf("%w: %s", ErrThresholdUnsupported, SplitSchemeDilithium3)
+
+	default:
+		return KeyShare{}, KeyShare{}, fmt.Errorf("splitting %s keys is not supported", privKey.Type())
+	}
+}
+
+// CombineKeyShares rebuilds the private key from both shares of a key split by
+// SplitPrivKey, or from a threshold of the shares of a key shared by
+// SharePrivKey, checked against the public key of the shares. The shares must
+// be gathered on a machine trusted with the whole key, e.g. to import it into
+// its keyring: they are never meant to be sent to a signer.
+func CombineKeyShares(shares ...KeyShare) (cryptotypes.PrivKey, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no key shares")
+	}
+	first := shares[0]
+	if first.Scheme != SplitSchemeShamirSecp256k1 && len(shares) != 2 {
+		return nil, fmt.Errorf("expected 2 key shares, got %d", len(shares))
+	}
+
+	seen := make(map[uint32]bool, len(shares))
+	for _, s := range shares {
+		if err := s.Validate(); err != nil {
+			return nil, fmt.Errorf("key share %d: %w", s.Index, err)
+		}
+		if s.Scheme != first.Scheme {
+			return nil, fmt.Errorf("key shares of different schemes %s and %s", first.Scheme, s.Scheme)
+		}
+		if !bytes.Equal(s.PubKey, first.PubKey) {
+			return nil, fmt.Errorf("key shares of different keys")
+		}
+		if s.GroupID != first.GroupID || s.Threshold != first.Threshold {
+			return nil, fmt.Errorf("key shares of different splits of the key")
+		}
+		if seen[s.Index] {
+			return nil, fmt.Errorf("duplicate key share %d", s.Index)
+		}
+		seen[s.Index] = true
+	}
+
+	var x btcec.ModNScalar
+	defer x.Zero()
+	switch first.Scheme {
+	case SplitSchemeSecp256k1:
+		for _, s := range shares {
+			share, err := parseScalarShare(s.Share)
+			if err != nil {
+				return nil, fmt.Errorf("key share %d: %w", s.Index, err)
+			}
+			x.Add(&share)
+			share.Zero()
+		}
+
+	case SplitSchemeShamirSecp256k1:
+		if len(shares) < int(first.Threshold) {
+			return nil, fmt.Errorf("expected at least %d key shares, got %d", first.Threshold, len(shares))
+		}
+		if err := interpolateShares(&x, shares[:first.Threshold]); err != nil {
+			return nil, err
+		}
+	}
+
+	xBytes := x.Bytes()
+	privKey := &secp256k1.PrivKey{Key: xBytes[:]}
+	if !bytes.Equal(privKey.PubKey().Bytes(), first.PubKey) {
+		zero(privKey.Key)
+		return nil, fmt.Errorf("key shares don't combine into the key %X", first.PubKey)
+	}
+	return privKey, nil
+}
+
+// Validate checks the scheme, index and share of the key share.
+func (s KeyShare) Validate() error {
+	if err := validateShareIndex(s.Scheme, s.Index, s.Threshold, s.GroupID); err != nil {
+		return err
+	}
+
+	switch s.Scheme {
+	case SplitSchemeSecp256k1, SplitSchemeShamirSecp256k1:
+		if len(s.PubKey) != secp256k1.PubKeySize {
+			return fmt.Errorf("invalid secp256k1 public key size %d", len(s.PubKey))
+		}
+		_, err := parseScalarShare(s.Share)
+		return err
+	case SplitSchemeDilithium3:
+		return fmt.Errorf("%w: %s", ErrThresholdUnsupported, s.Scheme)
+	default:
+		return fmt.Errorf("unknown key split scheme %q", s.Scheme)
+	}
+}
+
+// EncryptArmorKeyShare encrypts and armors a key share with a passphrase, using
+// the KDF selected by the crypto policy, e.g. to hand the remote share to its
+// custodian.
+func EncryptArmorKeyShare(share KeyShare, passphrase string) (string, error) {
+	if err := share.Validate(); err != nil {
+		return "", err
+	}
+	kdf, err := PolicyKDF()
+	if err != nil {
+		return "", err
+	}
+
+	bz, err := json.Marshal(share)
+	if err != nil {
+		return "", err
+	}
+	defer zero(bz)
+
+	saltBytes := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, saltBytes); err != nil {
+		return "", err
+	}
+	key, err := kdf.DeriveKey([]byte(passphrase), saltBytes)
+	if err != nil {
+		return "", sdkerrors.Wrapf(err, "error generating %s key from passphrase", kdf.Name())
+	}
+	defer zero(key)
+
+	cipherName, encBytes, err := EncryptSymmetric(bz, key)
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]string{
+		headerVersion: keyShareVersion,
+		headerKDF:     kdf.Name(),
+		headerSalt:    fmt.Sprintf("%X", saltBytes),
+	}
+	if params := kdf.Params(); params != "" {
+		header[headerKDFParams] = params
+	}
+	setCipherHeader(header, cipherName)
+	return EncodeArmor(blockTypeKeyShare, header, encBytes), nil
+}
+
+// UnarmorDecryptKeyShare decrypts a key share armored by EncryptArmorKeyShare.
+func UnarmorDecryptKeyShare(armorStr, passphrase string) (KeyShare, error) {
+	encBytes, header, err := unarmorBytes(armorStr, blockTypeKeyShare)
+	if err != nil {
+		return KeyShare{}, err
+	}
+	if header[headerVersion] != keyShareVersion {
+		return KeyShare{}, fmt.Errorf("unsupported key share armor version %q", header[headerVersion])
+	}
+
+	kdf, err := GetKDF(header[headerKDF], header[headerKDFParams])
+	if err != nil {
+		return KeyShare{}, err
+	}
+	if err := GetPolicy().CheckKDF(kdf); err != nil {
+		return KeyShare{}, err
+	}
+	if err := checkFIPSCipher(armorCipher(header)); err != nil {
+		return KeyShare{}, err
+	}
+
+	saltBytes, err := hex.DecodeString(header[headerSalt])
+	if err != nil {
+		return KeyShare{}, fmt.Errorf("error decoding salt: %v", err.Error())
+	}
+	key, err := kdf.DeriveKey([]byte(passphrase), saltBytes)
+	if err != nil {
+		return KeyShare{}, sdkerrors.Wrapf(err, "error generating %s key from passphrase", kdf.Name())
+	}
+	defer zero(key)
+
+	bz, err := DecryptSymmetric(header[headerCipher], encBytes, key)
+	if err != nil {
+		return KeyShare{}, sdkerrors.ErrWrongPassword
+	}
+	defer zero(bz)
+
+	var share KeyShare
+	if err := json.Unmarshal(bz, &share); err != nil {
+		return KeyShare{}, fmt.Errorf("invalid key share: %w", err)
+	}
+	return share, share.Validate()
+}
+
+// randomScalar returns a uniformly random non-zero scalar modulo the
+// secp256k1 curve order.
+func randomScalar(r io.Reader) (btcec.ModNScalar, error) {
+	var s btcec.ModNScalar
+	bz := make([]byte, secp256k1ScalarSize)
+	defer zero(bz)
+	for {
+		if _, err := io.ReadFull(r, bz); err != nil {
+			return s, err
+		}
+		if overflow := s.SetByteSlice(bz); !overflow && !s.IsZero() {
+			return s, nil
+		}
+	}
+}
+
+func parseScalarShare(bz []byte) (btcec.ModNScalar, error) {
+	var s btcec.ModNScalar
+	if len(bz) != secp256k1ScalarSize {
+		return s, fmt.Errorf("invalid key share size %d", len(bz))
+	}
+	if overflow := s.SetByteSlice(bz); overflow || s.IsZero() {
+		return s, fmt.Errorf("invalid key share")
+	}
+	return s, nil
+}
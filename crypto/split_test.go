@@ -0,0 +1,67 @@
+package crypto_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+func TestSplitPrivKey(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	local, remote, err := crypto.SplitPrivKey(privKey)
+	require.NoError(t, err)
+	require.Equal(t, uint32(crypto.KeyShareLocal), local.Index)
+	require.Equal(t, uint32(crypto.KeyShareRemote), remote.Index)
+	require.Equal(t, privKey.PubKey().Bytes(), local.PubKey)
+	require.NotEqual(t, privKey.Bytes(), local.Share)
+	require.NotEqual(t, privKey.Bytes(), remote.Share)
+
+	// both shares rebuild the key, in any order
+	combined, err := crypto.CombineKeyShares(remote, local)
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(combined))
+
+	_, err = crypto.CombineKeyShares(local)
+	require.ErrorContains(t, err, "expected 2 key shares")
+	_, err = crypto.CombineKeyShares(local, local)
+	require.ErrorContains(t, err, "duplicate key share")
+
+	// shares of different splits of the same key don't combine
+	_, otherRemote, err := crypto.SplitPrivKey(privKey)
+	require.NoError(t, err)
+	_, err = crypto.CombineKeyShares(local, otherRemote)
+	require.ErrorContains(t, err, "don't combine into the key")
+
+	_, _, err = crypto.SplitPrivKey(ed25519.GenPrivKey())
+	require.ErrorContains(t, err, "splitting ed25519 keys is not supported")
+}
+
+func TestThresholdDilithiumUnsupported(t *testing.T) {
+	local := crypto.KeyShare{Scheme: crypto.SplitSchemeDilithium3, Index: crypto.KeyShareLocal}
+	remote := local
+	remote.Index = crypto.KeyShareRemote
+	_, err := crypto.CombineKeyShares(local, remote)
+	require.True(t, errors.Is(err, crypto.ErrThresholdUnsupported))
+}
+
+func TestEncryptArmorKeyShare(t *testing.T) {
+	_, remote, err := crypto.SplitPrivKey(secp256k1.GenPrivKey())
+	require.NoError(t, err)
+
+	armored, err := crypto.EncryptArmorKeyShare(remote, "passphrase")
+	require.NoError(t, err)
+	require.Contains(t, armored, "BARON CHAIN KEY SHARE")
+
+	decrypted, err := crypto.UnarmorDecryptKeyShare(armored, "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, remote, decrypted)
+
+	_, err = crypto.UnarmorDecryptKeyShare(armored, "wrong")
+	require.ErrorIs(t, err, sdkerrors.ErrWrongPassword)
+}
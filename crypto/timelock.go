@@ -0,0 +1,295 @@
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/xsalsa20symmetric"
+)
+
+const (
+	blockTypePrivKeyTimelock = "BARON CHAIN TIMELOCKED PRIVATE KEY"
+	timelockVersion          = "1"
+
+	headerTimelockModulus    = "timelock-modulus"
+	headerTimelockBase       = "timelock-base"
+	headerTimelockSquarings  = "timelock-squarings"
+	headerTimelockUnlockTime = "timelock-unlock-time"
+
+	// timelockModulusBits is the size of the RSA modulus of the puzzles, whose
+	// factorization would let the puzzle be solved without the squarings.
+	timelockModulusBits = 2048
+	timelockKeyDomain   = "baron-chain timelock key:"
+
+	// the context is checked every timelockCheckInterval squarings
+	timelockCheckInterval = 1 << 16
+)
+
+// ErrTimelockCorrupted is returned when a time-lock puzzle was solved but its
+// solution doesn't decrypt the key, i.e. the puzzle or the key was altered.
+var ErrTimelockCorrupted = errors.New("time-lock puzzle solution doesn't decrypt the key")
+
+// TimelockParams are the parameters of the time-lock puzzle of a time-locked
+// key.
+type TimelockParams struct {
+	// Squarings is the number of sequential modular squarings needed to
+	// solve the puzzle. They can't be parallelized, so that the solving time
+	// only depends on the speed of a single core.
+	Squarings uint64
+	// UnlockTime is the time the key is expected to be unlocked at. It is only
+	// recorded for information, the puzzle doesn't depend on it.
+	UnlockTime time.Time
+}
+
+// NewTimelockParams returns the parameters of a puzzle taking delay to solve
+// at the given rate of squarings per second, see MeasureTimelockSquaringRate.
+// The rate should be the one of the fastest hardware expected to solve the
+// puzzle, as it bounds the time the key stays locked.
+func NewTimelockParams(delay time.Duration, squaringsPerSecond uint64) TimelockParams {
+	squarings := uint64(delay.Seconds() * float64(squaringsPerSecond))
+	if squarings == 0 {
+		squarings = 1
+	}
+	return TimelockParams{Squarings: squarings, UnlockTime: time.Now().Add(delay).UTC()}
+}
+
+// MeasureTimelockSquaringRate returns the number of squarings per second this
+// machine solves time-lock puzzles at, measured for the given duration.
+func MeasureTimelockSquaringRate(d time.Duration) (uint64, error) {
+	// any odd modulus of the size of the puzzles' is as fast to square modulo
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), timelockModulusBits))
+	if err != nil {
+		return 0, err
+	}
+	n.SetBit(n, timelockModulusBits-1, 1).SetBit(n, 0, 1)
+	x := big.NewInt(3)
+
+	var squarings uint64
+	start := time.Now()
+	for time.Since(start) < d {
+		for i := 0; i < 1000; i++ {
+			x.Mul(x, x).Mod(x, n)
+		}
+		squarings += 1000
+	}
+	return uint64(float64(squarings) / time.Since(start).Seconds()), nil
+}
+
+// EncryptArmorPrivKeyTimelock encrypts a private key with a passphrase, as
+// EncryptArmorPrivKey, then wraps it with a Rivest-Shamir-Wagner time-lock
+// puzzle, so that escrowed keys can't be decrypted, even with the passphrase,
+// before the puzzle is solved by UnlockArmorPrivKeyTimelock. The key
+// algorithm and the KDF selected by the crypto policy must be allowed by it.
+func EncryptArmorPrivKeyTimelock(privKey cryptotypes.PrivKey, passphrase, algo string, params TimelockParams) (string, error) {
+	if params.Squarings == 0 {
+		return "", errors.New("time-lock puzzle must have at least one squaring")
+	}
+
+	kdf, err := PolicyKDF()
+	if err != nil {
+		return "", err
+	}
+	p := GetPolicy()
+	if err := p.CheckAlgo(privKey.Type()); err != nil {
+		return "", err
+	}
+	if err := p.CheckKDF(kdf); err != nil {
+		return "", err
+	}
+
+	saltBytes, cipherName, encBytes, err := encryptPrivKey(privKey, passphrase, kdf)
+	if err != nil {
+		return "", err
+	}
+
+	n, base, solution, err := newTimelockPuzzle(params.Squarings)
+	if err != nil {
+		return "", err
+	}
+	key := timelockKey(n, solution)
+	defer zero(key)
+
+	header := map[string]string{
+		headerVersion:           timelockVersion,
+		headerKDF:               kdf.Name(),
+		headerSalt:              fmt.Sprintf("%X", saltBytes),
+		headerTimelockModulus:   fmt.Sprintf("%X", n),
+		headerTimelockBase:      fmt.Sprintf("%X", base),
+		headerTimelockSquarings: strconv.FormatUint(params.Squarings, 10),
+	}
+	if kdfParams := kdf.Params(); kdfParams != "" {
+		header[headerKDFParams] = kdfParams
+	}
+	if algo != "" {
+		header[headerType] = algo
+	}
+	if !params.UnlockTime.IsZero() {
+		header[headerTimelockUnlockTime] = params.UnlockTime.UTC().Format(time.RFC3339)
+	}
+	// the puzzle layer is sealed with the cipher of the private key
+	setCipherHeader(header, cipherName)
+	var sealed []byte
+	if cipherName == CipherAES256GCM {
+		_, sealed, err = EncryptSymmetric(encBytes, key)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		sealed = xsalsa20symmetric.EncryptSymmetric(encBytes, key)
+	}
+	return EncodeArmor(blockTypePrivKeyTimelock, header, sealed), nil
+}
+
+// UnlockArmorPrivKeyTimelock solves the time-lock puzzle of a key armored by
+// EncryptArmorPrivKeyTimelock, which takes its number of sequential squarings,
+// and returns the key armored as by EncryptArmorPrivKey, to be decrypted with
+// its passphrase by UnarmorDecryptPrivKey. It returns the context error if
+// the context is done before the puzzle is solved.
+func UnlockArmorPrivKeyTimelock(ctx context.Context, armorStr string) (string, error) {
+	encBytes, header, err := unarmorBytes(armorStr, blockTypePrivKeyTimelock)
+	if err != nil {
+		return "", err
+	}
+	if header[headerVersion] != timelockVersion {
+		return "", fmt.Errorf("unsupported time-locked key armor version %q", header[headerVersion])
+	}
+
+	// checked before solving the puzzle, which is costly
+	if err := checkFIPSCipher(armorCipher(header)); err != nil {
+		return "", err
+	}
+	n, base, squarings, err := parseTimelockPuzzle(header)
+	if err != nil {
+		return "", err
+	}
+	solution, err := solveTimelockPuzzle(ctx, n, base, squarings)
+	if err != nil {
+		return "", err
+	}
+	key := timelockKey(n, solution)
+	defer zero(key)
+
+	privKeyEnc, err := DecryptSymmetric(header[headerCipher], encBytes, key)
+	if err != nil {
+		return "", ErrTimelockCorrupted
+	}
+
+	privKeyHeader := map[string]string{
+		headerKDF:  header[headerKDF],
+		headerSalt: header[headerSalt],
+	}
+	for _, h := range []string{headerKDFParams, headerType, headerCipher} {
+		if v, ok := header[h]; ok {
+			privKeyHeader[h] = v
+		}
+	}
+	return EncodeArmor(blockTypePrivKey, privKeyHeader, privKeyEnc), nil
+}
+
+// UnarmorDecryptPrivKeyTimelock solves the time-lock puzzle of a key armored by
+// EncryptArmorPrivKeyTimelock, and decrypts it with its passphrase.
+func UnarmorDecryptPrivKeyTimelock(ctx context.Context, armorStr, passphrase string) (cryptotypes.PrivKey, string, error) {
+	armored, err := UnlockArmorPrivKeyTimelock(ctx, armorStr)
+	if err != nil {
+		return nil, "", err
+	}
+	return UnarmorDecryptPrivKey(armored, passphrase)
+}
+
+// TimelockParamsOf returns the parameters of the time-lock puzzle of a key
+// armored by EncryptArmorPrivKeyTimelock, without solving it.
+func TimelockParamsOf(armorStr string) (TimelockParams, error) {
+	_, header, err := unarmorBytes(armorStr, blockTypePrivKeyTimelock)
+	if err != nil {
+		return TimelockParams{}, err
+	}
+	_, _, squarings, err := parseTimelockPuzzle(header)
+	if err != nil {
+		return TimelockParams{}, err
+	}
+
+	params := TimelockParams{Squarings: squarings}
+	if s := header[headerTimelockUnlockTime]; s != "" {
+		if params.UnlockTime, err = time.Parse(time.RFC3339, s); err != nil {
+			return TimelockParams{}, fmt.Errorf("invalid time-lock unlock time: %w", err)
+		}
+	}
+	return params, nil
+}
+
+// newTimelockPuzzle returns the modulus n = p*q and base of a new puzzle, with
+// its solution base^(2^squarings) mod n, computed with the shortcut the
+// factorization of n gives: the exponent is reduced modulo phi(n).
+func newTimelockPuzzle(squarings uint64) (n, base, solution *big.Int, err error) {
+	var p, q *big.Int
+	for p == nil || p.Cmp(q) == 0 {
+		if p, err = rand.Prime(rand.Reader, timelockModulusBits/2); err != nil {
+			return nil, nil, nil, err
+		}
+		if q, err = rand.Prime(rand.Reader, timelockModulusBits/2); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	one := big.NewInt(1)
+	n = new(big.Int).Mul(p, q)
+	phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+
+	// base in [2, n-2]
+	base, err = rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(3)))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	base.Add(base, big.NewInt(2))
+
+	exp := new(big.Int).Exp(big.NewInt(2), new(big.Int).SetUint64(squarings), phi)
+	solution = new(big.Int).Exp(base, exp, n)
+	return n, base, solution, nil
+}
+
+func parseTimelockPuzzle(header map[string]string) (n, base *big.Int, squarings uint64, err error) {
+	n, ok := new(big.Int).SetString(header[headerTimelockModulus], 16)
+	if !ok || n.BitLen() < timelockModulusBits {
+		return nil, nil, 0, fmt.Errorf("invalid time-lock modulus, expected at least %d bits", timelockModulusBits)
+	}
+	base, ok = new(big.Int).SetString(header[headerTimelockBase], 16)
+	if !ok || base.Cmp(big.NewInt(1)) <= 0 || base.Cmp(n) >= 0 {
+		return nil, nil, 0, errors.New("invalid time-lock base")
+	}
+	squarings, err = strconv.ParseUint(header[headerTimelockSquarings], 10, 64)
+	if err != nil || squarings == 0 {
+		return nil, nil, 0, fmt.Errorf("invalid time-lock squarings %q", header[headerTimelockSquarings])
+	}
+	return n, base, squarings, nil
+}
+
+// solveTimelockPuzzle computes base^(2^squarings) mod n by sequential
+// squarings.
+func solveTimelockPuzzle(ctx context.Context, n, base *big.Int, squarings uint64) (*big.Int, error) {
+	x := new(big.Int).Set(base)
+	for i := uint64(0); i < squarings; i++ {
+		if i%timelockCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("time-lock puzzle solved up to squaring %d of %d: %w", i, squarings, err)
+			}
+		}
+		x.Mul(x, x).Mod(x, n)
+	}
+	return x, nil
+}
+
+// timelockKey derives the key encrypting a time-locked key from the solution
+// of its puzzle.
+func timelockKey(n, solution *big.Int) []byte {
+	h := sha256.New()
+	h.Write([]byte(timelockKeyDomain))
+	h.Write(solution.FillBytes(make([]byte, (n.BitLen()+7)/8)))
+	return h.Sum(nil)
+}
@@ -0,0 +1,68 @@
+package crypto_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+func TestEncryptArmorPrivKeyTimelock(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	unlockTime := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := crypto.TimelockParams{Squarings: 5000, UnlockTime: unlockTime}
+
+	armored, err := crypto.EncryptArmorPrivKeyTimelock(privKey, testPassphrase, "", params)
+	require.NoError(t, err)
+	require.Contains(t, armored, "BARON CHAIN TIMELOCKED PRIVATE KEY")
+
+	got, err := crypto.TimelockParamsOf(armored)
+	require.NoError(t, err)
+	require.Equal(t, params, got)
+
+	// the passphrase alone doesn't decrypt the key
+	_, _, err = crypto.UnarmorDecryptPrivKey(armored, testPassphrase)
+	require.ErrorContains(t, err, "unrecognized armor type")
+
+	unlocked, err := crypto.UnlockArmorPrivKeyTimelock(context.Background(), armored)
+	require.NoError(t, err)
+	decrypted, algo, err := crypto.UnarmorDecryptPrivKey(unlocked, testPassphrase)
+	require.NoError(t, err)
+	require.Equal(t, "secp256k1", algo)
+	require.True(t, privKey.Equals(decrypted))
+
+	decrypted, _, err = crypto.UnarmorDecryptPrivKeyTimelock(context.Background(), armored, testPassphrase)
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(decrypted))
+	_, _, err = crypto.UnarmorDecryptPrivKeyTimelock(context.Background(), armored, "wrong")
+	require.Error(t, err)
+
+	// solving a puzzle with fewer squarings doesn't unlock the key
+	tampered := strings.Replace(armored, "timelock-squarings: 5000", "timelock-squarings: 4999", 1)
+	require.NotEqual(t, armored, tampered)
+	_, err = crypto.UnlockArmorPrivKeyTimelock(context.Background(), tampered)
+	require.ErrorIs(t, err, crypto.ErrTimelockCorrupted)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = crypto.UnlockArmorPrivKeyTimelock(ctx, armored)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = crypto.EncryptArmorPrivKeyTimelock(privKey, testPassphrase, "", crypto.TimelockParams{})
+	require.Error(t, err)
+}
+
+func TestNewTimelockParams(t *testing.T) {
+	rate, err := crypto.MeasureTimelockSquaringRate(50 * time.Millisecond)
+	require.NoError(t, err)
+	require.Greater(t, rate, uint64(0))
+
+	params := crypto.NewTimelockParams(time.Hour, 1000)
+	require.Equal(t, uint64(3600000), params.Squarings)
+	require.WithinDuration(t, time.Now().Add(time.Hour), params.UnlockTime, time.Minute)
+}
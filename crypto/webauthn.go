@@ -0,0 +1,203 @@
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cosmos/cosmos-sdk/crypto/xsalsa20symmetric"
+)
+
+const (
+	blockTypePrivKeyWebAuthn = "BARON CHAIN WEBAUTHN WRAPPED PRIVATE KEY"
+	webAuthnVersion          = "1"
+
+	headerWebAuthnRPID         = "webauthn-rp-id"
+	headerWebAuthnCredentialID = "webauthn-credential-id"
+	headerWebAuthnPRFSalt      = "webauthn-prf-salt"
+
+	webAuthnPRFSaltSize = 32
+	webAuthnKeyDomain   = "baron-chain webauthn key:"
+)
+
+// ErrWebAuthnUnwrap is returned when the secret of a WebAuthn credential
+// doesn't decrypt a wrapped key, i.e. another credential was used, or the
+// wrapped key was altered.
+var ErrWebAuthnUnwrap = errors.New("WebAuthn credential secret doesn't decrypt the key")
+
+// WebAuthnAuthenticator creates WebAuthn credentials and evaluates their PRF
+// extension, e.g. through a browser talking to a platform authenticator. The
+// authenticator must check the presence of the user before releasing a PRF
+// output, so that the keys wrapped with it can't be unwrapped unattended.
+type WebAuthnAuthenticator interface {
+	// Register creates a credential of the relying party rpID supporting the
+	// PRF extension, and returns its ID.
+	Register(ctx context.Context, rpID, userName string) (credentialID []byte, err error)
+	// PRF returns the 32 bytes output of the PRF extension of the credential
+	// for the salt, after a user presence check.
+	PRF(ctx context.Context, rpID string, credentialID, salt []byte) ([]byte, error)
+}
+
+// WebAuthnCredential identifies the WebAuthn credential a key is wrapped with.
+type WebAuthnCredential struct {
+	RPID string
+	ID   []byte
+}
+
+// WrapArmorPrivKeyWebAuthn wraps a private key armored by EncryptArmorPrivKey
+// with the PRF output of a WebAuthn credential, so that decrypting it requires
+// both its passphrase and a user presence check by the authenticator holding
+// the credential, see UnwrapArmorPrivKeyWebAuthn.
+func WrapArmorPrivKeyWebAuthn(ctx context.Context, armorStr string, authenticator WebAuthnAuthenticator, credential WebAuthnCredential) (string, error) {
+	encBytes, header, err := unarmorBytes(armorStr, blockTypePrivKey)
+	if err != nil {
+		return "", err
+	}
+	if err := validatePrivKeyHeader(blockTypePrivKey, header); err != nil {
+		return "", err
+	}
+	if credential.RPID == "" || len(credential.ID) == 0 {
+		return "", errors.New("WebAuthn credential must have a relying party ID and a credential ID")
+	}
+
+	salt := make([]byte, webAuthnPRFSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", err
+	}
+	key, err := webAuthnKey(ctx, authenticator, credential, salt)
+	if err != nil {
+		return "", err
+	}
+	defer zero(key)
+
+	// the credential layer is sealed with the cipher of the private key
+	cipherName := armorCipher(header)
+	if err := checkFIPSCipher(cipherName); err != nil {
+		return "", err
+	}
+	var sealed []byte
+	if cipherName == CipherAES256GCM {
+		aead, err := newAESGCM(key)
+		if err != nil {
+			return "", err
+		}
+		nonce := make([]byte, gcmNonceSize, gcmNonceSize+len(encBytes)+aead.Overhead())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return "", err
+		}
+		sealed = aead.Seal(nonce, nonce, encBytes, nil)
+	} else {
+		sealed = xsalsa20symmetric.EncryptSymmetric(encBytes, key)
+	}
+
+	wrappedHeader := map[string]string{
+		headerVersion:              webAuthnVersion,
+		headerWebAuthnRPID:         credential.RPID,
+		headerWebAuthnCredentialID: base64.RawURLEncoding.EncodeToString(credential.ID),
+		headerWebAuthnPRFSalt:      fmt.Sprintf("%X", salt),
+	}
+	for _, h := range []string{headerKDF, headerSalt, headerKDFParams, headerType, headerCipher} {
+		if v, ok := header[h]; ok {
+			wrappedHeader[h] = v
+		}
+	}
+	return EncodeArmor(blockTypePrivKeyWebAuthn, wrappedHeader, sealed), nil
+}
+
+// UnwrapArmorPrivKeyWebAuthn unwraps a key armored by WrapArmorPrivKeyWebAuthn
+// with the PRF output of its credential, and returns the key armored as by
+// EncryptArmorPrivKey, to be decrypted with its passphrase by
+// UnarmorDecryptPrivKey.
+func UnwrapArmorPrivKeyWebAuthn(ctx context.Context, armorStr string, authenticator WebAuthnAuthenticator) (string, error) {
+	encBytes, header, err := unarmorBytes(armorStr, blockTypePrivKeyWebAuthn)
+	if err != nil {
+		return "", err
+	}
+	if header[headerVersion] != webAuthnVersion {
+		return "", fmt.Errorf("unsupported WebAuthn wrapped key armor version %q", header[headerVersion])
+	}
+
+	// checked before the user is asked for their presence
+	if err := checkFIPSCipher(armorCipher(header)); err != nil {
+		return "", err
+	}
+	credential, salt, err := parseWebAuthnHeader(header)
+	if err != nil {
+		return "", err
+	}
+	key, err := webAuthnKey(ctx, authenticator, credential, salt)
+	if err != nil {
+		return "", err
+	}
+	defer zero(key)
+
+	privKeyEnc, err := DecryptSymmetric(header[headerCipher], encBytes, key)
+	if err != nil {
+		return "", ErrWebAuthnUnwrap
+	}
+
+	privKeyHeader := map[string]string{
+		headerKDF:  header[headerKDF],
+		headerSalt: header[headerSalt],
+	}
+	for _, h := range []string{headerKDFParams, headerType, headerCipher} {
+		if v, ok := header[h]; ok {
+			privKeyHeader[h] = v
+		}
+	}
+	return EncodeArmor(blockTypePrivKey, privKeyHeader, privKeyEnc), nil
+}
+
+// WebAuthnCredentialOf returns the WebAuthn credential a key armored by
+// WrapArmorPrivKeyWebAuthn is wrapped with, or false if the armor isn't a
+// WebAuthn wrapped key.
+func WebAuthnCredentialOf(armorStr string) (WebAuthnCredential, bool) {
+	_, header, err := unarmorBytes(armorStr, blockTypePrivKeyWebAuthn)
+	if err != nil {
+		return WebAuthnCredential{}, false
+	}
+	credential, _, err := parseWebAuthnHeader(header)
+	return credential, err == nil
+}
+
+func parseWebAuthnHeader(header map[string]string) (WebAuthnCredential, []byte, error) {
+	credential := WebAuthnCredential{RPID: header[headerWebAuthnRPID]}
+	if credential.RPID == "" {
+		return WebAuthnCredential{}, nil, errors.New("missing WebAuthn relying party ID")
+	}
+	id, err := base64.RawURLEncoding.DecodeString(header[headerWebAuthnCredentialID])
+	if err != nil || len(id) == 0 {
+		return WebAuthnCredential{}, nil, errors.New("invalid WebAuthn credential ID")
+	}
+	credential.ID = id
+
+	salt, err := hex.DecodeString(header[headerWebAuthnPRFSalt])
+	if err != nil || len(salt) != webAuthnPRFSaltSize {
+		return WebAuthnCredential{}, nil, errors.New("invalid WebAuthn PRF salt")
+	}
+	return credential, salt, nil
+}
+
+// webAuthnKey derives the key wrapping a key from the PRF output of its
+// credential.
+func webAuthnKey(ctx context.Context, authenticator WebAuthnAuthenticator, credential WebAuthnCredential, salt []byte) ([]byte, error) {
+	secret, err := authenticator.PRF(ctx, credential.RPID, credential.ID, salt)
+	if err != nil {
+		return nil, fmt.Errorf("WebAuthn assertion failed: %w", err)
+	}
+	defer zero(secret)
+	if len(secret) != KDFKeySize {
+		return nil, fmt.Errorf("WebAuthn PRF output must be %d bytes long, got %d", KDFKeySize, len(secret))
+	}
+
+	h := sha256.New()
+	h.Write([]byte(webAuthnKeyDomain))
+	h.Write([]byte(credential.RPID))
+	h.Write(secret)
+	return h.Sum(nil), nil
+}
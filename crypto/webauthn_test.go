@@ -0,0 +1,83 @@
+package crypto_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+// fakeAuthenticator evaluates the PRF of its credentials as an HMAC of the
+// salt keyed by the credential ID and its secret, like the hmac-secret
+// extension of a security key.
+type fakeAuthenticator struct {
+	secret  []byte
+	absent  bool
+	queries int
+}
+
+func (a *fakeAuthenticator) Register(_ context.Context, rpID, userName string) ([]byte, error) {
+	return []byte(rpID + "/" + userName), nil
+}
+
+func (a *fakeAuthenticator) PRF(_ context.Context, _ string, credentialID, salt []byte) ([]byte, error) {
+	a.queries++
+	if a.absent {
+		return nil, errors.New("user presence check timed out")
+	}
+	mac := hmac.New(sha256.New, append(append([]byte{}, a.secret...), credentialID...))
+	mac.Write(salt)
+	return mac.Sum(nil), nil
+}
+
+func TestWrapArmorPrivKeyWebAuthn(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	armored, err := crypto.EncryptArmorPrivKey(privKey, testPassphrase, "")
+	require.NoError(t, err)
+
+	authenticator := &fakeAuthenticator{secret: []byte("platform authenticator")}
+	id, err := authenticator.Register(context.Background(), "localhost", "validator")
+	require.NoError(t, err)
+	credential := crypto.WebAuthnCredential{RPID: "localhost", ID: id}
+
+	wrapped, err := crypto.WrapArmorPrivKeyWebAuthn(context.Background(), armored, authenticator, credential)
+	require.NoError(t, err)
+	require.Contains(t, wrapped, "BARON CHAIN WEBAUTHN WRAPPED PRIVATE KEY")
+	require.Equal(t, 1, authenticator.queries)
+
+	got, ok := crypto.WebAuthnCredentialOf(wrapped)
+	require.True(t, ok)
+	require.Equal(t, credential, got)
+	_, ok = crypto.WebAuthnCredentialOf(armored)
+	require.False(t, ok)
+
+	// the passphrase alone doesn't decrypt the key
+	_, _, err = crypto.UnarmorDecryptPrivKey(wrapped, testPassphrase)
+	require.ErrorContains(t, err, "unrecognized armor type")
+
+	unwrapped, err := crypto.UnwrapArmorPrivKeyWebAuthn(context.Background(), wrapped, authenticator)
+	require.NoError(t, err)
+	decrypted, algo, err := crypto.UnarmorDecryptPrivKey(unwrapped, testPassphrase)
+	require.NoError(t, err)
+	require.Equal(t, "secp256k1", algo)
+	require.True(t, privKey.Equals(decrypted))
+
+	// another authenticator doesn't hold the credential secret
+	other := &fakeAuthenticator{secret: []byte("another authenticator")}
+	_, err = crypto.UnwrapArmorPrivKeyWebAuthn(context.Background(), wrapped, other)
+	require.ErrorIs(t, err, crypto.ErrWebAuthnUnwrap)
+
+	// the key isn't unwrapped without the user presence check
+	authenticator.absent = true
+	_, err = crypto.UnwrapArmorPrivKeyWebAuthn(context.Background(), wrapped, authenticator)
+	require.ErrorContains(t, err, "user presence check timed out")
+
+	_, err = crypto.WrapArmorPrivKeyWebAuthn(context.Background(), armored, authenticator, crypto.WebAuthnCredential{RPID: "localhost"})
+	require.ErrorContains(t, err, "credential ID")
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"cosmossdk.io/depinject"
+	"github.com/pkg/errors"
 	"github.com/regen-network/gocuke"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -59,6 +60,19 @@ func TestBindInterface(t *testing.T) {
 		Run()
 }
 
+func TestBindInterfaceSuggestions(t *testing.T) {
+	var duck Duck
+	err := depinject.Inject(
+		depinject.Configs(
+			depinject.Provide(ProvideMallard),
+			depinject.BindInterface(pkgPath+".Duck", pkgPath+".Malard"),
+		),
+		&duck,
+	)
+	require.True(t, depinject.IsNoTypeForBindingError(errors.Cause(err)))
+	require.ErrorContains(t, err, "Did you mean:\n    "+pkgPath+".Mallard")
+}
+
 // Provider Functions
 
 func ProvideMallard() Mallard       { return Mallard{} }
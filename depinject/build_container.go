@@ -0,0 +1,81 @@
+package depinject
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Container is a built container, whose resolved values are cached so that
+// they can be extracted several times with Resolve, see BuildContainer. It can
+// be rebuilt with another config with Reload.
+type Container struct {
+	mtx      sync.Mutex
+	ctr      *container
+	config   Config
+	debugOpt DebugOption
+}
+
+// BuildContainer builds the container of config, calling its providers and
+// invokers as Inject does, and returns it. Test suites injecting many times
+// with the same config can then resolve their outputs from the container
+// instead of rebuilding it for each Inject call:
+//
+//	ctr, err := depinject.BuildContainer(appConfig)
+//	...
+//	var bankKeeper bankkeeper.Keeper
+//	err = ctr.Resolve(&bankKeeper)
+//
+// Like Inject, it uses AutoDebug mode.
+func BuildContainer(config Config) (*Container, error) {
+	return BuildContainerDebug(AutoDebug(), config)
+}
+
+// BuildContainerDebug is like BuildContainer but with configurable debug
+// options.
+func BuildContainerDebug(debugOpt DebugOption, config Config) (*Container, error) {
+	ctr, err := runInjection(InjectionOptions{
+		location:    LocationFromCaller(1),
+		debugOpt:    debugOpt,
+		config:      config,
+		recordCalls: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Container{ctr: ctr, config: config, debugOpt: debugOpt}, nil
+}
+
+// Resolve fills the outputs, which must be pointers, with the values of the
+// container. The providers of the types already resolved, by the build of the
+// container or by a previous call of Resolve, are not called again. It is
+// safe for concurrent use.
+func (c *Container) Resolve(outputs ...interface{}) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	loc := LocationFromCaller(1)
+	for _, output := range outputs {
+		val := reflect.ValueOf(output)
+		if val.Kind() != reflect.Pointer || val.IsNil() {
+			return errors.Wrapf(ErrInvalidOutputType, "%T is invalid", output)
+		}
+
+		typ := val.Type().Elem()
+		r, err := c.ctr.getResolver(typ, nil)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			return errors.WithStack(newErrTypeNotResolvable(typ, loc, c.ctr.suggestTypes(typ)))
+		}
+
+		value, err := r.resolve(c.ctr, nil, loc)
+		if err != nil {
+			return errors.Wrapf(err, "resolving %v", typ)
+		}
+		val.Elem().Set(value)
+	}
+	return nil
+}
@@ -28,20 +28,25 @@ func (c containerConfig) apply(ctr *container) error {
 // - Must be exported functions from non-internal packages
 // - Must have exported input/output types from non-internal packages
 // - Should have exported generic type parameters (not checked)
+//
+// A ProviderDescriptor may be passed in place of a function to list the
+// provider inputs and outputs explicitly.
 func Provide(providers ...interface{}) Config {
+	loc := LocationFromCaller(1)
 	return containerConfig(func(ctr *container) error {
-		return provide(ctr, nil, providers)
+		return provide(ctr, nil, providers, loc)
 	})
 }
 
 // ProvideInModule registers dependency injection providers in a specific module scope.
 // See Provide for provider requirements.
 func ProvideInModule(moduleName string, providers ...interface{}) Config {
+	loc := LocationFromCaller(1)
 	return containerConfig(func(ctr *container) error {
 		if moduleName == "" {
 			return ErrEmptyModuleName
 		}
-		return provide(ctr, ctr.moduleKeyContext.createOrGetModuleKey(moduleName), providers)
+		return provide(ctr, ctr.moduleKeyContext.createOrGetModuleKey(moduleName), providers, loc)
 	})
 }
 
@@ -54,20 +59,24 @@ func ProvideInModule(moduleName string, providers ...interface{}) Config {
 // - Must be exported functions from non-internal packages
 // - Must have exported input types from non-internal packages
 // - Should have exported generic type parameters (not checked)
+//
+// A ProviderDescriptor without outputs may be passed in place of a function.
 func Invoke(invokers ...interface{}) Config {
+	loc := LocationFromCaller(1)
 	return containerConfig(func(ctr *container) error {
-		return invoke(ctr, nil, invokers)
+		return invoke(ctr, nil, invokers, loc)
 	})
 }
 
 // InvokeInModule registers invoker functions to run in a specific module scope.
 // See Invoke for invoker requirements.
 func InvokeInModule(moduleName string, invokers ...interface{}) Config {
+	loc := LocationFromCaller(1)
 	return containerConfig(func(ctr *container) error {
 		if moduleName == "" {
 			return ErrEmptyModuleName
 		}
-		return invoke(ctr, ctr.moduleKeyContext.createOrGetModuleKey(moduleName), invokers)
+		return invoke(ctr, ctr.moduleKeyContext.createOrGetModuleKey(moduleName), invokers, loc)
 	})
 }
 
@@ -103,14 +112,31 @@ func Supply(values ...interface{}) Config {
 	loc := LocationFromCaller(1)
 	return containerConfig(func(ctr *container) error {
 		for _, v := range values {
-			if err := ctr.supply(reflect.ValueOf(v), loc); err != nil {
-				return errors.WithStack(err)
+			if err := supply(ctr, reflect.ValueOf(v), loc); err != nil {
+				return err
 			}
 		}
 		return nil
 	})
 }
 
+// SupplyAs registers a value into the container as a value of type T, which is
+// usually an interface the value implements. It saves a BindInterface and a
+// provider of the concrete type when wiring test doubles:
+//
+//	SupplyAs[bank.Keeper](mockBankKeeper)
+func SupplyAs[T any](value T) Config {
+	loc := LocationFromCaller(1)
+	return containerConfig(func(ctr *container) error {
+		// the element of a pointer to value has the static type T
+		v := reflect.ValueOf(&value).Elem()
+		if v.Kind() == reflect.Interface && v.IsNil() {
+			return errors.Errorf("cannot supply a nil %v", v.Type())
+		}
+		return supply(ctr, v, loc)
+	})
+}
+
 // Error registers an error that will cause container initialization to fail
 func Error(err error) Config {
 	return containerConfig(func(*container) error {
@@ -132,12 +158,43 @@ func Configs(configs ...Config) Config {
 
 // Helper functions
 
-func provide(ctr *container, key *moduleKey, providers []interface{}) error {
-	for _, provider := range providers {
-		desc, err := extractProviderDescriptor(provider)
+func supply(ctr *container, value reflect.Value, loc Location) error {
+	if ctr.recorder != nil {
+		return errors.WithStack(ctr.recorder.recordSupply(value))
+	}
+	return errors.WithStack(ctr.supply(value, loc))
+}
+
+func provide(ctr *container, key *moduleKey, providers []interface{}, loc Location) error {
+	return provideShared(ctr, key, providers, nil, loc)
+}
+
+// provideShared registers the providers, sharing the outputs of each one with
+// the other containers through the element of shared of the same index, if
+// shared isn't nil, see Singleton.
+func provideShared(ctr *container, key *moduleKey, providers []interface{}, shared []*sharedOutputs, loc Location) error {
+	for i, provider := range providers {
+		var (
+			desc providerDescriptor
+			err  error
+		)
+		if d, ok := provider.(ProviderDescriptor); ok {
+			desc, err = extractExplicitProviderDescriptor(d, loc)
+		} else {
+			desc, err = extractProviderDescriptor(provider)
+		}
 		if err != nil {
 			return errors.WithStack(err)
 		}
+		if shared != nil {
+			desc.shared = shared[i]
+		}
+		if ctr.recorder != nil {
+			if err = ctr.recorder.recordProvider(provider, &desc, key); err != nil {
+				return errors.WithStack(err)
+			}
+			continue
+		}
 		if _, err = ctr.addNode(&desc, key); err != nil {
 			return errors.WithStack(err)
 		}
@@ -145,12 +202,26 @@ func provide(ctr *container, key *moduleKey, providers []interface{}) error {
 	return nil
 }
 
-func invoke(ctr *container, key *moduleKey, invokers []interface{}) error {
+func invoke(ctr *container, key *moduleKey, invokers []interface{}, loc Location) error {
 	for _, invoker := range invokers {
-		desc, err := extractInvokerDescriptor(invoker)
+		var (
+			desc providerDescriptor
+			err  error
+		)
+		if d, ok := invoker.(ProviderDescriptor); ok {
+			desc, err = extractExplicitInvokerDescriptor(d, loc)
+		} else {
+			desc, err = extractInvokerDescriptor(invoker)
+		}
 		if err != nil {
 			return errors.WithStack(err)
 		}
+		if ctr.recorder != nil {
+			if err = ctr.recorder.recordInvoker(invoker, &desc, key); err != nil {
+				return errors.WithStack(err)
+			}
+			continue
+		}
 		if err = ctr.addInvoker(&desc, key); err != nil {
 			return errors.WithStack(err)
 		}
@@ -163,12 +234,17 @@ func bindInterface(ctr *container, inTypeName, outTypeName, moduleName string) e
 	if moduleName != "" {
 		mk = &moduleKey{name: moduleName}
 	}
-	
-	ctr.addBinding(interfaceBinding{
+
+	binding := interfaceBinding{
 		interfaceName: inTypeName,
 		implTypeName:  outTypeName,
 		moduleKey:     mk,
-	})
-	
+	}
+	if ctr.recorder != nil {
+		return ctr.recorder.recordBinding(binding)
+	}
+
+	ctr.addBinding(binding)
+
 	return nil
 }
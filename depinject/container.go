@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/pkg/errors"
 	"cosmossdk.io/depinject/internal/graphviz"
@@ -29,6 +30,39 @@ type container struct {
 	resolveStack      []resolveFrame
 	callerStack      []Location
 	callerMap        map[Location]bool
+
+	// moduleWhitelists are the fully-qualified names of the types each
+	// whitelisted module may consume, see ModuleWhitelist.
+	moduleWhitelists map[string]map[string]bool
+
+	// lazy is set by Lazy, for build to only resolve the requested outputs
+	// instead of the whole graph.
+	lazy bool
+
+	// recorder, when set, records the structure of the applied configs
+	// instead of registering them.
+	recorder configRecorder
+
+	// calls, when set, records the provider calls for Reload, which reuses
+	// the outputs of previousCalls whose inputs are unchanged.
+	calls         map[string]*providerCall
+	previousCalls map[string]*providerCall
+
+	// buildOutputs is the provider filling the outputs of build, which isn't
+	// profiled nor recorded.
+	buildOutputs *providerDescriptor
+
+	// teardowns are the hooks registered with Teardown.
+	teardowns []teardownHook
+}
+
+// configRecorder records the providers, invokers, bindings and supplied values
+// of configs, see BuildManifest and ExportConfig.
+type configRecorder interface {
+	recordProvider(provider interface{}, desc *providerDescriptor, key *moduleKey) error
+	recordInvoker(invoker interface{}, desc *providerDescriptor, key *moduleKey) error
+	recordBinding(b interfaceBinding) error
+	recordSupply(value reflect.Value) error
 }
 
 type (
@@ -66,6 +100,7 @@ func newContainer(cfg *debugConfig) *container {
 func (c *container) call(provider *providerDescriptor, moduleKey *moduleKey) ([]reflect.Value, error) {
 	loc := provider.Location
 	graphNode := c.locationGraphNode(loc, moduleKey)
+	c.setProviderTooltip(graphNode, provider)
 	markGraphNodeAsFailed(graphNode)
 
 	if err := c.checkCyclicDependency(loc); err != nil {
@@ -74,6 +109,17 @@ func (c *container) call(provider *providerDescriptor, moduleKey *moduleKey) ([]
 
 	c.pushCaller(loc)
 	defer c.popCaller(loc)
+	if provider != c.buildOutputs {
+		// the outputs of build aren't a provider, and don't root the stacks
+		defer c.profileCall(loc, moduleKey)()
+	}
+
+	if out, ok := provider.shared.get(moduleKey); ok {
+		c.logf("Reusing the outputs of the singleton %s", loc)
+		c.recordSharedCall(provider, moduleKey, out)
+		markGraphNodeAsUsed(graphNode)
+		return out, nil
+	}
 
 	c.logf("Resolving dependencies for %s", loc)
 	c.indentLogger()
@@ -83,11 +129,25 @@ func (c *container) call(provider *providerDescriptor, moduleKey *moduleKey) ([]
 		return nil, err
 	}
 
+	if out, ok := c.reuseCall(provider, moduleKey, inVals); ok {
+		c.logf("Reusing the outputs of %s, whose inputs are unchanged", loc)
+		markGraphNodeAsUsed(graphNode)
+		return out, nil
+	}
+
 	c.logf("Calling %s", loc)
 	out, err := provider.Fn(inVals)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error calling provider %s", loc)
 	}
+	out = provider.shared.store(moduleKey, out)
+	c.recordCall(provider, moduleKey, inVals, out)
+
+	if c.verifyPure {
+		if err := c.verifyPureCall(provider, inVals, out); err != nil {
+			return nil, err
+		}
+	}
 
 	markGraphNodeAsUsed(graphNode)
 	return out, nil
@@ -96,6 +156,9 @@ func (c *container) call(provider *providerDescriptor, moduleKey *moduleKey) ([]
 func (c *container) resolveInputs(inputs []providerInput, moduleKey *moduleKey, loc Location) ([]reflect.Value, error) {
 	inVals := make([]reflect.Value, len(inputs))
 	for i, in := range inputs {
+		if err := c.checkWhitelist(in.Type, moduleKey, loc); err != nil {
+			return nil, err
+		}
 		val, err := c.resolve(in, moduleKey, loc)
 		if err != nil {
 			return nil, err
@@ -105,11 +168,28 @@ func (c *container) resolveInputs(inputs []providerInput, moduleKey *moduleKey,
 	return inVals, nil
 }
 
+// checkCyclicDependency fails if loc is being called already, with the chain
+// of providers from its previous call, e.g. A -> B -> C -> A.
 func (c *container) checkCyclicDependency(loc Location) error {
-	if c.callerMap[loc] {
-		return errors.Wrapf(ErrCyclicDependency, "%s -> %s", loc.Name(), loc.Name())
+	if !c.callerMap[loc] {
+		return nil
 	}
-	return nil
+
+	start := 0
+	for i, caller := range c.callerStack {
+		if caller == loc {
+			start = i
+			break
+		}
+	}
+	var chain bytes.Buffer
+	for _, caller := range c.callerStack[start:] {
+		chain.WriteString(caller.Name())
+		chain.WriteString(" -> ")
+	}
+	chain.WriteString(loc.Name())
+	c.logf("Cyclic dependency: %s", chain.String())
+	return errors.Wrapf(ErrCyclicDependency, "%s", chain.String())
 }
 
 func (c *container) pushCaller(loc Location) {
@@ -122,6 +202,190 @@ func (c *container) popCaller(loc Location) {
 	c.callerStack = c.callerStack[:len(c.callerStack)-1]
 }
 
+func (c *container) resolve(in providerInput, moduleKey *moduleKey, caller Location) (reflect.Value, error) {
+	c.resolveStack = append(c.resolveStack, resolveFrame{loc: caller, typ: in.Type})
+	defer func() { c.resolveStack = c.resolveStack[:len(c.resolveStack)-1] }()
+
+	typeGraphNode := c.typeGraphNode(in.Type)
+
+	switch in.Type {
+	case moduleKeyType, ownModuleKeyType:
+		if moduleKey == nil {
+			return reflect.Value{}, errors.Wrapf(ErrModuleScopeRequired, "trying to resolve %v for %s", in.Type, caller)
+		}
+		c.logf("Providing %v %s", in.Type, moduleKey.name)
+		markGraphNodeAsUsed(typeGraphNode)
+		if in.Type == ownModuleKeyType {
+			return reflect.ValueOf(OwnModuleKey{moduleKey}), nil
+		}
+		return reflect.ValueOf(ModuleKey{moduleKey}), nil
+	}
+
+	vr, err := c.getResolver(in.Type, moduleKey)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if vr == nil {
+		if in.Optional {
+			c.logf("Providing zero value for optional dependency %v", in.Type)
+			return reflect.Zero(in.Type), nil
+		}
+
+		markGraphNodeAsFailed(typeGraphNode)
+		c.logf("Can't resolve %v, %s", in.Type, c.formatResolveStack())
+		return reflect.Value{}, errors.WithStack(newErrTypeNotResolvable(in.Type, caller, c.suggestTypes(in.Type)))
+	}
+
+	res, err := vr.resolve(c, moduleKey, caller)
+	if err != nil {
+		markGraphNodeAsFailed(typeGraphNode)
+		return reflect.Value{}, err
+	}
+
+	markGraphNodeAsUsed(typeGraphNode)
+	return res, nil
+}
+
+func (c *container) formatResolveStack() string {
+	var buf bytes.Buffer
+	buf.WriteString("while resolving:\n")
+	for i := len(c.resolveStack) - 1; i >= 0; i-- {
+		frame := c.resolveStack[i]
+		fmt.Fprintf(&buf, "\t%v for %s\n", frame.typ, frame.loc)
+	}
+	return buf.String()
+}
+
+func (c *container) supply(value reflect.Value, loc Location) error {
+	if !value.IsValid() {
+		return errors.Errorf("cannot supply a nil value: %s", loc)
+	}
+
+	typ := value.Type()
+	locGraphNode := c.locationGraphNode(loc, nil)
+	markGraphNodeAsUsed(locGraphNode)
+	typeGraphNode := c.typeGraphNode(typ)
+	c.addGraphEdge(locGraphNode, typeGraphNode)
+
+	if existing, ok := c.resolverByType(typ); ok {
+		return duplicateDefinitionError(typ, loc, existing.describeLocation())
+	}
+
+	c.addResolver(typ, supplyResolver{
+		typ:       typ,
+		value:     value,
+		loc:       loc,
+		graphNode: typeGraphNode,
+	})
+	return nil
+}
+
+func (c *container) addInvoker(provider *providerDescriptor, key *moduleKey) error {
+	if len(provider.Outputs) > 0 {
+		return errors.Wrapf(ErrInvalidInvoker, "%s", provider.Location)
+	}
+
+	c.invokers = append(c.invokers, invoker{
+		fn:     provider,
+		modKey: key,
+	})
+	return nil
+}
+
+// build resolves the outputs, which must be pointers, then, unless the
+// container is lazy, the values of all the providers which aren't
+// module-scoped, and finally calls the invokers.
+func (c *container) build(loc Location, outputs ...interface{}) error {
+	var providerIn []providerInput
+	for _, output := range outputs {
+		typ := reflect.TypeOf(output)
+		if typ == nil || typ.Kind() != reflect.Pointer {
+			return errors.Wrapf(ErrInvalidOutputType, "%v is invalid", typ)
+		}
+		providerIn = append(providerIn, providerInput{Type: typ.Elem()})
+	}
+
+	desc := providerDescriptor{
+		Inputs: providerIn,
+		Fn: func(values []reflect.Value) ([]reflect.Value, error) {
+			if len(values) != len(outputs) {
+				return nil, errors.New("internal error, unexpected number of values")
+			}
+
+			for i, output := range outputs {
+				if !values[i].CanInterface() {
+					return nil, errors.Errorf("depinject.Out struct %s can't have unexported fields", values[i].String())
+				}
+				reflect.ValueOf(output).Elem().Set(values[i])
+			}
+			return nil, nil
+		},
+		Location: loc,
+	}
+	c.locationGraphNode(loc, nil).SetShape("hexagon")
+
+	desc, err := expandStructArgsProvider(desc)
+	if err != nil {
+		return err
+	}
+
+	c.logf("Registering outputs")
+	c.indentLogger()
+	node, err := c.addNode(&desc, nil)
+	c.dedentLogger()
+	if err != nil {
+		return err
+	}
+
+	sn, ok := node.(*simpleProvider)
+	if !ok {
+		return errors.Errorf("cannot run module-scoped provider as an invoker")
+	}
+	c.buildOutputs = sn.provider
+
+	c.logf("Building container")
+	if _, err = sn.resolveValues(c); err != nil {
+		return err
+	}
+	if !c.lazy {
+		if err = c.resolveAll(loc); err != nil {
+			return err
+		}
+	}
+	c.logf("Done building container")
+
+	c.logf("Calling invokers")
+	for _, inv := range c.invokers {
+		if _, err := c.call(inv.fn, inv.modKey); err != nil {
+			return err
+		}
+	}
+	c.logf("Done calling invokers")
+
+	return nil
+}
+
+// resolveAll resolves the values of the providers which aren't module-scoped,
+// in the order of their type names, whether or not they are needed.
+func (c *container) resolveAll(loc Location) error {
+	names := make([]string, 0, len(c.resolvers))
+	for name := range c.resolvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch r := c.resolvers[name].(type) {
+		case *simpleResolver, *sliceGroupResolver, *mapOfOnePerModuleResolver:
+			if _, err := r.resolve(c, nil, loc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Resolver Management
 
 func (c *container) getResolver(typ reflect.Type, key *moduleKey) (resolver, error) {
@@ -133,12 +397,11 @@ func (c *container) getResolver(typ reflect.Type, key *moduleKey) (resolver, err
 		return r, nil
 	}
 
-	elemType := c.getElementType(typ)
-	if elemType == typ {
-		return c.resolveInterfaceType(typ)
+	if r, err := c.createContainerResolver(c.getElementType(typ), typ); err != nil || r != nil {
+		return r, err
 	}
 
-	return c.createContainerResolver(elemType, typ)
+	return c.resolveInterfaceType(typ)
 }
 
 func (c *container) getElementType(typ reflect.Type) reflect.Type {
@@ -148,6 +411,62 @@ func (c *container) getElementType(typ reflect.Type) reflect.Type {
 	return typ
 }
 
+func (c *container) getExplicitResolver(typ reflect.Type, key *moduleKey) (resolver, error) {
+	// module scoped binding takes precedence
+	binding, found := c.interfaceBindings[bindingKeyFromType(typ, key)]
+	if !found {
+		binding, found = c.interfaceBindings[bindingKeyFromType(typ, nil)]
+	}
+	if !found {
+		return nil, nil
+	}
+
+	if binding.resolver != nil {
+		return binding.resolver, nil
+	}
+
+	if res, ok := c.resolverByTypeName(binding.implTypeName); ok {
+		c.logf("Registering resolver %v for interface type %v by explicit binding", res.getType(), typ)
+		binding.resolver = res
+		return res, nil
+	}
+
+	return nil, newErrNoTypeForExplicitBindingFound(binding, c.suggestTypeNames(binding.implTypeName))
+}
+
+// createContainerResolver registers the resolvers of the many-per-container or
+// one-per-module type elemType and of its slice or map type, and returns the
+// resolver of typ, which is either of them.
+func (c *container) createContainerResolver(elemType, typ reflect.Type) (resolver, error) {
+	switch {
+	case isManyPerContainerType(elemType):
+		c.logf("Registering resolver for many-per-container type %v", elemType)
+		r := newGroupResolver(elemType)
+		r.graphNode = c.typeGraphNode(r.sliceType)
+		r.graphNode.SetComment("many-per-container")
+		c.addResolver(elemType, r)
+		c.addResolver(r.sliceType, newSliceGroupResolver(r))
+	case isOnePerModuleType(elemType):
+		c.logf("Registering resolver for one-per-module type %v", elemType)
+		mapType := reflect.MapOf(stringType, elemType)
+		r := &onePerModuleResolver{
+			typ:       elemType,
+			mapType:   mapType,
+			providers: map[*moduleKey]*simpleProvider{},
+			idxMap:    map[*moduleKey]int{},
+			graphNode: c.typeGraphNode(mapType),
+		}
+		r.graphNode.SetComment("one-per-module")
+		c.addResolver(elemType, r)
+		c.addResolver(mapType, &mapOfOnePerModuleResolver{r})
+	default:
+		return nil, nil
+	}
+
+	r, _ := c.resolverByType(typ)
+	return r, nil
+}
+
 func (c *container) resolveInterfaceType(typ reflect.Type) (resolver, error) {
 	if typ.Kind() != reflect.Interface {
 		return nil, nil
@@ -160,7 +479,9 @@ func (c *container) resolveInterfaceType(typ reflect.Type) (resolver, error) {
 	case 1:
 		for resolverType := range matches {
 			res, _ := c.resolverByType(resolverType)
-			c.logf("Implicitly registering resolver %v for interface type %v", resolverType, typ)
+			c.warn(WarningImplicitBinding, SeverityWarning,
+				"interface %v is implicitly bound to %v, its only provided implementation, bind it explicitly with BindInterface",
+				typ, resolverType)
 			c.addResolver(typ, res)
 			return res, nil
 		}
@@ -186,6 +507,7 @@ func (c *container) findImplementingTypes(interfaceType reflect.Type) map[reflec
 
 func (c *container) addNode(provider *providerDescriptor, key *moduleKey) (interface{}, error) {
 	providerGraphNode := c.locationGraphNode(provider.Location, key)
+	c.setProviderTooltip(providerGraphNode, provider)
 
 	if err := c.validateProviderInputs(provider, key); err != nil {
 		return nil, err
@@ -199,6 +521,109 @@ func (c *container) addNode(provider *providerDescriptor, key *moduleKey) (inter
 	return c.addModuleScopedNode(provider, providerGraphNode)
 }
 
+func (c *container) hasModuleKeyParam(provider *providerDescriptor) bool {
+	for _, in := range provider.Inputs {
+		if in.Type == moduleKeyType {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *container) addSimpleNode(provider *providerDescriptor, key *moduleKey, providerGraphNode *graphviz.Node) (interface{}, error) {
+	c.logf("Registering %s", provider.Location.String())
+	c.indentLogger()
+	defer c.dedentLogger()
+
+	sp := &simpleProvider{
+		provider:  provider,
+		moduleKey: key,
+	}
+
+	for i, out := range provider.Outputs {
+		typ := out.Type
+
+		// one-per-module maps can't be used as a return type
+		if isOnePerModuleMapType(typ) {
+			return nil, errors.Errorf("%v cannot be used as a return type because %v is a one-per-module type",
+				typ, typ.Elem())
+		}
+
+		// many-per-container slices of many-per-container types
+		if isManyPerContainerSliceType(typ) {
+			typ = typ.Elem()
+		}
+
+		vr, err := c.getResolver(typ, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if vr != nil {
+			c.logf("Found resolver for %v: %T", typ, vr)
+			if err := vr.addNode(sp, i); err != nil {
+				return nil, err
+			}
+		} else {
+			c.logf("Registering resolver for simple type %v", typ)
+			vr = &simpleResolver{
+				node:        sp,
+				typ:         typ,
+				graphNode:   c.typeGraphNode(typ),
+				idxInValues: i,
+			}
+			c.addResolver(typ, vr)
+		}
+
+		c.addGraphEdge(providerGraphNode, vr.typeGraphNode())
+	}
+
+	return sp, nil
+}
+
+func (c *container) addModuleScopedNode(provider *providerDescriptor, providerGraphNode *graphviz.Node) (interface{}, error) {
+	for _, in := range provider.Inputs {
+		if in.Type == ownModuleKeyType {
+			return nil, errors.Wrapf(ErrDuplicateModuleScope, "%v and %v must not both be inputs of %s",
+				moduleKeyType, ownModuleKeyType, provider.Location)
+		}
+	}
+
+	c.logf("Registering module-scoped provider: %s", provider.Location.String())
+	c.indentLogger()
+	defer c.dedentLogger()
+
+	node := &moduleDepProvider{
+		provider:        provider,
+		calledForModule: map[*moduleKey]bool{},
+		valueMap:        map[*moduleKey][]reflect.Value{},
+	}
+
+	for i, out := range provider.Outputs {
+		typ := out.Type
+
+		c.logf("Registering resolver for module-scoped type %v", typ)
+
+		if existing, ok := c.resolverByType(typ); ok {
+			return nil, errors.Errorf("duplicate provision of type %v by module-scoped provider %s\n\talready provided by %s",
+				typ, provider.Location, existing.describeLocation())
+		}
+
+		typeGraphNode := c.typeGraphNode(typ)
+		c.addResolver(typ, &moduleDepResolver{
+			typ:         typ,
+			idxInValues: i,
+			node:        node,
+			valueMap:    map[*moduleKey]reflect.Value{},
+			graphNode:   typeGraphNode,
+		})
+
+		c.addGraphEdge(providerGraphNode, typeGraphNode)
+	}
+
+	return node, nil
+}
+
 func (c *container) validateProviderInputs(provider *providerDescriptor, key *moduleKey) error {
 	for _, in := range provider.Inputs {
 		if err := c.validateInput(in.Type, key); err != nil {
@@ -212,6 +637,18 @@ func (c *container) validateProviderInputs(provider *providerDescriptor, key *mo
 	return nil
 }
 
+func (c *container) validateInput(typ reflect.Type, _ *moduleKey) error {
+	if isManyPerContainerType(typ) {
+		return errors.Wrapf(ErrInvalidManyPerContainerType, "%v can't be used as an input parameter, use %v instead",
+			typ, reflect.SliceOf(typ))
+	}
+	if isOnePerModuleType(typ) {
+		return errors.Errorf("one-per-module type %v can't be used as an input parameter, use %v instead",
+			typ, reflect.MapOf(stringType, typ))
+	}
+	return nil
+}
+
 func (c *container) addInputTypeToGraph(typ reflect.Type, provider *providerDescriptor, key *moduleKey) error {
 	vr, err := c.getResolver(typ, key)
 	if err != nil {
@@ -269,6 +706,7 @@ func bindingKeyFromType(typ reflect.Type, key *moduleKey) string {
 }
 
 func (c *container) addBinding(p interfaceBinding) {
+	c.warnShadowedBinding(p)
 	c.interfaceBindings[bindingKeyFromTypeName(p.interfaceName, p.moduleKey)] = p
 }
 
@@ -1,12 +1,16 @@
 package depinject_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
-	"gotest.tools/v3/golden"
 
 	"cosmossdk.io/depinject"
 )
@@ -203,6 +207,154 @@ func TestResolutionErrors(t *testing.T) {
 	}
 }
 
+type (
+	CycleA struct{}
+	CycleB struct{}
+	CycleC struct{}
+)
+
+func ProvideCycleA(CycleB) CycleA { return CycleA{} }
+
+func ProvideCycleB(CycleC) CycleB { return CycleB{} }
+
+func ProvideCycleC(CycleA) CycleC { return CycleC{} }
+
+func TestCyclicDependencyChain(t *testing.T) {
+	var a CycleA
+	err := depinject.Inject(depinject.Provide(ProvideCycleA, ProvideCycleB, ProvideCycleC), &a)
+	require.ErrorIs(t, err, depinject.ErrCyclicDependency)
+	require.ErrorContains(t, err,
+		"cosmossdk.io/depinject_test.ProvideCycleA -> cosmossdk.io/depinject_test.ProvideCycleB -> "+
+			"cosmossdk.io/depinject_test.ProvideCycleC -> cosmossdk.io/depinject_test.ProvideCycleA")
+}
+
+func TestBuildContainer(t *testing.T) {
+	countedKeeperDCalls = 0
+	config := depinject.Configs(
+		depinject.Supply(KVStoreKey{name: "a"}),
+		depinject.Provide(ProvideCountedKeeperD),
+	)
+
+	ctr, err := depinject.BuildContainer(config)
+	require.NoError(t, err)
+	require.Equal(t, 1, countedKeeperDCalls)
+
+	// the values resolved by the build are cached
+	for i := 0; i < 3; i++ {
+		var (
+			d   KeeperD
+			key KVStoreKey
+		)
+		require.NoError(t, ctr.Resolve(&d, &key))
+		require.Equal(t, KeeperD{key: KVStoreKey{name: "a"}}, d)
+		require.Equal(t, "a", key.name)
+	}
+	require.Equal(t, 1, countedKeeperDCalls)
+
+	var d KeeperD
+	require.ErrorIs(t, ctr.Resolve(d), depinject.ErrInvalidOutputType)
+	var a KeeperA
+	require.Error(t, ctr.Resolve(&a))
+
+	_, err = depinject.BuildContainer(depinject.Error(fmt.Errorf("config error")))
+	require.Error(t, err)
+}
+
+type CommandRouter struct{ Commands []Command }
+
+func ProvideCommandRouter(_ KVStoreKey, commands ...Command) CommandRouter {
+	return CommandRouter{Commands: commands}
+}
+
+func ProvideTwoCommands() []Command { return []Command{{}, {}} }
+
+func ProvideCommand() Command { return Command{} }
+
+func TestVariadicProvider(t *testing.T) {
+	var router CommandRouter
+	require.NoError(t, depinject.Inject(
+		depinject.Configs(
+			depinject.Supply(KVStoreKey{name: "router"}),
+			depinject.Provide(ProvideCommandRouter, ProvideTwoCommands, ProvideCommand),
+		),
+		&router,
+	))
+	require.Len(t, router.Commands, 3)
+
+	err := depinject.Inject(depinject.Provide(ProvideVariadicKeepers), new(int))
+	require.ErrorIs(t, err, depinject.ErrInvalidProvider)
+}
+
+func ProvideVariadicKeepers(...KeeperA) int { return 0 }
+
+func TestSupplyAs(t *testing.T) {
+	var s fmt.Stringer
+	require.NoError(t, depinject.Inject(depinject.SupplyAs[fmt.Stringer](time.Second), &s))
+	require.Equal(t, time.Second, s)
+
+	// the value is only supplied as the interface
+	var d time.Duration
+	require.Error(t, depinject.Inject(depinject.SupplyAs[fmt.Stringer](time.Second), &d))
+
+	require.ErrorContains(t, depinject.Inject(depinject.SupplyAs[fmt.Stringer](nil), &s), "cannot supply a nil fmt.Stringer")
+}
+
+func ProvideNestedKeeperD() KeeperD {
+	return KeeperD{key: KVStoreKey{name: "nested"}}
+}
+
+func TestNewScope(t *testing.T) {
+	parent := depinject.Configs(
+		depinject.Supply(KVStoreKey{name: "parent"}),
+		depinject.Provide(ProvideCountedKeeperD),
+	)
+
+	var d KeeperD
+	require.NoError(t, depinject.Inject(parent, &d))
+	require.Equal(t, "parent", d.key.name)
+
+	// the overrides replace the supplied value and the provider of the parent
+	scope := depinject.NewScope(parent, depinject.Supply(KVStoreKey{name: "child"}))
+	require.NoError(t, depinject.Inject(scope, &d))
+	require.Equal(t, "child", d.key.name)
+
+	nested := depinject.NewScope(scope, depinject.Provide(ProvideNestedKeeperD))
+	require.NoError(t, depinject.Inject(nested, &d))
+	require.Equal(t, "nested", d.key.name)
+
+	// without a scope, the same types are duplicates
+	require.Error(t, depinject.Inject(depinject.Configs(parent, depinject.Supply(KVStoreKey{name: "child"})), &d))
+}
+
+func TestSingleton(t *testing.T) {
+	countedKeeperDCalls = 0
+	parent := depinject.Configs(
+		depinject.Supply(KVStoreKey{name: "parent"}),
+		depinject.Singleton(ProvideCountedKeeperD),
+	)
+	scope := depinject.NewScope(parent, depinject.Supply(KVStoreKey{name: "child"}))
+
+	// the scope reuses the keeper of the parent, which isn't called again
+	var d KeeperD
+	require.NoError(t, depinject.Inject(parent, &d))
+	require.Equal(t, "parent", d.key.name)
+	require.NoError(t, depinject.Inject(scope, &d))
+	require.Equal(t, "parent", d.key.name)
+	require.Equal(t, 1, countedKeeperDCalls)
+
+	// a per-child provider is called again with the overrides of the scope
+	parent = depinject.Configs(
+		depinject.Supply(KVStoreKey{name: "parent"}),
+		depinject.PerChild(ProvideCountedKeeperD),
+	)
+	scope = depinject.NewScope(parent, depinject.Supply(KVStoreKey{name: "child"}))
+	require.NoError(t, depinject.Inject(parent, &d))
+	require.Equal(t, "parent", d.key.name)
+	require.NoError(t, depinject.Inject(scope, &d))
+	require.Equal(t, "child", d.key.name)
+	require.Equal(t, 3, countedKeeperDCalls)
+}
+
 func TestDebugOptions(t *testing.T) {
 	t.Run("logging and visualization", func(t *testing.T) {
 		var logOut, dotGraph string
@@ -229,6 +381,206 @@ func TestDebugOptions(t *testing.T) {
 		require.NoError(t, depinject.InjectDebug(debugOpts, depinject.Configs()))
 		verifyDebugOutput(t, logOut, dotGraph, outfile, graphfile)
 	})
+
+	t.Run("file path template with rotation", func(t *testing.T) {
+		dir := t.TempDir()
+		debugOpts := depinject.DebugOptions(
+			depinject.DebugAppName("simd"),
+			depinject.DebugFileRetention(2),
+			depinject.FileVisualizer(filepath.Join(dir, "debug", "{app}-{timestamp}.dot")),
+		)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, depinject.InjectDebug(debugOpts, depinject.Configs()))
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		matches, err := filepath.Glob(filepath.Join(dir, "debug", "simd-*.dot"))
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+	})
+
+	t.Run("auto debug cleans up timestamped files", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "[debug]")
+		t.Setenv(depinject.DebugFileEnvVar, filepath.Join(dir, "{app}-{timestamp}.dot"))
+		debugOpts := depinject.DebugOptions(
+			depinject.DebugAppName("simd"),
+			depinject.DebugFileRetention(2),
+			depinject.AutoDebug(),
+		)
+		failing := depinject.Provide(ProvideKeeperAFromMissing)
+
+		var a KeeperA
+		for i := 0; i < 3; i++ {
+			require.Error(t, depinject.InjectDebug(debugOpts, failing, &a))
+			time.Sleep(2 * time.Millisecond)
+		}
+		other := filepath.Join(dir, "other.dot")
+		require.NoError(t, os.WriteFile(other, nil, 0o600))
+
+		matches, err := filepath.Glob(filepath.Join(dir, "simd-*.dot"))
+		require.NoError(t, err)
+		require.Len(t, matches, 0) // the brackets of dir are a character class
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		require.NoError(t, depinject.InjectDebug(debugOpts, depinject.Configs()))
+		entries, err = os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.FileExists(t, other)
+	})
+
+	t.Run("unrelated files are kept", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(depinject.DebugFileEnvVar, filepath.Join(dir, "{timestamp}"))
+		notes, invalid := filepath.Join(dir, "notes.txt"), filepath.Join(dir, "20231399T250000.000Z")
+		require.NoError(t, os.WriteFile(notes, nil, 0o600))
+		require.NoError(t, os.WriteFile(invalid, nil, 0o600))
+
+		debugOpts := depinject.DebugOptions(
+			depinject.DebugFileRetention(1),
+			depinject.FileVisualizer("ignored.dot"),
+		)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, depinject.InjectDebug(debugOpts, depinject.Configs()))
+			time.Sleep(2 * time.Millisecond)
+		}
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		// the cleanup of AutoDebug only removes the debug files
+		require.NoError(t, depinject.InjectDebug(depinject.AutoDebug(), depinject.Configs()))
+		entries, err = os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.FileExists(t, notes)
+		require.FileExists(t, invalid)
+	})
+
+	t.Run("env var override", func(t *testing.T) {
+		override := filepath.Join(t.TempDir(), "override.dot")
+		t.Setenv(depinject.DebugFileEnvVar, override)
+
+		debugOpts := depinject.FileVisualizer("ignored.dot")
+		require.NoError(t, depinject.InjectDebug(debugOpts, depinject.Configs()))
+
+		contents, err := os.ReadFile(override)
+		require.NoError(t, err)
+		require.Contains(t, string(contents), "digraph")
+		require.NoFileExists(t, "ignored.dot")
+	})
+
+	t.Run("mermaid and json visualization", func(t *testing.T) {
+		var mermaidGraph, jsonGraph string
+		debugOpts := depinject.DebugOptions(
+			depinject.MermaidVisualizer(func(g string) { mermaidGraph = g }),
+			depinject.JSONVisualizer(func(g string) { jsonGraph = g }),
+		)
+
+		var a KeeperA
+		require.NoError(t, depinject.InjectDebug(debugOpts, scenarioConfig, &a))
+		require.True(t, strings.HasPrefix(mermaidGraph, "flowchart LR\n"))
+		require.Contains(t, mermaidGraph, "subgraph")
+		require.Contains(t, mermaidGraph, " --> ")
+
+		var graph struct {
+			Nodes []struct {
+				Name string `json:"name"`
+			} `json:"nodes"`
+			Edges []struct {
+				From string `json:"from"`
+				To   string `json:"to"`
+			} `json:"edges"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(jsonGraph), &graph))
+		require.NotEmpty(t, graph.Nodes)
+		require.NotEmpty(t, graph.Edges)
+	})
+}
+
+// impureIntsCalls counts the calls of ProvideImpureInts.
+var impureIntsCalls int
+
+func ProvideImpureInts() []int {
+	impureIntsCalls++
+	return []int{impureIntsCalls}
+}
+
+// numberedKVStoreKeyCalls counts the calls of ProvideNumberedKVStoreKey.
+var numberedKVStoreKeyCalls int
+
+func ProvideNumberedKVStoreKey() KVStoreKey {
+	numberedKVStoreKeyCalls++
+	return KVStoreKey{name: fmt.Sprintf("key-%d", numberedKVStoreKeyCalls)}
+}
+
+func TestVerifyPure(t *testing.T) {
+	t.Run("pure providers", func(t *testing.T) {
+		var a KeeperA
+		require.NoError(t, depinject.InjectDebug(depinject.VerifyPure(), scenarioConfig, &a))
+	})
+
+	t.Run("impure provider", func(t *testing.T) {
+		impureIntsCalls = 0
+		config := depinject.Provide(ProvideImpureInts)
+
+		var x []int
+		err := depinject.InjectDebug(depinject.VerifyPure(), config, &x)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not pure")
+	})
+
+	t.Run("custom comparator", func(t *testing.T) {
+		numberedKVStoreKeyCalls = 0
+		config := depinject.Provide(ProvideNumberedKVStoreKey)
+		debugOpts := depinject.DebugOptions(
+			depinject.VerifyPure(),
+			depinject.PureComparator(func(a, b KVStoreKey) bool { return len(a.name) == len(b.name) }),
+		)
+
+		var key KVStoreKey
+		require.NoError(t, depinject.InjectDebug(debugOpts, config, &key))
+		require.Equal(t, "key-1", key.name)
+		require.Equal(t, 2, numberedKVStoreKeyCalls)
+	})
+}
+
+func ProvideSlowInt() int {
+	time.Sleep(10 * time.Millisecond)
+	return 1
+}
+
+func ProvideIntString(x int) string { return fmt.Sprint(x) }
+
+func TestProfiler(t *testing.T) {
+	config := depinject.Provide(ProvideSlowInt, ProvideIntString)
+
+	var foldedStacks string
+	var s string
+	require.NoError(t, depinject.InjectDebug(depinject.Profiler(func(stacks string) { foldedStacks = stacks }), config, &s))
+	require.Equal(t, "1", s)
+
+	// the int provider is stacked on top of the string provider which needed it
+	lines := strings.Split(strings.TrimSpace(foldedStacks), "\n")
+	require.Len(t, lines, 2)
+	require.NotContains(t, lines[0], ";")
+	stack, us, ok := strings.Cut(lines[1], " ")
+	require.True(t, ok)
+	require.Len(t, strings.Split(stack, ";"), 2)
+	require.True(t, strings.HasPrefix(stack, strings.Fields(lines[0])[0]+";"))
+	micros, err := strconv.Atoi(us)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, micros, 10000)
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "depinject.folded")
+	require.NoError(t, depinject.InjectDebug(depinject.FileProfiler(filename), config, &s))
+	contents, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(contents)), "\n"), 2)
 }
 
 // Helper functions
@@ -1,10 +1,13 @@
 package depinject
 
 import (
+	"bytes"
 	"fmt"
+	"go/ast"
 	"os"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"cosmossdk.io/depinject/internal/graphviz"
 )
@@ -35,6 +38,22 @@ type (
 		onError       DebugOption
 		onSuccess     DebugOption
 		cleanup       []func()
+
+		appName            string
+		debugFileRetention int
+
+		verifyPure      bool
+		pureComparators map[reflect.Type]valueComparator
+
+		providerDocs bool
+		parsedFiles  map[string]*ast.File
+
+		profile   *callProfile
+		profilers []func(string)
+
+		warnings         []Warning
+		warningsAsErrors bool
+		warningHandlers  []func(Warning)
 	}
 
 	debugOption func(*debugConfig) error
@@ -60,6 +79,32 @@ func Visualizer(visualizer func(dotGraph string)) DebugOption {
 	})
 }
 
+// MermaidVisualizer provides a function to receive container rendering as a
+// mermaid flowchart, which can be embedded in Markdown documents
+func MermaidVisualizer(visualizer func(mermaidGraph string)) DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.addFuncVisualizer(func(_ string) { visualizer(c.graph.Mermaid()) })
+		return nil
+	})
+}
+
+// JSONVisualizer provides a function to receive container rendering as JSON,
+// listing the module sub-graphs, the nodes and the edges of the graph with
+// their attributes
+func JSONVisualizer(visualizer func(jsonGraph string)) DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.addFuncVisualizer(func(_ string) {
+			buf := &bytes.Buffer{}
+			if err := c.graph.RenderJSON(buf); err != nil {
+				c.logf("Error rendering JSON graph: %+v", err)
+				return
+			}
+			visualizer(buf.String())
+		})
+		return nil
+	})
+}
+
 // LogVisualizer dumps a graphviz DOT rendering to the log
 func LogVisualizer() DebugOption {
 	return debugOption(func(c *debugConfig) error {
@@ -68,7 +113,9 @@ func LogVisualizer() DebugOption {
 	})
 }
 
-// FileVisualizer dumps a graphviz DOT rendering to the specified file
+// FileVisualizer dumps a graphviz DOT rendering to the specified file.
+// The filename is a path template which may contain the {timestamp} and {app}
+// placeholders and is overridden by the DEPINJECT_DEBUG_FILE environment variable.
 func FileVisualizer(filename string) DebugOption {
 	return debugOption(func(c *debugConfig) error {
 		c.addFileVisualizer(filename)
@@ -86,11 +133,13 @@ func Logger(logger func(string)) DebugOption {
 	})
 }
 
-// Debug sets default debug options (stderr output and file visualization)
+// Debug sets default debug options (stderr output and file visualization
+// with provider doc comments)
 func Debug() DebugOption {
 	return DebugOptions(
 		StderrLogger(),
 		FileVisualizer(defaultDebugFile),
+		ProviderDocs(),
 	)
 }
 
@@ -98,7 +147,12 @@ func Debug() DebugOption {
 func AutoDebug() DebugOption {
 	return DebugOptions(
 		OnError(Debug()),
-		OnSuccess(DebugCleanup(func() { deleteIfExists(defaultDebugFile) })),
+		OnSuccess(debugOption(func(c *debugConfig) error {
+			c.cleanup = append(c.cleanup, func() {
+				c.removeDebugFiles(debugFileTemplate(defaultDebugFile))
+			})
+			return nil
+		})),
 	)
 }
 
@@ -147,7 +201,10 @@ func DebugOptions(options ...DebugOption) DebugOption {
 func (c debugOption) applyConfig(ctr *debugConfig) error { return c(ctr) }
 
 func newDebugConfig() (*debugConfig, error) {
-	return &debugConfig{graph: graphviz.NewGraph()}, nil
+	return &debugConfig{
+		graph:              graphviz.NewGraph(),
+		debugFileRetention: defaultDebugFileRetention,
+	}, nil
 }
 
 func (c *debugConfig) initLogBuf() {
@@ -201,13 +258,23 @@ func (c *debugConfig) enableLogVisualizer() {
 
 func (c *debugConfig) addFileVisualizer(filename string) {
 	c.visualizers = append(c.visualizers, func(_ string) {
-		if err := c.saveGraphToFile(filename); err != nil {
-			c.logf("Error saving graphviz file %s: %+v", filename, err)
+		template := debugFileTemplate(filename)
+		path := c.expandDebugFileTemplate(template, time.Now())
+		if err := c.saveGraphToFile(path); err != nil {
+			c.logf("Error saving graphviz file %s: %+v", path, err)
+			return
 		}
+		c.rotateDebugFiles(template)
 	})
 }
 
 func (c *debugConfig) saveGraphToFile(filename string) error {
+	if dir := filepath.Dir(filename); dir != "." {
+		if err := os.MkdirAll(dir, defaultDebugDirPerms); err != nil {
+			return err
+		}
+	}
+
 	if err := os.WriteFile(filename, []byte(c.graph.String()), defaultFilePerms); err != nil {
 		return err
 	}
@@ -0,0 +1,172 @@
+package depinject
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DebugFileEnvVar is the environment variable which, when set, overrides
+	// the path template of every debug file written by the container.
+	DebugFileEnvVar = "DEPINJECT_DEBUG_FILE"
+
+	// DebugFileTimestamp is the path template placeholder replaced with the
+	// UTC time at which the debug file is written.
+	DebugFileTimestamp = "{timestamp}"
+
+	// DebugFileAppName is the path template placeholder replaced with the
+	// application name (see DebugAppName).
+	DebugFileAppName = "{app}"
+
+	debugFileTimeFormat       = "20060102T150405.000Z"
+	defaultDebugFileRetention = 5
+	defaultDebugDirPerms      = 0o755
+)
+
+// DebugAppName sets the application name used to expand the {app}
+// placeholder in debug file path templates. It defaults to the base name of
+// the running executable.
+func DebugAppName(name string) DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.appName = name
+		return nil
+	})
+}
+
+// DebugFileRetention sets how many debug files produced by a path template
+// containing {timestamp} are kept. Older files are removed each time a new one
+// is written. A value of zero or less disables rotation.
+func DebugFileRetention(n int) DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.debugFileRetention = n
+		return nil
+	})
+}
+
+// debugFileTemplate returns the path template to use for a debug file,
+// honoring the DebugFileEnvVar override.
+func debugFileTemplate(template string) string {
+	if env := os.Getenv(DebugFileEnvVar); env != "" {
+		return env
+	}
+	return template
+}
+
+func (c *debugConfig) debugAppName() string {
+	if c.appName != "" {
+		return c.appName
+	}
+	return filepath.Base(os.Args[0])
+}
+
+// expandDebugFileTemplate replaces the placeholders of a path template.
+func (c *debugConfig) expandDebugFileTemplate(template string, now time.Time) string {
+	return strings.NewReplacer(
+		DebugFileTimestamp, now.UTC().Format(debugFileTimeFormat),
+		DebugFileAppName, c.debugAppName(),
+	).Replace(template)
+}
+
+// rotateDebugFiles removes the oldest files produced by the given template so
+// that at most debugFileRetention of them are left on disk.
+func (c *debugConfig) rotateDebugFiles(template string) {
+	if c.debugFileRetention <= 0 || !strings.Contains(template, DebugFileTimestamp) {
+		return
+	}
+
+	matches := c.debugFiles(template)
+	if len(matches) <= c.debugFileRetention {
+		return
+	}
+
+	modTimes := make(map[string]time.Time, len(matches))
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil {
+			modTimes[match] = info.ModTime()
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return modTimes[matches[i]].Before(modTimes[matches[j]])
+	})
+
+	for _, match := range matches[:len(matches)-c.debugFileRetention] {
+		if err := os.Remove(match); err == nil {
+			c.logf("Removed old debug file %s", match)
+		}
+	}
+}
+
+// removeDebugFiles removes the files produced by the given template: all of
+// them if it contains {timestamp}, and the file it expands to otherwise.
+func (c *debugConfig) removeDebugFiles(template string) {
+	if !strings.Contains(template, DebugFileTimestamp) {
+		deleteIfExists(c.expandDebugFileTemplate(template, time.Now()))
+		return
+	}
+
+	for _, match := range c.debugFiles(template) {
+		if err := os.Remove(match); err == nil {
+			c.logf("Removed debug file %s", match)
+		}
+	}
+}
+
+// debugFiles returns the files produced by a path template containing
+// {timestamp}: the files matching its glob pattern whose timestamps parse with
+// debugFileTimeFormat, so that the other files next to them are left alone.
+func (c *debugConfig) debugFiles(template string) []string {
+	matches, err := filepath.Glob(c.debugFilePattern(template))
+	if err != nil {
+		return nil
+	}
+
+	re := c.debugFileRegexp(template)
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		timestamps := re.FindStringSubmatch(filepath.Clean(match))
+		if timestamps == nil {
+			continue
+		}
+
+		valid := true
+		for _, timestamp := range timestamps[1:] {
+			if _, err := time.Parse(debugFileTimeFormat, timestamp); err != nil {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			files = append(files, match)
+		}
+	}
+	return files
+}
+
+// debugFileRegexp returns the regexp matching the cleaned paths of the files
+// produced by a path template, capturing their timestamps.
+func (c *debugConfig) debugFileRegexp(template string) *regexp.Regexp {
+	parts := strings.Split(filepath.Clean(template), DebugFileTimestamp)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(strings.ReplaceAll(part, DebugFileAppName, c.debugAppName()))
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, `(\d{8}T\d{6}\.\d{3}Z)`) + "$")
+}
+
+// debugFilePattern returns the glob pattern matching the files produced by a
+// path template, whatever their timestamp.
+func (c *debugConfig) debugFilePattern(template string) string {
+	parts := strings.Split(template, DebugFileTimestamp)
+	for i, part := range parts {
+		parts[i] = globEscaper.Replace(strings.ReplaceAll(part, DebugFileAppName, c.debugAppName()))
+	}
+	return strings.Join(parts, "*")
+}
+
+// globEscaper escapes the glob metacharacters of a path, with character
+// classes rather than backslashes, which are separators on Windows.
+var globEscaper = strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]")
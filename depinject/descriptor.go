@@ -0,0 +1,169 @@
+package depinject
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// ProviderDescriptor describes a provider or invoker by listing its input and
+// output types explicitly, instead of having the container inspect a func
+// with reflection. It can be passed to Provide, ProvideInModule, Invoke and
+// InvokeInModule in place of a function, which allows the container to be
+// used in environments with limited support for reflection on func types,
+// such as TinyGo or wasm, as Fn is never called through reflect.Value.Call.
+//
+// Ex:
+//
+//	depinject.Provide(depinject.ProviderDescriptor{
+//	    Name:    "ProvideKeeper",
+//	    Inputs:  []depinject.ProviderInput{{Type: depinject.TypeOf[KVStoreKey]()}},
+//	    Outputs: []reflect.Type{depinject.TypeOf[Keeper]()},
+//	    Fn: func(inputs []interface{}) ([]interface{}, error) {
+//	        return []interface{}{NewKeeper(inputs[0].(KVStoreKey))}, nil
+//	    },
+//	})
+//
+// Inputs and outputs may use In and Out structs, and are subject to the same
+// requirements as the ones of provider functions.
+type ProviderDescriptor struct {
+	// Name identifies the provider in logs, errors and debug graphs.
+	Name string
+
+	// Doc documents the provider in debug graphs, see ProviderDocs.
+	Doc string
+
+	// Inputs are the types of the values passed to Fn, in order.
+	Inputs []ProviderInput
+
+	// Outputs are the types of the values returned by Fn, in order. Invokers
+	// must not have outputs.
+	Outputs []reflect.Type
+
+	// Fn is called with one value per input and must return one value per
+	// output, each assignable to the corresponding output type.
+	Fn func(inputs []interface{}) ([]interface{}, error)
+}
+
+// ProviderInput describes an input of a ProviderDescriptor.
+type ProviderInput struct {
+	Type reflect.Type
+
+	// Optional inputs are set to their zero value when they cannot be
+	// resolved, rather than causing an error.
+	Optional bool
+}
+
+// TypeOf returns the reflect.Type of T, including when T is an interface type.
+func TypeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func extractExplicitProviderDescriptor(d ProviderDescriptor, loc Location) (providerDescriptor, error) {
+	rctr, err := d.toProviderDescriptor(loc)
+	if err != nil {
+		return providerDescriptor{}, err
+	}
+	return postProcessProvider(rctr)
+}
+
+func extractExplicitInvokerDescriptor(d ProviderDescriptor, loc Location) (providerDescriptor, error) {
+	if len(d.Outputs) != 0 {
+		return providerDescriptor{}, errors.Wrapf(ErrInvalidInvoker, "provider descriptor %s", descriptorLocation(d.Name, loc))
+	}
+
+	rctr, err := d.toProviderDescriptor(loc)
+	if err != nil {
+		return providerDescriptor{}, err
+	}
+
+	markInputsOptional(rctr)
+	return postProcessProvider(rctr)
+}
+
+// toProviderDescriptor converts the descriptor into the container's internal
+// representation, located at loc with the descriptor name.
+func (d ProviderDescriptor) toProviderDescriptor(loc Location) (providerDescriptor, error) {
+	if d.Name == "" {
+		return providerDescriptor{}, errors.Errorf("provider descriptor defined at %s must have a name", loc)
+	}
+	loc = descriptorLocation(d.Name, loc)
+
+	if d.Fn == nil {
+		return providerDescriptor{}, errors.Errorf("provider descriptor %s must have a Fn", loc)
+	}
+
+	in := make([]providerInput, len(d.Inputs))
+	for i, input := range d.Inputs {
+		if input.Type == nil {
+			return providerDescriptor{}, errors.Errorf("input %d of provider descriptor %s has no type", i, loc)
+		}
+		in[i] = providerInput{Type: input.Type, Optional: input.Optional}
+	}
+
+	out := make([]providerOutput, len(d.Outputs))
+	for i, typ := range d.Outputs {
+		if typ == nil {
+			return providerDescriptor{}, errors.Errorf("output %d of provider descriptor %s has no type", i, loc)
+		}
+		if typ == errType {
+			return providerDescriptor{}, errors.Errorf("provider descriptor %s must return errors from Fn, not as an output", loc)
+		}
+		out[i] = providerOutput{Type: typ}
+	}
+
+	fn := d.Fn
+	return providerDescriptor{
+		Inputs:  in,
+		Outputs: out,
+		Fn: func(values []reflect.Value) ([]reflect.Value, error) {
+			inputs := make([]interface{}, len(values))
+			for i, value := range values {
+				inputs[i] = value.Interface()
+			}
+
+			outputs, err := fn(inputs)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(outputs) != len(out) {
+				return nil, errors.Errorf("provider descriptor %s returned %d values, expected %d", loc, len(outputs), len(out))
+			}
+
+			res := make([]reflect.Value, len(outputs))
+			for i, output := range outputs {
+				typ := out[i].Type
+				if output == nil {
+					res[i] = reflect.Zero(typ)
+					continue
+				}
+
+				value := reflect.ValueOf(output)
+				if !value.Type().AssignableTo(typ) {
+					return nil, errors.Errorf("provider descriptor %s returned a %v for output %d, expected %v", loc, value.Type(), i, typ)
+				}
+				if value.Type() != typ {
+					converted := reflect.New(typ).Elem()
+					converted.Set(value)
+					value = converted
+				}
+				res[i] = value
+			}
+			return res, nil
+		},
+		Location: loc,
+		Doc:      d.Doc,
+		explicit: true,
+	}, nil
+}
+
+// descriptorLocation returns the location of a provider descriptor: the
+// location where it was registered, named after the descriptor.
+func descriptorLocation(name string, registered Location) Location {
+	loc := &location{name: name, pkg: "unknown", file: "unknown"}
+	if l, ok := registered.(*location); ok {
+		loc.pkg, loc.file, loc.line = l.pkg, l.file, l.line
+	}
+	return loc
+}
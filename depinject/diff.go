@@ -0,0 +1,172 @@
+package depinject
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigDiff is the difference between the structures of two configs, e.g. of
+// two versions of an app config, listing the changes of the app wiring for
+// release notes. Providers and invokers are identified by their name, module
+// scope, inputs and outputs, so that a provider whose signature changed is
+// both removed and added.
+type ConfigDiff struct {
+	AddedProviders   []ManifestProvider
+	RemovedProviders []ManifestProvider
+	// ScopeChanges are the providers moved to another module scope, or
+	// between a module scope and the global scope.
+	ScopeChanges    []ScopeChange
+	AddedInvokers   []ManifestProvider
+	RemovedInvokers []ManifestProvider
+	AddedBindings   []ManifestBinding
+	RemovedBindings []ManifestBinding
+}
+
+// ScopeChange is a provider moved from the module scope OldModule to
+// NewModule, the global scope being the empty module name.
+type ScopeChange struct {
+	Provider  ManifestProvider
+	OldModule string
+	NewModule string
+}
+
+// DiffConfigs returns the difference between the structures of the configs
+// from and to. The configs are applied to containers which only record their
+// structure, as by BuildManifest: providers and invokers are not called.
+func DiffConfigs(from, to Config) (*ConfigDiff, error) {
+	fromManifest, err := BuildManifest(from)
+	if err != nil {
+		return nil, err
+	}
+	toManifest, err := BuildManifest(to)
+	if err != nil {
+		return nil, err
+	}
+	return DiffManifests(fromManifest, toManifest), nil
+}
+
+// DiffManifests returns the difference between the configs of the manifests
+// from and to, e.g. of the manifest checked in for the previous release and
+// of the current config.
+func DiffManifests(from, to *Manifest) *ConfigDiff {
+	diff := &ConfigDiff{}
+	added, removed := diffEntries(from.Providers, to.Providers, describeProvider)
+	diff.AddedProviders, diff.RemovedProviders, diff.ScopeChanges = matchScopeChanges(added, removed)
+	diff.AddedInvokers, diff.RemovedInvokers = diffEntries(from.Invokers, to.Invokers, describeProvider)
+	diff.AddedBindings, diff.RemovedBindings = diffEntries(from.Bindings, to.Bindings, describeBinding)
+	return diff
+}
+
+// Empty reports whether the configs have the same structure, supplied values
+// aside.
+func (d *ConfigDiff) Empty() bool {
+	return len(d.AddedProviders) == 0 && len(d.RemovedProviders) == 0 && len(d.ScopeChanges) == 0 &&
+		len(d.AddedInvokers) == 0 && len(d.RemovedInvokers) == 0 &&
+		len(d.AddedBindings) == 0 && len(d.RemovedBindings) == 0
+}
+
+// String renders the difference as a Markdown list per kind of change, to
+// paste in release notes.
+func (d *ConfigDiff) String() string {
+	var b strings.Builder
+	section := func(title string, entries []string) {
+		if len(entries) == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s:\n", title)
+		for _, entry := range entries {
+			fmt.Fprintf(&b, "- `%s`\n", entry)
+		}
+	}
+	describeAll := func(ps []ManifestProvider) []string {
+		entries := make([]string, len(ps))
+		for i, p := range ps {
+			entries[i] = describeProvider(p)
+		}
+		return entries
+	}
+
+	section("Added providers", describeAll(d.AddedProviders))
+	section("Removed providers", describeAll(d.RemovedProviders))
+	scopeChanges := make([]string, len(d.ScopeChanges))
+	for i, c := range d.ScopeChanges {
+		scopeChanges[i] = fmt.Sprintf("%s: %s -> %s", c.Provider.Name, scopeName(c.OldModule), scopeName(c.NewModule))
+	}
+	section("Changed module scopes", scopeChanges)
+	section("Added invokers", describeAll(d.AddedInvokers))
+	section("Removed invokers", describeAll(d.RemovedInvokers))
+	bindings := func(bs []ManifestBinding) []string {
+		entries := make([]string, len(bs))
+		for i, b := range bs {
+			entries[i] = describeBinding(b)
+		}
+		return entries
+	}
+	section("Added bindings", bindings(d.AddedBindings))
+	section("Removed bindings", bindings(d.RemovedBindings))
+
+	if b.Len() == 0 {
+		return "No app wiring changes\n"
+	}
+	return b.String()
+}
+
+// diffEntries returns the entries of to missing from from, and those of from
+// missing from to, by their description, in the order of the manifests.
+func diffEntries[T any](from, to []T, describe func(T) string) (added, removed []T) {
+	missing := func(entries, other []T) []T {
+		counts := make(map[string]int)
+		for _, e := range other {
+			counts[describe(e)]++
+		}
+		var res []T
+		for _, e := range entries {
+			key := describe(e)
+			if counts[key] > 0 {
+				counts[key]--
+				continue
+			}
+			res = append(res, e)
+		}
+		return res
+	}
+	return missing(to, from), missing(from, to)
+}
+
+// matchScopeChanges pairs the removed and added providers which only differ by
+// their module scope.
+func matchScopeChanges(added, removed []ManifestProvider) ([]ManifestProvider, []ManifestProvider, []ScopeChange) {
+	var changes []ScopeChange
+	unscoped := func(p ManifestProvider) string {
+		p.Module = ""
+		return describeProvider(p)
+	}
+
+	var stillRemoved []ManifestProvider
+	for _, r := range removed {
+		i := -1
+		for j, a := range added {
+			if unscoped(a) == unscoped(r) {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			stillRemoved = append(stillRemoved, r)
+			continue
+		}
+		changes = append(changes, ScopeChange{Provider: added[i], OldModule: r.Module, NewModule: added[i].Module})
+		added = append(added[:i:i], added[i+1:]...)
+	}
+	return added, stillRemoved, changes
+}
+
+func scopeName(module string) string {
+	if module == "" {
+		return "global"
+	}
+	return "module " + module
+}
@@ -0,0 +1,56 @@
+package depinject_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/depinject"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	v1 := depinject.Configs(
+		depinject.Provide(ProvideKVStoreKey),
+		depinject.ProvideInModule("a", ProvideKeeperA),
+		depinject.Invoke(InvokeKeeperA),
+	)
+	v2 := depinject.Configs(
+		depinject.ProvideInModule("b", ProvideKeeperA),
+		depinject.Invoke(InvokeKeeperA),
+		depinject.BindInterfaceInModule("a", "cosmossdk.io/depinject_test/depinject_test.Duck", "cosmossdk.io/depinject_test/depinject_test.Mallard"),
+	)
+
+	diff, err := depinject.DiffConfigs(v1, v1)
+	require.NoError(t, err)
+	require.True(t, diff.Empty())
+	require.Equal(t, "No app wiring changes\n", diff.String())
+
+	diff, err = depinject.DiffConfigs(v1, v2)
+	require.NoError(t, err)
+	require.False(t, diff.Empty())
+	require.Empty(t, diff.AddedProviders)
+	require.Len(t, diff.RemovedProviders, 1)
+	require.Equal(t, "cosmossdk.io/depinject_test.ProvideKVStoreKey", diff.RemovedProviders[0].Name)
+	require.Len(t, diff.ScopeChanges, 1)
+	require.Equal(t, "cosmossdk.io/depinject_test.ProvideKeeperA", diff.ScopeChanges[0].Provider.Name)
+	require.Equal(t, "a", diff.ScopeChanges[0].OldModule)
+	require.Equal(t, "b", diff.ScopeChanges[0].NewModule)
+	require.Empty(t, diff.AddedInvokers)
+	require.Equal(t, []depinject.ManifestBinding{{
+		Interface:      "cosmossdk.io/depinject_test/depinject_test.Duck",
+		Implementation: "cosmossdk.io/depinject_test/depinject_test.Mallard",
+		Module:         "a",
+	}}, diff.AddedBindings)
+	require.Contains(t, diff.String(), "Changed module scopes:\n- `cosmossdk.io/depinject_test.ProvideKeeperA: module a -> module b`\n")
+
+	// the reverse diff swaps the added and removed entries
+	reverse, err := depinject.DiffConfigs(v2, v1)
+	require.NoError(t, err)
+	require.Equal(t, diff.RemovedProviders, reverse.AddedProviders)
+	require.Equal(t, diff.AddedBindings, reverse.RemovedBindings)
+	require.Equal(t, "b", reverse.ScopeChanges[0].OldModule)
+
+	_, err = depinject.DiffConfigs(v1, depinject.Error(errors.New("config error")))
+	require.ErrorContains(t, err, "config error")
+}
@@ -22,9 +22,10 @@ type (
 	// for an explicitly bound implementation
 	ErrNoTypeForExplicitBindingFound struct {
 		error
-		Implementation string // The implementation type name
-		Interface      string // The interface type name
-		ModuleName     string // Optional module name
+		Implementation string   // The implementation type name
+		Interface      string   // The interface type name
+		ModuleName     string   // Optional module name
+		Suggestions    []string // Registered types with names similar to Implementation
 	}
 
 	// ErrDuplicateDefinition occurs when the same type is provided multiple times
@@ -34,6 +35,44 @@ type (
 		NewLocation  Location     // Location of the duplicate definition
 		OldLocation  string       // Location of the existing definition
 	}
+
+	// ErrImpureProvider occurs when VerifyPure is enabled and two calls of a
+	// provider with the same inputs return different outputs
+	ErrImpureProvider struct {
+		error
+		Location Location     // Location of the provider
+		Type     reflect.Type // The output type which differs, if any
+		Err      error        // The error returned by the second call, if any
+	}
+
+	// ErrTypeNotResolvable occurs when no provider, supplied value or interface
+	// binding resolves a required type
+	ErrTypeNotResolvable struct {
+		error
+		Type        reflect.Type // The type which can't be resolved
+		Location    Location     // Location of the provider or caller requiring it
+		Suggestions []string     // Registered types with similar names
+	}
+
+	// ErrDuplicateOnePerModule occurs when a module provides several values
+	// of the same one-per-module type
+	ErrDuplicateOnePerModule struct {
+		error
+		Type             reflect.Type // The one-per-module type
+		ModuleName       string       // The module providing it twice
+		NewLocation      Location     // Location of the duplicate provider
+		ExistingLocation Location     // Location of the existing provider
+	}
+
+	// ErrTypeNotWhitelisted occurs when a provider or invoker of a module
+	// with a whitelist consumes a type the whitelist doesn't allow
+	ErrTypeNotWhitelisted struct {
+		error
+		ModuleName string       // The module consuming the type
+		Type       reflect.Type // The type which isn't whitelisted
+		Location   Location     // Location of the provider or invoker
+		Allowed    []string     // The whitelisted type names
+	}
 )
 
 // Error constructors
@@ -51,7 +90,7 @@ func newErrMultipleImplicitInterfaceBindings(iface reflect.Type, matches map[ref
 }
 
 // newErrNoTypeForExplicitBindingFound creates an error for missing implementation
-func newErrNoTypeForExplicitBindingFound(binding interfaceBinding) ErrNoTypeForExplicitBindingFound {
+func newErrNoTypeForExplicitBindingFound(binding interfaceBinding, suggestions []string) ErrNoTypeForExplicitBindingFound {
 	var moduleName string
 	if binding.moduleKey != nil {
 		moduleName = binding.moduleKey.name
@@ -61,6 +100,7 @@ func newErrNoTypeForExplicitBindingFound(binding interfaceBinding) ErrNoTypeForE
 		Implementation: binding.implTypeName,
 		Interface:      binding.interfaceName,
 		ModuleName:     moduleName,
+		Suggestions:    suggestions,
 	}
 }
 
@@ -73,6 +113,44 @@ func newErrDuplicateDefinition(typ reflect.Type, newLoc Location, oldLoc string)
 	}
 }
 
+// newErrImpureProvider creates an error for a provider returning different outputs
+func newErrImpureProvider(loc Location, typ reflect.Type, err error) ErrImpureProvider {
+	return ErrImpureProvider{
+		Location: loc,
+		Type:     typ,
+		Err:      err,
+	}
+}
+
+// newErrTypeNotResolvable creates an error for a required type which can't be resolved
+func newErrTypeNotResolvable(typ reflect.Type, loc Location, suggestions []string) ErrTypeNotResolvable {
+	return ErrTypeNotResolvable{
+		Type:        typ,
+		Location:    loc,
+		Suggestions: suggestions,
+	}
+}
+
+// newErrDuplicateOnePerModule creates an error for a one-per-module type provided twice by a module
+func newErrDuplicateOnePerModule(typ reflect.Type, moduleName string, newLoc, existingLoc Location) ErrDuplicateOnePerModule {
+	return ErrDuplicateOnePerModule{
+		Type:             typ,
+		ModuleName:       moduleName,
+		NewLocation:      newLoc,
+		ExistingLocation: existingLoc,
+	}
+}
+
+// newErrTypeNotWhitelisted creates an error for a type consumed in violation of a module whitelist
+func newErrTypeNotWhitelisted(moduleName string, typ reflect.Type, loc Location, allowed []string) ErrTypeNotWhitelisted {
+	return ErrTypeNotWhitelisted{
+		ModuleName: moduleName,
+		Type:       typ,
+		Location:   loc,
+		Allowed:    allowed,
+	}
+}
+
 // Error method implementations
 
 func (e ErrMultipleImplicitInterfaceBindings) Error() string {
@@ -87,21 +165,18 @@ func (e ErrMultipleImplicitInterfaceBindings) Error() string {
 }
 
 func (e ErrNoTypeForExplicitBindingFound) Error() string {
+	var b strings.Builder
+	b.WriteString("No type for explicit binding found")
 	if e.ModuleName != "" {
-		return fmt.Sprintf(
-			"No implementation found for explicit binding in module %q:\n"+
-			"  Interface: %s\n"+
-			"  Expected Implementation: %s",
-			e.ModuleName, e.Interface, e.Implementation,
-		)
+		b.WriteString(fmt.Sprintf(" in module %q", e.ModuleName))
 	}
-	
-	return fmt.Sprintf(
-		"No implementation found for explicit binding:\n"+
+	b.WriteString(fmt.Sprintf(":\n"+
 		"  Interface: %s\n"+
 		"  Expected Implementation: %s",
 		e.Interface, e.Implementation,
-	)
+	))
+	writeSuggestions(&b, e.Suggestions)
+	return b.String()
 }
 
 func (e ErrDuplicateDefinition) Error() string {
@@ -113,6 +188,58 @@ func (e ErrDuplicateDefinition) Error() string {
 	)
 }
 
+func (e ErrImpureProvider) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("Provider %s is not pure, second call failed: %v", e.Location, e.Err)
+	}
+
+	return fmt.Sprintf("Provider %s is not pure, two calls returned different values of type %v", e.Location, e.Type)
+}
+
+func (e ErrImpureProvider) Unwrap() error {
+	return e.Err
+}
+
+func (e ErrTypeNotResolvable) Error() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(
+		"Can't resolve type %s:\n"+
+			"  Required by: %s",
+		fullyQualifiedTypeName(e.Type), e.Location,
+	))
+	writeSuggestions(&b, e.Suggestions)
+	return b.String()
+}
+
+// writeSuggestions writes the "did you mean" list of an error, if any.
+func writeSuggestions(b *strings.Builder, suggestions []string) {
+	if len(suggestions) > 0 {
+		b.WriteString("\n  Did you mean:")
+		for _, suggestion := range suggestions {
+			b.WriteString(fmt.Sprintf("\n    %s", suggestion))
+		}
+	}
+}
+
+func (e ErrDuplicateOnePerModule) Error() string {
+	return fmt.Sprintf(
+		"Duplicate provision of one-per-module type %v in module %q:\n"+
+			"  New definition at: %s\n"+
+			"  Existing definition at: %s\n"+
+			"  A module provides at most one value of map[string]%v",
+		e.Type, e.ModuleName, e.NewLocation, e.ExistingLocation, e.Type,
+	)
+}
+
+func (e ErrTypeNotWhitelisted) Error() string {
+	return fmt.Sprintf(
+		"Module %q is not allowed to consume type %s:\n"+
+			"  Consumed by: %s\n"+
+			"  Whitelisted types: [%s]",
+		e.ModuleName, fullyQualifiedTypeName(e.Type), e.Location, strings.Join(e.Allowed, ", "),
+	)
+}
+
 // Helper functions
 
 // duplicateDefinitionError wraps the creation of ErrDuplicateDefinition
@@ -135,8 +262,32 @@ func IsNoTypeForBindingError(err error) bool {
 	return ok
 }
 
+// IsTypeNotResolvableError checks if an error is ErrTypeNotResolvable
+func IsTypeNotResolvableError(err error) bool {
+	var target ErrTypeNotResolvable
+	return errors.As(err, &target)
+}
+
+// IsDuplicateOnePerModuleError checks if an error is ErrDuplicateOnePerModule
+func IsDuplicateOnePerModuleError(err error) bool {
+	var target ErrDuplicateOnePerModule
+	return errors.As(err, &target)
+}
+
 // IsDuplicateDefinitionError checks if an error is ErrDuplicateDefinition
 func IsDuplicateDefinitionError(err error) bool {
 	_, ok := err.(ErrDuplicateDefinition)
 	return ok
 }
+
+// IsImpureProviderError checks if an error is ErrImpureProvider
+func IsImpureProviderError(err error) bool {
+	_, ok := err.(ErrImpureProvider)
+	return ok
+}
+
+// IsTypeNotWhitelistedError checks if an error is ErrTypeNotWhitelisted
+func IsTypeNotWhitelistedError(err error) bool {
+	_, ok := errors.Cause(err).(ErrTypeNotWhitelisted)
+	return ok
+}
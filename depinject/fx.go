@@ -0,0 +1,122 @@
+package depinject
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// ExportedConfig holds the providers, invokers and supplied values of a
+// Config, as registered, so that they can be registered with another
+// dependency injection framework, such as fx:
+//
+//	fx.Options(
+//	    fx.Provide(exported.Providers...),
+//	    fx.Invoke(exported.Invokers...),
+//	    fx.Supply(exported.Supplies...),
+//	)
+//
+// Note that fx requires all the inputs of invokers, while depinject treats
+// them as optional.
+type ExportedConfig struct {
+	Providers []interface{}
+	Invokers  []interface{}
+	Supplies  []interface{}
+}
+
+// ExportConfig returns the providers, invokers and supplied values of the
+// config. Only configs which don't depend on depinject features other
+// frameworks lack can be exported: it fails on module-scoped providers and
+// invokers, ProviderDescriptors, interface bindings, In and Out structs other
+// than the fx and dig ones, ModuleKey and OwnModuleKey inputs, and
+// one-per-module and many-per-container types.
+//
+// Constructors written for fx or dig don't need to be exported or adapted to
+// be used with depinject: they can be passed to Provide and Invoke directly,
+// as long as their fx.In and fx.Out structs don't use named values or value
+// groups.
+func ExportConfig(cfg Config) (ExportedConfig, error) {
+	debugCfg, err := newDebugConfig()
+	if err != nil {
+		return ExportedConfig{}, err
+	}
+
+	exporter := &configExporter{}
+	ctr := newContainer(debugCfg)
+	ctr.recorder = exporter
+	if err := cfg.apply(ctr); err != nil {
+		return ExportedConfig{}, err
+	}
+	return exporter.exported, nil
+}
+
+// configExporter records the providers, invokers and supplied values of the
+// configs applied to a container.
+type configExporter struct {
+	exported ExportedConfig
+}
+
+var _ configRecorder = (*configExporter)(nil)
+
+func (e *configExporter) recordProvider(provider interface{}, desc *providerDescriptor, key *moduleKey) error {
+	if err := checkExportable(provider, desc, key); err != nil {
+		return err
+	}
+	e.exported.Providers = append(e.exported.Providers, provider)
+	return nil
+}
+
+func (e *configExporter) recordInvoker(invoker interface{}, desc *providerDescriptor, key *moduleKey) error {
+	if err := checkExportable(invoker, desc, key); err != nil {
+		return err
+	}
+	e.exported.Invokers = append(e.exported.Invokers, invoker)
+	return nil
+}
+
+func (e *configExporter) recordBinding(b interfaceBinding) error {
+	return errors.Errorf("can't export the binding of interface %s to %s", b.interfaceName, b.implTypeName)
+}
+
+func (e *configExporter) recordSupply(value reflect.Value) error {
+	e.exported.Supplies = append(e.exported.Supplies, value.Interface())
+	return nil
+}
+
+// checkExportable checks the provider or invoker only uses types other
+// frameworks know of.
+func checkExportable(fn interface{}, desc *providerDescriptor, key *moduleKey) error {
+	if key != nil {
+		return errors.Errorf("can't export %s, scoped to module %s", desc.Location, key.name)
+	}
+	if _, ok := fn.(ProviderDescriptor); ok {
+		return errors.Errorf("can't export provider descriptor %s", desc.Location)
+	}
+
+	typ := reflect.TypeOf(fn)
+	for i := 0; i < typ.NumIn(); i++ {
+		if in := typ.In(i); in.AssignableTo(isInType) {
+			return errors.Errorf("can't export %s, which takes the depinject.In struct %v", desc.Location, in)
+		}
+	}
+	for i := 0; i < typ.NumOut(); i++ {
+		if out := typ.Out(i); out.AssignableTo(isOutType) {
+			return errors.Errorf("can't export %s, which returns the depinject.Out struct %v", desc.Location, out)
+		}
+	}
+
+	for _, in := range desc.Inputs {
+		switch {
+		case in.Type == moduleKeyType || in.Type == ownModuleKeyType:
+			return errors.Errorf("can't export %s, which takes a %v", desc.Location, in.Type)
+		case isOnePerModuleMapType(in.Type) || isManyPerContainerSliceType(in.Type):
+			return errors.Errorf("can't export %s, which takes the collected values of %v", desc.Location, in.Type.Elem())
+		}
+	}
+	for _, out := range desc.Outputs {
+		if isOnePerModuleType(out.Type) || isManyPerContainerType(out.Type) || isManyPerContainerSliceType(out.Type) {
+			return errors.Errorf("can't export %s, which returns the one-per-module or many-per-container type %v", desc.Location, out.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package depinject_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+
+	"cosmossdk.io/depinject"
+)
+
+type FxLogger struct{ Prefix string }
+
+type FxServerParams struct {
+	dig.In
+
+	Logger  FxLogger
+	Keeper  KeeperA `optional:"true"`
+	Options []string
+}
+
+type FxServer struct{ Logger FxLogger }
+
+type FxServerResult struct {
+	dig.Out
+
+	Server FxServer
+	Addr   string
+}
+
+func NewFxLogger() FxLogger { return FxLogger{Prefix: "fx"} }
+
+func NewFxServer(p FxServerParams) FxServerResult {
+	return FxServerResult{Server: FxServer{Logger: p.Logger}, Addr: "localhost"}
+}
+
+type FxNamedParams struct {
+	dig.In
+
+	Logger FxLogger `name:"main"`
+}
+
+func NewFxNamed(FxNamedParams) FxServer { return FxServer{} }
+
+func TestFxConstructors(t *testing.T) {
+	// fx parameter and result objects are expanded as In and Out structs
+	manifest, err := depinject.BuildManifest(depinject.Provide(NewFxServer))
+	require.NoError(t, err)
+	require.Equal(t, []depinject.ManifestProvider{{
+		Name: "cosmossdk.io/depinject_test.NewFxServer",
+		Inputs: []string{
+			"cosmossdk.io/depinject_test/depinject_test.FxLogger",
+			"cosmossdk.io/depinject_test/depinject_test.KeeperA (optional)",
+			"[]string",
+		},
+		Outputs: []string{
+			"cosmossdk.io/depinject_test/depinject_test.FxServer",
+			"string",
+		},
+	}}, manifest.Providers)
+
+	_, err = depinject.BuildManifest(depinject.Provide(NewFxNamed))
+	require.ErrorContains(t, err, "dig name tag of field Logger")
+}
+
+func TestExportConfig(t *testing.T) {
+	exported, err := depinject.ExportConfig(depinject.Configs(
+		depinject.Provide(NewFxLogger, NewFxServer),
+		depinject.Supply([]string{"opt"}),
+	))
+	require.NoError(t, err)
+	require.Len(t, exported.Providers, 2)
+	require.Equal(t, []interface{}{[]string{"opt"}}, exported.Supplies)
+
+	// the exported config is registered with dig, which fx is built upon
+	c := dig.New()
+	for _, p := range exported.Providers {
+		require.NoError(t, c.Provide(p))
+	}
+	for _, s := range exported.Supplies {
+		s := s
+		require.NoError(t, c.Provide(func() []string { return s.([]string) }))
+	}
+	var server FxServer
+	require.NoError(t, c.Invoke(func(s FxServer) { server = s }))
+	require.Equal(t, "fx", server.Logger.Prefix)
+
+	_, err = depinject.ExportConfig(depinject.ProvideInModule("a", NewFxLogger))
+	require.ErrorContains(t, err, "scoped to module a")
+	_, err = depinject.ExportConfig(depinject.Provide(ProvideKVStoreKey))
+	require.ErrorContains(t, err, "depinject.ModuleKey")
+	_, err = depinject.ExportConfig(depinject.BindInterface("cosmossdk.io/depinject_test/depinject_test.Duck", "cosmossdk.io/depinject_test/depinject_test.Mallard"))
+	require.ErrorContains(t, err, "can't export the binding")
+}
@@ -6,7 +6,9 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/regen-network/gocuke v0.6.2
 	github.com/stretchr/testify v1.8.1
+	go.uber.org/dig v1.15.0
 	golang.org/x/exp v0.0.0-20220722155223-a9213eeb770e
+	gopkg.in/yaml.v3 v3.0.1
 	gotest.tools/v3 v3.4.0
 )
 
@@ -23,6 +25,5 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rogpeppe/go-internal v1.8.1 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	pgregory.net/rapid v0.5.3 // indirect
 )
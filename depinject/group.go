@@ -16,7 +16,7 @@ var (
 // ManyPerContainerType marks a type which automatically gets grouped together.
 // For a ManyPerContainerType T:
 // - T and []T can be declared as output parameters multiple times
-// - All provided values can be retrieved using []T input parameter
+// - All provided values can be retrieved using []T or variadic ...T input parameter
 type ManyPerContainerType interface {
 	IsManyPerContainerType() // Marker function
 }
@@ -8,6 +8,8 @@ import (
 var (
 	ErrInvalidDebugConfig = fmt.Errorf("failed to create debug configuration")
 	ErrProviderRegistration = fmt.Errorf("failed to register providers")
+	// ErrREPLDisabled is returned by REPL in builds without the depinject_debug tag.
+	ErrREPLDisabled = fmt.Errorf("the depinject REPL requires the depinject_debug build tag")
 )
 
 // InjectionOptions holds the configuration for injection
@@ -16,6 +18,11 @@ type InjectionOptions struct {
 	debugOpt   DebugOption
 	config     Config
 	outputs    []interface{}
+
+	// recordCalls records the provider calls of the container, reusing the
+	// outputs of previousCalls, see Reload.
+	recordCalls   bool
+	previousCalls map[string]*providerCall
 }
 
 // Inject builds and runs a dependency injection container.
@@ -36,7 +43,8 @@ func Inject(containerConfig Config, outputs ...interface{}) error {
 		config:   containerConfig,
 		outputs:  outputs,
 	}
-	return runInjection(opts)
+	_, err := runInjection(opts)
+	return err
 }
 
 // InjectDebug is like Inject but with configurable debug options.
@@ -52,28 +60,32 @@ func InjectDebug(debugOpt DebugOption, config Config, outputs ...interface{}) er
 		config:   config,
 		outputs:  outputs,
 	}
-	return runInjection(opts)
+	_, err := runInjection(opts)
+	return err
 }
 
-// runInjection handles the main injection process
-func runInjection(opts InjectionOptions) error {
+// runInjection handles the main injection process and returns the built
+// container
+func runInjection(opts InjectionOptions) (*container, error) {
 	cfg, err := setupDebugConfig()
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrInvalidDebugConfig, err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDebugConfig, err)
 	}
 
 	// Ensure cleanup and graph generation on function exit
 	defer func() {
 		cfg.generateGraph()
+		cfg.generateProfile()
 		runCleanup(cfg)
 	}()
 
 	// Run injection process
-	if err := performInjection(cfg, opts); err != nil {
-		return handleInjectionError(cfg, err)
+	ctr, err := performInjection(cfg, opts)
+	if err != nil {
+		return nil, handleInjectionError(cfg, err)
 	}
 
-	return handleInjectionSuccess(cfg)
+	return ctr, handleInjectionSuccess(cfg)
 }
 
 // setupDebugConfig creates and validates the debug configuration
@@ -86,10 +98,10 @@ func setupDebugConfig() (*debugConfig, error) {
 }
 
 // performInjection executes the main injection logic
-func performInjection(cfg *debugConfig, opts InjectionOptions) error {
+func performInjection(cfg *debugConfig, opts InjectionOptions) (*container, error) {
 	if opts.debugOpt != nil {
 		if err := opts.debugOpt.applyConfig(cfg); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -97,19 +109,28 @@ func performInjection(cfg *debugConfig, opts InjectionOptions) error {
 }
 
 // buildContainer creates and configures the dependency container
-func buildContainer(cfg *debugConfig, opts InjectionOptions) error {
+func buildContainer(cfg *debugConfig, opts InjectionOptions) (*container, error) {
 	cfg.logf("Registering providers")
 	cfg.indentLogger()
 	defer cfg.dedentLogger()
 
 	container := newContainer(cfg)
+	if opts.recordCalls {
+		container.calls = make(map[string]*providerCall)
+		container.previousCalls = opts.previousCalls
+	}
 	
 	if err := opts.config.apply(container); err != nil {
 		cfg.logf("Failed registering providers: %+v", err)
-		return fmt.Errorf("%w: %v", ErrProviderRegistration, err)
+		return nil, registrationError{err}
+	}
+
+	if err := container.build(opts.location, opts.outputs...); err != nil {
+		return nil, err
 	}
 
-	return container.build(opts.location, opts.outputs...)
+	container.warnUnusedProviders()
+	return container, cfg.checkWarnings()
 }
 
 // handleInjectionError processes errors during injection
@@ -141,3 +162,21 @@ func runCleanup(cfg *debugConfig) {
 		cleanup()
 	}
 }
+
+// registrationError is an error of the providers registration, which is both
+// ErrProviderRegistration and the error of the config.
+type registrationError struct {
+	err error
+}
+
+func (e registrationError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrProviderRegistration, e.err)
+}
+
+func (e registrationError) Unwrap() error {
+	return e.err
+}
+
+func (e registrationError) Is(target error) bool {
+	return target == ErrProviderRegistration
+}
@@ -0,0 +1,324 @@
+package depinject
+
+import (
+	"reflect"
+	"sort"
+)
+
+// GraphReport is the dependency graph of a config, as structured data for
+// tooling, e.g. to validate the app wiring in CI, where the debug graph is a
+// Graphviz DOT string meant for humans.
+type GraphReport struct {
+	Providers []GraphProvider `json:"providers"`
+	// Invokers are the invokers, whose inputs are all optional.
+	Invokers []GraphProvider `json:"invokers,omitempty"`
+	// Supplies are the types of the supplied values.
+	Supplies []string          `json:"supplies,omitempty"`
+	Bindings []ManifestBinding `json:"bindings,omitempty"`
+	// Types are the types provided or consumed by the graph.
+	Types []GraphType `json:"types"`
+	// Edges are the dependencies between the providers, invokers and supplied
+	// values.
+	Edges []GraphEdge `json:"edges"`
+	// Unresolved are the required inputs which no provider, supplied value or
+	// interface binding resolves, which fail the build of the container.
+	Unresolved []GraphUnresolved `json:"unresolved,omitempty"`
+}
+
+// GraphProvider is a provider or an invoker of a GraphReport.
+type GraphProvider struct {
+	// ID identifies the provider in the types and edges of the report: its
+	// name, followed by its module in brackets when it is module-scoped.
+	ID string `json:"id"`
+	// Name is the package path qualified function name.
+	Name string `json:"name"`
+	// Module is the name of the module the provider is scoped to, if any.
+	Module   string       `json:"module,omitempty"`
+	Consumes []GraphInput `json:"consumes,omitempty"`
+	Provides []string     `json:"provides,omitempty"`
+}
+
+// GraphInput is an input of a provider or an invoker.
+type GraphInput struct {
+	Type     string `json:"type"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// GraphType is a type of a GraphReport, with the IDs of the providers,
+// invokers and supplied values providing and consuming it. The ID of a
+// supplied value is "supply" followed by its type.
+type GraphType struct {
+	Name       string   `json:"name"`
+	ProvidedBy []string `json:"provided_by,omitempty"`
+	ConsumedBy []string `json:"consumed_by,omitempty"`
+}
+
+// GraphEdge is a dependency of the To provider or invoker on the Type output
+// of the From provider or supplied value.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// GraphUnresolved is a required input of a provider which isn't resolved.
+type GraphUnresolved struct {
+	Consumer string `json:"consumer"`
+	Type     string `json:"type"`
+	// Suggestions are the provided types with names similar to Type, e.g.
+	// when Type is a misspelled interface.
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// Inspect returns the dependency graph of the config. The config is applied to
+// a container which only records its structure, as by BuildManifest:
+// providers and invokers are not called. Inputs are resolved as the container
+// does, through interface bindings, implicit interface implementations and
+// one-per-module and many-per-container types.
+func Inspect(cfg Config) (*GraphReport, error) {
+	debugCfg, err := newDebugConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := &graphRecorder{}
+	ctr := newContainer(debugCfg)
+	ctr.recorder = recorder
+	if err := cfg.apply(ctr); err != nil {
+		return nil, err
+	}
+	return recorder.report(), nil
+}
+
+// graphRecorder records the providers, invokers, bindings and supplied values
+// of the configs applied to a container, to build their GraphReport.
+type graphRecorder struct {
+	providers []graphNode
+	invokers  []graphNode
+	bindings  []interfaceBinding
+	supplies  []reflect.Type
+}
+
+// graphNode is a recorded provider or invoker.
+type graphNode struct {
+	desc *providerDescriptor
+	key  *moduleKey
+}
+
+var _ configRecorder = (*graphRecorder)(nil)
+
+func (g *graphRecorder) recordProvider(_ interface{}, desc *providerDescriptor, key *moduleKey) error {
+	g.providers = append(g.providers, graphNode{desc: desc, key: key})
+	return nil
+}
+
+func (g *graphRecorder) recordInvoker(_ interface{}, desc *providerDescriptor, key *moduleKey) error {
+	g.invokers = append(g.invokers, graphNode{desc: desc, key: key})
+	return nil
+}
+
+func (g *graphRecorder) recordBinding(b interfaceBinding) error {
+	g.bindings = append(g.bindings, b)
+	return nil
+}
+
+func (g *graphRecorder) recordSupply(value reflect.Value) error {
+	g.supplies = append(g.supplies, value.Type())
+	return nil
+}
+
+// report resolves the inputs of the recorded providers and invokers.
+func (g *graphRecorder) report() *GraphReport {
+	report := &GraphReport{Providers: []GraphProvider{}, Types: []GraphType{}, Edges: []GraphEdge{}}
+
+	// the providers of each type, by fully qualified name
+	var (
+		providedBy = make(map[string][]string)
+		consumedBy = make(map[string][]string)
+		types      = make(map[string]reflect.Type)
+	)
+	for _, typ := range g.supplies {
+		name := fullyQualifiedTypeName(typ)
+		report.Supplies = append(report.Supplies, name)
+		providedBy[name] = append(providedBy[name], "supply "+name)
+		types[name] = typ
+	}
+	for _, n := range g.providers {
+		p := newGraphProvider(n)
+		for _, out := range n.desc.Outputs {
+			name := fullyQualifiedTypeName(out.Type)
+			providedBy[name] = append(providedBy[name], p.ID)
+			types[name] = out.Type
+		}
+		report.Providers = append(report.Providers, p)
+	}
+	for _, n := range g.invokers {
+		report.Invokers = append(report.Invokers, newGraphProvider(n))
+	}
+	for _, b := range g.bindings {
+		mb := ManifestBinding{Interface: b.interfaceName, Implementation: b.implTypeName}
+		if b.moduleKey != nil {
+			mb.Module = b.moduleKey.name
+		}
+		report.Bindings = append(report.Bindings, mb)
+	}
+
+	providedTypes := make([]reflect.Type, 0, len(types))
+	for _, typ := range types {
+		providedTypes = append(providedTypes, typ)
+	}
+
+	resolve := func(consumer string, n graphNode, optional bool) {
+		for _, in := range n.desc.Inputs {
+			if in.Type == moduleKeyType || in.Type == ownModuleKeyType {
+				continue
+			}
+			name := fullyQualifiedTypeName(in.Type)
+			consumedBy[name] = append(consumedBy[name], consumer)
+
+			froms := g.resolveType(in.Type, n.key, providedBy, types)
+			for _, from := range froms {
+				report.Edges = append(report.Edges, GraphEdge{From: from.id, To: consumer, Type: from.typeName})
+			}
+			if len(froms) == 0 && !in.Optional && !optional && !isManyPerContainerSliceType(in.Type) && !isOnePerModuleMapType(in.Type) {
+				report.Unresolved = append(report.Unresolved, GraphUnresolved{
+					Consumer:    consumer,
+					Type:        name,
+					Suggestions: suggestTypes(in.Type, providedTypes),
+				})
+			}
+		}
+	}
+	for i, n := range g.providers {
+		resolve(report.Providers[i].ID, n, false)
+	}
+	for i, n := range g.invokers {
+		resolve(report.Invokers[i].ID, n, true)
+	}
+
+	names := make(map[string]bool)
+	for name := range providedBy {
+		names[name] = true
+	}
+	for name := range consumedBy {
+		names[name] = true
+	}
+	for name := range names {
+		report.Types = append(report.Types, GraphType{Name: name, ProvidedBy: providedBy[name], ConsumedBy: consumedBy[name]})
+	}
+
+	report.sort()
+	return report
+}
+
+// graphSource is a provider or supplied value resolving an input, with the
+// type it provides.
+type graphSource struct {
+	id       string
+	typeName string
+}
+
+// resolveType returns the providers and supplied values resolving an input of
+// type typ in the module of key, as the container would.
+func (g *graphRecorder) resolveType(typ reflect.Type, key *moduleKey, providedBy map[string][]string, types map[string]reflect.Type) []graphSource {
+	sources := func(name string) []graphSource {
+		var s []graphSource
+		for _, id := range providedBy[name] {
+			s = append(s, graphSource{id: id, typeName: name})
+		}
+		return s
+	}
+
+	switch {
+	case isManyPerContainerSliceType(typ), isOnePerModuleMapType(typ):
+		return sources(fullyQualifiedTypeName(typ.Elem()))
+
+	case typ.Kind() == reflect.Interface:
+		name := fullyQualifiedTypeName(typ)
+		if s := sources(name); len(s) > 0 {
+			return s
+		}
+		// a binding to the module of the consumer takes precedence over a
+		// global one
+		var impl string
+		for _, b := range g.bindings {
+			if b.interfaceName != name {
+				continue
+			}
+			if b.moduleKey == nil && impl == "" {
+				impl = b.implTypeName
+			} else if b.moduleKey != nil && key != nil && b.moduleKey.name == key.name {
+				impl = b.implTypeName
+				break
+			}
+		}
+		if impl != "" {
+			return sources(impl)
+		}
+
+		var implementations []graphSource
+		for implName, implType := range types {
+			if implType.Kind() != reflect.Interface && implType.Implements(typ) {
+				implementations = append(implementations, sources(implName)...)
+			}
+		}
+		sort.Slice(implementations, func(i, j int) bool { return implementations[i].id < implementations[j].id })
+		return implementations
+
+	default:
+		return sources(fullyQualifiedTypeName(typ))
+	}
+}
+
+func newGraphProvider(n graphNode) GraphProvider {
+	p := GraphProvider{Name: n.desc.Location.Name()}
+	if n.key != nil {
+		p.Module = n.key.name
+	}
+	p.ID = p.Name + moduleSuffix(p.Module)
+	for _, in := range n.desc.Inputs {
+		p.Consumes = append(p.Consumes, GraphInput{Type: fullyQualifiedTypeName(in.Type), Optional: in.Optional})
+	}
+	for _, out := range n.desc.Outputs {
+		p.Provides = append(p.Provides, fullyQualifiedTypeName(out.Type))
+	}
+	return p
+}
+
+// sort orders the entries of the report, so that it doesn't depend on the
+// order of the configs.
+func (r *GraphReport) sort() {
+	sortProviders := func(ps []GraphProvider) {
+		sort.SliceStable(ps, func(i, j int) bool { return ps[i].ID < ps[j].ID })
+	}
+	sortProviders(r.Providers)
+	sortProviders(r.Invokers)
+	sort.Strings(r.Supplies)
+	sort.SliceStable(r.Bindings, func(i, j int) bool {
+		if r.Bindings[i].Interface != r.Bindings[j].Interface {
+			return r.Bindings[i].Interface < r.Bindings[j].Interface
+		}
+		return r.Bindings[i].Module < r.Bindings[j].Module
+	})
+	for _, t := range r.Types {
+		sort.Strings(t.ProvidedBy)
+		sort.Strings(t.ConsumedBy)
+	}
+	sort.Slice(r.Types, func(i, j int) bool { return r.Types[i].Name < r.Types[j].Name })
+	sort.SliceStable(r.Edges, func(i, j int) bool {
+		a, b := r.Edges[i], r.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Type < b.Type
+	})
+	sort.SliceStable(r.Unresolved, func(i, j int) bool {
+		if r.Unresolved[i].Consumer != r.Unresolved[j].Consumer {
+			return r.Unresolved[i].Consumer < r.Unresolved[j].Consumer
+		}
+		return r.Unresolved[i].Type < r.Unresolved[j].Type
+	})
+}
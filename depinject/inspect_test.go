@@ -0,0 +1,59 @@
+package depinject_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/depinject"
+)
+
+func ProvideKeeperDFromB(KeeperB) KeeperD { return KeeperD{} }
+
+func TestInspect(t *testing.T) {
+	config := depinject.Configs(
+		depinject.Provide(ProvideKVStoreKey, ProvideKeeperDFromB),
+		depinject.ProvideInModule("a", ProvideKeeperA),
+		depinject.Invoke(InvokeKeeperA),
+		depinject.Supply(MsgClientA{key: "a"}),
+	)
+
+	report, err := depinject.Inspect(config)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, p := range report.Providers {
+		ids = append(ids, p.ID)
+	}
+	require.Equal(t, []string{
+		"cosmossdk.io/depinject_test.ProvideKVStoreKey",
+		"cosmossdk.io/depinject_test.ProvideKeeperA [a]",
+		"cosmossdk.io/depinject_test.ProvideKeeperDFromB",
+	}, ids)
+	require.Equal(t, "a", report.Providers[1].Module)
+	require.Equal(t, []string{"cosmossdk.io/depinject_test/depinject_test.MsgClientA"}, report.Supplies)
+
+	require.Equal(t, []depinject.GraphEdge{{
+		From: "cosmossdk.io/depinject_test.ProvideKVStoreKey",
+		To:   "cosmossdk.io/depinject_test.ProvideKeeperA [a]",
+		Type: "cosmossdk.io/depinject_test/depinject_test.KVStoreKey",
+	}, {
+		From: "cosmossdk.io/depinject_test.ProvideKeeperA [a]",
+		To:   "cosmossdk.io/depinject_test.InvokeKeeperA",
+		Type: "cosmossdk.io/depinject_test/depinject_test.KeeperA",
+	}}, report.Edges)
+
+	require.Contains(t, report.Types, depinject.GraphType{
+		Name:       "cosmossdk.io/depinject_test/depinject_test.KeeperA",
+		ProvidedBy: []string{"cosmossdk.io/depinject_test.ProvideKeeperA [a]"},
+		ConsumedBy: []string{"cosmossdk.io/depinject_test.InvokeKeeperA"},
+	})
+	require.Equal(t, []depinject.GraphUnresolved{{
+		Consumer: "cosmossdk.io/depinject_test.ProvideKeeperDFromB",
+		Type:     "cosmossdk.io/depinject_test/depinject_test.KeeperB",
+		Suggestions: []string{
+			"cosmossdk.io/depinject_test/depinject_test.KeeperA",
+			"cosmossdk.io/depinject_test/depinject_test.KeeperD",
+		},
+	}}, report.Unresolved)
+}
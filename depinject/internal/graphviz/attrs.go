@@ -44,6 +44,10 @@ func (a *Attributes) SetFontColor(color string) { a.SetAttr("fontcolor", color)
 // SetFontSize sets the fontsize attribute.
 func (a *Attributes) SetFontSize(size string) { a.SetAttr("fontsize", size) }
 
+// SetTooltip sets the tooltip attribute, shown when hovering over SVG and
+// image map renderings.
+func (a *Attributes) SetTooltip(tooltip string) { a.SetAttr("tooltip", tooltip) }
+
 // SetStyle sets the style attribute.
 func (a *Attributes) SetStyle(style string) { a.SetAttr("style", style) }
 
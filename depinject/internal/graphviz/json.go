@@ -0,0 +1,81 @@
+package graphviz
+
+import (
+	"encoding/json"
+	"io"
+
+	"cosmossdk.io/depinject/internal/util"
+)
+
+// JSONGraph is the machine-readable representation of a graph, where
+// sub-graphs, nodes and edges are flattened and sorted by name.
+type JSONGraph struct {
+	Attrs     map[string]string `json:"attrs,omitempty"`
+	Subgraphs []JSONSubgraph    `json:"subgraphs"`
+	Nodes     []JSONNode        `json:"nodes"`
+	Edges     []JSONEdge        `json:"edges"`
+}
+
+// JSONSubgraph is a sub-graph of a JSONGraph.
+type JSONSubgraph struct {
+	Name string `json:"name"`
+	// Parent is the name of the parent sub-graph, empty for the root graph.
+	Parent string            `json:"parent,omitempty"`
+	Attrs  map[string]string `json:"attrs,omitempty"`
+}
+
+// JSONNode is a node of a JSONGraph.
+type JSONNode struct {
+	Name string `json:"name"`
+	// Subgraph is the name of the sub-graph of the node, empty for the root
+	// graph.
+	Subgraph string            `json:"subgraph,omitempty"`
+	Attrs    map[string]string `json:"attrs,omitempty"`
+}
+
+// JSONEdge is an edge of a JSONGraph, between the named nodes.
+type JSONEdge struct {
+	From  string            `json:"from"`
+	To    string            `json:"to"`
+	Attrs map[string]string `json:"attrs,omitempty"`
+}
+
+// JSON returns the machine-readable representation of the graph.
+func (g *Graph) JSON() JSONGraph {
+	res := JSONGraph{
+		Attrs:     g.attrs,
+		Subgraphs: []JSONSubgraph{},
+		Nodes:     []JSONNode{},
+		Edges:     []JSONEdge{},
+	}
+	g.appendJSON(&res)
+	return res
+}
+
+func (g *Graph) appendJSON(res *JSONGraph) {
+	subgraph := ""
+	if g.parent != nil {
+		subgraph = g.name
+	}
+
+	_ = util.IterateMapOrdered(g.subgraphs, func(name string, sub *Graph) error {
+		res.Subgraphs = append(res.Subgraphs, JSONSubgraph{Name: name, Parent: subgraph, Attrs: sub.attrs})
+		sub.appendJSON(res)
+		return nil
+	})
+	_ = util.IterateMapOrdered(g.myNodes, func(name string, node *Node) error {
+		res.Nodes = append(res.Nodes, JSONNode{Name: name, Subgraph: subgraph, Attrs: node.attrs})
+		return nil
+	})
+	for _, edge := range g.edges {
+		res.Edges = append(res.Edges, JSONEdge{From: edge.from.name, To: edge.to.name, Attrs: edge.attrs})
+	}
+}
+
+// RenderJSON renders the graph as indented JSON.
+func (g *Graph) RenderJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(g.JSON())
+}
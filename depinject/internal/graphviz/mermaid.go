@@ -0,0 +1,115 @@
+package graphviz
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"cosmossdk.io/depinject/internal/util"
+)
+
+// mermaidEscaper escapes the characters of labels which mermaid would
+// otherwise interpret.
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+// RenderMermaid renders the graph as a mermaid flowchart, which can be
+// embedded in Markdown documents. Sub-graphs are rendered as mermaid
+// sub-graphs, box nodes as rectangles and other nodes as stadiums. Colors and
+// pen widths are rendered as node styles, other attributes are ignored.
+func (g *Graph) RenderMermaid(w io.Writer) error {
+	// mermaid ids can't contain most of the characters of type names, so nodes
+	// and sub-graphs are numbered in the order they are rendered
+	ids := map[*Node]string{}
+	for i, name := range util.OrderedMapKeys(g.allNodes) {
+		ids[g.allNodes[name]] = fmt.Sprintf("n%d", i)
+	}
+
+	if _, err := fmt.Fprintln(w, "flowchart LR"); err != nil {
+		return err
+	}
+	sgCount := 0
+	var styles []string
+	if err := g.renderMermaid(w, "  ", ids, &sgCount, &styles); err != nil {
+		return err
+	}
+	for _, style := range styles {
+		if _, err := fmt.Fprintf(w, "  %s\n", style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Graph) renderMermaid(w io.Writer, indent string, ids map[*Node]string, sgCount *int, styles *[]string) error {
+	err := util.IterateMapOrdered(g.subgraphs, func(name string, subgraph *Graph) error {
+		label := name
+		if l, ok := subgraph.attrs["label"]; ok {
+			label = l
+		}
+		id := fmt.Sprintf("sg%d", *sgCount)
+		*sgCount++
+		if _, err := fmt.Fprintf(w, "%ssubgraph %s [\"%s\"]\n", indent, id, mermaidEscaper.Replace(label)); err != nil {
+			return err
+		}
+		if err := subgraph.renderMermaid(w, indent+"  ", ids, sgCount, styles); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(w, "%send\n", indent)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = util.IterateMapOrdered(g.myNodes, func(name string, node *Node) error {
+		id := ids[node]
+		label := mermaidEscaper.Replace(name)
+		var err error
+		if node.attrs["shape"] == "box" {
+			_, err = fmt.Fprintf(w, "%s%s[\"%s\"]\n", indent, id, label)
+		} else {
+			_, err = fmt.Fprintf(w, "%s%s([\"%s\"])\n", indent, id, label)
+		}
+		if style := node.mermaidStyle(); style != "" {
+			*styles = append(*styles, fmt.Sprintf("style %s %s", id, style))
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, edge := range g.edges {
+		if _, err := fmt.Fprintf(w, "%s%s --> %s\n", indent, ids[edge.from], ids[edge.to]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mermaidStyle returns the mermaid style of the color, font color and pen
+// width attributes of the node.
+func (n Node) mermaidStyle() string {
+	var style []string
+	if color, ok := n.attrs["color"]; ok {
+		style = append(style, "stroke:"+color)
+	}
+	if w, ok := n.attrs["penwidth"]; ok {
+		style = append(style, fmt.Sprintf("stroke-width:%spx", w))
+	}
+	if color, ok := n.attrs["fontcolor"]; ok {
+		style = append(style, "color:"+color)
+	}
+	return strings.Join(style, ",")
+}
+
+// Mermaid returns the graph as a mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	buf := &bytes.Buffer{}
+	err := g.RenderMermaid(buf)
+	if err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
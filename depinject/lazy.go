@@ -0,0 +1,15 @@
+package depinject
+
+// Lazy makes the container only call the providers whose outputs are requested,
+// by an output pointer of Inject or by the inputs of another provider or of an
+// invoker, instead of resolving the whole graph when it is built. Apps with
+// many optional modules then don't pay for the keepers they don't use.
+//
+// Errors of the providers which aren't called, e.g. missing dependencies, are
+// not reported in lazy mode.
+func Lazy() Config {
+	return containerConfig(func(ctr *container) error {
+		ctr.lazy = true
+		return nil
+	})
+}
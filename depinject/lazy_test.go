@@ -0,0 +1,50 @@
+package depinject_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/depinject"
+)
+
+func ProvideKeeperAFromMissing(_ MsgClientA) KeeperA { return KeeperA{} }
+
+// countedKeeperDCalls counts the calls of ProvideCountedKeeperD.
+var countedKeeperDCalls int
+
+func ProvideCountedKeeperD(key KVStoreKey) KeeperD {
+	countedKeeperDCalls++
+	return KeeperD{key: key}
+}
+
+func TestLazy(t *testing.T) {
+	config := depinject.Configs(
+		depinject.Supply(KVStoreKey{name: "a"}),
+		depinject.Provide(ProvideCountedKeeperD),
+	)
+	missing := depinject.Provide(ProvideKeeperAFromMissing)
+
+	// all providers are called, and a missing dependency fails the build
+	countedKeeperDCalls = 0
+	var key KVStoreKey
+	require.NoError(t, depinject.Inject(config, &key))
+	require.Equal(t, 1, countedKeeperDCalls)
+	err := depinject.Inject(depinject.Configs(config, missing), &key)
+	require.True(t, depinject.IsTypeNotResolvableError(err))
+
+	// only the providers of the outputs are called
+	lazy := depinject.Configs(depinject.Lazy(), config, missing)
+	countedKeeperDCalls = 0
+	require.NoError(t, depinject.Inject(lazy, &key))
+	require.Equal(t, "a", key.name)
+	require.Equal(t, 0, countedKeeperDCalls)
+
+	var d KeeperD
+	require.NoError(t, depinject.Inject(lazy, &d))
+	require.Equal(t, 1, countedKeeperDCalls)
+
+	var a KeeperA
+	err = depinject.Inject(lazy, &a)
+	require.True(t, depinject.IsTypeNotResolvableError(err))
+}
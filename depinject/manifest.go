@@ -0,0 +1,233 @@
+package depinject
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestVersion is the version of the manifest format written by
+// Manifest.Encode.
+const ManifestVersion = 1
+
+// ErrManifestMismatch is returned by VerifyManifest when the structure of a
+// config differs from its manifest.
+var ErrManifestMismatch = errors.New("config doesn't match its manifest")
+
+// Manifest is the declarative structure of a composed Config: its providers
+// and invokers, by package and function name, with their module scopes and
+// types, its interface bindings and its supplied types. It is serialized to
+// YAML so that the app wiring can be reviewed and audited, and verified at
+// runtime with VerifyManifest.
+type Manifest struct {
+	Version   int                `yaml:"version"`
+	Providers []ManifestProvider `yaml:"providers,omitempty"`
+	Invokers  []ManifestProvider `yaml:"invokers,omitempty"`
+	Bindings  []ManifestBinding  `yaml:"bindings,omitempty"`
+	Supplies  []ManifestSupply   `yaml:"supplies,omitempty"`
+}
+
+// ManifestProvider is a provider or an invoker of a manifest.
+type ManifestProvider struct {
+	// Name is the package path qualified function name.
+	Name string `yaml:"name"`
+	// Module is the name of the module the provider is scoped to, if any.
+	Module  string   `yaml:"module,omitempty"`
+	Inputs  []string `yaml:"inputs,omitempty"`
+	Outputs []string `yaml:"outputs,omitempty"`
+}
+
+// ManifestBinding is an interface binding of a manifest.
+type ManifestBinding struct {
+	Interface      string `yaml:"interface"`
+	Implementation string `yaml:"implementation"`
+	Module         string `yaml:"module,omitempty"`
+}
+
+// ManifestSupply is the type of a supplied value of a manifest.
+type ManifestSupply struct {
+	Type string `yaml:"type"`
+}
+
+// BuildManifest returns the manifest of the config. The config is applied to
+// a container which only records its structure: providers and invokers are
+// not called.
+func BuildManifest(cfg Config) (*Manifest, error) {
+	debugCfg, err := newDebugConfig()
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{Version: ManifestVersion}
+	ctr := newContainer(debugCfg)
+	ctr.recorder = manifest
+	if err := cfg.apply(ctr); err != nil {
+		return nil, err
+	}
+
+	manifest.sort()
+	return manifest, nil
+}
+
+// ParseManifest decodes a YAML manifest.
+func ParseManifest(bz []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(bz, &m); err != nil {
+		return nil, errors.Wrap(err, "invalid manifest")
+	}
+	if m.Version != ManifestVersion {
+		return nil, errors.Errorf("unsupported manifest version %d, expected %d", m.Version, ManifestVersion)
+	}
+
+	m.sort()
+	return &m, nil
+}
+
+// Encode returns the YAML encoding of the manifest.
+func (m *Manifest) Encode() ([]byte, error) {
+	return yaml.Marshal(m)
+}
+
+// VerifyManifest checks that the structure of the config, as compiled in the
+// running binary, matches the YAML manifest. It returns an
+// ErrManifestMismatch error listing the differences otherwise.
+func VerifyManifest(cfg Config, manifest []byte) error {
+	expected, err := ParseManifest(manifest)
+	if err != nil {
+		return err
+	}
+	actual, err := BuildManifest(cfg)
+	if err != nil {
+		return err
+	}
+
+	if diff := expected.Diff(actual); len(diff) > 0 {
+		return errors.Wrapf(ErrManifestMismatch, "\n%s", strings.Join(diff, "\n"))
+	}
+	return nil
+}
+
+// Diff returns the entries of the manifest missing from other, prefixed with
+// "-", and the entries of other missing from the manifest, prefixed with "+".
+func (m *Manifest) Diff(other *Manifest) []string {
+	expected, actual := m.entries(), other.entries()
+
+	var diff []string
+	for entry, n := range expected {
+		for i := actual[entry]; i < n; i++ {
+			diff = append(diff, "- "+entry)
+		}
+	}
+	for entry, n := range actual {
+		for i := expected[entry]; i < n; i++ {
+			diff = append(diff, "+ "+entry)
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i][2:] < diff[j][2:] })
+	return diff
+}
+
+// entries counts the entries of the manifest, in a one line description.
+func (m *Manifest) entries() map[string]int {
+	entries := make(map[string]int)
+	for _, p := range m.Providers {
+		entries["provider "+describeProvider(p)]++
+	}
+	for _, p := range m.Invokers {
+		entries["invoker "+describeProvider(p)]++
+	}
+	for _, b := range m.Bindings {
+		entries["binding "+describeBinding(b)]++
+	}
+	for _, s := range m.Supplies {
+		entries["supply "+s.Type]++
+	}
+	return entries
+}
+
+// describeProvider returns a one line description of a provider or invoker.
+func describeProvider(p ManifestProvider) string {
+	return fmt.Sprintf("%s%s(%s) -> (%s)", p.Name, moduleSuffix(p.Module),
+		strings.Join(p.Inputs, ", "), strings.Join(p.Outputs, ", "))
+}
+
+// describeBinding returns a one line description of an interface binding.
+func describeBinding(b ManifestBinding) string {
+	return fmt.Sprintf("%s -> %s%s", b.Interface, b.Implementation, moduleSuffix(b.Module))
+}
+
+func moduleSuffix(module string) string {
+	if module == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", module)
+}
+
+// sort orders the entries of the manifest, so that its encoding doesn't depend
+// on the order of the configs.
+func (m *Manifest) sort() {
+	sortProviders := func(ps []ManifestProvider) {
+		sort.SliceStable(ps, func(i, j int) bool {
+			if ps[i].Name != ps[j].Name {
+				return ps[i].Name < ps[j].Name
+			}
+			return ps[i].Module < ps[j].Module
+		})
+	}
+	sortProviders(m.Providers)
+	sortProviders(m.Invokers)
+	sort.SliceStable(m.Bindings, func(i, j int) bool {
+		if m.Bindings[i].Interface != m.Bindings[j].Interface {
+			return m.Bindings[i].Interface < m.Bindings[j].Interface
+		}
+		return m.Bindings[i].Module < m.Bindings[j].Module
+	})
+	sort.SliceStable(m.Supplies, func(i, j int) bool { return m.Supplies[i].Type < m.Supplies[j].Type })
+}
+
+var _ configRecorder = (*Manifest)(nil)
+
+func (m *Manifest) recordProvider(_ interface{}, desc *providerDescriptor, key *moduleKey) error {
+	m.Providers = append(m.Providers, newManifestProvider(desc, key))
+	return nil
+}
+
+func (m *Manifest) recordInvoker(_ interface{}, desc *providerDescriptor, key *moduleKey) error {
+	m.Invokers = append(m.Invokers, newManifestProvider(desc, key))
+	return nil
+}
+
+func (m *Manifest) recordBinding(b interfaceBinding) error {
+	mb := ManifestBinding{Interface: b.interfaceName, Implementation: b.implTypeName}
+	if b.moduleKey != nil {
+		mb.Module = b.moduleKey.name
+	}
+	m.Bindings = append(m.Bindings, mb)
+	return nil
+}
+
+func (m *Manifest) recordSupply(value reflect.Value) error {
+	m.Supplies = append(m.Supplies, ManifestSupply{Type: fullyQualifiedTypeName(value.Type())})
+	return nil
+}
+
+func newManifestProvider(desc *providerDescriptor, key *moduleKey) ManifestProvider {
+	p := ManifestProvider{Name: desc.Location.Name()}
+	if key != nil {
+		p.Module = key.name
+	}
+	for _, in := range desc.Inputs {
+		typ := fullyQualifiedTypeName(in.Type)
+		if in.Optional {
+			typ += " (optional)"
+		}
+		p.Inputs = append(p.Inputs, typ)
+	}
+	for _, out := range desc.Outputs {
+		p.Outputs = append(p.Outputs, fullyQualifiedTypeName(out.Type))
+	}
+	return p
+}
@@ -0,0 +1,76 @@
+package depinject_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/depinject"
+)
+
+func ProvideKeeperA(key KVStoreKey) KeeperA {
+	return KeeperA{key: key}
+}
+
+func InvokeKeeperA(KeeperA) {}
+
+func TestManifest(t *testing.T) {
+	config := depinject.Configs(
+		depinject.Provide(ProvideKVStoreKey),
+		depinject.ProvideInModule("a", ProvideKeeperA),
+		depinject.Invoke(InvokeKeeperA),
+		depinject.BindInterfaceInModule("a", "cosmossdk.io/depinject_test/depinject_test.Duck", "cosmossdk.io/depinject_test/depinject_test.Mallard"),
+		depinject.Supply(MsgClientA{key: "a"}),
+	)
+
+	manifest, err := depinject.BuildManifest(config)
+	require.NoError(t, err)
+	require.Equal(t, []depinject.ManifestProvider{{
+		Name:    "cosmossdk.io/depinject_test.ProvideKVStoreKey",
+		Inputs:  []string{"cosmossdk.io/depinject/depinject.ModuleKey"},
+		Outputs: []string{"cosmossdk.io/depinject_test/depinject_test.KVStoreKey"},
+	}, {
+		Name:    "cosmossdk.io/depinject_test.ProvideKeeperA",
+		Module:  "a",
+		Inputs:  []string{"cosmossdk.io/depinject_test/depinject_test.KVStoreKey"},
+		Outputs: []string{"cosmossdk.io/depinject_test/depinject_test.KeeperA"},
+	}}, manifest.Providers)
+	require.Len(t, manifest.Invokers, 1)
+	require.Equal(t, "cosmossdk.io/depinject_test.InvokeKeeperA", manifest.Invokers[0].Name)
+	require.Equal(t, []depinject.ManifestBinding{{
+		Interface:      "cosmossdk.io/depinject_test/depinject_test.Duck",
+		Implementation: "cosmossdk.io/depinject_test/depinject_test.Mallard",
+		Module:         "a",
+	}}, manifest.Bindings)
+	require.Equal(t, []depinject.ManifestSupply{{Type: "cosmossdk.io/depinject_test/depinject_test.MsgClientA"}}, manifest.Supplies)
+
+	bz, err := manifest.Encode()
+	require.NoError(t, err)
+	require.NoError(t, depinject.VerifyManifest(config, bz))
+
+	// the order of the configs doesn't matter
+	require.NoError(t, depinject.VerifyManifest(depinject.Configs(
+		depinject.Supply(MsgClientA{key: "b"}),
+		depinject.Invoke(InvokeKeeperA),
+		depinject.ProvideInModule("a", ProvideKeeperA),
+		depinject.BindInterfaceInModule("a", "cosmossdk.io/depinject_test/depinject_test.Duck", "cosmossdk.io/depinject_test/depinject_test.Mallard"),
+		depinject.Provide(ProvideKVStoreKey),
+	), bz))
+
+	err = depinject.VerifyManifest(depinject.Configs(
+		depinject.Provide(ProvideKVStoreKey),
+		depinject.ProvideInModule("b", ProvideKeeperA),
+		depinject.Invoke(InvokeKeeperA),
+		depinject.BindInterfaceInModule("a", "cosmossdk.io/depinject_test/depinject_test.Duck", "cosmossdk.io/depinject_test/depinject_test.Mallard"),
+	), bz)
+	require.ErrorIs(t, err, depinject.ErrManifestMismatch)
+	require.ErrorContains(t, err, "- provider cosmossdk.io/depinject_test.ProvideKeeperA [a]")
+	require.ErrorContains(t, err, "+ provider cosmossdk.io/depinject_test.ProvideKeeperA [b]")
+	require.ErrorContains(t, err, "- supply cosmossdk.io/depinject_test/depinject_test.MsgClientA")
+
+	_, err = depinject.BuildManifest(depinject.Error(errors.New("config error")))
+	require.ErrorContains(t, err, "config error")
+	_, err = depinject.ParseManifest([]byte("version: 2\n"))
+	require.ErrorContains(t, err, "unsupported manifest version")
+}
@@ -0,0 +1,60 @@
+package depinject
+
+import "github.com/pkg/errors"
+
+// HasProviders is a module registering its providers with RegisterModules.
+// The providers are scoped to the module named by ModuleName, as by
+// ProvideInModule.
+type HasProviders interface {
+	ModuleName() string
+	Providers() []interface{}
+}
+
+// HasInvokers is a module registered with RegisterModules which also has
+// invokers, scoped to the module as by InvokeInModule.
+type HasInvokers interface {
+	Invokers() []interface{}
+}
+
+// RegisterModules registers the providers of each module, and its invokers if
+// it implements HasInvokers, in the scope of the module, so that app configs
+// don't have to repeat the module name for each of them:
+//
+//	RegisterModules(bank.Module{}, staking.Module{})
+//
+// is equivalent to:
+//
+//	Configs(
+//	    ProvideInModule("bank", bank.Module{}.Providers()...),
+//	    InvokeInModule("bank", bank.Module{}.Invokers()...),
+//	    ProvideInModule("staking", staking.Module{}.Providers()...),
+//	)
+//
+// Registering two modules with the same name fails.
+func RegisterModules(mods ...HasProviders) Config {
+	loc := LocationFromCaller(1)
+	return containerConfig(func(ctr *container) error {
+		registered := make(map[string]bool, len(mods))
+		for _, mod := range mods {
+			name := mod.ModuleName()
+			if name == "" {
+				return errors.Wrapf(ErrEmptyModuleName, "registering module %T", mod)
+			}
+			if registered[name] {
+				return errors.Errorf("module %s is registered twice", name)
+			}
+			registered[name] = true
+
+			key := ctr.moduleKeyContext.createOrGetModuleKey(name)
+			if err := provide(ctr, key, mod.Providers(), loc); err != nil {
+				return errors.Wrapf(err, "registering module %s", name)
+			}
+			if invokers, ok := mod.(HasInvokers); ok {
+				if err := invoke(ctr, key, invokers.Invokers(), loc); err != nil {
+					return errors.Wrapf(err, "registering module %s", name)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,47 @@
+package depinject_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/depinject"
+)
+
+type registeredModuleA struct{}
+
+func (registeredModuleA) ModuleName() string { return "a" }
+
+func (registeredModuleA) Providers() []interface{} {
+	return []interface{}{ProvideKVStoreKey, ProvideKeeperA}
+}
+
+func (registeredModuleA) Invokers() []interface{} { return []interface{}{InvokeKeeperA} }
+
+type registeredModuleB struct{ name string }
+
+func (m registeredModuleB) ModuleName() string { return m.name }
+
+func (registeredModuleB) Providers() []interface{} { return []interface{}{ProvideMsgClientA} }
+
+func TestRegisterModules(t *testing.T) {
+	manifest, err := depinject.BuildManifest(depinject.RegisterModules(registeredModuleA{}, registeredModuleB{name: "b"}))
+	require.NoError(t, err)
+
+	modules := map[string]string{}
+	for _, p := range manifest.Providers {
+		modules[p.Name] = p.Module
+	}
+	require.Equal(t, map[string]string{
+		"cosmossdk.io/depinject_test.ProvideKVStoreKey": "a",
+		"cosmossdk.io/depinject_test.ProvideKeeperA":    "a",
+		"cosmossdk.io/depinject_test.ProvideMsgClientA": "b",
+	}, modules)
+	require.Len(t, manifest.Invokers, 1)
+	require.Equal(t, "a", manifest.Invokers[0].Module)
+
+	_, err = depinject.BuildManifest(depinject.RegisterModules(registeredModuleB{name: "b"}, registeredModuleB{name: "b"}))
+	require.ErrorContains(t, err, "module b is registered twice")
+	_, err = depinject.BuildManifest(depinject.RegisterModules(registeredModuleB{}))
+	require.ErrorIs(t, err, depinject.ErrEmptyModuleName)
+}
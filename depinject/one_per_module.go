@@ -3,6 +3,7 @@ package depinject
 import (
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/pkg/errors"
 
@@ -55,20 +56,22 @@ func (o *onePerModuleResolver) describeLocation() string {
 
 func (o *mapOfOnePerModuleResolver) resolve(c *container, _ *moduleKey, caller Location) (reflect.Value, error) {
 	// Log
+	keys := o.sortedModuleKeys()
 	c.logf("Providing one-per-module type map %v to %s from:", o.mapType, caller.Name())
 	c.indentLogger()
-	for key, node := range o.providers {
-		c.logf("%s: %s", key.name, node.provider.Location)
+	for _, key := range keys {
+		c.logf("%s: %s", key.name, o.providers[key].provider.Location)
 	}
 	c.dedentLogger()
 
 	// Resolve
 	if !o.resolved {
 		res := reflect.MakeMap(o.mapType)
-		for key, node := range o.providers {
+		for _, key := range keys {
+			node := o.providers[key]
 			values, err := node.resolveValues(c)
 			if err != nil {
-				return reflect.Value{}, err
+				return reflect.Value{}, errors.Wrapf(err, "resolving one-per-module type %v of module %s", o.typ, key.name)
 			}
 			idx := o.idxMap[key]
 			if len(values) <= idx {
@@ -87,12 +90,12 @@ func (o *mapOfOnePerModuleResolver) resolve(c *container, _ *moduleKey, caller L
 
 func (o *onePerModuleResolver) addNode(n *simpleProvider, i int) error {
 	if n.moduleKey == nil {
-		return errors.Errorf("cannot define a provider with one-per-module dependency %v which isn't provided in a module", o.typ)
+		return errors.Errorf("cannot define a provider with one-per-module dependency %v which isn't provided in a module: %s, use ProvideInModule",
+			o.typ, n.provider.Location)
 	}
 
 	if existing, ok := o.providers[n.moduleKey]; ok {
-		return errors.Errorf("duplicate provision for one-per-module type %v in module %s: %s\n\talready provided by %s",
-			o.typ, n.moduleKey.name, n.provider.Location, existing.provider.Location)
+		return errors.WithStack(newErrDuplicateOnePerModule(o.typ, n.moduleKey.name, n.provider.Location, existing.provider.Location))
 	}
 
 	o.providers[n.moduleKey] = n
@@ -108,3 +111,72 @@ func (o *mapOfOnePerModuleResolver) addNode(s *simpleProvider, _ int) error {
 func (o onePerModuleResolver) typeGraphNode() *graphviz.Node {
 	return o.graphNode
 }
+
+// sortedModuleKeys returns the keys of the modules providing the type, sorted
+// by name, so that logs and errors don't depend on map iteration.
+func (o *onePerModuleResolver) sortedModuleKeys() []*moduleKey {
+	keys := make([]*moduleKey, 0, len(o.providers))
+	for key := range o.providers {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+	return keys
+}
+
+// ProvideOnePerModule provides value as the value of the one-per-module type T
+// of the module, without declaring a provider function:
+//
+//	ProvideOnePerModule("bank", Handler{...})
+//
+// Providing two values of T in the same module fails with
+// ErrDuplicateOnePerModule, naming both provider locations.
+func ProvideOnePerModule[T OnePerModuleType](moduleName string, value T) Config {
+	loc := LocationFromCaller(1)
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	desc := ProviderDescriptor{
+		Name:    fmt.Sprintf("ProvideOnePerModule[%v]", typ),
+		Outputs: []reflect.Type{typ},
+		Fn: func([]interface{}) ([]interface{}, error) {
+			return []interface{}{value}, nil
+		},
+	}
+	return containerConfig(func(ctr *container) error {
+		if moduleName == "" {
+			return ErrEmptyModuleName
+		}
+		return provide(ctr, ctr.moduleKeyContext.createOrGetModuleKey(moduleName), []interface{}{desc}, loc)
+	})
+}
+
+// ModuleValue is the value of a one-per-module type of a module.
+type ModuleValue[T OnePerModuleType] struct {
+	Module string
+	Value  T
+}
+
+// SortedModuleValues returns the values of a one-per-module map input sorted
+// by module name, for consumers which must iterate them deterministically,
+// e.g. to register routes or run migrations.
+func SortedModuleValues[T OnePerModuleType](values map[string]T) []ModuleValue[T] {
+	res := make([]ModuleValue[T], 0, len(values))
+	for module, value := range values {
+		res = append(res, ModuleValue[T]{Module: module, Value: value})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Module < res[j].Module })
+	return res
+}
+
+// RequireModules checks a one-per-module map input has a value for each of the
+// modules, and returns an error naming the modules which don't provide one.
+func RequireModules[T OnePerModuleType](values map[string]T, modules ...string) error {
+	var missing []string
+	for _, module := range modules {
+		if _, ok := values[module]; !ok {
+			missing = append(missing, module)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("modules %v don't provide one-per-module type %v", missing, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return nil
+}
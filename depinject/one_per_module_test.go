@@ -0,0 +1,49 @@
+package depinject_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/depinject"
+)
+
+func TestProvideOnePerModule(t *testing.T) {
+	var handlers map[string]Handler
+	require.NoError(t, depinject.Inject(
+		depinject.Configs(
+			depinject.ProvideOnePerModule("b", Handler{}),
+			depinject.ProvideOnePerModule("a", Handler{}),
+		),
+		&handlers,
+	))
+	require.Len(t, handlers, 2)
+	require.Equal(t, []depinject.ModuleValue[Handler]{
+		{Module: "a", Value: Handler{}},
+		{Module: "b", Value: Handler{}},
+	}, depinject.SortedModuleValues(handlers))
+
+	require.NoError(t, depinject.RequireModules(handlers, "a", "b"))
+	err := depinject.RequireModules(handlers, "a", "c", "d")
+	require.ErrorContains(t, err, "[c d]")
+
+	err = depinject.Inject(
+		depinject.ProvideOnePerModule("", Handler{}),
+		&handlers,
+	)
+	require.ErrorIs(t, err, depinject.ErrEmptyModuleName)
+}
+
+func TestDuplicateOnePerModule(t *testing.T) {
+	var handlers map[string]Handler
+	err := depinject.Inject(
+		depinject.Configs(
+			depinject.ProvideOnePerModule("a", Handler{}),
+			depinject.ProvideOnePerModule("a", Handler{}),
+		),
+		&handlers,
+	)
+	require.True(t, depinject.IsDuplicateOnePerModuleError(err))
+	require.ErrorContains(t, err, `module "a"`)
+	require.ErrorContains(t, err, "one_per_module_test.go")
+}
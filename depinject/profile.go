@@ -0,0 +1,146 @@
+package depinject
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// callProfile records the durations of the provider calls of a container
+// build, by stack of providers.
+type callProfile struct {
+	frames []profileFrame
+	// self is the duration spent in the last provider of each stack, excluding
+	// the providers it required
+	self map[string]time.Duration
+}
+
+type profileFrame struct {
+	stack    string
+	start    time.Time
+	children time.Duration
+}
+
+// Profiler records the durations of the provider calls of the container build
+// and passes them to profiler in the folded stack format of flame graph tools,
+// such as flamegraph.pl, inferno or speedscope. Each line is a stack of
+// providers, separated by ';', from a provider needed by the build outputs to
+// the providers it required, followed by the microseconds spent in the last
+// provider itself, excluding the providers it required. Providers called to
+// resolve inputs are thus shown on top of the providers which needed them.
+func Profiler(profiler func(foldedStacks string)) DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.enableProfile()
+		c.profilers = append(c.profilers, profiler)
+		return nil
+	})
+}
+
+// FileProfiler writes the folded stacks recorded as described in Profiler to
+// the specified file, e.g. to render a flame graph with
+//
+//	flamegraph.pl --countname=us depinject.folded > depinject.svg
+//
+// The filename is a path template which may contain the {timestamp} and {app}
+// placeholders, as for FileVisualizer. Unlike graph files, it is not
+// overridden by DebugFileEnvVar.
+func FileProfiler(filename string) DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.enableProfile()
+		c.profilers = append(c.profilers, func(foldedStacks string) {
+			path := c.expandDebugFileTemplate(filename, time.Now())
+			if err := c.saveProfileToFile(path, foldedStacks); err != nil {
+				c.logf("Error saving profile file %s: %+v", path, err)
+				return
+			}
+			c.rotateDebugFiles(filename)
+		})
+		return nil
+	})
+}
+
+func (c *debugConfig) enableProfile() {
+	if c.profile == nil {
+		c.profile = &callProfile{self: make(map[string]time.Duration)}
+	}
+}
+
+// profileCall starts recording a call of the provider at loc, and returns the
+// function ending it.
+func (c *debugConfig) profileCall(loc Location, key *moduleKey) func() {
+	p := c.profile
+	if p == nil {
+		return func() {}
+	}
+
+	frame := loc.Name()
+	if key != nil {
+		frame = fmt.Sprintf("%s (%s)", frame, key.name)
+	}
+	// ';' separates the frames of folded stacks
+	frame = strings.ReplaceAll(frame, ";", ",")
+
+	stack := frame
+	if n := len(p.frames); n > 0 {
+		stack = p.frames[n-1].stack + ";" + frame
+	}
+	p.frames = append(p.frames, profileFrame{stack: stack, start: time.Now()})
+
+	return func() {
+		n := len(p.frames)
+		f := p.frames[n-1]
+		p.frames = p.frames[:n-1]
+
+		elapsed := time.Since(f.start)
+		p.self[f.stack] += elapsed - f.children
+		if n > 1 {
+			p.frames[n-2].children += elapsed
+		}
+	}
+}
+
+// foldedStacks returns the recorded stacks in folded format, sorted.
+func (p *callProfile) foldedStacks() string {
+	stacks := make([]string, 0, len(p.self))
+	for stack := range p.self {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+
+	var b strings.Builder
+	for _, stack := range stacks {
+		fmt.Fprintf(&b, "%s %d\n", stack, p.self[stack].Microseconds())
+	}
+	return b.String()
+}
+
+func (c *debugConfig) generateProfile() {
+	if c.profile == nil {
+		return
+	}
+
+	foldedStacks := c.profile.foldedStacks()
+	for _, profiler := range c.profilers {
+		profiler(foldedStacks)
+	}
+}
+
+func (c *debugConfig) saveProfileToFile(filename, foldedStacks string) error {
+	if dir := filepath.Dir(filename); dir != "." {
+		if err := os.MkdirAll(dir, defaultDebugDirPerms); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(filename, []byte(foldedStacks), defaultFilePerms); err != nil {
+		return err
+	}
+
+	if path, err := filepath.Abs(filename); err == nil {
+		c.logf("Saved profile of container build to %s", path)
+	}
+	return nil
+}
@@ -2,8 +2,8 @@ package depinject
 
 import (
 	"reflect"
+	"regexp"
 	"strings"
-	"unicode"
 
 	"github.com/pkg/errors"
 	"golang.org/x/exp/slices"
@@ -27,6 +27,17 @@ type providerDescriptor struct {
 	// Location defines the source code location to be used for this provider
 	// in error messages.
 	Location Location
+
+	// Doc is the documentation of the provider shown in debug graphs. When it
+	// is empty, the doc comment of the function at Location is used.
+	Doc string
+
+	// explicit is set for the providers of a ProviderDescriptor, whose Fn may
+	// capture values, so that Reload never reuses their outputs.
+	explicit bool
+
+	// shared holds the outputs of a singleton, see Singleton.
+	shared *sharedOutputs
 }
 
 type providerInput struct {
@@ -52,45 +63,104 @@ func extractInvokerDescriptor(provider interface{}) (providerDescriptor, error)
 		return providerDescriptor{}, err
 	}
 
-	// mark all inputs as optional
+	markInputsOptional(rctr)
+	return postProcessProvider(rctr)
+}
+
+// markInputsOptional marks all inputs of an invoker as optional.
+func markInputsOptional(rctr providerDescriptor) {
 	for i, input := range rctr.Inputs {
 		input.Optional = true
 		rctr.Inputs[i] = input
 	}
-
-	return postProcessProvider(rctr)
 }
 
+// ErrInvalidProvider is wrapped by the errors of the functions which can't be
+// used as providers or invokers.
+var ErrInvalidProvider = errors.New("invalid provider")
+
+// anonymousFuncName matches the parts of the names of closures, e.g. the last
+// part of TestFoo.func1 or glob..func1, and both of the last parts of nested
+// closures, e.g. TestFoo.func1.2.
+var anonymousFuncName = regexp.MustCompile(`^(func)?[0-9]+$`)
+
 func doExtractProviderDescriptor(ctr interface{}) (providerDescriptor, error) {
 	val := reflect.ValueOf(ctr)
+	if !val.IsValid() {
+		return providerDescriptor{}, errors.Wrap(ErrInvalidProvider, "provider is nil")
+	}
 	typ := val.Type()
 	if typ.Kind() != reflect.Func {
 		return providerDescriptor{}, errors.Errorf("expected a Func type, got %v", typ)
 	}
+	if val.IsNil() {
+		return providerDescriptor{}, errors.Wrapf(ErrInvalidProvider, "provider function of type %v is nil", typ)
+	}
 
 	loc := LocationFromPC(val.Pointer()).(*location)
-	nameParts := strings.Split(loc.name, ".")
-	if len(nameParts) == 0 {
-		return providerDescriptor{}, errors.Errorf("missing function name %s", loc)
+	if err := checkProviderName(loc); err != nil {
+		return providerDescriptor{}, err
 	}
+	return funcProviderDescriptor(val, loc)
+}
 
+// checkProviderName checks that the function at loc is an exported function,
+// or the method expression of an exported type, e.g. (*Keeper).ProvideFoo,
+// whose receiver is then its first input, usually given with Supply.
+func checkProviderName(loc *location) error {
+	nameParts := strings.Split(loc.name, ".")
 	lastNamePart := nameParts[len(nameParts)-1]
-
-	if unicode.IsLower([]rune(lastNamePart)[0]) {
-		return providerDescriptor{}, errors.Errorf("function must be exported: %s", loc)
+	if lastNamePart == "" {
+		return errors.Wrapf(ErrInvalidProvider, "missing function name %s", loc)
 	}
 
+	if strings.HasSuffix(lastNamePart, "-fm") {
+		return errors.Wrapf(ErrInvalidProvider,
+			"function can't be used as a provider (it might be a bound instance method): %s; "+
+				"provide the method expression, e.g. (*Keeper).Method, and supply the receiver with Supply", loc)
+	}
 	if strings.Contains(lastNamePart, "-") {
-		return providerDescriptor{}, errors.Errorf("function can't be used as a provider (it might be a bound instance method): %s", loc)
+		return errors.Wrapf(ErrInvalidProvider, "function can't be used as a provider: %s", loc)
+	}
+
+	for _, part := range nameParts[1:] {
+		if anonymousFuncName.MatchString(part) {
+			return errors.Wrapf(ErrInvalidProvider, "anonymous function can't be used as a provider, declare an exported function instead: %s", loc)
+		}
+	}
+
+	if !isExported(lastNamePart) {
+		return errors.Wrapf(ErrInvalidProvider, "function must be exported: %s", loc)
+	}
+
+	if len(nameParts) > 1 {
+		// the receiver of a method expression, e.g. (*Keeper) or Keeper[...]
+		recv := strings.TrimSuffix(strings.Trim(nameParts[len(nameParts)-2], "(*)"), "[...]")
+		if recv == "" || !isExported(recv) {
+			return errors.Wrapf(ErrInvalidProvider, "receiver type of method must be exported: %s", loc)
+		}
 	}
 
 	pkgParts := strings.Split(loc.pkg, "/")
 	if slices.Contains(pkgParts, "internal") {
-		return providerDescriptor{}, errors.Errorf("function must not be in an internal package: %s", loc)
+		return errors.Wrapf(ErrInvalidProvider, "function must not be in an internal package: %s", loc)
 	}
 
-	if typ.IsVariadic() {
-		return providerDescriptor{}, errors.Errorf("variadic function can't be used as a provider: %s", loc)
+	return nil
+}
+
+// funcProviderDescriptor returns the descriptor of the provider function val.
+// Its error outputs, in any position, are removed from the outputs, and the
+// first non-nil one is returned by Fn. Fn returns the panics of the function
+// as errors. The variadic parameter of a function, e.g. func(...Handler)
+// Router, must be of a ManyPerContainerType, and is filled with all the values
+// of the type.
+func funcProviderDescriptor(val reflect.Value, loc *location) (providerDescriptor, error) {
+	typ := val.Type()
+	if typ.IsVariadic() && !isManyPerContainerSliceType(typ.In(typ.NumIn()-1)) {
+		return providerDescriptor{}, errors.Wrapf(ErrInvalidProvider,
+			"variadic function can't be used as a provider unless its variadic parameter is of a ManyPerContainerType, got %v: %s",
+			typ.In(typ.NumIn()-1).Elem(), loc)
 	}
 
 	numIn := typ.NumIn()
@@ -101,16 +171,13 @@ func doExtractProviderDescriptor(ctr interface{}) (providerDescriptor, error) {
 		}
 	}
 
-	errIdx := -1
+	var errIdxs []int
 	numOut := typ.NumOut()
 	var out []providerOutput
 	for i := 0; i < numOut; i++ {
 		t := typ.Out(i)
 		if t == errType {
-			if i != numOut-1 {
-				return providerDescriptor{}, errors.Errorf("output error parameter is not last parameter in function %s", loc)
-			}
-			errIdx = i
+			errIdxs = append(errIdxs, i)
 		} else {
 			out = append(out, providerOutput{Type: t})
 		}
@@ -119,16 +186,34 @@ func doExtractProviderDescriptor(ctr interface{}) (providerDescriptor, error) {
 	return providerDescriptor{
 		Inputs:  in,
 		Outputs: out,
-		Fn: func(values []reflect.Value) ([]reflect.Value, error) {
-			res := val.Call(values)
-			if errIdx >= 0 {
-				err := res[errIdx]
-				if !err.IsZero() {
+		Fn: func(values []reflect.Value) (_ []reflect.Value, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = errors.Errorf("provider panicked: %v", r)
+				}
+			}()
+
+			var res []reflect.Value
+			if typ.IsVariadic() {
+				res = val.CallSlice(values)
+			} else {
+				res = val.Call(values)
+			}
+			if len(errIdxs) == 0 {
+				return res, nil
+			}
+			for _, i := range errIdxs {
+				if err := res[i]; !err.IsNil() {
 					return nil, err.Interface().(error)
 				}
-				return res[0:errIdx], nil
 			}
-			return res, nil
+			vals := make([]reflect.Value, 0, len(out))
+			for i, v := range res {
+				if typ.Out(i) != errType {
+					vals = append(vals, v)
+				}
+			}
+			return vals, nil
 		},
 		Location: loc,
 	}, nil
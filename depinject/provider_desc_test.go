@@ -1,6 +1,7 @@
 package depinject
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -45,12 +46,37 @@ func StructInAndOut(_ float32, _ StructIn, _ byte) (int16, StructOut, int32, err
 	return int16(0), StructOut{}, int32(0), nil
 }
 
-func BadErrorPosition() (error, int) { return nil, 0 }
+func ErrorFirst() (error, int) { return nil, 0 }
+
+func MultipleErrors(int) (error, string, error) { return nil, "", errors.New("second") }
+
+type Keeper struct{ X int }
+
+func (k *Keeper) ProvideX() int { return k.X }
+
+type unexportedKeeper struct{}
+
+func (unexportedKeeper) Provide() int { return 0 }
 
 func BadOptionalFn(_ BadOptional) int { return 0 }
 
 func Variadic(...float64) int { return 0 }
 
+type VariadicHandler struct{ Name string }
+
+func (VariadicHandler) IsManyPerContainerType() {}
+
+func VariadicMany(prefix string, handlers ...VariadicHandler) string {
+	for _, h := range handlers {
+		prefix += h.Name
+	}
+	return prefix
+}
+
+// DocumentedProvider provides an int
+// for the debug graph tests.
+func DocumentedProvider() int { return 0 }
+
 func TestExtractProviderDescriptor(t *testing.T) {
 	var (
 		intType     = reflect.TypeOf(0)
@@ -127,11 +153,67 @@ func TestExtractProviderDescriptor(t *testing.T) {
 			"",
 		},
 		{
-			"error bad position",
-			BadErrorPosition,
+			"error first",
+			ErrorFirst,
+			[]providerInput{},
+			[]providerOutput{{Type: intType}},
+			"",
+		},
+		{
+			"multiple errors",
+			MultipleErrors,
+			[]providerInput{{Type: intType}},
+			[]providerOutput{{Type: stringType}},
+			"",
+		},
+		{
+			"method expression",
+			(*Keeper).ProvideX,
+			[]providerInput{{Type: reflect.TypeOf(&Keeper{})}},
+			[]providerOutput{{Type: intType}},
+			"",
+		},
+		{
+			"unexported receiver",
+			unexportedKeeper.Provide,
+			nil,
+			nil,
+			"receiver type of method must be exported",
+		},
+		{
+			"bound method",
+			(&Keeper{}).ProvideX,
 			nil,
 			nil,
-			"error parameter is not last parameter",
+			"supply the receiver with Supply",
+		},
+		{
+			"anonymous function",
+			func() int { return 0 },
+			nil,
+			nil,
+			"anonymous function can't be used as a provider",
+		},
+		{
+			"nested anonymous function",
+			func() func() int { return func() int { return 0 } }(),
+			nil,
+			nil,
+			"anonymous function can't be used as a provider",
+		},
+		{
+			"nil",
+			nil,
+			nil,
+			nil,
+			"provider is nil",
+		},
+		{
+			"nil function",
+			(func() int)(nil),
+			nil,
+			nil,
+			"provider function of type func() int is nil",
 		},
 		{
 			"bad optional",
@@ -147,6 +229,13 @@ func TestExtractProviderDescriptor(t *testing.T) {
 			nil,
 			"variadic function can't be used",
 		},
+		{
+			"variadic many-per-container",
+			VariadicMany,
+			[]providerInput{{Type: stringType}, {Type: reflect.TypeOf([]VariadicHandler{})}},
+			[]providerOutput{{Type: stringType}},
+			"",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -166,3 +255,264 @@ func TestExtractProviderDescriptor(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractExplicitProviderDescriptor(t *testing.T) {
+	var (
+		intType    = reflect.TypeOf(0)
+		stringType = reflect.TypeOf("")
+		bytesType  = reflect.TypeOf([]byte{})
+		errorType  = reflect.TypeOf((*error)(nil)).Elem()
+	)
+
+	loc := LocationFromCaller(0)
+	desc, err := extractExplicitProviderDescriptor(ProviderDescriptor{
+		Name:    "StructInAndOut",
+		Inputs:  []ProviderInput{{Type: reflect.TypeOf(StructIn{})}, {Type: intType, Optional: true}},
+		Outputs: []reflect.Type{reflect.TypeOf(StructOut{}), TypeOf[error]()},
+		Fn: func(inputs []interface{}) ([]interface{}, error) {
+			in := inputs[0].(StructIn)
+			return []interface{}{StructOut{X: "x", Y: []byte{byte(in.X)}}, nil}, nil
+		},
+	}, loc)
+	assert.ErrorContains(t, err, "must return errors from Fn")
+
+	desc, err = extractExplicitProviderDescriptor(ProviderDescriptor{
+		Name:    "StructInAndOut",
+		Inputs:  []ProviderInput{{Type: reflect.TypeOf(StructIn{})}, {Type: intType, Optional: true}},
+		Outputs: []reflect.Type{reflect.TypeOf(StructOut{}), errorType},
+	}, loc)
+	assert.ErrorContains(t, err, "must have a Fn")
+
+	desc, err = extractExplicitProviderDescriptor(ProviderDescriptor{
+		Name:    "StructInAndOut",
+		Inputs:  []ProviderInput{{Type: reflect.TypeOf(StructIn{})}, {Type: intType, Optional: true}},
+		Outputs: []reflect.Type{reflect.TypeOf(StructOut{})},
+		Fn: func(inputs []interface{}) ([]interface{}, error) {
+			in := inputs[0].(StructIn)
+			return []interface{}{StructOut{X: "x", Y: []byte{byte(in.X), byte(inputs[1].(int))}}}, nil
+		},
+	}, loc)
+	assert.NilError(t, err)
+	assert.Assert(t, reflect.DeepEqual([]providerInput{{Type: intType}, {Type: reflect.TypeOf(0.0), Optional: true}, {Type: intType, Optional: true}}, desc.Inputs))
+	assert.Assert(t, reflect.DeepEqual([]providerOutput{{Type: stringType}, {Type: bytesType}}, desc.Outputs))
+	assert.Equal(t, "StructInAndOut", desc.Location.(*location).name)
+
+	out, err := desc.Fn([]reflect.Value{reflect.ValueOf(1), reflect.ValueOf(2.0), reflect.ValueOf(3)})
+	assert.NilError(t, err)
+	assert.Equal(t, "x", out[0].Interface())
+	assert.DeepEqual(t, []byte{1, 3}, out[1].Interface())
+
+	invoker, err := extractExplicitInvokerDescriptor(ProviderDescriptor{
+		Name:   "Invoker",
+		Inputs: []ProviderInput{{Type: intType}},
+		Fn:     func([]interface{}) ([]interface{}, error) { return nil, nil },
+	}, loc)
+	assert.NilError(t, err)
+	assert.Assert(t, reflect.DeepEqual([]providerInput{{Type: intType, Optional: true}}, invoker.Inputs))
+
+	_, err = extractExplicitInvokerDescriptor(ProviderDescriptor{
+		Name:    "Invoker",
+		Outputs: []reflect.Type{intType},
+		Fn:      func([]interface{}) ([]interface{}, error) { return nil, nil },
+	}, loc)
+	assert.ErrorIs(t, err, ErrInvalidInvoker)
+}
+
+func TestExplicitProviderDescriptorOutputs(t *testing.T) {
+	type Duck interface{ Quack() }
+
+	outputs := []interface{}{}
+	desc, err := ProviderDescriptor{
+		Name:    "Ducks",
+		Outputs: []reflect.Type{TypeOf[Duck](), reflect.TypeOf(0)},
+		Fn:      func([]interface{}) ([]interface{}, error) { return outputs, nil },
+	}.toProviderDescriptor(LocationFromCaller(0))
+	assert.NilError(t, err)
+
+	outputs = []interface{}{nil, 1}
+	out, err := desc.Fn(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, TypeOf[Duck](), out[0].Type())
+	assert.Assert(t, out[0].IsNil())
+
+	outputs = []interface{}{nil}
+	_, err = desc.Fn(nil)
+	assert.ErrorContains(t, err, "returned 1 values, expected 2")
+
+	outputs = []interface{}{nil, "1"}
+	_, err = desc.Fn(nil)
+	assert.ErrorContains(t, err, "returned a string for output 1")
+}
+
+func TestProviderDocs(t *testing.T) {
+	cfg, err := newDebugConfig()
+	assert.NilError(t, err)
+	tooltip := func(provider interface{}) string {
+		desc, ok := provider.(providerDescriptor)
+		if !ok {
+			desc, err = extractProviderDescriptor(provider)
+			assert.NilError(t, err)
+		}
+		node, _ := graphviz.NewGraph().FindOrCreateNode("provider")
+		cfg.setProviderTooltip(node, &desc)
+		return node.String()
+	}
+
+	// doc comments are only looked up with ProviderDocs
+	assert.Equal(t, "", tooltip(DocumentedProvider))
+	assert.NilError(t, ProviderDocs().applyConfig(cfg))
+	assert.Equal(t, `[tooltip="DocumentedProvider provides an int\nfor the debug graph tests."]`, tooltip(DocumentedProvider))
+	assert.Equal(t, "", tooltip(SimpleArgs))
+
+	desc, err := ProviderDescriptor{
+		Name:    "Explicit",
+		Doc:     "Explicit provides an int.",
+		Outputs: []reflect.Type{reflect.TypeOf(0)},
+		Fn:      func([]interface{}) ([]interface{}, error) { return []interface{}{0}, nil },
+	}.toProviderDescriptor(LocationFromCaller(0))
+	assert.NilError(t, err)
+	assert.Equal(t, `[tooltip="Explicit provides an int."]`, tooltip(desc))
+}
+
+func PanickingProvider() int { panic("boom") }
+
+func TestProviderDescriptorFn(t *testing.T) {
+	desc, err := extractProviderDescriptor(MultipleErrors)
+	assert.NilError(t, err)
+	_, err = desc.Fn([]reflect.Value{reflect.ValueOf(1)})
+	assert.Error(t, err, "second")
+
+	// the panics of providers and calls with the wrong inputs are errors
+	desc, err = extractProviderDescriptor(PanickingProvider)
+	assert.NilError(t, err)
+	_, err = desc.Fn(nil)
+	assert.ErrorContains(t, err, "provider panicked: boom")
+	_, err = desc.Fn([]reflect.Value{reflect.ValueOf(1)})
+	assert.ErrorContains(t, err, "provider panicked")
+
+	// the receiver of a method expression is supplied
+	var x int
+	assert.NilError(t, Inject(Configs(Supply(&Keeper{X: 3}), Provide((*Keeper).ProvideX)), &x))
+	assert.Equal(t, 3, x)
+
+	_, err = extractProviderDescriptor((&Keeper{}).ProvideX)
+	assert.ErrorIs(t, err, ErrInvalidProvider)
+}
+
+func FuzzCheckProviderName(f *testing.F) {
+	f.Add("cosmossdk.io/depinject", "SimpleArgs")
+	f.Add("cosmossdk.io/depinject", "(*Keeper[...]).ProvideX")
+	f.Add("cosmossdk.io/depinject", "glob..func1")
+	f.Add("cosmossdk.io/depinject/internal", "Foo-fm")
+	f.Add("", "")
+
+	f.Fuzz(func(t *testing.T, pkg, name string) {
+		_ = checkProviderName(&location{pkg: pkg, name: name})
+	})
+}
+
+type fuzzUnexported struct{}
+
+// fuzzProviderTypes are the types of the inputs and outputs of the fuzzed
+// providers, covering the supported and unsupported shapes.
+var fuzzProviderTypes = []reflect.Type{
+	reflect.TypeOf(0),
+	reflect.TypeOf(""),
+	errType,
+	reflect.TypeOf(StructIn{}),
+	reflect.TypeOf(StructOut{}),
+	reflect.TypeOf(BadOptional{}),
+	reflect.TypeOf([]float64{}),
+	reflect.TypeOf(fuzzUnexported{}),
+	reflect.TypeOf(map[string]graphviz.Attributes{}),
+	reflect.TypeOf(func(int) error { return nil }),
+	reflect.TypeOf(&Keeper{}),
+	reflect.TypeOf(ModuleKey{}),
+}
+
+func TestVariadicProviderCall(t *testing.T) {
+	desc, err := extractProviderDescriptor(VariadicMany)
+	assert.NilError(t, err)
+
+	res, err := desc.Fn([]reflect.Value{
+		reflect.ValueOf("handlers:"),
+		reflect.ValueOf([]VariadicHandler{{Name: "a"}, {Name: "b"}}),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, res[0].Interface(), "handlers:ab")
+
+	res, err = desc.Fn([]reflect.Value{reflect.ValueOf("none"), reflect.ValueOf([]VariadicHandler(nil))})
+	assert.NilError(t, err)
+	assert.Equal(t, res[0].Interface(), "none")
+}
+
+// FuzzExtractProviderDescriptor extracts the descriptors of providers of
+// arbitrary signatures, and calls those which are valid, checking that
+// malformed providers are rejected with errors rather than panics.
+func FuzzExtractProviderDescriptor(f *testing.F) {
+	f.Add([]byte{0x12, 0x30, 0x00})
+	f.Add([]byte{0x03, 0x04, 0x22, 0x01})
+	f.Add([]byte{0x80, 0x26, 0x02, 0xff})
+
+	f.Fuzz(func(t *testing.T, sig []byte) {
+		if len(sig) < 2 {
+			return
+		}
+		// the first byte holds the variadic flag and the behavior of the
+		// function, the second one the numbers of inputs and outputs
+		variadic, behavior := sig[0]&0x80 != 0, sig[0]&0x3
+		numIn, numOut := int(sig[1]>>4)%4, int(sig[1]&0xf)%4
+		sig = sig[2:]
+		typeAt := func(i int) reflect.Type {
+			if i >= len(sig) {
+				return fuzzProviderTypes[0]
+			}
+			return fuzzProviderTypes[int(sig[i])%len(fuzzProviderTypes)]
+		}
+
+		in := make([]reflect.Type, numIn)
+		for i := range in {
+			in[i] = typeAt(i)
+		}
+		if variadic && numIn > 0 {
+			in[numIn-1] = reflect.SliceOf(in[numIn-1])
+		} else {
+			variadic = false
+		}
+		out := make([]reflect.Type, numOut)
+		for i := range out {
+			out[i] = typeAt(numIn + i)
+		}
+
+		typ := reflect.FuncOf(in, out, variadic)
+		fn := reflect.MakeFunc(typ, func([]reflect.Value) []reflect.Value {
+			if behavior == 1 {
+				panic("fuzz")
+			}
+			res := make([]reflect.Value, numOut)
+			for i, t := range out {
+				res[i] = reflect.Zero(t)
+				if t == errType && behavior == 2 {
+					res[i] = reflect.ValueOf(errors.New("fuzz")).Convert(errType)
+				}
+			}
+			return res
+		})
+
+		desc, err := funcProviderDescriptor(fn, &location{pkg: "cosmossdk.io/depinject", name: "Fuzz"})
+		if err != nil {
+			return
+		}
+		if desc, err = postProcessProvider(desc); err != nil {
+			return
+		}
+		inputs := make([]reflect.Value, len(desc.Inputs))
+		for i, input := range desc.Inputs {
+			inputs[i] = reflect.Zero(input.Type)
+		}
+		outputs, err := desc.Fn(inputs)
+		if err == nil && len(outputs) != len(desc.Outputs) {
+			t.Fatalf("provider returned %d outputs, expected %d", len(outputs), len(desc.Outputs))
+		}
+	})
+}
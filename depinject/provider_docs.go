@@ -0,0 +1,65 @@
+package depinject
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"cosmossdk.io/depinject/internal/graphviz"
+)
+
+// ProviderDocs attaches the doc comments of the provider functions to their
+// nodes in the debug graph, as tooltips shown when hovering over the nodes of
+// an SVG rendering. Doc comments are read from the provider source files, so
+// they are only found when the sources the binary was built from are present,
+// i.e. in debug and test builds. Provider descriptors use their Doc field.
+func ProviderDocs() DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.providerDocs = true
+		return nil
+	})
+}
+
+// setProviderTooltip sets the doc of provider as the tooltip of its graph node.
+func (c *debugConfig) setProviderTooltip(node *graphviz.Node, provider *providerDescriptor) {
+	doc := provider.Doc
+	if doc == "" && c.providerDocs {
+		doc = c.lookupFuncDoc(provider.Location)
+	}
+	if doc != "" {
+		node.SetTooltip(doc)
+	}
+}
+
+// lookupFuncDoc returns the doc comment of the function at loc, or an empty
+// string when its source file can't be parsed.
+func (c *debugConfig) lookupFuncDoc(loc Location) string {
+	l, ok := loc.(*location)
+	if !ok || l.file == "" || l.file == "unknown" {
+		return ""
+	}
+
+	if c.parsedFiles == nil {
+		c.parsedFiles = make(map[string]*ast.File)
+	}
+	file, ok := c.parsedFiles[l.file]
+	if !ok {
+		// failures are cached too, so that each file is parsed once
+		file, _ = parser.ParseFile(token.NewFileSet(), l.file, nil, parser.ParseComments)
+		c.parsedFiles[l.file] = file
+	}
+	if file == nil {
+		return ""
+	}
+
+	// the name of generic functions ends with their type parameters
+	name, _, _ := strings.Cut(l.name, "[")
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok && fn.Recv == nil && fn.Name.Name == name && fn.Doc != nil {
+			return strings.TrimSpace(fn.Doc.Text())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package depinject
+
+import (
+	"reflect"
+)
+
+// valueComparator reports whether two values provided for the same type are equal.
+type valueComparator func(a, b reflect.Value) bool
+
+// VerifyPure makes the container call every provider twice with the same
+// inputs and fail with an ErrImpureProvider error if the two calls return
+// different outputs. Outputs are compared with reflect.DeepEqual unless a
+// comparator is registered for their type with PureComparator. Outputs of
+// func or chan type, which cannot be deep-compared, are skipped when no
+// comparator is registered.
+//
+// It detects providers with hidden nondeterminism, such as map iteration or
+// wall clock reads, and is meant to be used in tests only: providers with side
+// effects run twice.
+func VerifyPure() DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.verifyPure = true
+		return nil
+	})
+}
+
+// PureComparator registers the function used by VerifyPure to compare the
+// outputs of type T of two calls of the same provider.
+func PureComparator[T any](equal func(a, b T) bool) DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		if c.pureComparators == nil {
+			c.pureComparators = make(map[reflect.Type]valueComparator)
+		}
+		c.pureComparators[reflect.TypeOf((*T)(nil)).Elem()] = func(a, b reflect.Value) bool {
+			return equal(a.Interface().(T), b.Interface().(T))
+		}
+		return nil
+	})
+}
+
+// verifyPureCall calls the provider a second time with the same inputs and
+// compares its outputs to the ones of the first call.
+func (c *container) verifyPureCall(provider *providerDescriptor, inVals, out []reflect.Value) error {
+	// invokers have no outputs and are called for their side effects
+	if len(out) == 0 {
+		return nil
+	}
+
+	c.logf("Verifying %s is pure", provider.Location)
+	again, err := provider.Fn(inVals)
+	if err != nil {
+		return newErrImpureProvider(provider.Location, nil, err)
+	}
+
+	for i := range out {
+		typ := out[i].Type()
+		if equal, ok := c.pureComparators[typ]; ok {
+			if !equal(out[i], again[i]) {
+				return newErrImpureProvider(provider.Location, typ, nil)
+			}
+			continue
+		}
+
+		switch typ.Kind() {
+		case reflect.Func, reflect.Chan:
+			c.logf("Skipping purity check of %v provided by %s, no comparator registered", typ, provider.Location)
+			continue
+		}
+
+		if !reflect.DeepEqual(out[i].Interface(), again[i].Interface()) {
+			return newErrImpureProvider(provider.Location, typ, nil)
+		}
+	}
+
+	return nil
+}
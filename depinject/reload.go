@@ -0,0 +1,310 @@
+package depinject
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// providerCall is a call of a provider or invoker, recorded by the containers
+// built by BuildContainer so that Reload reuses its outputs.
+type providerCall struct {
+	// name identifies the provider in a ReloadReport: its name, followed by
+	// its module in brackets when it is module-scoped.
+	name    string
+	inputs  []reflect.Value
+	outputs []reflect.Value
+	// reused is set when the outputs are the ones of the previous call.
+	reused bool
+}
+
+// teardownHook is a hook registered with Teardown.
+type teardownHook struct {
+	typ reflect.Type
+	fn  func(reflect.Value) error
+	loc Location
+}
+
+// Teardown registers a hook called by Reload on the values of type T, or
+// implementing T if T is an interface, which the reloaded config replaces,
+// e.g. to close the stores or stop the servers of the previous config:
+//
+//	depinject.Teardown(func(s *Server) error { return s.Close() })
+//
+// The hooks of the previous config are called, after the new config is built.
+func Teardown[T any](hook func(T) error) Config {
+	loc := LocationFromCaller(1)
+	typ := TypeOf[T]()
+	return containerConfig(func(ctr *container) error {
+		if hook == nil {
+			return errors.Errorf("teardown hook of %v is nil: %s", typ, loc)
+		}
+		ctr.teardowns = append(ctr.teardowns, teardownHook{
+			typ: typ,
+			fn: func(value reflect.Value) error {
+				v, _ := value.Interface().(T)
+				return hook(v)
+			},
+			loc: loc,
+		})
+		return nil
+	})
+}
+
+// ReloadReport is the result of Reload.
+type ReloadReport struct {
+	// Diff is the difference between the structures of the previous and the
+	// new configs.
+	Diff *ConfigDiff
+	// Called are the providers and invokers called by the reload: the new
+	// ones, and those whose inputs changed.
+	Called []string
+	// Reused are the providers and invokers whose inputs are unchanged, and
+	// whose previous outputs are reused.
+	Reused []string
+	// TornDown are the previous providers whose replaced values were passed to
+	// teardown hooks.
+	TornDown []string
+}
+
+// Reload rebuilds the container with config, e.g. a module config edited in
+// a development app server, without restarting it. The providers and invokers
+// whose inputs are the same as in the previous build are not called again, and
+// their previous outputs are reused: values of pointer types are the same only
+// if they are the same pointer, so that the providers depending on a provider
+// called again are also called again. The providers of a ProviderDescriptor,
+// such as ProvideOnePerModule, are always called again. The previous values
+// which the new build replaces are then passed to the teardown hooks of the
+// previous config, see Teardown.
+//
+// If config fails to build, the container is left unchanged. Teardown errors
+// are returned after the container is reloaded.
+func Reload(c *Container, config Config) (*ReloadReport, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	diff, err := DiffConfigs(c.config, config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctr, err := runInjection(InjectionOptions{
+		location:      LocationFromCaller(1),
+		debugOpt:      c.debugOpt,
+		config:        config,
+		recordCalls:   true,
+		previousCalls: c.ctr.calls,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReloadReport{Diff: diff}
+	for _, key := range sortedCallKeys(ctr.calls) {
+		if call := ctr.calls[key]; call.reused {
+			report.Reused = append(report.Reused, call.name)
+		} else {
+			report.Called = append(report.Called, call.name)
+		}
+	}
+
+	var teardownErrs []error
+	for _, key := range sortedCallKeys(c.ctr.calls) {
+		prev := c.ctr.calls[key]
+		next, ok := ctr.calls[key]
+		if ok && next.reused {
+			continue
+		}
+
+		tornDown := false
+		for _, out := range prev.outputs {
+			if ok && containsSameValue(next.outputs, out) {
+				continue
+			}
+			ran, errs := c.ctr.teardown(out)
+			tornDown = tornDown || ran
+			teardownErrs = append(teardownErrs, errs...)
+		}
+		if tornDown {
+			report.TornDown = append(report.TornDown, prev.name)
+		}
+	}
+
+	c.ctr, c.config = ctr, config
+	if len(teardownErrs) > 0 {
+		return report, errors.Wrapf(teardownErrs[0], "%d teardown hooks failed, first error", len(teardownErrs))
+	}
+	return report, nil
+}
+
+// teardown calls the teardown hooks of the value, and reports whether any was
+// called.
+func (c *container) teardown(value reflect.Value) (bool, []error) {
+	if !value.IsValid() {
+		return false, nil
+	}
+
+	var (
+		ran  bool
+		errs []error
+	)
+	for _, hook := range c.teardowns {
+		if value.Type() != hook.typ && (hook.typ.Kind() != reflect.Interface || !value.Type().Implements(hook.typ)) {
+			continue
+		}
+		ran = true
+		c.logf("Tearing down %v with the hook registered at %s", value.Type(), hook.loc)
+		if err := hook.fn(value); err != nil {
+			errs = append(errs, errors.Wrapf(err, "teardown hook of %v registered at %s", hook.typ, hook.loc))
+		}
+	}
+	return ran, errs
+}
+
+// reuseCall returns the outputs of the previous call of the provider if its
+// inputs are unchanged, and records the call.
+func (c *container) reuseCall(provider *providerDescriptor, key *moduleKey, inputs []reflect.Value) ([]reflect.Value, bool) {
+	if c.calls == nil || provider.explicit || provider == c.buildOutputs {
+		return nil, false
+	}
+
+	callKey := providerCallKey(provider, key)
+	prev, ok := c.previousCalls[callKey]
+	if !ok || len(prev.inputs) != len(inputs) {
+		return nil, false
+	}
+	for i, in := range inputs {
+		if !sameValue(prev.inputs[i], in) {
+			return nil, false
+		}
+	}
+
+	c.calls[callKey] = &providerCall{name: prev.name, inputs: inputs, outputs: prev.outputs, reused: true}
+	return prev.outputs, true
+}
+
+// recordCall records the call of the provider, for the next Reload.
+func (c *container) recordCall(provider *providerDescriptor, key *moduleKey, inputs, outputs []reflect.Value) {
+	if c.calls == nil || provider == c.buildOutputs {
+		return
+	}
+
+	c.calls[providerCallKey(provider, key)] = &providerCall{name: providerCallName(provider, key), inputs: inputs, outputs: outputs}
+}
+
+// recordSharedCall records the reuse of the outputs of a singleton, for the
+// next Reload not to tear them down.
+func (c *container) recordSharedCall(provider *providerDescriptor, key *moduleKey, outputs []reflect.Value) {
+	if c.calls == nil {
+		return
+	}
+
+	c.calls[providerCallKey(provider, key)] = &providerCall{name: providerCallName(provider, key), outputs: outputs, reused: true}
+}
+
+// providerCallName is the name of a provider in a ReloadReport.
+func providerCallName(provider *providerDescriptor, key *moduleKey) string {
+	name := provider.Location.Name()
+	if key != nil {
+		name = fmt.Sprintf("%s [%s]", name, key.name)
+	}
+	return name
+}
+
+// providerCallKey identifies the calls of a provider across builds: its
+// location, which tells apart the provider descriptors of a function, and its
+// module.
+func providerCallKey(provider *providerDescriptor, key *moduleKey) string {
+	if key == nil {
+		return fmt.Sprintf("%v;", provider.Location)
+	}
+	return fmt.Sprintf("%v;%s", provider.Location, key.name)
+}
+
+func sortedCallKeys(calls map[string]*providerCall) []string {
+	keys := make([]string, 0, len(calls))
+	for key := range calls {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsSameValue(values []reflect.Value, value reflect.Value) bool {
+	for _, v := range values {
+		if sameValue(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameValue reports whether the values are the same: module keys of the same
+// name, slices, arrays and maps of the same elements, and otherwise equal
+// comparable values, pointers being the same only if they are the same
+// pointer. Funcs and other values which can't be compared are not the same.
+func sameValue(a, b reflect.Value) (same bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Type() {
+	case moduleKeyType, ownModuleKeyType:
+		// module keys are created by each container
+		ka, kb := a.Convert(moduleKeyType).Interface().(ModuleKey), b.Convert(moduleKeyType).Interface().(ModuleKey)
+		if ka.moduleKey == nil || kb.moduleKey == nil {
+			return ka.moduleKey == kb.moduleKey
+		}
+		return ka.name == kb.name
+	}
+
+	switch a.Kind() {
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() {
+			return false
+		}
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !sameValue(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			if v := b.MapIndex(iter.Key()); !v.IsValid() || !sameValue(iter.Value(), v) {
+				return false
+			}
+		}
+		return true
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return sameValue(a.Elem(), b.Elem())
+	case reflect.Func:
+		return false
+	}
+
+	if !a.Type().Comparable() || !a.CanInterface() {
+		return false
+	}
+	// comparable structs may hold interfaces of values which aren't
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a.Interface() == b.Interface()
+}
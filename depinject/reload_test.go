@@ -0,0 +1,89 @@
+package depinject_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/depinject"
+)
+
+type ServerConfig struct{ Addr string }
+
+type Server struct {
+	Addr   string
+	Closed bool
+}
+
+func ProvideServer(config ServerConfig) *Server {
+	return &Server{Addr: config.Addr}
+}
+
+func TestReload(t *testing.T) {
+	countedKeeperDCalls = 0
+	config := func(addr string) depinject.Config {
+		return depinject.Configs(
+			depinject.Supply(KVStoreKey{name: "a"}, ServerConfig{Addr: addr}),
+			depinject.Provide(ProvideCountedKeeperD, ProvideServer),
+			depinject.Teardown(func(s *Server) error {
+				s.Closed = true
+				return nil
+			}),
+		)
+	}
+
+	ctr, err := depinject.BuildContainer(config(":1"))
+	require.NoError(t, err)
+	var prev *Server
+	require.NoError(t, ctr.Resolve(&prev))
+
+	report, err := depinject.Reload(ctr, config(":2"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"cosmossdk.io/depinject_test.ProvideServer"}, report.Called)
+	require.Equal(t, []string{"cosmossdk.io/depinject_test.ProvideCountedKeeperD"}, report.Reused)
+	require.Equal(t, []string{"cosmossdk.io/depinject_test.ProvideServer"}, report.TornDown)
+
+	// the keeper, whose inputs are unchanged, is not provided again
+	require.Equal(t, 1, countedKeeperDCalls)
+	require.True(t, prev.Closed)
+
+	var (
+		next *Server
+		d    KeeperD
+	)
+	require.NoError(t, ctr.Resolve(&next, &d))
+	require.Equal(t, ":2", next.Addr)
+	require.False(t, next.Closed)
+	require.Equal(t, KeeperD{key: KVStoreKey{name: "a"}}, d)
+
+	// a config failing to build leaves the container unchanged
+	_, err = depinject.Reload(ctr, depinject.Error(fmt.Errorf("config error")))
+	require.Error(t, err)
+	require.NoError(t, ctr.Resolve(&next))
+	require.Equal(t, ":2", next.Addr)
+	require.False(t, next.Closed)
+}
+
+func TestReloadTeardownError(t *testing.T) {
+	config := func(addr string) depinject.Config {
+		return depinject.Configs(
+			depinject.Supply(ServerConfig{Addr: addr}),
+			depinject.Provide(ProvideServer),
+			depinject.Teardown(func(s *Server) error {
+				return fmt.Errorf("can't close %s", s.Addr)
+			}),
+		)
+	}
+
+	ctr, err := depinject.BuildContainer(config(":1"))
+	require.NoError(t, err)
+
+	_, err = depinject.Reload(ctr, config(":2"))
+	require.ErrorContains(t, err, "can't close :1")
+
+	// the container is reloaded anyway
+	var s *Server
+	require.NoError(t, ctr.Resolve(&s))
+	require.Equal(t, ":2", s.Addr)
+}
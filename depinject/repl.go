@@ -0,0 +1,205 @@
+//go:build depinject_debug
+
+package depinject
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const replPrompt = "depinject> "
+
+const replHelp = `Commands:
+  types [filter]            list the provided types and their providers
+  bindings                  list the interface bindings
+  provenance <type> [mod]   print the providers the type is built from, recursively
+  resolve <type> [mod]      resolve the type, calling its providers, and print it
+  help                      print this help
+  quit                      exit
+Types are given by their name, ex. KeeperA, their package qualified name, ex.
+keeper.KeeperA, or their full name as printed by types. Module-scoped types are
+resolved in the module mod.
+`
+
+// packageQualifier matches the package qualifiers of a type name.
+var packageQualifier = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*\.`)
+
+// REPL registers the providers of config in a container and runs an
+// interactive prompt reading commands from in, to list the provided types,
+// print their provenance, and resolve them on demand. Unlike Inject, the
+// container isn't built: only the resolved types have their providers called.
+// It is only available in builds with the depinject_debug tag, ex. in a
+// debugging main:
+//
+//	err := depinject.REPL(appConfig, os.Stdin, os.Stdout)
+func REPL(config Config, in io.Reader, out io.Writer) error {
+	cfg, err := newDebugConfig()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDebugConfig, err)
+	}
+
+	ctr := newContainer(cfg)
+	if err := config.apply(ctr); err != nil {
+		return registrationError{err}
+	}
+	return ctr.repl(in, out)
+}
+
+func (c *container) repl(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	fmt.Fprint(out, replPrompt)
+	for scanner.Scan() {
+		args := strings.Fields(scanner.Text())
+		if len(args) > 0 {
+			if args[0] == "quit" || args[0] == "exit" {
+				return nil
+			}
+			if err := c.replCommand(out, args[0], args[1:]); err != nil {
+				fmt.Fprintf(out, "error: %v\n", err)
+			}
+		}
+		fmt.Fprint(out, replPrompt)
+	}
+	fmt.Fprintln(out)
+	return scanner.Err()
+}
+
+func (c *container) replCommand(out io.Writer, cmd string, args []string) error {
+	switch cmd {
+	case "help":
+		fmt.Fprint(out, replHelp)
+		return nil
+
+	case "types":
+		filter := strings.Join(args, " ")
+		for _, name := range c.providedTypeNames() {
+			r := c.resolvers[name]
+			if filter == "" || strings.Contains(strings.ToLower(name), strings.ToLower(filter)) {
+				fmt.Fprintf(out, "%s\t%s\n", name, r.describeLocation())
+			}
+		}
+		return nil
+
+	case "bindings":
+		keys := make([]string, 0, len(c.interfaceBindings))
+		for key := range c.interfaceBindings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			b := c.interfaceBindings[key]
+			scope := "global"
+			if b.moduleKey != nil {
+				scope = "module " + b.moduleKey.name
+			}
+			fmt.Fprintf(out, "%s -> %s (%s)\n", b.interfaceName, b.implTypeName, scope)
+		}
+		return nil
+
+	case "provenance", "resolve":
+		if len(args) == 0 || len(args) > 2 {
+			return errors.Errorf("usage: %s <type> [module]", cmd)
+		}
+		r, err := c.lookupResolver(args[0])
+		if err != nil {
+			return err
+		}
+		var key *moduleKey
+		if len(args) == 2 {
+			key = c.moduleKeyContext.createOrGetModuleKey(args[1])
+		}
+
+		if cmd == "provenance" {
+			c.writeProvenance(out, r, key, "", make(map[Location]bool))
+			return nil
+		}
+		value, err := r.resolve(c, key, LocationFromCaller(0))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%v = %+v\n", r.getType(), value)
+		return nil
+
+	default:
+		return errors.Errorf("unknown command %q, see help", cmd)
+	}
+}
+
+// providedTypeNames returns the sorted full names of the provided types.
+func (c *container) providedTypeNames() []string {
+	names := make([]string, 0, len(c.resolvers))
+	for name := range c.resolvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupResolver returns the resolver of the provided type with the given
+// full, package qualified or unqualified name.
+func (c *container) lookupResolver(name string) (resolver, error) {
+	if r, ok := c.resolverByTypeName(name); ok {
+		return r, nil
+	}
+
+	var matches []string
+	for _, full := range c.providedTypeNames() {
+		typ := c.resolvers[full].getType()
+		if typ.String() == name || packageQualifier.ReplaceAllString(typ.String(), "") == name {
+			matches = append(matches, full)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, errors.Errorf("no provided type %s, see types", name)
+	case 1:
+		return c.resolvers[matches[0]], nil
+	default:
+		return nil, errors.Errorf("type name %s is ambiguous, use one of:\n  %s", name, strings.Join(matches, "\n  "))
+	}
+}
+
+// writeProvenance writes the tree of the providers of the type of r.
+func (c *container) writeProvenance(out io.Writer, r resolver, key *moduleKey, indent string, seen map[Location]bool) {
+	node, ok := r.(*simpleResolver)
+	if !ok {
+		fmt.Fprintf(out, "%s%v <- %s\n", indent, r.getType(), r.describeLocation())
+		return
+	}
+
+	loc := node.node.provider.Location
+	status := ""
+	if node.resolved {
+		status = " (resolved)"
+	}
+	fmt.Fprintf(out, "%s%v <- %s%s\n", indent, r.getType(), loc, status)
+	if seen[loc] {
+		fmt.Fprintf(out, "%s  ... (cycle)\n", indent)
+		return
+	}
+	seen[loc] = true
+	defer delete(seen, loc)
+
+	if node.node.moduleKey != nil {
+		key = node.node.moduleKey
+	}
+	for _, in := range node.node.provider.Inputs {
+		inResolver, err := c.getResolver(in.Type, key)
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, "%s  %v: %v\n", indent, in.Type, err)
+		case inResolver == nil && in.Optional:
+			fmt.Fprintf(out, "%s  %v: not provided (optional)\n", indent, in.Type)
+		case inResolver == nil:
+			fmt.Fprintf(out, "%s  %v: not provided\n", indent, in.Type)
+		default:
+			c.writeProvenance(out, inResolver, key, indent+"  ", seen)
+		}
+	}
+}
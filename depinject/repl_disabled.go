@@ -0,0 +1,11 @@
+//go:build !depinject_debug
+
+package depinject
+
+import "io"
+
+// REPL is only available in builds with the depinject_debug tag, see the
+// depinject_debug implementation.
+func REPL(Config, io.Reader, io.Writer) error {
+	return ErrREPLDisabled
+}
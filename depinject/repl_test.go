@@ -0,0 +1,91 @@
+//go:build depinject_debug
+
+package depinject
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type (
+	ReplName    string
+	ReplGreeter struct{ greeting string }
+)
+
+func ProvideReplName() ReplName { return "baron" }
+
+func ProvideReplNamePtr() *ReplName {
+	name := ReplName("other")
+	return &name
+}
+
+func ProvideReplGreeter(name ReplName) ReplGreeter {
+	return ReplGreeter{greeting: "hello " + string(name)}
+}
+
+// addReplProvider registers the resolvers of the outputs of provider.
+func addReplProvider(t *testing.T, ctr *container, provider interface{}) {
+	t.Helper()
+
+	desc, err := extractProviderDescriptor(provider)
+	assert.NilError(t, err)
+	node := &simpleProvider{provider: &desc}
+	for i, out := range desc.Outputs {
+		ctr.addResolver(out.Type, &simpleResolver{node: node, idxInValues: i, typ: out.Type})
+	}
+}
+
+func TestREPL(t *testing.T) {
+	cfg, err := newDebugConfig()
+	assert.NilError(t, err)
+	ctr := newContainer(cfg)
+	addReplProvider(t, ctr, ProvideReplName)
+	addReplProvider(t, ctr, ProvideReplGreeter)
+
+	var out bytes.Buffer
+	assert.NilError(t, ctr.repl(strings.NewReader(strings.Join([]string{
+		"types greeter",
+		"provenance ReplGreeter",
+		"resolve depinject.ReplName",
+		"provenance ReplName",
+		"resolve unknown",
+		"resolve",
+		"frobnicate",
+		"quit",
+		"types",
+	}, "\n")), &out))
+
+	greeterType := reflect.TypeOf(ReplGreeter{})
+	nameLoc := LocationFromPC(reflect.ValueOf(ProvideReplName).Pointer()).String()
+	greeterLoc := LocationFromPC(reflect.ValueOf(ProvideReplGreeter).Pointer()).String()
+
+	assert.Equal(t, out.String(), strings.Join([]string{
+		replPrompt + fullyQualifiedTypeName(greeterType) + "\t" + greeterLoc,
+		replPrompt + "depinject.ReplGreeter <- " + greeterLoc,
+		"  depinject.ReplName <- " + nameLoc,
+		replPrompt + "depinject.ReplName = baron",
+		replPrompt + "depinject.ReplName <- " + nameLoc + " (resolved)",
+		replPrompt + "error: no provided type unknown, see types",
+		replPrompt + "error: usage: resolve <type> [module]",
+		replPrompt + `error: unknown command "frobnicate", see help`,
+		replPrompt,
+	}, "\n"))
+}
+
+func TestREPLLookupType(t *testing.T) {
+	cfg, err := newDebugConfig()
+	assert.NilError(t, err)
+	ctr := newContainer(cfg)
+	addReplProvider(t, ctr, ProvideReplName)
+	addReplProvider(t, ctr, ProvideReplNamePtr)
+
+	_, err = ctr.lookupResolver("ReplName")
+	assert.NilError(t, err)
+	r, err := ctr.lookupResolver("*ReplName")
+	assert.NilError(t, err)
+	assert.Equal(t, r.getType(), reflect.TypeOf((*ReplName)(nil)))
+}
@@ -0,0 +1,132 @@
+package depinject
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// NewScope returns the config of a child container of parent: the providers,
+// invokers, bindings and supplied values of parent, where the types provided
+// or supplied by overrides replace those of parent instead of failing as
+// duplicates. Bindings of overrides replace those of parent as usual. Scopes
+// nest, so that a scope may be the parent of another:
+//
+//	testScope := depinject.NewScope(appConfig, depinject.Supply(mockBankKeeper))
+//
+// Outputs of many-per-container and one-per-module types add to those of
+// parent, and are not replaced. Providers of parent whose outputs are all
+// replaced are not called. The manifest of a scope lists the providers of
+// both parent and overrides.
+func NewScope(parent Config, overrides ...Config) Config {
+	return containerConfig(func(ctr *container) error {
+		if err := parent.apply(ctr); err != nil {
+			return err
+		}
+
+		override := Configs(overrides...)
+		if ctr.recorder != nil {
+			return override.apply(ctr)
+		}
+
+		manifest, err := BuildManifest(override)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		var overridden []string
+		for _, p := range manifest.Providers {
+			overridden = append(overridden, p.Outputs...)
+		}
+		for _, s := range manifest.Supplies {
+			overridden = append(overridden, s.Type)
+		}
+		for _, typeName := range overridden {
+			if r, ok := ctr.resolvers[typeName]; ok && isOverridable(r) {
+				ctr.logf("Overriding %s of the parent scope, provided at %s", typeName, r.describeLocation())
+				delete(ctr.resolvers, typeName)
+			}
+		}
+
+		return override.apply(ctr)
+	})
+}
+
+// isOverridable reports whether the resolver provides a single value per
+// container, which the overrides of a scope replace.
+func isOverridable(r resolver) bool {
+	switch r.(type) {
+	case *simpleResolver, supplyResolver, *supplyResolver, moduleDepResolver, *moduleDepResolver:
+		return true
+	default:
+		return false
+	}
+}
+
+// Singleton registers providers like Provide, whose outputs are shared by all
+// the containers built from the config instead of being created by each of
+// them, e.g. a logger or a metrics registry shared by a container and its
+// scopes. A singleton is called by the first container needing its outputs,
+// and the next containers reuse them without resolving its inputs, so that
+// the overrides of a scope don't apply to it. A module-scoped singleton is
+// called once per module.
+func Singleton(providers ...interface{}) Config {
+	loc := LocationFromCaller(1)
+	shared := make([]*sharedOutputs, len(providers))
+	for i := range shared {
+		shared[i] = &sharedOutputs{outputs: map[string][]reflect.Value{}}
+	}
+	return containerConfig(func(ctr *container) error {
+		return provideShared(ctr, nil, providers, shared, loc)
+	})
+}
+
+// PerChild registers providers like Provide, which every container calls
+// again, e.g. the stores of which each scope needs its own instances. This is
+// the default for providers, which PerChild marks as not to be singletons.
+func PerChild(providers ...interface{}) Config {
+	loc := LocationFromCaller(1)
+	return containerConfig(func(ctr *container) error {
+		return provide(ctr, nil, providers, loc)
+	})
+}
+
+// sharedOutputs are the outputs of a singleton by module name, the empty name
+// being the global scope.
+type sharedOutputs struct {
+	mtx     sync.Mutex
+	outputs map[string][]reflect.Value
+}
+
+// get returns the outputs of the singleton for the module, if it was called.
+func (s *sharedOutputs) get(key *moduleKey) ([]reflect.Value, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	out, ok := s.outputs[sharedOutputsKey(key)]
+	return out, ok
+}
+
+// store stores the outputs of the singleton for the module, unless another
+// container stored its own first, and returns the stored outputs.
+func (s *sharedOutputs) store(key *moduleKey, out []reflect.Value) []reflect.Value {
+	if s == nil {
+		return out
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if prev, ok := s.outputs[sharedOutputsKey(key)]; ok {
+		return prev
+	}
+	s.outputs[sharedOutputsKey(key)] = out
+	return out
+}
+
+func sharedOutputsKey(key *moduleKey) string {
+	if key == nil {
+		return ""
+	}
+	return key.name
+}
@@ -17,6 +17,9 @@ import (
 //	optional	if set to true, the dependency is optional and will
 //				be set to its default value if not found, rather than causing
 //				an error
+//	ignore		if set to true, the field is not a dependency and is left
+//				to its zero value, so that the struct can carry unexported
+//				helper fields. depinject:"-" is equivalent
 type In struct{}
 
 func (In) isIn() {}
@@ -29,6 +32,9 @@ var isInType = reflect.TypeOf((*isIn)(nil)).Elem()
 // fields of the struct should be treated as dependency outputs.
 // This allows a struct to be used to specify outputs rather than
 // positional return values.
+//
+// Fields tagged with ignore:"true" or depinject:"-" are not outputs, as for
+// In structs.
 type Out struct{}
 
 func (Out) isOut() {}
@@ -37,11 +43,74 @@ type isOut interface{ isOut() }
 
 var isOutType = reflect.TypeOf((*isOut)(nil)).Elem()
 
+// digPkgPath is the package of dig.In and dig.Out, which fx.In and fx.Out are
+// aliases of. Structs embedding them are recognized by name, so that the fx
+// and dig parameter and result objects of their constructors are treated as
+// In and Out structs without depending on dig.
+const digPkgPath = "go.uber.org/dig"
+
+// isInStruct reports whether typ is an In struct, or dig.In itself.
+func isInStruct(typ reflect.Type) bool {
+	return typ.AssignableTo(isInType) || isDigType(typ, "In") || embedsDigType(typ, "In")
+}
+
+// isOutStruct reports whether typ is an Out struct, or dig.Out itself.
+func isOutStruct(typ reflect.Type) bool {
+	return typ.AssignableTo(isOutType) || isDigType(typ, "Out") || embedsDigType(typ, "Out")
+}
+
+func isDigType(typ reflect.Type, name string) bool {
+	return typ.PkgPath() == digPkgPath && typ.Name() == name
+}
+
+func embedsDigType(typ reflect.Type, name string) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if f := typ.Field(i); f.Anonymous && isDigType(f.Type, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDigTags rejects the tags of the fields of dig structs which have no
+// depinject equivalent: named values and value groups.
+func checkDigTags(typ reflect.Type, f reflect.StructField) error {
+	for _, tag := range []string{"name", "group"} {
+		if _, found := f.Tag.Lookup(tag); found {
+			return errors.Errorf("dig %s tag of field %s of %v is not supported", tag, f.Name, typ)
+		}
+	}
+	return nil
+}
+
+// isIgnoredField reports whether the field of an In or Out struct is tagged
+// with ignore:"true" or depinject:"-", and is not a dependency.
+func isIgnoredField(typ reflect.Type, f reflect.StructField) (bool, error) {
+	if tag, found := f.Tag.Lookup("depinject"); found {
+		if tag != "-" {
+			return false, errors.Errorf("bad depinject tag %q (should be \"-\") on field %s of %v", tag, f.Name, typ)
+		}
+		return true, nil
+	}
+
+	tag, found := f.Tag.Lookup("ignore")
+	if !found {
+		return false, nil
+	}
+	if tag != "true" {
+		return false, errors.Errorf("bad ignore tag %q (should be \"true\") on field %s of %v", tag, f.Name, typ)
+	}
+	return true, nil
+}
+
 func expandStructArgsProvider(provider providerDescriptor) (providerDescriptor, error) {
 	var structArgsInInput bool
 	var newIn []providerInput
 	for _, in := range provider.Inputs {
-		if in.Type.AssignableTo(isInType) {
+		if isInStruct(in.Type) {
 			structArgsInInput = true
 			inTypes, err := structArgsInTypes(in.Type)
 			if err != nil {
@@ -53,7 +122,10 @@ func expandStructArgsProvider(provider providerDescriptor) (providerDescriptor,
 		}
 	}
 
-	newOut, structArgsInOutput := expandStructArgsOutTypes(provider.Outputs)
+	newOut, structArgsInOutput, err := expandStructArgsOutTypes(provider.Outputs)
+	if err != nil {
+		return providerDescriptor{}, err
+	}
 
 	if structArgsInInput || structArgsInOutput {
 		return providerDescriptor{
@@ -61,6 +133,9 @@ func expandStructArgsProvider(provider providerDescriptor) (providerDescriptor,
 			Outputs:  newOut,
 			Fn:       expandStructArgsFn(provider),
 			Location: provider.Location,
+			Doc:      provider.Doc,
+			explicit: provider.explicit,
+			shared:   provider.shared,
 		}, nil
 	}
 
@@ -75,7 +150,7 @@ func expandStructArgsFn(provider providerDescriptor) func(inputs []reflect.Value
 		j := 0
 		inputs1 := make([]reflect.Value, len(inParams))
 		for i, in := range inParams {
-			if in.Type.AssignableTo(isInType) {
+			if isInStruct(in.Type) {
 				v, n, err := buildIn(in.Type, inputs[j:])
 				if err != nil {
 					return []reflect.Value{}, err
@@ -95,7 +170,7 @@ func expandStructArgsFn(provider providerDescriptor) func(inputs []reflect.Value
 
 		var outputs1 []reflect.Value
 		for i, out := range outParams {
-			if out.Type.AssignableTo(isOutType) {
+			if isOutStruct(out.Type) {
 				outputs1 = append(outputs1, extractFromOut(out.Type, outputs[i])...)
 			} else {
 				outputs1 = append(outputs1, outputs[i])
@@ -111,9 +186,24 @@ func structArgsInTypes(typ reflect.Type) ([]providerInput, error) {
 	var res []providerInput
 	for i := 0; i < n; i++ {
 		f := typ.Field(i)
-		if f.Type.AssignableTo(isInType) {
+		if isInStruct(f.Type) {
 			continue
 		}
+		ignored, err := isIgnoredField(typ, f)
+		if err != nil {
+			return nil, err
+		}
+		if ignored {
+			continue
+		}
+		if !f.IsExported() {
+			return nil, errors.Errorf("unexported field %s of %v must be tagged with ignore:\"true\"", f.Name, typ)
+		}
+		if embedsDigType(typ, "In") {
+			if err := checkDigTags(typ, f); err != nil {
+				return nil, err
+			}
+		}
 
 		var optional bool
 		optTag, found := f.Tag.Lookup("optional")
@@ -133,34 +223,53 @@ func structArgsInTypes(typ reflect.Type) ([]providerInput, error) {
 	return res, nil
 }
 
-func expandStructArgsOutTypes(outputs []providerOutput) ([]providerOutput, bool) {
+func expandStructArgsOutTypes(outputs []providerOutput) ([]providerOutput, bool, error) {
 	foundStructArgs := false
 	var newOut []providerOutput
 	for _, out := range outputs {
-		if out.Type.AssignableTo(isOutType) {
+		if isOutStruct(out.Type) {
 			foundStructArgs = true
-			newOut = append(newOut, structArgsOutTypes(out.Type)...)
+			outTypes, err := structArgsOutTypes(out.Type)
+			if err != nil {
+				return nil, false, err
+			}
+			newOut = append(newOut, outTypes...)
 		} else {
 			newOut = append(newOut, out)
 		}
 	}
-	return newOut, foundStructArgs
+	return newOut, foundStructArgs, nil
 }
 
-func structArgsOutTypes(typ reflect.Type) []providerOutput {
+func structArgsOutTypes(typ reflect.Type) ([]providerOutput, error) {
 	n := typ.NumField()
 	var res []providerOutput
 	for i := 0; i < n; i++ {
 		f := typ.Field(i)
-		if f.Type.AssignableTo(isOutType) {
+		if isOutStruct(f.Type) {
+			continue
+		}
+		ignored, err := isIgnoredField(typ, f)
+		if err != nil {
+			return nil, err
+		}
+		if ignored {
 			continue
 		}
+		if !f.IsExported() {
+			return nil, errors.Errorf("unexported field %s of %v must be tagged with ignore:\"true\"", f.Name, typ)
+		}
+		if embedsDigType(typ, "Out") {
+			if err := checkDigTags(typ, f); err != nil {
+				return nil, err
+			}
+		}
 
 		res = append(res, providerOutput{
 			Type: f.Type,
 		})
 	}
-	return res
+	return res, nil
 }
 
 func buildIn(typ reflect.Type, values []reflect.Value) (reflect.Value, int, error) {
@@ -169,7 +278,8 @@ func buildIn(typ reflect.Type, values []reflect.Value) (reflect.Value, int, erro
 	res := reflect.New(typ)
 	for i := 0; i < numFields; i++ {
 		f := typ.Field(i)
-		if f.Type.AssignableTo(isInType) {
+		// the tags were checked when expanding the provider
+		if ignored, _ := isIgnoredField(typ, f); ignored || isInStruct(f.Type) {
 			continue
 		}
 		if !res.Elem().Field(i).CanSet() {
@@ -190,7 +300,7 @@ func extractFromOut(typ reflect.Type, value reflect.Value) []reflect.Value {
 	var res []reflect.Value
 	for i := 0; i < numFields; i++ {
 		f := typ.Field(i)
-		if f.Type.AssignableTo(isOutType) {
+		if ignored, _ := isIgnoredField(typ, f); ignored || isOutStruct(f.Type) {
 			continue
 		}
 
@@ -0,0 +1,84 @@
+package depinject
+
+import (
+	"reflect"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type ignoredHelper struct{ calls int }
+
+type inWithIgnoredFields struct {
+	In
+
+	Name   string
+	Count  int                `optional:"true"`
+	helper *ignoredHelper     `ignore:"true"`
+	Cache  map[string]string  `depinject:"-"`
+	cache  map[string]float64 `ignore:"true"`
+}
+
+type outWithIgnoredFields struct {
+	Out
+
+	Name   string
+	helper *ignoredHelper `ignore:"true"`
+}
+
+func TestStructArgsIgnoredFields(t *testing.T) {
+	inType := reflect.TypeOf(inWithIgnoredFields{})
+	inputs, err := structArgsInTypes(inType)
+	assert.NilError(t, err)
+	assert.Equal(t, len(inputs), 2)
+	assert.Equal(t, inputs[0].Type, reflect.TypeOf(""))
+	assert.Equal(t, inputs[1].Type, reflect.TypeOf(0))
+	assert.Assert(t, inputs[1].Optional)
+
+	v, n, err := buildIn(inType, []reflect.Value{reflect.ValueOf("a"), reflect.ValueOf(3)})
+	assert.NilError(t, err)
+	assert.Equal(t, n, 2)
+	in := v.Interface().(inWithIgnoredFields)
+	assert.Equal(t, in.Name, "a")
+	assert.Equal(t, in.Count, 3)
+	assert.Assert(t, in.helper == nil && in.Cache == nil)
+
+	outType := reflect.TypeOf(outWithIgnoredFields{})
+	outputs, err := structArgsOutTypes(outType)
+	assert.NilError(t, err)
+	assert.Equal(t, len(outputs), 1)
+	assert.Equal(t, outputs[0].Type, reflect.TypeOf(""))
+	values := extractFromOut(outType, reflect.ValueOf(outWithIgnoredFields{Name: "b", helper: &ignoredHelper{}}))
+	assert.Equal(t, len(values), 1)
+	assert.Equal(t, values[0].Interface(), "b")
+}
+
+func TestStructArgsUnexportedFields(t *testing.T) {
+	type unexportedIn struct {
+		In
+		name string
+	}
+	_, err := structArgsInTypes(reflect.TypeOf(unexportedIn{}))
+	assert.ErrorContains(t, err, `unexported field name of depinject.unexportedIn must be tagged with ignore:"true"`)
+
+	type unexportedOut struct {
+		Out
+		name string
+	}
+	_, err = structArgsOutTypes(reflect.TypeOf(unexportedOut{}))
+	assert.ErrorContains(t, err, "unexported field name")
+
+	type badIgnoreTag struct {
+		In
+		Name string `ignore:"yes"`
+	}
+	_, err = structArgsInTypes(reflect.TypeOf(badIgnoreTag{}))
+	assert.ErrorContains(t, err, `bad ignore tag "yes"`)
+
+	type badDepinjectTag struct {
+		Out
+		Name string `depinject:"skip"`
+	}
+	_, err = structArgsOutTypes(reflect.TypeOf(badDepinjectTag{}))
+	assert.ErrorContains(t, err, `bad depinject tag "skip"`)
+}
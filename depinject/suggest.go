@@ -0,0 +1,171 @@
+package depinject
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxTypeSuggestions is the maximum number of types suggested for a type which
+// can't be resolved.
+const maxTypeSuggestions = 5
+
+// suggestTypes returns the fully qualified names of the registered types
+// similar to typ, the most similar first.
+func (c *container) suggestTypes(typ reflect.Type) []string {
+	candidates := make([]reflect.Type, 0, len(c.resolvers))
+	for _, r := range c.resolvers {
+		candidates = append(candidates, r.getType())
+	}
+	return suggestTypes(typ, candidates)
+}
+
+// suggestTypeNames is like suggestTypes for the fully qualified name of a
+// type, e.g. the implementation of an explicit interface binding.
+func (c *container) suggestTypeNames(typeName string) []string {
+	candidates := make([]reflect.Type, 0, len(c.resolvers))
+	for _, r := range c.resolvers {
+		candidates = append(candidates, r.getType())
+	}
+	name, pkg := typeNameAndPkgFromString(typeName)
+	return similarTypeNames(typeName, name, pkg, candidates)
+}
+
+// suggestTypes returns the fully qualified names of the candidates whose name
+// is within a few edits of the name of typ, or contains it, ignoring case, e.g.
+// a misspelled interface, its implementation or the same type name in another
+// package. Candidates in the package of typ come first for the same distance.
+func suggestTypes(typ reflect.Type, candidates []reflect.Type) []string {
+	name, pkg := typeNameAndPkg(typ)
+	return similarTypeNames(fullyQualifiedTypeName(typ), name, pkg, candidates)
+}
+
+// similarTypeNames implements suggestTypes for the type typName, whose name
+// and package path are name and pkg.
+func similarTypeNames(typName, name, pkg string, candidates []reflect.Type) []string {
+	if name == "" {
+		return nil
+	}
+	maxDistance := len(name) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	type suggestion struct {
+		name  string
+		score int
+	}
+	var (
+		suggestions []suggestion
+		seen        = map[string]bool{typName: true}
+	)
+	for _, candidate := range candidates {
+		candidateName := fullyQualifiedTypeName(candidate)
+		if seen[candidateName] {
+			continue
+		}
+		seen[candidateName] = true
+
+		n, p := typeNameAndPkg(candidate)
+		if n == "" {
+			continue
+		}
+		distance, ok := nameDistance(name, n, maxDistance)
+		if !ok {
+			continue
+		}
+		score := 2 * distance
+		if p != pkg {
+			score++
+		}
+		suggestions = append(suggestions, suggestion{name: candidateName, score: score})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].score != suggestions[j].score {
+			return suggestions[i].score < suggestions[j].score
+		}
+		return suggestions[i].name < suggestions[j].name
+	})
+	if len(suggestions) > maxTypeSuggestions {
+		suggestions = suggestions[:maxTypeSuggestions]
+	}
+	res := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		res[i] = s.name
+	}
+	return res
+}
+
+// minContainedNameLen is the minimum length of a type name for the names
+// containing it to be suggested.
+const minContainedNameLen = 4
+
+// nameDistance returns the edit distance of the names, ignoring case, and
+// whether they are similar: within maxDistance edits, or one containing the
+// other, in which case the distance is the length difference.
+func nameDistance(a, b string, maxDistance int) (int, bool) {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if distance := levenshtein(a, b); distance <= maxDistance {
+		return distance, true
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(a) >= minContainedNameLen && strings.Contains(b, a) {
+		return len(b) - len(a), true
+	}
+	return 0, false
+}
+
+// typeNameAndPkg returns the name and package path of the named type typ, or
+// of its element type for pointers, slices, maps and arrays.
+func typeNameAndPkg(typ reflect.Type) (string, string) {
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Array:
+		typ = typ.Elem()
+	}
+	return typ.Name(), typ.PkgPath()
+}
+
+// typeNameAndPkgFromString is like typeNameAndPkg for a name returned by
+// fullyQualifiedTypeName, e.g. cosmossdk.io/foo/*foo.Keeper.
+func typeNameAndPkgFromString(typeName string) (string, string) {
+	var pkg string
+	if i := strings.LastIndex(typeName, "/"); i >= 0 {
+		pkg, typeName = typeName[:i], typeName[i+1:]
+	}
+	return typeName[strings.LastIndex(typeName, ".")+1:], pkg
+}
+
+// levenshtein returns the edit distance of a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,46 @@
+package depinject
+
+import (
+	"reflect"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type (
+	bankKeeper     interface{ Send() }
+	bankKeepers    struct{}
+	stakingKeeper  struct{}
+	bankKeeperImpl struct{}
+)
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, levenshtein("", ""), 0)
+	assert.Equal(t, levenshtein("keeper", ""), 6)
+	assert.Equal(t, levenshtein("keeper", "keeper"), 0)
+	assert.Equal(t, levenshtein("keeper", "keepers"), 1)
+	assert.Equal(t, levenshtein("kitten", "sitting"), 3)
+}
+
+func TestSuggestTypes(t *testing.T) {
+	bankKeeperType := reflect.TypeOf((*bankKeeper)(nil)).Elem()
+	candidates := []reflect.Type{
+		bankKeeperType,
+		reflect.TypeOf(bankKeepers{}),
+		reflect.TypeOf(&bankKeepers{}),
+		reflect.TypeOf(stakingKeeper{}),
+		reflect.TypeOf(bankKeeperImpl{}),
+		reflect.TypeOf(""),
+	}
+
+	assert.DeepEqual(t, suggestTypes(bankKeeperType, candidates), []string{
+		fullyQualifiedTypeName(reflect.TypeOf(&bankKeepers{})),
+		fullyQualifiedTypeName(reflect.TypeOf(bankKeepers{})),
+		fullyQualifiedTypeName(reflect.TypeOf(bankKeeperImpl{})),
+	})
+	assert.Equal(t, len(suggestTypes(reflect.TypeOf(0), candidates)), 0)
+
+	err := newErrTypeNotResolvable(bankKeeperType, LocationFromCaller(0), suggestTypes(bankKeeperType, candidates))
+	assert.ErrorContains(t, err, "Did you mean:")
+	assert.Assert(t, IsTypeNotResolvableError(err))
+}
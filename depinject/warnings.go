@@ -0,0 +1,176 @@
+package depinject
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrWarningsAsErrors is returned, wrapped with the warnings, when the
+// container emitted warnings and WarningsAsErrors is set.
+var ErrWarningsAsErrors = errors.New("container warnings treated as errors")
+
+// Severity is the severity of a container Warning.
+type Severity int
+
+const (
+	// SeverityInfo is the severity of diagnostics which are usually expected,
+	// such as the providers of a module whose outputs the app doesn't need.
+	SeverityInfo Severity = iota
+	// SeverityWarning is the severity of diagnostics which are likely
+	// mistakes, or which make the config fragile.
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "INFO"
+	case SeverityWarning:
+		return "WARN"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// WarningKind identifies the cause of a container Warning.
+type WarningKind string
+
+const (
+	// WarningShadowedBinding is emitted when an interface binding replaces
+	// an earlier binding of the same interface, in the same scope.
+	WarningShadowedBinding WarningKind = "shadowed-binding"
+	// WarningImplicitBinding is emitted when an interface input is resolved
+	// by the only provided type implementing it, without a binding. Providing
+	// a second implementation would then break the container.
+	WarningImplicitBinding WarningKind = "implicit-binding"
+	// WarningUnusedProvider is emitted after a successful build for each
+	// provider which wasn't called.
+	WarningUnusedProvider WarningKind = "unused-provider"
+)
+
+// Warning is a non-fatal diagnostic of the container. Unlike resolution
+// errors, warnings don't make the build fail, unless WarningsAsErrors is set.
+type Warning struct {
+	Kind     WarningKind
+	Severity Severity
+	Message  string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s %s: %s", w.Severity, w.Kind, w.Message)
+}
+
+// WarningsAsErrors makes the container build fail with ErrWarningsAsErrors
+// if warnings of SeverityWarning were emitted. The build is completed first,
+// so that all the warnings are reported at once. Diagnostics of SeverityInfo
+// are only logged.
+func WarningsAsErrors() DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.warningsAsErrors = true
+		return nil
+	})
+}
+
+// WarningHandler passes the warnings of the container to handler, as they are
+// emitted, e.g. to route them to the app logger at the level of their
+// severity. Warnings are also written to the debug loggers.
+func WarningHandler(handler func(Warning)) DebugOption {
+	return debugOption(func(c *debugConfig) error {
+		c.warningHandlers = append(c.warningHandlers, handler)
+		return nil
+	})
+}
+
+// warn emits a warning to the debug loggers and the warning handlers.
+func (c *debugConfig) warn(kind WarningKind, severity Severity, format string, args ...interface{}) {
+	w := Warning{Kind: kind, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	c.warnings = append(c.warnings, w)
+	c.logf("%s", w)
+	for _, handler := range c.warningHandlers {
+		handler(w)
+	}
+}
+
+// checkWarnings returns an ErrWarningsAsErrors error listing the warnings of
+// SeverityWarning when WarningsAsErrors is set.
+func (c *debugConfig) checkWarnings() error {
+	if !c.warningsAsErrors {
+		return nil
+	}
+
+	var msgs []string
+	for _, w := range c.warnings {
+		if w.Severity >= SeverityWarning {
+			msgs = append(msgs, w.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.Wrapf(ErrWarningsAsErrors, "%d warning(s):\n  %s", len(msgs), strings.Join(msgs, "\n  "))
+}
+
+// warnShadowedBinding warns if the binding replaces an existing binding of
+// the same interface and scope.
+func (c *container) warnShadowedBinding(b interfaceBinding) {
+	existing, ok := c.interfaceBindings[bindingKeyFromTypeName(b.interfaceName, b.moduleKey)]
+	if !ok {
+		return
+	}
+
+	scope := "globally"
+	if b.moduleKey != nil {
+		scope = fmt.Sprintf("in module %s", b.moduleKey.name)
+	}
+	c.warn(WarningShadowedBinding, SeverityWarning, "binding of interface %s to %s %s shadows its binding to %s",
+		b.interfaceName, b.implTypeName, scope, existing.implTypeName)
+}
+
+// warnUnusedProviders warns about the providers which weren't called by the
+// build.
+func (c *container) warnUnusedProviders() {
+	called := make(map[Location]bool)
+	addSimple := func(providers ...*simpleProvider) {
+		for _, p := range providers {
+			called[p.provider.Location] = called[p.provider.Location] || p.called
+		}
+	}
+
+	for _, r := range c.resolvers {
+		switch r := r.(type) {
+		case *simpleResolver:
+			addSimple(r.node)
+		case moduleDepResolver:
+			called[r.node.provider.Location] = called[r.node.provider.Location] || len(r.node.calledForModule) > 0
+		case *moduleDepResolver:
+			called[r.node.provider.Location] = called[r.node.provider.Location] || len(r.node.calledForModule) > 0
+		case *onePerModuleResolver:
+			for _, p := range r.providers {
+				addSimple(p)
+			}
+		case *mapOfOnePerModuleResolver:
+			for _, p := range r.providers {
+				addSimple(p)
+			}
+		case *groupResolver:
+			addSimple(r.providers...)
+		case *sliceGroupResolver:
+			addSimple(r.providers...)
+		}
+	}
+
+	var unused []string
+	for loc, ok := range called {
+		if !ok {
+			unused = append(unused, loc.String())
+		}
+	}
+	sort.Strings(unused)
+
+	for _, loc := range unused {
+		c.warn(WarningUnusedProvider, SeverityInfo, "provider %s was not called, none of its outputs is needed", loc)
+	}
+}
@@ -0,0 +1,107 @@
+package depinject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type warningsDuck interface{ quack() }
+
+type warningsMallard struct{}
+
+func (warningsMallard) quack() {}
+
+func provideUsed() warningsMallard { return warningsMallard{} }
+
+func provideUnused() warningsMallard { return warningsMallard{} }
+
+// newWarningsContainer returns a container recording the warnings it emits.
+func newWarningsContainer(t *testing.T, opts ...DebugOption) (*container, *[]Warning) {
+	t.Helper()
+
+	cfg, err := newDebugConfig()
+	assert.NilError(t, err)
+
+	var warnings []Warning
+	opts = append(opts, WarningHandler(func(w Warning) { warnings = append(warnings, w) }))
+	assert.NilError(t, DebugOptions(opts...).applyConfig(cfg))
+	return newContainer(cfg), &warnings
+}
+
+func TestWarnShadowedBinding(t *testing.T) {
+	ctr, warnings := newWarningsContainer(t)
+
+	assert.NilError(t, Configs(
+		BindInterface("duck.Duck", "duck.Mallard"),
+		BindInterfaceInModule("pond", "duck.Duck", "duck.Canvasback"),
+	).apply(ctr))
+	assert.Equal(t, len(*warnings), 0)
+
+	assert.NilError(t, BindInterface("duck.Duck", "duck.Canvasback").apply(ctr))
+	assert.Equal(t, len(*warnings), 1)
+	w := (*warnings)[0]
+	assert.Equal(t, w.Kind, WarningShadowedBinding)
+	assert.Equal(t, w.Severity, SeverityWarning)
+	assert.Equal(t, w.Message, "binding of interface duck.Duck to duck.Canvasback globally shadows its binding to duck.Mallard")
+}
+
+func TestWarnImplicitBinding(t *testing.T) {
+	ctr, warnings := newWarningsContainer(t)
+
+	mallardType := reflect.TypeOf(warningsMallard{})
+	ctr.addResolver(mallardType, &simpleResolver{
+		typ:  mallardType,
+		node: &simpleProvider{provider: &providerDescriptor{Location: LocationFromPC(reflect.ValueOf(provideUsed).Pointer())}},
+	})
+
+	duckType := reflect.TypeOf((*warningsDuck)(nil)).Elem()
+	r, err := ctr.resolveInterfaceType(duckType)
+	assert.NilError(t, err)
+	assert.Equal(t, r.getType(), mallardType)
+
+	assert.Equal(t, len(*warnings), 1)
+	assert.Equal(t, (*warnings)[0].Kind, WarningImplicitBinding)
+	assert.Equal(t, (*warnings)[0].Severity, SeverityWarning)
+}
+
+func TestWarnUnusedProviders(t *testing.T) {
+	ctr, warnings := newWarningsContainer(t)
+
+	used := &simpleProvider{
+		provider: &providerDescriptor{Location: LocationFromPC(reflect.ValueOf(provideUsed).Pointer())},
+		called:   true,
+	}
+	unused := &simpleProvider{
+		provider: &providerDescriptor{Location: LocationFromPC(reflect.ValueOf(provideUnused).Pointer())},
+	}
+	ctr.resolvers["used"] = &simpleResolver{node: used}
+	ctr.resolvers["unused"] = &simpleResolver{node: unused}
+	// a provider is used when any of its outputs is
+	ctr.resolvers["used2"] = &simpleResolver{node: &simpleProvider{provider: used.provider}}
+
+	ctr.warnUnusedProviders()
+	assert.Equal(t, len(*warnings), 1)
+	assert.Equal(t, (*warnings)[0].Kind, WarningUnusedProvider)
+	assert.Equal(t, (*warnings)[0].Severity, SeverityInfo)
+	assert.Equal(t, (*warnings)[0].Message, "provider "+unused.provider.Location.String()+" was not called, none of its outputs is needed")
+}
+
+func TestWarningsAsErrors(t *testing.T) {
+	ctr, _ := newWarningsContainer(t)
+	ctr.warn(WarningShadowedBinding, SeverityWarning, "shadowed")
+	// warnings are only errors with WarningsAsErrors
+	assert.NilError(t, ctr.checkWarnings())
+
+	ctr, _ = newWarningsContainer(t, WarningsAsErrors())
+	ctr.warn(WarningUnusedProvider, SeverityInfo, "unused")
+	assert.NilError(t, ctr.checkWarnings())
+
+	ctr.warn(WarningShadowedBinding, SeverityWarning, "shadowed")
+	err := ctr.checkWarnings()
+	assert.Assert(t, errors.Is(err, ErrWarningsAsErrors))
+	assert.ErrorContains(t, err, "1 warning(s)")
+	assert.ErrorContains(t, err, "WARN shadowed-binding: shadowed")
+}
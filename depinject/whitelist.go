@@ -0,0 +1,71 @@
+package depinject
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ModuleWhitelist restricts the types the providers and invokers of a module
+// may consume to the given fully-qualified type names, in the form used by
+// BindInterface, e.g. "cosmossdk.io/core/store/store.KVStoreService".
+// Resolving any other type for the module fails with ErrTypeNotWhitelisted, so
+// that a module only gets the capabilities the app author granted it. Slices
+// of many-per-container types and maps of one-per-module types are allowed by
+// whitelisting their element type. The ModuleKey and OwnModuleKey inputs are
+// always allowed.
+//
+// Modules without a whitelist may consume any type. Whitelisting a module
+// several times allows the union of the types.
+//
+// Example:
+//
+//	ModuleWhitelist(
+//	    "bank", // module name
+//	    "cosmossdk.io/core/store/store.KVStoreService",
+//	    "github.com/cosmos/cosmos-sdk/x/bank/types/types.AccountKeeper",
+//	)
+func ModuleWhitelist(moduleName string, typeNames ...string) Config {
+	return containerConfig(func(ctr *container) error {
+		if moduleName == "" {
+			return ErrEmptyModuleName
+		}
+
+		if ctr.moduleWhitelists == nil {
+			ctr.moduleWhitelists = make(map[string]map[string]bool)
+		}
+		whitelist, ok := ctr.moduleWhitelists[moduleName]
+		if !ok {
+			whitelist = make(map[string]bool)
+			ctr.moduleWhitelists[moduleName] = whitelist
+		}
+		for _, name := range typeNames {
+			whitelist[name] = true
+		}
+		return nil
+	})
+}
+
+// checkWhitelist returns an ErrTypeNotWhitelisted error if the module of key
+// has a whitelist which doesn't allow it to consume typ.
+func (c *container) checkWhitelist(typ reflect.Type, key *moduleKey, loc Location) error {
+	if key == nil || typ == moduleKeyType || typ == ownModuleKeyType {
+		return nil
+	}
+
+	whitelist, ok := c.moduleWhitelists[key.name]
+	if !ok {
+		return nil
+	}
+	if whitelist[fullyQualifiedTypeName(typ)] || whitelist[fullyQualifiedTypeName(c.getElementType(typ))] {
+		return nil
+	}
+
+	allowed := make([]string, 0, len(whitelist))
+	for name := range whitelist {
+		allowed = append(allowed, name)
+	}
+	sort.Strings(allowed)
+	return errors.WithStack(newErrTypeNotWhitelisted(key.name, typ, loc, allowed))
+}
@@ -0,0 +1,42 @@
+package depinject
+
+import (
+	"reflect"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type whitelistKeeper struct{}
+
+type whitelistStore struct{}
+
+func TestModuleWhitelist(t *testing.T) {
+	cfg, err := newDebugConfig()
+	assert.NilError(t, err)
+	ctr := newContainer(cfg)
+
+	keeperType := reflect.TypeOf(whitelistKeeper{})
+	storeType := reflect.TypeOf(whitelistStore{})
+	loc := LocationFromCaller(0)
+
+	assert.NilError(t, Configs(
+		ModuleWhitelist("bank", fullyQualifiedTypeName(storeType)),
+		ModuleWhitelist("bank", fullyQualifiedTypeName(reflect.TypeOf(""))),
+	).apply(ctr))
+	assert.ErrorIs(t, ModuleWhitelist("").apply(ctr), ErrEmptyModuleName)
+
+	bank := &moduleKey{name: "bank"}
+	assert.NilError(t, ctr.checkWhitelist(storeType, bank, loc))
+	assert.NilError(t, ctr.checkWhitelist(stringType, bank, loc))
+	assert.NilError(t, ctr.checkWhitelist(moduleKeyType, bank, loc))
+	assert.NilError(t, ctr.checkWhitelist(ownModuleKeyType, bank, loc))
+
+	err = ctr.checkWhitelist(keeperType, bank, loc)
+	assert.Assert(t, IsTypeNotWhitelistedError(err))
+	assert.ErrorContains(t, err, `Module "bank" is not allowed to consume type cosmossdk.io/depinject/depinject.whitelistKeeper`)
+
+	// modules without a whitelist and the global scope aren't restricted
+	assert.NilError(t, ctr.checkWhitelist(keeperType, &moduleKey{name: "staking"}, loc))
+	assert.NilError(t, ctr.checkWhitelist(keeperType, nil, loc))
+}
@@ -0,0 +1,140 @@
+package depinject
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// WireCleanups collects the cleanup functions returned by the providers
+// registered with ProvideWire.
+type WireCleanups struct {
+	fns []func()
+}
+
+// Cleanup calls the collected cleanup functions in the reverse order of the
+// calls of the providers which returned them, as the cleanup function of a
+// wire injector does, and forgets them.
+func (c *WireCleanups) Cleanup() {
+	for i := len(c.fns) - 1; i >= 0; i-- {
+		c.fns[i]()
+	}
+	c.fns = nil
+}
+
+var cleanupType = reflect.TypeOf((func())(nil))
+
+// ProvideWire registers providers written for google/wire in global scope.
+// Besides the providers accepted by Provide, it accepts providers returning a
+// cleanup function of type func() after their values, and before their error
+// if any, which is added to cleanups when the provider is called.
+//
+// Wire provider sets are only read by the wire code generator, so their
+// providers are passed one by one: wire.NewSet(NewDB, NewServer) becomes
+// ProvideWire(cleanups, NewDB, NewServer), and the wire.Bind calls of the set
+// become WireBind configs.
+func ProvideWire(cleanups *WireCleanups, providers ...interface{}) Config {
+	loc := LocationFromCaller(1)
+	return containerConfig(func(ctr *container) error {
+		if cleanups == nil {
+			return errors.Errorf("ProvideWire at %s requires a non-nil *WireCleanups", loc)
+		}
+
+		adapted := make([]interface{}, len(providers))
+		for i, provider := range providers {
+			var err error
+			if adapted[i], err = adaptWireProvider(cleanups, provider); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		return provide(ctr, nil, adapted, loc)
+	})
+}
+
+// adaptWireProvider returns a ProviderDescriptor for a provider returning a
+// cleanup function, and the provider itself otherwise.
+func adaptWireProvider(cleanups *WireCleanups, provider interface{}) (interface{}, error) {
+	val := reflect.ValueOf(provider)
+	typ := val.Type()
+	if typ.Kind() != reflect.Func {
+		return provider, nil
+	}
+
+	numOut := typ.NumOut()
+	cleanupIdx := -1
+	for i := 0; i < numOut; i++ {
+		if typ.Out(i) == cleanupType {
+			cleanupIdx = i
+		}
+	}
+	if cleanupIdx < 0 {
+		return provider, nil
+	}
+
+	name := LocationFromPC(val.Pointer()).Name()
+	hasErr := typ.Out(numOut-1) == errType
+	lastValue := numOut - 1
+	if hasErr {
+		lastValue--
+	}
+	if cleanupIdx == 0 || cleanupIdx != lastValue {
+		return nil, errors.Errorf("wire provider %s must return its cleanup function after its values and before its error", name)
+	}
+	if typ.IsVariadic() {
+		return nil, errors.Errorf("variadic function can't be used as a provider: %s", name)
+	}
+
+	desc := ProviderDescriptor{Name: name}
+	for i := 0; i < typ.NumIn(); i++ {
+		desc.Inputs = append(desc.Inputs, ProviderInput{Type: typ.In(i)})
+	}
+	for i := 0; i < cleanupIdx; i++ {
+		desc.Outputs = append(desc.Outputs, typ.Out(i))
+	}
+
+	desc.Fn = func(inputs []interface{}) ([]interface{}, error) {
+		args := make([]reflect.Value, len(inputs))
+		for i, in := range inputs {
+			if in == nil {
+				args[i] = reflect.Zero(typ.In(i))
+			} else {
+				args[i] = reflect.ValueOf(in)
+			}
+		}
+
+		res := val.Call(args)
+		if hasErr && !res[numOut-1].IsNil() {
+			return nil, res[numOut-1].Interface().(error)
+		}
+		if cleanup := res[cleanupIdx].Interface().(func()); cleanup != nil {
+			cleanups.fns = append(cleanups.fns, cleanup)
+		}
+
+		outputs := make([]interface{}, cleanupIdx)
+		for i := range outputs {
+			outputs[i] = res[i].Interface()
+		}
+		return outputs, nil
+	}
+	return desc, nil
+}
+
+// WireBind binds an interface to an implementation in global scope, given the
+// arguments of the equivalent wire.Bind call: a pointer to the interface type
+// and a pointer to the implementation type, e.g. WireBind(new(Duck),
+// new(*Mallard)).
+func WireBind(iface, to interface{}) Config {
+	ifaceType, toType := reflect.TypeOf(iface), reflect.TypeOf(to)
+	return containerConfig(func(ctr *container) error {
+		if ifaceType == nil || ifaceType.Kind() != reflect.Pointer || ifaceType.Elem().Kind() != reflect.Interface {
+			return errors.Errorf("WireBind expects a pointer to an interface type, got %v", ifaceType)
+		}
+		if toType == nil || toType.Kind() != reflect.Pointer {
+			return errors.Errorf("WireBind expects a pointer to the implementation type, got %v", toType)
+		}
+		if !toType.Elem().Implements(ifaceType.Elem()) {
+			return errors.Errorf("%v doesn't implement %v", toType.Elem(), ifaceType.Elem())
+		}
+		return bindInterface(ctr, fullyQualifiedTypeName(ifaceType.Elem()), fullyQualifiedTypeName(toType.Elem()), "")
+	})
+}
@@ -0,0 +1,77 @@
+package depinject
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type WireDB struct{ Name string }
+
+var wireClosed []string
+
+func ProvideWireDB(name string) (WireDB, func(), error) {
+	if name == "" {
+		return WireDB{}, nil, errors.New("missing name")
+	}
+	return WireDB{Name: name}, func() { wireClosed = append(wireClosed, name) }, nil
+}
+
+func ProvideWireNoCleanup(int) string { return "" }
+
+func BadWireCleanupPosition() (func(), int) { return nil, 0 }
+
+func TestAdaptWireProvider(t *testing.T) {
+	cleanups := &WireCleanups{}
+
+	adapted, err := adaptWireProvider(cleanups, ProvideWireNoCleanup)
+	assert.NilError(t, err)
+	_, isDescriptor := adapted.(ProviderDescriptor)
+	assert.Assert(t, !isDescriptor)
+
+	_, err = adaptWireProvider(cleanups, BadWireCleanupPosition)
+	assert.ErrorContains(t, err, "must return its cleanup function after its values")
+
+	adapted, err = adaptWireProvider(cleanups, ProvideWireDB)
+	assert.NilError(t, err)
+	desc := adapted.(ProviderDescriptor)
+	assert.Equal(t, "cosmossdk.io/depinject.ProvideWireDB", desc.Name)
+	assert.Assert(t, reflect.DeepEqual([]ProviderInput{{Type: TypeOf[string]()}}, desc.Inputs))
+	assert.Assert(t, reflect.DeepEqual([]reflect.Type{TypeOf[WireDB]()}, desc.Outputs))
+
+	out, err := desc.Fn([]interface{}{"a"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []interface{}{WireDB{Name: "a"}}, out)
+	_, err = desc.Fn([]interface{}{"b"})
+	assert.NilError(t, err)
+	_, err = desc.Fn([]interface{}{""})
+	assert.ErrorContains(t, err, "missing name")
+	assert.Equal(t, 2, len(cleanups.fns))
+
+	// cleanups run in reverse order, once
+	cleanups.Cleanup()
+	cleanups.Cleanup()
+	assert.DeepEqual(t, []string{"b", "a"}, wireClosed)
+}
+
+type WireStore interface{ Get() string }
+
+type WireMemStore struct{}
+
+func (*WireMemStore) Get() string { return "" }
+
+func TestWireBind(t *testing.T) {
+	manifest, err := BuildManifest(WireBind(new(WireStore), new(*WireMemStore)))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []ManifestBinding{{
+		Interface:      "cosmossdk.io/depinject/depinject.WireStore",
+		Implementation: "cosmossdk.io/depinject/*depinject.WireMemStore",
+	}}, manifest.Bindings)
+
+	_, err = BuildManifest(WireBind(new(WireStore), new(WireMemStore)))
+	assert.ErrorContains(t, err, "doesn't implement")
+	_, err = BuildManifest(WireBind(new(WireMemStore), new(*WireMemStore)))
+	assert.ErrorContains(t, err, "expects a pointer to an interface type")
+}
@@ -94,6 +94,11 @@ type BaseConfig struct {
 	// IAVLLazyLoading enable/disable the lazy loading of iavl store.
 	IAVLLazyLoading bool `mapstructure:"iavl-lazy-loading"`
 
+	// QueryGasLimit defines the gas limit of gRPC and ABCI queries, independent
+	// of the block gas limit, so that expensive queries can't stall the node.
+	// A value of 0 indicates no limit.
+	QueryGasLimit uint64 `mapstructure:"query-gas-limit"`
+
 	// AppDBBackend defines the type of Database to use for the application and snapshots databases.
 	// An empty string indicates that the Tendermint config's DBBackend value should be used.
 	AppDBBackend string `mapstructure:"app-db-backend"`
@@ -202,6 +207,26 @@ type StateSyncConfig struct {
 	SnapshotKeepRecent uint32 `mapstructure:"snapshot-keep-recent"`
 }
 
+// SnapshotStoreConfig defines the snapshot repository used by the snapshots
+// commands.
+type SnapshotStoreConfig struct {
+	// Backend is the snapshot store backend: local, s3 or gcs.
+	Backend string `mapstructure:"backend"`
+
+	// Bucket is the bucket holding the snapshots of a remote backend.
+	Bucket string `mapstructure:"bucket"`
+
+	// Prefix is the object key prefix of the snapshots of a remote backend.
+	Prefix string `mapstructure:"prefix"`
+
+	// Region is the region of the s3 bucket.
+	Region string `mapstructure:"region"`
+
+	// Endpoint overrides the s3 or gcs service endpoint, e.g. for S3 compatible
+	// storage services.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
 // MempoolConfig defines the configurations for the SDK built-in app-side mempool
 // implementations.
 type MempoolConfig struct {
@@ -212,6 +237,20 @@ type MempoolConfig struct {
 	MaxTxs int
 }
 
+// RateLimitConfig defines the rate limits of the transactions accepted by
+// CheckTx. A limit is disabled when its rate or burst is zero, and the rate
+// limiter is only set when one of them is enabled.
+type RateLimitConfig struct {
+	// SenderPerSecond is the number of transactions each signer can send per
+	// second on average, and SenderBurst the number it can send at once.
+	SenderPerSecond float64 `mapstructure:"sender-per-second"`
+	SenderBurst     uint64  `mapstructure:"sender-burst"`
+	// MsgTypePerSecond is the number of messages of each type accepted per
+	// second on average, and MsgTypeBurst the number accepted at once.
+	MsgTypePerSecond float64 `mapstructure:"msg-type-per-second"`
+	MsgTypeBurst     uint64  `mapstructure:"msg-type-burst"`
+}
+
 type (
 	// StoreConfig defines application configuration for state streaming and other
 	// storage related operations.
@@ -257,6 +296,9 @@ type Config struct {
 	Store     StoreConfig      `mapstructure:"store"`
 	Streamers StreamersConfig  `mapstructure:"streamers"`
 	Mempool   MempoolConfig    `mapstructure:"mempool"`
+	RateLimit RateLimitConfig  `mapstructure:"rate-limit"`
+
+	SnapshotStore SnapshotStoreConfig `mapstructure:"snapshot-store"`
 }
 
 // SetMinGasPrices sets the validator's minimum gas prices.
@@ -300,6 +342,7 @@ func DefaultConfig() *Config {
 			IAVLCacheSize:       781250,
 			IAVLDisableFastNode: false,
 			IAVLLazyLoading:     false,
+			QueryGasLimit:       0,
 			AppDBBackend:        "",
 		},
 		Telemetry: telemetry.Config{
@@ -339,6 +382,9 @@ func DefaultConfig() *Config {
 			SnapshotInterval:   0,
 			SnapshotKeepRecent: 2,
 		},
+		SnapshotStore: SnapshotStoreConfig{
+			Backend: "local",
+		},
 		Store: StoreConfig{
 			Streamers: []string{},
 		},
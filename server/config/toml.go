@@ -81,6 +81,11 @@ iavl-disable-fastnode = {{ .BaseConfig.IAVLDisableFastNode }}
 # Default is false.
 iavl-lazy-loading = {{ .BaseConfig.IAVLLazyLoading }}
 
+# QueryGasLimit defines the gas limit of gRPC and ABCI queries, independent of
+# the block gas limit, so that expensive queries can't stall the node.
+# A value of 0 indicates no limit.
+query-gas-limit = {{ .BaseConfig.QueryGasLimit }}
+
 # AppDBBackend defines the database backend type to use for the application and snapshots DBs.
 # An empty string indicates that a fallback will be used.
 # The fallback is the db_backend value set in Tendermint's config.toml.
@@ -238,6 +243,31 @@ snapshot-interval = {{ .StateSync.SnapshotInterval }}
 # snapshot-keep-recent specifies the number of recent snapshots to keep and serve (0 to keep all).
 snapshot-keep-recent = {{ .StateSync.SnapshotKeepRecent }}
 
+###############################################################################
+###                        Snapshot Store Configuration                     ###
+###############################################################################
+
+# The snapshot store is the repository the snapshots commands (list, dump, load,
+# delete, prune) operate on. Remote backends read their credentials from the
+# environment: the AWS default credential chain for s3, and the Google application
+# default credentials for gcs.
+[snapshot-store]
+
+# backend is the snapshot store backend: local (the node's data/snapshots directory), s3 or gcs.
+backend = "{{ .SnapshotStore.Backend }}"
+
+# bucket is the bucket holding the snapshots of a remote backend.
+bucket = "{{ .SnapshotStore.Bucket }}"
+
+# prefix is the object key prefix of the snapshots within the bucket.
+prefix = "{{ .SnapshotStore.Prefix }}"
+
+# region is the region of the s3 bucket.
+region = "{{ .SnapshotStore.Region }}"
+
+# endpoint overrides the service endpoint, e.g. for S3 compatible storage services.
+endpoint = "{{ .SnapshotStore.Endpoint }}"
+
 ###############################################################################
 ###                         Store / State Streaming                         ###
 ###############################################################################
@@ -273,6 +303,25 @@ fsync = "{{ .Streamers.File.Fsync }}"
 # Note, this configuration only applies to SDK built-in app-side mempool
 # implementations.
 max-txs = "{{ .Mempool.MaxTxs }}"
+
+###############################################################################
+###                         Rate Limit                                      ###
+###############################################################################
+
+[rate-limit]
+# The rate limits of the transactions accepted by CheckTx. A limit is disabled
+# when its rate or its burst is 0. The allowances are only charged once the ante
+# handler verified the signatures and fees of a transaction.
+#
+# sender-per-second is the number of transactions each signer can send per second,
+# on average, and sender-burst the number of transactions it can send at once.
+sender-per-second = {{ .RateLimit.SenderPerSecond }}
+sender-burst = {{ .RateLimit.SenderBurst }}
+
+# msg-type-per-second is the number of messages of each type accepted per second,
+# on average, and msg-type-burst the number of messages accepted at once.
+msg-type-per-second = {{ .RateLimit.MsgTypePerSecond }}
+msg-type-burst = {{ .RateLimit.MsgTypeBurst }}
 `
 
 var configTemplate *template.Template
@@ -60,6 +60,7 @@ const (
 	FlagIAVLCacheSize       = "iavl-cache-size"
 	FlagDisableIAVLFastNode = "iavl-disable-fastnode"
 	FlagIAVLLazyLoading     = "iavl-lazy-loading"
+	FlagQueryGasLimit       = "query-gas-limit"
 
 	// state sync-related flags
 	FlagStateSyncSnapshotInterval   = "state-sync.snapshot-interval"
@@ -84,6 +85,12 @@ const (
 
 	// mempool flags
 	FlagMempoolMaxTxs = "mempool.max-txs"
+
+	// rate limit flags
+	FlagRateLimitSenderPerSecond  = "rate-limit.sender-per-second"
+	FlagRateLimitSenderBurst      = "rate-limit.sender-burst"
+	FlagRateLimitMsgTypePerSecond = "rate-limit.msg-type-per-second"
+	FlagRateLimitMsgTypeBurst     = "rate-limit.msg-type-burst"
 )
 
 // StartCmd runs the service passed in, either stand-alone or in-process with
@@ -177,6 +184,7 @@ is performed. Note, when enabled, gRPC will also be automatically enabled.
 	cmd.Flags().Uint64(FlagPruningInterval, 0, "Height interval at which pruned heights are removed from disk (ignored if pruning is not 'custom')")
 	cmd.Flags().Uint(FlagInvCheckPeriod, 0, "Assert registered invariants every N blocks")
 	cmd.Flags().Uint64(FlagMinRetainBlocks, 0, "Minimum block height offset during ABCI commit to prune Tendermint blocks")
+	cmd.Flags().Uint64(FlagQueryGasLimit, 0, "Gas limit of gRPC and ABCI queries, independent of the block gas limit (0 for no limit)")
 
 	cmd.Flags().Bool(FlagAPIEnable, false, "Define if the API server should be enabled")
 	cmd.Flags().Bool(FlagAPISwagger, false, "Define if swagger documentation should automatically be registered (Note: the API must also be enabled)")
@@ -201,6 +209,11 @@ is performed. Note, when enabled, gRPC will also be automatically enabled.
 
 	cmd.Flags().Int(FlagMempoolMaxTxs, mempool.DefaultMaxTx, "Sets MaxTx value for the app-side mempool")
 
+	cmd.Flags().Float64(FlagRateLimitSenderPerSecond, 0, "Number of txs each signer can send per second in CheckTx (0 disables the sender limit)")
+	cmd.Flags().Uint64(FlagRateLimitSenderBurst, 0, "Number of txs each signer can send at once in CheckTx")
+	cmd.Flags().Float64(FlagRateLimitMsgTypePerSecond, 0, "Number of msgs of each type accepted per second in CheckTx (0 disables the msg type limit)")
+	cmd.Flags().Uint64(FlagRateLimitMsgTypeBurst, 0, "Number of msgs of each type accepted at once in CheckTx")
+
 	// add support for all Tendermint-specific command line options
 	tcmd.AddNodeFlags(cmd)
 	return cmd
@@ -3,8 +3,10 @@ package server
 // DONTCOVER
 
 import (
+	"context"
 	"fmt"
 
+	cmtcfg "github.com/cometbft/cometbft/config"
 	"github.com/cometbft/cometbft/light"
 	"github.com/cometbft/cometbft/node"
 	"github.com/cometbft/cometbft/p2p"
@@ -150,21 +152,7 @@ func BootstrapStateCmd(appCreator types.AppCreator) *cobra.Command {
 				height = app.CommitMultiStore().LastCommitID().Version
 			}
 
-			blockStoreDB, err := node.DefaultDBProvider(&node.DBContext{ID: "blockstore", Config: cfg})
-			if err != nil {
-				return err
-			}
-			blockStore := store.NewBlockStore(blockStoreDB)
-
-			stateDB, err := node.DefaultDBProvider(&node.DBContext{ID: "state", Config: cfg})
-			if err != nil {
-				return err
-			}
-			stateStore := sm.NewStore(stateDB, sm.StoreOptions{
-				DiscardABCIResponses: cfg.Storage.DiscardABCIResponses,
-			})
-
-			genState, _, err := node.LoadStateFromDBOrGenesisDocProvider(stateDB, node.DefaultGenesisDocProviderFunc(cfg))
+			genState, err := LoadGenesisState(cfg)
 			if err != nil {
 				return err
 			}
@@ -181,35 +169,71 @@ func BootstrapStateCmd(appCreator types.AppCreator) *cobra.Command {
 				return fmt.Errorf("failed to set up light client state provider: %w", err)
 			}
 
-			state, err := stateProvider.State(cmd.Context(), uint64(height))
-			if err != nil {
-				return fmt.Errorf("failed to get state: %w", err)
-			}
+			return BootstrapStateFromProvider(cmd.Context(), cfg, stateProvider, uint64(height))
+		},
+	}
 
-			commit, err := stateProvider.Commit(cmd.Context(), uint64(height))
-			if err != nil {
-				return fmt.Errorf("failed to get commit: %w", err)
-			}
+	cmd.Flags().Int64("height", 0, "Block height to bootstrap state at, if not provided it uses the latest block height in app state")
 
-			if err := stateStore.Bootstrap(state); err != nil {
-				return fmt.Errorf("failed to bootstrap state: %w", err)
-			}
+	return cmd
+}
 
-			if err := blockStore.SaveSeenCommit(state.LastBlockHeight, commit); err != nil {
-				return fmt.Errorf("failed to save seen commit: %w", err)
-			}
+// LoadGenesisState returns the CometBFT state stored in the node's state
+// database, or the genesis state if the database is empty.
+func LoadGenesisState(cfg *cmtcfg.Config) (sm.State, error) {
+	stateDB, err := node.DefaultDBProvider(&node.DBContext{ID: "state", Config: cfg})
+	if err != nil {
+		return sm.State{}, err
+	}
+	defer stateDB.Close()
 
-			store.SaveBlockStoreState(&cmtstore.BlockStoreState{
-				// it breaks the invariant that blocks in range [Base, Height] must exists, but it do works in practice.
-				Base:   state.LastBlockHeight,
-				Height: state.LastBlockHeight,
-			}, blockStoreDB)
+	state, _, err := node.LoadStateFromDBOrGenesisDocProvider(stateDB, node.DefaultGenesisDocProviderFunc(cfg))
+	return state, err
+}
 
-			return nil
-		},
+// BootstrapStateFromProvider bootstraps the CometBFT state and block stores at
+// the given height with the state and commit returned by the state provider,
+// so that the node can start from an app state restored at that height.
+func BootstrapStateFromProvider(ctx context.Context, cfg *cmtcfg.Config, stateProvider statesync.StateProvider, height uint64) error {
+	blockStoreDB, err := node.DefaultDBProvider(&node.DBContext{ID: "blockstore", Config: cfg})
+	if err != nil {
+		return err
 	}
+	defer blockStoreDB.Close()
+	blockStore := store.NewBlockStore(blockStoreDB)
 
-	cmd.Flags().Int64("height", 0, "Block height to bootstrap state at, if not provided it uses the latest block height in app state")
+	stateDB, err := node.DefaultDBProvider(&node.DBContext{ID: "state", Config: cfg})
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{
+		DiscardABCIResponses: cfg.Storage.DiscardABCIResponses,
+	})
+
+	state, err := stateProvider.State(ctx, height)
+	if err != nil {
+		return fmt.Errorf("failed to get state: %w", err)
+	}
 
-	return cmd
+	commit, err := stateProvider.Commit(ctx, height)
+	if err != nil {
+		return fmt.Errorf("failed to get commit: %w", err)
+	}
+
+	if err := stateStore.Bootstrap(state); err != nil {
+		return fmt.Errorf("failed to bootstrap state: %w", err)
+	}
+
+	if err := blockStore.SaveSeenCommit(state.LastBlockHeight, commit); err != nil {
+		return fmt.Errorf("failed to save seen commit: %w", err)
+	}
+
+	store.SaveBlockStoreState(&cmtstore.BlockStoreState{
+		// it breaks the invariant that blocks in range [Base, Height] must exists, but it do works in practice.
+		Base:   state.LastBlockHeight,
+		Height: state.LastBlockHeight,
+	}, blockStoreDB)
+
+	return nil
 }
@@ -476,7 +476,18 @@ func DefaultBaseappOptions(appOpts types.AppOptions) []func(*baseapp.BaseApp) {
 		cast.ToUint32(appOpts.Get(FlagStateSyncSnapshotKeepRecent)),
 	)
 
-	return []func(*baseapp.BaseApp){
+	rateLimits := baseapp.TokenBucketRateLimiterConfig{
+		PerSender: baseapp.RateLimit{
+			PerSecond: cast.ToFloat64(appOpts.Get(FlagRateLimitSenderPerSecond)),
+			Burst:     cast.ToUint64(appOpts.Get(FlagRateLimitSenderBurst)),
+		},
+		PerMsgType: baseapp.RateLimit{
+			PerSecond: cast.ToFloat64(appOpts.Get(FlagRateLimitMsgTypePerSecond)),
+			Burst:     cast.ToUint64(appOpts.Get(FlagRateLimitMsgTypeBurst)),
+		},
+	}
+
+	opts := []func(*baseapp.BaseApp){
 		baseapp.SetPruning(pruningOpts),
 		baseapp.SetMinGasPrices(cast.ToString(appOpts.Get(FlagMinGasPrices))),
 		baseapp.SetHaltHeight(cast.ToUint64(appOpts.Get(FlagHaltHeight))),
@@ -494,13 +505,22 @@ func DefaultBaseappOptions(appOpts types.AppOptions) []func(*baseapp.BaseApp) {
 			),
 		),
 		baseapp.SetIAVLLazyLoading(cast.ToBool(appOpts.Get(FlagIAVLLazyLoading))),
+		baseapp.SetQueryGasLimit(cast.ToUint64(appOpts.Get(FlagQueryGasLimit))),
 		baseapp.SetChainID(chainID),
 	}
+	if rateLimits.Enabled() {
+		opts = append(opts, baseapp.SetRateLimiter(baseapp.NewTokenBucketRateLimiter(rateLimits)))
+	}
+	return opts
+}
+
+// GetSnapshotDir returns the directory of the local snapshot store.
+func GetSnapshotDir(appOpts types.AppOptions) string {
+	return filepath.Join(cast.ToString(appOpts.Get(flags.FlagHome)), "data", "snapshots")
 }
 
 func GetSnapshotStore(appOpts types.AppOptions) (*snapshots.Store, error) {
-	homeDir := cast.ToString(appOpts.Get(flags.FlagHome))
-	snapshotDir := filepath.Join(homeDir, "data", "snapshots")
+	snapshotDir := GetSnapshotDir(appOpts)
 	if err := os.MkdirAll(snapshotDir, os.ModePerm); err != nil {
 		return nil, fmt.Errorf("failed to create snapshots directory: %w", err)
 	}
@@ -253,6 +253,9 @@ func NewSimApp(
 	// baseAppOptions = append(baseAppOptions, prepareOpt)
 
 	bApp := baseapp.NewBaseApp(appName, logger, db, txConfig.TxDecoder(), baseAppOptions...)
+	// the amino JSON txs of legacy clients are accepted too while they migrate
+	// to protobuf
+	bApp.SetTxDecoders(txConfig.TxDecoder(), authtx.AminoJSONTxDecoder(legacyAmino, appCodec))
 	bApp.SetCommitMultiStoreTracer(traceStore)
 	bApp.SetVersion(version.Version)
 	bApp.SetInterfaceRegistry(interfaceRegistry)
@@ -545,6 +548,8 @@ func (app *SimApp) setAnteHandler(txConfig client.TxConfig) {
 	}
 
 	app.SetAnteHandler(anteHandler)
+	app.SetSignatureAlgorithms(ante.DefaultSignatureAlgorithms...)
+	app.SetTxSignatureExtractor(ante.NewTxSignatureExtractor(app.AccountKeeper, txConfig.SignModeHandler()), 0)
 }
 
 func (app *SimApp) setPostHandler() {
@@ -23,6 +23,8 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/keys"
 	"github.com/cosmos/cosmos-sdk/client/pruning"
 	"github.com/cosmos/cosmos-sdk/client/rpc"
+	_ "github.com/cosmos/cosmos-sdk/client/rpc/kafkasink" // the kafka sink of query subscribe
+	_ "github.com/cosmos/cosmos-sdk/client/rpc/natssink"  // the nats sink of query subscribe
 	"github.com/cosmos/cosmos-sdk/client/snapshot"
 	"github.com/cosmos/cosmos-sdk/server"
 	serverconfig "github.com/cosmos/cosmos-sdk/server/config"
@@ -56,6 +58,7 @@ func NewRootCmd() *cobra.Command {
 		WithInput(os.Stdin).
 		WithAccountRetriever(types.AccountRetriever{}).
 		WithHomeDir(simapp.DefaultNodeHome).
+		WithKeyringOptions(keys.PQCKeyringOption()).
 		WithViper("") // In simapp, we don't use any prefix for env variables.
 
 	rootCmd := &cobra.Command{
@@ -215,10 +218,18 @@ func queryCommand() *cobra.Command {
 
 	cmd.AddCommand(
 		rpc.QueryEventForTxCmd(),
+		rpc.SubscribeCommand(),
 		authcmd.GetAccountCmd(),
 		rpc.ValidatorCommand(),
 		rpc.BlockCommand(),
-		authcmd.QueryTxsByEventsCmd(),
+		rpc.SnapshotsOfferedCommand(),
+		rpc.StoreQueryCommand(),
+		rpc.ValidatorUptimeCommand(),
+		rpc.BalancesCommand(),
+		rpc.SupplyCommand(),
+		rpc.UpgradeWatchCommand(),
+		rpc.ValidatorPowersCommand(),
+		rpc.TxSearchCommand(),
 		authcmd.QueryTxCmd(),
 	)
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"cosmossdk.io/simapp"
@@ -12,11 +13,17 @@ import (
 func main() {
 	rootCmd := cmd.NewRootCmd()
 	if err := svrcmd.Execute(rootCmd, "", simapp.DefaultNodeHome); err != nil {
+		// errors of the key commands carry their exit code
+		var exitErr interface{ ExitCode() int }
+
 		switch e := err.(type) {
 		case server.ErrorCode:
 			os.Exit(e.Code)
 
 		default:
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
 			os.Exit(1)
 		}
 	}
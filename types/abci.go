@@ -27,3 +27,50 @@ type ProcessProposalHandler func(Context, abci.RequestProcessProposal) abci.Resp
 
 // PrepareProposalHandler defines a function type alias for preparing a proposal
 type PrepareProposalHandler func(Context, abci.RequestPrepareProposal) abci.ResponsePrepareProposal
+
+// RequestExtendVote asks the application for the extension of the precommit
+// vote of the node for the block of the given hash and height.
+//
+// CometBFT v0.37 has no ExtendVote ABCI method: the request mirrors the ABCI
+// 2.0 one, so that applications can implement their handlers before the
+// consensus engine calls them.
+type RequestExtendVote struct {
+	Hash   []byte
+	Height int64
+}
+
+// ResponseExtendVote is the response of an ExtendVoteHandler.
+type ResponseExtendVote struct {
+	VoteExtension []byte
+}
+
+// RequestVerifyVoteExtension asks the application to verify the extension of
+// the precommit vote of another validator for the block of the given hash and
+// height.
+type RequestVerifyVoteExtension struct {
+	Hash             []byte
+	ValidatorAddress []byte
+	Height           int64
+	VoteExtension    []byte
+}
+
+// VerifyVoteExtensionStatus is the status of a verified vote extension.
+type VerifyVoteExtensionStatus int32
+
+const (
+	VerifyVoteExtensionUnknown VerifyVoteExtensionStatus = iota
+	VerifyVoteExtensionAccept
+	VerifyVoteExtensionReject
+)
+
+// ResponseVerifyVoteExtension is the response of a VerifyVoteExtensionHandler.
+type ResponseVerifyVoteExtension struct {
+	Status VerifyVoteExtensionStatus
+}
+
+// ExtendVoteHandler returns the extension of the precommit vote of the node,
+// e.g. an attestation of off-chain data such as a price feed.
+type ExtendVoteHandler func(Context, RequestExtendVote) (ResponseExtendVote, error)
+
+// VerifyVoteExtensionHandler verifies the vote extension of another validator.
+type VerifyVoteExtensionHandler func(Context, RequestVerifyVoteExtension) (ResponseVerifyVoteExtension, error)
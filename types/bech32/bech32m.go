@@ -0,0 +1,137 @@
+package bech32
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/btcutil/bech32"
+)
+
+// bech32m is the checksum variant of BIP-350, which only differs from bech32
+// by the constant the checksum is XORed with.
+const (
+	bech32mConst   = 0x2bc830a3
+	bech32mCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	// maxLength is the limit DecodeAndConvert applies to bech32 strings
+	maxLength = 1023
+)
+
+var bech32mGenerator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// ConvertAndEncodeM converts data to base32 and encodes it as bech32m
+// (BIP-350).
+func ConvertAndEncodeM(hrp string, data []byte) (string, error) {
+	converted, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("encoding bech32m failed: %w", err)
+	}
+
+	return encodeM(hrp, converted)
+}
+
+// DecodeAndConvertM decodes a bech32m (BIP-350) string and converts its data
+// back to bytes. Strings with a bech32 checksum are rejected.
+func DecodeAndConvertM(bech string) (string, []byte, error) {
+	hrp, data, err := decodeM(bech)
+	if err != nil {
+		return "", nil, fmt.Errorf("decoding bech32m failed: %w", err)
+	}
+
+	converted, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("decoding bech32m failed: %w", err)
+	}
+
+	return hrp, converted, nil
+}
+
+func encodeM(hrp string, data []byte) (string, error) {
+	if hrp == "" {
+		return "", fmt.Errorf("empty human readable part")
+	}
+	hrp = strings.ToLower(hrp)
+
+	values := append(hrpExpand(hrp), data...)
+	polymod := polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ bech32mConst
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(data) + 6)
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, v := range data {
+		if v >= 32 {
+			return "", fmt.Errorf("invalid data value %d", v)
+		}
+		b.WriteByte(bech32mCharset[v])
+	}
+	for i := 0; i < 6; i++ {
+		b.WriteByte(bech32mCharset[(polymod>>(5*(5-i)))&31])
+	}
+
+	if b.Len() > maxLength {
+		return "", fmt.Errorf("encoded string longer than %d characters", maxLength)
+	}
+	return b.String(), nil
+}
+
+func decodeM(bech string) (string, []byte, error) {
+	if len(bech) > maxLength {
+		return "", nil, fmt.Errorf("string longer than %d characters", maxLength)
+	}
+
+	lower := strings.ToLower(bech)
+	if lower != bech && strings.ToUpper(bech) != bech {
+		return "", nil, fmt.Errorf("string not all lowercase or all uppercase")
+	}
+	for i := 0; i < len(lower); i++ {
+		if lower[i] < 33 || lower[i] > 126 {
+			return "", nil, fmt.Errorf("invalid character %q", lower[i])
+		}
+	}
+
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+7 > len(lower) {
+		return "", nil, fmt.Errorf("invalid separator index %d", sep)
+	}
+
+	hrp := lower[:sep]
+	data := make([]byte, len(lower)-sep-1)
+	for i := range data {
+		v := strings.IndexByte(bech32mCharset, lower[sep+1+i])
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid character %q", lower[sep+1+i])
+		}
+		data[i] = byte(v)
+	}
+
+	if polymod(append(hrpExpand(hrp), data...)) != bech32mConst {
+		return "", nil, fmt.Errorf("invalid bech32m checksum")
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+func hrpExpand(hrp string) []byte {
+	values := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	return values
+}
+
+func polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range bech32mGenerator {
+			if (top>>i)&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
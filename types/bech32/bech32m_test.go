@@ -0,0 +1,68 @@
+package bech32_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+func TestEncodeAndDecodeM(t *testing.T) {
+	sum := sha256.Sum256([]byte("hello world\n"))
+
+	bech, err := bech32.ConvertAndEncodeM("shasum", sum[:])
+	require.NoError(t, err)
+
+	hrp, data, err := bech32.DecodeAndConvertM(bech)
+	require.NoError(t, err)
+	require.Equal(t, "shasum", hrp)
+	require.Equal(t, sum[:], data)
+
+	// the bech32 and bech32m checksums of the same data differ, and each is
+	// rejected by the decoder of the other
+	legacy, err := bech32.ConvertAndEncode("shasum", sum[:])
+	require.NoError(t, err)
+	require.NotEqual(t, legacy, bech)
+	_, _, err = bech32.DecodeAndConvertM(legacy)
+	require.Error(t, err)
+	_, _, err = bech32.DecodeAndConvert(bech)
+	require.Error(t, err)
+}
+
+// TestDecodeMVectors checks the checksums of the BIP-350 test vectors.
+func TestDecodeMVectors(t *testing.T) {
+	valid := []string{
+		"A1LQFN3A",
+		"a1lqfn3a",
+		"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6",
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+		"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+		"?1v759aa",
+	}
+	for _, s := range valid {
+		_, _, err := bech32.DecodeAndConvertM(s)
+		// the data of some vectors isn't whole bytes, only the checksum
+		// errors matter
+		if err != nil {
+			require.NotContains(t, err.Error(), "checksum", s)
+			require.NotContains(t, err.Error(), "separator", s)
+		}
+	}
+
+	invalid := []string{
+		"in1muywd",      // empty data part without checksum
+		"mm1crxm3i",     // invalid character in checksum
+		"M1VUXWEZ",      // checksum computed with uppercase hrp
+		"1qyrz8wqd2c9m", // empty hrp
+		"qyrz8wqd2c9m",  // no separator
+		"au1s5cgom",     // invalid character
+		"A1LQFN3a",      // mixed case
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", // bech32 checksum
+	}
+	for _, s := range invalid {
+		_, _, err := bech32.DecodeAndConvertM(s)
+		require.Error(t, err, s)
+	}
+}
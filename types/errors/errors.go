@@ -167,6 +167,10 @@ var (
 	// supplied.
 	ErrInvalidGasLimit = Register(RootCodespace, 41, "invalid gas limit")
 
+	// ErrRateLimited defines an error when a tx is rejected by the rate
+	// limiter of a node.
+	ErrRateLimited = Register(RootCodespace, 42, "rate limited")
+
 	// ErrPanic should only be set when we recovering from a panic
 	ErrPanic = errorsmod.ErrPanic
 )
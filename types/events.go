@@ -256,6 +256,10 @@ const (
 	AttributeKeyModule = "module"
 	AttributeKeySender = "sender"
 	AttributeKeyAmount = "amount"
+
+	// EventTypeParamChange is emitted by modules when their params change, with
+	// their name as AttributeKeyModule attribute.
+	EventTypeParamChange = "param_change"
 )
 
 type (
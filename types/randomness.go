@@ -0,0 +1,57 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+)
+
+// randomnessDomainPrefix separates the randomness of the context from other
+// hashes of the block hash.
+const randomnessDomainPrefix = "baron-chain/randomness/v1"
+
+// ErrNoRandomness is returned by Context.Randomness when the context has no
+// block hash, e.g. in InitChain or in queries.
+var ErrNoRandomness = errors.New("no randomness: the context has no block hash")
+
+// Randomness returns 32 bytes of per-block randomness, derived from the block
+// hash, the chain ID and height, and the domain. Every node derives the same
+// bytes, so that modules can use them in state transitions. Each module, or
+// each use of a module, must pass its own domain, e.g. "lottery/draw", so that
+// the values of different domains are independent.
+//
+// The block hash is known to the proposer in advance, and a proposer may
+// choose between blocks, so the randomness must not secure stakes worth
+// grinding, e.g. leader elections.
+func (c Context) Randomness(domain string) ([]byte, error) {
+	if len(c.headerHash) == 0 {
+		return nil, ErrNoRandomness
+	}
+	if domain == "" {
+		return nil, errors.New("randomness domain cannot be empty")
+	}
+
+	h := sha256.New()
+	for _, field := range [][]byte{[]byte(randomnessDomainPrefix), []byte(domain), []byte(c.chainID)} {
+		var size [binary.MaxVarintLen64]byte
+		h.Write(size[:binary.PutUvarint(size[:], uint64(len(field)))])
+		h.Write(field)
+	}
+	var height [8]byte
+	binary.BigEndian.PutUint64(height[:], uint64(c.header.Height))
+	h.Write(height[:])
+	h.Write(c.headerHash)
+	return h.Sum(nil), nil
+}
+
+// Rand returns a pseudo-random generator seeded with the Randomness of the
+// domain, e.g. to shuffle a list. The generator is deterministic, and must not
+// be shared between blocks or goroutines.
+func (c Context) Rand(domain string) (*rand.Rand, error) {
+	seed, err := c.Randomness(domain)
+	if err != nil {
+		return nil, err
+	}
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed)))), nil //nolint:gosec // deterministic by design
+}
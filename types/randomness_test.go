@@ -0,0 +1,48 @@
+package types_test
+
+import (
+	"testing"
+
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestContextRandomness(t *testing.T) {
+	ctx := types.NewContext(nil, tmproto.Header{Height: 10}, false, nil).
+		WithChainID("baron-1").
+		WithHeaderHash([]byte("block hash"))
+
+	_, err := ctx.WithHeaderHash(nil).Randomness("lottery")
+	require.ErrorIs(t, err, types.ErrNoRandomness)
+	_, err = ctx.Randomness("")
+	require.Error(t, err)
+
+	r, err := ctx.Randomness("lottery")
+	require.NoError(t, err)
+	require.Len(t, r, 32)
+
+	again, err := ctx.Randomness("lottery")
+	require.NoError(t, err)
+	require.Equal(t, r, again)
+
+	for _, other := range []types.Context{
+		ctx.WithHeaderHash([]byte("other hash")),
+		ctx.WithBlockHeight(11),
+		ctx.WithChainID("baron-2"),
+	} {
+		o, err := other.Randomness("lottery")
+		require.NoError(t, err)
+		require.NotEqual(t, r, o)
+	}
+	o, err := ctx.Randomness("lottery/draw")
+	require.NoError(t, err)
+	require.NotEqual(t, r, o)
+
+	rng1, err := ctx.Rand("shuffle")
+	require.NoError(t, err)
+	rng2, err := ctx.Rand("shuffle")
+	require.NoError(t, err)
+	require.Equal(t, rng1.Perm(10), rng2.Perm(10))
+}
@@ -0,0 +1,46 @@
+package ante
+
+import (
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// cachedParamsAccountKeeper is an AccountKeeper returning params read once
+// when the ante handler was built, instead of reading them from the store on
+// every transaction.
+type cachedParamsAccountKeeper struct {
+	AccountKeeper
+
+	params types.Params
+}
+
+// GetParams returns the cached params, charging the gas of reading them from
+// the store so that the gas used by transactions doesn't depend on the cache.
+func (ak cachedParamsAccountKeeper) GetParams(ctx sdk.Context) types.Params {
+	gasConfig := ctx.KVGasConfig()
+	ctx.GasMeter().ConsumeGas(gasConfig.ReadCostFlat, storetypes.GasReadCostFlatDesc)
+	ctx.GasMeter().ConsumeGas(gasConfig.ReadCostPerByte*storetypes.Gas(len(types.ParamsKey)+ak.params.Size()), storetypes.GasReadPerByteDesc)
+	return ak.params
+}
+
+// NewAnteHandlerBuilder returns a function building the AnteHandler of
+// NewAnteHandler with the auth params cached from the state of its context,
+// e.g. the signature verification costs. It is meant to be used with
+// baseapp.NewReloadableAnteHandler, which rebuilds the AnteHandler when the
+// auth params change.
+func NewAnteHandlerBuilder(options HandlerOptions) (func(ctx sdk.Context) (sdk.AnteHandler, error), error) {
+	// validate the options once, so that building never fails because of them
+	if _, err := NewAnteHandler(options); err != nil {
+		return nil, err
+	}
+
+	return func(ctx sdk.Context) (sdk.AnteHandler, error) {
+		opts := options
+		opts.AccountKeeper = cachedParamsAccountKeeper{
+			AccountKeeper: options.AccountKeeper,
+			params:        options.AccountKeeper.GetParams(ctx),
+		}
+		return NewAnteHandler(opts)
+	}, nil
+}
@@ -6,6 +6,9 @@ import (
 	"encoding/hex"
 	"fmt"
 
+	"github.com/cosmos/gogoproto/proto"
+
+	"github.com/cosmos/cosmos-sdk/crypto/aggsig"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
 	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
@@ -232,6 +235,15 @@ func OnlyLegacyAminoSigners(sigData signing.SignatureData) bool {
 	}
 }
 
+// LegacyStdTx is implemented by the transactions which may be converted from
+// legacy amino JSON StdTxs, e.g. by authtx.AminoJSONTxDecoder. The signatures
+// of a StdTx don't carry the sequence they were signed with: as by the legacy
+// ante handler, they are verified with the sequences of the signer accounts,
+// which their sign bytes cover.
+type LegacyStdTx interface {
+	IsLegacyStdTx() bool
+}
+
 func (svd SigVerificationDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (newCtx sdk.Context, err error) {
 	sigTx, ok := tx.(authsigning.SigVerifiableTx)
 	if !ok {
@@ -252,6 +264,9 @@ func (svd SigVerificationDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simul
 		return ctx, sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "invalid number of signer;  expected: %d, got %d", len(signerAddrs), len(sigs))
 	}
 
+	legacyTx, ok := tx.(LegacyStdTx)
+	legacyStdTx := ok && legacyTx.IsLegacyStdTx()
+
 	for i, sig := range sigs {
 		acc, err := GetSignerAcc(ctx, svd.ak, signerAddrs[i])
 		if err != nil {
@@ -264,8 +279,8 @@ func (svd SigVerificationDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simul
 			return ctx, sdkerrors.Wrap(sdkerrors.ErrInvalidPubKey, "pubkey on account is not set")
 		}
 
-		// Check account sequence number.
-		if sig.Sequence != acc.GetSequence() {
+		// Check account sequence number, unless the signature doesn't carry it.
+		if !legacyStdTx && sig.Sequence != acc.GetSequence() {
 			return ctx, sdkerrors.Wrapf(
 				sdkerrors.ErrWrongSequence,
 				"account sequence mismatch, expected %d, got %d", acc.GetSequence(), sig.Sequence,
@@ -289,7 +304,12 @@ func (svd SigVerificationDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simul
 
 		// no need to verify signatures on recheck tx
 		if !simulate && !ctx.IsReCheckTx() {
-			err := authsigning.VerifySignature(pubKey, signerData, sig.Data, svd.signModeHandler, tx)
+			verifyKey := pubKey
+			if _, single := sig.Data.(*signing.SingleSignatureData); single {
+				// the signatures of the block verified ahead of time aren't verified again
+				verifyKey = aggsig.WithCache(pubKey, aggsig.CacheFromContext(ctx))
+			}
+			err := authsigning.VerifySignature(verifyKey, signerData, sig.Data, svd.signModeHandler, tx)
 			if err != nil {
 				var errMsg string
 				if OnlyLegacyAminoSigners(sig.Data) {
@@ -308,6 +328,73 @@ func (svd SigVerificationDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simul
 	return next(ctx, tx, simulate)
 }
 
+// NewTxSignatureExtractor returns the baseapp.TxSignatureExtractor of the
+// single signatures verified by the SigVerificationDecorator, for the BaseApp
+// to verify the signatures of proposed blocks ahead of their execution. The
+// sign bytes are computed with the sequences of the signatures rather than
+// those of the accounts, which may be incremented by earlier transactions of
+// the block: signatures with a wrong sequence are rejected by the decorator
+// before being verified anyway.
+func NewTxSignatureExtractor(ak AccountKeeper, signModeHandler authsigning.SignModeHandler) func(sdk.Context, sdk.Tx) ([]aggsig.Entry, error) {
+	return func(ctx sdk.Context, tx sdk.Tx) ([]aggsig.Entry, error) {
+		sigTx, ok := tx.(authsigning.SigVerifiableTx)
+		if !ok {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "invalid transaction type")
+		}
+		sigs, err := sigTx.GetSignaturesV2()
+		if err != nil {
+			return nil, err
+		}
+		pubKeys, err := sigTx.GetPubKeys()
+		if err != nil {
+			return nil, err
+		}
+		signerAddrs := sigTx.GetSigners()
+		if len(sigs) != len(signerAddrs) || len(pubKeys) != len(signerAddrs) {
+			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "invalid number of signer;  expected: %d, got %d", len(signerAddrs), len(sigs))
+		}
+
+		entries := make([]aggsig.Entry, 0, len(sigs))
+		for i, sig := range sigs {
+			data, ok := sig.Data.(*signing.SingleSignatureData)
+			if !ok {
+				continue
+			}
+			// the account is created, or its public key set, by an earlier
+			// transaction of the block, or by this one
+			acc := ak.GetAccount(ctx, signerAddrs[i])
+			if acc == nil {
+				continue
+			}
+			pubKey := acc.GetPubKey()
+			if pubKey == nil {
+				pubKey = pubKeys[i]
+			}
+			if pubKey == nil {
+				continue
+			}
+
+			var accNum uint64
+			if ctx.BlockHeight() != 0 {
+				accNum = acc.GetAccountNumber()
+			}
+			signerData := authsigning.SignerData{
+				Address:       acc.GetAddress().String(),
+				ChainID:       ctx.ChainID(),
+				AccountNumber: accNum,
+				Sequence:      sig.Sequence,
+				PubKey:        pubKey,
+			}
+			signBytes, err := signModeHandler.GetSignBytes(data.SignMode, signerData, tx)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, aggsig.Entry{PubKey: pubKey, Msg: signBytes, Sig: data.Signature})
+		}
+		return entries, nil
+	}
+}
+
 // IncrementSequenceDecorator handles incrementing sequences of all signers.
 // Use the IncrementSequenceDecorator decorator to prevent replay attacks. Note,
 // there is need to execute IncrementSequenceDecorator on RecheckTx since
@@ -384,6 +471,15 @@ func (vscd ValidateSigCountDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, sim
 	return next(ctx, tx, simulate)
 }
 
+// DefaultSignatureAlgorithms are the type URLs of the public keys whose
+// signatures DefaultSigVerificationGasConsumer accepts, for apps using it to
+// advertise them with baseapp.SetSignatureAlgorithms.
+var DefaultSignatureAlgorithms = []string{
+	"/" + proto.MessageName(&secp256k1.PubKey{}),
+	"/" + proto.MessageName(&secp256r1.PubKey{}),
+	"/" + proto.MessageName(&kmultisig.LegacyAminoPubKey{}),
+}
+
 // DefaultSigVerificationGasConsumer is the default implementation of SignatureVerificationGasConsumer. It consumes gas
 // for signature verification based upon the public key type. The cost is fetched from the given params and is matched
 // by the concrete type.
@@ -14,10 +14,13 @@ import (
 	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
 	"github.com/cosmos/cosmos-sdk/testutil/testdata"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	"github.com/cosmos/cosmos-sdk/x/auth/ante"
 	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	"github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/gogoproto/proto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -115,6 +118,16 @@ func TestConsumeSignatureVerificationGas(t *testing.T) {
 			require.Nil(t, err)
 			require.Equal(t, tt.gasConsumed, tt.args.meter.GasConsumed(), fmt.Sprintf("%d != %d", tt.gasConsumed, tt.args.meter.GasConsumed()))
 		}
+
+		// the signature algorithms advertised are the ones accepted
+		if tt.args.pubkey != nil {
+			typeURL := "/" + proto.MessageName(tt.args.pubkey)
+			if tt.shouldErr {
+				require.NotContains(t, ante.DefaultSignatureAlgorithms, typeURL)
+			} else {
+				require.Contains(t, ante.DefaultSignatureAlgorithms, typeURL)
+			}
+		}
 	}
 }
 
@@ -295,6 +308,41 @@ func TestSigVerification_ExplicitAmino(t *testing.T) {
 	}
 }
 
+func TestSigVerification_LegacyStdTx(t *testing.T) {
+	suite := SetupTestSuite(t, true)
+	decoder := authtx.AminoJSONTxDecoder(suite.encCfg.Amino, suite.encCfg.Codec)
+
+	priv, _, addr := testdata.KeyTestPubAddr()
+	acc := suite.accountKeeper.NewAccountWithAddress(suite.ctx, addr)
+	require.NoError(t, acc.SetSequence(3))
+	suite.accountKeeper.SetAccount(suite.ctx, acc)
+
+	msgs := []sdk.Msg{testdata.NewTestMsg(addr)}
+	fee := legacytx.NewStdFee(testdata.NewTestGasLimit(), testdata.NewTestFeeAmount())
+	spkd := ante.NewSetPubKeyDecorator(suite.accountKeeper)
+	svd := ante.NewSigVerificationDecorator(suite.accountKeeper, suite.clientCtx.TxConfig.SignModeHandler())
+	antehandler := sdk.ChainAnteDecorators(spkd, svd)
+
+	stdTxSignedWith := func(sequence uint64) sdk.Tx {
+		signBytes := legacytx.StdSignBytes(suite.ctx.ChainID(), acc.GetAccountNumber(), sequence, 0, fee, msgs, "", nil)
+		sig, err := priv.Sign(signBytes)
+		require.NoError(t, err)
+		stdTx := legacytx.NewStdTx(msgs, fee, []legacytx.StdSignature{legacytx.NewStdSignature(priv.PubKey(), sig)}, "")
+		bz, err := suite.encCfg.Amino.MarshalJSON(stdTx)
+		require.NoError(t, err)
+		tx, err := decoder(bz)
+		require.NoError(t, err)
+		return tx
+	}
+
+	// the signatures of StdTxs don't carry their sequence, they are verified
+	// with the sequence of the account
+	_, err := antehandler(suite.ctx, stdTxSignedWith(3), false)
+	require.NoError(t, err)
+	_, err = antehandler(suite.ctx, stdTxSignedWith(2), false)
+	require.ErrorIs(t, err, sdkerrors.ErrUnauthorized)
+}
+
 func TestSigIntegration(t *testing.T) {
 	// generate private keys
 	privs := []cryptotypes.PrivKey{
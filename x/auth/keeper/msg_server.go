@@ -32,5 +32,10 @@ func (ms msgServer) UpdateParams(goCtx context.Context, req *types.MsgUpdatePara
 		return nil, err
 	}
 
+	// let the ante handlers caching the params reload them
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(sdk.EventTypeParamChange, sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName)),
+	)
+
 	return &types.MsgUpdateParamsResponse{}, nil
 }
@@ -31,6 +31,10 @@ type wrapper struct {
 	authInfoBz []byte
 
 	txBodyHasUnknownNonCriticals bool
+
+	// legacyStdTx is set for the transactions converted from legacy amino
+	// JSON StdTxs, see AminoJSONTxDecoder.
+	legacyStdTx bool
 }
 
 var (
@@ -38,6 +42,7 @@ var (
 	_ client.TxBuilder           = &wrapper{}
 	_ tx.TipTx                   = &wrapper{}
 	_ ante.HasExtensionOptionsTx = &wrapper{}
+	_ ante.LegacyStdTx           = &wrapper{}
 	_ ExtensionOptionsTxBuilder  = &wrapper{}
 	_ tx.TipTx                   = &wrapper{}
 )
@@ -357,6 +362,11 @@ func WrapTx(protoTx *tx.Tx) client.TxBuilder {
 	}
 }
 
+// IsLegacyStdTx implements ante.LegacyStdTx.
+func (w *wrapper) IsLegacyStdTx() bool {
+	return w.legacyStdTx
+}
+
 func (w *wrapper) GetExtensionOptions() []*codectypes.Any {
 	return w.tx.Body.ExtensionOptions
 }
@@ -10,7 +10,6 @@ import (
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/runtime"
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth/ante"
 	"github.com/cosmos/cosmos-sdk/x/auth/posthandler"
 	"github.com/cosmos/cosmos-sdk/x/auth/tx"
@@ -52,7 +51,8 @@ func ProvideModule(in TxInputs) TxOutputs {
 			if err != nil {
 				panic(err)
 			}
-			app.SetAnteHandler(anteHandler)
+			app.SetReloadableAnteHandler(anteHandler)
+			app.SetSignatureAlgorithms(ante.DefaultSignatureAlgorithms...)
 		}
 
 		// PostHandlers
@@ -87,12 +87,14 @@ func ProvideModule(in TxInputs) TxOutputs {
 	return TxOutputs{TxConfig: txConfig, BaseAppOption: baseAppOption}
 }
 
-func newAnteHandler(txConfig client.TxConfig, in TxInputs) (sdk.AnteHandler, error) {
+// newAnteHandler returns the default ante handler, rebuilt when the auth params
+// change.
+func newAnteHandler(txConfig client.TxConfig, in TxInputs) (*baseapp.ReloadableAnteHandler, error) {
 	if in.BankKeeper == nil {
 		return nil, fmt.Errorf("both AccountKeeper and BankKeeper are required")
 	}
 
-	anteHandler, err := ante.NewAnteHandler(
+	build, err := ante.NewAnteHandlerBuilder(
 		ante.HandlerOptions{
 			AccountKeeper:   in.AccountKeeper,
 			BankKeeper:      in.BankKeeper,
@@ -105,5 +107,5 @@ func newAnteHandler(txConfig client.TxConfig, in TxInputs) (sdk.AnteHandler, err
 		return nil, fmt.Errorf("failed to create ante handler: %w", err)
 	}
 
-	return anteHandler, nil
+	return baseapp.NewReloadableAnteHandler(build, authtypes.ModuleName), nil
 }
@@ -10,6 +10,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
 )
 
 // DefaultTxDecoder returns a default protobuf TxDecoder using the provided Marshaler.
@@ -90,6 +91,61 @@ func DefaultJSONTxDecoder(cdc codec.ProtoCodecMarshaler) sdk.TxDecoder {
 	}
 }
 
+// AminoJSONTxDecoder returns a TxDecoder of the amino JSON encoded StdTxs of
+// legacy clients, e.g. to chain after DefaultTxDecoder with
+// baseapp.SetTxDecoders. The StdTxs are converted to protobuf transactions, so
+// that the ante handler verifies their signatures in the legacy amino JSON sign
+// mode. As in the legacy StdTx layout, the signatures don't carry the sequence
+// they were signed with: they are verified with the sequences of the signer
+// accounts, see ante.LegacyStdTx.
+func AminoJSONTxDecoder(aminoCdc *codec.LegacyAmino, cdc codec.Codec) sdk.TxDecoder {
+	decode := legacytx.StdTxConfig{Cdc: aminoCdc}.TxJSONDecoder()
+
+	return func(txBytes []byte) (sdk.Tx, error) {
+		decoded, err := decode(txBytes)
+		if err != nil {
+			return nil, err
+		}
+		// amino skips the unknown fields, so that any JSON object decodes
+		stdTx := decoded.(legacytx.StdTx)
+		if len(stdTx.Msgs) == 0 {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "not an amino JSON StdTx: no messages")
+		}
+
+		sigs, err := stdTx.GetSignaturesV2()
+		if err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrTxDecode, err.Error())
+		}
+
+		builder := newBuilder(cdc)
+		if err := builder.SetMsgs(stdTx.GetMsgs()...); err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrTxDecode, err.Error())
+		}
+		if err := builder.SetSignatures(sigs...); err != nil {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrTxDecode, err.Error())
+		}
+		builder.SetMemo(stdTx.GetMemo())
+		builder.SetFeeAmount(stdTx.GetFee())
+		builder.SetGasLimit(stdTx.GetGas())
+		builder.SetTimeoutHeight(stdTx.GetTimeoutHeight())
+		for _, fee := range []struct {
+			addr string
+			set  func(sdk.AccAddress)
+		}{{stdTx.Fee.Payer, builder.SetFeePayer}, {stdTx.Fee.Granter, builder.SetFeeGranter}} {
+			if fee.addr == "" {
+				continue
+			}
+			addr, err := sdk.AccAddressFromBech32(fee.addr)
+			if err != nil {
+				return nil, sdkerrors.Wrap(sdkerrors.ErrTxDecode, err.Error())
+			}
+			fee.set(addr)
+		}
+		builder.legacyStdTx = true
+		return builder.GetTx(), nil
+	}
+}
+
 // rejectNonADR027TxRaw rejects txBytes that do not follow ADR-027. This is NOT
 // a generic ADR-027 checker, it only applies decoding TxRaw. Specifically, it
 // only checks that:
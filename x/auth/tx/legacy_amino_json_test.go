@@ -5,9 +5,12 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/cosmos/cosmos-sdk/codec"
 	cdctypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	"github.com/cosmos/cosmos-sdk/testutil/testdata"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/tx"
 	signingtypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
 	"github.com/cosmos/cosmos-sdk/x/auth/migrations/legacytx"
@@ -147,3 +150,46 @@ func TestLegacyAminoJSONHandler_Modes(t *testing.T) {
 	handler := signModeLegacyAminoJSONHandler{}
 	require.Equal(t, []signingtypes.SignMode{signingtypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON}, handler.Modes())
 }
+
+func TestAminoJSONTxDecoder(t *testing.T) {
+	cdc := codec.NewLegacyAmino()
+	sdk.RegisterLegacyAminoCodec(cdc)
+	cryptocodec.RegisterCrypto(cdc)
+	cdc.RegisterConcrete(&testdata.TestMsg{}, "cosmos-sdk/Test", nil)
+	registry := cdctypes.NewInterfaceRegistry()
+	testdata.RegisterInterfaces(registry)
+	cryptocodec.RegisterInterfaces(registry)
+	decoder := AminoJSONTxDecoder(cdc, codec.NewProtoCodec(registry))
+
+	stdTx := legacytx.NewStdTx(
+		[]sdk.Msg{msg},
+		legacytx.StdFee{Amount: coins, Gas: gas, Granter: addr2.String()},
+		[]legacytx.StdSignature{{PubKey: pubkey1, Signature: []byte("signature")}},
+		memo,
+	)
+	stdTx.TimeoutHeight = timeout
+	bz, err := cdc.MarshalJSON(stdTx)
+	require.NoError(t, err)
+
+	// the StdTx is decoded to a protobuf tx, signed in the amino JSON sign mode
+	// with the sequence of the account
+	decoded, err := decoder(bz)
+	require.NoError(t, err)
+	sigTx := decoded.(signing.Tx)
+	require.Equal(t, []sdk.Msg{msg}, sigTx.GetMsgs())
+	require.Equal(t, memo, sigTx.GetMemo())
+	require.Equal(t, coins, sigTx.GetFee())
+	require.Equal(t, gas, sigTx.GetGas())
+	require.Equal(t, timeout, sigTx.GetTimeoutHeight())
+	require.Equal(t, addr2, sigTx.FeeGranter())
+	sigs, err := sigTx.GetSignaturesV2()
+	require.NoError(t, err)
+	require.Equal(t, []signingtypes.SignatureV2{{
+		PubKey: pubkey1,
+		Data:   &signingtypes.SingleSignatureData{SignMode: signingtypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, Signature: []byte("signature")},
+	}}, sigs)
+	require.True(t, decoded.(*wrapper).IsLegacyStdTx())
+
+	_, err = decoder([]byte(`{"types":{},"message":{}}`))
+	require.ErrorIs(t, err, sdkerrors.ErrTxDecode)
+}